@@ -50,7 +50,7 @@ export class OptimizedBuffer {
     char: Uint32Array
     fg: Float32Array
     bg: Float32Array
-    attributes: Uint8Array
+    attributes: Uint16Array
   } | null = null
   private _destroyed: boolean = false
 
@@ -69,7 +69,7 @@ export class OptimizedBuffer {
     char: Uint32Array
     fg: Float32Array
     bg: Float32Array
-    attributes: Uint8Array
+    attributes: Uint16Array
   } {
     this.guard()
     if (this._rawBuffers === null) {
@@ -83,7 +83,7 @@ export class OptimizedBuffer {
         char: new Uint32Array(toArrayBuffer(charPtr, 0, size * 4)),
         fg: new Float32Array(toArrayBuffer(fgPtr, 0, size * 4 * 4)),
         bg: new Float32Array(toArrayBuffer(bgPtr, 0, size * 4 * 4)),
-        attributes: new Uint8Array(toArrayBuffer(attributesPtr, 0, size)),
+        attributes: new Uint16Array(toArrayBuffer(attributesPtr, 0, size * 2)),
       }
     }
 
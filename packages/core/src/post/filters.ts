@@ -267,7 +267,7 @@ export class DistortionEffect {
       let tempChar: Uint32Array | null = null
       let tempFg: Float32Array | null = null
       let tempBg: Float32Array | null = null
-      let tempAttr: Uint8Array | null = null
+      let tempAttr: Uint16Array | null = null
 
       for (const glitch of this.activeGlitches) {
         const y = glitch.y
@@ -281,7 +281,7 @@ export class DistortionEffect {
             tempChar = new Uint32Array(width)
             tempFg = new Float32Array(width * 4)
             tempBg = new Float32Array(width * 4)
-            tempAttr = new Uint8Array(width)
+            tempAttr = new Uint16Array(width)
           }
 
           // 1. Copy original row data to temp buffers
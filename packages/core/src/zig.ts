@@ -150,15 +150,15 @@ function getOpenTUILib(libPath?: string) {
     },
 
     bufferDrawText: {
-      args: ["ptr", "ptr", "u32", "u32", "u32", "ptr", "ptr", "u8"],
+      args: ["ptr", "ptr", "u32", "u32", "u32", "ptr", "ptr", "u16"],
       returns: "void",
     },
     bufferSetCellWithAlphaBlending: {
-      args: ["ptr", "u32", "u32", "u32", "ptr", "ptr", "u8"],
+      args: ["ptr", "u32", "u32", "u32", "ptr", "ptr", "u16"],
       returns: "void",
     },
     bufferSetCell: {
-      args: ["ptr", "u32", "u32", "u32", "ptr", "ptr", "u8"],
+      args: ["ptr", "u32", "u32", "u32", "ptr", "ptr", "u16"],
       returns: "void",
     },
     bufferFillRect: {
@@ -350,7 +350,7 @@ function getOpenTUILib(libPath?: string) {
       returns: "void",
     },
     textBufferInsertChunkGroup: {
-      args: ["ptr", "usize", "ptr", "u32", "ptr", "ptr", "u8"],
+      args: ["ptr", "usize", "ptr", "u32", "ptr", "ptr", "u16"],
       returns: "u32",
     },
     textBufferRemoveChunkGroup: {
@@ -358,7 +358,7 @@ function getOpenTUILib(libPath?: string) {
       returns: "u32",
     },
     textBufferReplaceChunkGroup: {
-      args: ["ptr", "usize", "ptr", "u32", "ptr", "ptr", "u8"],
+      args: ["ptr", "usize", "ptr", "u32", "ptr", "ptr", "u16"],
       returns: "u32",
     },
     textBufferGetChunkGroupCount: {
@@ -1281,7 +1281,7 @@ class FFIRenderLib implements RenderLib {
   }
 
   public textBufferSetDefaultAttributes(buffer: Pointer, attributes: number | null): void {
-    const attrValue = attributes === null ? null : new Uint8Array([attributes])
+    const attrValue = attributes === null ? null : new Uint16Array([attributes])
     this.opentui.symbols.textBufferSetDefaultAttributes(buffer, attrValue)
   }
 
@@ -1296,8 +1296,8 @@ class FFIRenderLib implements RenderLib {
     bg: RGBA | null,
     attributes: number | null,
   ): number {
-    // Create attribute buffer - null means use default, otherwise pass the u8 value
-    const attrValue = attributes === null ? null : new Uint8Array([attributes])
+    // Create attribute buffer - null means use default, otherwise pass the u16 value
+    const attrValue = attributes === null ? null : new Uint16Array([attributes])
     return this.opentui.symbols.textBufferWriteChunk(
       buffer,
       textBytes,
@@ -1400,7 +1400,7 @@ class FFIRenderLib implements RenderLib {
   ): number {
     const fgPtr = fg ? fg.buffer : null
     const bgPtr = bg ? bg.buffer : null
-    const attr = attributes ?? 255
+    const attr = attributes ?? 0xffff
     return this.opentui.symbols.textBufferInsertChunkGroup(
       buffer,
       index,
@@ -1426,7 +1426,7 @@ class FFIRenderLib implements RenderLib {
   ): number {
     const fgPtr = fg ? fg.buffer : null
     const bgPtr = bg ? bg.buffer : null
-    const attr = attributes ?? 255
+    const attr = attributes ?? 0xffff
     return this.opentui.symbols.textBufferReplaceChunkGroup(
       buffer,
       index,
@@ -32,6 +32,25 @@ pub const ANSI = struct {
         std.fmt.format(writer, "\x1b[48;2;{d};{d};{d}m", .{ r, g, b }) catch return AnsiError.WriteFailed;
     }
 
+    pub fn fgColor256Output(writer: anytype, index: u8) AnsiError!void {
+        std.fmt.format(writer, "\x1b[38;5;{d}m", .{index}) catch return AnsiError.WriteFailed;
+    }
+
+    pub fn bgColor256Output(writer: anytype, index: u8) AnsiError!void {
+        std.fmt.format(writer, "\x1b[48;5;{d}m", .{index}) catch return AnsiError.WriteFailed;
+    }
+
+    // System colors 0-7 are 30-37, and their bright variants 8-15 are 90-97
+    pub fn fgColor16Output(writer: anytype, index: u8) AnsiError!void {
+        const code: u32 = if (index < 8) 30 + @as(u32, index) else 90 + @as(u32, index & 7);
+        std.fmt.format(writer, "\x1b[{d}m", .{code}) catch return AnsiError.WriteFailed;
+    }
+
+    pub fn bgColor16Output(writer: anytype, index: u8) AnsiError!void {
+        const code: u32 = if (index < 8) 40 + @as(u32, index) else 100 + @as(u32, index & 7);
+        std.fmt.format(writer, "\x1b[{d}m", .{code}) catch return AnsiError.WriteFailed;
+    }
+
     // Text attribute constants
     pub const bold = "\x1b[1m";
     pub const dim = "\x1b[2m";
@@ -148,7 +167,7 @@ pub const TextAttributes = struct {
     pub const HIDDEN: u8 = 1 << 6;
     pub const STRIKETHROUGH: u8 = 1 << 7;
 
-    pub fn applyAttributesOutputWriter(writer: anytype, attributes: u8) AnsiError!void {
+    pub fn applyAttributesOutputWriter(writer: anytype, attributes: u16) AnsiError!void {
         if (attributes & BOLD != 0) writer.writeAll(ANSI.bold) catch return AnsiError.WriteFailed;
         if (attributes & DIM != 0) writer.writeAll(ANSI.dim) catch return AnsiError.WriteFailed;
         if (attributes & ITALIC != 0) writer.writeAll(ANSI.italic) catch return AnsiError.WriteFailed;
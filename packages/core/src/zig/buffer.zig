@@ -80,7 +80,7 @@ pub const Cell = struct {
     char: u32,
     fg: RGBA,
     bg: RGBA,
-    attributes: u8,
+    attributes: u16,
 };
 
 fn isRGBAWithAlpha(color: RGBA) bool {
@@ -119,7 +119,7 @@ pub const OptimizedBuffer = struct {
         char: []u32,
         fg: []RGBA,
         bg: []RGBA,
-        attributes: []u8,
+        attributes: []u16,
     },
     width: u32,
     height: u32,
@@ -165,7 +165,7 @@ pub const OptimizedBuffer = struct {
                 .char = allocator.alloc(u32, size) catch return BufferError.OutOfMemory,
                 .fg = allocator.alloc(RGBA, size) catch return BufferError.OutOfMemory,
                 .bg = allocator.alloc(RGBA, size) catch return BufferError.OutOfMemory,
-                .attributes = allocator.alloc(u8, size) catch return BufferError.OutOfMemory,
+                .attributes = allocator.alloc(u16, size) catch return BufferError.OutOfMemory,
             },
             .width = width,
             .height = height,
@@ -203,7 +203,7 @@ pub const OptimizedBuffer = struct {
         return self.buffer.bg.ptr;
     }
 
-    pub fn getAttributesPtr(self: *OptimizedBuffer) [*]u8 {
+    pub fn getAttributesPtr(self: *OptimizedBuffer) [*]u16 {
         return self.buffer.attributes.ptr;
     }
 
@@ -429,6 +429,12 @@ pub const OptimizedBuffer = struct {
         return self.id;
     }
 
+    /// Returns the grapheme cluster a cell char refers to, or null if it refers to none
+    pub fn graphemeBytes(self: *const OptimizedBuffer, char: u32) ?[]const u8 {
+        if (!gp.isGraphemeChar(char)) return null;
+        return self.pool.get(gp.graphemeIdFromChar(char)) catch null;
+    }
+
     /// Calculate the real byte size of the character buffer including grapheme pool data
     pub fn getRealCharSize(self: *const OptimizedBuffer) u32 {
         const total_chars = self.width * self.height;
@@ -554,7 +560,7 @@ pub const OptimizedBuffer = struct {
         char: u32,
         fg: RGBA,
         bg: RGBA,
-        attributes: u8,
+        attributes: u16,
     ) !void {
         if (!self.isPointInScissor(@intCast(x), @intCast(y))) return;
         const overlayCell = Cell{ .char = char, .fg = fg, .bg = bg, .attributes = attributes };
@@ -574,7 +580,7 @@ pub const OptimizedBuffer = struct {
         char: u32,
         fg: RGBA,
         bg: RGBA,
-        attributes: u8,
+        attributes: u16,
     ) !void {
         if (!self.isPointInScissor(@intCast(x), @intCast(y))) return;
         const overlayCell = Cell{ .char = char, .fg = fg, .bg = bg, .attributes = attributes };
@@ -654,7 +660,7 @@ pub const OptimizedBuffer = struct {
         y: u32,
         fg: RGBA,
         bg: ?RGBA,
-        attributes: u8,
+        attributes: u16,
     ) BufferError!void {
         if (x >= self.width or y >= self.height) return;
         if (text.len == 0) return;
@@ -868,7 +874,7 @@ pub const OptimizedBuffer = struct {
 
                 var chunkFg = source_chunk.fg orelse text_buffer.default_fg orelse .{ 1.0, 1.0, 1.0, 1.0 };
                 var chunkBg = source_chunk.bg orelse text_buffer.default_bg orelse .{ 0.0, 0.0, 0.0, 0.0 };
-                var chunkAttributes: u8 = @intCast(source_chunk.attributes & tb.ATTR_MASK);
+                var chunkAttributes: u16 = source_chunk.attributes & tb.ATTR_MASK;
 
                 if (source_chunk.attributes & tb.USE_DEFAULT_ATTR != 0) {
                     if (text_buffer.default_attributes) |defAttr| {
@@ -0,0 +1,52 @@
+const std = @import("std");
+
+/// Failure codes reported by getLastError, shared with the language bindings.
+pub const ErrorCode = enum(u8) {
+    none = 0,
+    out_of_memory = 1,
+    out_of_bounds = 2,
+    invalid_argument = 3,
+    write_failed = 4,
+};
+
+// The C ABI returns void for most calls, so failures are kept per thread until the
+// caller asks for them, like errno.
+threadlocal var lastCode: ErrorCode = .none;
+threadlocal var lastMessage: [128]u8 = undefined;
+threadlocal var lastMessageLen: usize = 0;
+
+fn codeFor(err: anyerror) ErrorCode {
+    return switch (err) {
+        error.OutOfMemory => .out_of_memory,
+        error.InvalidDimensions, error.InvalidIndex, error.BufferTooSmall => .out_of_bounds,
+        else => .invalid_argument,
+    };
+}
+
+fn record(code: ErrorCode, operation: []const u8, err: anyerror) void {
+    lastCode = code;
+    const message = std.fmt.bufPrint(&lastMessage, "{s}: {s}", .{ operation, @errorName(err) }) catch lastMessage[0..];
+    lastMessageLen = message.len;
+}
+
+/// Records err as the failure of operation on this thread.
+pub fn set(operation: []const u8, err: anyerror) void {
+    record(codeFor(err), operation, err);
+}
+
+/// Records a failed write to the terminal on this thread.
+pub fn setWriteFailed(operation: []const u8, err: anyerror) void {
+    record(.write_failed, operation, err);
+}
+
+/// Copies as much of the message of the last failure on this thread as fits into out,
+/// clears the failure and returns its code.
+pub fn take(out: []u8) ErrorCode {
+    const code = lastCode;
+    const len = @min(lastMessageLen, out.len);
+    @memcpy(out[0..len], lastMessage[0..len]);
+    if (len < out.len) out[len] = 0;
+    lastCode = .none;
+    lastMessageLen = 0;
+    return code;
+}
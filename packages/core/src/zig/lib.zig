@@ -9,6 +9,7 @@ const text_buffer = @import("text-buffer.zig");
 const terminal = @import("terminal.zig");
 const gwidth = @import("gwidth.zig");
 const logger = @import("logger.zig");
+const errors = @import("errors.zig");
 
 pub const OptimizedBuffer = buffer.OptimizedBuffer;
 pub const CliRenderer = renderer.CliRenderer;
@@ -19,6 +20,14 @@ export fn setLogCallback(callback: ?*const fn (level: u8, msgPtr: [*]const u8, m
     logger.setLogCallback(callback);
 }
 
+/// Returns the code of the last failure on the calling thread and clears it, copying
+/// its message NUL-terminated into outPtr when it fits into maxLen bytes.
+export fn getLastError(outPtr: ?[*]u8, maxLen: usize) u8 {
+    var empty: [0]u8 = .{};
+    const out: []u8 = if (outPtr) |ptr| ptr[0..maxLen] else empty[0..];
+    return @intFromEnum(errors.take(out));
+}
+
 fn f32PtrToRGBA(ptr: [*]const f32) RGBA {
     return .{ ptr[0], ptr[1], ptr[2], ptr[3] };
 }
@@ -90,6 +99,14 @@ export fn render(rendererPtr: *renderer.CliRenderer, force: bool) void {
     rendererPtr.render(force);
 }
 
+export fn setDiscardOutput(rendererPtr: *renderer.CliRenderer, discard: bool) void {
+    rendererPtr.setDiscardOutput(discard);
+}
+
+export fn waitForRender(rendererPtr: *renderer.CliRenderer) void {
+    rendererPtr.waitForRender();
+}
+
 export fn createOptimizedBuffer(width: u32, height: u32, respectAlpha: bool, widthMethod: u8, idPtr: [*]const u8, idLen: usize) ?*buffer.OptimizedBuffer {
     if (width == 0 or height == 0) {
         logger.warn("Invalid buffer dimensions: {}x{}", .{ width, height });
@@ -154,6 +171,22 @@ export fn setCursorColor(rendererPtr: *renderer.CliRenderer, color: [*]const f32
     rendererPtr.terminal.setCursorColor(f32PtrToRGBA(color));
 }
 
+export fn setColorMode(rendererPtr: *renderer.CliRenderer, mode: u8, palettePtr: [*]const f32, paletteLen: u32) void {
+    const modeEnum: renderer.ColorMode = switch (mode) {
+        1 => .ansi256,
+        2 => .ansi16,
+        3 => .none,
+        else => .truecolor,
+    };
+
+    var palette: [256]RGBA = undefined;
+    const len = @min(paletteLen, palette.len);
+    for (0..len) |i| {
+        palette[i] = f32PtrToRGBA(palettePtr + i * 4);
+    }
+    rendererPtr.setColorMode(modeEnum, palette[0..len]);
+}
+
 export fn setDebugOverlay(rendererPtr: *renderer.CliRenderer, enabled: bool, corner: u8) void {
     const cornerEnum: renderer.DebugOverlayCorner = switch (corner) {
         0 => .topLeft,
@@ -178,7 +211,7 @@ export fn setTerminalTitle(rendererPtr: *renderer.CliRenderer, titlePtr: [*]cons
 
 // Buffer functions
 export fn bufferClear(bufferPtr: *buffer.OptimizedBuffer, bg: [*]const f32) void {
-    bufferPtr.clear(f32PtrToRGBA(bg), null) catch {};
+    bufferPtr.clear(f32PtrToRGBA(bg), null) catch |err| errors.set("bufferClear", err);
 }
 
 export fn bufferGetCharPtr(bufferPtr: *buffer.OptimizedBuffer) [*]u32 {
@@ -193,7 +226,7 @@ export fn bufferGetBgPtr(bufferPtr: *buffer.OptimizedBuffer) [*]RGBA {
     return bufferPtr.getBgPtr();
 }
 
-export fn bufferGetAttributesPtr(bufferPtr: *buffer.OptimizedBuffer) [*]u8 {
+export fn bufferGetAttributesPtr(bufferPtr: *buffer.OptimizedBuffer) [*]u16 {
     return bufferPtr.getAttributesPtr();
 }
 
@@ -216,24 +249,33 @@ export fn bufferGetRealCharSize(bufferPtr: *buffer.OptimizedBuffer) u32 {
     return bufferPtr.getRealCharSize();
 }
 
+/// Copies as much of the grapheme cluster a cell char refers to as fits into outputPtr
+/// and returns its length, or 0 if the char refers to no cluster.
+export fn bufferGetGrapheme(bufferPtr: *buffer.OptimizedBuffer, char: u32, outputPtr: [*]u8, outputLen: usize) u32 {
+    const bytes = bufferPtr.graphemeBytes(char) orelse return 0;
+    const copyLen = @min(bytes.len, outputLen);
+    @memcpy(outputPtr[0..copyLen], bytes[0..copyLen]);
+    return @intCast(bytes.len);
+}
+
 export fn bufferWriteResolvedChars(bufferPtr: *buffer.OptimizedBuffer, outputPtr: [*]u8, outputLen: usize, addLineBreaks: bool) u32 {
     const output_slice = outputPtr[0..outputLen];
     return bufferPtr.writeResolvedChars(output_slice, addLineBreaks) catch 0;
 }
 
-export fn bufferDrawText(bufferPtr: *buffer.OptimizedBuffer, text: [*]const u8, textLen: usize, x: u32, y: u32, fg: [*]const f32, bg: ?[*]const f32, attributes: u8) void {
+export fn bufferDrawText(bufferPtr: *buffer.OptimizedBuffer, text: [*]const u8, textLen: usize, x: u32, y: u32, fg: [*]const f32, bg: ?[*]const f32, attributes: u16) void {
     const rgbaFg = f32PtrToRGBA(fg);
     const rgbaBg = if (bg) |bgPtr| f32PtrToRGBA(bgPtr) else null;
-    bufferPtr.drawText(text[0..textLen], x, y, rgbaFg, rgbaBg, attributes) catch {};
+    bufferPtr.drawText(text[0..textLen], x, y, rgbaFg, rgbaBg, attributes) catch |err| errors.set("bufferDrawText", err);
 }
 
-export fn bufferSetCellWithAlphaBlending(bufferPtr: *buffer.OptimizedBuffer, x: u32, y: u32, char: u32, fg: [*]const f32, bg: [*]const f32, attributes: u8) void {
+export fn bufferSetCellWithAlphaBlending(bufferPtr: *buffer.OptimizedBuffer, x: u32, y: u32, char: u32, fg: [*]const f32, bg: [*]const f32, attributes: u16) void {
     const rgbaFg = f32PtrToRGBA(fg);
     const rgbaBg = f32PtrToRGBA(bg);
-    bufferPtr.setCellWithAlphaBlending(x, y, char, rgbaFg, rgbaBg, attributes) catch {};
+    bufferPtr.setCellWithAlphaBlending(x, y, char, rgbaFg, rgbaBg, attributes) catch |err| errors.set("bufferSetCellWithAlphaBlending", err);
 }
 
-export fn bufferSetCell(bufferPtr: *buffer.OptimizedBuffer, x: u32, y: u32, char: u32, fg: [*]const f32, bg: [*]const f32, attributes: u8) void {
+export fn bufferSetCell(bufferPtr: *buffer.OptimizedBuffer, x: u32, y: u32, char: u32, fg: [*]const f32, bg: [*]const f32, attributes: u16) void {
     const rgbaFg = f32PtrToRGBA(fg);
     const rgbaBg = f32PtrToRGBA(bg);
     const cell = buffer.Cell{
@@ -247,7 +289,7 @@ export fn bufferSetCell(bufferPtr: *buffer.OptimizedBuffer, x: u32, y: u32, char
 
 export fn bufferFillRect(bufferPtr: *buffer.OptimizedBuffer, x: u32, y: u32, width: u32, height: u32, bg: [*]const f32) void {
     const rgbaBg = f32PtrToRGBA(bg);
-    bufferPtr.fillRect(x, y, width, height, rgbaBg) catch {};
+    bufferPtr.fillRect(x, y, width, height, rgbaBg) catch |err| errors.set("bufferFillRect", err);
 }
 
 export fn bufferDrawPackedBuffer(bufferPtr: *buffer.OptimizedBuffer, data: [*]const u8, dataLen: usize, posX: u32, posY: u32, terminalWidthCells: u32, terminalHeightCells: u32) void {
@@ -307,15 +349,15 @@ export fn bufferDrawBox(
         shouldFill,
         titleSlice,
         titleAlignment,
-    ) catch {};
+    ) catch |err| errors.set("bufferDrawBox", err);
 }
 
 export fn bufferResize(bufferPtr: *buffer.OptimizedBuffer, width: u32, height: u32) void {
-    bufferPtr.resize(width, height) catch {};
+    bufferPtr.resize(width, height) catch |err| errors.set("bufferResize", err);
 }
 
 export fn resizeRenderer(rendererPtr: *renderer.CliRenderer, width: u32, height: u32) void {
-    rendererPtr.resize(width, height) catch {};
+    rendererPtr.resize(width, height) catch |err| errors.set("resizeRenderer", err);
 }
 
 export fn addToHitGrid(rendererPtr: *renderer.CliRenderer, x: i32, y: i32, width: u32, height: u32, id: u32) void {
@@ -408,7 +450,7 @@ export fn textBufferSetDefaultBg(tb: *text_buffer.TextBuffer, bg: ?[*]const f32)
     tb.setDefaultBg(bgColor);
 }
 
-export fn textBufferSetDefaultAttributes(tb: *text_buffer.TextBuffer, attr: ?[*]const u8) void {
+export fn textBufferSetDefaultAttributes(tb: *text_buffer.TextBuffer, attr: ?[*]const u16) void {
     const attrValue = if (attr) |a| a[0] else null;
     tb.setDefaultAttributes(attrValue);
 }
@@ -417,13 +459,16 @@ export fn textBufferResetDefaults(tb: *text_buffer.TextBuffer) void {
     tb.resetDefaults();
 }
 
-export fn textBufferWriteChunk(tb: *text_buffer.TextBuffer, textBytes: [*]const u8, textLen: u32, fg: ?[*]const f32, bg: ?[*]const f32, attr: ?[*]const u8) u32 {
+export fn textBufferWriteChunk(tb: *text_buffer.TextBuffer, textBytes: [*]const u8, textLen: u32, fg: ?[*]const f32, bg: ?[*]const f32, attr: ?[*]const u16) u32 {
     const textSlice = textBytes[0..textLen];
     const fgColor = if (fg) |fgPtr| f32PtrToRGBA(fgPtr) else null;
     const bgColor = if (bg) |bgPtr| f32PtrToRGBA(bgPtr) else null;
     const attrValue = if (attr) |a| a[0] else null;
 
-    return tb.writeChunk(textSlice, fgColor, bgColor, attrValue) catch 0;
+    return tb.writeChunk(textSlice, fgColor, bgColor, attrValue) catch |err| {
+        errors.set("textBufferWriteChunk", err);
+        return 0;
+    };
 }
 
 export fn textBufferFinalizeLineInfo(tb: *text_buffer.TextBuffer) void {
@@ -461,7 +506,7 @@ export fn bufferDrawTextBuffer(
         .height = clipHeight,
     } else null;
 
-    bufferPtr.drawTextBuffer(textBufferPtr, x, y, clip_rect) catch {};
+    bufferPtr.drawTextBuffer(textBufferPtr, x, y, clip_rect) catch |err| errors.set("bufferDrawTextBuffer", err);
 }
 
 // Get selection info as packed u64: [start:u32][end:u32]
@@ -490,23 +535,26 @@ export fn textBufferResetLocalSelection(tb: *text_buffer.TextBuffer) void {
     tb.resetLocalSelection();
 }
 
-export fn textBufferInsertChunkGroup(tb: *text_buffer.TextBuffer, index: usize, textBytes: [*]const u8, textLen: u32, fg: ?[*]const f32, bg: ?[*]const f32, attr: u8) u32 {
+export fn textBufferInsertChunkGroup(tb: *text_buffer.TextBuffer, index: usize, textBytes: [*]const u8, textLen: u32, fg: ?[*]const f32, bg: ?[*]const f32, attr: u16) u32 {
     const textSlice = textBytes[0..textLen];
     const fgColor = if (fg) |fgPtr| f32PtrToRGBA(fgPtr) else null;
     const bgColor = if (bg) |bgPtr| f32PtrToRGBA(bgPtr) else null;
-    const attrValue = if (attr == 255) null else attr;
-    return tb.insertChunkGroup(index, textSlice, fgColor, bgColor, attrValue) catch 0;
+    const attrValue = if (attr == 0xFFFF) null else attr;
+    return tb.insertChunkGroup(index, textSlice, fgColor, bgColor, attrValue) catch |err| {
+        errors.set("textBufferInsertChunkGroup", err);
+        return 0;
+    };
 }
 
 export fn textBufferRemoveChunkGroup(tb: *text_buffer.TextBuffer, index: usize) u32 {
     return tb.removeChunkGroup(index) catch tb.char_count;
 }
 
-export fn textBufferReplaceChunkGroup(tb: *text_buffer.TextBuffer, index: usize, textBytes: [*]const u8, textLen: u32, fg: ?[*]const f32, bg: ?[*]const f32, attr: u8) u32 {
+export fn textBufferReplaceChunkGroup(tb: *text_buffer.TextBuffer, index: usize, textBytes: [*]const u8, textLen: u32, fg: ?[*]const f32, bg: ?[*]const f32, attr: u16) u32 {
     const textSlice = textBytes[0..textLen];
     const fgColor = if (fg) |fgPtr| f32PtrToRGBA(fgPtr) else null;
     const bgColor = if (bg) |bgPtr| f32PtrToRGBA(bgPtr) else null;
-    const attrValue = if (attr == 255) null else attr;
+    const attrValue = if (attr == 0xFFFF) null else attr;
     return tb.replaceChunkGroup(index, textSlice, fgColor, bgColor, attrValue) catch tb.char_count;
 }
 
@@ -5,6 +5,7 @@ const buf = @import("buffer.zig");
 const gp = @import("grapheme.zig");
 const Terminal = @import("terminal.zig");
 const logger = @import("logger.zig");
+const errors = @import("errors.zig");
 
 pub const RGBA = ansi.RGBA;
 pub const OptimizedBuffer = buf.OptimizedBuffer;
@@ -39,6 +40,15 @@ pub const DebugOverlayCorner = enum {
     bottomRight,
 };
 
+// Colors the renderer writes: 24-bit, indices of the 256 color palette, the 16 system
+// colors or none at all
+pub const ColorMode = enum {
+    truecolor,
+    ansi256,
+    ansi16,
+    none,
+};
+
 pub const CliRenderer = struct {
     width: u32,
     height: u32,
@@ -87,6 +97,10 @@ pub const CliRenderer = struct {
         .enabled = false,
         .corner = .bottomRight,
     },
+    discardOutput: bool = false,
+    colorMode: ColorMode = .truecolor,
+    palette: [256]RGBA = undefined,
+    paletteLen: u32 = 0,
     // Threading
     useThread: bool = false,
     renderMutex: std.Thread.Mutex = .{},
@@ -282,7 +296,7 @@ pub const CliRenderer = struct {
     }
 
     pub fn performShutdownSequence(self: *CliRenderer) void {
-        if (!self.terminalSetup) return;
+        if (!self.terminalSetup or self.discardOutput) return;
 
         const direct = self.stdoutWriter.writer();
         self.terminal.resetState(direct) catch {
@@ -416,6 +430,67 @@ pub const CliRenderer = struct {
         self.renderOffset = offset;
     }
 
+    // The palette holds the colors of the indices the terminal is told to show, from 0.
+    // Without the colors a mode needs, 24-bit colors are written.
+    pub fn setColorMode(self: *CliRenderer, mode: ColorMode, palette: []const RGBA) void {
+        const len = @min(palette.len, self.palette.len);
+        @memcpy(self.palette[0..len], palette[0..len]);
+        self.paletteLen = @intCast(len);
+        self.colorMode = switch (mode) {
+            .ansi256 => if (len > 16) mode else .truecolor,
+            .ansi16 => if (len > 0) mode else .truecolor,
+            else => mode,
+        };
+    }
+
+    // Closest palette index by the "redmean" distance. The system colors are left out
+    // of the 256 color palette since terminals configure them freely.
+    fn paletteIndex(self: *CliRenderer, color: RGBA) u8 {
+        const first: u32 = if (self.colorMode == .ansi256) 16 else 0;
+        const last: u32 = if (self.colorMode == .ansi256) self.paletteLen else @min(self.paletteLen, 16);
+        const r1: i32 = rgbaComponentToU8(color[0]);
+        const g1: i32 = rgbaComponentToU8(color[1]);
+        const b1: i32 = rgbaComponentToU8(color[2]);
+
+        var best: u32 = first;
+        var bestDistance: i32 = -1;
+        var i = first;
+        while (i < last) : (i += 1) {
+            const c = self.palette[i];
+            const r2: i32 = rgbaComponentToU8(c[0]);
+            const g2: i32 = rgbaComponentToU8(c[1]);
+            const b2: i32 = rgbaComponentToU8(c[2]);
+            const rmean = @divTrunc(r1 + r2, 2);
+            const dr = r1 - r2;
+            const dg = g1 - g2;
+            const db = b1 - b2;
+            const distance = (((512 + rmean) * dr * dr) >> 8) + 4 * dg * dg + (((767 - rmean) * db * db) >> 8);
+            if (bestDistance < 0 or distance < bestDistance) {
+                best = i;
+                bestDistance = distance;
+            }
+        }
+        return @intCast(best);
+    }
+
+    fn writeColors(self: *CliRenderer, writer: anytype, fg: RGBA, bg: RGBA) void {
+        switch (self.colorMode) {
+            .truecolor => {
+                ansi.ANSI.fgColorOutput(writer, rgbaComponentToU8(fg[0]), rgbaComponentToU8(fg[1]), rgbaComponentToU8(fg[2])) catch {};
+                ansi.ANSI.bgColorOutput(writer, rgbaComponentToU8(bg[0]), rgbaComponentToU8(bg[1]), rgbaComponentToU8(bg[2])) catch {};
+            },
+            .ansi256 => {
+                ansi.ANSI.fgColor256Output(writer, self.paletteIndex(fg)) catch {};
+                ansi.ANSI.bgColor256Output(writer, self.paletteIndex(bg)) catch {};
+            },
+            .ansi16 => {
+                ansi.ANSI.fgColor16Output(writer, self.paletteIndex(fg)) catch {};
+                ansi.ANSI.bgColor16Output(writer, self.paletteIndex(bg)) catch {};
+            },
+            .none => {},
+        }
+    }
+
     fn renderThreadFn(self: *CliRenderer) void {
         while (true) {
             self.renderMutex.lock();
@@ -434,7 +509,7 @@ pub const CliRenderer = struct {
             const outputLen = self.currentOutputLen;
 
             const writeStart = std.time.microTimestamp();
-            if (outputLen > 0) {
+            if (outputLen > 0 and !self.discardOutput) {
                 var bufferedWriter = &self.stdoutWriter;
                 bufferedWriter.writer().writeAll(outputData[0..outputLen]) catch {};
                 bufferedWriter.flush() catch {};
@@ -448,6 +523,23 @@ pub const CliRenderer = struct {
         }
     }
 
+    // Frames are still compared and kept as the current buffer, but never written, for
+    // renderers without a terminal to draw on
+    pub fn setDiscardOutput(self: *CliRenderer, discard: bool) void {
+        self.discardOutput = discard;
+    }
+
+    // Wait until the render thread has written the last frame
+    pub fn waitForRender(self: *CliRenderer) void {
+        if (!self.useThread) return;
+
+        self.renderMutex.lock();
+        while (self.renderInProgress) {
+            self.renderCondition.wait(&self.renderMutex);
+        }
+        self.renderMutex.unlock();
+    }
+
     // Render once with current state
     pub fn render(self: *CliRenderer, force: bool) void {
         const now = std.time.microTimestamp();
@@ -481,9 +573,11 @@ pub const CliRenderer = struct {
             self.renderMutex.unlock();
         } else {
             const writeStart = std.time.microTimestamp();
-            var bufferedWriter = &self.stdoutWriter;
-            bufferedWriter.writer().writeAll(outputBuffer[0..outputBufferLen]) catch {};
-            bufferedWriter.flush() catch {};
+            if (!self.discardOutput) {
+                var bufferedWriter = &self.stdoutWriter;
+                bufferedWriter.writer().writeAll(outputBuffer[0..outputBufferLen]) catch |err| errors.setWriteFailed("render", err);
+                bufferedWriter.flush() catch |err| errors.setWriteFailed("render", err);
+            }
             self.renderStats.stdoutWriteTime = @as(f64, @floatFromInt(std.time.microTimestamp() - writeStart));
         }
 
@@ -527,7 +621,7 @@ pub const CliRenderer = struct {
 
         var currentFg: ?RGBA = null;
         var currentBg: ?RGBA = null;
-        var currentAttributes: i16 = -1;
+        var currentAttributes: i32 = -1;
         var utf8Buf: [4]u8 = undefined;
 
         const colorEpsilon: f32 = COLOR_EPSILON_DEFAULT;
@@ -566,7 +660,7 @@ pub const CliRenderer = struct {
 
                 const fgMatch = currentFg != null and buf.rgbaEqual(currentFg.?, cell.fg, colorEpsilon);
                 const bgMatch = currentBg != null and buf.rgbaEqual(currentBg.?, cell.bg, colorEpsilon);
-                const sameAttributes = fgMatch and bgMatch and @as(i16, cell.attributes) == currentAttributes;
+                const sameAttributes = fgMatch and bgMatch and @as(i32, cell.attributes) == currentAttributes;
 
                 if (!sameAttributes or runStart == -1) {
                     if (runLength > 0) {
@@ -582,16 +676,7 @@ pub const CliRenderer = struct {
 
                     ansi.ANSI.moveToOutput(writer, x + 1, y + 1 + self.renderOffset) catch {};
 
-                    const fgR = rgbaComponentToU8(cell.fg[0]);
-                    const fgG = rgbaComponentToU8(cell.fg[1]);
-                    const fgB = rgbaComponentToU8(cell.fg[2]);
-
-                    const bgR = rgbaComponentToU8(cell.bg[0]);
-                    const bgG = rgbaComponentToU8(cell.bg[1]);
-                    const bgB = rgbaComponentToU8(cell.bg[2]);
-
-                    ansi.ANSI.fgColorOutput(writer, fgR, fgG, fgB) catch {};
-                    ansi.ANSI.bgColorOutput(writer, bgR, bgG, bgB) catch {};
+                    self.writeColors(writer, cell.fg, cell.bg);
 
                     ansi.TextAttributes.applyAttributesOutputWriter(writer, cell.attributes) catch {};
                 }
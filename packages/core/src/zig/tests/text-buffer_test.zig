@@ -10,7 +10,7 @@ const LineInfo = struct {
     lines: []const text_buffer.TextLine,
 };
 
-fn testWriteAndGetLineInfo(tb: *TextBuffer, text: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u8) !LineInfo {
+fn testWriteAndGetLineInfo(tb: *TextBuffer, text: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u16) !LineInfo {
     _ = try tb.writeChunk(text, fg, bg, attr);
     tb.finalizeLineInfo();
     return LineInfo{
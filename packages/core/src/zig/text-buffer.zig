@@ -13,7 +13,7 @@ pub const TextSelection = buffer.TextSelection;
 pub const USE_DEFAULT_FG: u16 = 0x8000;
 pub const USE_DEFAULT_BG: u16 = 0x4000;
 pub const USE_DEFAULT_ATTR: u16 = 0x2000;
-pub const ATTR_MASK: u16 = 0x00FF;
+pub const ATTR_MASK: u16 = 0x1FFF;
 
 pub const TextBufferError = error{
     OutOfMemory,
@@ -152,7 +152,7 @@ pub const TextBuffer = struct {
     local_selection: ?LocalSelection,
     default_fg: ?RGBA,
     default_bg: ?RGBA,
-    default_attributes: ?u8,
+    default_attributes: ?u16,
 
     allocator: Allocator,
     global_allocator: Allocator,
@@ -323,7 +323,7 @@ pub const TextBuffer = struct {
         self.default_bg = bg;
     }
 
-    pub fn setDefaultAttributes(self: *TextBuffer, attributes: ?u8) void {
+    pub fn setDefaultAttributes(self: *TextBuffer, attributes: ?u16) void {
         self.default_attributes = attributes;
     }
 
@@ -642,7 +642,7 @@ pub const TextBuffer = struct {
 
     /// Write a UTF-8 encoded text chunk with styling to the buffer
     /// Creates a new chunk with the specified styling and adds it to the current line
-    pub fn writeChunk(self: *TextBuffer, textBytes: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u8) TextBufferError!u32 {
+    pub fn writeChunk(self: *TextBuffer, textBytes: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u16) TextBufferError!u32 {
         // Empty text creates a single chunk group
         if (textBytes.len == 0) {
             const chunk_group = self.allocator.create(ChunkGroup) catch return TextBufferError.OutOfMemory;
@@ -672,7 +672,7 @@ pub const TextBuffer = struct {
             attrValue |= USE_DEFAULT_BG;
         }
         if (attr) |a| {
-            attrValue |= @as(u16, a);
+            attrValue |= a & ATTR_MASK;
         } else {
             attrValue |= USE_DEFAULT_ATTR;
         }
@@ -1073,7 +1073,7 @@ pub const TextBuffer = struct {
 
     /// Insert a chunk group at the specified index
     /// This maps to StyledText.insert() operation
-    pub fn insertChunkGroup(self: *TextBuffer, index: usize, text_bytes: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u8) TextBufferError!u32 {
+    pub fn insertChunkGroup(self: *TextBuffer, index: usize, text_bytes: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u16) TextBufferError!u32 {
         if (text_bytes.len == 0) return self.char_count;
 
         // Save the current state to identify newly created chunks
@@ -1179,7 +1179,7 @@ pub const TextBuffer = struct {
 
     /// Replace a chunk group at the specified index
     /// This maps to StyledText.replace() operation
-    pub fn replaceChunkGroup(self: *TextBuffer, index: usize, text_bytes: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u8) TextBufferError!u32 {
+    pub fn replaceChunkGroup(self: *TextBuffer, index: usize, text_bytes: []const u8, fg: ?RGBA, bg: ?RGBA, attr: ?u16) TextBufferError!u32 {
         if (index >= self.chunk_groups.items.len) return TextBufferError.InvalidIndex;
 
         _ = try self.removeChunkGroup(index);
@@ -0,0 +1,51 @@
+package opentui
+
+import "testing"
+
+// TestDrawingAllocations checks that the drawing calls of a render loop don't
+// allocate, which would make the GC pause frames.
+func TestDrawingAllocations(t *testing.T) {
+	buffer := newTestBuffer(t, 80, 24)
+	bg := NewRGBA(0.2, 0.2, 0.3, 0.5)
+	batch := buffer.NewBatch()
+	tests := []struct {
+		name string
+		draw func()
+	}{
+		{"DrawText", func() { buffer.DrawText("status: ok", 1, 1, White, &bg, AttrBold) }},
+		{"DrawText wide", func() { buffer.DrawText("漢字 café", 1, 2, White, nil, 0) }},
+		{"FillRect", func() { buffer.FillRect(1, 1, 10, 2, bg) }},
+		{"SetCellWithAlphaBlending", func() { buffer.SetCellWithAlphaBlending(1, 1, 'x', White, bg, 0) }},
+		{"DrawBatch", func() {
+			batch.DrawText("status: ok", 1, 1, White, nil, 0)
+			batch.FillRect(1, 1, 10, 2, bg)
+			batch.Flush()
+		}},
+	}
+	for _, tt := range tests {
+		tt.draw() // Let the batch grow its storage
+		if allocs := testing.AllocsPerRun(100, tt.draw); allocs != 0 {
+			t.Errorf("%s allocates %v times per call", tt.name, allocs)
+		}
+	}
+
+	text := NewTextBuffer(1<<16, WidthMethodUnicode)
+	if text == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer text.Close()
+	chunk := TextChunk{Text: "log line\n", Foreground: &bg}
+	if allocs := testing.AllocsPerRun(100, func() { text.WriteChunk(chunk) }); allocs != 0 {
+		t.Errorf("WriteChunk allocates %v times per call", allocs)
+	}
+}
+
+func BenchmarkDrawText(b *testing.B) {
+	buffer := newTestBuffer(b, 80, 24)
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buffer.DrawText("status: ok", uint32(i%70), uint32(i%24), White, nil, 0)
+	}
+}
@@ -0,0 +1,439 @@
+package opentui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSIOptions controls how DrawANSI renders text containing escape sequences.
+type ANSIOptions struct {
+	Foreground *RGBA        // Color until an SGR sequence sets one and after a reset, nil means White
+	Background *RGBA        // Background until an SGR sequence sets one, nil keeps the existing background
+	Width      uint32       // Width of the drawing rect, 0 extends to the buffer edge
+	Height     uint32       // Height of the drawing rect, 0 extends to the buffer edge
+	Palette    *ANSIPalette // Colors of the 16 system colors, nil means DefaultPalette
+
+	// CursorMovement honors cursor movement sequences (CUU, CUD, CUF, CUB, CNL, CPL,
+	// CHA and CUP) within the drawing rect, with CUP and CHA relative to its top left
+	// corner. When false they are ignored like every other unsupported sequence.
+	CursorMovement bool
+}
+
+// Limits applied while parsing untrusted input
+const (
+	maxANSIParams     = 32
+	maxANSIParamValue = 65535
+)
+
+func rgb8(r, g, b uint8) RGBA {
+	return NewRGB(float32(r)/255, float32(g)/255, float32(b)/255)
+}
+
+// xterm256Color returns a color of the xterm 256 color palette, with the 16 system
+// colors taken from DefaultPalette.
+func xterm256Color(n uint8) RGBA {
+	switch {
+	case n < 16:
+		return DefaultPalette[n]
+	case n < 232:
+		n -= 16
+		return rgb8(xtermCubeLevels[n/36], xtermCubeLevels[n/6%6], xtermCubeLevels[n%6])
+	default:
+		gray := 8 + (n-232)*10
+		return rgb8(gray, gray, gray)
+	}
+}
+
+// ansiState is the pen and cursor while drawing ANSI text
+type ansiState struct {
+	fg, defaultFg RGBA
+	palette       *ANSIPalette
+	bg, defaultBg *RGBA
+	bgColor       RGBA // storage for bg when set by a sequence
+	attrs         Attributes
+	col, row      int64
+}
+
+// DrawANSI draws text containing ANSI escape sequences with its top left corner at
+// (x, y). SGR sequences select 16 color, 256 color and truecolor foreground and
+// background colors as well as bold, dim, italic, underline, blink, reverse and
+// strikethrough. "\n" moves to the start of the next line, "\r" to the start of the
+// current one and tabs advance to the next tab stop of the buffer. Text outside the
+// drawing rect is clipped, and unsupported or malformed sequences are skipped.
+// Returns the size of the area covered by the drawn text.
+func (b *Buffer) DrawANSI(data []byte, x, y uint32, opts ANSIOptions) (Size, error) {
+	if b.ptr == nil {
+		return Size{}, closedError("buffer")
+	}
+	width, height, err := b.Size()
+	if err != nil {
+		return Size{}, err
+	}
+	if x >= width || y >= height {
+		return Size{}, nil
+	}
+	rectWidth, rectHeight := int64(width-x), int64(height-y)
+	if opts.Width > 0 {
+		rectWidth = min(rectWidth, int64(opts.Width))
+	}
+	if opts.Height > 0 {
+		rectHeight = min(rectHeight, int64(opts.Height))
+	}
+
+	st := ansiState{defaultFg: White, defaultBg: opts.Background, palette: opts.Palette}
+	if st.palette == nil {
+		st.palette = &DefaultPalette
+	}
+	if opts.Foreground != nil {
+		st.defaultFg = *opts.Foreground
+	}
+	st.fg, st.bg = st.defaultFg, st.defaultBg
+
+	tabWidth := int64(b.tabWidth)
+	if tabWidth == 0 {
+		tabWidth = defaultTabWidth
+	}
+
+	var size Size
+	var run strings.Builder
+	runCol := int64(0)
+	flush := func() {
+		if run.Len() == 0 {
+			return
+		}
+		b.drawLine(run.String(), x+uint32(runCol), y+uint32(st.row), st.fg, st.bg, st.attrs)
+		run.Reset()
+	}
+
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == 0x1b:
+			flush()
+			i = ansiEscape(data, i, &st, opts.CursorMovement, rectWidth, rectHeight)
+			continue
+		case c == '\n':
+			flush()
+			st.col, st.row = 0, st.row+1
+		case c == '\r':
+			flush()
+			st.col = 0
+		case c == '\t':
+			flush()
+			st.col += tabWidth - st.col%tabWidth
+		case c == '\b':
+			flush()
+			st.col = max(st.col-1, 0)
+		case c < 0x20 || c == 0x7f:
+			// Other control characters take no cell
+		default:
+			// Printable text up to the next control character or escape
+			end := i
+			for end < len(data) && data[end] >= 0x20 && data[end] != 0x7f {
+				end++
+			}
+			text := string(data[i:end])
+			if !utf8.ValidString(text) {
+				text = strings.ToValidUTF8(text, "\uFFFD")
+			}
+			for g := Graphemes(text); g.Next(); {
+				w := int64(g.Width())
+				r, _ := utf8.DecodeRuneInString(g.Str())
+				// C1 controls encoded as UTF-8 are dropped like C0 controls
+				if w == 0 || (r >= 0x80 && r < 0xa0) {
+					continue
+				}
+				if st.row < rectHeight && st.col+w <= rectWidth {
+					if run.Len() == 0 {
+						runCol = st.col
+					}
+					run.WriteString(g.Str())
+					size.Width = max(size.Width, uint32(st.col+w))
+					size.Height = max(size.Height, uint32(st.row+1))
+				} else {
+					flush()
+				}
+				st.col += w
+			}
+			i = end
+			continue
+		}
+		i++
+	}
+	flush()
+	return size, nil
+}
+
+// ansiEscape consumes the escape sequence starting at data[i] and applies it to st.
+// Returns the index after the sequence.
+func ansiEscape(data []byte, i int, st *ansiState, cursor bool, rectWidth, rectHeight int64) int {
+	i++ // ESC
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '[':
+		return ansiCSI(data, i+1, st, cursor, rectWidth, rectHeight)
+	case ']', 'P', 'X', '^', '_':
+		// OSC, DCS, SOS, PM and APC strings end with BEL or ST
+		for i++; i < len(data); i++ {
+			if data[i] == 0x07 {
+				return i + 1
+			}
+			if data[i] == 0x1b {
+				if i+1 < len(data) && data[i+1] == '\\' {
+					return i + 2
+				}
+				return i // A new escape sequence aborts the string
+			}
+		}
+		return i
+	default:
+		// Other escape sequences have optional intermediate bytes and a final byte
+		for i < len(data) && data[i] >= 0x20 && data[i] <= 0x2f {
+			i++
+		}
+		if i < len(data) && data[i] >= 0x30 && data[i] <= 0x7e {
+			i++
+		}
+		return i
+	}
+}
+
+// ansiCSI consumes a control sequence whose parameters start at data[i].
+// Returns the index after the sequence.
+func ansiCSI(data []byte, i int, st *ansiState, cursor bool, rectWidth, rectHeight int64) int {
+	start := i
+	for i < len(data) && data[i] >= 0x30 && data[i] <= 0x3f {
+		i++
+	}
+	params := data[start:i]
+	intermediate := i
+	for i < len(data) && data[i] >= 0x20 && data[i] <= 0x2f {
+		i++
+	}
+	if i >= len(data) {
+		return i
+	}
+	final := data[i]
+	if final < 0x40 || final > 0x7e {
+		// Malformed sequence, resume at the offending byte
+		return i
+	}
+	i++
+
+	// Private sequences (parameters starting with <, =, > or ?) and sequences with
+	// intermediate bytes are not supported
+	if intermediate != i-1 || (len(params) > 0 && params[0] >= '<') {
+		return i
+	}
+
+	switch final {
+	case 'm':
+		st.applySGR(params)
+	case 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'f':
+		if cursor {
+			st.moveCursor(final, params, rectWidth, rectHeight)
+		}
+	}
+	return i
+}
+
+// ansiParam is a parameter of a control sequence with its colon separated
+// sub-parameters. A value of -1 marks an omitted parameter.
+type ansiParam struct {
+	values [6]int
+	count  int
+}
+
+// parseANSIParams splits control sequence parameters on ';' and ':'.
+func parseANSIParams(params []byte, out []ansiParam) []ansiParam {
+	out = append(out[:0], ansiParam{values: [6]int{-1, -1, -1, -1, -1, -1}, count: 1})
+	for _, c := range params {
+		p := &out[len(out)-1]
+		switch {
+		case c >= '0' && c <= '9':
+			v := &p.values[p.count-1]
+			*v = min(max(*v, 0)*10+int(c-'0'), maxANSIParamValue)
+		case c == ':':
+			if p.count < len(p.values) {
+				p.count++
+			}
+		case c == ';':
+			if len(out) == maxANSIParams {
+				return out
+			}
+			out = append(out, ansiParam{values: [6]int{-1, -1, -1, -1, -1, -1}, count: 1})
+		}
+	}
+	return out
+}
+
+// value returns the n-th sub-parameter, or def when it was omitted.
+func (p ansiParam) value(n, def int) int {
+	if n >= p.count || p.values[n] < 0 {
+		return def
+	}
+	return p.values[n]
+}
+
+// applySGR applies a Select Graphic Rendition sequence.
+func (st *ansiState) applySGR(raw []byte) {
+	var storage [maxANSIParams]ansiParam
+	params := parseANSIParams(raw, storage[:0])
+	for i := 0; i < len(params); i++ {
+		p := params[i]
+		switch code := p.value(0, 0); {
+		case code == 0:
+			st.fg, st.bg, st.attrs = st.defaultFg, st.defaultBg, 0
+		case code == 1:
+			st.attrs |= AttrBold
+		case code == 2:
+			st.attrs |= AttrDim
+		case code == 3:
+			st.attrs |= AttrItalic
+		case code == 4:
+			if p.value(1, 1) == 0 {
+				st.attrs &^= AttrUnderline
+			} else {
+				st.attrs |= AttrUnderline
+			}
+		case code == 5 || code == 6:
+			st.attrs |= AttrBlink
+		case code == 7:
+			st.attrs |= AttrReverse
+		case code == 8:
+			st.attrs |= AttrHidden
+		case code == 9:
+			st.attrs |= AttrStrike
+		case code == 21:
+			st.attrs |= AttrUnderline
+		case code == 22:
+			st.attrs &^= AttrBold | AttrDim
+		case code == 23:
+			st.attrs &^= AttrItalic
+		case code == 24:
+			st.attrs &^= AttrUnderline
+		case code == 25:
+			st.attrs &^= AttrBlink
+		case code == 27:
+			st.attrs &^= AttrReverse
+		case code == 28:
+			st.attrs &^= AttrHidden
+		case code == 29:
+			st.attrs &^= AttrStrike
+		case code >= 30 && code <= 37:
+			st.fg = st.palette[code-30]
+		case code >= 90 && code <= 97:
+			st.fg = st.palette[code-90+8]
+		case code == 39:
+			st.fg = st.defaultFg
+		case code >= 40 && code <= 47:
+			st.setBackground(st.palette[code-40])
+		case code >= 100 && code <= 107:
+			st.setBackground(st.palette[code-100+8])
+		case code == 49:
+			st.bg = st.defaultBg
+		case code == 38 || code == 48:
+			color, ok, consumed := extendedColor(params[i:], st.palette)
+			i += consumed
+			if !ok {
+				continue
+			}
+			if code == 38 {
+				st.fg = color
+			} else {
+				st.setBackground(color)
+			}
+		}
+	}
+}
+
+func (st *ansiState) setBackground(color RGBA) {
+	st.bgColor = color
+	st.bg = &st.bgColor
+}
+
+// extendedColor parses the color of a 38 or 48 SGR parameter, either in colon form
+// (38:5:n, 38:2:r:g:b or 38:2:cs:r:g:b) or semicolon form (38;5;n, 38;2;r;g;b).
+// Returns the color, whether it was valid and the number of extra parameters consumed.
+func extendedColor(params []ansiParam, palette *ANSIPalette) (RGBA, bool, int) {
+	p := params[0]
+	if p.count > 1 {
+		switch p.value(1, -1) {
+		case 5:
+			n := p.value(2, -1)
+			return paletteColor(palette, n), n >= 0 && n <= 255, 0
+		case 2:
+			first := 2
+			if p.count >= 6 {
+				first = 3 // Skip the color space id
+			}
+			return truecolor(p.value(first, 0), p.value(first+1, 0), p.value(first+2, 0)), true, 0
+		}
+		return RGBA{}, false, 0
+	}
+
+	arg := func(n int) int {
+		if n >= len(params) {
+			return -1
+		}
+		return params[n].value(0, 0)
+	}
+	switch arg(1) {
+	case 5:
+		if len(params) < 3 {
+			return RGBA{}, false, len(params) - 1
+		}
+		n := arg(2)
+		return paletteColor(palette, n), n <= 255, 2
+	case 2:
+		if len(params) < 5 {
+			return RGBA{}, false, len(params) - 1
+		}
+		return truecolor(arg(2), arg(3), arg(4)), true, 4
+	}
+	return RGBA{}, false, min(1, len(params)-1)
+}
+
+func paletteColor(palette *ANSIPalette, n int) RGBA {
+	if n < 0 || n > 255 {
+		return RGBA{}
+	}
+	return palette.Color(uint8(n))
+}
+
+func truecolor(r, g, b int) RGBA {
+	return rgb8(uint8(min(r, 255)), uint8(min(g, 255)), uint8(min(b, 255)))
+}
+
+// moveCursor applies a cursor movement sequence, keeping the cursor within the rect.
+func (st *ansiState) moveCursor(final byte, raw []byte, rectWidth, rectHeight int64) {
+	var storage [maxANSIParams]ansiParam
+	params := parseANSIParams(raw, storage[:0])
+	n := int64(max(params[0].value(0, 1), 1))
+	switch final {
+	case 'A':
+		st.row -= n
+	case 'B':
+		st.row += n
+	case 'C':
+		st.col += n
+	case 'D':
+		st.col -= n
+	case 'E':
+		st.col, st.row = 0, st.row+n
+	case 'F':
+		st.col, st.row = 0, st.row-n
+	case 'G':
+		st.col = n - 1
+	case 'H', 'f':
+		st.row = n - 1
+		st.col = 0
+		if len(params) > 1 {
+			st.col = int64(max(params[1].value(0, 1), 1)) - 1
+		}
+	}
+	st.col = min(max(st.col, 0), rectWidth-1)
+	st.row = min(max(st.row, 0), rectHeight-1)
+}
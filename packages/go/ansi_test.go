@@ -0,0 +1,160 @@
+package opentui
+
+import (
+	"testing"
+)
+
+func TestDrawANSI(t *testing.T) {
+	buffer := newTestBuffer(t, 12, 4)
+
+	data := "\x1b[1;31mred\x1b[0m plain\r\n\x1b[38;5;46mgr\x1b[48;2;0;0;255mbl\x1b[39;49m\n\x1b]0;title\x07\x1b[?25lok"
+	size, err := buffer.DrawANSI([]byte(data), 1, 0, ANSIOptions{})
+	if err != nil {
+		t.Fatalf("DrawANSI failed: %v", err)
+	}
+	if size != (Size{Width: 9, Height: 3}) {
+		t.Errorf("DrawANSI size = %+v, want 9x3", size)
+	}
+
+	expectRows(t, buffer,
+		" red plain  ",
+		" grbl       ",
+		" ok         ",
+		"            ",
+	)
+
+	tests := []struct {
+		x, y  uint32
+		fg    RGBA
+		bg    RGBA
+		attrs Attributes
+	}{
+		{1, 0, ANSIRed, Black, AttrBold},
+		{5, 0, White, Black, 0},
+		{1, 1, rgb8(0, 255, 0), Black, 0},
+		{3, 1, rgb8(0, 255, 0), rgb8(0, 0, 255), 0},
+	}
+	for _, tt := range tests {
+		cell, _ := buffer.GetCell(tt.x, tt.y)
+		if cell.Foreground != tt.fg || cell.Background != tt.bg || cell.Attributes != tt.attrs {
+			t.Errorf("cell (%d, %d) = %+v, want fg %v bg %v attrs %d", tt.x, tt.y, cell, tt.fg, tt.bg, tt.attrs)
+		}
+	}
+}
+
+func TestDrawANSIColorForms(t *testing.T) {
+	tests := []struct {
+		sgr  string
+		want RGBA
+	}{
+		{"34", ANSIBlue},
+		{"94", ANSIBrightBlue},
+		{"38;5;196", rgb8(255, 0, 0)},
+		{"38;5;244", rgb8(128, 128, 128)},
+		{"38:5:21", rgb8(0, 0, 255)},
+		{"38;2;10;20;30", rgb8(10, 20, 30)},
+		{"38:2:10:20:30", rgb8(10, 20, 30)},
+		{"38:2::10:20:30", rgb8(10, 20, 30)},
+		{"38;2;999;0;0", rgb8(255, 0, 0)},
+		{"38;5;300", White},
+		{"38;5", White},
+		{"31;38;7;1", ANSIRed},
+	}
+
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 2, 1)
+		buffer.DrawANSI([]byte("\x1b["+tt.sgr+"mx"), 0, 0, ANSIOptions{})
+		if cell, _ := buffer.GetCell(0, 0); cell.Foreground != tt.want {
+			t.Errorf("SGR %q foreground = %v, want %v", tt.sgr, cell.Foreground, tt.want)
+		}
+	}
+}
+
+func TestDrawANSIClipping(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 3)
+
+	size, _ := buffer.DrawANSI([]byte("abcdef\n漢字漢\nxyz\nhidden"), 1, 0, ANSIOptions{Width: 4, Height: 2})
+	if size != (Size{Width: 4, Height: 2}) {
+		t.Errorf("DrawANSI size = %+v, want 4x2", size)
+	}
+	// Rows are compared rune by rune, so a wide character is followed by its blank second cell
+	expectRows(t, buffer,
+		" abcd ",
+		" 漢 字  ",
+		"      ",
+	)
+}
+
+func TestDrawANSICursorMovement(t *testing.T) {
+	data := []byte("ab\x1b[2;3Hc\x1b[Ad\x1b[10De\x1b[99B")
+
+	buffer := newTestBuffer(t, 6, 3)
+	buffer.DrawANSI(data, 1, 0, ANSIOptions{Width: 4, CursorMovement: true})
+	expectRows(t, buffer,
+		" eb d ",
+		"   c  ",
+		"      ",
+	)
+
+	// Without CursorMovement the sequences are dropped
+	buffer = newTestBuffer(t, 6, 3)
+	buffer.DrawANSI(data, 0, 0, ANSIOptions{})
+	expectRows(t, buffer,
+		"abcde ",
+		"      ",
+		"      ",
+	)
+}
+
+func TestDrawANSIMalformed(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"a\x1b", "a     "},
+		{"a\x1b[31", "a     "},
+		{"a\x1b[3\x01b", "ab    "},
+		{"a\x1b]8;;unterminated", "a     "},
+		{"a\x1b]0;t\x1b[1mb", "ab    "},
+		{"a\x1b(Bb", "ab    "},
+		{"a\xffb", "a�b   "},
+		{"a\u009bb", "ab    "},
+		{"a\x00\x07b", "ab    "},
+	}
+
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 6, 1)
+		if _, err := buffer.DrawANSI([]byte(tt.data), 0, 0, ANSIOptions{}); err != nil {
+			t.Fatalf("DrawANSI(%q) failed: %v", tt.data, err)
+		}
+		if got := bufferRows(t, buffer)[0]; got != tt.want {
+			t.Errorf("DrawANSI(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
+
+func FuzzDrawANSI(f *testing.F) {
+	buffer := newTestBuffer(f, 8, 4)
+	for _, seed := range []string{
+		"\x1b[1;31mred\x1b[0m",
+		"\x1b[38;2;1;2;3;48;5;17mx\x1b[m",
+		"\x1b[38:2::1:2:3:4:5:6:7m",
+		"\x1b[99999999999999999999;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;;m",
+		"\x1b[5;5H\x1b[99A\x1b[99D\x1b[E\x1b[F\x1b[0G",
+		"\x1b]8;;http://x\x1b\\link\x1b]8;;\x07",
+		"漢\t字\r\n\b\x1b",
+		"\xc3\x28\xe2\x82\xf0\x9f",
+	} {
+		f.Add([]byte(seed), true)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte, cursor bool) {
+		size, err := buffer.DrawANSI(data, 1, 1, ANSIOptions{Width: 5, Height: 2, CursorMovement: cursor})
+		if err != nil {
+			t.Fatalf("DrawANSI failed: %v", err)
+		}
+		if size.Width > 5 || size.Height > 2 {
+			t.Errorf("DrawANSI size %+v exceeds the 5x2 rect", size)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package opentui
+
+import "regexp"
+
+// The methods below take attributes as uint8, as they did before Attributes. They
+// are kept for one release to ease the migration and will be removed in the next.
+
+// DrawTextUint8 is DrawText with uint8 attributes.
+//
+// Deprecated: Use DrawText, which takes Attributes.
+func (b *Buffer) DrawTextUint8(text string, x, y uint32, fg RGBA, bg *RGBA, attributes uint8) error {
+	return b.DrawText(text, x, y, fg, bg, Attributes(attributes))
+}
+
+// DrawTextLinesUint8 is DrawTextLines with uint8 attributes.
+//
+// Deprecated: Use DrawTextLines, which takes Attributes.
+func (b *Buffer) DrawTextLinesUint8(text string, x, y uint32, fg RGBA, bg *RGBA, attributes uint8) (uint32, error) {
+	return b.DrawTextLines(text, x, y, fg, bg, Attributes(attributes))
+}
+
+// DrawTextBidiUint8 is DrawTextBidi with uint8 attributes.
+//
+// Deprecated: Use DrawTextBidi, which takes Attributes.
+func (b *Buffer) DrawTextBidiUint8(text string, x, y uint32, fg RGBA, bg *RGBA, attributes uint8) error {
+	return b.DrawTextBidi(text, x, y, fg, bg, Attributes(attributes))
+}
+
+// DrawTextWrappedUint8 is DrawTextWrapped with uint8 attributes.
+//
+// Deprecated: Use DrawTextWrapped, which takes Attributes.
+func (b *Buffer) DrawTextWrappedUint8(text string, rect Rect, fg RGBA, bg *RGBA, attrs uint8, wrap WrapMode) (uint32, error) {
+	return b.DrawTextWrapped(text, rect, fg, bg, Attributes(attrs), wrap)
+}
+
+// DrawTextAlignedUint8 is DrawTextAligned with uint8 attributes.
+//
+// Deprecated: Use DrawTextAligned, which takes Attributes.
+func (b *Buffer) DrawTextAlignedUint8(text string, rect Rect, hAlign TextAlignment, vAlign VerticalAlignment, fg RGBA, bg *RGBA, attrs uint8) error {
+	return b.DrawTextAligned(text, rect, hAlign, vAlign, fg, bg, Attributes(attrs))
+}
+
+// DrawTextLinkUint8 is DrawTextLink with uint8 attributes.
+//
+// Deprecated: Use DrawTextLink, which takes Attributes.
+func (b *Buffer) DrawTextLinkUint8(text, url string, x, y uint32, fg RGBA, bg *RGBA, attrs uint8) error {
+	return b.DrawTextLink(text, url, x, y, fg, bg, Attributes(attrs))
+}
+
+// SetCellWithAlphaBlendingUint8 is SetCellWithAlphaBlending with uint8 attributes.
+//
+// Deprecated: Use SetCellWithAlphaBlending, which takes Attributes.
+func (b *Buffer) SetCellWithAlphaBlendingUint8(x, y uint32, char rune, fg, bg RGBA, attributes uint8) error {
+	return b.SetCellWithAlphaBlending(x, y, char, fg, bg, Attributes(attributes))
+}
+
+// SetCellGraphemeUint8 is SetCellGrapheme with uint8 attributes.
+//
+// Deprecated: Use SetCellGrapheme, which takes Attributes.
+func (b *Buffer) SetCellGraphemeUint8(x, y uint32, cluster string, fg, bg RGBA, attrs uint8) error {
+	return b.SetCellGrapheme(x, y, cluster, fg, bg, Attributes(attrs))
+}
+
+// DrawLineUint8 is DrawLine with uint8 attributes.
+//
+// Deprecated: Use DrawLine, which takes Attributes.
+func (b *Buffer) DrawLineUint8(x0, y0, x1, y1 int32, char rune, fg RGBA, bg *RGBA, attrs uint8) error {
+	return b.DrawLine(x0, y0, x1, y1, char, fg, bg, Attributes(attrs))
+}
+
+// DrawRuneRepeatUint8 is DrawRuneRepeat with uint8 attributes.
+//
+// Deprecated: Use DrawRuneRepeat, which takes Attributes.
+func (b *Buffer) DrawRuneRepeatUint8(x, y uint32, count uint32, ch rune, fg RGBA, bg *RGBA, attrs uint8) error {
+	return b.DrawRuneRepeat(x, y, count, ch, fg, bg, Attributes(attrs))
+}
+
+// DrawRuneRepeatVerticalUint8 is DrawRuneRepeatVertical with uint8 attributes.
+//
+// Deprecated: Use DrawRuneRepeatVertical, which takes Attributes.
+func (b *Buffer) DrawRuneRepeatVerticalUint8(x, y uint32, count uint32, ch rune, fg RGBA, bg *RGBA, attrs uint8) error {
+	return b.DrawRuneRepeatVertical(x, y, count, ch, fg, bg, Attributes(attrs))
+}
+
+// WriteStyledStringUint8 is WriteStyledString with uint8 attributes.
+//
+// Deprecated: Use WriteStyledString, which takes Attributes.
+func (tb *TextBuffer) WriteStyledStringUint8(text string, fg, bg *RGBA, attributes *uint8) (uint32, error) {
+	return tb.WriteStyledString(text, fg, bg, widenAttributes(attributes))
+}
+
+// SetDefaultAttributesUint8 is SetDefaultAttributes with uint8 attributes.
+//
+// Deprecated: Use SetDefaultAttributes, which takes Attributes.
+func (tb *TextBuffer) SetDefaultAttributesUint8(attributes *uint8) error {
+	return tb.SetDefaultAttributes(widenAttributes(attributes))
+}
+
+// HighlightMatchesUint8 is HighlightMatches with uint8 attributes.
+//
+// Deprecated: Use HighlightMatches, which takes Attributes.
+func (tb *TextBuffer) HighlightMatchesUint8(re *regexp.Regexp, fg, bg *RGBA, attrs *uint8) (int, error) {
+	return tb.HighlightMatches(re, fg, bg, widenAttributes(attrs))
+}
+
+// widenAttributes converts optional uint8 attributes, keeping nil.
+func widenAttributes(attrs *uint8) *Attributes {
+	if attrs == nil {
+		return nil
+	}
+	wide := Attributes(*attrs)
+	return &wide
+}
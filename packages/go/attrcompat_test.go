@@ -0,0 +1,21 @@
+package opentui
+
+import "testing"
+
+func TestUint8AttributeShims(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 1)
+	if err := buffer.DrawTextUint8("ab", 0, 0, White, nil, uint8(AttrItalic)); err != nil {
+		t.Fatalf("DrawTextUint8 failed: %v", err)
+	}
+	if cell, _ := buffer.GetCell(1, 0); cell.Attributes != AttrItalic {
+		t.Errorf("attributes %#x, want %#x", cell.Attributes, AttrItalic)
+	}
+
+	if widenAttributes(nil) != nil {
+		t.Error("nil attributes should stay nil")
+	}
+	bold := uint8(AttrBold)
+	if attrs := widenAttributes(&bold); attrs == nil || *attrs != AttrBold {
+		t.Errorf("widenAttributes(%d) = %v", bold, attrs)
+	}
+}
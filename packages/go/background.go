@@ -0,0 +1,171 @@
+package opentui
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueryTimeout is returned when the terminal does not answer a query in time,
+// either because it does not support the query or because its reply was not passed
+// to ProcessCapabilityResponse.
+var ErrQueryTimeout = newError("terminal did not answer the query in time")
+
+// backgroundQuery tracks the background color reported by the terminal. Replies
+// arrive through ProcessCapabilityResponse, usually from the input goroutine.
+type backgroundQuery struct {
+	mu      sync.Mutex
+	color   *RGBA // last reported background, nil until the terminal answered
+	waiters []chan RGBA
+}
+
+// QueryBackgroundColor asks the terminal for its background color with OSC 11 and
+// waits up to timeout for the reply. Unless the renderer reads replies from its own
+// Input, the reply is read by the application like any other terminal input and must
+// be passed to ProcessCapabilityResponse from another goroutine while this call
+// waits. Returns ErrQueryTimeout if no reply arrives.
+func (r *Renderer) QueryBackgroundColor(timeout time.Duration) (RGBA, error) {
+	if r.ptr == nil {
+		return RGBA{}, closedError("renderer")
+	}
+
+	reply := make(chan RGBA, 1)
+	r.background.mu.Lock()
+	r.background.waiters = append(r.background.waiters, reply)
+	r.background.mu.Unlock()
+	defer r.background.removeWaiter(reply)
+
+	if _, err := r.terminal().Write([]byte("\x1b]11;?\x07")); err != nil {
+		return RGBA{}, err
+	}
+	r.readReplies()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case color := <-reply:
+		return color, nil
+	case <-timer.C:
+		return RGBA{}, ErrQueryTimeout
+	}
+}
+
+// IsDarkBackground reports whether the terminal background is dark, judged by the
+// relative luminance of the color reported by the last OSC 11 reply. Before any
+// reply it falls back to the COLORFGBG environment variable set by some terminals,
+// and returns an error when that is missing too.
+func (r *Renderer) IsDarkBackground() (bool, error) {
+	if r.ptr == nil {
+		return false, closedError("renderer")
+	}
+	r.background.mu.Lock()
+	color := r.background.color
+	r.background.mu.Unlock()
+	if color != nil {
+		return isDarkColor(*color), nil
+	}
+	if dark, ok := colorFGBGIsDark(os.Getenv("COLORFGBG")); ok {
+		return dark, nil
+	}
+	return false, newError("terminal background color is unknown")
+}
+
+// report stores a reported background and wakes up the pending queries.
+func (q *backgroundQuery) report(color RGBA) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.color = &color
+	for _, waiter := range q.waiters {
+		select {
+		case waiter <- color:
+		default:
+		}
+	}
+	q.waiters = nil
+}
+
+func (q *backgroundQuery) removeWaiter(reply chan RGBA) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, waiter := range q.waiters {
+		if waiter == reply {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// isDarkColor reports whether black text would contrast worse with c than white
+// text, which happens below a relative luminance of about 0.18.
+func isDarkColor(c RGBA) bool {
+	return relativeLuminance(c) < 0.179
+}
+
+// backgroundColorReply extracts the color of the last OSC 11 reply
+// (ESC ] 11 ; rgb:RRRR/GGGG/BBBB terminated by BEL or ST) contained in a response.
+func backgroundColorReply(response []byte) (RGBA, bool) {
+	var color RGBA
+	found := false
+	for {
+		start := bytes.Index(response, []byte("\x1b]11;"))
+		if start < 0 {
+			return color, found
+		}
+		response = response[start+5:]
+		end := bytes.IndexAny(response, "\x07\x1b")
+		if end < 0 {
+			return color, found
+		}
+		if c, ok := parseXColor(string(response[:end])); ok {
+			color, found = c, true
+		}
+		response = response[end:]
+	}
+}
+
+// parseXColor parses an X11 color specification of the form rgb:R/G/B, or
+// rgba:R/G/B/A, where each component has 1 to 4 hex digits.
+func parseXColor(spec string) (RGBA, bool) {
+	body, ok := strings.CutPrefix(spec, "rgb:")
+	components := 3
+	if !ok {
+		if body, ok = strings.CutPrefix(spec, "rgba:"); !ok {
+			return RGBA{}, false
+		}
+		components = 4
+	}
+	parts := strings.Split(body, "/")
+	if len(parts) != components {
+		return RGBA{}, false
+	}
+	var values [4]float32
+	values[3] = 1
+	for i, part := range parts {
+		if len(part) < 1 || len(part) > 4 {
+			return RGBA{}, false
+		}
+		v, err := strconv.ParseUint(part, 16, 16)
+		if err != nil {
+			return RGBA{}, false
+		}
+		values[i] = float32(v) / float32(uint64(1)<<(4*len(part))-1)
+	}
+	return NewRGBA(values[0], values[1], values[2], values[3]), true
+}
+
+// colorFGBGIsDark interprets COLORFGBG ("fg;bg" or "fg;default;bg" with ANSI color
+// indices) the way rxvt and other terminals that set it do: backgrounds 0 to 6 and 8
+// are dark.
+func colorFGBGIsDark(value string) (dark, ok bool) {
+	if value == "" {
+		return false, false
+	}
+	fields := strings.Split(value, ";")
+	bg, err := strconv.Atoi(fields[len(fields)-1])
+	if err != nil || bg < 0 || bg > 15 {
+		return false, false
+	}
+	return bg <= 6 || bg == 8, true
+}
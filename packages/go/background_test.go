@@ -0,0 +1,97 @@
+package opentui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBackgroundColorReply(t *testing.T) {
+	tests := []struct {
+		response string
+		want     RGBA
+		ok       bool
+	}{
+		{"\x1b]11;rgb:ffff/ffff/ffff\x07", White, true},
+		{"\x1b]11;rgb:0000/0000/0000\x1b\\", Black, true},
+		{"\x1b[?62;4c\x1b]11;rgb:1e/21/27\x07", rgb8(30, 33, 39), true},
+		{"\x1b]11;rgba:f/0/0/f\x07", Red, true},
+		{"\x1b]11;rgb:ffff/ffff\x07", RGBA{}, false},
+		{"\x1b]11;rgb:ffff/ffff/ffff", RGBA{}, false},
+		{"\x1b]10;rgb:ffff/ffff/ffff\x07", RGBA{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := backgroundColorReply([]byte(tt.response))
+		if ok != tt.ok || (ok && !closeColor(got, tt.want)) {
+			t.Errorf("backgroundColorReply(%q) = %+v, %v, want %+v, %v", tt.response, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestColorFGBGIsDark(t *testing.T) {
+	tests := []struct {
+		value    string
+		dark, ok bool
+	}{
+		{"15;0", true, true},
+		{"0;15", false, true},
+		{"12;default;8", true, true},
+		{"0;7", false, true},
+		{"", false, false},
+		{"15;default", false, false},
+	}
+	for _, tt := range tests {
+		if dark, ok := colorFGBGIsDark(tt.value); dark != tt.dark || ok != tt.ok {
+			t.Errorf("colorFGBGIsDark(%q) = %v, %v, want %v, %v", tt.value, dark, ok, tt.dark, tt.ok)
+		}
+	}
+}
+
+func TestIsDarkColor(t *testing.T) {
+	if !isDarkColor(DarkTheme.Surface) || isDarkColor(LightTheme.Surface) {
+		t.Error("theme surfaces classified wrongly")
+	}
+	if !isDarkColor(NewRGB(0.4, 0.4, 0.4)) || isDarkColor(NewRGB(0.6, 0.6, 0.6)) {
+		t.Error("grays classified wrongly")
+	}
+}
+
+func TestQueryBackgroundColor(t *testing.T) {
+	renderer := NewRenderer(4, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	t.Setenv("COLORFGBG", "")
+	if _, err := renderer.IsDarkBackground(); err == nil {
+		t.Error("IsDarkBackground should fail before the terminal answered")
+	}
+
+	var color RGBA
+	var err error
+	out := captureStdout(t, func() {
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			renderer.ProcessCapabilityResponse([]byte("\x1b]11;rgb:fafa/fafa/fafa\x1b\\"))
+		}()
+		color, err = renderer.QueryBackgroundColor(time.Second)
+	})
+	if !strings.Contains(out, "\x1b]11;?\x07") {
+		t.Errorf("query not written, got %q", out)
+	}
+	if err != nil || !closeColor(color, rgb8(250, 250, 250)) {
+		t.Errorf("QueryBackgroundColor = %+v, %v", color, err)
+	}
+	if dark, err := renderer.IsDarkBackground(); err != nil || dark {
+		t.Errorf("IsDarkBackground = %v, %v, want false", dark, err)
+	}
+
+	captureStdout(t, func() {
+		_, err = renderer.QueryBackgroundColor(time.Millisecond)
+	})
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("unanswered query returned %v, want ErrQueryTimeout", err)
+	}
+}
@@ -0,0 +1,305 @@
+package opentui
+
+/*
+#include "opentui.h"
+#include "color.h"
+
+enum {
+	BATCH_TEXT,
+	BATCH_FILL,
+	BATCH_CELL,
+	BATCH_BLEND,
+	BATCH_BOX,
+};
+
+// batchCommand is a drawing call recorded by a DrawBatch. Text and border characters
+// are stored apart, at offset in the arrays passed to bufferExecuteCommands.
+typedef struct {
+	uint8_t op;
+	uint8_t hasBg;
+	uint16_t attributes;
+	int32_t x, y;
+	uint32_t width, height;
+	uint32_t value;  // character, text length or packed box options
+	uint32_t offset; // of the text or border characters
+	float fg[4];
+	float bg[4];
+} batchCommand;
+
+// bufferExecuteCommands runs count commands on buffer in order, in a single cgo call.
+// Returns the getLastError code of the first box that failed, with its message.
+static uint8_t bufferExecuteCommands(OptimizedBuffer* buffer, const batchCommand* commands, size_t count,
+		const uint8_t* text, const uint32_t* borderChars, uint8_t* message, size_t maxLen) {
+	uint8_t code = 0;
+	for (size_t i = 0; i < count; i++) {
+		const batchCommand* c = &commands[i];
+		switch (c->op) {
+		case BATCH_TEXT:
+			bufferDrawText(buffer, text + c->offset, c->value, (uint32_t)c->x, (uint32_t)c->y,
+				c->fg, c->hasBg ? c->bg : NULL, c->attributes);
+			break;
+		case BATCH_FILL:
+			bufferFillRect(buffer, (uint32_t)c->x, (uint32_t)c->y, c->width, c->height, c->bg);
+			break;
+		case BATCH_CELL:
+			bufferSetCell(buffer, (uint32_t)c->x, (uint32_t)c->y, c->value, c->fg, c->bg, c->attributes);
+			break;
+		case BATCH_BLEND:
+			bufferSetCellWithAlphaBlending(buffer, (uint32_t)c->x, (uint32_t)c->y, c->value,
+				c->fg, c->bg, c->attributes);
+			break;
+		case BATCH_BOX:
+			getLastError(NULL, 0);
+			bufferDrawBox(buffer, c->x, c->y, c->width, c->height, borderChars + c->offset, c->value,
+				c->fg, c->bg, NULL, 0);
+			if (code == 0) {
+				code = getLastError(message, maxLen);
+			}
+			break;
+		}
+	}
+	return code;
+}
+*/
+import "C"
+import (
+	"strings"
+	"unsafe"
+)
+
+// batchEntry is a drawing call of a DrawBatch, as the caller made it
+type batchEntry struct {
+	op            uint8
+	x, y          int32
+	width, height uint32
+	char          rune
+	fg, bg        RGBA
+	hasBg         bool
+	attributes    Attributes
+	text          string
+	box           BoxOptions
+}
+
+// DrawBatch records drawing calls on a buffer and runs them on Flush, with one cgo
+// call for a whole run of them rather than one or more per call, which dominate the
+// cost of frames drawn a few cells at a time. The buffer ends up exactly as if the
+// calls were made directly, in the same order: the native layer draws and blends
+// the same way, and calls that need work on the Go side, such as setting a double
+// width character or drawing a box title, are made directly between the runs.
+//
+// A batch is reused after Flush, keeping its storage, so recording a frame of the
+// same shape again doesn't allocate. It's not safe for concurrent use.
+type DrawBatch struct {
+	buffer  *Buffer
+	entries []batchEntry
+
+	// Storage of the run being sent to the native layer
+	commands []C.batchCommand
+	text     []byte
+	borders  []uint32
+	message  [128]byte // of the first failure in the run
+}
+
+// NewBatch returns an empty batch drawing on the buffer.
+func (b *Buffer) NewBatch() *DrawBatch {
+	return &DrawBatch{buffer: b}
+}
+
+// Len returns the number of calls recorded since the last Flush.
+func (d *DrawBatch) Len() int {
+	return len(d.entries)
+}
+
+// Reset drops the calls recorded since the last Flush.
+func (d *DrawBatch) Reset() {
+	clear(d.entries) // Let go of the strings
+	d.entries = d.entries[:0]
+}
+
+// DrawText records a call to Buffer.DrawText.
+func (d *DrawBatch) DrawText(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) {
+	e := batchEntry{op: C.BATCH_TEXT, x: int32(x), y: int32(y), fg: fg, attributes: attributes, text: text}
+	if bg != nil {
+		e.bg, e.hasBg = *bg, true
+	}
+	d.entries = append(d.entries, e)
+}
+
+// FillRect records a call to Buffer.FillRect.
+func (d *DrawBatch) FillRect(x, y, width, height uint32, bg RGBA) {
+	d.entries = append(d.entries, batchEntry{op: C.BATCH_FILL, x: int32(x), y: int32(y), width: width, height: height, bg: bg})
+}
+
+// SetCell records a call to Buffer.SetCell. A cell out of bounds makes Flush return
+// the error SetCell does.
+func (d *DrawBatch) SetCell(x, y uint32, cell Cell) {
+	d.entries = append(d.entries, batchEntry{op: C.BATCH_CELL, x: int32(x), y: int32(y),
+		char: cell.Char, fg: cell.Foreground, bg: cell.Background, attributes: cell.Attributes})
+}
+
+// SetCellWithAlphaBlending records a call to Buffer.SetCellWithAlphaBlending.
+func (d *DrawBatch) SetCellWithAlphaBlending(x, y uint32, char rune, fg, bg RGBA, attributes Attributes) {
+	d.entries = append(d.entries, batchEntry{op: C.BATCH_BLEND, x: int32(x), y: int32(y), char: char, fg: fg, bg: bg, attributes: attributes})
+}
+
+// DrawBox records a call to Buffer.DrawBox.
+func (d *DrawBatch) DrawBox(x, y int32, width, height uint32, options BoxOptions, borderColor, backgroundColor RGBA) {
+	d.entries = append(d.entries, batchEntry{op: C.BATCH_BOX, x: x, y: y, width: width, height: height, fg: borderColor, bg: backgroundColor, box: options})
+}
+
+// Flush draws the calls recorded on the buffer in order and empties the batch. All the
+// calls are made even if some fail; the first error is returned.
+func (d *DrawBatch) Flush() error {
+	defer d.Reset()
+	b := d.buffer
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return err
+	}
+	// Hyperlinks, grapheme clusters and Go side blending are kept up to date call by
+	// call, so buffers using them take the direct path throughout
+	direct := b.links.active() || b.graphemes || b.blendMode == BlendLinear
+
+	var first error
+	keep := func(err error) {
+		if first == nil {
+			first = err
+		}
+	}
+	for i := range d.entries {
+		e := &d.entries[i]
+		if e.op == C.BATCH_TEXT {
+			// Lines are drawn on their own, the way DrawTextLines does
+			text := e.text
+			for y := uint32(e.y); y < da.Height; y++ {
+				line, rest, more := strings.Cut(text, "\n")
+				line = strings.TrimSuffix(line, "\r")
+				if direct {
+					keep(d.run())
+					b.drawLine(line, uint32(e.x), y, e.fg, e.bgPtr(), e.attributes)
+				} else {
+					d.addText(e, b.expandTabs(line, int64(uint32(e.x))), y)
+				}
+				if !more {
+					break
+				}
+				text = rest
+			}
+		} else if direct || !d.add(&da, e) {
+			keep(d.run())
+			keep(d.apply(e))
+		}
+	}
+	keep(d.run())
+	return first
+}
+
+// bgPtr returns the optional background of a DrawText call.
+func (e *batchEntry) bgPtr() *RGBA {
+	if !e.hasBg {
+		return nil
+	}
+	return &e.bg
+}
+
+// apply makes the call recorded in e on the buffer directly.
+func (d *DrawBatch) apply(e *batchEntry) error {
+	b := d.buffer
+	switch e.op {
+	case C.BATCH_FILL:
+		return b.FillRect(uint32(e.x), uint32(e.y), e.width, e.height, e.bg)
+	case C.BATCH_CELL:
+		return b.SetCell(uint32(e.x), uint32(e.y), Cell{Char: e.char, Foreground: e.fg, Background: e.bg, Attributes: e.attributes})
+	case C.BATCH_BLEND:
+		return b.SetCellWithAlphaBlending(uint32(e.x), uint32(e.y), e.char, e.fg, e.bg, e.attributes)
+	case C.BATCH_BOX:
+		return b.DrawBox(e.x, e.y, e.width, e.height, e.box, e.fg, e.bg)
+	}
+	return nil
+}
+
+// addText adds a line of a DrawText call to the run.
+func (d *DrawBatch) addText(e *batchEntry, line string, y uint32) {
+	if line == "" {
+		return // Nothing to draw
+	}
+	c := e.command()
+	c.y = C.int32_t(y)
+	c.value = C.uint32_t(len(line))
+	c.offset = C.uint32_t(len(d.text))
+	d.text = append(d.text, line...)
+	d.commands = append(d.commands, c)
+}
+
+// add adds the call recorded in e to the run, unless it needs work on the Go side.
+// Reports whether it did.
+func (d *DrawBatch) add(da *DirectAccess, e *batchEntry) bool {
+	x, y := uint32(e.x), uint32(e.y)
+	c := e.command()
+	switch e.op {
+	case C.BATCH_CELL:
+		if x >= da.Width || y >= da.Height {
+			return false // SetCell reports the error
+		}
+		fallthrough
+	case C.BATCH_BLEND:
+		if runeWidth(e.char) > 1 {
+			return false // Only text drawing puts wide characters into the grapheme pool
+		}
+		c.value = C.uint32_t(e.char)
+	case C.BATCH_BOX:
+		if e.box.Title != "" && e.box.Sides.Top && e.y >= 0 {
+			return false // The title is laid out on the Go side
+		}
+		chars := e.box.BorderDash.apply(e.box.BorderChars)
+		if !sameBorderEdges(chars) {
+			return false // The bottom and right edges are redrawn on the Go side
+		}
+		c.value = packBorderOptions(e.box.Sides, e.box.Fill, uint8(e.box.TitleAlignment))
+		c.offset = C.uint32_t(len(d.borders))
+		native := nativeBorderChars(chars)
+		d.borders = append(d.borders, native[:]...)
+	}
+	d.commands = append(d.commands, c)
+	return true
+}
+
+// command returns the native command for e, with the fields shared by all calls set.
+func (e *batchEntry) command() C.batchCommand {
+	c := C.batchCommand{
+		op:         C.uint8_t(e.op),
+		attributes: C.uint16_t(e.attributes),
+		x:          C.int32_t(e.x),
+		y:          C.int32_t(e.y),
+		width:      C.uint32_t(e.width),
+		height:     C.uint32_t(e.height),
+		fg:         e.fg.toC().rgba,
+		bg:         e.bg.toC().rgba,
+	}
+	if e.hasBg {
+		c.hasBg = 1
+	}
+	return c
+}
+
+// run sends the commands added so far to the native layer in a single call.
+func (d *DrawBatch) run() error {
+	if len(d.commands) == 0 {
+		return nil
+	}
+	var text *C.uint8_t
+	if len(d.text) > 0 {
+		text = (*C.uint8_t)(unsafe.Pointer(&d.text[0]))
+	}
+	var borders *C.uint32_t
+	if len(d.borders) > 0 {
+		borders = (*C.uint32_t)(unsafe.Pointer(&d.borders[0]))
+	}
+	code := C.bufferExecuteCommands(d.buffer.ptr, &d.commands[0], C.size_t(len(d.commands)), text, borders,
+		(*C.uint8_t)(unsafe.Pointer(&d.message[0])), C.size_t(len(d.message)))
+	d.commands, d.text, d.borders = d.commands[:0], d.text[:0], d.borders[:0]
+	return nativeError(code, d.message[:])
+}
@@ -0,0 +1,170 @@
+package opentui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// drawer is the drawing calls shared by Buffer and DrawBatch, to draw the same frame
+// both ways
+type drawer interface {
+	DrawText(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes)
+	FillRect(x, y, width, height uint32, bg RGBA)
+	SetCell(x, y uint32, cell Cell)
+	SetCellWithAlphaBlending(x, y uint32, char rune, fg, bg RGBA, attributes Attributes)
+	DrawBox(x, y int32, width, height uint32, options BoxOptions, borderColor, backgroundColor RGBA)
+}
+
+// directDrawer draws on a buffer right away, keeping the first error
+type directDrawer struct {
+	buffer *Buffer
+	err    error
+}
+
+func (d *directDrawer) keep(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *directDrawer) DrawText(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) {
+	d.keep(d.buffer.DrawText(text, x, y, fg, bg, attributes))
+}
+
+func (d *directDrawer) FillRect(x, y, width, height uint32, bg RGBA) {
+	d.keep(d.buffer.FillRect(x, y, width, height, bg))
+}
+
+func (d *directDrawer) SetCell(x, y uint32, cell Cell) {
+	d.keep(d.buffer.SetCell(x, y, cell))
+}
+
+func (d *directDrawer) SetCellWithAlphaBlending(x, y uint32, char rune, fg, bg RGBA, attributes Attributes) {
+	d.keep(d.buffer.SetCellWithAlphaBlending(x, y, char, fg, bg, attributes))
+}
+
+func (d *directDrawer) DrawBox(x, y int32, width, height uint32, options BoxOptions, borderColor, backgroundColor RGBA) {
+	d.keep(d.buffer.DrawBox(x, y, width, height, options, borderColor, backgroundColor))
+}
+
+// drawBatchScene draws calls covering the fast path and every reason to leave it.
+func drawBatchScene(d drawer) {
+	bg := NewRGB(0.1, 0.2, 0.3)
+	translucent := NewRGBA(1, 0, 0, 0.5)
+	d.FillRect(0, 0, 30, 10, bg)
+	d.DrawText("plain text", 1, 1, White, nil, AttrBold)
+	d.DrawText("two\r\nlines\twith a tab", 2, 2, Yellow, &bg, 0)
+	d.DrawText("漢字 wide", 0, 4, White, nil, 0)
+	d.DrawText("x", 1, 4, Green, nil, 0)  // Over the second half of 漢
+	d.DrawText("ab", 1, 4, Green, nil, 0) // Ends on the first half of 字
+	d.FillRect(4, 5, 3, 2, translucent)   // Blended
+	d.DrawText("narrow", 20, 5, White, nil, 0)
+	d.SetCell(20, 5, Cell{Char: '字', Foreground: Red, Background: Blue})
+	d.SetCellWithAlphaBlending(21, 5, 'z', White, translucent, 0)
+	d.SetCellWithAlphaBlending(22, 5, '漢', White, translucent, 0)
+	d.SetCell(50, 50, Cell{Char: 'x'}) // Out of bounds
+	d.DrawBox(10, 6, 12, 4, BoxOptions{Sides: BorderSides{Top: true, Right: true, Bottom: true, Left: true}, BorderChars: BorderRounded.BoxChars()}, White, bg)
+	d.DrawBox(0, 6, 9, 4, BoxOptions{Sides: BorderSides{Top: true, Right: true, Bottom: true, Left: true}, BorderChars: BorderSingle.BoxChars(), Title: "title", Fill: true}, White, bg)
+	d.DrawText("below the buffer\nand past it", 0, 9, White, nil, 0)
+	d.DrawText("", 0, 0, White, nil, 0)
+}
+
+// compareBuffers reports the cells that differ between two buffers.
+func compareBuffers(t *testing.T, got, want *Buffer) {
+	t.Helper()
+	g, _ := got.GetDirectAccess()
+	w, _ := want.GetDirectAccess()
+	for y := uint32(0); y < w.Height; y++ {
+		for x := uint32(0); x < w.Width; x++ {
+			gc, _ := g.GetCell(x, y)
+			wc, _ := w.GetCell(x, y)
+			if *gc != *wc {
+				t.Errorf("cell (%d, %d) = %+v, want %+v", x, y, *gc, *wc)
+			}
+		}
+	}
+}
+
+func TestDrawBatchMatchesDirectCalls(t *testing.T) {
+	for _, mode := range []BlendMode{BlendSRGB, BlendLinear} {
+		t.Run(fmt.Sprint(mode), func(t *testing.T) {
+			want := newTestBuffer(t, 30, 10)
+			got := newTestBuffer(t, 30, 10)
+			want.SetBlendMode(mode)
+			got.SetBlendMode(mode)
+
+			direct := &directDrawer{buffer: want}
+			drawBatchScene(direct)
+			batch := got.NewBatch()
+			drawBatchScene(batch)
+			if batch.Len() != 16 {
+				t.Errorf("Len() = %d, want 16", batch.Len())
+			}
+			err := batch.Flush()
+			if !errors.Is(err, ErrOutOfBounds) || !errors.Is(direct.err, ErrOutOfBounds) {
+				t.Errorf("Flush returned %v, direct calls %v, want the out of bounds cell reported", err, direct.err)
+			}
+			if batch.Len() != 0 {
+				t.Errorf("Len() = %d after Flush", batch.Len())
+			}
+			compareBuffers(t, got, want)
+		})
+	}
+}
+
+func TestDrawBatchReuse(t *testing.T) {
+	buffer := newTestBuffer(t, 20, 2)
+	batch := buffer.NewBatch()
+	batch.DrawText("first", 0, 0, White, nil, 0)
+	batch.Reset()
+	batch.DrawText("second", 0, 1, White, nil, 0)
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if cell, _ := buffer.GetCell(0, 0); cell.Char != ' ' {
+		t.Errorf("reset call drawn: %q", cell.Char)
+	}
+	if cell, _ := buffer.GetCell(0, 1); cell.Char != 's' {
+		t.Errorf("call after Reset not drawn: %q", cell.Char)
+	}
+
+	buffer.Close()
+	batch.FillRect(0, 0, 1, 1, Red)
+	if err := batch.Flush(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush on a closed buffer returned %v", err)
+	}
+}
+
+// drawBenchmarkFrame draws a 200x60 frame a cell or a short label at a time.
+func drawBenchmarkFrame(d drawer) {
+	bg := NewRGBA(0.2, 0.2, 0.3, 0.5)
+	for y := uint32(0); y < 60; y++ {
+		for x := uint32(0); x < 200; x += 10 {
+			d.DrawText("label", x, y, White, nil, 0)
+			d.SetCellWithAlphaBlending(x+6, y, '│', White, bg, 0)
+			d.FillRect(x+7, y, 3, 1, bg)
+		}
+	}
+}
+
+func BenchmarkDrawDirect(b *testing.B) {
+	buffer := newTestBuffer(b, 200, 60)
+	direct := &directDrawer{buffer: buffer}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		drawBenchmarkFrame(direct)
+	}
+}
+
+func BenchmarkDrawBatch(b *testing.B) {
+	buffer := newTestBuffer(b, 200, 60)
+	batch := buffer.NewBatch()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		drawBenchmarkFrame(batch)
+		batch.Flush()
+	}
+}
@@ -0,0 +1,417 @@
+package opentui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// bidiClass is the bidirectional character type of the Unicode bidi algorithm (UAX #9).
+// Explicit embeddings, overrides and isolates are not supported.
+type bidiClass uint8
+
+const (
+	bidiL   bidiClass = iota // Left to right
+	bidiR                    // Right to left
+	bidiAL                   // Arabic letter
+	bidiEN                   // European number
+	bidiES                   // European separator
+	bidiET                   // European terminator
+	bidiAN                   // Arabic number
+	bidiCS                   // Common separator
+	bidiNSM                  // Nonspacing mark
+	bidiS                    // Segment separator
+	bidiWS                   // Whitespace
+	bidiON                   // Other neutral
+)
+
+// bidiClassOf returns the bidi class of a rune. The scripts covered are Latin and
+// the other left to right scripts, Hebrew, Arabic, Syriac, Thaana and N'Ko.
+func bidiClassOf(r rune) bidiClass {
+	switch {
+	case r == '\t' || r == 0x1f:
+		return bidiS
+	case r >= '0' && r <= '9', r >= 0x06f0 && r <= 0x06f9, r == 0xb2, r == 0xb3, r == 0xb9:
+		return bidiEN
+	case r == '+' || r == '-' || r == 0x2212:
+		return bidiES
+	case r == '#' || r == '$' || r == '%' || r == 0xb0 || r == 0xb1 || (r >= 0xa2 && r <= 0xa5) ||
+		r == 0x066a || r == 0x2030 || (r >= 0x20a0 && r <= 0x20cf):
+		return bidiET
+	case (r >= 0x0600 && r <= 0x0605) || (r >= 0x0660 && r <= 0x0669) || r == 0x066b || r == 0x066c:
+		return bidiAN
+	case r == ',' || r == '.' || r == '/' || r == ':' || r == 0xa0 || r == 0x060c || r == 0x202f || r == 0x2044:
+		return bidiCS
+	case r == 0x200e:
+		return bidiL
+	case r == 0x200f:
+		return bidiR
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return bidiNSM
+	case unicode.IsSpace(r):
+		return bidiWS
+	case (r >= 0x0590 && r <= 0x05ff) || (r >= 0x07c0 && r <= 0x085f) || (r >= 0xfb1d && r <= 0xfb4f):
+		return bidiR
+	case (r >= 0x0600 && r <= 0x07bf) || (r >= 0x0860 && r <= 0x08ff) || (r >= 0xfb50 && r <= 0xfdff) || (r >= 0xfe70 && r <= 0xfefe):
+		return bidiAL
+	case unicode.In(r, unicode.L, unicode.Mc, unicode.Nd, unicode.Nl, unicode.No):
+		return bidiL
+	}
+	return bidiON
+}
+
+// bidiMirrors maps characters to their mirrored glyph in right to left runs
+var bidiMirrors = map[rune]rune{
+	'(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '<': '>', '>': '<',
+	'«': '»', '»': '«', '‹': '›', '›': '‹',
+}
+
+// bidiVisual reorders a single line of text from logical to visual order, so that
+// drawing it left to right displays right to left runs correctly. The paragraph
+// direction is taken from the first strong character. Grapheme clusters are kept
+// intact and bracket-like characters in right to left runs are mirrored.
+func bidiVisual(line string) string {
+	var clusters []string
+	var classes []bidiClass
+	hasRTL := false
+	for g := Graphemes(line); g.Next(); {
+		cluster := g.Str()
+		clusters = append(clusters, cluster)
+		class := bidiClassOf(leadingRune(cluster))
+		hasRTL = hasRTL || class == bidiR || class == bidiAL || class == bidiAN
+		classes = append(classes, class)
+	}
+	if !hasRTL {
+		return line
+	}
+	original := append([]bidiClass(nil), classes...)
+
+	// P2, P3: the paragraph level follows the first strong character
+	paragraph := 0
+	for _, class := range classes {
+		if class == bidiL {
+			break
+		}
+		if class == bidiR || class == bidiAL {
+			paragraph = 1
+			break
+		}
+	}
+	sos := bidiL
+	if paragraph == 1 {
+		sos = bidiR
+	}
+
+	resolveWeakTypes(classes, sos)
+	resolveNeutralTypes(classes, sos)
+
+	// I1, I2: implicit levels
+	levels := make([]int, len(classes))
+	for i, class := range classes {
+		level := paragraph
+		switch {
+		case paragraph == 0 && class == bidiR:
+			level = 1
+		case paragraph == 0 && (class == bidiAN || class == bidiEN):
+			level = 2
+		case paragraph == 1 && (class == bidiL || class == bidiEN || class == bidiAN):
+			level = 2
+		}
+		levels[i] = level
+	}
+
+	// L1: trailing whitespace and segment separators go back to the paragraph level
+	for i := len(original) - 1; i >= 0 && (original[i] == bidiWS || original[i] == bidiS); i-- {
+		levels[i] = paragraph
+	}
+	for i, class := range original {
+		if class == bidiS {
+			for j := i; j >= 0 && (j == i || original[j] == bidiWS); j-- {
+				levels[j] = paragraph
+			}
+		}
+	}
+
+	// L4: mirror brackets at odd levels
+	for i, cluster := range clusters {
+		if levels[i]%2 == 1 {
+			if mirrored, ok := bidiMirrors[leadingRune(cluster)]; ok {
+				clusters[i] = string(mirrored) + cluster[len(string(leadingRune(cluster))):]
+			}
+		}
+	}
+
+	// L2: reverse runs from the highest level down to the lowest odd level
+	highest, lowestOdd := 0, 3
+	for _, level := range levels {
+		highest = max(highest, level)
+		if level%2 == 1 {
+			lowestOdd = min(lowestOdd, level)
+		}
+	}
+	for level := highest; level >= lowestOdd; level-- {
+		for i := 0; i < len(levels); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(levels) && levels[j] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				clusters[a], clusters[b] = clusters[b], clusters[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+	return strings.Join(clusters, "")
+}
+
+// resolveWeakTypes applies rules W1 to W7 to the classes of a line.
+func resolveWeakTypes(classes []bidiClass, sos bidiClass) {
+	// W1: nonspacing marks take the class of the previous character
+	prev := sos
+	for i, class := range classes {
+		if class == bidiNSM {
+			classes[i] = prev
+		} else {
+			prev = class
+		}
+	}
+	// W2, W3: European numbers after Arabic letters become Arabic numbers
+	strong := sos
+	for i, class := range classes {
+		switch class {
+		case bidiL, bidiR, bidiAL:
+			strong = class
+			if class == bidiAL {
+				classes[i] = bidiR
+			}
+		case bidiEN:
+			if strong == bidiAL {
+				classes[i] = bidiAN
+			}
+		}
+	}
+	// W4: a single separator between two numbers of the same type joins them
+	for i := 1; i+1 < len(classes); i++ {
+		before, after := classes[i-1], classes[i+1]
+		switch {
+		case classes[i] == bidiES && before == bidiEN && after == bidiEN:
+			classes[i] = bidiEN
+		case classes[i] == bidiCS && before == after && (before == bidiEN || before == bidiAN):
+			classes[i] = before
+		}
+	}
+	// W5: terminators next to European numbers become European numbers
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiET {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidiET {
+			j++
+		}
+		if (i > 0 && classes[i-1] == bidiEN) || (j < len(classes) && classes[j] == bidiEN) {
+			for k := i; k < j; k++ {
+				classes[k] = bidiEN
+			}
+		}
+		i = j
+	}
+	// W6: remaining separators and terminators are neutral
+	for i, class := range classes {
+		if class == bidiES || class == bidiET || class == bidiCS {
+			classes[i] = bidiON
+		}
+	}
+	// W7: European numbers in left to right context are left to right
+	strong = sos
+	for i, class := range classes {
+		switch class {
+		case bidiL, bidiR:
+			strong = class
+		case bidiEN:
+			if strong == bidiL {
+				classes[i] = bidiL
+			}
+		}
+	}
+}
+
+// resolveNeutralTypes applies rules N1 and N2: neutrals between characters of the same
+// direction take that direction, others take the paragraph direction.
+func resolveNeutralTypes(classes []bidiClass, sos bidiClass) {
+	direction := func(class bidiClass) bidiClass {
+		if class == bidiEN || class == bidiAN {
+			return bidiR
+		}
+		return class
+	}
+	isNeutral := func(class bidiClass) bool {
+		return class == bidiWS || class == bidiON || class == bidiS
+	}
+
+	for i := 0; i < len(classes); {
+		if !isNeutral(classes[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && isNeutral(classes[j]) {
+			j++
+		}
+		before, after := sos, sos
+		if i > 0 {
+			before = direction(classes[i-1])
+		}
+		if j < len(classes) {
+			after = direction(classes[j])
+		}
+		resolved := sos
+		if before == after {
+			resolved = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = resolved
+		}
+		i = j
+	}
+}
+
+func leadingRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
+
+// arabicForm describes the presentation forms of an Arabic letter
+type arabicForm struct {
+	isolated rune // Isolated form, followed by the final, initial and medial forms
+	forms    int  // 1 for non-joining, 2 for right joining and 4 for dual joining letters
+}
+
+// arabicForms maps the basic Arabic letters to their Presentation Forms-B
+var arabicForms = map[rune]arabicForm{
+	0x0621: {0xfe80, 1}, 0x0622: {0xfe81, 2}, 0x0623: {0xfe83, 2}, 0x0624: {0xfe85, 2},
+	0x0625: {0xfe87, 2}, 0x0626: {0xfe89, 4}, 0x0627: {0xfe8d, 2}, 0x0628: {0xfe8f, 4},
+	0x0629: {0xfe93, 2}, 0x062a: {0xfe95, 4}, 0x062b: {0xfe99, 4}, 0x062c: {0xfe9d, 4},
+	0x062d: {0xfea1, 4}, 0x062e: {0xfea5, 4}, 0x062f: {0xfea9, 2}, 0x0630: {0xfeab, 2},
+	0x0631: {0xfead, 2}, 0x0632: {0xfeaf, 2}, 0x0633: {0xfeb1, 4}, 0x0634: {0xfeb5, 4},
+	0x0635: {0xfeb9, 4}, 0x0636: {0xfebd, 4}, 0x0637: {0xfec1, 4}, 0x0638: {0xfec5, 4},
+	0x0639: {0xfec9, 4}, 0x063a: {0xfecd, 4}, 0x0641: {0xfed1, 4}, 0x0642: {0xfed5, 4},
+	0x0643: {0xfed9, 4}, 0x0644: {0xfedd, 4}, 0x0645: {0xfee1, 4}, 0x0646: {0xfee5, 4},
+	0x0647: {0xfee9, 4}, 0x0648: {0xfeed, 2}, 0x0649: {0xfeef, 2}, 0x064a: {0xfef1, 4},
+}
+
+// lamAlef maps the alef variants following a lam to the isolated form of their ligature
+var lamAlef = map[rune]rune{0x0622: 0xfef5, 0x0623: 0xfef7, 0x0625: 0xfef9, 0x0627: 0xfefb}
+
+const (
+	arabicLam     = 0x0644
+	arabicTatweel = 0x0640
+)
+
+// arabicJoining returns how a rune joins its neighbors: 'D' joins on both sides, 'R'
+// only to the preceding letter, 'T' is transparent and 'U' does not join.
+func arabicJoining(r rune) byte {
+	if r == arabicTatweel {
+		return 'D'
+	}
+	if form, ok := arabicForms[r]; ok {
+		switch form.forms {
+		case 4:
+			return 'D'
+		case 2:
+			return 'R'
+		}
+		return 'U'
+	}
+	if r >= 0x0610 && r <= 0x06ff && unicode.In(r, unicode.Mn) {
+		return 'T'
+	}
+	return 'U'
+}
+
+// shapeArabic replaces Arabic letters with the presentation form matching their
+// position in a word and joins lam followed by alef into a ligature. Text in logical
+// order goes in and comes out, ready to be reordered for display.
+func shapeArabic(text string) string {
+	runes := []rune(text)
+	shaped := false
+	for _, r := range runes {
+		if _, ok := arabicForms[r]; ok {
+			shaped = true
+			break
+		}
+	}
+	if !shaped {
+		return text
+	}
+
+	// neighbor returns the joining type of the nearest non-transparent rune from i in direction step
+	neighbor := func(i, step int) (int, byte) {
+		for j := i + step; j >= 0 && j < len(runes); j += step {
+			if joining := arabicJoining(runes[j]); joining != 'T' {
+				return j, joining
+			}
+		}
+		return -1, 'U'
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		form, ok := arabicForms[r]
+		if !ok || form.forms == 1 {
+			out.WriteRune(r)
+			continue
+		}
+		_, before := neighbor(i, -1)
+		joinsBefore := before == 'D'
+
+		if r == arabicLam && i+1 < len(runes) {
+			if ligature, ok := lamAlef[runes[i+1]]; ok {
+				if joinsBefore {
+					ligature++ // Final form
+				}
+				out.WriteRune(ligature)
+				i++
+				continue
+			}
+		}
+
+		_, after := neighbor(i, 1)
+		joinsAfter := form.forms == 4 && (after == 'D' || after == 'R')
+		offset := rune(0)
+		switch {
+		case joinsBefore && joinsAfter:
+			offset = 3
+		case joinsAfter:
+			offset = 2
+		case joinsBefore:
+			offset = 1
+		}
+		out.WriteRune(form.isolated + offset)
+	}
+	return out.String()
+}
+
+// DrawTextBidi draws text like DrawText after shaping Arabic letters and reordering
+// each line for display with the Unicode bidirectional algorithm, so Hebrew and
+// Arabic read correctly, also when mixed with left to right text. x is the left
+// edge of the drawn text whatever its direction. Explicit embedding controls and
+// complex shaping beyond Arabic letter joining are not supported.
+func (b *Buffer) DrawTextBidi(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = bidiVisual(shapeArabic(strings.TrimSuffix(line, "\r")))
+	}
+	return b.DrawText(strings.Join(lines, "\n"), x, y, fg, bg, attributes)
+}
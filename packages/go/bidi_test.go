@@ -0,0 +1,67 @@
+package opentui
+
+import (
+	"testing"
+)
+
+func TestBidiVisual(t *testing.T) {
+	tests := []struct {
+		logical string
+		visual  string
+	}{
+		{"plain text", "plain text"},
+		{"שלום", "םולש"},
+		{"hello שלום world", "hello םולש world"},
+		{"שלום hello", "hello םולש"},
+		{"שלום 123", "123 םולש"},
+		{"(שלום)", "(םולש)"},
+		{"abc (שלום) def", "abc (םולש) def"},
+		{"שלום, עולם!", "!םלוע ,םולש"},
+		{"מחיר 10.50$ היום", "םויה 10.50$ ריחמ"},
+		{"שָׁלוֹם", "םוֹלשָׁ"},
+		// Trailing whitespace takes the paragraph direction
+		{"שלום  ", "  םולש"},
+	}
+
+	for _, tt := range tests {
+		if got := bidiVisual(tt.logical); got != tt.visual {
+			t.Errorf("bidiVisual(%q) = %q, want %q", tt.logical, got, tt.visual)
+		}
+	}
+}
+
+func TestShapeArabic(t *testing.T) {
+	tests := []struct {
+		logical string
+		shaped  string
+	}{
+		// Seen initial, lam-alef final ligature, meem isolated
+		{"سلام", "ﺳﻼﻡ"},
+		// Beh initial, yeh medial, teh final
+		{"بيت", "ﺑﻴﺖ"},
+		// Dal only joins to the preceding letter, so the following reh stands alone
+		{"در", "ﺩﺭ"},
+		// Harakat are transparent to joining
+		{"بَت", "ﺑَﺖ"},
+		{"lam-alef لا", "lam-alef ﻻ"},
+		{"no arabic", "no arabic"},
+	}
+
+	for _, tt := range tests {
+		if got := shapeArabic(tt.logical); got != tt.shaped {
+			t.Errorf("shapeArabic(%q) = %q, want %q", tt.logical, got, tt.shaped)
+		}
+	}
+}
+
+func TestDrawTextBidi(t *testing.T) {
+	buffer := newTestBuffer(t, 12, 2)
+
+	if err := buffer.DrawTextBidi("ab אבג\nسلام", 1, 0, White, nil, 0); err != nil {
+		t.Fatalf("DrawTextBidi failed: %v", err)
+	}
+	expectRows(t, buffer,
+		" ab גבא     ",
+		" ﻡﻼﺳ        ",
+	)
+}
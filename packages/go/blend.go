@@ -0,0 +1,103 @@
+package opentui
+
+// BlendMode selects how translucent colors are blended with the cells below them.
+//
+// Colors use straight alpha: R, G and B are the color itself, not multiplied by A.
+// Both modes keep that convention for the colors they store.
+type BlendMode uint8
+
+const (
+	// BlendSRGB blends in the native layer, mixing the sRGB values directly with a
+	// perceptual curve applied to alpha. Translucent light colors come out darker and
+	// less saturated than they should.
+	BlendSRGB BlendMode = iota
+
+	// BlendLinear blends on the Go side in linear light with alpha taken as is,
+	// compositing source over destination with premultiplied values and converting
+	// the result back to straight alpha.
+	BlendLinear
+)
+
+// SetBlendMode sets how SetCellWithAlphaBlending, FillRect and the drawing helpers
+// built on them blend translucent colors. The default is BlendSRGB. Buffers returned
+// by the renderer are wrapped anew by every GetNextBuffer call, so the mode has to
+// be set on each of them.
+func (b *Buffer) SetBlendMode(mode BlendMode) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if mode > BlendLinear {
+		return newError("invalid blend mode")
+	}
+	b.blendMode = mode
+	return nil
+}
+
+// GetBlendMode returns the blend mode set with SetBlendMode.
+func (b *Buffer) GetBlendMode() (BlendMode, error) {
+	if b.ptr == nil {
+		return BlendSRGB, closedError("buffer")
+	}
+	return b.blendMode, nil
+}
+
+// blendCell blends overlay over the cell at (x, y) in linear light. Cells
+// outside the buffer are left alone.
+func (da *DirectAccess) blendCell(x, y uint32, overlay Cell) {
+	if x >= da.Width || y >= da.Height {
+		return
+	}
+	i := y*da.Width + x
+	// The characters are the stored ones, so a kept cluster stays in the grapheme pool
+	dest := Cell{Char: rune(da.Chars[i]), Foreground: da.Foreground[i], Background: da.Background[i], Attributes: da.Attributes[i]}
+	da.SetCell(x, y, blendCellLinear(overlay, dest))
+}
+
+// fillRectLinear blends bg over every cell of a rectangle clipped to the buffer.
+func (da *DirectAccess) fillRectLinear(x, y, width, height uint32, bg RGBA) {
+	for row := y; row < da.Height && row-y < height; row++ {
+		for col := x; col < da.Width && col-x < width; col++ {
+			da.blendCell(col, row, Cell{Char: ' ', Foreground: White, Background: bg})
+		}
+	}
+}
+
+// blendCellLinear blends overlay over dest. A space drawn over a visible character
+// keeps the character and tints it with the overlay background, like the native
+// blending does.
+func blendCellLinear(overlay, dest Cell) Cell {
+	if overlay.Foreground.A >= 1 && overlay.Background.A >= 1 {
+		return overlay
+	}
+	out := overlay
+	out.Background = blendOver(overlay.Background, dest.Background)
+	if overlay.Char == ' ' && dest.Char != 0 && dest.Char != ' ' && storedCharWidth(uint32(dest.Char)) == 1 {
+		out.Char = dest.Char
+		out.Attributes = dest.Attributes
+		out.Foreground = blendOver(overlay.Background, dest.Foreground)
+	} else {
+		out.Foreground = blendOver(overlay.Foreground, dest.Background)
+	}
+	return out
+}
+
+// blendOver composites src over dst in linear light. Both colors and the result
+// use straight alpha.
+func blendOver(src, dst RGBA) RGBA {
+	if src.A >= 1 {
+		return src
+	}
+	if src.A <= 0 {
+		return dst
+	}
+	a := clamp01(src.A)
+	outA := a + dst.A*(1-a)
+	if outA <= 0 {
+		return RGBA{}
+	}
+	channel := func(s, d float32) float32 {
+		premultiplied := srgbToLinear(s)*a + srgbToLinear(d)*dst.A*(1-a)
+		return linearToSRGB(premultiplied / outA)
+	}
+	return RGBA{R: channel(src.R, dst.R), G: channel(src.G, dst.G), B: channel(src.B, dst.B), A: outA}
+}
@@ -0,0 +1,81 @@
+package opentui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlendOver(t *testing.T) {
+	midGray := NewRGB(0.5, 0.5, 0.5)
+	tests := []struct {
+		name        string
+		src, dst    RGBA
+		want, wantA float32
+	}{
+		{"50% white over mid-gray", NewRGBA(1, 1, 1, 0.5), midGray, 0.8019, 1},
+		{"25% white over mid-gray", NewRGBA(1, 1, 1, 0.25), midGray, 0.6730, 1},
+		{"50% white over 50% mid-gray", NewRGBA(1, 1, 1, 0.5), NewRGBA(0.5, 0.5, 0.5, 0.5), 0.8746, 0.75},
+		{"transparent over mid-gray", NewRGBA(1, 1, 1, 0), midGray, 0.5, 1},
+		{"opaque over mid-gray", White, midGray, 1, 1},
+	}
+	for _, tt := range tests {
+		got := blendOver(tt.src, tt.dst)
+		for _, c := range []float32{got.R, got.G, got.B} {
+			if math.Abs(float64(c-tt.want)) > 1e-4 {
+				t.Errorf("%s: got %+v, want channels %.4f", tt.name, got, tt.want)
+				break
+			}
+		}
+		if math.Abs(float64(got.A-tt.wantA)) > 1e-6 {
+			t.Errorf("%s: alpha = %v, want %v", tt.name, got.A, tt.wantA)
+		}
+	}
+
+	// Straight alpha: a fully transparent destination takes the source color unchanged
+	if got := blendOver(NewRGBA(0.2, 0.4, 0.6, 0.5), Transparent); !closeColor(got, NewRGBA(0.2, 0.4, 0.6, 0.5)) {
+		t.Errorf("blend over transparent = %+v", got)
+	}
+}
+
+func TestBlendCellLinear(t *testing.T) {
+	dest := Cell{Char: 'x', Foreground: Black, Background: NewRGB(0.5, 0.5, 0.5), Attributes: AttrBold}
+	sparkle := NewRGBA(1, 1, 1, 0.5)
+
+	// A translucent space tints the character below instead of erasing it
+	got := blendCellLinear(Cell{Char: ' ', Foreground: White, Background: sparkle}, dest)
+	if got.Char != 'x' || got.Attributes != AttrBold || !closeColor(got.Background, NewRGB(0.8019, 0.8019, 0.8019)) {
+		t.Errorf("space over text = %+v", got)
+	}
+	if !closeColor(got.Foreground, NewRGB(0.7354, 0.7354, 0.7354)) {
+		t.Errorf("tinted foreground = %+v", got.Foreground)
+	}
+
+	got = blendCellLinear(Cell{Char: 'o', Foreground: sparkle, Background: Transparent}, dest)
+	if got.Char != 'o' || got.Background != dest.Background || !closeColor(got.Foreground, NewRGB(0.8019, 0.8019, 0.8019)) {
+		t.Errorf("translucent text = %+v", got)
+	}
+}
+
+func TestSetBlendMode(t *testing.T) {
+	buffer := newTestBuffer(t, 2, 1)
+	buffer.FillRect(0, 0, 2, 1, NewRGB(0.5, 0.5, 0.5))
+	if err := buffer.SetBlendMode(BlendLinear); err != nil {
+		t.Fatalf("SetBlendMode failed: %v", err)
+	}
+	if err := buffer.SetBlendMode(BlendLinear + 1); err == nil {
+		t.Error("SetBlendMode should reject unknown modes")
+	}
+	buffer.FillRect(0, 0, 1, 1, NewRGBA(1, 1, 1, 0.5))
+	buffer.SetCellWithAlphaBlending(1, 0, ' ', White, NewRGBA(1, 1, 1, 0.5), 0)
+	for x := uint32(0); x < 2; x++ {
+		if cell, _ := buffer.GetCell(x, 0); !closeColor(cell.Background, NewRGB(0.8019, 0.8019, 0.8019)) {
+			t.Errorf("cell %d background = %+v", x, cell.Background)
+		}
+	}
+	if clone, err := buffer.Clone(); err == nil {
+		defer clone.Close()
+		if mode, _ := clone.GetBlendMode(); mode != BlendLinear {
+			t.Errorf("clone blend mode = %v", mode)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package opentui
+
+import (
+	"os"
+	"strings"
+)
+
+// BorderStyle selects one of the built-in border character presets
+type BorderStyle uint8
+
+const (
+	BorderSingle BorderStyle = iota
+	BorderRounded
+	BorderDouble
+	BorderHeavy
+	BorderASCII
+)
+
+// RoundedBoxChars provides box drawing characters with rounded corners
+var RoundedBoxChars = [8]rune{
+	'╭', '─', '╮',
+	'│', '│',
+	'╰', '─', '╯',
+}
+
+// DoubleBoxChars provides double-line box drawing characters
+var DoubleBoxChars = [8]rune{
+	'╔', '═', '╗',
+	'║', '║',
+	'╚', '═', '╝',
+}
+
+// HeavyBoxChars provides heavy-line box drawing characters
+var HeavyBoxChars = [8]rune{
+	'┏', '━', '┓',
+	'┃', '┃',
+	'┗', '━', '┛',
+}
+
+// ASCIIBoxChars provides plain ASCII border characters for terminals without Unicode support
+var ASCIIBoxChars = [8]rune{
+	'+', '-', '+',
+	'|', '|',
+	'+', '-', '+',
+}
+
+// BoxChars returns the border characters for the style.
+// Unknown styles fall back to DefaultBoxChars.
+func (s BorderStyle) BoxChars() [8]rune {
+	switch s {
+	case BorderRounded:
+		return RoundedBoxChars
+	case BorderDouble:
+		return DoubleBoxChars
+	case BorderHeavy:
+		return HeavyBoxChars
+	case BorderASCII:
+		return ASCIIBoxChars
+	default:
+		return DefaultBoxChars
+	}
+}
+
+// WithStyle returns a copy of the options with BorderChars filled from the preset.
+func (o BoxOptions) WithStyle(style BorderStyle) BoxOptions {
+	o.BorderChars = style.BoxChars()
+	return o
+}
+
+// BorderStyleFor returns the preferred style if the terminal can display it,
+// or BorderASCII if caps reports that Unicode is unsupported.
+// A nil caps is treated as a Unicode capable terminal.
+func BorderStyleFor(preferred BorderStyle, caps *Capabilities) BorderStyle {
+	if caps != nil && !caps.SupportsUnicode {
+		return BorderASCII
+	}
+	return preferred
+}
+
+// detectUnicodeSupport guesses from the environment whether the terminal can display Unicode.
+// The locale is consulted in the usual precedence order; without any locale
+// only the dumb terminal is assumed to lack Unicode.
+func detectUnicodeSupport() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			value = strings.ToLower(value)
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+	return true
+}
+
+// DashPattern selects how the edges of a box border are broken up
+type DashPattern uint8
+
+const (
+	DashNone DashPattern = iota // Solid edges
+	Dashed2                     // Two dashes per cell (╌ ╎)
+	Dashed3                     // Three dashes per cell (┄ ┆)
+	Dotted                      // Four dashes per cell (┈ ┊)
+)
+
+// dashGlyphs holds the light and heavy horizontal and vertical glyphs of each dash pattern
+var dashGlyphs = map[DashPattern]struct{ light, heavy [2]rune }{
+	Dashed2: {light: [2]rune{'╌', '╎'}, heavy: [2]rune{'╍', '╏'}},
+	Dashed3: {light: [2]rune{'┄', '┆'}, heavy: [2]rune{'┅', '┇'}},
+	Dotted:  {light: [2]rune{'┈', '┊'}, heavy: [2]rune{'┉', '┋'}},
+}
+
+// apply substitutes the edge characters of chars with the dash pattern.
+// Heavy borders get heavy dashes, every other Unicode border gets light ones.
+// ASCII borders have no dashed variants and are returned unchanged.
+func (d DashPattern) apply(chars [8]rune) [8]rune {
+	glyphs, ok := dashGlyphs[d]
+	if !ok || chars == ASCIIBoxChars {
+		return chars
+	}
+
+	dashes := glyphs.light
+	if chars[1] == '━' {
+		dashes = glyphs.heavy
+	}
+	chars[1], chars[6] = dashes[0], dashes[0]
+	chars[3], chars[4] = dashes[1], dashes[1]
+	return chars
+}
+
+// Indices of the border characters of the native box renderer
+const (
+	nativeTopLeft = iota
+	nativeTopRight
+	nativeBottomLeft
+	nativeBottomRight
+	nativeHorizontal
+	nativeVertical
+	nativeTopT
+	nativeBottomT
+	nativeLeftT
+	nativeRightT
+	nativeCross
+	nativeBorderCount
+)
+
+// nativeBorderChars maps border characters in the order of BoxOptions.BorderChars to
+// the order of the native box renderer. It draws both horizontal edges with one
+// character and both vertical edges with another, so it gets the top and left ones
+// and fixBorderEdges redraws the bottom and right edges. Boxes draw no junctions; they
+// get the edge characters.
+func nativeBorderChars(chars [8]rune) [nativeBorderCount]uint32 {
+	var native [nativeBorderCount]uint32
+	native[nativeTopLeft] = uint32(chars[0])
+	native[nativeTopRight] = uint32(chars[2])
+	native[nativeBottomLeft] = uint32(chars[5])
+	native[nativeBottomRight] = uint32(chars[7])
+	native[nativeHorizontal] = uint32(chars[1])
+	native[nativeVertical] = uint32(chars[3])
+	native[nativeTopT] = uint32(chars[1])
+	native[nativeBottomT] = uint32(chars[6])
+	native[nativeLeftT] = uint32(chars[3])
+	native[nativeRightT] = uint32(chars[4])
+	native[nativeCross] = uint32(chars[1])
+	return native
+}
+
+// sameBorderEdges reports whether the bottom and right edges of chars match the top
+// and left ones, so that the native box renderer draws them as they are.
+func sameBorderEdges(chars [8]rune) bool {
+	return chars[6] == chars[1] && chars[4] == chars[3]
+}
+
+// fixBorderEdges redraws the bottom and right edges of a box the native renderer drew
+// with the top and left edge characters.
+func (b *Buffer) fixBorderEdges(x, y int32, width, height uint32, sides BorderSides, chars [8]rune) error {
+	if sameBorderEdges(chars) || width == 0 || height == 0 {
+		return nil
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return err
+	}
+	left, top := max(int64(x), 0), max(int64(y), 0)
+	right := min(int64(x)+int64(width), int64(da.Width)) - 1
+	bottom := min(int64(y)+int64(height), int64(da.Height)) - 1
+	if left > right || top > bottom {
+		return nil
+	}
+	swap := func(col, row int64, from, to rune) {
+		i := row*int64(da.Width) + col
+		if da.Chars[i] == uint32(from) {
+			da.Chars[i] = uint32(to)
+		}
+	}
+	if sides.Bottom && bottom == int64(y)+int64(height)-1 {
+		for col := left; col <= right; col++ {
+			swap(col, bottom, chars[1], chars[6])
+		}
+	}
+	if sides.Right && right == int64(x)+int64(width)-1 {
+		for row := top; row <= bottom; row++ {
+			swap(right, row, chars[3], chars[4])
+		}
+	}
+	return nil
+}
+
+// Title layout constants, matching the native box renderer
+const (
+	boxTitleMargin   = 2 // Cells between the box edge and the title
+	boxTitleMinSpace = 2 * boxTitleMargin
+)
+
+// drawBoxTitle draws the title of a box on its top border.
+// Titles wider than the space between the corners are truncated with an ellipsis.
+func (b *Buffer) drawBoxTitle(x, y int32, width uint32, options BoxOptions, borderColor, backgroundColor RGBA) {
+	if options.Title == "" || !options.Sides.Top || y < 0 {
+		return
+	}
+
+	padding := int(options.TitlePadding)
+	available := int(width) - boxTitleMinSpace - 2*padding
+	if available < 1 {
+		return
+	}
+
+	title := TruncateToWidth(options.Title, uint32(available), "…")
+	total := stringWidth(title) + 2*padding
+
+	offset := boxTitleMargin
+	switch options.TitleAlignment {
+	case AlignCenter:
+		offset = max(boxTitleMargin, (int(width)-total)/2)
+	case AlignRight:
+		offset = int(width) - boxTitleMargin - total
+	}
+
+	fg := borderColor
+	if options.TitleColor != nil {
+		fg = *options.TitleColor
+	}
+
+	spaces := strings.Repeat(" ", padding)
+	left := int64(x) + int64(offset)
+	b.drawClusters(spaces+title+spaces, left, int64(y), left, left+int64(total), fg, &backgroundColor, options.TitleAttributes)
+}
@@ -0,0 +1,187 @@
+package opentui
+
+import (
+	"testing"
+)
+
+func TestBorderPresets(t *testing.T) {
+	tests := []struct {
+		style BorderStyle
+		want  []string
+	}{
+		{BorderSingle, []string{"┌──┐", "│  │", "└──┘"}},
+		{BorderRounded, []string{"╭──╮", "│  │", "╰──╯"}},
+		{BorderDouble, []string{"╔══╗", "║  ║", "╚══╝"}},
+		{BorderHeavy, []string{"┏━━┓", "┃  ┃", "┗━━┛"}},
+		{BorderASCII, []string{"+--+", "|  |", "+--+"}},
+	}
+
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 4, 3)
+		options := BoxOptions{
+			Sides: BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+		}.WithStyle(tt.style)
+		if err := buffer.DrawBox(0, 0, 4, 3, options, White, Black); err != nil {
+			t.Fatalf("DrawBox failed: %v", err)
+		}
+		expectRows(t, buffer, tt.want...)
+	}
+}
+
+func TestBorderStyleFor(t *testing.T) {
+	if got := BorderStyleFor(BorderRounded, nil); got != BorderRounded {
+		t.Errorf("BorderStyleFor with nil caps = %v, want BorderRounded", got)
+	}
+	if got := BorderStyleFor(BorderRounded, &Capabilities{SupportsUnicode: true}); got != BorderRounded {
+		t.Errorf("BorderStyleFor with Unicode = %v, want BorderRounded", got)
+	}
+	if got := BorderStyleFor(BorderRounded, &Capabilities{}); got != BorderASCII {
+		t.Errorf("BorderStyleFor without Unicode = %v, want BorderASCII", got)
+	}
+}
+
+func TestDetectUnicodeSupport(t *testing.T) {
+	tests := []struct {
+		term, lcAll, lang string
+		want              bool
+	}{
+		{"xterm-256color", "", "en_US.UTF-8", true},
+		{"xterm-256color", "C", "en_US.UTF-8", false},
+		{"vt100", "", "C", false},
+		{"dumb", "", "en_US.UTF-8", false},
+		{"xterm", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv("TERM", tt.term)
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", "")
+		t.Setenv("LANG", tt.lang)
+		if got := detectUnicodeSupport(); got != tt.want {
+			t.Errorf("detectUnicodeSupport(TERM=%q LC_ALL=%q LANG=%q) = %v, want %v", tt.term, tt.lcAll, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestDashedBorders(t *testing.T) {
+	tests := []struct {
+		style BorderStyle
+		dash  DashPattern
+		want  []string
+	}{
+		{BorderSingle, DashNone, []string{"┌──┐", "│  │", "└──┘"}},
+		{BorderSingle, Dashed2, []string{"┌╌╌┐", "╎  ╎", "└╌╌┘"}},
+		{BorderRounded, Dashed3, []string{"╭┄┄╮", "┆  ┆", "╰┄┄╯"}},
+		{BorderHeavy, Dotted, []string{"┏┉┉┓", "┋  ┋", "┗┉┉┛"}},
+		{BorderASCII, Dotted, []string{"+--+", "|  |", "+--+"}},
+	}
+
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 4, 3)
+		options := BoxOptions{
+			Sides:      BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+			BorderDash: tt.dash,
+		}.WithStyle(tt.style)
+		if err := buffer.DrawBox(0, 0, 4, 3, options, White, Black); err != nil {
+			t.Fatalf("DrawBox failed: %v", err)
+		}
+		expectRows(t, buffer, tt.want...)
+	}
+}
+
+func TestBorderCharsOrder(t *testing.T) {
+	options := BoxOptions{
+		Sides:       BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+		BorderChars: [8]rune{'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h'},
+	}
+	want := []string{"abbc", "d  e", "fggh"}
+
+	buffer := newTestBuffer(t, 4, 3)
+	if err := buffer.DrawBox(0, 0, 4, 3, options, White, Black); err != nil {
+		t.Fatalf("DrawBox failed: %v", err)
+	}
+	expectRows(t, buffer, want...)
+
+	batched := newTestBuffer(t, 4, 3)
+	batch := batched.NewBatch()
+	batch.DrawBox(0, 0, 4, 3, options, White, Black)
+	if err := batch.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	expectRows(t, batched, want...)
+}
+
+// drawTitledBox draws a 3-row box with the given title options onto a fresh buffer.
+func drawTitledBox(t *testing.T, width uint32, options BoxOptions) *Buffer {
+	t.Helper()
+	buffer := newTestBuffer(t, width, 3)
+	options.Sides = BorderSides{Top: true, Right: true, Bottom: true, Left: true}
+	options.BorderChars = DefaultBoxChars
+	if err := buffer.DrawBox(0, 0, width, 3, options, White, Black); err != nil {
+		t.Fatalf("DrawBox failed: %v", err)
+	}
+	return buffer
+}
+
+func TestBoxTitleAlignment(t *testing.T) {
+	tests := []struct {
+		alignment TextAlignment
+		padding   uint8
+		want      string
+	}{
+		{AlignLeft, 0, "┌─Hi─────┐"},
+		{AlignCenter, 0, "┌───Hi───┐"},
+		{AlignRight, 0, "┌─────Hi─┐"},
+		{AlignLeft, 1, "┌─ Hi ───┐"},
+		{AlignCenter, 1, "┌── Hi ──┐"},
+		{AlignRight, 1, "┌─── Hi ─┐"},
+	}
+
+	for _, tt := range tests {
+		buffer := drawTitledBox(t, 10, BoxOptions{Title: "Hi", TitleAlignment: tt.alignment, TitlePadding: tt.padding})
+		if got := bufferRows(t, buffer)[0]; got != tt.want {
+			t.Errorf("alignment %d padding %d: top row = %q, want %q", tt.alignment, tt.padding, got, tt.want)
+		}
+	}
+}
+
+func TestBoxTitleTruncation(t *testing.T) {
+	buffer := drawTitledBox(t, 10, BoxOptions{Title: "Hello, wide world"})
+	if got := bufferRows(t, buffer)[0]; got != "┌─Hello…─┐" {
+		t.Errorf("top row = %q, want %q", got, "┌─Hello…─┐")
+	}
+
+	// A 20-rune CJK title is 40 cells wide; only two characters fit in the 6 available cells,
+	// and a space fills the cell a third one would half cover
+	title := "漢字漢字漢字漢字漢字漢字漢字漢字漢字漢字"
+	titleColor := Yellow
+	buffer = drawTitledBox(t, 10, BoxOptions{Title: title, TitleColor: &titleColor, TitleAttributes: AttrBold})
+
+	da, err := buffer.GetDirectAccess()
+	if err != nil {
+		t.Fatalf("GetDirectAccess failed: %v", err)
+	}
+	cells := map[uint32]rune{0: '┌', 1: '─', 2: '漢', 4: '字', 6: ' ', 7: '…', 8: '─', 9: '┐'}
+	for x, want := range cells {
+		cell, _ := da.GetCell(x, 0)
+		if cell.Char != want {
+			t.Errorf("cell %d = %q, want %q", x, cell.Char, want)
+		}
+		isTitle := x == 2 || x == 4 || x == 6 || x == 7
+		if isTitle && (cell.Foreground != Yellow || cell.Attributes != AttrBold) {
+			t.Errorf("cell %d not styled as title: %+v", x, cell)
+		}
+		if !isTitle && cell.Foreground != White {
+			t.Errorf("cell %d should keep the border color: %+v", x, cell)
+		}
+	}
+
+	// Padding leaves room for a single wide character
+	buffer = drawTitledBox(t, 10, BoxOptions{Title: title, TitlePadding: 1})
+	row := []rune(bufferRows(t, buffer)[0])
+	for x, want := range map[int]rune{2: ' ', 3: '漢', 5: ' ', 6: '…', 7: ' ', 8: '─'} {
+		if got := row[x]; got != want {
+			t.Errorf("padded cell %d = %q, want %q", x, got, want)
+		}
+	}
+}
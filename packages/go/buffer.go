@@ -2,18 +2,27 @@ package opentui
 
 /*
 #include "opentui.h"
+#include "color.h"
 #include <stdlib.h>
 */
 import "C"
 import (
+	"runtime"
+	"strings"
 	"unsafe"
 )
 
 // Buffer wraps the OptimizedBuffer from the C library.
 // It represents a 2D array of terminal cells for efficient rendering.
 type Buffer struct {
-	ptr     *C.OptimizedBuffer
-	managed bool // true if buffer is managed by renderer
+	ptr          *C.OptimizedBuffer
+	managed      bool       // true if buffer is managed by renderer
+	widthMethod  uint8      // width method the buffer was created with
+	graphemes    bool       // true if DrawText places whole grapheme clusters
+	tabWidth     uint8      // distance between tab stops, 0 means defaultTabWidth
+	absoluteTabs bool       // true if tab stops are counted from column 0 instead of the draw origin
+	links        *linkTable // hyperlinks of the cells, shared with the renderer for its next buffer
+	blendMode    BlendMode  // how translucent colors are blended with existing cells
 }
 
 // WidthMethod constants for Unicode width calculation
@@ -26,7 +35,7 @@ const (
 // If respectAlpha is true, the buffer will handle alpha blending.
 // The widthMethod parameter controls how text width is calculated (use WidthMethodUnicode for full Unicode support).
 func NewBuffer(width, height uint32, respectAlpha bool, widthMethod uint8) *Buffer {
-	if width == 0 || height == 0 {
+	if checkDimensions(width, height) != nil {
 		return nil
 	}
 	
@@ -35,7 +44,7 @@ func NewBuffer(width, height uint32, respectAlpha bool, widthMethod uint8) *Buff
 		return nil
 	}
 	
-	b := &Buffer{ptr: ptr, managed: false}
+	b := &Buffer{ptr: ptr, managed: false, widthMethod: widthMethod}
 	setFinalizer(b, func(b *Buffer) { b.Close() })
 	return b
 }
@@ -55,7 +64,7 @@ func (b *Buffer) Close() error {
 // Width returns the buffer width in cells.
 func (b *Buffer) Width() (uint32, error) {
 	if b.ptr == nil {
-		return 0, newError("buffer is closed")
+		return 0, closedError("buffer")
 	}
 	return uint32(C.getBufferWidth(b.ptr)), nil
 }
@@ -63,7 +72,7 @@ func (b *Buffer) Width() (uint32, error) {
 // Height returns the buffer height in cells.
 func (b *Buffer) Height() (uint32, error) {
 	if b.ptr == nil {
-		return 0, newError("buffer is closed")
+		return 0, closedError("buffer")
 	}
 	return uint32(C.getBufferHeight(b.ptr)), nil
 }
@@ -71,7 +80,7 @@ func (b *Buffer) Height() (uint32, error) {
 // Size returns the buffer dimensions.
 func (b *Buffer) Size() (uint32, uint32, error) {
 	if b.ptr == nil {
-		return 0, 0, newError("buffer is closed")
+		return 0, 0, closedError("buffer")
 	}
 	w := uint32(C.getBufferWidth(b.ptr))
 	h := uint32(C.getBufferHeight(b.ptr))
@@ -81,16 +90,19 @@ func (b *Buffer) Size() (uint32, uint32, error) {
 // Clear fills the entire buffer with the specified background color.
 func (b *Buffer) Clear(bg RGBA) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
+	}
+	C.bufferClearValue(b.ptr, bg.toC())
+	if b.links != nil {
+		b.links.reset()
 	}
-	C.bufferClear(b.ptr, bg.toCFloat())
 	return nil
 }
 
 // GetRespectAlpha returns whether the buffer respects alpha values.
 func (b *Buffer) GetRespectAlpha() (bool, error) {
 	if b.ptr == nil {
-		return false, newError("buffer is closed")
+		return false, closedError("buffer")
 	}
 	return bool(C.bufferGetRespectAlpha(b.ptr)), nil
 }
@@ -98,54 +110,212 @@ func (b *Buffer) GetRespectAlpha() (bool, error) {
 // SetRespectAlpha sets whether the buffer should respect alpha values.
 func (b *Buffer) SetRespectAlpha(respectAlpha bool) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
 	C.bufferSetRespectAlpha(b.ptr, C.bool(respectAlpha))
 	return nil
 }
 
+// SetGraphemeClusters sets whether DrawText treats grapheme clusters as units.
+// When enabled, emoji sequences, flags and characters with combining marks are each
+// passed to the native layer as a whole and placed at their display column, so the
+// following text lines up with the measured width of every cluster.
+func (b *Buffer) SetGraphemeClusters(enabled bool) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	b.graphemes = enabled
+	return nil
+}
+
+// SetTabWidth sets the distance between tab stops used to expand tabs in drawn text.
+// The default is 8.
+func (b *Buffer) SetTabWidth(width uint8) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if width == 0 {
+		return newError("tab width must be at least 1")
+	}
+	b.tabWidth = width
+	return nil
+}
+
+// SetAbsoluteTabStops sets whether tab stops are counted from column 0 of the buffer.
+// By default they are counted from the x position text is drawn at.
+func (b *Buffer) SetAbsoluteTabStops(absolute bool) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	b.absoluteTabs = absolute
+	return nil
+}
+
+// expandTabs replaces tabs in text drawn at column x with spaces up to the next tab stop.
+func (b *Buffer) expandTabs(text string, x int64) string {
+	origin := int64(0)
+	if b.absoluteTabs {
+		origin = x
+	}
+	text, _ = expandTabs(text, origin, origin, b.tabWidth)
+	return text
+}
+
 // DrawText draws text at the specified position with the given colors and attributes.
-func (b *Buffer) DrawText(text string, x, y uint32, fg RGBA, bg *RGBA, attributes uint8) error {
+// Each line of a multi-line text starts at x on the next row, see DrawTextLines.
+// Tabs are expanded to the next tab stop, see SetTabWidth.
+func (b *Buffer) DrawText(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) error {
+	_, err := b.DrawTextLines(text, x, y, fg, bg, attributes)
+	return err
+}
+
+// DrawTextLines draws text split on "\n" or "\r\n", starting each line at x one row
+// below the previous one. Lines falling below the buffer are clipped.
+// Returns the number of lines drawn.
+func (b *Buffer) DrawTextLines(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) (uint32, error) {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return 0, closedError("buffer")
 	}
 	
+	height := uint32(C.getBufferHeight(b.ptr))
+	var lines uint32
+	for y+lines < height {
+		line, rest, more := strings.Cut(text, "\n")
+		b.drawLine(strings.TrimSuffix(line, "\r"), x, y+lines, fg, bg, attributes)
+		lines++
+		if !more {
+			break
+		}
+		text = rest
+	}
+	return lines, nil
+}
+
+// drawLine draws a single line of text, expanding tabs and placing grapheme clusters
+// when enabled.
+func (b *Buffer) drawLine(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) {
+	text = b.expandTabs(text, int64(x))
+	width := uint32(stringWidth(text))
+	b.links.clearRect(x, y, width, 1)
+	if b.graphemes {
+		col := x
+		for g := Graphemes(text); g.Next(); {
+			// Zero width clusters such as control characters take no cell
+			if width := g.Width(); width > 0 {
+				b.drawText(g.Str(), col, y, fg, bg, attributes)
+				col += uint32(width)
+			}
+		}
+	} else {
+		b.drawText(text, x, y, fg, bg, attributes)
+	}
+}
+
+// drawText passes text to the native layer as it is.
+func (b *Buffer) drawText(text string, x, y uint32, fg RGBA, bg *RGBA, attributes Attributes) {
 	textPtr, textLen := stringToC(text)
 	if textPtr == nil {
-		return nil // Empty string, nothing to draw
+		return // Empty string, nothing to draw
 	}
 	
-	var bgPtr *C.float
-	if bg != nil {
-		bgPtr = bg.toCFloat()
-	}
-	
-	C.bufferDrawText(b.ptr, textPtr, textLen, C.uint32_t(x), C.uint32_t(y), fg.toCFloat(), bgPtr, C.uint8_t(attributes))
-	return nil
+	bgColor, hasBg := optionalColor(bg)
+	C.bufferDrawTextValue(b.ptr, textPtr, textLen, C.uint32_t(x), C.uint32_t(y), fg.toC(), bgColor, hasBg, C.uint16_t(attributes))
+	runtime.KeepAlive(text)
 }
 
 // SetCellWithAlphaBlending sets a single cell with alpha blending support.
-func (b *Buffer) SetCellWithAlphaBlending(x, y uint32, char rune, fg, bg RGBA, attributes uint8) error {
+// Translucent colors are blended according to the blend mode of the buffer.
+func (b *Buffer) SetCellWithAlphaBlending(x, y uint32, char rune, fg, bg RGBA, attributes Attributes) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
-	C.bufferSetCellWithAlphaBlending(b.ptr, C.uint32_t(x), C.uint32_t(y), C.uint32_t(char), fg.toCFloat(), bg.toCFloat(), C.uint8_t(attributes))
+	b.setCellBlended(x, y, char, fg, bg, attributes)
 	return nil
 }
 
+// setCellBlended writes a single cell with alpha blending. A double width character
+// covers the next cell too.
+func (b *Buffer) setCellBlended(x, y uint32, char rune, fg, bg RGBA, attributes Attributes) {
+	overlay := Cell{Char: char, Foreground: fg, Background: bg, Attributes: attributes}
+	switch {
+	case b.blendMode == BlendLinear:
+		if da, err := b.directAccess(); err == nil {
+			da.blendCell(x, y, overlay)
+		}
+	case runeWidth(char) == 2:
+		// Only text drawing puts wide characters into the grapheme pool
+		b.drawText(string(char), x, y, fg, &bg, attributes)
+	default:
+		C.bufferSetCellWithAlphaBlendingValue(b.ptr, C.uint32_t(x), C.uint32_t(y), C.uint32_t(char), fg.toC(), bg.toC(), C.uint16_t(attributes))
+	}
+}
+
+// GetCell returns the cell at the specified coordinates.
+func (b *Buffer) GetCell(x, y uint32) (Cell, error) {
+	if b.ptr == nil {
+		return Cell{}, closedError("buffer")
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return Cell{}, err
+	}
+	cell, err := da.GetCell(x, y)
+	if err != nil {
+		return Cell{}, err
+	}
+	return *cell, nil
+}
+
+// SetCell overwrites the cell at the specified coordinates exactly, without alpha blending.
+// Use SetCellWithAlphaBlending to blend translucent colors with the existing cell.
+func (b *Buffer) SetCell(x, y uint32, cell Cell) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return err
+	}
+	return da.SetCell(x, y, cell)
+}
+
+// SetChar replaces the character at the specified coordinates, keeping its colors and attributes.
+func (b *Buffer) SetChar(x, y uint32, char rune) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return err
+	}
+	if x >= da.Width || y >= da.Height {
+		return boundsError("coordinates")
+	}
+	i := y*da.Width + x
+	return da.SetCell(x, y, Cell{Char: char, Foreground: da.Foreground[i], Background: da.Background[i], Attributes: da.Attributes[i]})
+}
+
 // FillRect fills a rectangular area with the specified background color.
+// A translucent color is blended according to the blend mode of the buffer.
 func (b *Buffer) FillRect(x, y, width, height uint32, bg RGBA) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
+	}
+	if b.blendMode == BlendLinear && bg.A < 1 {
+		if da, err := b.directAccess(); err == nil {
+			da.fillRectLinear(x, y, width, height, bg)
+		}
+	} else {
+		C.bufferFillRectValue(b.ptr, C.uint32_t(x), C.uint32_t(y), C.uint32_t(width), C.uint32_t(height), bg.toC())
 	}
-	C.bufferFillRect(b.ptr, C.uint32_t(x), C.uint32_t(y), C.uint32_t(width), C.uint32_t(height), bg.toCFloat())
+	b.links.clearRect(x, y, width, height)
 	return nil
 }
 
 // DrawPackedBuffer draws packed buffer data at the specified position.
 func (b *Buffer) DrawPackedBuffer(data []byte, posX, posY, terminalWidthCells, terminalHeightCells uint32) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
 	if len(data) == 0 {
 		return nil
@@ -160,7 +330,7 @@ func (b *Buffer) DrawPackedBuffer(data []byte, posX, posY, terminalWidthCells, t
 // DrawSuperSampleBuffer draws super-sampled pixel data for high-resolution graphics.
 func (b *Buffer) DrawSuperSampleBuffer(x, y uint32, pixelData []byte, format SuperSampleFormat, alignedBytesPerRow uint32) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
 	if len(pixelData) == 0 {
 		return nil
@@ -175,26 +345,29 @@ func (b *Buffer) DrawSuperSampleBuffer(x, y uint32, pixelData []byte, format Sup
 // DrawBox draws a box with optional borders and title.
 func (b *Buffer) DrawBox(x, y int32, width, height uint32, options BoxOptions, borderColor, backgroundColor RGBA) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
 	
 	// Convert border characters to C array
-	borderChars := runesToC(options.BorderChars[:])
+	chars := options.BorderDash.apply(options.BorderChars)
+	native := nativeBorderChars(chars)
+	borderChars := (*C.uint32_t)(unsafe.Pointer(&native[0]))
 	
 	// Pack options
 	packed := packBorderOptions(options.Sides, options.Fill, uint8(options.TitleAlignment))
 	
-	// Handle title
-	var titlePtr *C.uint8_t
-	var titleLen C.uint32_t
-	if options.Title != "" {
-		ptr, len := stringToC(options.Title)
-		titlePtr = ptr
-		titleLen = C.uint32_t(len)
+	// The title is laid out on the Go side so it can be styled and truncated
+	err := checkNative(func() {
+		C.bufferDrawBoxValue(b.ptr, C.int32_t(x), C.int32_t(y), C.uint32_t(width), C.uint32_t(height),
+			borderChars, packed, borderColor.toC(), backgroundColor.toC())
+	})
+	if err != nil {
+		return err
 	}
-	
-	C.bufferDrawBox(b.ptr, C.int32_t(x), C.int32_t(y), C.uint32_t(width), C.uint32_t(height),
-		borderChars, packed, borderColor.toCFloat(), backgroundColor.toCFloat(), titlePtr, titleLen)
+	if err := b.fixBorderEdges(x, y, width, height, options.Sides, chars); err != nil {
+		return err
+	}
+	b.drawBoxTitle(x, y, width, options, borderColor, backgroundColor)
 	return nil
 }
 
@@ -202,22 +375,38 @@ func (b *Buffer) DrawBox(x, y int32, width, height uint32, options BoxOptions, b
 // This may invalidate any existing content.
 func (b *Buffer) Resize(width, height uint32) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
-	if width == 0 || height == 0 {
-		return newError("invalid dimensions")
+	if err := checkDimensions(width, height); err != nil {
+		return err
+	}
+	return b.resize(width, height)
+}
+
+// resize resizes the native buffer, leaving the checks of the dimensions to it.
+func (b *Buffer) resize(width, height uint32) error {
+	if err := checkNative(func() { C.bufferResize(b.ptr, C.uint32_t(width), C.uint32_t(height)) }); err != nil {
+		return err
+	}
+	if b.links != nil {
+		b.links.reset()
 	}
-	C.bufferResize(b.ptr, C.uint32_t(width), C.uint32_t(height))
 	return nil
 }
 
 // DrawFrameBuffer draws another buffer onto this buffer at the specified position.
+// The cells it draws over take the hyperlinks of the other buffer.
 func (b *Buffer) DrawFrameBuffer(destX, destY int32, frameBuffer *Buffer, sourceX, sourceY, sourceWidth, sourceHeight uint32) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
 	if frameBuffer == nil || frameBuffer.ptr == nil {
-		return newError("frame buffer is nil or closed")
+		return kindError(ErrClosed, "frame buffer is nil or closed")
+	}
+	if b.links.active() || frameBuffer.links.active() {
+		if err := b.drawFrameLinks(destX, destY, frameBuffer, sourceX, sourceY, sourceWidth, sourceHeight); err != nil {
+			return err
+		}
 	}
 	
 	C.drawFrameBuffer(b.ptr, C.int32_t(destX), C.int32_t(destY), frameBuffer.ptr,
@@ -226,12 +415,16 @@ func (b *Buffer) DrawFrameBuffer(destX, destY int32, frameBuffer *Buffer, source
 }
 
 // DrawTextBuffer draws a text buffer onto this buffer with optional clipping.
+// A text buffer wrapped with WrapToWidth is drawn as its wrapped lines.
 func (b *Buffer) DrawTextBuffer(textBuffer *TextBuffer, x, y int32, clipRect *ClipRect) error {
 	if b.ptr == nil {
-		return newError("buffer is closed")
+		return closedError("buffer")
 	}
 	if textBuffer == nil || textBuffer.ptr == nil {
-		return newError("text buffer is nil or closed")
+		return kindError(ErrClosed, "text buffer is nil or closed")
+	}
+	if textBuffer.layout != nil {
+		return b.drawTextLines(textBuffer, x, y, 0, ^uint32(0), clipRect)
 	}
 	
 	var clipX, clipY C.int32_t
@@ -255,13 +448,23 @@ func (b *Buffer) DrawTextBuffer(textBuffer *TextBuffer, x, y int32, clipRect *Cl
 // This is an advanced feature for performance-critical operations.
 // The returned slices are valid until the buffer is resized or closed.
 func (b *Buffer) GetDirectAccess() (*DirectAccess, error) {
+	da, err := b.directAccess()
+	if err != nil {
+		return nil, err
+	}
+	return &da, nil
+}
+
+// directAccess returns direct access to the buffer's arrays by value, which keeps
+// the drawing calls that use it from allocating.
+func (b *Buffer) directAccess() (DirectAccess, error) {
 	if b.ptr == nil {
-		return nil, newError("buffer is closed")
+		return DirectAccess{}, closedError("buffer")
 	}
 	
 	width, height, err := b.Size()
 	if err != nil {
-		return nil, err
+		return DirectAccess{}, err
 	}
 	
 	size := int(width * height)
@@ -271,48 +474,68 @@ func (b *Buffer) GetDirectAccess() (*DirectAccess, error) {
 	bgPtr := C.bufferGetBgPtr(b.ptr)
 	attrPtr := C.bufferGetAttributesPtr(b.ptr)
 	
-	return &DirectAccess{
+	return DirectAccess{
 		Chars:      cArrayToSlice((*uint32)(charPtr), size),
 		Foreground: cArrayToSlice((*RGBA)(unsafe.Pointer(fgPtr)), size),
 		Background: cArrayToSlice((*RGBA)(unsafe.Pointer(bgPtr)), size),
-		Attributes: cArrayToSlice((*uint8)(attrPtr), size),
+		Attributes: cArrayToSlice((*Attributes)(attrPtr), size),
 		Width:      width,
 		Height:     height,
+		ptr:        b.ptr,
 	}, nil
 }
 
 // DirectAccess provides direct access to buffer internal arrays for performance-critical operations.
 // Warning: This is an advanced feature. Modifying these slices directly bypasses normal safety checks.
+//
+// Chars holds the code points of narrow characters; cells of other grapheme clusters,
+// including wide characters and accented letters, hold references to the clusters in
+// the native grapheme pool, with the high bit set. GetCell resolves them.
 type DirectAccess struct {
-	Chars      []uint32 // Character codes (Unicode code points)
-	Foreground []RGBA   // Foreground colors
-	Background []RGBA   // Background colors
-	Attributes []uint8  // Text attributes
-	Width      uint32   // Buffer width
-	Height     uint32   // Buffer height
+	Chars      []uint32     // Character codes (Unicode code points or grapheme pool references)
+	Foreground []RGBA       // Foreground colors
+	Background []RGBA       // Background colors
+	Attributes []Attributes // Text attributes
+	Width      uint32       // Buffer width
+	Height     uint32       // Buffer height
+
+	ptr *C.OptimizedBuffer // native buffer, to resolve grapheme pool references
 }
 
 // GetCell returns the cell at the specified coordinates using direct access.
+// The character of a cell holding a grapheme cluster is the first code point of the
+// cluster, see GetCluster; the further cells of a wide cluster read as spaces.
 func (da *DirectAccess) GetCell(x, y uint32) (*Cell, error) {
 	if x >= da.Width || y >= da.Height {
-		return nil, newError("coordinates out of bounds")
+		return nil, boundsError("coordinates")
 	}
 	
 	index := y*da.Width + x
 	return &Cell{
-		Char:       rune(da.Chars[index]),
+		Char:       cellChar(da.cellText(index)),
 		Foreground: da.Foreground[index],
 		Background: da.Background[index],
 		Attributes: da.Attributes[index],
 	}, nil
 }
 
-// SetCell sets the cell at the specified coordinates using direct access.
+// SetCell sets the cell at the specified coordinates using direct access. Like
+// Buffer.SetCell, it blanks the rest of a wide character it overwrites, and a double
+// width character covers the next cell too.
 func (da *DirectAccess) SetCell(x, y uint32, cell Cell) error {
 	if x >= da.Width || y >= da.Height {
-		return newError("coordinates out of bounds")
+		return boundsError("coordinates")
 	}
 	
+	if da.ptr != nil && runeWidth(cell.Char) == 2 {
+		da.setWideCell(x, y, cell)
+		return nil
+	}
+	if da.ptr != nil {
+		C.bufferSetCellValue(da.ptr, C.uint32_t(x), C.uint32_t(y), C.uint32_t(cell.Char),
+			cell.Foreground.toC(), cell.Background.toC(), C.uint16_t(cell.Attributes))
+		return nil
+	}
 	index := y*da.Width + x
 	da.Chars[index] = uint32(cell.Char)
 	da.Foreground[index] = cell.Foreground
@@ -321,6 +544,57 @@ func (da *DirectAccess) SetCell(x, y uint32, cell Cell) error {
 	return nil
 }
 
+// Clone returns an independent copy of the buffer with the same size, width method,
+// alpha handling, blend mode and cell contents. The clone is unmanaged even when the
+// original belongs to a renderer, so it is released by Close or its finalizer.
+func (b *Buffer) Clone() (*Buffer, error) {
+	if b.ptr == nil {
+		return nil, closedError("buffer")
+	}
+	
+	width, height, err := b.Size()
+	if err != nil {
+		return nil, err
+	}
+	respectAlpha, err := b.GetRespectAlpha()
+	if err != nil {
+		return nil, err
+	}
+	
+	clone := NewBuffer(width, height, respectAlpha, b.widthMethod)
+	if clone == nil {
+		return nil, newError("failed to create buffer")
+	}
+	clone.graphemes = b.graphemes
+	clone.tabWidth = b.tabWidth
+	clone.absoluteTabs = b.absoluteTabs
+	clone.blendMode = b.blendMode
+	if b.links.active() {
+		clone.links = &linkTable{}
+		clone.links.copyFrom(b.links)
+	}
+	
+	src, err := b.GetDirectAccess()
+	if err != nil {
+		clone.Close()
+		return nil, err
+	}
+	dst, err := clone.GetDirectAccess()
+	if err != nil {
+		clone.Close()
+		return nil, err
+	}
+	if src.hasClusters() {
+		copyCellRect(dst, src, 0, 0, 0, 0, width, height)
+		return clone, nil
+	}
+	copy(dst.Chars, src.Chars)
+	copy(dst.Foreground, src.Foreground)
+	copy(dst.Background, src.Background)
+	copy(dst.Attributes, src.Attributes)
+	return clone, nil
+}
+
 // Valid checks if the buffer is still valid (not closed).
 func (b *Buffer) Valid() bool {
 	return b.ptr != nil
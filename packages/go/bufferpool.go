@@ -0,0 +1,190 @@
+package opentui
+
+import "sync"
+
+const (
+	defaultPoolSize        = 16  // Idle buffers a pool keeps when MaxIdle is 0
+	defaultResizeThreshold = 0.5 // Cell count difference up to which a pooled buffer is resized
+)
+
+// BufferPool keeps offscreen buffers for reuse, e.g. for panels drawn into their own
+// buffers every frame, so that a frame doesn't create and destroy native buffers. Get
+// hands out a pooled buffer of the requested size, or resizes one of a close size, and
+// Put gives it back once it's been composited.
+//
+// Pooled buffers have no finalizer, so a buffer forgotten in the pool is only freed by
+// Close or when the pool itself is collected; buffers handed out by Get have theirs
+// re-armed and are freed like any other buffer if they're never put back.
+// A BufferPool is safe for concurrent use.
+type BufferPool struct {
+	// MaxIdle is the number of buffers kept while not in use, 0 means 16. Buffers put
+	// back beyond it are closed.
+	MaxIdle int
+	// ResizeThreshold is how much the cell count of a pooled buffer may differ from
+	// the requested one, as a fraction of the requested one, for Get to resize it
+	// instead of creating a new buffer. 0 means 0.5, a negative value only reuses
+	// buffers of the exact size.
+	ResizeThreshold float64
+
+	mu          sync.Mutex
+	idle        []*Buffer
+	widthMethod uint8
+}
+
+// NewBufferPool creates a pool of buffers using widthMethod to measure text, keeping
+// up to maxIdle buffers while they're not in use (0 means 16).
+func NewBufferPool(maxIdle int, widthMethod uint8) *BufferPool {
+	p := &BufferPool{MaxIdle: maxIdle, widthMethod: widthMethod}
+	setFinalizer(p, func(p *BufferPool) { p.Close() })
+	return p
+}
+
+// Get returns a cleared buffer of width by height cells, reusing a pooled buffer when
+// there's one of the same size or, failing that, one close enough to resize. Buffers
+// respecting alpha are cleared to Transparent, others to Black. Get returns nil if the
+// dimensions are invalid or the buffer can't be created, like NewBuffer.
+func (p *BufferPool) Get(width, height uint32, respectAlpha bool) *Buffer {
+	if checkDimensions(width, height) != nil {
+		return nil
+	}
+	bg := Black
+	if respectAlpha {
+		bg = Transparent
+	}
+
+	for {
+		b, exact := p.take(width, height)
+		if b == nil {
+			break
+		}
+		if !exact && b.Resize(width, height) != nil {
+			b.Close()
+			continue
+		}
+		p.reset(b)
+		if b.SetRespectAlpha(respectAlpha) != nil || b.Clear(bg) != nil {
+			b.Close()
+			continue
+		}
+		setFinalizer(b, func(b *Buffer) { b.Close() })
+		return b
+	}
+
+	b := NewBuffer(width, height, respectAlpha, p.widthMethod)
+	if b == nil {
+		return nil
+	}
+	if err := b.Clear(bg); err != nil {
+		b.Close()
+		return nil
+	}
+	return b
+}
+
+// Put gives a buffer back to the pool, or closes it if the pool already keeps MaxIdle
+// buffers. The buffer must not be used afterwards. Buffers of a renderer, closed
+// buffers and buffers measuring text differently from the pool are not pooled; the
+// latter are closed.
+func (p *BufferPool) Put(b *Buffer) {
+	if b == nil || b.ptr == nil || b.managed {
+		return
+	}
+	if b.widthMethod != p.widthMethod {
+		b.Close()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, idle := range p.idle {
+		if idle == b {
+			return
+		}
+	}
+	if len(p.idle) >= p.maxIdle() {
+		b.Close()
+		return
+	}
+	clearFinalizer(b)
+	p.idle = append(p.idle, b)
+}
+
+// Len returns the number of buffers in the pool.
+func (p *BufferPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.idle)
+}
+
+// Close frees the pooled buffers. Buffers handed out before are not affected, and the
+// pool can still be used.
+func (p *BufferPool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+	for _, b := range idle {
+		b.Close()
+	}
+	return nil
+}
+
+func (p *BufferPool) maxIdle() int {
+	if p.MaxIdle <= 0 {
+		return defaultPoolSize
+	}
+	return p.MaxIdle
+}
+
+func (p *BufferPool) resizeThreshold() float64 {
+	if p.ResizeThreshold == 0 {
+		return defaultResizeThreshold
+	}
+	return p.ResizeThreshold
+}
+
+// take removes a buffer of width by height cells from the pool, or else the one whose
+// cell count is closest to it within the resize threshold, reporting whether its size
+// is exact. It returns nil if no pooled buffer fits.
+func (p *BufferPool) take(width, height uint32) (*Buffer, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cells := float64(width) * float64(height)
+	limit := cells * p.resizeThreshold()
+	best, bestDiff := -1, 0.0
+	for i, b := range p.idle {
+		w, h, err := b.Size()
+		if err != nil {
+			continue
+		}
+		if w == width && h == height {
+			best = i
+			break
+		}
+		diff := float64(w)*float64(h) - cells
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff <= limit && (best < 0 || diff < bestDiff) {
+			best, bestDiff = i, diff
+		}
+	}
+	if best < 0 {
+		return nil, false
+	}
+
+	b := p.idle[best]
+	last := len(p.idle) - 1
+	p.idle[best] = p.idle[last]
+	p.idle[last] = nil
+	p.idle = p.idle[:last]
+	w, h, _ := b.Size()
+	return b, w == width && h == height
+}
+
+// reset drops the drawing settings of the previous user of a pooled buffer, so it
+// draws like a new one.
+func (p *BufferPool) reset(b *Buffer) {
+	*b = Buffer{ptr: b.ptr, widthMethod: b.widthMethod}
+}
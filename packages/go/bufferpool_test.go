@@ -0,0 +1,188 @@
+package opentui
+
+import (
+	"runtime"
+	"testing"
+)
+
+// newTestPool returns a pool of maxIdle buffers, skipping the test when buffers can't
+// be created.
+func newTestPool(t testing.TB, maxIdle int) *BufferPool {
+	t.Helper()
+	probe := NewBuffer(1, 1, false, WidthMethodUnicode)
+	if probe == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	probe.Close()
+	pool := NewBufferPool(maxIdle, WidthMethodUnicode)
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func TestBufferPoolReuse(t *testing.T) {
+	pool := newTestPool(t, 4)
+	b := pool.Get(20, 5, false)
+	if b == nil {
+		t.Fatal("Get returned nil")
+	}
+	b.DrawText("left over", 0, 0, White, nil, AttrBold)
+	b.SetBlendMode(BlendLinear)
+	b.SetTabWidth(2)
+	pool.Put(b)
+	if pool.Len() != 1 {
+		t.Fatalf("Len() = %d after Put, want 1", pool.Len())
+	}
+
+	again := pool.Get(20, 5, true)
+	if again != b {
+		t.Fatal("Get did not reuse the pooled buffer")
+	}
+	if pool.Len() != 0 {
+		t.Errorf("Len() = %d after Get, want 0", pool.Len())
+	}
+	if cell, _ := again.GetCell(0, 0); cell.Char != ' ' || cell.Background != Transparent || cell.Attributes != 0 {
+		t.Errorf("reused buffer not cleared: %+v", cell)
+	}
+	if alpha, _ := again.GetRespectAlpha(); !alpha {
+		t.Error("reused buffer doesn't respect alpha")
+	}
+	if mode, _ := again.GetBlendMode(); mode != BlendSRGB {
+		t.Errorf("reused buffer blends with %v", mode)
+	}
+
+	pool.Put(again)
+	pool.Put(again) // Putting a buffer twice keeps it once
+	if pool.Len() != 1 {
+		t.Errorf("Len() = %d after putting a buffer twice, want 1", pool.Len())
+	}
+}
+
+func TestBufferPoolResize(t *testing.T) {
+	pool := newTestPool(t, 4)
+	b := pool.Get(20, 5, false)
+	pool.Put(b)
+
+	near := pool.Get(20, 6, false) // 120 cells for 100, within the default threshold
+	if near != b {
+		t.Fatal("Get did not resize the pooled buffer")
+	}
+	if w, h, _ := near.Size(); w != 20 || h != 6 {
+		t.Errorf("Size() = %dx%d, want 20x6", w, h)
+	}
+	pool.Put(near)
+
+	far := pool.Get(80, 24, false)
+	if far == b {
+		t.Error("Get resized a buffer far from the requested size")
+	}
+	if pool.Len() != 1 {
+		t.Errorf("Len() = %d, want the small buffer kept", pool.Len())
+	}
+	far.Close()
+
+	pool.ResizeThreshold = -1
+	if exact := pool.Get(20, 5, false); exact == b {
+		t.Error("Get resized a buffer with resizing disabled")
+	} else {
+		exact.Close()
+	}
+}
+
+func TestBufferPoolCap(t *testing.T) {
+	pool := newTestPool(t, 2)
+	buffers := []*Buffer{pool.Get(4, 4, false), pool.Get(4, 4, false), pool.Get(4, 4, false)}
+	for _, b := range buffers {
+		pool.Put(b)
+	}
+	if pool.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", pool.Len())
+	}
+	if buffers[2].Valid() {
+		t.Error("buffer put beyond the cap not closed")
+	}
+
+	pool.Close()
+	if pool.Len() != 0 || buffers[0].Valid() || buffers[1].Valid() {
+		t.Error("Close kept pooled buffers")
+	}
+
+	other := NewBuffer(4, 4, false, WidthMethodWCWidth)
+	pool.Put(other)
+	if pool.Len() != 0 || other.Valid() {
+		t.Error("buffer with another width method pooled")
+	}
+	pool.Put(nil)
+	pool.Put(other)
+}
+
+func TestBufferPoolFinalizers(t *testing.T) {
+	pool := newTestPool(t, 4)
+	b := pool.Get(10, 2, false)
+	pool.Put(b)
+	b = nil
+
+	// With its finalizer disabled, the pooled buffer stays open however often the GC
+	// runs, even though only the pool refers to it.
+	for i := 0; i < 3; i++ {
+		runtime.GC()
+	}
+	b = pool.Get(10, 2, false)
+	if b == nil || !b.Valid() {
+		t.Fatal("pooled buffer was freed")
+	}
+	pool.Put(b)
+
+	invalid := pool.Get(0, 3, false)
+	if invalid != nil {
+		t.Error("Get returned a buffer for invalid dimensions")
+	}
+}
+
+// renderPanels draws a frame of ten 40x12 panels into their own buffers and
+// composites them, creating the panel buffers with get and releasing them with put
+// once the frame is done.
+func renderPanels(frame *Buffer, get func(w, h uint32) *Buffer, put func(*Buffer)) {
+	bg := NewRGB(0.1, 0.1, 0.2)
+	var panels [10]*Buffer
+	for i := range panels {
+		panel := get(40, 12)
+		panel.DrawBox(0, 0, 40, 12, BoxOptions{Sides: BorderSides{Top: true, Right: true, Bottom: true, Left: true}, BorderChars: BorderSingle.BoxChars(), Fill: true}, White, bg)
+		panel.DrawText("panel", 2, 1, White, nil, AttrBold)
+		frame.DrawFrameBuffer(int32(i%5)*40, int32(i/5)*12, panel, 0, 0, 40, 12)
+		panels[i] = panel
+	}
+	for _, panel := range panels {
+		put(panel)
+	}
+}
+
+// The benchmarks render a 200x24 frame of ten panels; at 60fps a frame has about
+// 16.7ms.
+
+func BenchmarkPanelsPooled(b *testing.B) {
+	frame := newTestBuffer(b, 200, 24)
+	pool := newTestPool(b, 16)
+	get := func(w, h uint32) *Buffer { return pool.Get(w, h, true) }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		renderPanels(frame, get, pool.Put)
+	}
+}
+
+func BenchmarkPanelsFresh(b *testing.B) {
+	frame := newTestBuffer(b, 200, 24)
+	get := func(w, h uint32) *Buffer {
+		panel := NewBuffer(w, h, true, WidthMethodUnicode)
+		panel.Clear(Transparent)
+		return panel
+	}
+	put := func(panel *Buffer) { panel.Close() }
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		renderPanels(frame, get, put)
+	}
+}
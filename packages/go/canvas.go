@@ -0,0 +1,146 @@
+package opentui
+
+// brailleBase is the code point of the empty braille pattern
+const brailleBase = 0x2800
+
+// brailleDots maps a dot position within a cell, indexed as [y][x], to its braille bit
+var brailleDots = [4][2]uint8{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// Canvas is a drawing surface with 2x4 dots per cell rendered using braille characters.
+// Each cell has a single foreground color; when dots of different colors share a cell,
+// the color of the last dot drawn wins.
+type Canvas struct {
+	width  uint32 // Width in cells
+	height uint32 // Height in cells
+	dots   []uint8
+	colors []RGBA
+}
+
+// NewCanvas creates a new canvas covering the given number of cells.
+// Returns nil if either dimension is zero.
+func NewCanvas(widthCells, heightCells uint32) *Canvas {
+	if widthCells == 0 || heightCells == 0 {
+		return nil
+	}
+	size := widthCells * heightCells
+	return &Canvas{
+		width:  widthCells,
+		height: heightCells,
+		dots:   make([]uint8, size),
+		colors: make([]RGBA, size),
+	}
+}
+
+// Size returns the canvas resolution in dots.
+func (c *Canvas) Size() (int, int) {
+	return int(c.width) * 2, int(c.height) * 4
+}
+
+// SetPoint turns on the dot at (px, py). Points outside the canvas are ignored.
+func (c *Canvas) SetPoint(px, py int, color RGBA) {
+	c.setDot(px, py, color, true)
+}
+
+// UnsetPoint turns off the dot at (px, py). Points outside the canvas are ignored.
+func (c *Canvas) UnsetPoint(px, py int) {
+	c.setDot(px, py, RGBA{}, false)
+}
+
+// Point reports whether the dot at (px, py) is set.
+func (c *Canvas) Point(px, py int) bool {
+	index, bit, ok := c.locate(px, py)
+	return ok && c.dots[index]&bit != 0
+}
+
+// Line draws a line of dots between two points, inclusive of both end points.
+// The line is clipped to the canvas.
+func (c *Canvas) Line(x0, y0, x1, y1 int, color RGBA) {
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	dy = -dy
+
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	errTerm := dx + dy
+	for {
+		c.SetPoint(x0, y0, color)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * errTerm
+		if e2 >= dy {
+			errTerm += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			errTerm += dx
+			y0 += sy
+		}
+	}
+}
+
+// Clear turns off every dot on the canvas.
+func (c *Canvas) Clear() {
+	for i := range c.dots {
+		c.dots[i] = 0
+		c.colors[i] = RGBA{}
+	}
+}
+
+// Flush draws the canvas onto buffer with its top-left cell at (atX, atY).
+// Cells without any dots set are left untouched, as is the background of every cell.
+func (c *Canvas) Flush(buffer *Buffer, atX, atY uint32) error {
+	if buffer == nil || buffer.ptr == nil {
+		return kindError(ErrClosed, "buffer is nil or closed")
+	}
+	for y := uint32(0); y < c.height; y++ {
+		for x := uint32(0); x < c.width; x++ {
+			index := y*c.width + x
+			if c.dots[index] == 0 {
+				continue
+			}
+			buffer.setCell(atX+x, atY+y, brailleBase+rune(c.dots[index]), c.colors[index], nil, 0)
+		}
+	}
+	return nil
+}
+
+// setDot sets or clears a single dot.
+func (c *Canvas) setDot(px, py int, color RGBA, on bool) {
+	index, bit, ok := c.locate(px, py)
+	if !ok {
+		return
+	}
+	if on {
+		c.dots[index] |= bit
+		c.colors[index] = color
+	} else {
+		c.dots[index] &^= bit
+	}
+}
+
+// locate returns the cell index and braille bit of a dot.
+func (c *Canvas) locate(px, py int) (uint32, uint8, bool) {
+	width, height := c.Size()
+	if px < 0 || py < 0 || px >= width || py >= height {
+		return 0, 0, false
+	}
+	index := uint32(py/4)*c.width + uint32(px/2)
+	return index, brailleDots[py%4][px%2], true
+}
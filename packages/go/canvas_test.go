@@ -0,0 +1,56 @@
+package opentui
+
+import (
+	"testing"
+)
+
+func TestCanvasPoints(t *testing.T) {
+	canvas := NewCanvas(2, 1)
+	if canvas == nil {
+		t.Fatal("NewCanvas returned nil")
+	}
+	if w, h := canvas.Size(); w != 4 || h != 4 {
+		t.Errorf("Size = %dx%d, want 4x4", w, h)
+	}
+
+	canvas.SetPoint(0, 0, Red)
+	canvas.SetPoint(1, 3, Green)
+	canvas.SetPoint(-1, 0, Red)
+	canvas.SetPoint(4, 0, Red)
+	canvas.SetPoint(0, 4, Red)
+
+	if !canvas.Point(0, 0) || !canvas.Point(1, 3) || canvas.Point(1, 0) {
+		t.Error("unexpected dot state after SetPoint")
+	}
+	if canvas.dots[0] != 0x01|0x80 || canvas.dots[1] != 0 {
+		t.Errorf("dots = %#x, %#x; want 0x81, 0", canvas.dots[0], canvas.dots[1])
+	}
+	if canvas.colors[0] != Green {
+		t.Errorf("cell color = %+v, want the last color written", canvas.colors[0])
+	}
+
+	canvas.UnsetPoint(0, 0)
+	if canvas.Point(0, 0) {
+		t.Error("UnsetPoint did not clear the dot")
+	}
+	canvas.Clear()
+	if canvas.Point(1, 3) {
+		t.Error("Clear did not clear the canvas")
+	}
+}
+
+func TestCanvasFlush(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 2)
+
+	canvas := NewCanvas(2, 1)
+	canvas.Line(-2, 0, 3, 0, White)
+	canvas.Line(0, 0, 0, 3, White)
+	if err := canvas.Flush(buffer, 1, 1); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	expectRows(t, buffer,
+		"    ",
+		" ⡏⠉ ",
+	)
+}
@@ -0,0 +1,112 @@
+package opentui
+
+import (
+	"bytes"
+	"os"
+	"strings"
+)
+
+// detectedCapabilities holds capabilities detected on the Go side from terminal responses
+type detectedCapabilities struct {
+	sixel               bool
+	iterm2              bool
+	hyperlinks          bool
+	syncOutput          bool
+	clipboard           bool
+	kitty               bool  // kitty keyboard protocol
+	kittyFlags          uint8 // active kitty keyboard flags from the last reply
+	mousePixels         bool  // mode 1016 reported on
+	mousePixelsReported bool  // a DECRPM reply for mode 1016 arrived
+	truecolor           bool
+	xtversion           string // text of the last XTVERSION reply, see IdentifyTerminal
+	secondaryDA         string // parameters of the last DA2 reply
+}
+
+// iterm2Terminals are terminal names that implement the iTerm2 inline image protocol
+var iterm2Terminals = []string{"iTerm2", "iTerm.app", "WezTerm"}
+
+// parseCapabilityResponse updates the detected capabilities from a terminal response.
+// Unrecognized data is ignored, so the full response can be passed through unchanged.
+func (d *detectedCapabilities) parseCapabilityResponse(response []byte) {
+	for _, params := range primaryDeviceAttributes(response) {
+		for _, param := range strings.Split(params, ";") {
+			// Attribute 4 announces sixel graphics, 52 clipboard access
+			switch param {
+			case "4":
+				d.sixel = true
+			case "52":
+				d.clipboard = true
+			}
+		}
+	}
+	if params, ok := secondaryDeviceAttributes(response); ok {
+		d.secondaryDA = params
+	}
+	if name := terminalVersion(response); name != "" {
+		d.xtversion = name
+		d.iterm2 = d.iterm2 || isITerm2Terminal(name)
+		d.hyperlinks = d.hyperlinks || isHyperlinkTerminal(name)
+		d.clipboard = d.clipboard || isClipboardTerminal(name)
+	}
+	if supported, ok := syncOutputReply(response); ok {
+		d.syncOutput = supported
+	}
+	if status, ok := modeReply(response, mousePixelsMode); ok {
+		d.mousePixels, d.mousePixelsReported = status == 1 || status == 3, true
+	}
+	if flags, ok := kittyKeyboardReply(response); ok {
+		d.kitty, d.kittyFlags = true, flags
+	}
+	d.truecolor = d.truecolor || truecolorReply(response)
+}
+
+// detectITerm2Support checks the environment for a terminal that supports
+// the iTerm2 inline image protocol.
+func detectITerm2Support() bool {
+	return isITerm2Terminal(os.Getenv("TERM_PROGRAM")) || isITerm2Terminal(os.Getenv("LC_TERMINAL"))
+}
+
+func isITerm2Terminal(name string) bool {
+	for _, terminal := range iterm2Terminals {
+		if strings.HasPrefix(name, terminal) {
+			return true
+		}
+	}
+	return false
+}
+
+// terminalVersion extracts the name and version from an XTVERSION reply (DCS > | text ST).
+func terminalVersion(response []byte) string {
+	start := bytes.Index(response, []byte("\x1bP>|"))
+	if start < 0 {
+		return ""
+	}
+	response = response[start+4:]
+	end := bytes.Index(response, []byte("\x1b\\"))
+	if end < 0 {
+		return ""
+	}
+	return string(response[:end])
+}
+
+// primaryDeviceAttributes extracts the parameter strings of all DA1 replies
+// (ESC [ ? params c) contained in a response.
+func primaryDeviceAttributes(response []byte) []string {
+	var replies []string
+	for {
+		start := bytes.Index(response, []byte("\x1b[?"))
+		if start < 0 {
+			return replies
+		}
+		response = response[start+3:]
+
+		end := 0
+		for end < len(response) && (response[end] == ';' || (response[end] >= '0' && response[end] <= '9')) {
+			end++
+		}
+		if end < len(response) && response[end] == 'c' {
+			replies = append(replies, string(response[:end]))
+		}
+		response = response[end:]
+	}
+}
@@ -0,0 +1,78 @@
+package opentui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// CaptureOptions controls CaptureTextWithOptions.
+type CaptureOptions struct {
+	TrimTrailingSpace bool // Remove the spaces at the end of each row
+}
+
+// CaptureText returns the characters of the buffer as one string per row, without
+// colors or attributes, for logs and golden files. A double width character appears
+// once, followed by a space for the cell it covers, so every row keeps the width of
+// the buffer. Empty cells and control characters become spaces.
+func (b *Buffer) CaptureText() ([]string, error) {
+	return b.CaptureTextWithOptions(CaptureOptions{})
+}
+
+// CaptureTextWithOptions is like CaptureText with the given options.
+func (b *Buffer) CaptureTextWithOptions(opts CaptureOptions) ([]string, error) {
+	if b.ptr == nil {
+		return nil, closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return nil, err
+	}
+
+	texts := da.cellTexts()
+	rows := make([]string, da.Height)
+	var row strings.Builder
+	for y := uint32(0); y < da.Height; y++ {
+		row.Reset()
+		for x := uint32(0); x < da.Width; x++ {
+			i := y*da.Width + x
+			if isClusterChar(da.Chars[i]) {
+				// Pooled clusters are followed by continuations for the cells they cover
+				if texts[i] == "" {
+					texts[i] = " "
+				}
+				row.WriteString(texts[i])
+				continue
+			}
+			r := rune(da.Chars[i])
+			if r < ' ' || r == 0x7f || !utf8.ValidRune(r) {
+				r = ' '
+			}
+			row.WriteRune(r)
+			if runeWidth(r) == 2 && x+1 < da.Width {
+				row.WriteByte(' ')
+				x++
+			}
+		}
+		text := row.String()
+		if opts.TrimTrailingSpace {
+			text = strings.TrimRight(text, " ")
+		}
+		rows[y] = text
+	}
+	return rows, nil
+}
+
+// CaptureText returns the frame shown on the terminal, the current buffer, as plain
+// text like Buffer.CaptureText.
+func (r *Renderer) CaptureText() ([]string, error) {
+	return r.CaptureTextWithOptions(CaptureOptions{})
+}
+
+// CaptureTextWithOptions is like CaptureText with the given options.
+func (r *Renderer) CaptureTextWithOptions(opts CaptureOptions) ([]string, error) {
+	current, err := r.GetCurrentBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return current.CaptureTextWithOptions(opts)
+}
@@ -0,0 +1,61 @@
+package opentui
+
+import "testing"
+
+func TestCaptureText(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 2)
+
+	buffer.DrawText("ab", 0, 0, Red, nil, AttrBold)
+	buffer.SetCell(3, 0, Cell{Char: '日', Foreground: White, Background: Black})
+	buffer.SetCell(2, 0, Cell{Char: 0, Foreground: White, Background: Black})
+	buffer.SetCellGrapheme(5, 0, "👍🏽", White, Black, 0)
+	buffer.SetCell(1, 1, Cell{Char: '\t', Foreground: White, Background: Black})
+
+	rows, err := buffer.CaptureText()
+	if err != nil {
+		t.Fatalf("CaptureText failed: %v", err)
+	}
+	want := []string{"ab 日 👍🏽  ", "        "}
+	if len(rows) != len(want) || rows[0] != want[0] || rows[1] != want[1] {
+		t.Errorf("CaptureText = %q, want %q", rows, want)
+	}
+
+	rows, _ = buffer.CaptureTextWithOptions(CaptureOptions{TrimTrailingSpace: true})
+	if rows[0] != "ab 日 👍🏽" || rows[1] != "" {
+		t.Errorf("trimmed capture = %q", rows)
+	}
+}
+
+func TestCaptureTextPooledClusters(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 1)
+	buffer.DrawText("é漢x", 0, 0, White, nil, 0)
+
+	rows, err := buffer.CaptureText()
+	if err != nil {
+		t.Fatalf("CaptureText failed: %v", err)
+	}
+	if len(rows) != 1 || rows[0] != "é漢 x  " {
+		t.Errorf("CaptureText = %q, want %q", rows, "é漢 x  ")
+	}
+}
+
+func TestRendererCaptureText(t *testing.T) {
+	renderer := NewRenderer(4, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	next, err := renderer.GetNextBuffer()
+	if err != nil {
+		t.Fatalf("GetNextBuffer failed: %v", err)
+	}
+	next.Clear(Black)
+	next.DrawText("hi", 0, 0, White, nil, 0)
+	captureStdout(t, func() { renderer.Render(false) })
+
+	rows, err := renderer.CaptureTextWithOptions(CaptureOptions{TrimTrailingSpace: true})
+	if err != nil || len(rows) != 1 || rows[0] != "hi" {
+		t.Errorf("CaptureText = %q, %v", rows, err)
+	}
+}
@@ -0,0 +1,246 @@
+package opentui
+
+/*
+#include "opentui.h"
+#include "color.h"
+*/
+import "C"
+import (
+	"runtime"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// Native cells hold the code point of a narrow character, or a reference to a
+// grapheme cluster in the pool of the native library. Text drawing stores every
+// cluster but a single printable ASCII character that way, so é, 漢 and emoji
+// sequences alike. The first cell of a cluster holds its pool id, and the cells a wide
+// cluster covers after it hold continuations, which tell how far the cluster extends
+// on either side.
+const (
+	charFlagMask         = 0xC0000000
+	charFlagGrapheme     = 0x80000000 // First cell of a cluster
+	charFlagContinuation = 0xC0000000 // Further cell of a wide cluster
+	charExtentMask       = 3
+	charRightExtentShift = 28 // Cells of the cluster right of the cell
+	charLeftExtentShift  = 26 // Cells of the cluster left of the cell
+)
+
+// isGraphemeChar reports whether c is the first cell of a pooled cluster.
+func isGraphemeChar(c uint32) bool {
+	return c&charFlagMask == charFlagGrapheme
+}
+
+// isContinuationChar reports whether c is a further cell of a wide pooled cluster.
+func isContinuationChar(c uint32) bool {
+	return c&charFlagMask == charFlagContinuation
+}
+
+// isClusterChar reports whether c is any cell of a pooled cluster.
+func isClusterChar(c uint32) bool {
+	return c&charFlagGrapheme != 0
+}
+
+// charRightExtent returns the number of cells of the cluster of c right of its cell.
+func charRightExtent(c uint32) uint32 {
+	if !isClusterChar(c) {
+		return 0
+	}
+	return c >> charRightExtentShift & charExtentMask
+}
+
+// charLeftExtent returns the number of cells of the cluster of c left of its cell.
+func charLeftExtent(c uint32) uint32 {
+	if !isContinuationChar(c) {
+		return 0
+	}
+	return c >> charLeftExtentShift & charExtentMask
+}
+
+// storedCharWidth returns the number of cells the cluster of a stored character
+// covers, like the native library counts them.
+func storedCharWidth(c uint32) uint32 {
+	switch {
+	case isContinuationChar(c):
+		return charLeftExtent(c) + 1 + charRightExtent(c)
+	case isGraphemeChar(c):
+		return charRightExtent(c) + 1
+	}
+	return 1
+}
+
+// setWideCell writes a double width character at (x, y), covering the next cell too.
+func (da *DirectAccess) setWideCell(x, y uint32, cell Cell) {
+	da.drawCluster(x, y, string(cell.Char), cell.Foreground, cell.Background, cell.Attributes)
+}
+
+// drawCluster writes a grapheme cluster at (x, y) with exactly the given colors. Only
+// text drawing puts clusters into the grapheme pool, so it's drawn as text with opaque
+// colors, and the cells it covers get the colors afterwards.
+func (da *DirectAccess) drawCluster(x, y uint32, cluster string, fg, bg RGBA, attributes Attributes) {
+	textPtr, textLen := stringToC(cluster)
+	if textPtr == nil || x >= da.Width || y >= da.Height {
+		return
+	}
+	da.clearCovered(x, y, uint32(stringWidth(cluster)))
+	opaqueFg, opaqueBg := fg, bg
+	opaqueFg.A, opaqueBg.A = 1, 1
+	C.bufferDrawTextValue(da.ptr, textPtr, textLen, C.uint32_t(x), C.uint32_t(y),
+		opaqueFg.toC(), opaqueBg.toC(), true, C.uint16_t(attributes))
+	runtime.KeepAlive(cluster)
+	i := y*da.Width + x
+	for col := x; col < da.Width; col++ {
+		if col > x && !isContinuationChar(da.Chars[i]) {
+			break
+		}
+		da.Foreground[i] = fg
+		da.Background[i] = bg
+		da.Attributes[i] = attributes
+		i++
+	}
+}
+
+// clearCovered blanks the clusters under the cells a cluster width cells wide written
+// at (x, y) covers right of its first cell. The native layer only clears a cluster
+// overwritten at the cell written, not the ones under the rest of the new cluster.
+func (da *DirectAccess) clearCovered(x, y, width uint32) {
+	i := y*da.Width + x
+	for k := uint32(1); k < width && x+k < da.Width; k++ {
+		if isClusterChar(da.Chars[i+k]) {
+			C.bufferSetCellValue(da.ptr, C.uint32_t(x+k), C.uint32_t(y), ' ',
+				da.Foreground[i+k].toC(), da.Background[i+k].toC(), 0)
+		}
+	}
+}
+
+// cellSnapshot is a cell read for copying, with the text of the cluster it holds
+type cellSnapshot struct {
+	char   uint32
+	text   string
+	fg, bg RGBA
+	attrs  Attributes
+}
+
+// copyCellRect copies the width by height cells from (srcX, srcY) in src to (dstX, dstY)
+// in dst, rectangles inside their buffers. src and dst may be the same buffer. Pool
+// references only hold while the buffer holding them keeps them, so clusters are
+// drawn anew; clusters cut by the edges of the rectangle become spaces.
+func copyCellRect(dst, src *DirectAccess, srcX, srcY, dstX, dstY, width, height uint32) {
+	texts := src.cellTexts()
+	cells := make([]cellSnapshot, 0, width*height)
+	for row := srcY; row < srcY+height; row++ {
+		for col := srcX; col < srcX+width; col++ {
+			i := row*src.Width + col
+			cells = append(cells, cellSnapshot{src.Chars[i], texts[i], src.Foreground[i], src.Background[i], src.Attributes[i]})
+		}
+	}
+	for row := uint32(0); row < height; row++ {
+		covered := uint32(0) // Cells left to cover by the last cluster drawn
+		for col := uint32(0); col < width; col++ {
+			s := cells[row*width+col]
+			x, y := dstX+col, dstY+row
+			i := y*dst.Width + x
+			inCluster := covered > 0
+			covered = max(covered, 1) - 1
+			switch {
+			case isGraphemeChar(s.char) && col+storedCharWidth(s.char) <= width:
+				dst.drawCluster(x, y, s.text, s.fg, s.bg, s.attrs)
+				covered = storedCharWidth(s.char) - 1
+			case isContinuationChar(s.char) && inCluster && isContinuationChar(dst.Chars[i]):
+				dst.Foreground[i], dst.Background[i], dst.Attributes[i] = s.fg, s.bg, s.attrs
+			case isClusterChar(s.char):
+				dst.SetCell(x, y, Cell{Char: ' ', Foreground: s.fg, Background: s.bg, Attributes: s.attrs})
+			case isClusterChar(dst.Chars[i]):
+				// The native layer blanks the rest of the cluster overwritten
+				dst.SetCell(x, y, Cell{Char: rune(s.char), Foreground: s.fg, Background: s.bg, Attributes: s.attrs})
+			default:
+				dst.Chars[i], dst.Foreground[i], dst.Background[i], dst.Attributes[i] = s.char, s.fg, s.bg, s.attrs
+			}
+		}
+	}
+}
+
+// hasClusters reports whether any cell holds a pooled cluster.
+func (da *DirectAccess) hasClusters() bool {
+	for _, c := range da.Chars {
+		if isClusterChar(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// graphemeText returns the cluster a grapheme start refers to in the native pool, or a
+// space if it refers to none, the way the native renderer writes it.
+func (da *DirectAccess) graphemeText(c uint32) string {
+	if da.ptr == nil {
+		return " "
+	}
+	var small [32]byte
+	out := small[:]
+	for {
+		n := C.bufferGetGrapheme(da.ptr, C.uint32_t(c), (*C.uint8_t)(unsafe.Pointer(&out[0])), C.size_t(len(out)))
+		if n == 0 {
+			return " "
+		}
+		if int(n) <= len(out) {
+			return string(out[:n])
+		}
+		out = make([]byte, n)
+	}
+}
+
+// cellTexts returns the text every cell shows: its character, the cluster it starts,
+// or "" for the further cells of a wide cluster.
+func (da *DirectAccess) cellTexts() []string {
+	texts := make([]string, len(da.Chars))
+	var clusters map[uint32]string // The same wide characters tend to repeat
+	for i, c := range da.Chars {
+		switch {
+		case isContinuationChar(c):
+		case isGraphemeChar(c):
+			text, ok := clusters[c]
+			if !ok {
+				text = da.graphemeText(c)
+				if clusters == nil {
+					clusters = make(map[uint32]string)
+				}
+				clusters[c] = text
+			}
+			texts[i] = text
+		default:
+			texts[i] = charText(c)
+		}
+	}
+	return texts
+}
+
+// cellText returns the text the cell at index i shows, like cellTexts.
+func (da *DirectAccess) cellText(i uint32) string {
+	c := da.Chars[i]
+	switch {
+	case isContinuationChar(c):
+		return ""
+	case isGraphemeChar(c):
+		return da.graphemeText(c)
+	}
+	return charText(c)
+}
+
+// charText returns the text of a narrow character stored in a cell. Values that aren't
+// code points show as a space, like the native renderer writes them.
+func charText(c uint32) string {
+	if !utf8.ValidRune(rune(c)) {
+		return " "
+	}
+	return string(rune(c))
+}
+
+// cellChar returns the character of a cell with the given text: the first code point
+// of its cluster, or a space for the further cells of a wide cluster.
+func cellChar(text string) rune {
+	if text == "" {
+		return ' '
+	}
+	return leadingRune(text)
+}
@@ -0,0 +1,267 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ClipboardTarget selects the clipboard written by CopyToClipboard
+type ClipboardTarget uint8
+
+const (
+	ClipboardSystem  ClipboardTarget = iota // The system clipboard
+	ClipboardPrimary                        // The primary selection, pasted with the middle button on X11
+)
+
+// maxClipboardPayload is the longest base64 payload accepted by OSC 52. Terminals
+// that limit the sequence, such as hterm and older xterm builds, stop around 74994
+// bytes, and larger payloads are silently dropped.
+const maxClipboardPayload = 74994
+
+var (
+	// ErrClipboardUnsupported is returned by CopyToClipboard when the terminal is not
+	// known to accept OSC 52, see SetClipboardSupport.
+	ErrClipboardUnsupported = newError("terminal does not support writing the clipboard")
+	// ErrClipboardTooLarge is returned by CopyToClipboard for text that exceeds what
+	// terminals accept in a single OSC 52 sequence.
+	ErrClipboardTooLarge = newError("text is too large for the terminal clipboard")
+	// ErrClipboardDenied is returned by ReadClipboard when the terminal answers without
+	// the clipboard contents, because the user or its settings refused the read.
+	ErrClipboardDenied = newError("terminal denied reading the clipboard")
+)
+
+// maxClipboardReply bounds the OSC 52 reply collected across responses, so a reply
+// that never ends doesn't keep growing.
+const maxClipboardReply = 8 << 20
+
+// clipboardRead collects OSC 52 replies for ReadClipboard. Replies arrive through
+// ProcessCapabilityResponse, usually from the input goroutine, and large ones are
+// split across several responses with other input around them.
+type clipboardRead struct {
+	mu      sync.Mutex
+	partial []byte // reply received so far, nil outside a reply
+	waiters []chan clipboardReply
+}
+
+type clipboardReply struct {
+	text string
+	err  error
+}
+
+// CopyToClipboard copies text to the clipboard of the user's machine with OSC 52,
+// which also works over SSH. Nothing is written if the terminal is not known to
+// support OSC 52 or the text is too large, so applications can tell the user that
+// copying failed. Terminals don't confirm the copy, and some ask the user first.
+func (r *Renderer) CopyToClipboard(text string, target ClipboardTarget) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if !r.clipboardSupported() {
+		return ErrClipboardUnsupported
+	}
+	sequence, err := clipboardSequence(text, target)
+	if err != nil {
+		return err
+	}
+	if r.tmuxPassthrough {
+		sequence = tmuxPassthrough(sequence)
+	}
+	_, err = r.terminal().Write([]byte(sequence))
+	return err
+}
+
+// ReadClipboard asks the terminal for the contents of the system clipboard with an
+// OSC 52 query and waits for the reply until ctx is done. Unless the renderer reads
+// replies from its own Input, the reply is read by the application like any other
+// terminal input and must be passed to ProcessCapabilityResponse from another
+// goroutine while this call waits; keystrokes before and after it are left alone.
+// Returns ErrClipboardDenied when the terminal answers without the contents, which
+// most terminals also do for an empty clipboard, and ErrQueryTimeout when ctx
+// expires, since many terminals ignore the query.
+func (r *Renderer) ReadClipboard(ctx context.Context) (string, error) {
+	if r.ptr == nil {
+		return "", closedError("renderer")
+	}
+	if r.clipboard != nil && !*r.clipboard {
+		return "", ErrClipboardUnsupported
+	}
+
+	reply := make(chan clipboardReply, 1)
+	r.clipboardRead.mu.Lock()
+	r.clipboardRead.waiters = append(r.clipboardRead.waiters, reply)
+	r.clipboardRead.mu.Unlock()
+	defer r.clipboardRead.removeWaiter(reply)
+
+	query := "\x1b]52;c;?\x1b\\"
+	if r.tmuxPassthrough {
+		query = tmuxPassthrough(query)
+	}
+	if _, err := r.terminal().Write([]byte(query)); err != nil {
+		return "", err
+	}
+	r.readReplies()
+	select {
+	case reply := <-reply:
+		return reply.text, reply.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", ErrQueryTimeout
+		}
+		return "", ctx.Err()
+	}
+}
+
+// SetClipboardSupport overrides the detection of OSC 52 support, for terminals that
+// need it enabled in their settings such as xterm and iTerm2.
+func (r *Renderer) SetClipboardSupport(supported bool) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.clipboard = &supported
+	return nil
+}
+
+// SetTmuxPassthrough sets whether CopyToClipboard wraps its sequence in a tmux
+// passthrough (DCS tmux; ... ST), so it reaches the terminal running tmux even when
+// tmux does not handle OSC 52 itself. Requires "set -g allow-passthrough on" in tmux.
+// Enabling it also makes CopyToClipboard skip the support detection, which only sees
+// tmux. It is off by default.
+func (r *Renderer) SetTmuxPassthrough(enabled bool) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.tmuxPassthrough = enabled
+	return nil
+}
+
+// clipboardSupported reports whether OSC 52 is set as supported or detected.
+func (r *Renderer) clipboardSupported() bool {
+	if r.clipboard != nil {
+		return *r.clipboard
+	}
+	return r.tmuxPassthrough || r.detected.clipboard || detectClipboardSupport()
+}
+
+// clipboardSequence builds the OSC 52 sequence setting target to text.
+func clipboardSequence(text string, target ClipboardTarget) (string, error) {
+	var selection string
+	switch target {
+	case ClipboardSystem:
+		selection = "c"
+	case ClipboardPrimary:
+		selection = "p"
+	default:
+		return "", newError("invalid clipboard target")
+	}
+	payload := base64.StdEncoding.EncodeToString([]byte(text))
+	if len(payload) > maxClipboardPayload {
+		return "", ErrClipboardTooLarge
+	}
+	return "\x1b]52;" + selection + ";" + payload + "\x1b\\", nil
+}
+
+// tmuxPassthrough wraps a sequence for tmux to pass to the outer terminal. Escapes
+// inside the passthrough are doubled.
+func tmuxPassthrough(sequence string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// receive scans a response for OSC 52 replies (ESC ] 52 ; selection ; data
+// terminated by BEL or ST) and hands them to the pending reads. A reply cut off at
+// the end of the response is kept and completed by the following responses.
+func (q *clipboardRead) receive(response []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiters) == 0 {
+		q.partial = nil
+		return
+	}
+	for len(response) > 0 {
+		if q.partial == nil {
+			start := bytes.Index(response, []byte("\x1b]52;"))
+			if start < 0 {
+				return
+			}
+			response = response[start+5:]
+			q.partial = []byte{}
+		}
+		end := bytes.IndexAny(response, "\x07\x1b")
+		if end < 0 {
+			if len(q.partial)+len(response) > maxClipboardReply {
+				q.partial = nil
+				return
+			}
+			q.partial = append(q.partial, response...)
+			return
+		}
+		q.partial = append(q.partial, response[:end]...)
+		q.report(clipboardReplyText(q.partial))
+		q.partial = nil
+		response = response[end:]
+	}
+}
+
+// report wakes up the pending reads with a reply.
+func (q *clipboardRead) report(reply clipboardReply) {
+	for _, waiter := range q.waiters {
+		select {
+		case waiter <- reply:
+		default:
+		}
+	}
+	q.waiters = nil
+}
+
+func (q *clipboardRead) removeWaiter(reply chan clipboardReply) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, waiter := range q.waiters {
+		if waiter == reply {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// clipboardReplyText decodes the body of an OSC 52 reply, "selection;base64".
+func clipboardReplyText(body []byte) clipboardReply {
+	_, data, ok := bytes.Cut(body, []byte(";"))
+	if !ok || len(data) == 0 {
+		return clipboardReply{err: ErrClipboardDenied}
+	}
+	text, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return clipboardReply{err: ErrClipboardDenied}
+	}
+	return clipboardReply{text: string(text)}
+}
+
+// clipboardTerminals are terminal names known to accept OSC 52 without configuration
+var clipboardTerminals = []string{"kitty", "WezTerm", "foot", "alacritty", "ghostty", "contour", "rio", "Tabby"}
+
+// detectClipboardSupport checks the environment for a terminal that accepts OSC 52.
+func detectClipboardSupport() bool {
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("WT_SESSION") != "" {
+		return true
+	}
+	term := os.Getenv("TERM")
+	if strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "alacritty") {
+		return true
+	}
+	return isClipboardTerminal(os.Getenv("TERM_PROGRAM"))
+}
+
+func isClipboardTerminal(name string) bool {
+	name = strings.ToLower(name)
+	for _, terminal := range clipboardTerminals {
+		if strings.HasPrefix(name, strings.ToLower(terminal)) {
+			return true
+		}
+	}
+	return false
+}
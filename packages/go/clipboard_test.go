@@ -0,0 +1,181 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClipboardSequence(t *testing.T) {
+	got, err := clipboardSequence("hi", ClipboardSystem)
+	if err != nil || got != "\x1b]52;c;aGk=\x1b\\" {
+		t.Errorf("clipboardSequence = %q, %v", got, err)
+	}
+	got, err = clipboardSequence("", ClipboardPrimary)
+	if err != nil || got != "\x1b]52;p;\x1b\\" {
+		t.Errorf("clipboardSequence primary = %q, %v", got, err)
+	}
+	if _, err := clipboardSequence("x", ClipboardTarget(7)); err == nil {
+		t.Error("invalid target should fail")
+	}
+
+	// 3 bytes encode to 4, so this is the largest text that fits
+	limit := maxClipboardPayload / 4 * 3
+	if _, err := clipboardSequence(strings.Repeat("a", limit), ClipboardSystem); err != nil {
+		t.Errorf("text at the limit failed: %v", err)
+	}
+	if _, err := clipboardSequence(strings.Repeat("a", limit+1), ClipboardSystem); !errors.Is(err, ErrClipboardTooLarge) {
+		t.Errorf("text over the limit = %v, want ErrClipboardTooLarge", err)
+	}
+
+	if got := tmuxPassthrough("\x1b]52;c;aGk=\x1b\\"); got != "\x1bPtmux;\x1b\x1b]52;c;aGk=\x1b\x1b\\\x1b\\" {
+		t.Errorf("tmuxPassthrough = %q", got)
+	}
+}
+
+func TestDetectClipboardSupport(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, true},
+		{map[string]string{"TERM_PROGRAM": "ghostty"}, true},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, false},
+		{map[string]string{"TERM_PROGRAM": "tmux"}, false},
+		{map[string]string{"TERM": "xterm-kitty"}, true},
+		{map[string]string{"TERM": "foot"}, true},
+		{map[string]string{"TERM": "xterm-256color"}, false},
+		{map[string]string{"WT_SESSION": "abc"}, true},
+	}
+
+	for _, tt := range tests {
+		for _, name := range []string{"KITTY_WINDOW_ID", "WT_SESSION", "TERM_PROGRAM", "TERM"} {
+			t.Setenv(name, tt.env[name])
+		}
+		if got := detectClipboardSupport(); got != tt.want {
+			t.Errorf("detectClipboardSupport(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+
+	var d detectedCapabilities
+	d.parseCapabilityResponse([]byte("\x1b[?62;4;52c"))
+	if !d.clipboard {
+		t.Error("DA1 attribute 52 should enable the clipboard")
+	}
+	d = detectedCapabilities{}
+	d.parseCapabilityResponse([]byte("\x1bP>|WezTerm 20240203\x1b\\"))
+	if !d.clipboard {
+		t.Error("WezTerm should support the clipboard")
+	}
+}
+
+func TestCopyToClipboard(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	for _, name := range []string{"KITTY_WINDOW_ID", "WT_SESSION", "TERM_PROGRAM", "TERM"} {
+		t.Setenv(name, "")
+	}
+	if err := renderer.CopyToClipboard("hi", ClipboardSystem); !errors.Is(err, ErrClipboardUnsupported) {
+		t.Errorf("CopyToClipboard on an unknown terminal = %v, want ErrClipboardUnsupported", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("unsupported copy wrote %q", out.String())
+	}
+
+	renderer.SetClipboardSupport(true)
+	if caps, _ := renderer.GetTerminalCapabilities(); !caps.SupportsClipboard {
+		t.Error("SupportsClipboard should follow SetClipboardSupport")
+	}
+	if err := renderer.CopyToClipboard("hi", ClipboardPrimary); err != nil {
+		t.Fatalf("CopyToClipboard failed: %v", err)
+	}
+	if got := out.String(); got != "\x1b]52;p;aGk=\x1b\\" {
+		t.Errorf("CopyToClipboard wrote %q", got)
+	}
+
+	out.Reset()
+	renderer.SetTmuxPassthrough(true)
+	renderer.CopyToClipboard("hi", ClipboardSystem)
+	if got := out.String(); !strings.HasPrefix(got, "\x1bPtmux;\x1b\x1b]52;c;") {
+		t.Errorf("passthrough copy wrote %q", got)
+	}
+}
+
+func TestClipboardReadReceive(t *testing.T) {
+	var q clipboardRead
+	wait := func() chan clipboardReply {
+		reply := make(chan clipboardReply, 1)
+		q.waiters = append(q.waiters, reply)
+		return reply
+	}
+
+	// A reply split across responses, with keystrokes around it
+	reply := wait()
+	q.receive([]byte("ab\x1b]52;c;aGVsbG8g"))
+	q.receive([]byte("d29ybGQ="))
+	select {
+	case <-reply:
+		t.Fatal("reply reported before it ended")
+	default:
+	}
+	q.receive([]byte("\x1b\\cd"))
+	if got := <-reply; got.err != nil || got.text != "hello world" {
+		t.Errorf("split reply = %+v", got)
+	}
+	if q.partial != nil || len(q.waiters) != 0 {
+		t.Error("reply should be consumed")
+	}
+
+	for _, denied := range []string{"\x1b]52;c;\x07", "\x1b]52;c;?\x1b\\", "\x1b]52;c;!!\x07"} {
+		reply := wait()
+		q.receive([]byte(denied))
+		if got := <-reply; !errors.Is(got.err, ErrClipboardDenied) {
+			t.Errorf("reply %q = %+v, want ErrClipboardDenied", denied, got)
+		}
+	}
+
+	// Without a pending read, replies are ignored
+	q.receive([]byte("\x1b]52;c;aGk="))
+	if q.partial != nil {
+		t.Error("reply collected without a pending read")
+	}
+}
+
+func TestReadClipboard(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	go writer.Write([]byte("\x1b]52;c;cGFzdGU=\x07"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	text, err := renderer.ReadClipboard(ctx)
+	if err != nil || text != "paste" {
+		t.Errorf("ReadClipboard = %q, %v", text, err)
+	}
+	if out.String() != "\x1b]52;c;?\x1b\\" {
+		t.Errorf("query written as %q", out.String())
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := renderer.ReadClipboard(ctx); !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("unanswered read returned %v, want ErrQueryTimeout", err)
+	}
+}
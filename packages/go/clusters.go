@@ -0,0 +1,62 @@
+package opentui
+
+// SetCellGrapheme sets a single cell to a whole grapheme cluster such as an emoji ZWJ
+// sequence, a flag or a character followed by a variation selector, with alpha blending
+// like SetCellWithAlphaBlending. A double width cluster also covers the next cell.
+// Overwriting either half of a double width cluster later clears the other half.
+//
+// The cluster is drawn as text, so the native cell refers to it in the grapheme pool
+// like the clusters DrawText writes, and GetCluster returns it.
+func (b *Buffer) SetCellGrapheme(x, y uint32, cluster string, fg, bg RGBA, attrs Attributes) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	g := Graphemes(cluster)
+	if !g.Next() {
+		return newError("empty grapheme cluster")
+	}
+	width := max(g.Width(), 1)
+	if g.Next() {
+		return newError("text is more than one grapheme cluster")
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return err
+	}
+	if x >= da.Width || y >= da.Height {
+		return boundsError("coordinates")
+	}
+	if x+uint32(width) > da.Width {
+		return newError("double width cluster does not fit in the last column")
+	}
+
+	char := leadingRune(cluster)
+	switch {
+	case len(string(char)) == len(cluster):
+		b.setCellBlended(x, y, char, fg, bg, attrs) // A single code point
+	case b.blendMode == BlendLinear:
+		i := y*da.Width + x
+		dest := Cell{Foreground: da.Foreground[i], Background: da.Background[i]}
+		cell := blendCellLinear(Cell{Char: char, Foreground: fg, Background: bg, Attributes: attrs}, dest)
+		da.drawCluster(x, y, cluster, cell.Foreground, cell.Background, attrs)
+	default:
+		b.drawText(cluster, x, y, fg, &bg, attrs)
+	}
+	return nil
+}
+
+// GetCluster returns the grapheme cluster shown in a cell: the cluster it holds, ""
+// for the trailing cell of a double width cluster, or otherwise its character.
+func (b *Buffer) GetCluster(x, y uint32) (string, error) {
+	if b.ptr == nil {
+		return "", closedError("buffer")
+	}
+	da, err := b.directAccess()
+	if err != nil {
+		return "", err
+	}
+	if x >= da.Width || y >= da.Height {
+		return "", boundsError("coordinates")
+	}
+	return da.cellText(y*da.Width + x), nil
+}
@@ -0,0 +1,96 @@
+package opentui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSetCellGrapheme(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 1)
+
+	heart := "❤️"
+	family := "👨‍👩‍👧"
+	if err := buffer.SetCellGrapheme(0, 0, heart, White, Black, 0); err != nil {
+		t.Fatalf("SetCellGrapheme failed: %v", err)
+	}
+	if err := buffer.SetCellGrapheme(3, 0, family, White, Black, 0); err != nil {
+		t.Fatalf("SetCellGrapheme failed: %v", err)
+	}
+
+	for x, want := range []string{heart, "", " ", family, "", " "} {
+		got, err := buffer.GetCluster(uint32(x), 0)
+		if err != nil {
+			t.Fatalf("GetCluster failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("GetCluster(%d, 0) = %q, want %q", x, got, want)
+		}
+	}
+
+	// Overwriting either half of a double width cluster blanks the other half
+	buffer.SetCell(4, 0, Cell{Char: 'x', Foreground: White, Background: Black})
+	buffer.DrawText("y", 0, 0, White, nil, 0)
+	expectRows(t, buffer, "y   x ")
+	for x := uint32(0); x < 6; x++ {
+		if kind, _ := buffer.GetCellKind(x, 0); kind != CellNarrow {
+			t.Errorf("cell %d should no longer be part of a wide cluster", x)
+		}
+	}
+	if got, _ := buffer.GetCluster(3, 0); got != " " {
+		t.Errorf("orphaned half should be blank, got %q", got)
+	}
+
+	// A single narrow code point is stored in the cell itself
+	buffer.SetCellGrapheme(2, 0, "a", White, Black, 0)
+	if da, _ := buffer.GetDirectAccess(); da.Chars[2] != 'a' {
+		t.Errorf("plain character stored as %#x", da.Chars[2])
+	}
+}
+
+func TestSetCellGraphemeErrors(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 1)
+
+	tests := []struct {
+		x       uint32
+		cluster string
+	}{
+		{0, ""},
+		{0, "ab"},
+		{4, "a"},
+		{3, "👍"},
+	}
+	for _, tt := range tests {
+		if err := buffer.SetCellGrapheme(tt.x, 0, tt.cluster, White, Black, 0); err == nil {
+			t.Errorf("SetCellGrapheme(%d, 0, %q) should fail", tt.x, tt.cluster)
+		}
+	}
+}
+
+func TestRenderGraphemeClusters(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 6, Height: 1, Output: &out})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	flag := "🇳🇱"
+	next, _ := renderer.GetNextBuffer()
+	next.Clear(Black)
+	next.SetCellGrapheme(2, 0, flag, White, Black, 0)
+	renderer.Render(false)
+	if !strings.Contains(out.String(), flag) {
+		t.Errorf("cluster missing from the frame %q", out.String())
+	}
+
+	// Replacing the cluster rewrites its cells
+	out.Reset()
+	next, _ = renderer.GetNextBuffer()
+	next.Clear(Black)
+	next.DrawText("ab", 2, 0, White, nil, 0)
+	renderer.Render(false)
+	if frame := out.String(); strings.Contains(frame, flag) || !strings.Contains(frame, "ab") {
+		t.Errorf("unexpected frame %q", frame)
+	}
+}
@@ -0,0 +1,198 @@
+package opentui
+
+import (
+	"math"
+)
+
+// srgbToLinear converts an sRGB encoded channel value to linear light.
+func srgbToLinear(c float32) float32 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return float32(math.Pow((float64(c)+0.055)/1.055, 2.4))
+}
+
+// linearToSRGB converts a linear light channel value to sRGB encoding.
+func linearToSRGB(c float32) float32 {
+	if c <= 0.0031308 {
+		return c * 12.92
+	}
+	return float32(1.055*math.Pow(float64(c), 1/2.4) - 0.055)
+}
+
+// relativeLuminance returns the WCAG relative luminance of the color, from 0 for
+// black to 1 for white. Alpha is ignored.
+func relativeLuminance(c RGBA) float32 {
+	return 0.2126*srgbToLinear(clamp01(c.R)) + 0.7152*srgbToLinear(clamp01(c.G)) + 0.0722*srgbToLinear(clamp01(c.B))
+}
+
+// Lerp interpolates between two colors channel by channel, alpha included, straight
+// on the sRGB values. t is clamped to [0, 1]. This is cheap and right for fading
+// alpha, but midpoints between different colors come out darker than they look
+// halfway; use Mix or a Gradient for those.
+func Lerp(a, b RGBA, t float32) RGBA {
+	t = clamp01(t)
+	return RGBA{
+		R: a.R + (b.R-a.R)*t,
+		G: a.G + (b.G-a.G)*t,
+		B: a.B + (b.B-a.B)*t,
+		A: a.A + (b.A-a.A)*t,
+	}
+}
+
+// Mix blends weight of color b into color a, interpolating in linear light so the
+// result looks like a mixture of the two. weight is clamped to [0, 1]; alpha is
+// interpolated directly.
+func Mix(a, b RGBA, weight float32) RGBA {
+	return lerpLinear(a, b, weight)
+}
+
+// lerpLinear interpolates between two colors in linear light.
+// t is clamped to [0, 1]; alpha is interpolated directly.
+func lerpLinear(a, b RGBA, t float32) RGBA {
+	t = clamp01(t)
+	mix := func(x, y float32) float32 {
+		lx, ly := srgbToLinear(x), srgbToLinear(y)
+		return linearToSRGB(lx + (ly-lx)*t)
+	}
+	return RGBA{
+		R: mix(a.R, b.R),
+		G: mix(a.G, b.G),
+		B: mix(a.B, b.B),
+		A: a.A + (b.A-a.A)*t,
+	}
+}
+
+func clamp01(v float32) float32 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// NewHSL creates an opaque color from hue in degrees, saturation and lightness in
+// [0, 1]. Hues outside [0, 360) wrap around.
+func NewHSL(h, s, l float32) RGBA {
+	s, l = clamp01(s), clamp01(l)
+	c := (1 - abs32(2*l-1)) * s
+	return hueToRGB(h, c, l-c/2)
+}
+
+// NewHSV creates an opaque color from hue in degrees, saturation and value in
+// [0, 1]. Hues outside [0, 360) wrap around.
+func NewHSV(h, s, v float32) RGBA {
+	s, v = clamp01(s), clamp01(v)
+	c := v * s
+	return hueToRGB(h, c, v-c)
+}
+
+// ToHSL returns the hue in degrees [0, 360), saturation and lightness of the color.
+// Alpha is ignored.
+func (c RGBA) ToHSL() (h, s, l float32) {
+	hi, lo := max(c.R, c.G, c.B), min(c.R, c.G, c.B)
+	l = (hi + lo) / 2
+	if d := hi - lo; d > 0 {
+		s = d / (1 - abs32(2*l-1))
+	}
+	return c.hue(), clamp01(s), l
+}
+
+// ToHSV returns the hue in degrees [0, 360), saturation and value of the color.
+// Alpha is ignored.
+func (c RGBA) ToHSV() (h, s, v float32) {
+	v = max(c.R, c.G, c.B)
+	if v > 0 {
+		s = (v - min(c.R, c.G, c.B)) / v
+	}
+	return c.hue(), s, v
+}
+
+// Lighten returns the color with its HSL lightness raised by amount, keeping hue,
+// saturation and alpha.
+func (c RGBA) Lighten(amount float32) RGBA {
+	h, s, l := c.ToHSL()
+	return c.withHSL(h, s, l+amount)
+}
+
+// Darken returns the color with its HSL lightness lowered by amount, keeping hue,
+// saturation and alpha.
+func (c RGBA) Darken(amount float32) RGBA {
+	return c.Lighten(-amount)
+}
+
+// Saturate returns the color with its HSL saturation raised by amount, or lowered
+// for a negative amount, keeping hue, lightness and alpha.
+func (c RGBA) Saturate(amount float32) RGBA {
+	h, s, l := c.ToHSL()
+	return c.withHSL(h, s+amount, l)
+}
+
+// withHSL returns the color given by h, s and l with the alpha of c.
+func (c RGBA) withHSL(h, s, l float32) RGBA {
+	out := NewHSL(h, s, l)
+	out.A = c.A
+	return out
+}
+
+// hue returns the hue of the color in degrees [0, 360), 0 for grays.
+func (c RGBA) hue() float32 {
+	hi, lo := max(c.R, c.G, c.B), min(c.R, c.G, c.B)
+	d := hi - lo
+	if d == 0 {
+		return 0
+	}
+	var h float32
+	switch hi {
+	case c.R:
+		h = (c.G - c.B) / d
+	case c.G:
+		h = (c.B-c.R)/d + 2
+	default:
+		h = (c.R-c.G)/d + 4
+	}
+	return wrapHue(h * 60)
+}
+
+// hueToRGB returns the opaque color of hue h with chroma c, offset by m on every channel.
+func hueToRGB(h, c, m float32) RGBA {
+	h = wrapHue(h) / 60
+	x := c * (1 - abs32(float32(math.Mod(float64(h), 2))-1))
+	var r, g, b float32
+	switch int(h) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	return NewRGB(clamp01(r+m), clamp01(g+m), clamp01(b+m))
+}
+
+// wrapHue maps a hue in degrees into [0, 360).
+func wrapHue(h float32) float32 {
+	h = float32(math.Mod(float64(h), 360))
+	if h < 0 {
+		h += 360
+	}
+	if h >= 360 {
+		h = 0
+	}
+	return h
+}
+
+func abs32(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,74 @@
+#ifndef OPENTUI_GO_COLOR_H
+#define OPENTUI_GO_COLOR_H
+
+#include "opentui.h"
+
+// Color carries an RGBA color by value. The native functions take colors as pointers
+// to four floats; these wrappers take Colors instead and pass pointers to their own
+// parameters, which live until the native function returns, so Go never hands out
+// pointers to its own memory for colors. Optional values come with a flag, and are
+// passed as NULL when it's false.
+typedef struct {
+	float rgba[4];
+} Color;
+
+static inline void setBackgroundColorValue(CliRenderer* renderer, Color color) {
+	setBackgroundColor(renderer, color.rgba);
+}
+
+static inline void setCursorColorValue(CliRenderer* renderer, Color color) {
+	setCursorColor(renderer, color.rgba);
+}
+
+static inline void bufferClearValue(OptimizedBuffer* buffer, Color bg) {
+	bufferClear(buffer, bg.rgba);
+}
+
+static inline void bufferDrawTextValue(OptimizedBuffer* buffer, const uint8_t* text, size_t textLen, uint32_t x, uint32_t y,
+		Color fg, Color bg, bool hasBg, uint16_t attributes) {
+	bufferDrawText(buffer, text, textLen, x, y, fg.rgba, hasBg ? bg.rgba : NULL, attributes);
+}
+
+static inline void bufferSetCellValue(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t char_code,
+		Color fg, Color bg, uint16_t attributes) {
+	bufferSetCell(buffer, x, y, char_code, fg.rgba, bg.rgba, attributes);
+}
+
+static inline void bufferSetCellWithAlphaBlendingValue(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t char_code,
+		Color fg, Color bg, uint16_t attributes) {
+	bufferSetCellWithAlphaBlending(buffer, x, y, char_code, fg.rgba, bg.rgba, attributes);
+}
+
+static inline void bufferFillRectValue(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t width, uint32_t height, Color bg) {
+	bufferFillRect(buffer, x, y, width, height, bg.rgba);
+}
+
+static inline void bufferDrawBoxValue(OptimizedBuffer* buffer, int32_t x, int32_t y, uint32_t width, uint32_t height,
+		const uint32_t* borderChars, uint32_t packedOptions, Color borderColor, Color backgroundColor) {
+	bufferDrawBox(buffer, x, y, width, height, borderChars, packedOptions, borderColor.rgba, backgroundColor.rgba, NULL, 0);
+}
+
+static inline void textBufferSetCellValue(TextBuffer* textBuffer, uint32_t index, uint32_t char_code, Color fg, Color bg, uint16_t attr) {
+	textBufferSetCell(textBuffer, index, char_code, fg.rgba, bg.rgba, attr);
+}
+
+static inline void textBufferSetSelectionValue(TextBuffer* textBuffer, uint32_t start, uint32_t end,
+		Color bg, bool hasBg, Color fg, bool hasFg) {
+	textBufferSetSelection(textBuffer, start, end, hasBg ? bg.rgba : NULL, hasFg ? fg.rgba : NULL);
+}
+
+static inline void textBufferSetDefaultFgValue(TextBuffer* textBuffer, Color fg, bool hasFg) {
+	textBufferSetDefaultFg(textBuffer, hasFg ? fg.rgba : NULL);
+}
+
+static inline void textBufferSetDefaultBgValue(TextBuffer* textBuffer, Color bg, bool hasBg) {
+	textBufferSetDefaultBg(textBuffer, hasBg ? bg.rgba : NULL);
+}
+
+static inline uint32_t textBufferWriteChunkValue(TextBuffer* textBuffer, const uint8_t* textBytes, uint32_t textLen,
+		Color fg, bool hasFg, Color bg, bool hasBg, uint16_t attr, bool hasAttr) {
+	return textBufferWriteChunk(textBuffer, textBytes, textLen, hasFg ? fg.rgba : NULL, hasBg ? bg.rgba : NULL,
+		hasAttr ? &attr : NULL);
+}
+
+#endif
@@ -0,0 +1,73 @@
+package opentui
+
+import (
+	"math"
+	"testing"
+)
+
+// closeColor reports whether two colors are within 1/255 on every channel.
+func closeColor(a, b RGBA) bool {
+	const eps = 1.0 / 255
+	return math.Abs(float64(a.R-b.R)) <= eps && math.Abs(float64(a.G-b.G)) <= eps &&
+		math.Abs(float64(a.B-b.B)) <= eps && math.Abs(float64(a.A-b.A)) <= eps
+}
+
+func TestHSLConstructors(t *testing.T) {
+	tests := []struct {
+		name string
+		got  RGBA
+		want RGBA
+	}{
+		{"hsl red", NewHSL(0, 1, 0.5), Red},
+		{"hsl green", NewHSL(120, 1, 0.5), Green},
+		{"hsl blue wraps", NewHSL(240+360, 1, 0.5), Blue},
+		{"hsl negative hue", NewHSL(-60, 1, 0.5), Magenta},
+		{"hsl gray", NewHSL(77, 0, 0.5), NewRGB(0.5, 0.5, 0.5)},
+		{"hsv yellow", NewHSV(60, 1, 1), Yellow},
+		{"hsv cyan", NewHSV(180, 1, 1), Cyan},
+		{"hsv dark", NewHSV(0, 0.5, 0.5), NewRGB(0.5, 0.25, 0.25)},
+	}
+	for _, tt := range tests {
+		if !closeColor(tt.got, tt.want) {
+			t.Errorf("%s = %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestHSLRoundTrip(t *testing.T) {
+	for r := 0; r <= 255; r += 15 {
+		for g := 0; g <= 255; g += 15 {
+			for b := 0; b <= 255; b += 15 {
+				c := NewRGB(float32(r)/255, float32(g)/255, float32(b)/255)
+				if h, s, l := c.ToHSL(); !closeColor(NewHSL(h, s, l), c) {
+					t.Fatalf("HSL round trip of %+v gave %+v", c, NewHSL(h, s, l))
+				}
+				if h, s, v := c.ToHSV(); !closeColor(NewHSV(h, s, v), c) {
+					t.Fatalf("HSV round trip of %+v gave %+v", c, NewHSV(h, s, v))
+				}
+			}
+		}
+	}
+}
+
+func TestLightenDarkenSaturate(t *testing.T) {
+	base := NewRGBA(0.2, 0.4, 0.8, 0.5)
+	h, s, l := base.ToHSL()
+
+	lighter := base.Lighten(0.15)
+	if lh, ls, ll := lighter.ToHSL(); math.Abs(float64(lh-h)) > 0.5 || math.Abs(float64(ls-s)) > 0.01 || math.Abs(float64(ll-l-0.15)) > 0.01 {
+		t.Errorf("Lighten changed hue or saturation: %v %v %v -> %v %v %v", h, s, l, lh, ls, ll)
+	}
+	if lighter.A != base.A {
+		t.Error("Lighten should keep alpha")
+	}
+	if _, _, dl := base.Darken(0.2).ToHSL(); math.Abs(float64(dl-(l-0.2))) > 0.01 {
+		t.Errorf("Darken(0.2) lightness = %v, want %v", dl, l-0.2)
+	}
+	if got := White.Lighten(0.5); !closeColor(got, White) {
+		t.Errorf("Lighten should clamp, got %+v", got)
+	}
+	if got := base.Saturate(-1); got.R != got.G || got.G != got.B {
+		t.Errorf("Saturate(-1) should give a gray, got %+v", got)
+	}
+}
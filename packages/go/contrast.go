@@ -0,0 +1,47 @@
+package opentui
+
+// MinContrastRatio is the WCAG AA contrast ratio for normal text, which
+// ReadableForeground and the drawing helpers aim for.
+const MinContrastRatio = 4.5
+
+// ContrastRatio returns the WCAG contrast ratio between two colors, from 1 for
+// identical luminance to 21 for black on white. The order of a and b does not
+// matter and alpha is ignored.
+func ContrastRatio(a, b RGBA) float64 {
+	la, lb := float64(relativeLuminance(a)), float64(relativeLuminance(b))
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ReadableForeground returns Black or White, whichever contrasts more with bg.
+// One of them always reaches MinContrastRatio.
+func ReadableForeground(bg RGBA) RGBA {
+	return ReadableForegroundFrom(bg, Black, White)
+}
+
+// ReadableForegroundFrom returns the first candidate reaching MinContrastRatio against
+// bg, or the candidate with the highest ratio when none does. Without candidates it
+// behaves like ReadableForeground.
+func ReadableForegroundFrom(bg RGBA, candidates ...RGBA) RGBA {
+	if len(candidates) == 0 {
+		return ReadableForeground(bg)
+	}
+	best, bestRatio := candidates[0], 0.0
+	for _, c := range candidates {
+		ratio := ContrastRatio(c, bg)
+		if ratio >= MinContrastRatio {
+			return c
+		}
+		if ratio > bestRatio {
+			best, bestRatio = c, ratio
+		}
+	}
+	return best
+}
+
+// readableOn returns fg when it is readable on bg and black or white otherwise.
+func readableOn(fg, bg RGBA) RGBA {
+	return ReadableForegroundFrom(bg, fg, Black, White)
+}
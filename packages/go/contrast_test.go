@@ -0,0 +1,55 @@
+package opentui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContrastRatio(t *testing.T) {
+	tests := []struct {
+		a, b RGBA
+		want float64
+	}{
+		{Black, White, 21},
+		{White, Black, 21},
+		{Gray, Gray, 1},
+		{rgb8(0x76, 0x76, 0x76), White, 4.54},
+		{rgb8(0x77, 0x77, 0x77), White, 4.48},
+		{Red, White, 4.00},
+		{Blue, White, 8.59},
+		{rgb8(0x00, 0x66, 0xcc), rgb8(0xff, 0xff, 0xff), 5.57},
+	}
+	for _, tt := range tests {
+		if got := ContrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.005 {
+			t.Errorf("ContrastRatio(%+v, %+v) = %.3f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestReadableForeground(t *testing.T) {
+	tests := []struct {
+		bg, want RGBA
+	}{
+		{White, Black},
+		{Black, White},
+		{Yellow, Black},
+		{Blue, White},
+		{Gray, Black},
+	}
+	for _, tt := range tests {
+		if got := ReadableForeground(tt.bg); got != tt.want {
+			t.Errorf("ReadableForeground(%+v) = %+v, want %+v", tt.bg, got, tt.want)
+		}
+	}
+
+	// The first candidate that is readable wins, otherwise the best one
+	if got := ReadableForegroundFrom(Black, Blue, Yellow, White); got != Yellow {
+		t.Errorf("ReadableForegroundFrom picked %+v, want Yellow", got)
+	}
+	if got := ReadableForegroundFrom(Gray, Red, Blue); got != Blue {
+		t.Errorf("ReadableForegroundFrom without a readable candidate picked %+v, want Blue", got)
+	}
+	if got := DarkTheme.ReadableForeground(LightTheme.Surface); got != DarkTheme.Surface {
+		t.Errorf("DarkTheme.ReadableForeground on a light background = %+v", got)
+	}
+}
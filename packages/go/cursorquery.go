@@ -0,0 +1,122 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+)
+
+// cursorQuery tracks the pending cursor position reports (DSR 6). Replies arrive
+// through ProcessCapabilityResponse, usually from the input goroutine.
+type cursorQuery struct {
+	mu      sync.Mutex
+	waiters []chan cursorPosition
+}
+
+type cursorPosition struct {
+	row, col uint32
+}
+
+// QueryCursorPosition asks the terminal where the cursor is with DSR 6 (CSI 6n) and
+// waits for the reply until ctx is done. Row and column are 1-based, like
+// SetCursorPosition. Unless the renderer reads replies from its own Input, the reply
+// is read by the application like any other terminal input and must be passed to
+// ProcessCapabilityResponse from another goroutine while this call waits; see
+// CursorQueryPending. Returns ErrQueryTimeout when ctx expires.
+func (r *Renderer) QueryCursorPosition(ctx context.Context) (row, col uint32, err error) {
+	if r.ptr == nil {
+		return 0, 0, closedError("renderer")
+	}
+
+	reply := make(chan cursorPosition, 1)
+	r.cursor.mu.Lock()
+	r.cursor.waiters = append(r.cursor.waiters, reply)
+	r.cursor.mu.Unlock()
+	defer r.cursor.removeWaiter(reply)
+
+	if _, err := r.terminal().Write([]byte("\x1b[6n")); err != nil {
+		return 0, 0, err
+	}
+	r.readReplies()
+	select {
+	case position := <-reply:
+		return position.row, position.col, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, 0, ErrQueryTimeout
+		}
+		return 0, 0, ctx.Err()
+	}
+}
+
+// CursorQueryPending reports whether QueryCursorPosition is waiting for a reply. The
+// reply, CSI row ; col R, reads like F3 with modifiers, so input parsers should pass
+// such sequences to ProcessCapabilityResponse instead of reporting a key while this
+// returns true. Replies are only picked out of responses while a query is pending.
+func (r *Renderer) CursorQueryPending() bool {
+	r.cursor.mu.Lock()
+	defer r.cursor.mu.Unlock()
+	return len(r.cursor.waiters) > 0
+}
+
+// receive hands the first cursor position report in a response to the pending
+// queries, if there are any.
+func (q *cursorQuery) receive(response []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiters) == 0 {
+		return
+	}
+	position, ok := cursorPositionReply(response)
+	if !ok {
+		return
+	}
+	for _, waiter := range q.waiters {
+		select {
+		case waiter <- position:
+		default:
+		}
+	}
+	q.waiters = nil
+}
+
+func (q *cursorQuery) removeWaiter(reply chan cursorPosition) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, waiter := range q.waiters {
+		if waiter == reply {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// cursorPositionReply extracts the first cursor position report (ESC [ row ; col R)
+// contained in a response.
+func cursorPositionReply(response []byte) (cursorPosition, bool) {
+	for {
+		start := bytes.Index(response, []byte("\x1b["))
+		if start < 0 {
+			return cursorPosition{}, false
+		}
+		response = response[start+2:]
+		end := 0
+		for end < len(response) && (response[end] >= '0' && response[end] <= '9' || response[end] == ';') {
+			end++
+		}
+		if end == len(response) || response[end] != 'R' {
+			continue
+		}
+		rowText, colText, ok := bytes.Cut(response[:end], []byte(";"))
+		if !ok {
+			continue
+		}
+		row, rowErr := strconv.ParseUint(string(rowText), 10, 32)
+		col, colErr := strconv.ParseUint(string(colText), 10, 32)
+		if rowErr == nil && colErr == nil && row > 0 && col > 0 {
+			return cursorPosition{uint32(row), uint32(col)}, true
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCursorPositionReply(t *testing.T) {
+	tests := []struct {
+		response string
+		want     cursorPosition
+		ok       bool
+	}{
+		{"\x1b[12;40R", cursorPosition{12, 40}, true},
+		{"a\x1b[A\x1b[3;1Rb", cursorPosition{3, 1}, true},
+		{"\x1b[?62;4c\x1b[1;5R", cursorPosition{1, 5}, true},
+		{"\x1b[12R", cursorPosition{}, false},
+		{"\x1b[0;0R", cursorPosition{}, false},
+		{"\x1b[12;40", cursorPosition{}, false},
+		{"x", cursorPosition{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := cursorPositionReply([]byte(tt.response))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("cursorPositionReply(%q) = %v, %v, want %v, %v", tt.response, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestQueryCursorPosition(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if renderer.CursorQueryPending() {
+		t.Error("no query should be pending")
+	}
+	// A report without a pending query is a key, not a reply
+	renderer.ProcessCapabilityResponse([]byte("\x1b[1;2R"))
+
+	go func() {
+		for !renderer.CursorQueryPending() {
+			time.Sleep(time.Millisecond)
+		}
+		writer.Write([]byte("\x1b[7;3R"))
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	row, col, err := renderer.QueryCursorPosition(ctx)
+	if err != nil || row != 7 || col != 3 {
+		t.Errorf("QueryCursorPosition = %d, %d, %v, want 7, 3", row, col, err)
+	}
+	if out.String() != "\x1b[6n" {
+		t.Errorf("query written as %q", out.String())
+	}
+	if renderer.CursorQueryPending() {
+		t.Error("query should be done")
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, _, err := renderer.QueryCursorPosition(ctx); !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("unanswered query returned %v, want ErrQueryTimeout", err)
+	}
+}
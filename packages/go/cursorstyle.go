@@ -0,0 +1,92 @@
+package opentui
+
+/*
+#include "opentui.h"
+#include <stdlib.h>
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// CursorShape is a cursor style as numbered by DECSCUSR (CSI n q), which sets the
+// shape of the cursor and whether it blinks together.
+type CursorShape uint8
+
+const (
+	CursorBlinkingBlock     CursorShape = iota + 1 // CSI 1 q
+	CursorSteadyBlock                              // CSI 2 q
+	CursorBlinkingUnderline                        // CSI 3 q
+	CursorSteadyUnderline                          // CSI 4 q
+	CursorBlinkingBar                              // CSI 5 q
+	CursorSteadyBar                                // CSI 6 q
+)
+
+// resetCursorStyle restores the terminal's default cursor (DECSCUSR 0)
+const resetCursorStyle = "\x1b[0 q"
+
+// nativeCursorStyles names the cursor styles of the native renderer, by the shape of
+// the blinking and steady pairs of CursorShape.
+var nativeCursorStyles = [...]string{"block", "underline", "line"}
+
+// Shape returns the CursorShape of the style, blinking or not. It fails for styles
+// other than CursorBlock, CursorUnderline and CursorBar.
+func (style CursorStyle) Shape(blinking bool) (CursorShape, error) {
+	var shape CursorShape
+	switch style {
+	case CursorBlock:
+		shape = CursorBlinkingBlock
+	case CursorUnderline:
+		shape = CursorBlinkingUnderline
+	case CursorBar:
+		shape = CursorBlinkingBar
+	default:
+		return 0, newError(fmt.Sprintf("unknown cursor style %q", style))
+	}
+	if !blinking {
+		shape++
+	}
+	return shape, nil
+}
+
+// SetCursorShape sets the cursor style, writing CSI shape q to the terminal. Frames
+// that show the cursor keep it. The terminal's default cursor is restored by
+// ResetCursorStyle, and on Close.
+func (r *Renderer) SetCursorShape(shape CursorShape) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if shape < CursorBlinkingBlock || shape > CursorSteadyBar {
+		return newError(fmt.Sprintf("unknown cursor shape %d", shape))
+	}
+	r.setNativeCursorStyle(nativeCursorStyles[(shape-1)/2], shape%2 == 1)
+	if _, err := fmt.Fprintf(r.terminal(), "\x1b[%d q", shape); err != nil {
+		return err
+	}
+	r.cursorStyled = true
+	return nil
+}
+
+// ResetCursorStyle restores the terminal's default cursor style with CSI 0 q, undoing
+// SetCursorShape and SetCursorStyle. Frames that show the cursor draw it as a steady
+// block again, as before any style was set.
+func (r *Renderer) ResetCursorStyle() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.setNativeCursorStyle("block", false)
+	if _, err := r.terminal().Write([]byte(resetCursorStyle)); err != nil {
+		return err
+	}
+	r.cursorStyled = false
+	return nil
+}
+
+// setNativeCursorStyle sets the cursor style the native renderer writes with every
+// frame that shows the cursor.
+func (r *Renderer) setNativeCursorStyle(style string, blinking bool) {
+	cStyle := C.CString(style)
+	defer C.free(unsafe.Pointer(cStyle))
+	C.setCursorStyle(r.ptr, (*C.uint8_t)(unsafe.Pointer(cStyle)), C.size_t(len(style)), C.bool(blinking))
+}
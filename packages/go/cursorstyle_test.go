@@ -0,0 +1,82 @@
+package opentui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSetCursorShape(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+
+	for _, tc := range []struct {
+		style    CursorStyle
+		blinking bool
+		want     string
+	}{
+		{CursorBlock, true, "\x1b[1 q"},
+		{CursorBlock, false, "\x1b[2 q"},
+		{CursorUnderline, true, "\x1b[3 q"},
+		{CursorUnderline, false, "\x1b[4 q"},
+		{CursorBar, true, "\x1b[5 q"},
+		{CursorBar, false, "\x1b[6 q"},
+	} {
+		out.Reset()
+		if err := renderer.SetCursorStyle(tc.style, tc.blinking); err != nil {
+			t.Fatalf("SetCursorStyle(%q, %v) failed: %v", tc.style, tc.blinking, err)
+		}
+		if out.String() != tc.want {
+			t.Errorf("SetCursorStyle(%q, %v) wrote %q, want %q", tc.style, tc.blinking, out.String(), tc.want)
+		}
+	}
+
+	out.Reset()
+	renderer.SetCursorShape(CursorSteadyBar)
+	if out.String() != "\x1b[6 q" {
+		t.Errorf("SetCursorShape(CursorSteadyBar) wrote %q", out.String())
+	}
+
+	out.Reset()
+	if err := renderer.SetCursorStyle("blok", true); err == nil {
+		t.Error("an unknown style should fail")
+	}
+	if err := renderer.SetCursorShape(7); err == nil {
+		t.Error("an unknown shape should fail")
+	}
+	if err := renderer.SetCursorShape(0); err == nil {
+		t.Error("shape 0 should fail, ResetCursorStyle restores the default")
+	}
+	if out.Len() != 0 {
+		t.Errorf("invalid styles wrote %q", out.String())
+	}
+
+	renderer.ResetCursorStyle()
+	if out.String() != "\x1b[0 q" {
+		t.Errorf("ResetCursorStyle wrote %q", out.String())
+	}
+	out.Reset()
+	renderer.Close()
+	if bytes.Contains(out.Bytes(), []byte("\x1b[0 q")) {
+		t.Error("Close should not reset a cursor style that was already reset")
+	}
+	if err := renderer.SetCursorShape(CursorSteadyBlock); err == nil {
+		t.Error("SetCursorShape after Close should fail")
+	}
+}
+
+func TestCloseResetsCursorStyle(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	renderer.SetCursorShape(CursorBlinkingUnderline)
+	out.Reset()
+	renderer.Close()
+	if !bytes.Contains(out.Bytes(), []byte("\x1b[0 q")) {
+		t.Errorf("Close wrote %q, want the default cursor style restored", out.String())
+	}
+}
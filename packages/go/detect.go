@@ -0,0 +1,106 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+)
+
+// capabilityProbes are the queries sent by DetectCapabilities. Terminals answer
+// queries in order and every terminal answers DA1, so its reply, which comes last,
+// ends the detection.
+const capabilityProbes = "\x1b[>0q" + // XTVERSION: name and version
+	"\x1b[?u" + // kitty keyboard protocol flags
+	"\x1b[?2026$p" + // DECRQM for synchronized output
+	"\x1b[38;2;1;2;3m\x1bP$qm\x1b\\\x1b[0m" + // DECRQSS reading back a 24-bit foreground
+	"\x1b[c" // DA1
+
+// capabilityDetection tracks the pending DetectCapabilities calls. Replies arrive
+// through ProcessCapabilityResponse, usually from the input goroutine.
+type capabilityDetection struct {
+	mu      sync.Mutex
+	waiters []chan struct{}
+}
+
+// DetectCapabilities probes the terminal and returns the capabilities once it has
+// answered, or when ctx expires. The probes ask for the terminal name (XTVERSION), the
+// kitty keyboard protocol, synchronized output and 24-bit color, followed by DA1,
+// whose reply completes the detection. Probes left unanswered at the deadline count
+// as unsupported, and the capabilities known so far are returned without an error;
+// only a canceled ctx fails. Unless the renderer reads replies from its own Input,
+// the replies are read by the application like any other terminal input and must be
+// passed to ProcessCapabilityResponse from another goroutine while this call waits.
+func (r *Renderer) DetectCapabilities(ctx context.Context) (*Capabilities, error) {
+	if r.ptr == nil {
+		return nil, closedError("renderer")
+	}
+
+	done := make(chan struct{}, 1)
+	r.detection.mu.Lock()
+	r.detection.waiters = append(r.detection.waiters, done)
+	r.detection.mu.Unlock()
+	defer r.detection.removeWaiter(done)
+
+	if _, err := r.terminal().Write([]byte(capabilityProbes)); err != nil {
+		return nil, err
+	}
+	r.readReplies()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ctx.Err()
+		}
+	}
+	return r.GetTerminalCapabilities()
+}
+
+// receive completes the pending detections if a response contains a DA1 reply.
+func (d *capabilityDetection) receive(response []byte) {
+	if len(primaryDeviceAttributes(response)) == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, waiter := range d.waiters {
+		select {
+		case waiter <- struct{}{}:
+		default:
+		}
+	}
+	d.waiters = nil
+}
+
+func (d *capabilityDetection) removeWaiter(done chan struct{}) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i, waiter := range d.waiters {
+		if waiter == done {
+			d.waiters = append(d.waiters[:i], d.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// truecolorReply reports whether a response contains a DECRQSS reply for SGR
+// (DCS 1 $ r params m ST) that kept the 24-bit foreground set by the probe. Terminals
+// without 24-bit color report the closest palette color instead.
+func truecolorReply(response []byte) bool {
+	for {
+		start := bytes.Index(response, []byte("\x1bP1$r"))
+		if start < 0 {
+			return false
+		}
+		response = response[start+5:]
+		end := bytes.Index(response, []byte("\x1b\\"))
+		if end < 0 {
+			return false
+		}
+		params := response[:end]
+		if bytes.Contains(params, []byte("38;2;1;2;3")) || bytes.Contains(params, []byte("38:2:1:2:3")) || bytes.Contains(params, []byte("38:2::1:2:3")) {
+			return true
+		}
+		response = response[end:]
+	}
+}
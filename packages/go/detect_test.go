@@ -0,0 +1,63 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestCapabilityReplies(t *testing.T) {
+	if !truecolorReply([]byte("\x1bP1$r0;38:2::1:2:3m\x1b\\")) || !truecolorReply([]byte("\x1bP1$r38;2;1;2;3m\x1b\\")) {
+		t.Error("24-bit SGR reply not recognized")
+	}
+	if truecolorReply([]byte("\x1bP1$r0;38;5;16m\x1b\\")) || truecolorReply([]byte("\x1bP0$r\x1b\\")) {
+		t.Error("palette SGR reply taken for 24-bit color")
+	}
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	go func() {
+		writer.Write([]byte("\x1bP>|WezTerm 20240203\x1b\\\x1b[?1u"))
+		writer.Write([]byte("\x1b[?2026;2$y\x1bP1$r38:2::1:2:3m\x1b\\"))
+		writer.Write([]byte("\x1b[?62;4;52c"))
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	caps, err := renderer.DetectCapabilities(ctx)
+	if err != nil {
+		t.Fatalf("DetectCapabilities failed: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("detection should end with the DA1 reply")
+	}
+	if !caps.SupportsKittyKeyboard || !caps.SupportsTruecolor || !caps.SupportsSyncOutput || !caps.SupportsSixel || !caps.SupportsClipboard {
+		t.Errorf("capabilities not detected: %+v", caps)
+	}
+	if out.String() != capabilityProbes {
+		t.Errorf("probes written as %q", out.String())
+	}
+
+	// A terminal that doesn't answer leaves the capabilities as they are
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := renderer.DetectCapabilities(ctx); err != nil {
+		t.Errorf("unanswered detection returned %v", err)
+	}
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := renderer.DetectCapabilities(canceled); err == nil {
+		t.Error("a canceled detection should fail")
+	}
+}
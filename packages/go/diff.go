@@ -0,0 +1,138 @@
+package opentui
+
+// Diff returns the cells that differ between this buffer and other, in row-major order.
+// A cell has changed if its text, colors or attributes differ.
+// Applying the result with ApplyDiff turns a copy of this buffer into other.
+func (b *Buffer) Diff(other *Buffer) ([]CellChange, error) {
+	return b.DiffWithEpsilon(other, 0)
+}
+
+// DiffWithEpsilon is like Diff, but treats colors as equal when no channel
+// differs by more than epsilon.
+func (b *Buffer) DiffWithEpsilon(other *Buffer, epsilon float32) ([]CellChange, error) {
+	if b.ptr == nil {
+		return nil, closedError("buffer")
+	}
+	if other == nil || other.ptr == nil {
+		return nil, kindError(ErrClosed, "other buffer is nil or closed")
+	}
+
+	from, err := b.GetDirectAccess()
+	if err != nil {
+		return nil, err
+	}
+	to, err := other.GetDirectAccess()
+	if err != nil {
+		return nil, err
+	}
+	if from.Width != to.Width || from.Height != to.Height {
+		return nil, newError("buffer dimensions differ")
+	}
+
+	// Cells holding the same cluster may refer to it by different ids, so clusters are
+	// compared by their text, resolved once a cell holds one
+	var fromTexts, toTexts []string
+	text := func(da *DirectAccess, texts *[]string, i int) string {
+		if *texts == nil {
+			*texts = da.cellTexts()
+		}
+		return (*texts)[i]
+	}
+	sameChar := func(i int) bool {
+		if !isClusterChar(from.Chars[i]) && !isClusterChar(to.Chars[i]) {
+			return from.Chars[i] == to.Chars[i]
+		}
+		return text(from, &fromTexts, i) == text(to, &toTexts, i)
+	}
+
+	var changes []CellChange
+	for i := range from.Chars {
+		if sameChar(i) &&
+			from.Attributes[i] == to.Attributes[i] &&
+			colorsEqual(from.Foreground[i], to.Foreground[i], epsilon) &&
+			colorsEqual(from.Background[i], to.Background[i], epsilon) {
+			continue
+		}
+		x, y := uint32(i)%from.Width, uint32(i)/from.Width
+		fromText, toText := text(from, &fromTexts, i), text(to, &toTexts, i)
+		changes = append(changes, CellChange{
+			X: x,
+			Y: y,
+			From: Cell{
+				Char:       cellChar(fromText),
+				Foreground: from.Foreground[i],
+				Background: from.Background[i],
+				Attributes: from.Attributes[i],
+			},
+			To: Cell{
+				Char:       cellChar(toText),
+				Foreground: to.Foreground[i],
+				Background: to.Background[i],
+				Attributes: to.Attributes[i],
+			},
+			FromText: fromText,
+			ToText:   toText,
+		})
+	}
+	return changes, nil
+}
+
+// ApplyDiff writes the To cell of every change, e.g. to mirror a remote buffer. A
+// ToText holding a whole cluster is written instead of the character of To, and the
+// trailing cell of a double width cluster, with an empty ToText, only takes its colors
+// and attributes. Nothing is written if any change lies outside the buffer.
+func (b *Buffer) ApplyDiff(changes []CellChange) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if change.X >= da.Width || change.Y >= da.Height {
+			return boundsError("cell change")
+		}
+	}
+	for _, change := range changes {
+		da.applyChange(change)
+	}
+	return nil
+}
+
+// applyChange writes the To cell of a change in the buffer.
+func (da *DirectAccess) applyChange(change CellChange) {
+	i := change.Y*da.Width + change.X
+	to := change.To
+	switch {
+	case change.ToText == "" && to.Char == ' ' && isContinuationChar(da.Chars[i]):
+		// Covered by the cluster of an earlier change
+		da.Foreground[i], da.Background[i], da.Attributes[i] = to.Foreground, to.Background, to.Attributes
+	case change.ToText != "" && change.ToText != string(to.Char):
+		// The cells the cluster covers keep their colors: they're either unchanged, so
+		// already right, or changed later on
+		n := min(uint32(max(stringWidth(change.ToText), 1)), da.Width-change.X)
+		fg := append([]RGBA(nil), da.Foreground[i+1:i+n]...)
+		bg := append([]RGBA(nil), da.Background[i+1:i+n]...)
+		attrs := append([]Attributes(nil), da.Attributes[i+1:i+n]...)
+		da.drawCluster(change.X, change.Y, change.ToText, to.Foreground, to.Background, to.Attributes)
+		for k := uint32(1); k < n && isContinuationChar(da.Chars[i+k]); k++ {
+			da.Foreground[i+k], da.Background[i+k], da.Attributes[i+k] = fg[k-1], bg[k-1], attrs[k-1]
+		}
+	default:
+		da.SetCell(change.X, change.Y, to)
+	}
+}
+
+// colorsEqual compares two colors channel by channel with a tolerance.
+func colorsEqual(a, b RGBA, epsilon float32) bool {
+	if epsilon <= 0 {
+		return a == b
+	}
+	within := func(x, y float32) bool {
+		d := x - y
+		return d <= epsilon && d >= -epsilon
+	}
+	return within(a.R, b.R) && within(a.G, b.G) && within(a.B, b.B) && within(a.A, b.A)
+}
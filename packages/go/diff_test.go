@@ -0,0 +1,115 @@
+package opentui
+
+import "testing"
+
+func TestBufferDiff(t *testing.T) {
+	a := newTestBuffer(t, 4, 2)
+	b := newTestBuffer(t, 4, 2)
+
+	changes, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("equal buffers have %d changes", len(changes))
+	}
+
+	b.DrawText("x", 1, 0, White, nil, 0)
+	b.SetCellWithAlphaBlending(3, 1, ' ', White, Red, 0)
+	changes, err = a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("got %d changes, want 2: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.X != 1 || c.Y != 0 || c.From.Char != ' ' || c.To.Char != 'x' {
+		t.Errorf("first change = %+v", c)
+	}
+	if c := changes[1]; c.X != 3 || c.Y != 1 || c.From.Background != Black || c.To.Background != Red {
+		t.Errorf("second change = %+v", c)
+	}
+
+	// Mirroring the changes makes the buffers equal
+	if err := a.ApplyDiff(changes); err != nil {
+		t.Fatalf("ApplyDiff failed: %v", err)
+	}
+	if changes, _ := a.Diff(b); len(changes) != 0 {
+		t.Errorf("%d changes left after ApplyDiff", len(changes))
+	}
+}
+
+func TestBufferDiffClusters(t *testing.T) {
+	a := newTestBuffer(t, 6, 1)
+	b := newTestBuffer(t, 6, 1)
+	a.DrawText("é", 0, 0, White, nil, 0)
+	b.DrawText("é漢", 0, 0, White, nil, 0)
+	b.SetCellGrapheme(4, 0, "👍🏽", Red, Blue, 0)
+
+	// The same cluster drawn into each buffer is no change
+	changes, err := a.Diff(b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(changes) != 4 {
+		t.Fatalf("got %d changes, want 4: %+v", len(changes), changes)
+	}
+	if c := changes[0]; c.X != 1 || c.To.Char != '漢' || c.ToText != "漢" || c.FromText != " " {
+		t.Errorf("first change = %+v", c)
+	}
+	if c := changes[1]; c.X != 2 || c.To.Char != ' ' || c.ToText != "" {
+		t.Errorf("second change = %+v", c)
+	}
+
+	if err := a.ApplyDiff(changes); err != nil {
+		t.Fatalf("ApplyDiff failed: %v", err)
+	}
+	if changes, _ := a.Diff(b); len(changes) != 0 {
+		t.Errorf("%d changes left after ApplyDiff: %+v", len(changes), changes)
+	}
+	if got, _ := a.GetCluster(4, 0); got != "👍🏽" {
+		t.Errorf("GetCluster(4, 0) = %q after ApplyDiff", got)
+	}
+}
+
+func TestBufferDiffEpsilon(t *testing.T) {
+	a := newTestBuffer(t, 1, 1)
+	b := newTestBuffer(t, 1, 1)
+	b.Clear(RGBA{R: 0.001, A: 1})
+
+	if changes, _ := a.Diff(b); len(changes) != 1 {
+		t.Errorf("exact diff found %d changes, want 1", len(changes))
+	}
+	if changes, _ := a.DiffWithEpsilon(b, 0.01); len(changes) != 0 {
+		t.Errorf("diff with epsilon found %d changes, want 0", len(changes))
+	}
+}
+
+func TestBufferDiffErrors(t *testing.T) {
+	a := newTestBuffer(t, 2, 2)
+	b := newTestBuffer(t, 3, 2)
+	if _, err := a.Diff(b); err == nil {
+		t.Error("expected error for different dimensions")
+	}
+	if err := a.ApplyDiff([]CellChange{{X: 0, Y: 0}, {X: 2, Y: 0}}); err == nil {
+		t.Error("expected error for out of bounds change")
+	}
+	if rows := bufferRows(t, a); rows[0] != "  " {
+		t.Errorf("failed ApplyDiff modified the buffer: %q", rows[0])
+	}
+}
+
+func BenchmarkBufferDiff(b *testing.B) {
+	from := newTestBuffer(b, 200, 60)
+	to := newTestBuffer(b, 200, 60)
+
+	// Change 1% of the cells
+	for i := 0; i < 200*60/100; i++ {
+		to.DrawText("x", uint32(i*97%200), uint32(i*31%60), White, nil, 0)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		from.Diff(to)
+	}
+}
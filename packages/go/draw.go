@@ -0,0 +1,358 @@
+package opentui
+
+import (
+	"unicode/utf8"
+)
+
+// Default characters used by DrawLine when no character is given
+const (
+	lineCharHorizontal   = '─'
+	lineCharVertical     = '│'
+	lineCharDiagonalDown = '╲'
+	lineCharDiagonalUp   = '╱'
+)
+
+// DrawLine draws a line between two points, inclusive of both end points.
+// If char is 0 a box drawing character matching the line direction is chosen.
+// If bg is nil the existing background colors are kept.
+// Parts of the line outside the buffer are clipped.
+func (b *Buffer) DrawLine(x0, y0, x1, y1 int32, char rune, fg RGBA, bg *RGBA, attrs Attributes) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+
+	width, height, err := b.Size()
+	if err != nil {
+		return err
+	}
+
+	if char == 0 {
+		char = defaultLineChar(x0, y0, x1, y1)
+	}
+
+	// Horizontal and vertical lines are clipped up front
+	if y0 == y1 {
+		if y0 < 0 || y0 >= int32(height) {
+			return nil
+		}
+		start, end := clipSpan(x0, x1, width)
+		for x := start; x < end; x++ {
+			b.setCell(uint32(x), uint32(y0), char, fg, bg, attrs)
+		}
+		return nil
+	}
+	if x0 == x1 {
+		if x0 < 0 || x0 >= int32(width) {
+			return nil
+		}
+		start, end := clipSpan(y0, y1, height)
+		for y := start; y < end; y++ {
+			b.setCell(uint32(x0), uint32(y), char, fg, bg, attrs)
+		}
+		return nil
+	}
+
+	// Bresenham walk for everything else
+	x, y := int64(x0), int64(y0)
+	dx, dy := abs64(int64(x1)-x), -abs64(int64(y1)-y)
+	sx, sy := int64(1), int64(1)
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	errTerm := dx + dy
+	for {
+		if x >= 0 && y >= 0 && x < int64(width) && y < int64(height) {
+			b.setCell(uint32(x), uint32(y), char, fg, bg, attrs)
+		} else if (sx > 0 && x >= int64(width)) || (sx < 0 && x < 0) ||
+			(sy > 0 && y >= int64(height)) || (sy < 0 && y < 0) {
+			// The line has left the buffer and can't come back
+			break
+		}
+
+		if x == int64(x1) && y == int64(y1) {
+			break
+		}
+		e2 := 2 * errTerm
+		if e2 >= dy {
+			errTerm += dy
+			x += sx
+		}
+		if e2 <= dx {
+			errTerm += dx
+			y += sy
+		}
+	}
+	return nil
+}
+
+// setCell writes a single cell, keeping the existing background when bg is nil.
+func (b *Buffer) setCell(x, y uint32, char rune, fg RGBA, bg *RGBA, attrs Attributes) {
+	if bg != nil {
+		b.SetCellWithAlphaBlending(x, y, char, fg, *bg, attrs)
+		return
+	}
+	var encoded [utf8.UTFMax]byte
+	n := utf8.EncodeRune(encoded[:], char)
+	b.DrawText(string(encoded[:n]), x, y, fg, nil, attrs)
+}
+
+// defaultLineChar picks a box drawing character for a line between two points.
+func defaultLineChar(x0, y0, x1, y1 int32) rune {
+	switch {
+	case y0 == y1:
+		return lineCharHorizontal
+	case x0 == x1:
+		return lineCharVertical
+	case (x1 > x0) == (y1 > y0):
+		return lineCharDiagonalDown
+	default:
+		return lineCharDiagonalUp
+	}
+}
+
+// clipSpan orders the inclusive range [a, b] and clips it to [0, limit).
+// The result is returned as a half-open range.
+func clipSpan(a, b int32, limit uint32) (int64, int64) {
+	start, end := int64(a), int64(b)
+	if start > end {
+		start, end = end, start
+	}
+	end++
+	if start < 0 {
+		start = 0
+	}
+	if end > int64(limit) {
+		end = int64(limit)
+	}
+	return start, end
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// LineStyle selects the box drawing character set used by DrawHLine and DrawVLine
+type LineStyle uint8
+
+const (
+	LineSingle LineStyle = iota
+	LineDouble
+	LineHeavy
+)
+
+// Directions a box drawing character connects to
+const (
+	connectUp uint8 = 1 << iota
+	connectRight
+	connectDown
+	connectLeft
+
+	connectHorizontal = connectLeft | connectRight
+	connectVertical   = connectUp | connectDown
+)
+
+// boxGlyphs maps connection masks to box drawing characters for each line style
+var boxGlyphs = map[LineStyle]map[uint8]rune{
+	LineSingle: {
+		connectHorizontal:                   '─',
+		connectVertical:                     '│',
+		connectRight | connectDown:          '┌',
+		connectLeft | connectDown:           '┐',
+		connectUp | connectRight:            '└',
+		connectUp | connectLeft:             '┘',
+		connectVertical | connectRight:      '├',
+		connectVertical | connectLeft:       '┤',
+		connectHorizontal | connectDown:     '┬',
+		connectHorizontal | connectUp:       '┴',
+		connectHorizontal | connectVertical: '┼',
+	},
+	LineDouble: {
+		connectHorizontal:                   '═',
+		connectVertical:                     '║',
+		connectRight | connectDown:          '╔',
+		connectLeft | connectDown:           '╗',
+		connectUp | connectRight:            '╚',
+		connectUp | connectLeft:             '╝',
+		connectVertical | connectRight:      '╠',
+		connectVertical | connectLeft:       '╣',
+		connectHorizontal | connectDown:     '╦',
+		connectHorizontal | connectUp:       '╩',
+		connectHorizontal | connectVertical: '╬',
+	},
+	LineHeavy: {
+		connectHorizontal:                   '━',
+		connectVertical:                     '┃',
+		connectRight | connectDown:          '┏',
+		connectLeft | connectDown:           '┓',
+		connectUp | connectRight:            '┗',
+		connectUp | connectLeft:             '┛',
+		connectVertical | connectRight:      '┣',
+		connectVertical | connectLeft:       '┫',
+		connectHorizontal | connectDown:     '┳',
+		connectHorizontal | connectUp:       '┻',
+		connectHorizontal | connectVertical: '╋',
+	},
+}
+
+// boxGlyphInfo describes the style and connections of a known box drawing character
+type boxGlyphInfo struct {
+	style   LineStyle
+	connect uint8
+}
+
+// boxGlyphLookup is the reverse of boxGlyphs, extended with rounded corners
+var boxGlyphLookup = func() map[rune]boxGlyphInfo {
+	lookup := map[rune]boxGlyphInfo{
+		'╭': {LineSingle, connectRight | connectDown},
+		'╮': {LineSingle, connectLeft | connectDown},
+		'╰': {LineSingle, connectUp | connectRight},
+		'╯': {LineSingle, connectUp | connectLeft},
+	}
+	for style, glyphs := range boxGlyphs {
+		for connect, char := range glyphs {
+			lookup[char] = boxGlyphInfo{style: style, connect: connect}
+		}
+	}
+	return lookup
+}()
+
+// DrawHLine draws a horizontal line of the given length starting at (x, y).
+// Where the line crosses or meets box drawing characters of the same style,
+// the matching junction character (├ ┤ ┬ ┴ ┼) is drawn instead.
+// Characters of other styles are overwritten. The line is clipped to the buffer.
+func (b *Buffer) DrawHLine(x, y int32, length uint32, style LineStyle, fg RGBA) error {
+	return b.drawStraightLine(x, y, length, style, fg, true)
+}
+
+// DrawVLine draws a vertical line of the given length starting at (x, y).
+// Junctions with existing box drawing characters are merged as in DrawHLine.
+func (b *Buffer) DrawVLine(x, y int32, length uint32, style LineStyle, fg RGBA) error {
+	return b.drawStraightLine(x, y, length, style, fg, false)
+}
+
+// drawStraightLine implements DrawHLine and DrawVLine.
+func (b *Buffer) drawStraightLine(x, y int32, length uint32, style LineStyle, fg RGBA, horizontal bool) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	glyphs, ok := boxGlyphs[style]
+	if !ok {
+		return newError("invalid line style")
+	}
+	if length == 0 {
+		return nil
+	}
+
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+
+	var texts []string // Text of the cells, for junctions with pooled characters
+	full, toStart, toEnd := connectHorizontal, connectLeft, connectRight
+	if !horizontal {
+		full, toStart, toEnd = connectVertical, connectUp, connectDown
+	}
+
+	for i := int64(0); i < int64(length); i++ {
+		cx, cy := int64(x), int64(y)
+		if horizontal {
+			cx += i
+		} else {
+			cy += i
+		}
+		if cx < 0 || cy < 0 || cx >= int64(da.Width) || cy >= int64(da.Height) {
+			continue
+		}
+
+		// End points only connect inwards so they form tees and corners
+		connect := full
+		if length > 1 && i == 0 {
+			connect = toEnd
+		} else if length > 1 && i == int64(length)-1 {
+			connect = toStart
+		}
+
+		char := glyphs[full]
+		index := uint32(cy)*da.Width + uint32(cx)
+		existing := rune(da.Chars[index])
+		if isClusterChar(da.Chars[index]) {
+			// Resolved once, a line never comes back to the cells it drew
+			if texts == nil {
+				texts = da.cellTexts()
+			}
+			existing = cellChar(texts[index])
+		}
+		if info, ok := boxGlyphLookup[existing]; ok && info.style == style {
+			if merged, ok := glyphs[info.connect|connect]; ok {
+				char = merged
+			} else {
+				char = existing
+			}
+		}
+		b.setCell(uint32(cx), uint32(cy), char, fg, nil, 0)
+	}
+	return nil
+}
+
+// DrawRuneRepeat draws ch repeatedly to the right of (x, y), filling count columns.
+// Wide characters advance by two columns and are never drawn past count columns.
+// If bg is nil the existing background colors are kept.
+// The run is clipped at the right edge of the buffer without allocating.
+func (b *Buffer) DrawRuneRepeat(x, y uint32, count uint32, ch rune, fg RGBA, bg *RGBA, attrs Attributes) error {
+	return b.drawRuneRepeat(x, y, count, ch, fg, bg, attrs, true)
+}
+
+// DrawRuneRepeatVertical draws ch repeatedly downwards from (x, y), filling count rows.
+// Like DrawRuneRepeat, it keeps the existing background if bg is nil and clips
+// at the bottom edge of the buffer.
+func (b *Buffer) DrawRuneRepeatVertical(x, y uint32, count uint32, ch rune, fg RGBA, bg *RGBA, attrs Attributes) error {
+	return b.drawRuneRepeat(x, y, count, ch, fg, bg, attrs, false)
+}
+
+// drawRuneRepeat implements DrawRuneRepeat and DrawRuneRepeatVertical.
+func (b *Buffer) drawRuneRepeat(x, y, count uint32, ch rune, fg RGBA, bg *RGBA, attrs Attributes, horizontal bool) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+
+	width := uint64(max(runeWidth(ch), 1))
+	if x >= da.Width || y >= da.Height || uint64(x)+width > uint64(da.Width) {
+		return nil
+	}
+
+	step, limit := width, uint64(count)
+	if horizontal {
+		limit = min(limit, uint64(da.Width-x))
+	} else {
+		step = 1
+		limit = min(limit, uint64(da.Height-y))
+	}
+
+	for i := uint64(0); i+step <= limit; i += step {
+		cx, cy := x, y
+		if horizontal {
+			cx += uint32(i)
+		} else {
+			cy += uint32(i)
+		}
+		cellBg := bg
+		if cellBg == nil {
+			// Blending the existing background over itself leaves it unchanged
+			cellBg = &da.Background[cy*da.Width+cx]
+		}
+		b.SetCellWithAlphaBlending(cx, cy, ch, fg, *cellBg, attrs)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package opentui
+
+import (
+	"testing"
+)
+
+// newTestBuffer creates a buffer for drawing tests, skipping the test if the library is unavailable.
+func newTestBuffer(t testing.TB, width, height uint32) *Buffer {
+	t.Helper()
+	buffer := NewBuffer(width, height, false, WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("Skipping drawing test - OpenTUI library not available")
+	}
+	t.Cleanup(func() { buffer.Close() })
+	if err := buffer.Clear(Black); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	return buffer
+}
+
+// bufferRows returns the characters of a buffer as one string per row, with a space
+// for the second half of a wide character.
+func bufferRows(t *testing.T, buffer *Buffer) []string {
+	t.Helper()
+	da, err := buffer.GetDirectAccess()
+	if err != nil {
+		t.Fatalf("GetDirectAccess failed: %v", err)
+	}
+	texts := da.cellTexts()
+	rows := make([]string, da.Height)
+	for y := uint32(0); y < da.Height; y++ {
+		row := make([]rune, da.Width)
+		for x := uint32(0); x < da.Width; x++ {
+			row[x] = cellChar(texts[y*da.Width+x])
+		}
+		rows[y] = string(row)
+	}
+	return rows
+}
+
+// expectRows compares the buffer contents against the expected rows.
+func expectRows(t *testing.T, buffer *Buffer, want ...string) {
+	t.Helper()
+	got := bufferRows(t, buffer)
+	if len(got) != len(want) {
+		t.Fatalf("buffer has %d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestDrawLine(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 4)
+
+	if err := buffer.DrawLine(0, 0, 5, 0, 0, White, nil, 0); err != nil {
+		t.Fatalf("DrawLine failed: %v", err)
+	}
+	buffer.DrawLine(5, 3, 5, 1, 0, White, nil, 0)
+	buffer.DrawLine(0, 1, 2, 3, '*', White, &Black, 0)
+
+	expectRows(t, buffer,
+		"──────",
+		"*    │",
+		" *   │",
+		"  *  │",
+	)
+}
+
+func TestDrawLineClipping(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 3)
+
+	buffer.DrawLine(-10, 1, 100, 1, 0, White, nil, 0)
+	buffer.DrawLine(2, -5, 2, -1, 0, White, nil, 0)
+	buffer.DrawLine(-2, -2, 10, 10, 'x', White, nil, 0)
+
+	expectRows(t, buffer,
+		"x   ",
+		"─x──",
+		"  x ",
+	)
+}
+
+func TestDrawHLineVLineJunctions(t *testing.T) {
+	buffer := newTestBuffer(t, 5, 4)
+
+	buffer.DrawVLine(2, 0, 4, LineSingle, White)
+	if err := buffer.DrawHLine(0, 1, 5, LineSingle, White); err != nil {
+		t.Fatalf("DrawHLine failed: %v", err)
+	}
+	buffer.DrawHLine(2, 2, 3, LineSingle, White)
+	buffer.DrawHLine(0, 3, 5, LineDouble, White)
+
+	expectRows(t, buffer,
+		"  │  ",
+		"──┼──",
+		"  ├──",
+		"═════",
+	)
+}
+
+func TestDrawHLineSplitsBox(t *testing.T) {
+	buffer := newTestBuffer(t, 5, 4)
+
+	options := BoxOptions{
+		Sides:       BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+		BorderChars: DefaultBoxChars,
+	}
+	if err := buffer.DrawBox(0, 0, 5, 4, options, White, Black); err != nil {
+		t.Fatalf("DrawBox failed: %v", err)
+	}
+	buffer.DrawHLine(0, 2, 5, LineSingle, White)
+	buffer.DrawVLine(2, 0, 3, LineSingle, White)
+
+	expectRows(t, buffer,
+		"┌─┬─┐",
+		"│ │ │",
+		"├─┴─┤",
+		"└───┘",
+	)
+}
+
+func TestDrawRuneRepeat(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 3)
+	if err := buffer.DrawRuneRepeat(1, 0, 3, '─', White, nil, 0); err != nil {
+		t.Fatalf("DrawRuneRepeat failed: %v", err)
+	}
+	// Clipped at the right edge
+	buffer.DrawRuneRepeat(4, 1, 10, '=', White, nil, 0)
+	// Wide characters fill whole pairs of columns only
+	buffer.DrawRuneRepeat(0, 2, 5, '中', White, nil, 0)
+
+	expectRows(t, buffer,
+		" ───  ",
+		"    ==",
+		"中 中   ",
+	)
+}
+
+func TestDrawRuneRepeatVertical(t *testing.T) {
+	buffer := newTestBuffer(t, 2, 3)
+	buffer.DrawRuneRepeatVertical(1, 1, 5, '│', White, &Blue, 0)
+	expectRows(t, buffer, "  ", " │", " │")
+
+	if cell, _ := buffer.GetCell(1, 2); cell.Background != Blue {
+		t.Errorf("background = %+v, want blue", cell.Background)
+	}
+	if cell, _ := buffer.GetCell(1, 0); cell.Char != ' ' {
+		t.Errorf("cell above the run was changed to %q", cell.Char)
+	}
+}
+
+func TestDrawRuneRepeatKeepsBackground(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 1)
+	buffer.FillRect(0, 0, 3, 1, Red)
+	buffer.DrawRuneRepeat(0, 0, 3, '-', White, nil, 0)
+
+	if cell, _ := buffer.GetCell(2, 0); cell.Background != Red {
+		t.Errorf("background = %+v, want red", cell.Background)
+	}
+	// No strings are built: besides the direct access lookup, allocations
+	// come only from the cell writes themselves
+	allocs := testing.AllocsPerRun(10, func() {
+		buffer.DrawRuneRepeat(0, 0, 3, '-', White, nil, 0)
+	})
+	cellAllocs := testing.AllocsPerRun(10, func() {
+		for x := uint32(0); x < 3; x++ {
+			buffer.SetCellWithAlphaBlending(x, 0, '-', White, Red, 0)
+		}
+	})
+	if allocs > cellAllocs+1 {
+		t.Errorf("DrawRuneRepeat allocated %.0f times, cell writes alone %.0f", allocs, cellAllocs)
+	}
+}
@@ -0,0 +1,122 @@
+package opentui
+
+/*
+#include "opentui.h"
+#include <string.h>
+
+// lastErrorMessage holds the message of the failure taken by takeLastError, per
+// thread like the failure itself, so that successful checks don't allocate.
+static _Thread_local uint8_t lastErrorMessage[128];
+
+static uint8_t takeLastError(void) {
+	return getLastError(lastErrorMessage, sizeof lastErrorMessage);
+}
+
+static void copyLastErrorMessage(uint8_t* message, size_t maxLen) {
+	memcpy(message, lastErrorMessage, maxLen < sizeof lastErrorMessage ? maxLen : sizeof lastErrorMessage);
+}
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// Kinds of errors, to branch on with errors.Is. The errors returned carry their own
+// message and match their kind, for example "buffer is closed" matches ErrClosed.
+var (
+	// ErrClosed is matched by calls on a renderer, buffer or text buffer that was closed.
+	ErrClosed = newError("closed")
+	// ErrOutOfBounds is matched by coordinates, indices and dimensions out of range.
+	ErrOutOfBounds = newError("out of bounds")
+	// ErrAllocation is matched when the native library could not allocate memory,
+	// or dimensions are too large to try.
+	ErrAllocation = newError("allocation failed")
+)
+
+// maxCells bounds the cells of a renderer or buffer, keeping cell indices within the
+// 32 bits the native library uses. No terminal comes close.
+const maxCells = 1 << 24
+
+// Codes returned by the native getLastError
+const (
+	nativeNone = iota
+	nativeOutOfMemory
+	nativeOutOfBounds
+	nativeInvalidArgument
+	nativeWriteFailed
+)
+
+// kindError creates an error with msg matching kind.
+func kindError(kind error, msg string) error {
+	return &Error{Message: msg, kind: kind}
+}
+
+// closedError returns the error for a call on a closed object, for example
+// "renderer is closed".
+func closedError(what string) error {
+	return kindError(ErrClosed, what+" is closed")
+}
+
+// boundsError returns the error for a value out of range, for example
+// "index out of bounds".
+func boundsError(what string) error {
+	return kindError(ErrOutOfBounds, what+" out of bounds")
+}
+
+// checkDimensions returns an error unless width by height cells can be allocated.
+func checkDimensions(width, height uint32) error {
+	if width == 0 || height == 0 {
+		return kindError(ErrOutOfBounds, "invalid dimensions")
+	}
+	if uint64(width)*uint64(height) > maxCells {
+		return kindError(ErrAllocation, "dimensions too large")
+	}
+	return nil
+}
+
+// checkNative runs call, a native call that reports failures through getLastError,
+// and returns its failure as an error. The native library keeps the failure per
+// thread, so the goroutine stays on its thread meanwhile.
+func checkNative(call func()) error {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.getLastError(nil, 0) // forget failures of unchecked calls
+	call()
+	code := C.takeLastError()
+	if code == nativeNone {
+		return nil
+	}
+	var message [128]byte
+	C.copyLastErrorMessage((*C.uint8_t)(unsafe.Pointer(&message[0])), C.size_t(len(message)))
+	return nativeError(code, message[:])
+}
+
+// nativeError returns the error for a code returned by getLastError along with its
+// NUL-terminated message, or nil for nativeNone.
+func nativeError(code C.uint8_t, message []byte) error {
+	if code == nativeNone {
+		return nil
+	}
+	msg := "native call failed"
+	if n := clen(message); n > 0 {
+		msg = string(message[:n])
+	}
+	switch code {
+	case nativeOutOfMemory:
+		return kindError(ErrAllocation, msg)
+	case nativeOutOfBounds:
+		return kindError(ErrOutOfBounds, msg)
+	}
+	return newError(msg)
+}
+
+// clen returns the length of the NUL-terminated string in b.
+func clen(b []byte) int {
+	for i, c := range b {
+		if c == 0 {
+			return i
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,76 @@
+package opentui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrorKinds(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		kind error
+	}{
+		{closedError("buffer"), ErrClosed},
+		{boundsError("index"), ErrOutOfBounds},
+		{checkDimensions(0, 24), ErrOutOfBounds},
+		{checkDimensions(1<<20, 1<<20), ErrAllocation},
+	} {
+		if !errors.Is(tc.err, tc.kind) {
+			t.Errorf("%q does not match %q", tc.err, tc.kind)
+		}
+	}
+	if err := closedError("text buffer"); err.Error() != "text buffer is closed" {
+		t.Errorf("closedError message = %q", err)
+	}
+	if errors.Is(newError("boom"), ErrClosed) || errors.Is(closedError("buffer"), ErrOutOfBounds) {
+		t.Error("errors should only match their own kind")
+	}
+	if err := checkDimensions(4096, 4096); err != nil {
+		t.Errorf("checkDimensions(4096, 4096) = %v", err)
+	}
+}
+
+// Failures of native calls come back with the native code and message.
+func TestNativeErrors(t *testing.T) {
+	b := newTestBuffer(t, 10, 5)
+	// Go checks the dimensions first, so skip them to have the native call fail
+	err := b.resize(0, 5)
+	if !errors.Is(err, ErrOutOfBounds) {
+		t.Fatalf("native resize to 0x5 = %v, want ErrOutOfBounds", err)
+	}
+	if want := "bufferResize: InvalidDimensions"; err.Error() != want {
+		t.Errorf("message %q, want %q", err.Error(), want)
+	}
+	if w, h, _ := b.Size(); w != 10 || h != 5 {
+		t.Errorf("failed resize changed the size to %dx%d", w, h)
+	}
+	// The failure is taken by the call that caused it
+	if err := b.Resize(4, 4); err != nil {
+		t.Errorf("Resize after a failure = %v", err)
+	}
+}
+
+func TestResizeErrors(t *testing.T) {
+	renderer := NewRenderer(10, 5)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+
+	if err := renderer.Resize(1<<20, 1<<20); !errors.Is(err, ErrAllocation) {
+		t.Errorf("absurd Resize = %v, want ErrAllocation", err)
+	}
+	if renderer.width != 10 || renderer.height != 5 {
+		t.Errorf("failed Resize changed the size to %dx%d", renderer.width, renderer.height)
+	}
+	if err := renderer.Resize(0, 5); !errors.Is(err, ErrOutOfBounds) {
+		t.Errorf("Resize(0, 5) = %v, want ErrOutOfBounds", err)
+	}
+
+	renderer.Close()
+	if err := renderer.Resize(20, 10); !errors.Is(err, ErrClosed) {
+		t.Errorf("Resize after Close = %v, want ErrClosed", err)
+	}
+	if _, err := renderer.GetNextBuffer(); !errors.Is(err, ErrClosed) {
+		t.Errorf("GetNextBuffer after Close = %v, want ErrClosed", err)
+	}
+}
@@ -10,15 +10,15 @@ import (
 func main() {
 	fmt.Println("Starting OpenTUI Go Basic Example...")
 	
-	// Create a new renderer with 80x24 dimensions
-	renderer := opentui.NewRenderer(80, 24)
-	if renderer == nil {
-		panic("Failed to create renderer - make sure the OpenTUI library is available")
+	// Create a new renderer the size of the terminal
+	renderer, err := opentui.NewRendererAuto()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create renderer: %v", err))
 	}
 	defer renderer.Close()
 	
 	// Set a dark blue background
-	err := renderer.SetBackgroundColor(opentui.NewRGB(0.1, 0.1, 0.3))
+	err = renderer.SetBackgroundColor(opentui.NewRGB(0.1, 0.1, 0.3))
 	if err != nil {
 		panic(fmt.Sprintf("Failed to set background color: %v", err))
 	}
@@ -73,7 +73,7 @@ func main() {
 			color = colors[i-1]
 		}
 		
-		attrs := uint8(0)
+		attrs := opentui.Attributes(0)
 		if i == 0 {
 			attrs = opentui.AttrBold | opentui.AttrUnderline
 		}
@@ -111,18 +111,12 @@ func main() {
 		panic(fmt.Sprintf("Failed to draw box content: %v", err))
 	}
 	
-	err = buffer.DrawText("Built with OpenTUI", 52, 9, 
+	err = buffer.DrawText("Built with OpenTUI\nGo Bindings v1.0", 52, 9, 
 		opentui.Cyan, nil, 0)
 	if err != nil {
 		panic(fmt.Sprintf("Failed to draw box content: %v", err))
 	}
 	
-	err = buffer.DrawText("Go Bindings v1.0", 52, 10, 
-		opentui.Yellow, nil, 0)
-	if err != nil {
-		panic(fmt.Sprintf("Failed to draw box content: %v", err))
-	}
-	
 	// Fill a colored rectangle
 	err = buffer.FillRect(10, 18, 60, 3, opentui.NewRGB(0.8, 0.2, 0.2))
 	if err != nil {
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"syscall"
+	"time"
+)
+
+// CPUSampler records the CPU usage of this process over time
+type CPUSampler struct {
+	Samples    []float64 // Usage in percent of one core, oldest first
+	MaxSamples int
+
+	lastSample time.Time
+	lastCPU    time.Duration
+}
+
+// NewCPUSampler creates a sampler that keeps the given number of samples
+func NewCPUSampler(maxSamples int) *CPUSampler {
+	return &CPUSampler{MaxSamples: maxSamples, lastSample: time.Now(), lastCPU: processCPUTime()}
+}
+
+// Sample records the usage since the previous sample
+func (s *CPUSampler) Sample() {
+	now := time.Now()
+	cpu := processCPUTime()
+	elapsed := now.Sub(s.lastSample)
+	if elapsed <= 0 {
+		return
+	}
+
+	usage := 100 * float64(cpu-s.lastCPU) / float64(elapsed)
+	s.Samples = append(s.Samples, usage)
+	if len(s.Samples) > s.MaxSamples {
+		s.Samples = s.Samples[len(s.Samples)-s.MaxSamples:]
+	}
+	s.lastSample, s.lastCPU = now, cpu
+}
+
+// processCPUTime returns the user and system CPU time used by this process
+func processCPUTime() time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
@@ -38,11 +38,9 @@ func (t *TerminalInput) Close() error {
 
 // InputEvent represents different types of input events
 type InputEvent struct {
-	Type   string // "key", "mouse_move", "mouse_click", "mouse_release"
-	Key    rune
-	MouseX uint32
-	MouseY uint32
-	Button int
+	Type string // "key", "key_release", "mouse" or "response"
+	Key  rune
+	Data []byte // Raw mouse report or reply to a terminal query for "mouse" and "response" events
 }
 
 // ReadInput reads input events from the terminal
@@ -64,6 +62,9 @@ func (t *TerminalInput) ReadInput() (*InputEvent, error) {
 		if next == '[' {
 			// ANSI escape sequence
 			return t.parseAnsiSequence()
+		} else if next == 'P' || next == ']' || next == '_' {
+			// DCS, OSC and APC strings are replies to terminal queries
+			return t.parseStringSequence(next)
 		} else {
 			// Put back the byte and return ESC
 			// Note: This is simplified - proper implementation would use unread
@@ -97,27 +98,81 @@ func (t *TerminalInput) parseAnsiSequence() (*InputEvent, error) {
 		}
 		
 		// Prevent infinite loops
-		if seq.Len() > 20 {
+		if seq.Len() > 64 {
 			break
 		}
 	}
 	
 	sequence := seq.String()
 	
-	// Parse mouse events (simplified)
-	// Real mouse parsing is more complex
-	if strings.Contains(sequence, "M") {
-		// Mouse click/release
-		return &InputEvent{Type: "mouse_click", MouseX: 10, MouseY: 10, Button: 1}, nil
+	// Private replies to terminal queries, such as DA1 or the kitty keyboard flags
+	if strings.HasPrefix(sequence, "?") || strings.HasPrefix(sequence, ">") {
+		return &InputEvent{Type: "response", Data: []byte("\x1b[" + sequence)}, nil
+	}
+	
+	// Keys in the kitty keyboard protocol: CSI code ; modifiers : event u
+	if strings.HasSuffix(sequence, "u") {
+		return parseKittyKey(strings.TrimSuffix(sequence, "u")), nil
+	}
+	
+	// SGR mouse reports, for Renderer.ParseMouseEvent
+	if strings.HasPrefix(sequence, "<") {
+		return &InputEvent{Type: "mouse", Data: []byte("\x1b[" + sequence)}, nil
 	}
 	
 	// Default to unknown key sequence
 	return &InputEvent{Type: "key", Key: 0}, nil
 }
 
+// parseKittyKey turns the parameters of a kitty keyboard protocol key into an
+// event. Releases are reported as their own event type, and Ctrl+letter becomes the
+// control character the terminal would have sent without the protocol.
+func parseKittyKey(params string) *InputEvent {
+	codeText, modifiers, _ := strings.Cut(params, ";")
+	code, err := strconv.Atoi(strings.Split(codeText, ":")[0])
+	if err != nil {
+		return &InputEvent{Type: "key", Key: 0}
+	}
+	mods, event, _ := strings.Cut(modifiers, ":")
+	if event == "3" {
+		return &InputEvent{Type: "key_release", Key: rune(code)}
+	}
+	if m, err := strconv.Atoi(mods); err == nil && (m-1)&4 != 0 && code >= 'a' && code <= 'z' {
+		return &InputEvent{Type: "key", Key: rune(code) & 0x1f}
+	}
+	return &InputEvent{Type: "key", Key: rune(code)}
+}
+
+// parseStringSequence reads a string sequence up to its BEL or ST terminator
+func (t *TerminalInput) parseStringSequence(introducer byte) (*InputEvent, error) {
+	data := []byte{27, introducer}
+	for {
+		b, err := t.reader.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, b)
+		if b == 7 || (b == '\\' && data[len(data)-2] == 27) {
+			break
+		}
+		
+		// Prevent unbounded growth
+		if len(data) > 4096 {
+			break
+		}
+	}
+	return &InputEvent{Type: "response", Data: data}, nil
+}
+
 // KeyboardOnlyInput provides a simpler keyboard-only input for the demo
 type KeyboardOnlyInput struct {
 	input *TerminalInput
+	
+	// OnResponse receives replies to terminal queries, which are not keys
+	OnResponse func(data []byte)
+	
+	// OnMouse receives mouse reports
+	OnMouse func(data []byte)
 }
 
 // NewKeyboardOnlyInput creates a keyboard-only input handler
@@ -145,6 +200,12 @@ func (k *KeyboardOnlyInput) ReadKey() (rune, error) {
 	if event.Type == "key" {
 		return event.Key, nil
 	}
+	if event.Type == "response" && k.OnResponse != nil {
+		k.OnResponse(event.Data)
+	}
+	if event.Type == "mouse" && k.OnMouse != nil {
+		k.OnMouse(event.Data)
+	}
 	
 	// Non-key events, try again
 	return k.ReadKey()
@@ -183,27 +244,3 @@ func RestoreTerminal() error {
 	cmd.Stdin = os.Stdin
 	return cmd.Run()
 }
-
-// GetTerminalSize gets the terminal dimensions
-func GetTerminalSize() (int, int, error) {
-	cmd := exec.Command("stty", "size")
-	cmd.Stdin = os.Stdin
-	out, err := cmd.Output()
-	if err != nil {
-		return 80, 24, nil // Default fallback
-	}
-	
-	parts := strings.Fields(strings.TrimSpace(string(out)))
-	if len(parts) != 2 {
-		return 80, 24, nil
-	}
-	
-	rows, err1 := strconv.Atoi(parts[0])
-	cols, err2 := strconv.Atoi(parts[1])
-	
-	if err1 != nil || err2 != nil {
-		return 80, 24, nil
-	}
-	
-	return cols, rows, nil
-}
\ No newline at end of file
@@ -1,140 +1,55 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/sst/opentui/packages/go"
+	"github.com/sst/opentui/packages/go/layout"
+	"github.com/sst/opentui/packages/go/widgets"
 )
 
-// ConsoleButton represents a clickable button with hover and press states
-type ConsoleButton struct {
-	ID           string
-	X, Y         int32
-	Width, Height uint32
-	Label        string
-	LogType      string
-	
-	// Colors
-	OriginalBg   opentui.RGBA
-	HoverBg      opentui.RGBA
-	PressBg      opentui.RGBA
-	BorderColor  opentui.RGBA
-	
-	// State
-	IsHovered    bool
-	IsPressed    bool
-	LastClickTime time.Time
-	
-	// Statistics
-	ClickCount   int
-}
+// buttonRow lays out the five buttons side by side with equal widths, between 8 and
+// 20 cells each, two cells apart
+var buttonRow = layout.Row(
+	layout.Flex(1).WithMin(8).WithMax(20),
+	layout.Flex(1).WithMin(8).WithMax(20),
+	layout.Flex(1).WithMin(8).WithMax(20),
+	layout.Flex(1).WithMin(8).WithMax(20),
+	layout.Flex(1).WithMin(8).WithMax(20),
+).WithGap(2)
 
-// NewConsoleButton creates a new console button
-func NewConsoleButton(id string, x, y int32, width, height uint32, color opentui.RGBA, label, logType string) *ConsoleButton {
-	// Create brighter border color
-	borderColor := opentui.NewRGBA(
-		min(color.R*1.3, 1.0),
-		min(color.G*1.3, 1.0), 
-		min(color.B*1.3, 1.0),
-		1.0,
-	)
-	
-	// Create hover and press colors
-	hoverBg := opentui.NewRGBA(
-		min(color.R*1.2, 1.0),
-		min(color.G*1.2, 1.0),
-		min(color.B*1.2, 1.0),
-		color.A,
-	)
-	
-	pressBg := opentui.NewRGBA(
-		color.R*0.8,
-		color.G*0.8,
-		color.B*0.8,
-		color.A,
-	)
-	
-	return &ConsoleButton{
-		ID:           id,
-		X:            x,
-		Y:            y,
-		Width:        width,
-		Height:       height,
-		Label:        label,
-		LogType:      logType,
-		OriginalBg:   color,
-		HoverBg:      hoverBg,
-		PressBg:      pressBg,
-		BorderColor:  borderColor,
-		IsHovered:    false,
-		IsPressed:    false,
-		ClickCount:   0,
-	}
+// logDescriptions are shown in a tooltip while the pointer is over the button of each
+// log type
+var logDescriptions = map[string]string{
+	"log":   "Prints a plain message with fmt.Fprintf",
+	"info":  "Logs an informational message",
+	"warn":  "Logs a warning with its reason",
+	"error": "Logs a simulated error with an error code",
+	"debug": "Logs debug variables and state",
 }
 
-// Contains checks if a point is within the button bounds
-func (b *ConsoleButton) Contains(x, y uint32) bool {
-	return x >= uint32(b.X) && x < uint32(b.X)+b.Width &&
-		   y >= uint32(b.Y) && y < uint32(b.Y)+b.Height
+// ConsoleButton is a button that logs a message of its type when clicked
+type ConsoleButton struct {
+	*widgets.Button
+	LogType string
+	Output  io.Writer // Where plain messages go; the others go to the standard logger
 }
 
-// Render draws the button to the buffer
-func (b *ConsoleButton) Render(buffer *opentui.Buffer) error {
-	// Choose background color based on state
-	var bgColor opentui.RGBA
-	if b.IsPressed {
-		bgColor = b.PressBg
-	} else if b.IsHovered {
-		bgColor = b.HoverBg
-	} else {
-		bgColor = b.OriginalBg
-	}
-	
-	// Draw the button box
-	boxOptions := opentui.BoxOptions{
-		Sides: opentui.BorderSides{
-			Top:    true,
-			Right:  true,
-			Bottom: true,
-			Left:   true,
-		},
-		Fill:           true,
-		Title:          b.Label,
-		TitleAlignment: opentui.AlignCenter,
-		BorderChars:    opentui.DefaultBoxChars,
-	}
-	
-	err := buffer.DrawBox(b.X, b.Y, b.Width, b.Height, boxOptions, b.BorderColor, bgColor)
-	if err != nil {
-		return fmt.Errorf("failed to draw button box: %v", err)
+// NewConsoleButton creates a new console button, placed by LayoutButtons
+func NewConsoleButton(id uint32, color opentui.RGBA, label, logType string) *ConsoleButton {
+	button := &ConsoleButton{
+		Button:  widgets.NewButton(id, opentui.Rect{}, label, widgets.NewStyle(color)),
+		LogType: logType,
+		Output:  os.Stdout,
 	}
-	
-	// Draw sparkle effect if recently clicked
-	timeSinceClick := time.Since(b.LastClickTime)
-	if timeSinceClick < 300*time.Millisecond {
-		alpha := 1.0 - float32(timeSinceClick.Milliseconds())/300.0
-		sparkleColor := opentui.NewRGBA(1, 1, 1, alpha)
-		
-		centerX := uint32(b.X) + b.Width/2
-		centerY := uint32(b.Y) + b.Height/2
-		
-		// Draw sparkles
-		buffer.SetCellWithAlphaBlending(centerX-1, centerY, '✦', sparkleColor, bgColor, 0)
-		buffer.SetCellWithAlphaBlending(centerX+1, centerY, '✦', sparkleColor, bgColor, 0)
-	}
-	
-	return nil
-}
-
-// Click handles a button click
-func (b *ConsoleButton) Click() {
-	b.IsPressed = true
-	b.LastClickTime = time.Now()
-	b.ClickCount++
-	b.TriggerConsoleLog()
+	button.OnClick = button.TriggerConsoleLog
+	return button
 }
 
 // TriggerConsoleLog simulates console logging based on the button type
@@ -143,34 +58,34 @@ func (b *ConsoleButton) TriggerConsoleLog() {
 	
 	switch b.LogType {
 	case "log":
-		fmt.Printf("Console Log #%d triggered at %s\n", b.ClickCount, timestamp)
-		fmt.Printf("  Data: This is a regular log message\n")
-		fmt.Printf("  Count: %d\n", b.ClickCount)
-		fmt.Printf("  Metadata: {source: console-demo, type: log}\n\n")
+		fmt.Fprintf(b.Output, "Console Log #%d triggered at %s\n", b.Clicks(), timestamp)
+		fmt.Fprintf(b.Output, "  Data: This is a regular log message\n")
+		fmt.Fprintf(b.Output, "  Count: %d\n", b.Clicks())
+		fmt.Fprintf(b.Output, "  Metadata: {source: console-demo, type: log}\n\n")
 		
 	case "info":
-		log.Printf("INFO: Info Log #%d triggered at %s", b.ClickCount, timestamp)
+		log.Printf("INFO: Info Log #%d triggered at %s", b.Clicks(), timestamp)
 		log.Printf("INFO:   Message: This is an informational message")
 		log.Printf("INFO:   Details: Info messages are used for general information")
-		log.Printf("INFO:   Count: %d\n", b.ClickCount)
+		log.Printf("INFO:   Count: %d\n", b.Clicks())
 		
 	case "warn":
-		log.Printf("WARN: Warning Log #%d triggered at %s", b.ClickCount, timestamp)
+		log.Printf("WARN: Warning Log #%d triggered at %s", b.Clicks(), timestamp)
 		log.Printf("WARN:   Warning: This is a warning message")
 		log.Printf("WARN:   Reason: Something might need attention")
-		log.Printf("WARN:   Count: %d\n", b.ClickCount)
+		log.Printf("WARN:   Count: %d\n", b.Clicks())
 		
 	case "error":
-		log.Printf("ERROR: Error Log #%d triggered at %s", b.ClickCount, timestamp)
+		log.Printf("ERROR: Error Log #%d triggered at %s", b.Clicks(), timestamp)
 		log.Printf("ERROR:   Error: This is an error message")
 		log.Printf("ERROR:   Details: Something went wrong (simulated)")
-		log.Printf("ERROR:   ErrorCode: ERR_%d", b.ClickCount)
-		log.Printf("ERROR:   Count: %d\n", b.ClickCount)
+		log.Printf("ERROR:   ErrorCode: ERR_%d", b.Clicks())
+		log.Printf("ERROR:   Count: %d\n", b.Clicks())
 		
 	case "debug":
-		log.Printf("DEBUG: Debug Log #%d triggered at %s", b.ClickCount, timestamp)
+		log.Printf("DEBUG: Debug Log #%d triggered at %s", b.Clicks(), timestamp)
 		log.Printf("DEBUG:   Debug: This is a debug message")
-		log.Printf("DEBUG:   Variables: {count: %d}", b.ClickCount)
+		log.Printf("DEBUG:   Variables: {count: %d}", b.Clicks())
 		log.Printf("DEBUG:   State: debugging\n")
 	}
 }
@@ -184,20 +99,18 @@ type DemoState struct {
 	Running     bool
 	MouseX      uint32
 	MouseY      uint32
+	Dispatcher  *widgets.Dispatcher
+	Popover     *widgets.Popover
+	Log         *widgets.LogView
+	CPU         *CPUSampler
+	lastCPU     time.Time
 }
 
 // NewDemoState creates a new demo state
 func NewDemoState() (*DemoState, error) {
-	renderer := opentui.NewRenderer(80, 30)
-	if renderer == nil {
-		return nil, fmt.Errorf("failed to create renderer")
-	}
-	
-	// Enable mouse tracking
-	err := renderer.EnableMouse(true)
+	renderer, err := opentui.NewRendererAuto()
 	if err != nil {
-		renderer.Close()
-		return nil, fmt.Errorf("failed to enable mouse: %v", err)
+		return nil, fmt.Errorf("failed to create renderer: %v", err)
 	}
 	
 	// Set background color
@@ -214,6 +127,14 @@ func NewDemoState() (*DemoState, error) {
 		return nil, fmt.Errorf("failed to get buffer: %v", err)
 	}
 	
+	// The log output goes to a pane under the buttons instead of the terminal, which
+	// the renderer draws over
+	logView, err := widgets.NewLogView(5, opentui.Rect{}, 1000)
+	if err != nil {
+		renderer.Close()
+		return nil, fmt.Errorf("failed to create log view: %v", err)
+	}
+	
 	// Create buttons
 	logColor := opentui.NewRGBA(160.0/255, 160.0/255, 170.0/255, 1.0)
 	infoColor := opentui.NewRGBA(100.0/255, 180.0/255, 200.0/255, 1.0)
@@ -221,49 +142,144 @@ func NewDemoState() (*DemoState, error) {
 	errorColor := opentui.NewRGBA(200.0/255, 120.0/255, 120.0/255, 1.0)
 	debugColor := opentui.NewRGBA(140.0/255, 140.0/255, 150.0/255, 1.0)
 	
-	startY := int32(8)
-	buttonWidth := uint32(14)
-	buttonHeight := uint32(5)
-	spacing := int32(16)
-	
 	buttons := []*ConsoleButton{
-		NewConsoleButton("log", 2, startY, buttonWidth, buttonHeight, logColor, "LOG", "log"),
-		NewConsoleButton("info", 2+spacing, startY, buttonWidth, buttonHeight, infoColor, "INFO", "info"),
-		NewConsoleButton("warn", 2+spacing*2, startY, buttonWidth, buttonHeight, warnColor, "WARN", "warn"),
-		NewConsoleButton("error", 2+spacing*3, startY, buttonWidth, buttonHeight, errorColor, "ERROR", "error"),
-		NewConsoleButton("debug", 2+spacing*4, startY, buttonWidth, buttonHeight, debugColor, "DEBUG", "debug"),
+		NewConsoleButton(0, logColor, "LOG", "log"),
+		NewConsoleButton(1, infoColor, "INFO", "info"),
+		NewConsoleButton(2, warnColor, "WARN", "warn"),
+		NewConsoleButton(3, errorColor, "ERROR", "error"),
+		NewConsoleButton(4, debugColor, "DEBUG", "debug"),
+	}
+	for _, button := range buttons {
+		button.Output = logView
 	}
+	log.SetOutput(logView)
+	log.SetFlags(0) // The log view stamps the lines itself
 	
-	return &DemoState{
+	demo := &DemoState{
 		Renderer:   renderer,
 		Buffer:     buffer,
 		Buttons:    buttons,
 		StatusText: "Click any button to start logging...",
 		Running:    true,
-	}, nil
+		Dispatcher: widgets.NewDispatcher(renderer, nil),
+		Popover:    widgets.NewPopover(renderer),
+		Log:        logView,
+		CPU:        NewCPUSampler(60),
+		lastCPU:    time.Now(),
+	}
+	
+	// The dispatcher passes mouse events to the button under the pointer, and keys
+	// to handleInput
+	demo.Dispatcher.OnMouse = func(ev opentui.MouseEvent) {
+		demo.MouseX = uint32(max(ev.Position.X, 0))
+		demo.MouseY = uint32(max(ev.Position.Y, 0))
+	}
+	demo.Dispatcher.OnKey = func(ev opentui.KeyEvent) bool {
+		demo.Popover.HandleKey(ev)
+		if !handleInput(demo, ev.Key) {
+			demo.Running = false
+		}
+		return true
+	}
+	demo.Dispatcher.AddWidget(logView.ID, logView)
+	for _, button := range buttons {
+		button := button
+		region := demo.Dispatcher.AddWidget(button.ID, button)
+		
+		// Describe the button while the pointer is over it
+		region.OnEnter = func(ev opentui.MouseEvent) {
+			if err := demo.Popover.Show(button.Rect, logDescriptions[button.LogType]); err != nil {
+				demo.StatusText = fmt.Sprintf("Failed to show tooltip: %v", err)
+			}
+		}
+		leave := region.OnLeave
+		region.OnLeave = func(ev opentui.MouseEvent) {
+			leave(ev)
+			demo.Popover.Hide()
+		}
+		button.OnClick = func() {
+			button.TriggerConsoleLog()
+			timestamp := time.Now().Format("15:04:05")
+			demo.StatusText = fmt.Sprintf("Last triggered: %s #%d at %s", 
+				button.LogType, button.Clicks(), timestamp)
+		}
+	}
+	return demo, nil
+}
+
+// EnableFeatures detects the terminal capabilities and enables mouse tracking and
+// the kitty keyboard protocol if the terminal supports them. Replies must be passed to
+// ProcessCapabilityResponse while it waits.
+func (d *DemoState) EnableFeatures() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	caps, err := d.Renderer.DetectCapabilities(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to detect capabilities: %v", err)
+	}
+	if caps.SupportsMouse {
+		if err := d.Renderer.EnableMouse(true); err != nil {
+			return fmt.Errorf("failed to enable mouse: %v", err)
+		}
+	}
+	if caps.SupportsKittyKeyboard {
+		// Esc arrives unambiguously, and key releases are reported separately
+		flags := opentui.KittyDisambiguate | opentui.KittyReportEvents
+		if err := d.Renderer.PushKittyKeyboard(flags); err != nil {
+			return fmt.Errorf("failed to enable the kitty keyboard protocol: %v", err)
+		}
+	}
+	return nil
 }
 
 // Close cleans up the demo state
 func (d *DemoState) Close() {
 	if d.Renderer != nil {
+		// Restore the keyboard mode of the shell; Close would pop it as well
+		d.Renderer.PopKittyKeyboard()
 		d.Renderer.DisableMouse()
 		d.Renderer.ClearTerminal()
 		d.Renderer.Close()
 	}
+	if d.Log != nil {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(log.LstdFlags)
+		d.Log.Close()
+	}
 }
 
-// Render draws the demo interface
+// Render draws the demo interface and renders it to the screen
 func (d *DemoState) Render() error {
+	if err := d.Draw(d.Buffer); err != nil {
+		return err
+	}
+	return d.Renderer.Render(false)
+}
+
+// LayoutButtons places the button row five rows high on row 8, across the width of
+// the terminal minus a margin of two cells on each side
+func (d *DemoState) LayoutButtons(width uint32) {
+	area := opentui.Rect{
+		Position: opentui.Position{X: 2, Y: 8},
+		Size:     opentui.Size{Width: width - min(width, 4), Height: 5},
+	}
+	for i, rect := range buttonRow.Layout(area) {
+		d.Buttons[i].Rect = rect
+	}
+}
+
+// Draw draws the demo interface into the buffer
+func (d *DemoState) Draw(buffer *opentui.Buffer) error {
 	// Clear buffer
 	backgroundColor := opentui.NewRGBA(18.0/255, 22.0/255, 35.0/255, 1.0)
-	err := d.Buffer.Clear(backgroundColor)
+	err := buffer.Clear(backgroundColor)
 	if err != nil {
 		return fmt.Errorf("failed to clear buffer: %v", err)
 	}
 	
 	// Draw title
 	titleColor := opentui.NewRGBA(255.0/255, 215.0/255, 135.0/255, 1.0)
-	err = d.Buffer.DrawText("Console Logging Demo", 2, 1, titleColor, nil, opentui.AttrBold)
+	err = buffer.DrawText("Console Logging Demo", 2, 1, titleColor, nil, opentui.AttrBold)
 	if err != nil {
 		return fmt.Errorf("failed to draw title: %v", err)
 	}
@@ -271,99 +287,97 @@ func (d *DemoState) Render() error {
 	// Draw instructions
 	instrColor := opentui.NewRGBA(176.0/255, 196.0/255, 222.0/255, 1.0)
 	instructions := "Click buttons to trigger different console log levels • Press 'q' to quit • ESC to exit"
-	err = d.Buffer.DrawText(instructions, 2, 2, instrColor, nil, 0)
+	err = buffer.DrawText(instructions, 2, 2, instrColor, nil, 0)
 	if err != nil {
 		return fmt.Errorf("failed to draw instructions: %v", err)
 	}
 	
 	// Draw mouse position (for debugging)
 	mouseInfo := fmt.Sprintf("Mouse: (%d, %d)", d.MouseX, d.MouseY)
-	err = d.Buffer.DrawText(mouseInfo, 2, 3, opentui.Gray, nil, 0)
+	err = buffer.DrawText(mouseInfo, 2, 3, opentui.Gray, nil, 0)
 	if err != nil {
 		return fmt.Errorf("failed to draw mouse info: %v", err)
 	}
 	
 	// Draw status
 	statusColor := opentui.NewRGBA(144.0/255, 238.0/255, 144.0/255, 1.0)
-	err = d.Buffer.DrawText(d.StatusText, 2, 5, statusColor, nil, opentui.AttrItalic)
+	err = buffer.DrawText(d.StatusText, 2, 5, statusColor, nil, opentui.AttrItalic)
 	if err != nil {
 		return fmt.Errorf("failed to draw status: %v", err)
 	}
 	
-	// Draw buttons
+	// Draw buttons and register them and the log pane for hit testing, laid out for
+	// the current width
+	if width, err := buffer.Width(); err == nil {
+		d.LayoutButtons(width)
+		d.Log.Rect = opentui.Rect{
+			Position: opentui.Position{X: 2, Y: 15},
+			Size:     opentui.Size{Width: width - min(width, 4), Height: 6},
+		}
+	}
 	for _, button := range d.Buttons {
-		err = button.Render(d.Buffer)
+		err = button.Render(buffer)
 		if err != nil {
-			return fmt.Errorf("failed to render button %s: %v", button.ID, err)
+			return fmt.Errorf("failed to render button %s: %v", button.LogType, err)
 		}
 	}
-	
-	// Draw decorations
-	decorColor := opentui.NewRGBA(100.0/255, 120.0/255, 150.0/255, 120.0/255)
-	decoration := "✦ ✧ ✦ ✧ ✦ ✧ ✦ ✧ ✦ ✧ ✦ ✧ ✦ ✧ ✦ ✧ ✦"
-	err = d.Buffer.DrawText(decoration, 2, 16, decorColor, nil, 0)
+	err = d.Dispatcher.Register()
 	if err != nil {
-		return fmt.Errorf("failed to draw decoration: %v", err)
+		return fmt.Errorf("failed to register buttons: %v", err)
 	}
 	
-	// Draw console info
+	// Draw the log pane, following the output until scrolled up with the wheel
 	consoleInfoColor := opentui.NewRGBA(120.0/255, 140.0/255, 160.0/255, 200.0/255)
-	consoleInfo := "Console output appears in the terminal. Check your terminal for log messages."
-	err = d.Buffer.DrawText(consoleInfo, 2, 18, consoleInfoColor, nil, opentui.AttrItalic)
+	consoleInfo := "Console output (scroll with the mouse wheel):"
+	err = buffer.DrawText(consoleInfo, 2, 14, consoleInfoColor, nil, opentui.AttrItalic)
 	if err != nil {
 		return fmt.Errorf("failed to draw console info: %v", err)
 	}
+	err = d.Log.Render(buffer)
+	if err != nil {
+		return fmt.Errorf("failed to draw log: %v", err)
+	}
 	
 	// Draw button stats
 	statsY := uint32(22)
 	for i, button := range d.Buttons {
-		stats := fmt.Sprintf("%s: %d clicks", button.LogType, button.ClickCount)
+		stats := fmt.Sprintf("%s: %d clicks", button.LogType, button.Clicks())
 		statsColor := opentui.NewRGBA(200.0/255, 200.0/255, 200.0/255, 1.0)
-		err = d.Buffer.DrawText(stats, uint32(2+i*15), statsY, statsColor, nil, 0)
+		err = buffer.DrawText(stats, uint32(2+i*15), statsY, statsColor, nil, 0)
 		if err != nil {
 			return fmt.Errorf("failed to draw stats: %v", err)
 		}
 	}
 	
-	// Render to screen
-	return d.Renderer.Render(false)
-}
-
-// HandleMouseMove processes mouse movement
-func (d *DemoState) HandleMouseMove(x, y uint32) {
-	d.MouseX = x
-	d.MouseY = y
-	
-	// Update hover states
-	for _, button := range d.Buttons {
-		wasHovered := button.IsHovered
-		button.IsHovered = button.Contains(x, y)
-		
-		// Reset press state when mouse leaves
-		if wasHovered && !button.IsHovered {
-			button.IsPressed = false
-		}
+	// Draw CPU usage of the demo itself, sampled twice a second
+	if time.Since(d.lastCPU) >= 500*time.Millisecond {
+		d.CPU.Sample()
+		d.lastCPU = time.Now()
 	}
-}
-
-// HandleMouseClick processes mouse clicks
-func (d *DemoState) HandleMouseClick(x, y uint32) {
-	for _, button := range d.Buttons {
-		if button.Contains(x, y) {
-			button.Click()
-			timestamp := time.Now().Format("15:04:05")
-			d.StatusText = fmt.Sprintf("Last triggered: %s #%d at %s", 
-				button.LogType, button.ClickCount, timestamp)
-			break
-		}
+	cpuLabel := "CPU:  --%"
+	if n := len(d.CPU.Samples); n > 0 {
+		cpuLabel = fmt.Sprintf("CPU: %3.0f%%", d.CPU.Samples[n-1])
 	}
-}
-
-func min(a, b float32) float32 {
-	if a < b {
-		return a
+	statsColor := opentui.NewRGBA(200.0/255, 200.0/255, 200.0/255, 1.0)
+	err = buffer.DrawText(cpuLabel, 2, statsY+2, statsColor, nil, 0)
+	if err != nil {
+		return fmt.Errorf("failed to draw cpu label: %v", err)
 	}
-	return b
+	
+	lo, hi, limit := 0.0, 100.0, 50.0
+	err = buffer.DrawSparkline(13, statsY+2, 60, d.CPU.Samples, opentui.SparklineOptions{
+		Min:            &lo,
+		Max:            &hi,
+		Color:          opentui.NewRGBA(100.0/255, 180.0/255, 200.0/255, 1.0),
+		Threshold:      &limit,
+		ThresholdColor: opentui.NewRGBA(200.0/255, 120.0/255, 120.0/255, 1.0),
+		Window:         true,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to draw cpu sparkline: %v", err)
+	}
+	
+	return nil
 }
 
 func main() {
@@ -375,8 +389,8 @@ func main() {
 	fmt.Println("  q/Q: Quit demo")
 	fmt.Println("  ESC: Exit")
 	fmt.Println()
-	fmt.Println("Mouse support is enabled - try clicking in supported terminals!")
-	fmt.Println("Log output will appear in this terminal window.")
+	fmt.Println("Mouse support is enabled if the terminal reports it - try clicking the buttons!")
+	fmt.Println("Log output will appear in a pane under the buttons.")
 	fmt.Println()
 	
 	// Try to set terminal to raw mode for better input handling
@@ -399,56 +413,52 @@ func main() {
 	}
 	defer input.Close()
 	
+	// Replies to the capability probes arrive on stdin between the keys
+	input.OnResponse = func(data []byte) {
+		demo.Renderer.ProcessCapabilityResponse(data)
+	}
+	
 	// Print initial console message
 	fmt.Println("✨ Console Demo initialized! Use keyboard controls or try clicking the buttons.")
 	fmt.Println()
 	
-	// Channel for input events
-	inputChan := make(chan rune, 1)
+	// Channel for input events, key and mouse events for the dispatcher
+	events := make(chan interface{}, 16)
+	input.OnMouse = func(data []byte) {
+		if ev, _, ok := demo.Renderer.ParseMouseEvent(data); ok {
+			events <- ev
+		}
+	}
 	
 	// Start input goroutine
 	go func() {
+		defer close(events)
 		for {
 			key, err := input.ReadKey()
 			if err != nil {
 				return
 			}
-			select {
-			case inputChan <- key:
-			default:
-				// Buffer full, skip
-			}
+			events <- opentui.KeyEvent{Key: key}
 		}
 	}()
 	
-	// Main demo loop
-	lastRender := time.Now()
-	renderInterval := 50 * time.Millisecond
+	// Detect capabilities before enabling mouse tracking
+	if err := demo.EnableFeatures(); err != nil {
+		log.Printf("Failed to enable terminal features: %v", err)
+	}
 	
-	for demo.Running {
-		// Handle input
-		select {
-		case key := <-inputChan:
-			if !handleInput(demo, key) {
-				demo.Running = false
-				continue
-			}
-		default:
-			// No input available
+	// Main demo loop, handling input before each frame
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err = demo.Renderer.RunLoop(ctx, 20, func(dt time.Duration, buffer *opentui.Buffer) error {
+		if !demo.Dispatcher.DispatchPending(events) || !demo.Running {
+			cancel()
+			return nil
 		}
-		
-		// Render at regular intervals
-		if time.Since(lastRender) >= renderInterval {
-			err := demo.Render()
-			if err != nil {
-				log.Printf("Render error: %v", err)
-				break
-			}
-			lastRender = time.Now()
-		}
-		
-		// Small sleep to prevent busy waiting
-		time.Sleep(10 * time.Millisecond)
+		return demo.Draw(buffer)
+	})
+	if err != nil {
+		log.Printf("Render error: %v", err)
 	}
 	
 	fmt.Println("\n🎉 Console Demo completed!")
@@ -466,31 +476,31 @@ func handleInput(demo *DemoState, key rune) bool {
 		if len(demo.Buttons) > 0 {
 			demo.Buttons[0].Click()
 			demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-				demo.Buttons[0].LogType, demo.Buttons[0].ClickCount)
+				demo.Buttons[0].LogType, demo.Buttons[0].Clicks())
 		}
 	case '2':
 		if len(demo.Buttons) > 1 {
 			demo.Buttons[1].Click()
 			demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-				demo.Buttons[1].LogType, demo.Buttons[1].ClickCount)
+				demo.Buttons[1].LogType, demo.Buttons[1].Clicks())
 		}
 	case '3':
 		if len(demo.Buttons) > 2 {
 			demo.Buttons[2].Click()
 			demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-				demo.Buttons[2].LogType, demo.Buttons[2].ClickCount)
+				demo.Buttons[2].LogType, demo.Buttons[2].Clicks())
 		}
 	case '4':
 		if len(demo.Buttons) > 3 {
 			demo.Buttons[3].Click()
 			demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-				demo.Buttons[3].LogType, demo.Buttons[3].ClickCount)
+				demo.Buttons[3].LogType, demo.Buttons[3].Clicks())
 		}
 	case '5':
 		if len(demo.Buttons) > 4 {
 			demo.Buttons[4].Click()
 			demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-				demo.Buttons[4].LogType, demo.Buttons[4].ClickCount)
+				demo.Buttons[4].LogType, demo.Buttons[4].Clicks())
 		}
 	}
 	return true
@@ -528,31 +538,31 @@ func runSimpleDemo(demo *DemoState) {
 			if len(demo.Buttons) > 0 {
 				demo.Buttons[0].Click()
 				demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-					demo.Buttons[0].LogType, demo.Buttons[0].ClickCount)
+					demo.Buttons[0].LogType, demo.Buttons[0].Clicks())
 			}
 		case "2":
 			if len(demo.Buttons) > 1 {
 				demo.Buttons[1].Click() 
 				demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-					demo.Buttons[1].LogType, demo.Buttons[1].ClickCount)
+					demo.Buttons[1].LogType, demo.Buttons[1].Clicks())
 			}
 		case "3":
 			if len(demo.Buttons) > 2 {
 				demo.Buttons[2].Click()
 				demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-					demo.Buttons[2].LogType, demo.Buttons[2].ClickCount)
+					demo.Buttons[2].LogType, demo.Buttons[2].Clicks())
 			}
 		case "4":
 			if len(demo.Buttons) > 3 {
 				demo.Buttons[3].Click()
 				demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-					demo.Buttons[3].LogType, demo.Buttons[3].ClickCount)
+					demo.Buttons[3].LogType, demo.Buttons[3].Clicks())
 			}
 		case "5":
 			if len(demo.Buttons) > 4 {
 				demo.Buttons[4].Click()
 				demo.StatusText = fmt.Sprintf("Triggered: %s #%d", 
-					demo.Buttons[4].LogType, demo.Buttons[4].ClickCount)
+					demo.Buttons[4].LogType, demo.Buttons[4].Clicks())
 			}
 		default:
 			fmt.Println("Unknown command. Try 1-5 for buttons, or 'q' to quit.")
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"time"
+
+	opentui "github.com/sst/opentui/packages/go"
+)
+
+// Usage: go run ./examples/image [picture.png]
+func main() {
+	img, name, err := loadImage()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load image: %v", err))
+	}
+
+	renderer := opentui.NewRenderer(80, 24)
+	if renderer == nil {
+		panic("Failed to create renderer - make sure the OpenTUI library is available")
+	}
+	defer renderer.Close()
+
+	background := opentui.NewRGB(0.1, 0.1, 0.15)
+	if err := renderer.ClearTerminal(); err != nil {
+		panic(fmt.Sprintf("Failed to clear terminal: %v", err))
+	}
+
+	buffer, err := renderer.GetNextBuffer()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to get buffer: %v", err))
+	}
+	if err := buffer.Clear(background); err != nil {
+		panic(fmt.Sprintf("Failed to clear buffer: %v", err))
+	}
+
+	// The image on the left, scaled into 40x20 cells
+	err = buffer.DrawImage(2, 2, img, opentui.ImageOptions{
+		Width:  40,
+		Height: 20,
+		Mode:   opentui.BlockQuadrant,
+		Dither: opentui.DitherFloydSteinberg,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("Failed to draw image: %v", err))
+	}
+
+	// A description on the right
+	bounds := img.Bounds()
+	lines := []string{
+		name,
+		fmt.Sprintf("%dx%d pixels", bounds.Dx(), bounds.Dy()),
+		"Quadrant blocks",
+		"Floyd-Steinberg dithering",
+	}
+	for i, line := range lines {
+		attrs := opentui.Attributes(0)
+		if i == 0 {
+			attrs = opentui.AttrBold
+		}
+		if err := buffer.DrawText(line, 46, uint32(3+i*2), opentui.White, nil, attrs); err != nil {
+			panic(fmt.Sprintf("Failed to draw text: %v", err))
+		}
+	}
+
+	if err := renderer.Render(true); err != nil {
+		panic(fmt.Sprintf("Failed to render: %v", err))
+	}
+
+	time.Sleep(10 * time.Second)
+
+	if err := renderer.ClearTerminal(); err != nil {
+		fmt.Printf("Warning: Failed to clear terminal on exit: %v\n", err)
+	}
+}
+
+// loadImage decodes the PNG or JPEG given on the command line,
+// or generates a gradient disc when no file is given.
+func loadImage() (image.Image, string, error) {
+	if len(os.Args) < 2 {
+		return gradientDisc(160), "Generated gradient disc", nil
+	}
+
+	file, err := os.Open(os.Args[1])
+	if err != nil {
+		return nil, "", err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, os.Args[1], err
+}
+
+// gradientDisc draws a color gradient disc on a transparent background.
+func gradientDisc(size int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	center := float64(size) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			dx, dy := float64(x)-center, float64(y)-center
+			if dx*dx+dy*dy > center*center {
+				continue
+			}
+			img.Set(x, y, color.NRGBA{
+				R: uint8(255 * float64(x) / float64(size)),
+				G: uint8(255 * float64(y) / float64(size)),
+				B: 200,
+				A: 255,
+			})
+		}
+	}
+	return img
+}
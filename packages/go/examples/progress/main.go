@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	opentui "github.com/sst/opentui/packages/go"
+	"github.com/sst/opentui/packages/go/widgets"
+)
+
+// updater applies a change to a progress bar while the frame loop isn't drawing it
+type updater func(change func(*widgets.Progress))
+
+// job is a simulated task reporting its progress through update
+type job func(update updater)
+
+// task is a job with the progress bar it updates from its own goroutine
+type task struct {
+	name string
+	bar  *widgets.Progress
+	run  job
+}
+
+// Usage: go run ./examples/progress
+func main() {
+	renderer, err := opentui.NewRendererAuto()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create renderer: %v", err))
+	}
+	defer renderer.Close()
+
+	if err := renderer.ClearTerminal(); err != nil {
+		panic(fmt.Sprintf("Failed to clear terminal: %v", err))
+	}
+
+	// The workers update the bars while the frame loop draws them
+	var mu sync.Mutex
+	update := func(bar *widgets.Progress) updater {
+		return func(change func(*widgets.Progress)) {
+			mu.Lock()
+			defer mu.Unlock()
+			change(bar)
+		}
+	}
+
+	blocks := widgets.DefaultProgressStyle
+	ascii := blocks
+	ascii.Bar = opentui.ProgressASCII
+	braille := blocks
+	braille.Bar = opentui.ProgressBraille
+
+	tasks := []*task{
+		{name: "Download", run: steps(100, 20*time.Millisecond, nil, nil)},
+		{name: "Files", run: steps(80, 90*time.Millisecond, func(bar *widgets.Progress, done int) {
+			bar.SetLabel(fmt.Sprintf("%d/80 files", done))
+		}, nil)},
+		{name: "Indexing", run: indeterminate(4 * time.Second)},
+		{name: "Upload", run: steps(100, 40*time.Millisecond, nil, func(done int) bool { return done == 70 })},
+		{name: "Tiny", run: steps(50, 60*time.Millisecond, nil, nil)},
+	}
+	styles := []widgets.ProgressStyle{blocks, ascii, blocks, braille, blocks}
+	widths := []uint32{40, 40, 40, 40, 5}
+	for i, t := range tasks {
+		rect := opentui.Rect{Position: opentui.Position{X: 14, Y: int32(3 + 2*i)}, Size: opentui.Size{Width: widths[i], Height: 1}}
+		t.bar = widgets.NewProgress(rect, styles[i])
+		go t.run(update(t.bar))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	background := opentui.NewRGB(0.1, 0.1, 0.15)
+	err = renderer.RunLoop(ctx, 30, func(dt time.Duration, buf *opentui.Buffer) error {
+		mu.Lock()
+		defer mu.Unlock()
+		buf.Clear(background)
+		buf.DrawText("OpenTUI Go Progress Bars", 2, 1, opentui.Yellow, nil, opentui.AttrBold)
+		for _, t := range tasks {
+			t.bar.Tick(dt)
+			buf.DrawText(t.name, 2, uint32(t.bar.Rect.Y), opentui.White, nil, 0)
+			if err := t.bar.Render(buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Render loop stopped: %v\n", err)
+	}
+
+	if err := renderer.ClearTerminal(); err != nil {
+		fmt.Printf("Warning: Failed to clear terminal on exit: %v\n", err)
+	}
+}
+
+// steps returns a job advancing a bar by one of total steps every interval. label
+// updates the label after each step, and failAt stops the job with a failure.
+func steps(total int, interval time.Duration, label func(*widgets.Progress, int), failAt func(int) bool) job {
+	return func(update updater) {
+		for done := 1; done <= total; done++ {
+			time.Sleep(interval)
+			failed := failAt != nil && failAt(done)
+			update(func(bar *widgets.Progress) {
+				bar.SetFraction(float64(done) / float64(total))
+				if label != nil {
+					label(bar, done)
+				}
+				if failed {
+					bar.SetLabel("failed")
+					bar.Fail()
+				}
+			})
+			if failed {
+				return
+			}
+		}
+		update(func(bar *widgets.Progress) { bar.Succeed() })
+	}
+}
+
+// indeterminate returns a job of unknown length that ends after duration.
+func indeterminate(duration time.Duration) job {
+	return func(update updater) {
+		update(func(bar *widgets.Progress) {
+			bar.SetIndeterminate()
+			bar.SetLabel("scanning")
+		})
+		time.Sleep(duration)
+		update(func(bar *widgets.Progress) {
+			bar.SetLabel("")
+			bar.Succeed()
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	opentui "github.com/sst/opentui/packages/go"
+	"github.com/sst/opentui/packages/go/widgets"
+)
+
+// arrowKeys maps the final byte of the arrow key sequences (ESC [ A to D) to keys
+var arrowKeys = map[byte]rune{'A': opentui.KeyUp, 'B': opentui.KeyDown, 'C': opentui.KeyRight, 'D': opentui.KeyLeft}
+
+// Usage: go run ./examples/settings
+// Tab moves the focus, Space toggles, the arrow keys pick a theme, Enter saves and q quits.
+func main() {
+	renderer, err := opentui.NewRendererAuto()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to create renderer: %v", err))
+	}
+	defer renderer.Close()
+
+	if err := setTerminalRaw(true); err != nil {
+		panic(fmt.Sprintf("Failed to set the terminal to raw mode: %v", err))
+	}
+	defer setTerminalRaw(false)
+	if err := renderer.EnableMouse(false); err != nil {
+		panic(fmt.Sprintf("Failed to enable the mouse: %v", err))
+	}
+
+	status := "Nothing saved yet"
+	notifications := widgets.NewCheckbox(1, opentui.Position{X: 4, Y: 4}, "Desktop notifications")
+	sounds := widgets.NewCheckbox(2, opentui.Position{X: 4, Y: 5}, "Play sounds")
+	notifications.SetChecked(true)
+	// Sounds only play with notifications on
+	notifications.OnChange = func(checked bool) { sounds.Disabled = !checked }
+
+	theme := widgets.NewRadioGroup(3, opentui.Position{X: 4, Y: 8}, "Light", "Dark", "System")
+	theme.Select(2)
+	if os.Getenv("TERM") == "linux" {
+		// The Linux console font lacks the bullet
+		theme.Glyphs = widgets.ASCIIRadioGlyphs
+	}
+
+	saveRect := opentui.Rect{Position: opentui.Position{X: 4, Y: 12}, Size: opentui.Size{Width: 12, Height: 3}}
+	save := widgets.NewButton(4, saveRect, "SAVE", widgets.NewStyle(opentui.Blue))
+	save.OnClick = func() {
+		status = fmt.Sprintf("Saved: notifications %v, sounds %v, theme %s",
+			notifications.Checked(), sounds.Checked(), theme.Options()[theme.Selected()])
+	}
+
+	// Tab cycles the keyboard focus through the widgets in this order, skipping sounds
+	// while it's disabled
+	focus := widgets.NewFocusManager(notifications, sounds, theme, save)
+
+	input := make(chan []byte)
+	go func() {
+		for {
+			data := make([]byte, 64)
+			n, err := os.Stdin.Read(data)
+			if err != nil {
+				close(input)
+				return
+			}
+			input <- data[:n]
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	background := opentui.NewRGB(0.1, 0.1, 0.15)
+	err = renderer.RunLoop(ctx, 30, func(dt time.Duration, buf *opentui.Buffer) error {
+		for pending := true; pending; {
+			select {
+			case data, ok := <-input:
+				if !ok {
+					cancel()
+					return nil
+				}
+				for len(data) > 0 {
+					if ev, n, ok := renderer.ParseMouseEvent(data); ok {
+						notifications.HandleMouse(ev)
+						sounds.HandleMouse(ev)
+						theme.HandleMouse(ev)
+						save.HandleMouse(ev)
+						data = data[n:]
+						continue
+					}
+					key, n := parseKey(data)
+					data = data[n:]
+					if key == 'q' {
+						cancel()
+						return nil
+					}
+					focus.HandleKey(opentui.KeyEvent{Key: key})
+				}
+			default:
+				pending = false
+			}
+		}
+
+		buf.Clear(background)
+		buf.DrawText("Settings", 2, 1, opentui.Yellow, nil, opentui.AttrBold)
+		buf.DrawText("Notifications", 2, 3, opentui.Gray, nil, 0)
+		buf.DrawText("Theme", 2, 7, opentui.Gray, nil, 0)
+		for _, render := range []func(*opentui.Buffer) error{notifications.Render, sounds.Render, theme.Render, save.Render} {
+			if err := render(buf); err != nil {
+				return err
+			}
+		}
+		buf.DrawText(status, 2, 16, opentui.White, nil, 0)
+		buf.DrawText("Tab: next  Space: toggle  ←→↑↓: theme  Enter: save  q: quit", 2, 18, opentui.Gray, nil, 0)
+
+		notifications.Register(renderer)
+		sounds.Register(renderer)
+		theme.Register(renderer)
+		return save.Register(renderer)
+	})
+	if err != nil {
+		fmt.Printf("Render loop stopped: %v\n", err)
+	}
+}
+
+// parseKey decodes the key at the start of data: an arrow key sequence or a single
+// byte. Returns the key and the number of bytes it takes.
+func parseKey(data []byte) (rune, int) {
+	if len(data) >= 3 && data[0] == 0x1b && data[1] == '[' {
+		if key, ok := arrowKeys[data[2]]; ok {
+			return key, 3
+		}
+	}
+	if data[0] == '\n' {
+		return opentui.KeyEnter, 1
+	}
+	return rune(data[0]), 1
+}
+
+// setTerminalRaw turns off line buffering and echo, or back on (Unix only).
+func setTerminalRaw(raw bool) error {
+	args := []string{"echo", "-cbreak"}
+	if raw {
+		args = []string{"-echo", "cbreak"}
+	}
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
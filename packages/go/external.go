@@ -0,0 +1,88 @@
+package opentui
+
+import (
+	"context"
+	"os"
+	"os/exec"
+)
+
+// RunExternal hands the terminal to cmd, for example $EDITOR or a pager, and takes it
+// back when cmd exits: like Suspend, mouse tracking and the Kitty keyboard protocol
+// are turned off, the alternate screen is left, the cursor is shown and stdin is
+// switched to cooked mode, and afterwards everything that was on is turned back on and
+// the next Render redraws every cell. Render draws nothing while cmd runs. Standard
+// streams cmd has none set for are connected to stdin, the renderer output and stderr.
+// Cancelling ctx kills cmd. The terminal is taken back even if cmd fails, whose error
+// is returned then.
+//
+// A goroutine reading stdin keeps doing so while cmd runs, so the application has to
+// stop reading keyboard input first; the same goes for a renderer Input reading stdin.
+func (r *Renderer) RunExternal(ctx context.Context, cmd *exec.Cmd) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+	if cmd.Stdout == nil {
+		if r.output != nil {
+			cmd.Stdout = r.output
+		} else {
+			cmd.Stdout = r.outputFile()
+		}
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+	if r.headless {
+		// Nothing on the terminal belongs to the renderer
+		return runExternal(ctx, cmd)
+	}
+
+	r.suspend.mu.Lock()
+	if r.suspend.suspended {
+		r.suspend.mu.Unlock()
+		return newError("renderer is suspended")
+	}
+	if err := r.releaseTerminal(); err != nil {
+		r.suspend.mu.Unlock()
+		return err
+	}
+	r.suspend.suspended = true
+	r.suspend.external = true
+	r.suspend.mu.Unlock()
+
+	runErr := runExternal(ctx, cmd)
+
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	r.suspend.external = false
+	if r.suspend.restore != nil {
+		if err := r.suspend.restore(); err != nil {
+			return err
+		}
+		r.suspend.restore = nil
+	}
+	r.suspend.suspended = false
+	if err := r.reclaimTerminal(); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// runExternal runs cmd until it exits or ctx is done, which kills it.
+func runExternal(ctx context.Context, cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		return ctx.Err()
+	}
+}
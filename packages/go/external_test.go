@@ -0,0 +1,105 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestRunExternal(t *testing.T) {
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not available")
+	}
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	var stops, cooks, restores int
+	renderer.suspend.stop = func() error { stops++; return nil }
+	renderer.suspend.cook = func() (func() error, error) {
+		cooks++
+		return func() error { restores++; return nil }, nil
+	}
+
+	renderer.EnableMouse(false)
+	renderer.EnableKittyKeyboard(3)
+	renderer.EnterAlternateScreen()
+	renderer.forceRender = false
+	out.Reset()
+
+	if err := renderer.RunExternal(context.Background(), exec.Command("true")); err != nil {
+		t.Fatalf("RunExternal failed: %v", err)
+	}
+	if out.String() != leaveAltScreen+showCursor+enterAltScreen {
+		t.Errorf("RunExternal wrote %q", out.String())
+	}
+	if cooks != 1 || restores != 1 || stops != 0 {
+		t.Errorf("%d cooks, %d restores, %d stops", cooks, restores, stops)
+	}
+	if renderer.Suspended() || renderer.suspend.external || !renderer.InAlternateScreen() || !renderer.forceRender {
+		t.Error("the terminal should be set up again with a full redraw")
+	}
+	if !renderer.mouseEnabled || renderer.mouseMovement || !renderer.kittyEnabled || renderer.kittyFlags != 3 {
+		t.Error("mouse tracking and the Kitty keyboard protocol should be turned back on as they were")
+	}
+
+	// A failing program still gives the terminal back
+	renderer.LeaveAlternateScreen()
+	out.Reset()
+	if err := renderer.RunExternal(context.Background(), exec.Command("false")); err == nil {
+		t.Error("the exit status of the program should be returned")
+	}
+	if out.String() != showCursor || renderer.Suspended() || renderer.InAlternateScreen() || restores != 2 {
+		t.Errorf("after a failing program: wrote %q, suspended %v", out.String(), renderer.Suspended())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := renderer.RunExternal(ctx, exec.Command("sleep", "10")); err != context.Canceled {
+		t.Errorf("cancelled RunExternal returned %v", err)
+	}
+
+	// Ctrl+Z while the program runs stops the process without touching the terminal
+	renderer.suspend.suspended, renderer.suspend.external = true, true
+	out.Reset()
+	if err := renderer.Suspend(); err != nil || stops != 1 || out.Len() != 0 {
+		t.Errorf("Suspend during RunExternal: %v, %d stops, wrote %q", err, stops, out.String())
+	}
+	if err := renderer.Resume(); err != nil || !renderer.Suspended() || out.Len() != 0 {
+		t.Error("Resume during RunExternal should leave the terminal to the program")
+	}
+}
+
+func TestRunExternalHeadless(t *testing.T) {
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not available")
+	}
+	outputIsTerminal = isTerminal
+	defer func() { outputIsTerminal = func(*os.File) bool { return true } }()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer reader.Close()
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: writer})
+	if renderer == nil {
+		writer.Close()
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	cooks := 0
+	renderer.suspend.cook = func() (func() error, error) { cooks++; return nil, nil }
+	if err := renderer.RunExternal(context.Background(), exec.Command("true")); err != nil {
+		t.Fatalf("RunExternal failed: %v", err)
+	}
+	if cooks != 0 || renderer.Suspended() {
+		t.Error("a headless renderer has no terminal to hand over")
+	}
+}
@@ -0,0 +1,88 @@
+package opentui
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"testing"
+)
+
+// drawDeep draws after growing the stack by depth frames, so that the goroutine
+// stack gets copied to a new place while colors are being passed around.
+func drawDeep(depth int, draw func()) {
+	var pad [256]byte
+	if depth > 0 {
+		drawDeep(depth-1, draw)
+	} else {
+		draw()
+	}
+	runtime.KeepAlive(pad)
+}
+
+// TestColorsUnderGCPressure draws with colors computed on the fly while the GC runs
+// all the time and stacks grow and move, and checks that every color arrives intact.
+func TestColorsUnderGCPressure(t *testing.T) {
+	buffer := newTestBuffer(t, 40, 4)
+	text := NewTextBuffer(64, WidthMethodUnicode)
+	if text == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer text.Close()
+
+	defer debug.SetGCPercent(debug.SetGCPercent(1))
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				runtime.GC()
+			}
+		}
+	}()
+	defer func() {
+		close(done)
+		wg.Wait()
+	}()
+
+	iterations := 2000
+	if testing.Short() {
+		iterations = 200
+	}
+	for i := 0; i < iterations; i++ {
+		fg := NewRGB(float32(i%256)/255, 0.5, float32(i%7)/7)
+		bg := NewRGB(0.25, float32(i%128)/127, float32(i%3)/3)
+		x := uint32(i % 30)
+		depth := i % 64
+
+		var cells [3]Cell
+		drawDeep(depth, func() {
+			buffer.FillRect(0, 0, 40, 1, bg)
+			buffer.DrawText("ab", x, 1, fg, &bg, 0)
+			buffer.SetCellWithAlphaBlending(x, 2, 'c', fg, bg, 0)
+			cells[0], _ = buffer.GetCell(x, 0)
+			cells[1], _ = buffer.GetCell(x+1, 1)
+			cells[2], _ = buffer.GetCell(x, 2)
+		})
+		if cells[0].Background != bg || cells[1].Foreground != fg || cells[1].Background != bg ||
+			cells[2].Foreground != fg || cells[2].Background != bg {
+			t.Fatalf("iteration %d: colors %+v, want fg %+v and bg %+v", i, cells, fg, bg)
+		}
+
+		drawDeep(depth, func() {
+			text.Reset()
+			text.WriteChunk(TextChunk{Text: "x", Foreground: &fg, Background: &bg})
+		})
+		da, err := text.GetDirectAccess()
+		if err != nil {
+			t.Fatalf("GetDirectAccess failed: %v", err)
+		}
+		if da.Foreground[0] != fg || da.Background[0] != bg {
+			t.Fatalf("iteration %d: text colors %+v on %+v, want %+v on %+v", i, da.Foreground[0], da.Background[0], fg, bg)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package opentui
+
+import "testing"
+
+func TestLerpLinear(t *testing.T) {
+	if got := lerpLinear(Black, White, 0); got != Black {
+		t.Errorf("lerpLinear at 0 = %+v, want black", got)
+	}
+	if got := lerpLinear(Black, White, 1); !closeColor(got, White) {
+		t.Errorf("lerpLinear at 1 = %+v, want white", got)
+	}
+
+	// Half way in linear light is brighter than half way in sRGB
+	mid := lerpLinear(Black, White, 0.5)
+	if !closeColor(mid, NewRGB(0.7354, 0.7354, 0.7354)) {
+		t.Errorf("lerpLinear midpoint = %+v, want ~0.735 gray", mid)
+	}
+}
+
+func TestGradientAt(t *testing.T) {
+	stops := []GradientStop{{0.25, Red}, {0.5, Green}, {1, Blue}}
+	tests := []struct {
+		pos  float32
+		want RGBA
+	}{
+		{0, Red},
+		{0.25, Red},
+		{0.5, Green},
+		{1, Blue},
+		{2, Blue},
+	}
+	gradient := NewGradient(stops[2], stops[0], stops[1])
+	for _, tt := range tests {
+		if got := gradient.At(tt.pos); !closeColor(got, tt.want) {
+			t.Errorf("At(%v) = %+v, want %+v", tt.pos, got, tt.want)
+		}
+	}
+
+	// Alpha is interpolated too, and sRGB interpolation is available
+	fade := NewGradient(GradientStop{0, White}, GradientStop{1, NewRGBA(0, 0, 0, 0)})
+	fade.Space = ColorSpaceSRGB
+	if got := fade.At(0.5); !closeColor(got, NewRGBA(0.5, 0.5, 0.5, 0.5)) {
+		t.Errorf("sRGB At(0.5) = %+v, want half gray at half alpha", got)
+	}
+	if got := NewGradient().At(0.5); got != Transparent {
+		t.Errorf("empty gradient At = %+v, want transparent", got)
+	}
+}
+
+func TestLerpMix(t *testing.T) {
+	a, b := NewRGBA(0, 0.2, 1, 1), NewRGBA(1, 0.6, 0, 0)
+	if got := Lerp(a, b, 0.25); !closeColor(got, NewRGBA(0.25, 0.3, 0.75, 0.75)) {
+		t.Errorf("Lerp(0.25) = %+v", got)
+	}
+	if got := Lerp(a, b, -1); got != a {
+		t.Errorf("Lerp should clamp t below 0, got %+v", got)
+	}
+	if got := Lerp(a, b, 2); got != b {
+		t.Errorf("Lerp should clamp t above 1, got %+v", got)
+	}
+	if got := Mix(Black, White, 0.5); !closeColor(got, lerpLinear(Black, White, 0.5)) {
+		t.Errorf("Mix should blend in linear light, got %+v", got)
+	}
+}
+
+func TestFillRectGradient(t *testing.T) {
+	buffer := newTestBuffer(t, 5, 3)
+
+	if err := buffer.FillRectGradient(0, 0, 5, 3, Black, White, GradientHorizontal); err != nil {
+		t.Fatalf("FillRectGradient failed: %v", err)
+	}
+
+	da, _ := buffer.GetDirectAccess()
+	for y := uint32(0); y < 3; y++ {
+		left, _ := da.GetCell(0, y)
+		right, _ := da.GetCell(4, y)
+		if !closeColor(left.Background, Black) || !closeColor(right.Background, White) {
+			t.Errorf("row %d: edges = %+v / %+v, want black / white", y, left.Background, right.Background)
+		}
+	}
+	mid, _ := da.GetCell(2, 1)
+	if !closeColor(mid.Background, lerpLinear(Black, White, 0.5)) {
+		t.Errorf("middle cell = %+v, want linear midpoint", mid.Background)
+	}
+
+	err := buffer.FillRectGradientStops(0, 0, 5, 3, []GradientStop{{1, Blue}, {0, Red}}, GradientVertical)
+	if err != nil {
+		t.Fatalf("FillRectGradientStops failed: %v", err)
+	}
+	top, _ := da.GetCell(3, 0)
+	bottom, _ := da.GetCell(3, 2)
+	if !closeColor(top.Background, Red) || !closeColor(bottom.Background, Blue) {
+		t.Errorf("vertical gradient edges = %+v / %+v, want red / blue", top.Background, bottom.Background)
+	}
+
+	if err := buffer.FillRectGradientStops(0, 0, 5, 3, nil, GradientDiagonal); err == nil {
+		t.Error("FillRectGradientStops should reject an empty stop list")
+	}
+}
@@ -0,0 +1,255 @@
+package opentui
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Grapheme cluster break properties from UAX #29
+type graphemeProperty uint8
+
+const (
+	gbOther graphemeProperty = iota
+	gbCR
+	gbLF
+	gbControl
+	gbExtend
+	gbZWJ
+	gbRegionalIndicator
+	gbPrepend
+	gbSpacingMark
+	gbL
+	gbV
+	gbT
+	gbLV
+	gbLVT
+)
+
+// extendedPictographic approximates the Extended_Pictographic property with the blocks
+// that hold emoji. Emoji modifiers are excluded since they extend the preceding emoji.
+var extendedPictographic = [][2]rune{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x2199}, {0x21A9, 0x21AA},
+	{0x231A, 0x231B}, {0x2328, 0x2328}, {0x23CF, 0x23CF}, {0x23E9, 0x23F3},
+	{0x23F8, 0x23FA}, {0x24C2, 0x24C2}, {0x25AA, 0x25AB}, {0x25B6, 0x25B6},
+	{0x25C0, 0x25C0}, {0x25FB, 0x25FE}, {0x2600, 0x27BF}, {0x2934, 0x2935},
+	{0x2B05, 0x2B07}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x3030, 0x3030}, {0x303D, 0x303D}, {0x3297, 0x3297}, {0x3299, 0x3299},
+	{0x1F000, 0x1F0FF}, {0x1F10D, 0x1F10F}, {0x1F12F, 0x1F12F}, {0x1F16C, 0x1F171},
+	{0x1F17E, 0x1F17F}, {0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F1AD, 0x1F1E5},
+	{0x1F201, 0x1F20F}, {0x1F21A, 0x1F21A}, {0x1F22F, 0x1F22F}, {0x1F232, 0x1F23A},
+	{0x1F23C, 0x1F23F}, {0x1F249, 0x1F3FA}, {0x1F400, 0x1F53D}, {0x1F546, 0x1F64F},
+	{0x1F680, 0x1F6FF}, {0x1F774, 0x1F77F}, {0x1F7D5, 0x1F7FF}, {0x1F80C, 0x1F80F},
+	{0x1F848, 0x1F84F}, {0x1F85A, 0x1F85F}, {0x1F888, 0x1F88F}, {0x1F8AE, 0x1F8FF},
+	{0x1F90C, 0x1F93A}, {0x1F93C, 0x1F945}, {0x1F947, 0x1FAFF}, {0x1FC00, 0x1FFFD},
+}
+
+func inRanges(r rune, ranges [][2]rune) bool {
+	lo, hi := 0, len(ranges)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < ranges[mid][0]:
+			hi = mid - 1
+		case r > ranges[mid][1]:
+			lo = mid + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+func isExtendedPictographic(r rune) bool {
+	return r >= 0xA9 && inRanges(r, extendedPictographic)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+// graphemePropertyOf classifies a rune for grapheme cluster breaking.
+func graphemePropertyOf(r rune) graphemeProperty {
+	switch {
+	case r == '\r':
+		return gbCR
+	case r == '\n':
+		return gbLF
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return gbControl
+	case r < 0x300:
+		if r == 0xAD {
+			return gbControl
+		}
+		return gbOther
+	case r == 0x200D:
+		return gbZWJ
+	case r == 0x200C, r >= 0x1F3FB && r <= 0x1F3FF, r >= 0xE0020 && r <= 0xE007F:
+		// Zero width non-joiner, emoji modifiers and tags
+		return gbExtend
+	case isRegionalIndicator(r):
+		return gbRegionalIndicator
+	case (r >= 0x600 && r <= 0x605) || r == 0x6DD || r == 0x70F || r == 0x8E2 || r == 0x110BD || r == 0x110CD:
+		return gbPrepend
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return gbL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return gbV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return gbT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return gbLV
+		}
+		return gbLVT
+	case unicode.In(r, unicode.Mn, unicode.Me):
+		return gbExtend
+	case unicode.Is(unicode.Mc, r):
+		return gbSpacingMark
+	case unicode.In(r, unicode.Cf, unicode.Zl, unicode.Zp):
+		return gbControl
+	}
+	return gbOther
+}
+
+// GraphemeIterator walks the user-perceived characters (extended grapheme clusters) of a string.
+//
+//	for g := opentui.Graphemes(text); g.Next(); {
+//		fmt.Println(g.Str(), g.Width())
+//	}
+type GraphemeIterator struct {
+	text       string
+	start, end int
+}
+
+// Graphemes returns an iterator over the grapheme clusters of text, following the
+// extended grapheme cluster rules of UAX #29. Emoji sequences joined with ZWJ,
+// emoji with skin tone modifiers, flags and characters with combining marks each
+// form a single cluster.
+func Graphemes(text string) *GraphemeIterator {
+	return &GraphemeIterator{text: text}
+}
+
+// Next advances to the next cluster and reports whether there is one.
+func (g *GraphemeIterator) Next() bool {
+	g.start = g.end
+	if g.start >= len(g.text) {
+		return false
+	}
+	g.end = g.start + graphemeLength(g.text[g.start:])
+	return true
+}
+
+// Str returns the current cluster.
+func (g *GraphemeIterator) Str() string {
+	return g.text[g.start:g.end]
+}
+
+// Offset returns the byte offset of the current cluster in the text.
+func (g *GraphemeIterator) Offset() int {
+	return g.start
+}
+
+// Width returns the number of terminal cells the current cluster occupies.
+func (g *GraphemeIterator) Width() int {
+	return clusterWidth(g.Str())
+}
+
+// graphemeLength returns the length in bytes of the first grapheme cluster of text.
+func graphemeLength(text string) int {
+	prev, size := utf8.DecodeRuneInString(text)
+	prevProp := graphemePropertyOf(prev)
+
+	// State for emoji ZWJ sequences (GB11) and flag pairs (GB12, GB13)
+	pictographic := isExtendedPictographic(prev)
+	regionalIndicators := 0
+	if prevProp == gbRegionalIndicator {
+		regionalIndicators = 1
+	}
+
+	for size < len(text) {
+		r, n := utf8.DecodeRuneInString(text[size:])
+		prop := graphemePropertyOf(r)
+		if graphemeBreak(prevProp, prop, pictographic, regionalIndicators, isExtendedPictographic(r)) {
+			break
+		}
+
+		switch {
+		case isExtendedPictographic(r):
+			pictographic = true
+		case prop != gbExtend && prop != gbZWJ:
+			pictographic = false
+		}
+		if prop == gbRegionalIndicator {
+			regionalIndicators++
+		} else {
+			regionalIndicators = 0
+		}
+		prev, prevProp = r, prop
+		size += n
+	}
+	return size
+}
+
+// graphemeBreak reports whether there is a cluster boundary between two runes.
+// pictographic is set while the cluster so far ends in an emoji followed by
+// extenders, and regionalIndicators counts the regional indicators before cur.
+func graphemeBreak(prev, cur graphemeProperty, pictographic bool, regionalIndicators int, curPictographic bool) bool {
+	switch {
+	case prev == gbCR && cur == gbLF:
+		return false
+	case prev == gbCR || prev == gbLF || prev == gbControl:
+		return true
+	case cur == gbCR || cur == gbLF || cur == gbControl:
+		return true
+	case prev == gbL && (cur == gbL || cur == gbV || cur == gbLV || cur == gbLVT):
+		return false
+	case (prev == gbLV || prev == gbV) && (cur == gbV || cur == gbT):
+		return false
+	case (prev == gbLVT || prev == gbT) && cur == gbT:
+		return false
+	case cur == gbExtend || cur == gbZWJ || cur == gbSpacingMark:
+		return false
+	case prev == gbPrepend:
+		return false
+	case prev == gbZWJ && curPictographic && pictographic:
+		return false
+	case prev == gbRegionalIndicator && cur == gbRegionalIndicator:
+		return regionalIndicators%2 == 0
+	}
+	return true
+}
+
+// clusterWidth returns the number of terminal cells a grapheme cluster occupies.
+// Flags and emoji presentation sequences are two cells wide, otherwise the
+// first visible character decides.
+func clusterWidth(cluster string) int {
+	first, size := utf8.DecodeRuneInString(cluster)
+	if size == len(cluster) {
+		return runeWidth(first)
+	}
+	if isRegionalIndicator(first) {
+		return 2
+	}
+
+	width := 0
+	for _, r := range cluster {
+		if r == 0xFE0F && isExtendedPictographic(first) {
+			return 2
+		}
+		if width == 0 {
+			width = runeWidth(r)
+		}
+	}
+	return width
+}
+
+// leadingCodepoints replaces every grapheme cluster of text with its first codepoint.
+func leadingCodepoints(text string) string {
+	out := make([]byte, 0, len(text))
+	for g := Graphemes(text); g.Next(); {
+		_, size := utf8.DecodeRuneInString(g.Str())
+		out = append(out, g.Str()[:size]...)
+	}
+	return string(out)
+}
@@ -0,0 +1,86 @@
+package opentui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func graphemeStrings(text string) ([]string, []int) {
+	var clusters []string
+	var widths []int
+	for g := Graphemes(text); g.Next(); {
+		clusters = append(clusters, g.Str())
+		widths = append(widths, g.Width())
+	}
+	return clusters, widths
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		clusters []string
+		widths   []int
+	}{
+		{"ascii", "ab", []string{"a", "b"}, []int{1, 1}},
+		{"family", "👩‍👩‍👧‍👦x", []string{"👩‍👩‍👧‍👦", "x"}, []int{2, 1}},
+		{"composed é", "\u00e9t\u00e9", []string{"\u00e9", "t", "\u00e9"}, []int{1, 1, 1}},
+		{"decomposed é", "e\u0301te\u0301", []string{"e\u0301", "t", "e\u0301"}, []int{1, 1, 1}},
+		{"flags", "🇩🇪🇫🇷🇮", []string{"🇩🇪", "🇫🇷", "🇮"}, []int{2, 2, 1}},
+		{"skin tone", "👍🏽!", []string{"👍🏽", "!"}, []int{2, 1}},
+		{"emoji presentation", "❤️", []string{"❤️"}, []int{2}},
+		{"crlf", "a\r\nb", []string{"a", "\r\n", "b"}, []int{1, 0, 1}},
+		{"hangul jamo", "각가", []string{"각", "가"}, []int{2, 2}},
+		{"cjk", "日本", []string{"日", "本"}, []int{2, 2}},
+	}
+	for _, tt := range tests {
+		clusters, widths := graphemeStrings(tt.text)
+		if !reflect.DeepEqual(clusters, tt.clusters) {
+			t.Errorf("%s: clusters = %q, want %q", tt.name, clusters, tt.clusters)
+		}
+		if !reflect.DeepEqual(widths, tt.widths) {
+			t.Errorf("%s: widths = %v, want %v", tt.name, widths, tt.widths)
+		}
+	}
+}
+
+func TestGraphemeWidths(t *testing.T) {
+	if w := stringWidth("👩‍👩‍👧‍👦 🇯🇵"); w != 5 {
+		t.Errorf("stringWidth = %d, want 5", w)
+	}
+	if got := TruncateToWidth("👩‍👩‍👧‍👦👩‍👩‍👧‍👦", 3, "…"); got != "👩‍👩‍👧‍👦…" {
+		t.Errorf("TruncateToWidth = %q", got)
+	}
+}
+
+func TestDrawTextGraphemeClusters(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 1)
+	if err := buffer.SetGraphemeClusters(true); err != nil {
+		t.Fatalf("SetGraphemeClusters failed: %v", err)
+	}
+	buffer.DrawText("👩‍👩‍👧‍👦é🇩🇪x", 0, 0, White, nil, 0)
+
+	// Each cluster starts at its display column
+	for x, want := range map[uint32]rune{0: '👩', 2: 'e', 3: '🇩', 5: 'x'} {
+		if cell, _ := buffer.GetCell(x, 0); cell.Char != want {
+			t.Errorf("cell %d = %q, want %q", x, cell.Char, want)
+		}
+	}
+}
+
+func TestTextBufferGraphemeClusters(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.SetGraphemeClusters(true)
+	tb.WriteString("👩‍👩‍👧‍👦é🇩🇪")
+	if length, _ := tb.Length(); length != 3 {
+		t.Errorf("length = %d, want one cell per cluster", length)
+	}
+	if got := leadingCodepoints("👍🏽é"); got != "👍e" {
+		t.Errorf("leadingCodepoints = %q", got)
+	}
+}
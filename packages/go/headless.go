@@ -0,0 +1,55 @@
+package opentui
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ErrNotATTY is returned by the terminal features of a headless renderer, such as
+// SetupTerminal, EnableMouse, EnableKittyKeyboard and EnterAlternateScreen. It is the
+// same error as ErrNotTerminal.
+var ErrNotATTY = ErrNotTerminal
+
+// outputIsTerminal decides whether a renderer writing to a file is interactive.
+var outputIsTerminal = isTerminal
+
+// Interactive reports whether the renderer draws on a terminal. A renderer whose
+// output is a file other than a terminal, for example stdout in CI or piped into
+// another program, is headless unless RendererOptions.ForceTTY is set: it draws into
+// its buffers as usual, but Render writes nothing, the terminal features fail with
+// ErrNotATTY and RunLoop stops after one frame with io.EOF.
+func (r *Renderer) Interactive() bool {
+	return !r.headless
+}
+
+// headlessSize returns the size of a headless renderer created without one: the
+// COLUMNS and LINES environment variables, or 80x24.
+func headlessSize() (width, height uint32) {
+	if width, height, ok := environmentSize(os.Getenv("COLUMNS"), os.Getenv("LINES")); ok {
+		return width, height
+	}
+	return defaultTerminalWidth, defaultTerminalHeight
+}
+
+// printFinalFrame writes the frame last rendered by a headless renderer to its output
+// as plain text, for RendererOptions.PrintFinalFrame.
+func (r *Renderer) printFinalFrame() error {
+	rows, err := r.CaptureTextWithOptions(CaptureOptions{TrimTrailingSpace: true})
+	if err != nil {
+		return err
+	}
+	// Blank rows at the bottom of the frame carry no information
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	var out io.Writer = r.outputFile()
+	if r.output != nil {
+		out = r.output
+	}
+	_, err = io.WriteString(out, strings.Join(rows, "\n")+"\n")
+	return err
+}
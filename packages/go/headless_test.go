@@ -0,0 +1,85 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	// Tests write to pipes, but check what a terminal would receive
+	outputIsTerminal = func(*os.File) bool { return true }
+	os.Exit(m.Run())
+}
+
+func TestHeadlessRenderer(t *testing.T) {
+	outputIsTerminal = isTerminal
+	defer func() { outputIsTerminal = func(*os.File) bool { return true } }()
+	t.Setenv("COLUMNS", "")
+	t.Setenv("LINES", "")
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	defer reader.Close()
+	renderer := NewRendererWithOptions(RendererOptions{Output: writer, PrintFinalFrame: true})
+	if renderer == nil {
+		writer.Close()
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if renderer.Interactive() {
+		t.Error("a renderer writing to a pipe should not be interactive")
+	}
+	if renderer.width != 80 || renderer.height != 24 {
+		t.Errorf("headless renderer is %dx%d, want 80x24", renderer.width, renderer.height)
+	}
+	for name, call := range map[string]func() error{
+		"SetupTerminal":        func() error { return renderer.SetupTerminal(true) },
+		"EnableMouse":          func() error { return renderer.EnableMouse(true) },
+		"EnableKittyKeyboard":  func() error { return renderer.EnableKittyKeyboard(1) },
+		"EnterAlternateScreen": renderer.EnterAlternateScreen,
+	} {
+		if err := call(); !errors.Is(err, ErrNotATTY) {
+			t.Errorf("%s = %v, want ErrNotATTY", name, err)
+		}
+	}
+
+	// The native renderer doesn't write the frames anywhere, not even to stdout
+	frames := 0
+	var stdout bytes.Buffer
+	redirectStdout(&stdout, func() {
+		err = renderer.RunLoop(context.Background(), 100, func(dt time.Duration, buf *Buffer) error {
+			frames++
+			buf.Clear(Black)
+			return buf.DrawText("done", 0, 0, White, nil, 0)
+		})
+	})
+	if err != io.EOF || frames != 1 {
+		t.Errorf("RunLoop drew %d frames and returned %v, want 1 and io.EOF", frames, err)
+	}
+	if stdout.Len() > 0 {
+		t.Errorf("headless renderer wrote %q to stdout", stdout.String())
+	}
+
+	renderer.Close()
+	writer.Close()
+	out, _ := io.ReadAll(reader)
+	if string(out) != "done\n" {
+		t.Errorf("headless output = %q, want only the final frame", out)
+	}
+
+	forced := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: writer, ForceTTY: true})
+	if forced == nil || !forced.Interactive() {
+		t.Error("ForceTTY should keep the renderer interactive")
+	}
+	if forced != nil {
+		forced.Close()
+	}
+}
@@ -0,0 +1,261 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// identifyProbes are the queries sent by IdentifyTerminal. As in capabilityProbes, the
+// DA1 reply comes last and ends the identification.
+const identifyProbes = "\x1b[>0q" + // XTVERSION: name and version
+	"\x1b[>c" + // DA2: terminal type and firmware version
+	"\x1b[c" // DA1
+
+// TerminalInfo identifies the terminal emulator. Names are lower case, such as
+// "kitty", "wezterm", "iterm2", "xterm" or "apple_terminal".
+type TerminalInfo struct {
+	Name        string // Terminal emulator, or the multiplexer when the terminal it runs in is unknown
+	Version     string // Version of Name as reported, such as "0.32.2", empty if unknown
+	Multiplexer string // "tmux", "screen" or "zellij" when running inside one, else empty
+}
+
+// multiplexers are the names of terminal multiplexers, which answer queries themselves
+var multiplexers = []string{"tmux", "screen", "zellij"}
+
+// terminalAliases normalizes the names terminals go by in TERM_PROGRAM and TERM
+var terminalAliases = map[string]string{
+	"iterm.app":     "iterm2",
+	"xterm-kitty":   "kitty",
+	"xterm-ghostty": "ghostty",
+}
+
+// termNames are the TERM values naming one terminal emulator, unlike the generic
+// xterm-256color.
+var termNames = []string{"xterm-kitty", "xterm-ghostty", "alacritty", "foot", "wezterm", "contour"}
+
+// IdentifyTerminal tells which terminal emulator the renderer runs in, from its
+// XTVERSION and DA2 replies and from the TERM_PROGRAM, LC_TERMINAL, TERM, TMUX, STY and
+// ZELLIJ environment variables. Like DetectCapabilities, it returns once the terminal
+// answered the final DA1 probe, or with what the environment tells when ctx expires;
+// only a canceled ctx fails. Unless the renderer reads replies from its own Input,
+// they must be passed to ProcessCapabilityResponse from another goroutine while this
+// call waits. Capabilities.ApplyQuirks corrects capabilities known to be misreported
+// by the terminal identified.
+func (r *Renderer) IdentifyTerminal(ctx context.Context) (TerminalInfo, error) {
+	if r.ptr == nil {
+		return TerminalInfo{}, closedError("renderer")
+	}
+
+	done := make(chan struct{}, 1)
+	r.detection.mu.Lock()
+	r.detection.waiters = append(r.detection.waiters, done)
+	r.detection.mu.Unlock()
+	defer r.detection.removeWaiter(done)
+
+	if _, err := r.terminal().Write([]byte(identifyProbes)); err != nil {
+		return TerminalInfo{}, err
+	}
+	r.readReplies()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return TerminalInfo{}, ctx.Err()
+		}
+	}
+	return identifyTerminal(r.detected.xtversion, r.detected.secondaryDA, os.Getenv), nil
+}
+
+// identifyTerminal combines an XTVERSION reply, the parameters of a DA2 reply and the
+// environment into a TerminalInfo. Replies come from the multiplexer when there is
+// one, so the terminal it runs in is only known from the environment.
+func identifyTerminal(xtversion, secondaryDA string, getenv func(string) string) TerminalInfo {
+	var info TerminalInfo
+	var muxName, muxVersion string
+	mux := func(name, version string) {
+		if info.Multiplexer == "" {
+			info.Multiplexer = name
+		}
+		if muxName == "" || (muxName == name && muxVersion == "") {
+			muxName, muxVersion = name, version
+		}
+	}
+	terminal := func(name, version string) {
+		name = normalizeTerminalName(name)
+		switch {
+		case name == "":
+		case isMultiplexer(name):
+			mux(name, version)
+		case info.Name == "":
+			info.Name, info.Version = name, version
+		}
+	}
+
+	switch {
+	case getenv("TMUX") != "":
+		mux("tmux", "")
+	case getenv("STY") != "":
+		mux("screen", "")
+	case getenv("ZELLIJ") != "":
+		mux("zellij", "")
+	}
+	terminal(splitTerminalVersion(xtversion))
+	terminal(getenv("TERM_PROGRAM"), getenv("TERM_PROGRAM_VERSION"))
+	terminal(getenv("LC_TERMINAL"), getenv("LC_TERMINAL_VERSION"))
+
+	// DA2 names a terminal type by number; xterm reports its patch level as version
+	params := strings.Split(secondaryDA, ";")
+	switch params[0] {
+	case "41":
+		if len(params) > 1 {
+			terminal("xterm", params[1])
+		}
+	case "83":
+		mux("screen", "")
+	case "84":
+		mux("tmux", "")
+	}
+
+	term := getenv("TERM")
+	for _, name := range termNames {
+		if term == name {
+			terminal(name, "")
+		}
+	}
+	for _, name := range multiplexers {
+		if strings.HasPrefix(term, name) {
+			mux(name, "")
+		}
+	}
+
+	if info.Name == "" {
+		info.Name, info.Version = muxName, muxVersion
+	}
+	return info
+}
+
+// splitTerminalVersion splits an XTVERSION text such as "kitty(0.32.2)" or
+// "WezTerm 20240203-110809-5046fc22" into the name and the version.
+func splitTerminalVersion(text string) (string, string) {
+	if open := strings.IndexByte(text, '('); open > 0 && strings.HasSuffix(text, ")") {
+		return text[:open], text[open+1 : len(text)-1]
+	}
+	name, version, _ := strings.Cut(text, " ")
+	return name, strings.TrimSpace(version)
+}
+
+func normalizeTerminalName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := terminalAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+func isMultiplexer(name string) bool {
+	for _, mux := range multiplexers {
+		if name == mux {
+			return true
+		}
+	}
+	return false
+}
+
+// terminalQuirk corrects capabilities for the terminals matching name, multiplexer and
+// a version below below, each ignored when empty.
+type terminalQuirk struct {
+	name        string
+	multiplexer string
+	below       string
+	apply       func(*Capabilities)
+}
+
+// terminalQuirks are capabilities detected wrongly, or not at all, on some terminals
+var terminalQuirks = []terminalQuirk{
+	// Terminal.app answers 24-bit colors with the nearest palette color on screen
+	{name: "apple_terminal", apply: func(c *Capabilities) { c.SupportsTruecolor = false }},
+	// xterm ignores OSC 8, printing the link text only
+	{name: "xterm", apply: func(c *Capabilities) { c.SupportsHyperlinks = false }},
+	// Sixel support came with tmux 3.4
+	{name: "tmux", below: "3.4", apply: func(c *Capabilities) { c.SupportsSixel = false }},
+	// tmux drops iTerm2 images, unless wrapped in its passthrough sequence
+	{multiplexer: "tmux", apply: func(c *Capabilities) { c.SupportsITerm2Images = false }},
+	// screen passes neither hyperlinks nor synchronized output through
+	{multiplexer: "screen", apply: func(c *Capabilities) {
+		c.SupportsHyperlinks = false
+		c.SupportsSyncOutput = false
+	}},
+}
+
+// ApplyQuirks corrects the capabilities known to be misreported by the terminal
+// identified by info, as returned by IdentifyTerminal, from a small built-in table.
+func (c *Capabilities) ApplyQuirks(info TerminalInfo) {
+	for _, quirk := range terminalQuirks {
+		if quirk.name != "" && quirk.name != info.Name {
+			continue
+		}
+		if quirk.multiplexer != "" && quirk.multiplexer != info.Multiplexer {
+			continue
+		}
+		if quirk.below != "" && !versionBelow(info.Version, quirk.below) {
+			continue
+		}
+		quirk.apply(c)
+	}
+}
+
+// versionBelow reports whether version is below limit, comparing the numbers in them
+// in order, so that "3.10" is above "3.9" and "3" equals "3.0". An unknown version is
+// not below any limit.
+func versionBelow(version, limit string) bool {
+	have, want := versionNumbers(version), versionNumbers(limit)
+	if len(have) == 0 {
+		return false
+	}
+	for i, n := range want {
+		number := 0
+		if i < len(have) {
+			number = have[i]
+		}
+		if number != n {
+			return number < n
+		}
+	}
+	return false
+}
+
+// versionNumbers extracts the runs of digits of a version, such as 3, 4 from "3.4a"
+func versionNumbers(version string) []int {
+	var numbers []int
+	for _, field := range strings.FieldsFunc(version, func(r rune) bool { return r < '0' || r > '9' }) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
+
+// secondaryDeviceAttributes extracts the parameters of the first DA2 reply
+// (ESC [ > params c) contained in a response.
+func secondaryDeviceAttributes(response []byte) (string, bool) {
+	for {
+		start := bytes.Index(response, []byte("\x1b[>"))
+		if start < 0 {
+			return "", false
+		}
+		response = response[start+3:]
+		end := 0
+		for end < len(response) && (response[end] == ';' || (response[end] >= '0' && response[end] <= '9')) {
+			end++
+		}
+		if end > 0 && end < len(response) && response[end] == 'c' {
+			return string(response[:end]), true
+		}
+	}
+}
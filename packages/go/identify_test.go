@@ -0,0 +1,121 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestIdentifyTerminalInfo(t *testing.T) {
+	tests := []struct {
+		xtversion, secondaryDA string
+		env                    map[string]string
+		want                   TerminalInfo
+	}{
+		{"kitty(0.32.2)", "1;4000;32", nil, TerminalInfo{"kitty", "0.32.2", ""}},
+		{"WezTerm 20240203-110809-5046fc22", "", map[string]string{"TERM_PROGRAM": "WezTerm"}, TerminalInfo{"wezterm", "20240203-110809-5046fc22", ""}},
+		{"", "", map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "3.5.0"}, TerminalInfo{"iterm2", "3.5.0", ""}},
+		{"", "41;388;0", map[string]string{"TERM": "xterm-256color"}, TerminalInfo{"xterm", "388", ""}},
+		{"", "", map[string]string{"TERM": "xterm-kitty"}, TerminalInfo{"kitty", "", ""}},
+		// Inside tmux the replies come from tmux, the terminal only from the environment
+		{"tmux 3.4", "84;0;0", map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0", "TERM_PROGRAM": "tmux", "LC_TERMINAL": "iTerm2", "LC_TERMINAL_VERSION": "3.5.0"}, TerminalInfo{"iterm2", "3.5.0", "tmux"}},
+		{"tmux 3.3a", "84;0;0", map[string]string{"TERM": "screen-256color"}, TerminalInfo{"tmux", "3.3a", "tmux"}},
+		{"", "83;40900;0", map[string]string{"STY": "1234.pts-0.host"}, TerminalInfo{"screen", "", "screen"}},
+		{"", "", nil, TerminalInfo{}},
+	}
+	for _, tt := range tests {
+		info := identifyTerminal(tt.xtversion, tt.secondaryDA, func(key string) string { return tt.env[key] })
+		if info != tt.want {
+			t.Errorf("identifyTerminal(%q, %q, %v) = %+v, want %+v", tt.xtversion, tt.secondaryDA, tt.env, info, tt.want)
+		}
+	}
+}
+
+func TestApplyQuirks(t *testing.T) {
+	all := Capabilities{SupportsTruecolor: true, SupportsSixel: true, SupportsITerm2Images: true, SupportsHyperlinks: true, SupportsSyncOutput: true}
+
+	caps := all
+	caps.ApplyQuirks(TerminalInfo{Name: "kitty", Version: "0.32.2"})
+	if caps != all {
+		t.Errorf("kitty capabilities changed to %+v", caps)
+	}
+
+	caps = all
+	caps.ApplyQuirks(TerminalInfo{Name: "tmux", Version: "3.3a", Multiplexer: "tmux"})
+	if caps.SupportsSixel || caps.SupportsITerm2Images || !caps.SupportsTruecolor {
+		t.Errorf("tmux 3.3a capabilities = %+v", caps)
+	}
+	caps = all
+	caps.ApplyQuirks(TerminalInfo{Name: "tmux", Version: "3.4", Multiplexer: "tmux"})
+	if !caps.SupportsSixel || caps.SupportsITerm2Images {
+		t.Errorf("tmux 3.4 capabilities = %+v", caps)
+	}
+
+	caps = all
+	caps.ApplyQuirks(TerminalInfo{Name: "apple_terminal"})
+	if caps.SupportsTruecolor {
+		t.Error("Terminal.app should lose 24-bit color")
+	}
+}
+
+func TestVersionBelow(t *testing.T) {
+	tests := []struct {
+		version, limit string
+		below          bool
+	}{
+		{"3.3a", "3.4", true},
+		{"3.4", "3.4", false},
+		{"3", "3.0", false},
+		{"3.10", "3.9", false},
+		{"0.9.1", "0.10", true},
+		{"", "3.4", false},
+	}
+	for _, tt := range tests {
+		if below := versionBelow(tt.version, tt.limit); below != tt.below {
+			t.Errorf("versionBelow(%q, %q) = %v, want %v", tt.version, tt.limit, below, tt.below)
+		}
+	}
+}
+
+func TestIdentifyTerminal(t *testing.T) {
+	for _, key := range []string{"TMUX", "STY", "ZELLIJ", "TERM_PROGRAM", "TERM_PROGRAM_VERSION", "LC_TERMINAL", "LC_TERMINAL_VERSION", "TERM"} {
+		t.Setenv(key, "")
+	}
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	go func() {
+		writer.Write([]byte("\x1bP>|kitty(0.32.2)\x1b\\\x1b[>1;4000;32c"))
+		writer.Write([]byte("\x1b[?62;c"))
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	info, err := renderer.IdentifyTerminal(ctx)
+	if err != nil {
+		t.Fatalf("IdentifyTerminal failed: %v", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("identification should end with the DA1 reply")
+	}
+	if info != (TerminalInfo{Name: "kitty", Version: "0.32.2"}) {
+		t.Errorf("IdentifyTerminal = %+v", info)
+	}
+	if out.String() != identifyProbes {
+		t.Errorf("probes written as %q", out.String())
+	}
+
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := renderer.IdentifyTerminal(canceled); err == nil {
+		t.Error("a canceled identification should fail")
+	}
+}
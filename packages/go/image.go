@@ -0,0 +1,472 @@
+package opentui
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// pixelAlphaThreshold is the alpha below which a pixel is treated as fully transparent
+const pixelAlphaThreshold = 16
+
+// Half block characters used for pixel drawing
+const (
+	upperHalfBlock = '▀'
+	lowerHalfBlock = '▄'
+)
+
+// DrawPixelsHalfBlock draws an image at 2x vertical resolution, mapping every two rows of
+// pixels onto one row of cells using '▀' with the top pixel as foreground and the bottom
+// pixel as background. Transparent pixels leave the existing cell content visible and
+// the image is clipped at the buffer edges.
+func (b *Buffer) DrawPixelsHalfBlock(x, y uint32, img *image.RGBA) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if img == nil {
+		return newError("image is nil")
+	}
+
+	width, height, err := b.Size()
+	if err != nil {
+		return err
+	}
+
+	bounds := img.Bounds()
+	for py := bounds.Min.Y; py < bounds.Max.Y; py += 2 {
+		cy := uint64(y) + uint64(py-bounds.Min.Y)/2
+		if cy >= uint64(height) {
+			break
+		}
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			cx := uint64(x) + uint64(px-bounds.Min.X)
+			if cx >= uint64(width) {
+				break
+			}
+
+			top, topVisible := pixelAt(img, px, py)
+			bottom, bottomVisible := pixelAt(img, px, py+1)
+			switch {
+			case topVisible && bottomVisible:
+				b.SetCellWithAlphaBlending(uint32(cx), uint32(cy), upperHalfBlock, top, bottom, 0)
+			case topVisible:
+				b.setCell(uint32(cx), uint32(cy), upperHalfBlock, top, nil, 0)
+			case bottomVisible:
+				b.setCell(uint32(cx), uint32(cy), lowerHalfBlock, bottom, nil, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// pixelAt returns the straight alpha color of a pixel and whether it is visible.
+// Pixels outside the image are invisible.
+func pixelAt(img *image.RGBA, px, py int) (RGBA, bool) {
+	if !(image.Point{X: px, Y: py}).In(img.Bounds()) {
+		return RGBA{}, false
+	}
+	c := img.RGBAAt(px, py)
+	if c.A < pixelAlphaThreshold {
+		return RGBA{}, false
+	}
+
+	// image.RGBA stores premultiplied alpha
+	a := float32(c.A)
+	return RGBA{
+		R: float32(c.R) / a,
+		G: float32(c.G) / a,
+		B: float32(c.B) / a,
+		A: a / 255,
+	}, true
+}
+
+// BlockMode selects the block characters used to draw pixels into cells
+type BlockMode uint8
+
+const (
+	BlockHalf     BlockMode = iota // 1x2 pixels per cell using half blocks, supported almost everywhere
+	BlockQuadrant                  // 2x2 pixels per cell using quadrant blocks
+	BlockSextant                   // 2x3 pixels per cell using Unicode 13 sextants
+)
+
+// quadrantGlyphs maps a 2x2 pixel mask to its block character.
+// Bits are numbered left to right, top to bottom.
+var quadrantGlyphs = [16]rune{
+	' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛',
+	'▗', '▚', '▐', '▜', '▄', '▙', '▟', '█',
+}
+
+// cellSize returns the number of pixels covered by one cell.
+func (m BlockMode) cellSize() (int, int) {
+	switch m {
+	case BlockQuadrant:
+		return 2, 2
+	case BlockSextant:
+		return 2, 3
+	default:
+		return 1, 2
+	}
+}
+
+// glyph returns the block character for a pixel mask.
+// Bits are numbered left to right, top to bottom.
+func (m BlockMode) glyph(mask int) rune {
+	switch m {
+	case BlockQuadrant:
+		return quadrantGlyphs[mask]
+	case BlockSextant:
+		// The sextant block skips the patterns already covered by ' ', '▌', '▐' and '█'
+		switch mask {
+		case 0:
+			return ' '
+		case 0b010101:
+			return '▌'
+		case 0b101010:
+			return '▐'
+		case 0b111111:
+			return '█'
+		}
+		code := 0x1FB00 + rune(mask) - 1
+		if mask > 0b010101 {
+			code--
+		}
+		if mask > 0b101010 {
+			code--
+		}
+		return code
+	default:
+		return [4]rune{' ', upperHalfBlock, lowerHalfBlock, '█'}[mask]
+	}
+}
+
+// DrawPixels draws an image using block characters, with mode selecting the resolution.
+// For quadrant and sextant modes each cell gets the glyph and foreground/background pair
+// that minimizes the squared color error over the pixels it covers. If some of a cell's
+// pixels are transparent, the opaque ones are drawn over the existing background instead.
+// Terminals without sextant support should use BlockHalf or BlockQuadrant.
+func (b *Buffer) DrawPixels(x, y uint32, img *image.RGBA, mode BlockMode) error {
+	if mode == BlockHalf {
+		return b.DrawPixelsHalfBlock(x, y, img)
+	}
+	if mode != BlockQuadrant && mode != BlockSextant {
+		return newError("invalid block mode")
+	}
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if img == nil {
+		return newError("image is nil")
+	}
+
+	width, height, err := b.Size()
+	if err != nil {
+		return err
+	}
+
+	cellWidth, cellHeight := mode.cellSize()
+	pixels := make([]RGBA, cellWidth*cellHeight)
+	bounds := img.Bounds()
+	for py := bounds.Min.Y; py < bounds.Max.Y; py += cellHeight {
+		cy := uint64(y) + uint64((py-bounds.Min.Y)/cellHeight)
+		if cy >= uint64(height) {
+			break
+		}
+		for px := bounds.Min.X; px < bounds.Max.X; px += cellWidth {
+			cx := uint64(x) + uint64((px-bounds.Min.X)/cellWidth)
+			if cx >= uint64(width) {
+				break
+			}
+
+			visible := 0
+			for i := range pixels {
+				color, ok := pixelAt(img, px+i%cellWidth, py+i/cellWidth)
+				pixels[i] = color
+				if ok {
+					visible |= 1 << i
+				}
+			}
+			if visible == 0 {
+				continue
+			}
+
+			mask, fg, bg := quantizeCell(pixels, visible)
+			if bg == nil {
+				b.setCell(uint32(cx), uint32(cy), mode.glyph(mask), fg, nil, 0)
+			} else {
+				b.SetCellWithAlphaBlending(uint32(cx), uint32(cy), mode.glyph(mask), fg, *bg, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// quantizeCell picks the two color representation of a cell's pixels.
+// It returns the mask of pixels drawn in the foreground color and the colors to use.
+// A nil background means the existing background should be kept, which happens
+// when only some of the pixels are visible.
+func quantizeCell(pixels []RGBA, visible int) (int, RGBA, *RGBA) {
+	full := 1<<len(pixels) - 1
+	if visible != full {
+		return visible, meanColor(pixels, visible), nil
+	}
+
+	// Searching downwards makes uniform cells come out as a full block
+	bestMask, bestErr := full, float32(-1)
+	var bestFg, bestBg RGBA
+	for mask := full; mask >= 1; mask-- {
+		fg := meanColor(pixels, mask)
+		bg := fg
+		if mask != full {
+			bg = meanColor(pixels, full&^mask)
+		}
+
+		var total float32
+		for i, p := range pixels {
+			if mask&(1<<i) != 0 {
+				total += colorDistance(p, fg)
+			} else {
+				total += colorDistance(p, bg)
+			}
+		}
+		if bestErr < 0 || total < bestErr {
+			bestMask, bestErr, bestFg, bestBg = mask, total, fg, bg
+		}
+	}
+	return bestMask, bestFg, &bestBg
+}
+
+// meanColor averages the pixels selected by mask.
+func meanColor(pixels []RGBA, mask int) RGBA {
+	var sum RGBA
+	var n float32
+	for i, p := range pixels {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		sum.R += p.R
+		sum.G += p.G
+		sum.B += p.B
+		sum.A += p.A
+		n++
+	}
+	if n == 0 {
+		return RGBA{}
+	}
+	return RGBA{R: sum.R / n, G: sum.G / n, B: sum.B / n, A: sum.A / n}
+}
+
+// colorDistance returns the squared distance between two colors in RGB space.
+func colorDistance(a, b RGBA) float32 {
+	dr, dg, db := a.R-b.R, a.G-b.G, a.B-b.B
+	return dr*dr + dg*dg + db*db
+}
+
+// DitherMode selects how colors are dithered before drawing an image
+type DitherMode uint8
+
+const (
+	DitherNone           DitherMode = iota // Draw colors as they are
+	DitherFloydSteinberg                   // Diffuse the error of snapping to the 256 color palette
+)
+
+// ImageOptions controls how DrawImage scales and draws an image.
+// If both Width and Height are zero the image is drawn at one pixel per block pixel.
+// Otherwise it is scaled to fit the given cells while keeping its aspect ratio,
+// with a zero dimension left unconstrained.
+type ImageOptions struct {
+	Width  uint32     // Target width in cells
+	Height uint32     // Target height in cells
+	Mode   BlockMode  // Block characters used for the pixels
+	Dither DitherMode // Dithering applied before drawing
+}
+
+// DrawImage draws any image.Image at (x, y), scaling it with a box filter.
+// With DitherFloydSteinberg, colors are snapped to the xterm 256 color palette with
+// error diffusion, so the image still looks smooth on terminals without truecolor.
+// Semi-transparent pixels are blended over the existing buffer content.
+func (b *Buffer) DrawImage(x, y uint32, img image.Image, opts ImageOptions) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if img == nil {
+		return newError("image is nil")
+	}
+	if opts.Mode > BlockSextant {
+		return newError("invalid block mode")
+	}
+	if opts.Dither > DitherFloydSteinberg {
+		return newError("invalid dither mode")
+	}
+
+	width, height := imageTargetSize(img.Bounds(), opts)
+	scaled := scaleImage(img, width, height)
+	if opts.Dither == DitherFloydSteinberg {
+		ditherFloydSteinberg(scaled)
+	}
+	return b.DrawPixels(x, y, scaled, opts.Mode)
+}
+
+// imageTargetSize returns the size in pixels an image is scaled to for DrawImage.
+func imageTargetSize(bounds image.Rectangle, opts ImageOptions) (int, int) {
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth <= 0 || srcHeight <= 0 || (opts.Width == 0 && opts.Height == 0) {
+		return max(srcWidth, 0), max(srcHeight, 0)
+	}
+
+	// Block pixels aren't square, so fit the image in terminal pixels
+	cellWidth, cellHeight := opts.Mode.cellSize()
+	pixelWidth := float64(defaultCellWidth) / float64(cellWidth)
+	pixelHeight := float64(defaultCellHeight) / float64(cellHeight)
+
+	scale := math.Inf(1)
+	if opts.Width > 0 {
+		scale = float64(opts.Width) * defaultCellWidth / float64(srcWidth)
+	}
+	if opts.Height > 0 {
+		scale = math.Min(scale, float64(opts.Height)*defaultCellHeight/float64(srcHeight))
+	}
+
+	width := int(math.Round(float64(srcWidth) * scale / pixelWidth))
+	height := int(math.Round(float64(srcHeight) * scale / pixelHeight))
+	return max(width, 1), max(height, 1)
+}
+
+// scaleImage resamples an image to the given size by averaging the source pixels
+// covered by each target pixel. Averaging happens on premultiplied colors so
+// transparent pixels don't bleed their color into their neighbours.
+func scaleImage(img image.Image, width, height int) *image.RGBA {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	if srcWidth == 0 || srcHeight == 0 {
+		return dst
+	}
+
+	for ty := 0; ty < height; ty++ {
+		y0 := bounds.Min.Y + ty*srcHeight/height
+		y1 := max(bounds.Min.Y+(ty+1)*srcHeight/height, y0+1)
+		for tx := 0; tx < width; tx++ {
+			x0 := bounds.Min.X + tx*srcWidth/width
+			x1 := max(bounds.Min.X+(tx+1)*srcWidth/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA(tx, ty, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// ditherFloydSteinberg snaps the visible pixels of an image to the xterm 256 color
+// palette, diffusing the error to the neighbouring pixels.
+func ditherFloydSteinberg(img *image.RGBA) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Work on straight alpha colors with room for the diffused error
+	pixels := make([][3]float32, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if c, ok := pixelAt(img, bounds.Min.X+x, bounds.Min.Y+y); ok {
+				pixels[y*width+x] = [3]float32{c.R * 255, c.G * 255, c.B * 255}
+			}
+		}
+	}
+
+	diffuse := func(x, y int, err [3]float32, weight float32) {
+		if x < 0 || x >= width || y >= height {
+			return
+		}
+		p := &pixels[y*width+x]
+		for i := range p {
+			p[i] += err[i] * weight
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			px, py := bounds.Min.X+x, bounds.Min.Y+y
+			a := img.RGBAAt(px, py).A
+			if a < pixelAlphaThreshold {
+				continue
+			}
+
+			old := pixels[y*width+x]
+			r, g, b := nearestXterm256(clampByte(old[0]), clampByte(old[1]), clampByte(old[2]))
+			err := [3]float32{old[0] - float32(r), old[1] - float32(g), old[2] - float32(b)}
+			diffuse(x+1, y, err, 7.0/16)
+			diffuse(x-1, y+1, err, 3.0/16)
+			diffuse(x, y+1, err, 5.0/16)
+			diffuse(x+1, y+1, err, 1.0/16)
+
+			// Store premultiplied again
+			img.SetRGBA(px, py, color.RGBA{
+				R: uint8(uint32(r) * uint32(a) / 255),
+				G: uint8(uint32(g) * uint32(a) / 255),
+				B: uint8(uint32(b) * uint32(a) / 255),
+				A: a,
+			})
+		}
+	}
+}
+
+// xtermCubeLevels are the channel values of the 6x6x6 color cube in the xterm 256 color palette
+var xtermCubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// nearestXterm256 returns the closest color of the xterm 256 color cube and gray ramp.
+// The 16 system colors are skipped since terminals configure them freely.
+func nearestXterm256(r, g, b uint8) (uint8, uint8, uint8) {
+	nearestLevel := func(v uint8) uint8 {
+		best := xtermCubeLevels[0]
+		for _, level := range xtermCubeLevels[1:] {
+			if absDiff(level, v) < absDiff(best, v) {
+				best = level
+			}
+		}
+		return best
+	}
+	cr, cg, cb := nearestLevel(r), nearestLevel(g), nearestLevel(b)
+
+	// Gray ramp runs from 8 to 238 in steps of 10
+	avg := (int(r) + int(g) + int(b)) / 3
+	step := min(max((avg-8+5)/10, 0), 23)
+	gray := uint8(8 + step*10)
+
+	distance := func(r2, g2, b2 uint8) int {
+		dr, dg, db := int(r)-int(r2), int(g)-int(g2), int(b)-int(b2)
+		return dr*dr + dg*dg + db*db
+	}
+	if distance(gray, gray, gray) < distance(cr, cg, cb) {
+		return gray, gray, gray
+	}
+	return cr, cg, cb
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+func clampByte(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	default:
+		return uint8(v + 0.5)
+	}
+}
@@ -0,0 +1,238 @@
+package opentui
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawPixelsHalfBlock(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 3)
+
+	// 2x3 image: the odd last row only has a top pixel, and (1,1) is transparent
+	img := image.NewRGBA(image.Rect(0, 0, 2, 3))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	img.Set(0, 1, color.RGBA{0, 0, 255, 255})
+	img.Set(1, 0, color.RGBA{0, 255, 0, 255})
+	img.Set(0, 2, color.RGBA{255, 255, 255, 255})
+
+	if err := buffer.DrawPixelsHalfBlock(1, 1, img); err != nil {
+		t.Fatalf("DrawPixelsHalfBlock failed: %v", err)
+	}
+
+	expectRows(t, buffer,
+		"   ",
+		" ▀▀",
+		" ▀ ",
+	)
+
+	da, _ := buffer.GetDirectAccess()
+	cell, _ := da.GetCell(1, 1)
+	if cell.Foreground != Red || cell.Background != Blue {
+		t.Errorf("cell (1,1) = %+v, want red on blue", cell)
+	}
+	cell, _ = da.GetCell(2, 1)
+	if cell.Foreground != Green || cell.Background != Black {
+		t.Errorf("cell (2,1) = %+v, want green keeping the black background", cell)
+	}
+}
+
+func TestDrawPixelsHalfBlockClipping(t *testing.T) {
+	buffer := newTestBuffer(t, 2, 1)
+
+	img := image.NewRGBA(image.Rect(5, 5, 10, 10))
+	for y := 5; y < 10; y++ {
+		for x := 5; x < 10; x++ {
+			img.Set(x, y, color.RGBA{0, 0, 0, 255})
+		}
+	}
+	if err := buffer.DrawPixelsHalfBlock(1, 0, img); err != nil {
+		t.Fatalf("DrawPixelsHalfBlock failed: %v", err)
+	}
+	expectRows(t, buffer, " ▀")
+}
+
+// blockTestImage returns a 4x6 reference image of red and blue pixels.
+func blockTestImage() *image.RGBA {
+	rows := []string{
+		"RRBB",
+		"RRBB",
+		"RBBR",
+		"BRRB",
+		"BBRR",
+		"BBRW",
+	}
+	colors := map[byte]color.RGBA{
+		'R': {255, 0, 0, 255},
+		'B': {0, 0, 255, 255},
+		'W': {255, 255, 255, 255},
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 4, 6))
+	for y, row := range rows {
+		for x := range row {
+			img.Set(x, y, colors[row[x]])
+		}
+	}
+	return img
+}
+
+func TestBlockModeGlyphs(t *testing.T) {
+	if got := BlockQuadrant.glyph(0b1001); got != '▚' {
+		t.Errorf("quadrant glyph for TL+BR = %q, want '▚'", got)
+	}
+	tests := []struct {
+		mask int
+		want rune
+	}{
+		{0b000001, 0x1FB00},
+		{0b010100, 0x1FB13},
+		{0b010101, '▌'},
+		{0b010110, 0x1FB14},
+		{0b101010, '▐'},
+		{0b101011, 0x1FB28},
+		{0b111110, 0x1FB3B},
+		{0b111111, '█'},
+	}
+	for _, tt := range tests {
+		if got := BlockSextant.glyph(tt.mask); got != tt.want {
+			t.Errorf("sextant glyph for %06b = %U, want %U", tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestDrawPixelsGolden(t *testing.T) {
+	tests := []struct {
+		mode BlockMode
+		want []string
+	}{
+		{BlockHalf, []string{"▀▀▀▀", "▀▀▀▀", "▀▀▀▀"}},
+		// Checkerboard cells pick the higher of two equivalent masks, the white
+		// pixel stands out on its own against three reds
+		{BlockQuadrant, []string{"██  ", "▚▚  ", "█▗  "}},
+		// Bottom-right dots, the complement of the top-right dot, and blue plus
+		// white grouped against the reds
+		{BlockSextant, []string{"\U0001FB1E\U0001FB1E  ", "\U0001FB3A\U0001FB20  ", "    "}},
+	}
+
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 4, 3)
+		if err := buffer.DrawPixels(0, 0, blockTestImage(), tt.mode); err != nil {
+			t.Fatalf("DrawPixels(%d) failed: %v", tt.mode, err)
+		}
+		expectRows(t, buffer, tt.want...)
+	}
+}
+
+func TestDrawPixelsColors(t *testing.T) {
+	buffer := newTestBuffer(t, 2, 1)
+	if err := buffer.DrawPixels(0, 0, blockTestImage(), BlockQuadrant); err != nil {
+		t.Fatalf("DrawPixels failed: %v", err)
+	}
+
+	// The top quadrant cells are solid red and solid blue
+	da, _ := buffer.GetDirectAccess()
+	left, _ := da.GetCell(0, 0)
+	right, _ := da.GetCell(1, 0)
+	if left.Char != '█' || left.Foreground != Red {
+		t.Errorf("left cell = %+v, want a red full block", left)
+	}
+	if right.Char != '█' || right.Foreground != Blue {
+		t.Errorf("right cell = %+v, want a blue full block", right)
+	}
+}
+
+func TestImageTargetSize(t *testing.T) {
+	bounds := image.Rect(0, 0, 100, 50)
+	tests := []struct {
+		name          string
+		opts          ImageOptions
+		width, height int
+	}{
+		{"native", ImageOptions{}, 100, 50},
+		{"half width", ImageOptions{Width: 20}, 20, 10},
+		{"half height", ImageOptions{Height: 5}, 20, 10},
+		{"half box", ImageOptions{Width: 40, Height: 5}, 20, 10},
+		{"quadrant width", ImageOptions{Width: 20, Mode: BlockQuadrant}, 40, 10},
+	}
+	for _, tt := range tests {
+		w, h := imageTargetSize(bounds, tt.opts)
+		if w != tt.width || h != tt.height {
+			t.Errorf("%s: got %dx%d, want %dx%d", tt.name, w, h, tt.width, tt.height)
+		}
+	}
+}
+
+func TestScaleImageBoxFilter(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.Set(x, 0, color.RGBA{200, 0, 0, 255})
+		img.Set(x, 1, color.RGBA{0, 0, 100, 255})
+	}
+	// Transparent pixels count towards coverage but not color
+	img.Set(3, 0, color.RGBA{})
+	img.Set(3, 1, color.RGBA{})
+
+	scaled := scaleImage(img, 2, 1)
+	if got := scaled.RGBAAt(0, 0); got != (color.RGBA{100, 0, 50, 255}) {
+		t.Errorf("left pixel = %v", got)
+	}
+	if got := scaled.RGBAAt(1, 0); got != (color.RGBA{50, 0, 25, 127}) {
+		t.Errorf("right pixel = %v", got)
+	}
+}
+
+func TestNearestXterm256(t *testing.T) {
+	tests := []struct{ in, want [3]uint8 }{
+		{[3]uint8{255, 0, 0}, [3]uint8{255, 0, 0}},
+		{[3]uint8{100, 100, 100}, [3]uint8{98, 98, 98}},
+		{[3]uint8{90, 140, 210}, [3]uint8{95, 135, 215}},
+	}
+	for _, tt := range tests {
+		r, g, b := nearestXterm256(tt.in[0], tt.in[1], tt.in[2])
+		if got := [3]uint8{r, g, b}; got != tt.want {
+			t.Errorf("nearestXterm256(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDitherFloydSteinberg(t *testing.T) {
+	// A flat color between two palette entries has to come out as a mix of both
+	img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{115, 0, 0, 255})
+		}
+	}
+	ditherFloydSteinberg(img)
+
+	seen := map[uint8]int{}
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			seen[img.RGBAAt(x, y).R]++
+		}
+	}
+	if len(seen) != 2 || seen[95] == 0 || seen[135] == 0 {
+		t.Errorf("dithered reds = %v, want a mix of 95 and 135", seen)
+	}
+}
+
+func TestDrawImage(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 2)
+
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 40))
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, color.NRGBA{255, 0, 0, 255})
+		}
+	}
+	if err := buffer.DrawImage(1, 0, img, ImageOptions{Width: 2}); err != nil {
+		t.Fatalf("DrawImage failed: %v", err)
+	}
+	expectRows(t, buffer,
+		" ▀▀ ",
+		"    ",
+	)
+	if err := buffer.DrawImage(0, 0, img, ImageOptions{Mode: 9}); err == nil {
+		t.Error("expected error for invalid block mode")
+	}
+}
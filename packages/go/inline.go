@@ -0,0 +1,257 @@
+package opentui
+
+/*
+#include "opentui.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// inlineQueryTimeout bounds the wait for the cursor position when the inline region
+// is placed. Without a reply the region goes to the bottom of the terminal.
+const inlineQueryTimeout = 100 * time.Millisecond
+
+// inlineRegion is the part of the main screen an inline renderer draws into. Its
+// fields are guarded by the suspend mutex, which already serializes frames.
+type inlineRegion struct {
+	height uint32 // rows reserved, the renderer height unless the terminal is smaller
+	rows   uint32 // height of the terminal
+	top    uint32 // first row of the region, 0-based
+	keep   bool   // leave the last frame on screen on Close
+
+	pending []byte // line written to InlineWriter without its newline yet
+
+	captureStdout bool
+	stdout        *os.File      // os.Stdout before it was replaced by the capture pipe
+	pipe          *os.File      // write end of the capture pipe, installed as os.Stdout
+	captured      chan struct{} // closed once the capture pipe is drained
+}
+
+// PrintAbove prints text on the main screen above the inline region, which moves
+// down to make room and stays below the text, scrolling the terminal when it reaches
+// the bottom. A trailing newline is implied. The region is cleared and redrawn by the
+// next Render.
+func (r *Renderer) PrintAbove(text string) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if r.inline == nil {
+		return newError("renderer is not inline")
+	}
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	return r.printAbove(strings.TrimSuffix(text, "\n"))
+}
+
+// InlineWriter returns a writer that prints complete lines above the inline region
+// like PrintAbove, for example for log.SetOutput. A line without its newline is held
+// back until the newline arrives or the renderer is closed.
+func (r *Renderer) InlineWriter() io.Writer {
+	return inlineWriter{r}
+}
+
+type inlineWriter struct {
+	r *Renderer
+}
+
+func (w inlineWriter) Write(p []byte) (int, error) {
+	r := w.r
+	if r.ptr == nil {
+		return 0, closedError("renderer")
+	}
+	if r.inline == nil {
+		return 0, newError("renderer is not inline")
+	}
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	region := r.inline
+	region.pending = append(region.pending, p...)
+	end := strings.LastIndexByte(string(region.pending), '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	lines := string(region.pending[:end])
+	region.pending = append(region.pending[:0], region.pending[end+1:]...)
+	if err := r.printAbove(lines); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// printAbove clears the region, prints text in its place and reserves the region
+// again below the text. The caller holds the suspend mutex.
+func (r *Renderer) printAbove(text string) error {
+	region := r.inline
+	text = strings.ReplaceAll(text, "\n", "\r\n")
+	if r.suspend.suspended {
+		// The shell owns the screen, so the text just goes below its output
+		_, err := r.terminal().Write([]byte(text + "\r\n"))
+		return err
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "\x1b[%d;1H\x1b[0m\x1b[J", region.top+1)
+	out.WriteString(text)
+	out.WriteString("\r\n")
+	// Scroll until the region fits below the text; on an empty screen these newlines
+	// only move the cursor down over blank rows
+	out.WriteString(strings.Repeat("\r\n", int(r.height-1)))
+	if _, err := r.terminal().Write([]byte(out.String())); err != nil {
+		return err
+	}
+	r.setInlineTop(region.top + printedRows(text, r.width))
+	r.forceRender = true
+	return nil
+}
+
+// inlineTop asks the terminal for the cursor position to find the first row of the
+// region the native setup reserves from the cursor down, scrolling if it doesn't fit.
+// It runs before the setup, whose probes are answered with cursor position reports
+// as well.
+func (r *Renderer) inlineTop() uint32 {
+	top := r.inline.rows - r.height
+	ctx, cancel := context.WithTimeout(context.Background(), inlineQueryTimeout)
+	defer cancel()
+	if row, _, err := r.QueryCursorPosition(ctx); err == nil {
+		top = min(row-1, top)
+	}
+	return top
+}
+
+// setInlineTop moves the region to start at row top, or as far down as it fits.
+func (r *Renderer) setInlineTop(top uint32) {
+	r.inline.top = min(top, r.inline.rows-r.height)
+	r.renderOffset = r.inline.top
+	C.setRenderOffset(r.ptr, C.uint32_t(r.renderOffset))
+}
+
+// applyInlineResize follows a terminal size change: the renderer takes the new width
+// and keeps its height unless the terminal got shorter, and the region moves up if it
+// no longer fits. Terminals rewrap their lines when the width changes, which scrambles
+// the region, so it is cleared and redrawn in full.
+func (r *Renderer) applyInlineResize(size Size, callbacks []resizeCallback) error {
+	region := r.inline
+	height := min(region.height, size.Height)
+	if size.Width == r.width && height == r.height && size.Height == region.rows {
+		return nil
+	}
+	region.rows = size.Height
+	top := min(region.top, size.Height-height)
+	if _, err := fmt.Fprintf(r.terminal(), "\x1b[%d;1H\x1b[0m\x1b[J", top+1); err != nil {
+		return err
+	}
+	if err := r.Resize(size.Width, height); err != nil {
+		return err
+	}
+	r.setInlineTop(top)
+	r.forceRender = true
+	for _, c := range callbacks {
+		c.fn(size.Width, height)
+	}
+	return nil
+}
+
+// leaveInline returns the sequence that hands the screen back to the shell: the
+// cursor goes below the last frame, or to the top of the region after erasing it.
+func (r *Renderer) leaveInline(keep bool) string {
+	if keep {
+		return fmt.Sprintf("\x1b[%d;1H\x1b[0m\r\n", r.inline.top+r.height)
+	}
+	return fmt.Sprintf("\x1b[%d;1H\x1b[0m\x1b[J", r.inline.top+1)
+}
+
+// interceptStdout replaces os.Stdout with a pipe whose output is printed above the
+// region, so fmt.Println and friends don't write into it.
+func (r *Renderer) interceptStdout() error {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	region := r.inline
+	region.stdout, region.pipe = os.Stdout, writer
+	region.captured = make(chan struct{})
+	os.Stdout = writer
+	go func() {
+		defer close(region.captured)
+		io.Copy(inlineWriter{r}, reader)
+		reader.Close()
+	}()
+	return nil
+}
+
+// closeInline restores os.Stdout, prints what is left of the captured output and
+// erases the region or moves the cursor below it. The renderer is still open.
+func (r *Renderer) closeInline() {
+	region := r.inline
+	if region.pipe != nil {
+		os.Stdout = region.stdout
+		region.pipe.Close()
+		<-region.captured
+		region.pipe = nil
+	}
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	if len(region.pending) > 0 {
+		r.printAbove(string(region.pending))
+		region.pending = nil
+	}
+	if !r.suspend.suspended && r.terminalSetup {
+		r.terminal().Write([]byte(r.leaveInline(region.keep)))
+	}
+	region.stdout = nil
+	// The native shutdown clears the whole screen when there is no render offset
+	C.setRenderOffset(r.ptr, C.uint32_t(max(r.renderOffset, 1)))
+}
+
+// printedRows returns the number of terminal rows text takes up when printed at a
+// width of cells, with escape sequences taking no space.
+func printedRows(text string, width uint32) uint32 {
+	var rows uint32
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		cells := uint32(stringWidth(stripEscapes(line)))
+		rows += max(1, (cells+width-1)/width)
+	}
+	return rows
+}
+
+// stripEscapes removes CSI, OSC and other escape sequences from s.
+func stripEscapes(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			out.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			break
+		}
+		switch s[i+1] {
+		case '[':
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+		case ']', 'P', '_':
+			// String sequences end with BEL or ST
+			i += 2
+			for i < len(s) && s[i] != '\x07' && !(s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\') {
+				i++
+			}
+			if i < len(s) && s[i] == '\x1b' {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return out.String()
+}
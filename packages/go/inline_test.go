@@ -0,0 +1,95 @@
+package opentui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrintedRows(t *testing.T) {
+	tests := []struct {
+		text  string
+		width uint32
+		want  uint32
+	}{
+		{"", 10, 1},
+		{"hello", 10, 1},
+		{"0123456789", 10, 1},
+		{"0123456789a", 10, 2},
+		{"a\nb\r\nc", 10, 3},
+		{"\x1b[31mred\x1b[0m \x1b]8;;https://x\x1b\\link\x1b]8;;\x1b\\", 8, 1},
+		{"日本語", 4, 2},
+	}
+	for _, tt := range tests {
+		if got := printedRows(tt.text, tt.width); got != tt.want {
+			t.Errorf("printedRows(%q, %d) = %d, want %d", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestInlineRenderer(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 10, Height: 24, Inline: true, InlineHeight: 3, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if width, height := renderer.width, renderer.height; width != 10 || height != 3 {
+		t.Errorf("inline renderer is %dx%d, want 10x3", width, height)
+	}
+	if err := renderer.SetupTerminal(true); err == nil {
+		t.Error("an inline renderer should refuse the alternate screen")
+	}
+
+	// The region starts at the cursor
+	go func() {
+		for !renderer.CursorQueryPending() {
+			time.Sleep(time.Millisecond)
+		}
+		writer.Write([]byte("\x1b[10;1R"))
+	}()
+	if err := renderer.SetupTerminal(false); err != nil {
+		t.Fatalf("SetupTerminal failed: %v", err)
+	}
+	if renderer.renderOffset != 9 {
+		t.Errorf("region starts at row %d, want 9", renderer.renderOffset)
+	}
+
+	out.Reset()
+	if err := renderer.PrintAbove("one\ntwo\n"); err != nil {
+		t.Fatalf("PrintAbove failed: %v", err)
+	}
+	if got, want := out.String(), "\x1b[10;1H\x1b[0m\x1b[Jone\r\ntwo\r\n\r\n\r\n"; got != want {
+		t.Errorf("PrintAbove wrote %q, want %q", got, want)
+	}
+	if renderer.renderOffset != 11 {
+		t.Errorf("region moved to row %d, want 11", renderer.renderOffset)
+	}
+
+	// Near the bottom the region stays on the last rows
+	w := renderer.InlineWriter()
+	io.WriteString(w, strings.Repeat("line\n", 20)+"partial")
+	if renderer.renderOffset != 21 {
+		t.Errorf("region moved to row %d, want 21", renderer.renderOffset)
+	}
+
+	// A shorter terminal moves the region up
+	renderer.resize.pending = &Size{Width: 8, Height: 20}
+	if err := renderer.applyPendingResize(); err != nil {
+		t.Fatalf("resize failed: %v", err)
+	}
+	if renderer.width != 8 || renderer.height != 3 || renderer.renderOffset != 17 {
+		t.Errorf("after resize: %dx%d at row %d, want 8x3 at row 17", renderer.width, renderer.height, renderer.renderOffset)
+	}
+
+	out.Reset()
+	renderer.Close()
+	if got := out.String(); !strings.Contains(got, "partial\r\n") || !strings.Contains(got, "\x1b[18;1H\x1b[0m\x1b[J") {
+		t.Errorf("Close should print the partial line and erase the region, got %q", got)
+	}
+}
@@ -0,0 +1,156 @@
+package opentui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+)
+
+// ITerm2ImageOptions controls how DrawITerm2Image sizes an image.
+// Dimensions that are left at zero are derived from the image size.
+type ITerm2ImageOptions struct {
+	Width               uint32 // Width in cells
+	Height              uint32 // Height in cells
+	WidthPercent        uint8  // Width in percent of the screen, overrides Width
+	HeightPercent       uint8  // Height in percent of the screen, overrides Height
+	PreserveAspectRatio bool   // Fit the image into the size without stretching it
+}
+
+// DrawITerm2Image draws encoded image data (PNG, JPEG, GIF or anything else the terminal
+// can decode) at cell (x, y) using the iTerm2 inline image protocol (OSC 1337 File=).
+// Like DrawSixel, the image is redrawn whenever the cells below it change until
+// ClearImages is called.
+// Use GetTerminalCapabilities to check SupportsITerm2Images before calling this.
+func (r *Renderer) DrawITerm2Image(x, y uint32, data []byte, opts ITerm2ImageOptions) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if len(data) == 0 {
+		return newError("image data is empty")
+	}
+	if x >= r.width || y >= r.height {
+		return nil
+	}
+
+	preserve := 0
+	if opts.PreserveAspectRatio {
+		preserve = 1
+	}
+
+	var seq bytes.Buffer
+	fmt.Fprintf(&seq, "\x1b]1337;File=inline=1;size=%d;width=%s;height=%s;preserveAspectRatio=%d:",
+		len(data),
+		iterm2Dimension(opts.Width, opts.WidthPercent),
+		iterm2Dimension(opts.Height, opts.HeightPercent),
+		preserve)
+	seq.WriteString(base64.StdEncoding.EncodeToString(data))
+	seq.WriteString("\x07")
+
+	width, height := r.iterm2Cells(data, opts)
+	rect := Rect{
+		Position: Position{X: int32(x), Y: int32(y)},
+		Size: Size{
+			Width:  min(width, r.width-x),
+			Height: min(height, r.height-y),
+		},
+	}
+	return r.addImage(terminalImage{rect: rect, sequence: seq.Bytes()})
+}
+
+// iterm2Dimension formats a size argument of the iTerm2 protocol.
+func iterm2Dimension(cells uint32, percent uint8) string {
+	switch {
+	case percent > 0:
+		return fmt.Sprintf("%d%%", min(percent, 100))
+	case cells > 0:
+		return fmt.Sprint(cells)
+	default:
+		return "auto"
+	}
+}
+
+// iterm2Cells estimates the number of cells an inline image covers.
+// Automatic dimensions use the pixel size of the image if it can be decoded
+// and otherwise cover the rest of the screen.
+func (r *Renderer) iterm2Cells(data []byte, opts ITerm2ImageOptions) (uint32, uint32) {
+	width, height := opts.Width, opts.Height
+	if opts.WidthPercent > 0 {
+		width = max(r.width*uint32(min(opts.WidthPercent, 100))/100, 1)
+	}
+	if opts.HeightPercent > 0 {
+		height = max(r.height*uint32(min(opts.HeightPercent, 100))/100, 1)
+	}
+	if width > 0 && height > 0 {
+		return width, height
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width == 0 || config.Height == 0 {
+		if width == 0 {
+			width = r.width
+		}
+		if height == 0 {
+			height = r.height
+		}
+		return width, height
+	}
+
+	// Scale a missing dimension along with the given one in pixels
+	pixelWidth, pixelHeight := uint64(config.Width), uint64(config.Height)
+	switch {
+	case width > 0:
+		pixelHeight = pixelHeight * uint64(width) * defaultCellWidth / pixelWidth
+	case height > 0:
+		pixelWidth = pixelWidth * uint64(height) * defaultCellHeight / pixelHeight
+	}
+	if width == 0 {
+		width = uint32((pixelWidth + defaultCellWidth - 1) / defaultCellWidth)
+	}
+	if height == 0 {
+		height = uint32((pixelHeight + defaultCellHeight - 1) / defaultCellHeight)
+	}
+	return max(width, 1), max(height, 1)
+}
+
+// DrawImageAuto draws an image at cell (x, y) with the best protocol the terminal supports:
+// iTerm2 inline images, then sixel graphics. Without either, the image is drawn into the
+// next buffer with half block characters at one cell per pixel column.
+func (r *Renderer) DrawImageAuto(x, y uint32, img image.Image) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if img == nil {
+		return newError("image is nil")
+	}
+
+	caps, err := r.GetTerminalCapabilities()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case caps.SupportsITerm2Images:
+		var data bytes.Buffer
+		if err := png.Encode(&data, img); err != nil {
+			return err
+		}
+		return r.DrawITerm2Image(x, y, data.Bytes(), ITerm2ImageOptions{PreserveAspectRatio: true})
+	case caps.SupportsSixel:
+		return r.DrawSixel(x, y, img, SixelOptions{})
+	}
+
+	buffer, err := r.GetNextBuffer()
+	if err != nil {
+		return err
+	}
+	rgba, ok := img.(*image.RGBA)
+	if !ok {
+		bounds := img.Bounds()
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	}
+	return buffer.DrawPixelsHalfBlock(x, y, rgba)
+}
@@ -0,0 +1,74 @@
+package opentui
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestITerm2Dimension(t *testing.T) {
+	tests := []struct {
+		cells   uint32
+		percent uint8
+		want    string
+	}{
+		{0, 0, "auto"},
+		{12, 0, "12"},
+		{12, 50, "50%"},
+		{0, 150, "100%"},
+	}
+	for _, tt := range tests {
+		if got := iterm2Dimension(tt.cells, tt.percent); got != tt.want {
+			t.Errorf("iterm2Dimension(%d, %d) = %q, want %q", tt.cells, tt.percent, got, tt.want)
+		}
+	}
+}
+
+func TestITerm2Cells(t *testing.T) {
+	var data bytes.Buffer
+	if err := png.Encode(&data, image.NewRGBA(image.Rect(0, 0, 100, 100))); err != nil {
+		t.Fatal(err)
+	}
+	r := &Renderer{width: 80, height: 24}
+
+	tests := []struct {
+		name          string
+		opts          ITerm2ImageOptions
+		width, height uint32
+	}{
+		{"auto", ITerm2ImageOptions{}, 10, 5},
+		{"fixed", ITerm2ImageOptions{Width: 3, Height: 4}, 3, 4},
+		{"percent", ITerm2ImageOptions{WidthPercent: 50, HeightPercent: 50}, 40, 12},
+		{"width only", ITerm2ImageOptions{Width: 20}, 20, 10},
+	}
+	for _, tt := range tests {
+		w, h := r.iterm2Cells(data.Bytes(), tt.opts)
+		if w != tt.width || h != tt.height {
+			t.Errorf("%s: got %dx%d, want %dx%d", tt.name, w, h, tt.width, tt.height)
+		}
+	}
+
+	// Undecodable data covers the rest of the screen
+	if w, h := r.iterm2Cells([]byte("not an image"), ITerm2ImageOptions{Height: 2}); w != 80 || h != 2 {
+		t.Errorf("got %dx%d for unknown data", w, h)
+	}
+}
+
+func TestDetectITerm2Support(t *testing.T) {
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("LC_TERMINAL", "")
+	if detectITerm2Support() {
+		t.Error("iTerm2 detected from empty environment")
+	}
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	if !detectITerm2Support() {
+		t.Error("iTerm2 not detected from TERM_PROGRAM")
+	}
+
+	var d detectedCapabilities
+	d.parseCapabilityResponse([]byte("\x1bP>|WezTerm 20240203\x1b\\"))
+	if !d.iterm2 {
+		t.Error("iTerm2 protocol not detected from XTVERSION reply")
+	}
+}
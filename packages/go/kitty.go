@@ -0,0 +1,167 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// Kitty keyboard protocol flags, for EnableKittyKeyboard and PushKittyKeyboard
+const (
+	KittyDisambiguate     uint8 = 1 << iota // Report ambiguous keys such as Esc and Alt+key as escape codes
+	KittyReportEvents                       // Report key repeat and release events
+	KittyReportAlternates                   // Report the shifted and base layout keys as well
+	KittyReportAllKeys                      // Report every key as an escape code, including text keys
+	KittyReportText                         // Report the text a key produces along with it
+)
+
+// kittyQuery tracks the pending kitty keyboard flag queries (CSI ? u). Replies arrive
+// through ProcessCapabilityResponse, usually from the input goroutine.
+type kittyQuery struct {
+	mu      sync.Mutex
+	waiters []chan uint8
+}
+
+// PushKittyKeyboard pushes flags onto the terminal's stack of kitty keyboard
+// protocol modes (CSI > flags u), so a program run from this one can push and pop its
+// own without disturbing them. Every push not popped yet is popped on Close, and
+// popped and pushed again around Suspend. Terminals ignore flags they don't support;
+// QueryKittyKeyboard reports the flags that took effect.
+func (r *Renderer) PushKittyKeyboard(flags uint8) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
+	}
+	if _, err := fmt.Fprintf(r.terminal(), "\x1b[>%du", flags); err != nil {
+		return err
+	}
+	r.kittyStack = append(r.kittyStack, flags)
+	return nil
+}
+
+// PopKittyKeyboard pops the flags pushed last by PushKittyKeyboard (CSI < u),
+// restoring the mode that was active before.
+func (r *Renderer) PopKittyKeyboard() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if len(r.kittyStack) == 0 {
+		return newError("no kitty keyboard flags were pushed")
+	}
+	if _, err := r.terminal().Write([]byte("\x1b[<u")); err != nil {
+		return err
+	}
+	r.kittyStack = r.kittyStack[:len(r.kittyStack)-1]
+	return nil
+}
+
+// QueryKittyKeyboard asks the terminal for the active kitty keyboard protocol flags
+// with CSI ? u and waits for the reply until ctx is done. Queried after a push, the
+// reply tells which of the pushed flags the terminal supports. The flags are also
+// reported by GetTerminalCapabilities. Unless the renderer reads replies from its own
+// Input, the reply must be passed to ProcessCapabilityResponse from another goroutine
+// while this call waits. Returns ErrQueryTimeout when ctx expires, which usually
+// means the terminal doesn't implement the protocol.
+func (r *Renderer) QueryKittyKeyboard(ctx context.Context) (uint8, error) {
+	if r.ptr == nil {
+		return 0, closedError("renderer")
+	}
+
+	reply := make(chan uint8, 1)
+	r.kittyQuery.mu.Lock()
+	r.kittyQuery.waiters = append(r.kittyQuery.waiters, reply)
+	r.kittyQuery.mu.Unlock()
+	defer r.kittyQuery.removeWaiter(reply)
+
+	if _, err := r.terminal().Write([]byte("\x1b[?u")); err != nil {
+		return 0, err
+	}
+	r.readReplies()
+	select {
+	case flags := <-reply:
+		return flags, nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return 0, ErrQueryTimeout
+		}
+		return 0, ctx.Err()
+	}
+}
+
+// receive hands the first kitty keyboard flags reply in a response to the pending
+// queries, if there are any.
+func (q *kittyQuery) receive(response []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiters) == 0 {
+		return
+	}
+	flags, ok := kittyKeyboardReply(response)
+	if !ok {
+		return
+	}
+	for _, waiter := range q.waiters {
+		select {
+		case waiter <- flags:
+		default:
+		}
+	}
+	q.waiters = nil
+}
+
+func (q *kittyQuery) removeWaiter(reply chan uint8) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, waiter := range q.waiters {
+		if waiter == reply {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// popKittyStack returns the sequence popping every flag set pushed with
+// PushKittyKeyboard, or nothing if there are none.
+func (r *Renderer) popKittyStack() string {
+	if len(r.kittyStack) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("\x1b[<%du", len(r.kittyStack))
+}
+
+// pushKittyStack returns the sequence pushing the flag sets of PushKittyKeyboard
+// again, in order.
+func (r *Renderer) pushKittyStack() string {
+	var sequence string
+	for _, flags := range r.kittyStack {
+		sequence += fmt.Sprintf("\x1b[>%du", flags)
+	}
+	return sequence
+}
+
+// kittyKeyboardReply extracts the flags from the first reply to the kitty keyboard
+// protocol query (ESC [ ? flags u) contained in a response.
+func kittyKeyboardReply(response []byte) (uint8, bool) {
+	for {
+		start := bytes.Index(response, []byte("\x1b[?"))
+		if start < 0 {
+			return 0, false
+		}
+		response = response[start+3:]
+		end := 0
+		for end < len(response) && response[end] >= '0' && response[end] <= '9' {
+			end++
+		}
+		if end > 0 && end < len(response) && response[end] == 'u' {
+			flags, err := strconv.ParseUint(string(response[:end]), 10, 8)
+			if err == nil {
+				return uint8(flags), true
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package opentui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestKittyKeyboardReply(t *testing.T) {
+	tests := []struct {
+		response string
+		flags    uint8
+		ok       bool
+	}{
+		{"\x1b[?0u", 0, true},
+		{"x\x1b[?62;4c\x1b[?31u", 31, true},
+		{"\x1b[?u", 0, false},
+		{"\x1b[?62;4c", 0, false},
+		{"\x1b[?999u", 0, false},
+	}
+	for _, tt := range tests {
+		flags, ok := kittyKeyboardReply([]byte(tt.response))
+		if flags != tt.flags || ok != tt.ok {
+			t.Errorf("kittyKeyboardReply(%q) = %d, %v, want %d, %v", tt.response, flags, ok, tt.flags, tt.ok)
+		}
+	}
+}
+
+func TestKittyKeyboardStack(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+	renderer.suspend.stop = func() error { return nil }
+	renderer.suspend.foreground = func() bool { return true }
+	renderer.suspend.cook = func() (func() error, error) { return nil, nil }
+
+	if err := renderer.PopKittyKeyboard(); err == nil {
+		t.Error("popping without a push should fail")
+	}
+	renderer.PushKittyKeyboard(KittyDisambiguate | KittyReportEvents)
+	renderer.PushKittyKeyboard(KittyReportAllKeys)
+	renderer.PopKittyKeyboard()
+	renderer.PushKittyKeyboard(KittyDisambiguate)
+	if out.String() != "\x1b[>3u\x1b[>8u\x1b[<u\x1b[>1u" {
+		t.Errorf("pushes and pops written as %q", out.String())
+	}
+
+	// Suspend pops every push, Resume pushes them again in order
+	out.Reset()
+	renderer.Suspend()
+	if out.String() != "\x1b[<2u"+showCursor {
+		t.Errorf("Suspend wrote %q", out.String())
+	}
+	out.Reset()
+	renderer.Resume()
+	if out.String() != "\x1b[>3u\x1b[>1u" {
+		t.Errorf("Resume wrote %q", out.String())
+	}
+
+	out.Reset()
+	renderer.Close()
+	if out.String() != "\x1b[<2u" {
+		t.Errorf("Close wrote %q, want the pushes popped", out.String())
+	}
+}
+
+func TestQueryKittyKeyboard(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	go writer.Write([]byte("\x1b[?5u"))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	flags, err := renderer.QueryKittyKeyboard(ctx)
+	if err != nil || flags != KittyDisambiguate|KittyReportAlternates {
+		t.Errorf("QueryKittyKeyboard = %d, %v, want 5", flags, err)
+	}
+	if out.String() != "\x1b[?u" {
+		t.Errorf("query written as %q", out.String())
+	}
+	caps, _ := renderer.GetTerminalCapabilities()
+	if !caps.SupportsKittyKeyboard || caps.KittyKeyboardFlags != 5 {
+		t.Errorf("capabilities report kitty keyboard %v with flags %d", caps.SupportsKittyKeyboard, caps.KittyKeyboardFlags)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := renderer.QueryKittyKeyboard(ctx); !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("unanswered query returned %v, want ErrQueryTimeout", err)
+	}
+}
@@ -0,0 +1,110 @@
+package opentui
+
+import (
+	"sort"
+)
+
+// Layer is an offscreen buffer composited over every frame right before it's
+// rendered, on top of whatever was drawn into the next buffer, e.g. for tooltips and
+// popovers. Layers with a higher Z go on top, and layers with the same Z in the order
+// they were created. The native renderer clears the next buffer after each render, so
+// a layer never becomes part of the content below it: hiding or closing it just stops
+// compositing it.
+type Layer struct {
+	Position Position // Cell of the next buffer the top left cell of the layer goes to
+	Z        int
+	Hidden   bool // Not composited while set
+
+	buffer   *Buffer
+	renderer *Renderer
+	seq      uint64
+}
+
+// NewLayer creates a layer of width by height cells at pos. Its buffer respects
+// alpha and starts out transparent, so only the cells drawn into it cover the frame.
+func (r *Renderer) NewLayer(pos Position, width, height uint32, z int) (*Layer, error) {
+	if r.ptr == nil {
+		return nil, closedError("renderer")
+	}
+	if err := checkDimensions(width, height); err != nil {
+		return nil, err
+	}
+	buffer := NewBuffer(width, height, true, WidthMethodUnicode)
+	if buffer == nil {
+		return nil, kindError(ErrAllocation, "failed to create layer buffer")
+	}
+	if err := buffer.Clear(Transparent); err != nil {
+		buffer.Close()
+		return nil, err
+	}
+	r.layerSeq++
+	layer := &Layer{Position: pos, Z: z, buffer: buffer, renderer: r, seq: r.layerSeq}
+	r.layers = append(r.layers, layer)
+	return layer, nil
+}
+
+// Buffer returns the buffer drawn over the frame. It keeps its content between frames.
+func (l *Layer) Buffer() *Buffer {
+	return l.buffer
+}
+
+// Rect returns the cells of the frame the layer covers.
+func (l *Layer) Rect() Rect {
+	width, _ := l.buffer.Width()
+	height, _ := l.buffer.Height()
+	return Rect{Position: l.Position, Size: Size{Width: width, Height: height}}
+}
+
+// Close removes the layer from the renderer and frees its buffer. The next frame is
+// rendered without it. Closing a layer twice does nothing.
+func (l *Layer) Close() error {
+	if l.renderer == nil {
+		return nil
+	}
+	layers := l.renderer.layers[:0]
+	for _, other := range l.renderer.layers {
+		if other != l {
+			layers = append(layers, other)
+		}
+	}
+	l.renderer.layers = layers
+	l.renderer = nil
+	return l.buffer.Close()
+}
+
+// compositeLayers draws the visible layers over the next buffer, lowest Z first.
+func (r *Renderer) compositeLayers() error {
+	if len(r.layers) == 0 {
+		return nil
+	}
+	next, err := r.GetNextBuffer()
+	if err != nil {
+		return err
+	}
+	layers := append([]*Layer(nil), r.layers...)
+	sort.Slice(layers, func(i, j int) bool {
+		if layers[i].Z != layers[j].Z {
+			return layers[i].Z < layers[j].Z
+		}
+		return layers[i].seq < layers[j].seq
+	})
+	for _, l := range layers {
+		if l.Hidden {
+			continue
+		}
+		rect := l.Rect()
+		if err := next.DrawFrameBuffer(rect.X, rect.Y, l.buffer, 0, 0, rect.Width, rect.Height); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// closeLayers frees the buffers of all the layers, when the renderer is closed.
+func (r *Renderer) closeLayers() {
+	for _, l := range r.layers {
+		l.renderer = nil
+		l.buffer.Close()
+	}
+	r.layers = nil
+}
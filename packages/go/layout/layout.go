@@ -0,0 +1,282 @@
+// Package layout splits a rect into regions with flexbox style rows and columns, so
+// that panels follow the size of the terminal instead of being placed at fixed cells.
+package layout
+
+import (
+	"math"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// Direction is the axis along which a container lays out its items.
+type Direction uint8
+
+const (
+	Horizontal Direction = iota // Items side by side, from left to right
+	Vertical                    // Items stacked from top to bottom
+)
+
+// cross returns the other axis.
+func (d Direction) cross() Direction {
+	if d == Horizontal {
+		return Vertical
+	}
+	return Horizontal
+}
+
+// SizingKind tells how an item takes its share of the main axis.
+type SizingKind uint8
+
+const (
+	SizeFixed   SizingKind = iota // Value cells
+	SizePercent                   // Value percent of the length of the container, gaps excluded
+	SizeFlex                      // A share of the cells left over, proportional to Value among flexible items
+)
+
+// Margin is the number of blank cells kept around an item, taken from its share.
+type Margin struct {
+	Top, Right, Bottom, Left uint32
+}
+
+// Uniform returns a margin of n cells on every side.
+func Uniform(n uint32) Margin {
+	return Margin{n, n, n, n}
+}
+
+// Item is a child of a container: the size it asks for along the main axis, with
+// bounds and margins, and optionally a container of its own laid out in its rect.
+// Items fill the cross axis, minus their margins.
+type Item struct {
+	Kind     SizingKind
+	Value    float64
+	Margin   Margin
+	Min, Max uint32     // Bounds on the main axis size, margins excluded; Max 0 is unbounded
+	Children *Container // Nested container, laid out in the rect of the item
+}
+
+// Fixed returns an item cells long.
+func Fixed(cells uint32) Item {
+	return Item{Kind: SizeFixed, Value: float64(cells)}
+}
+
+// Percent returns an item taking percent of the length of its container.
+func Percent(percent float64) Item {
+	return Item{Kind: SizePercent, Value: percent}
+}
+
+// Flex returns an item sharing the cells left over by fixed and percent items with the
+// other flexible items, in proportion to weight.
+func Flex(weight float64) Item {
+	return Item{Kind: SizeFlex, Value: weight}
+}
+
+// WithMargin returns the item with margin m.
+func (it Item) WithMargin(m Margin) Item {
+	it.Margin = m
+	return it
+}
+
+// WithMin returns the item with a size of at least cells along the main axis.
+func (it Item) WithMin(cells uint32) Item {
+	it.Min = cells
+	return it
+}
+
+// WithMax returns the item with a size of at most cells along the main axis.
+func (it Item) WithMax(cells uint32) Item {
+	it.Max = cells
+	return it
+}
+
+// Containing returns the item with c laid out in its rect.
+func (it Item) Containing(c *Container) Item {
+	it.Children = c
+	return it
+}
+
+// clamp bounds a size to Min and Max.
+func (it Item) clamp(size float64) float64 {
+	size = math.Max(size, float64(it.Min))
+	if it.Max > 0 {
+		size = math.Min(size, float64(it.Max))
+	}
+	return size
+}
+
+// margins returns the margins of the item before and after it along an axis.
+func (it Item) margins(dir Direction) (before, after uint32) {
+	if dir == Horizontal {
+		return it.Margin.Left, it.Margin.Right
+	}
+	return it.Margin.Top, it.Margin.Bottom
+}
+
+// Container lays out items along one axis, with Gap blank cells between them.
+type Container struct {
+	Direction Direction
+	Gap       uint32
+	Items     []Item
+}
+
+// Row returns a container laying out items side by side.
+func Row(items ...Item) *Container {
+	return &Container{Direction: Horizontal, Items: items}
+}
+
+// Column returns a container stacking items from top to bottom.
+func Column(items ...Item) *Container {
+	return &Container{Direction: Vertical, Items: items}
+}
+
+// WithGap returns the container with gap blank cells between its items.
+func (c *Container) WithGap(gap uint32) *Container {
+	c.Gap = gap
+	return c
+}
+
+// Layout resolves the rects of the items in rect. Items containing a container are
+// replaced by the rects of that container's items, depth first, so the result has a
+// rect for every leaf item in the order they were declared.
+//
+// Sizes are resolved along the main axis in cells that may be fractional, then rounded
+// at the boundaries between items, so that flexible items always tile rect exactly.
+// When fixed and percent items leave no room, flexible items shrink down to their Min
+// first, then the other items shrink in proportion to what they have above their Min.
+// Whatever still doesn't fit is cut off at the end of rect.
+func (c *Container) Layout(rect opentui.Rect) []opentui.Rect {
+	var rects []opentui.Rect
+	for i, r := range c.layoutItems(rect) {
+		if children := c.Items[i].Children; children != nil {
+			rects = append(rects, children.Layout(r)...)
+		} else {
+			rects = append(rects, r)
+		}
+	}
+	return rects
+}
+
+// layoutItems returns the rect of each item of the container, nested containers left
+// unresolved.
+func (c *Container) layoutItems(rect opentui.Rect) []opentui.Rect {
+	length, cross := rect.Width, rect.Height
+	if c.Direction == Vertical {
+		length, cross = rect.Height, rect.Width
+	}
+	n := len(c.Items)
+	if n == 0 {
+		return nil
+	}
+	gaps := uint64(c.Gap) * uint64(n-1)
+	available := float64(uint64(length) - min(gaps, uint64(length)))
+	sizes := c.sizes(available)
+
+	rects := make([]opentui.Rect, n)
+	// Boundaries are rounded from the exact running total, so rounding errors don't
+	// add up and the items reach the end of the rect when their sizes do
+	position := 0.0
+	limit := float64(length)
+	for i, item := range c.Items {
+		before, after := item.margins(c.Direction)
+		start := math.Min(position+float64(before), limit)
+		end := math.Min(start+sizes[i], limit)
+		position = end + float64(after)
+		if i < n-1 {
+			position += float64(c.Gap)
+		}
+
+		from, to := uint32(math.Round(start)), uint32(math.Round(end))
+		crossBefore, crossAfter := item.margins(c.Direction.cross())
+		crossSize := cross - min(crossBefore+crossAfter, cross)
+		if c.Direction == Horizontal {
+			rects[i] = opentui.Rect{
+				Position: opentui.Position{X: rect.X + int32(from), Y: rect.Y + int32(min(crossBefore, cross))},
+				Size:     opentui.Size{Width: to - from, Height: crossSize},
+			}
+		} else {
+			rects[i] = opentui.Rect{
+				Position: opentui.Position{X: rect.X + int32(min(crossBefore, cross)), Y: rect.Y + int32(from)},
+				Size:     opentui.Size{Width: crossSize, Height: to - from},
+			}
+		}
+	}
+	return rects
+}
+
+// sizes returns the main axis size of each item, margins excluded, for available
+// cells along the main axis.
+func (c *Container) sizes(available float64) []float64 {
+	sizes := make([]float64, len(c.Items))
+	used := 0.0
+	for i, item := range c.Items {
+		before, after := item.margins(c.Direction)
+		used += float64(before + after)
+		switch item.Kind {
+		case SizeFixed:
+			sizes[i] = item.clamp(item.Value)
+		case SizePercent:
+			sizes[i] = item.clamp(available * item.Value / 100)
+		case SizeFlex:
+			sizes[i] = float64(item.Min)
+		}
+		used += sizes[i]
+	}
+
+	if spare := available - used; spare > 0 {
+		c.grow(sizes, spare)
+	} else if spare < 0 {
+		c.shrink(sizes, -spare)
+	}
+	return sizes
+}
+
+// grow shares spare cells among the flexible items in proportion to their weights.
+// Items reaching their Max keep it and the rest is shared among the others.
+func (c *Container) grow(sizes []float64, spare float64) {
+	frozen := make([]bool, len(sizes))
+	for spare > 1e-9 {
+		weights := 0.0
+		for i, item := range c.Items {
+			if item.Kind == SizeFlex && !frozen[i] {
+				weights += math.Max(item.Value, 0)
+			}
+		}
+		if weights == 0 {
+			return
+		}
+		clamped := false
+		given := 0.0
+		for i, item := range c.Items {
+			if item.Kind != SizeFlex || frozen[i] {
+				continue
+			}
+			share := spare * math.Max(item.Value, 0) / weights
+			if item.Max > 0 && sizes[i]+share >= float64(item.Max) {
+				share = float64(item.Max) - sizes[i]
+				frozen[i], clamped = true, true
+			}
+			sizes[i] += share
+			given += share
+		}
+		spare -= given
+		if !clamped {
+			return
+		}
+	}
+}
+
+// shrink takes overflow cells from the items that were given more than their Min:
+// fixed and percent items in proportion to that excess, flexible items being at
+// their Min already.
+func (c *Container) shrink(sizes []float64, overflow float64) {
+	excess := 0.0
+	for i, item := range c.Items {
+		excess += sizes[i] - float64(item.Min)
+	}
+	if excess <= 0 {
+		return
+	}
+	ratio := math.Min(overflow/excess, 1)
+	for i, item := range c.Items {
+		sizes[i] -= (sizes[i] - float64(item.Min)) * ratio
+	}
+}
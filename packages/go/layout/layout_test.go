@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func rect(x, y int32, width, height uint32) opentui.Rect {
+	return opentui.Rect{Position: opentui.Position{X: x, Y: y}, Size: opentui.Size{Width: width, Height: height}}
+}
+
+// widths returns the widths of rects.
+func widths(rects []opentui.Rect) []uint32 {
+	out := make([]uint32, len(rects))
+	for i, r := range rects {
+		out[i] = r.Width
+	}
+	return out
+}
+
+func equal(a, b []uint32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRowSizes(t *testing.T) {
+	tests := []struct {
+		name  string
+		row   *Container
+		width uint32
+		want  []uint32
+	}{
+		{"equal weights round at the boundaries", Row(Flex(1), Flex(1), Flex(1)), 10, []uint32{3, 4, 3}},
+		{"fixed, percent and flexible", Row(Fixed(10), Percent(25), Flex(1)), 80, []uint32{10, 20, 50}},
+		{"weights", Row(Flex(1), Flex(3)), 20, []uint32{5, 15}},
+		{"max hands the rest to the others", Row(Flex(1).WithMax(5), Flex(1)), 20, []uint32{5, 15}},
+		{"min on a fixed item", Row(Fixed(2).WithMin(6), Flex(1)), 20, []uint32{6, 14}},
+		{"no flexible items leaves the end empty", Row(Fixed(5), Percent(50)), 20, []uint32{5, 10}},
+		{"flexible items shrink to their min first", Row(Fixed(30), Fixed(30), Flex(1).WithMin(4)), 40, []uint32{18, 18, 4}},
+		{"mins that don't fit are cut at the end", Row(Fixed(30).WithMin(30), Fixed(30).WithMin(30)), 40, []uint32{30, 10}},
+		{"gaps come off the length first", Row(Flex(1), Flex(1)).WithGap(2), 12, []uint32{5, 5}},
+		{"no room", Row(Fixed(3), Flex(1)), 0, []uint32{0, 0}},
+	}
+	for _, tt := range tests {
+		got := widths(tt.row.Layout(rect(0, 0, tt.width, 1)))
+		if !equal(got, tt.want) {
+			t.Errorf("%s: widths %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFlexTiling(t *testing.T) {
+	row := Row(Flex(1), Flex(2), Flex(3), Flex(1.5), Flex(0.7).WithMargin(Margin{Left: 1}))
+	for width := uint32(1); width <= 120; width++ {
+		x := int32(3)
+		for i, r := range row.Layout(rect(3, 0, width, 1)) {
+			if i == 4 {
+				x++
+			}
+			if r.X != x {
+				t.Fatalf("width %d: item %d at %d, want %d", width, i, r.X, x)
+			}
+			x += int32(r.Width)
+		}
+		if x != 3+int32(width) {
+			t.Errorf("width %d: items end at %d", width, x)
+		}
+	}
+}
+
+func TestNestedLayout(t *testing.T) {
+	screen := Column(
+		Fixed(1),
+		Flex(1).Containing(Row(Percent(30), Flex(1).WithMargin(Uniform(1)))),
+		Fixed(1),
+	)
+	got := screen.Layout(rect(0, 0, 80, 24))
+	want := []opentui.Rect{
+		rect(0, 0, 80, 1),
+		rect(0, 1, 24, 22),
+		rect(25, 2, 54, 20),
+		rect(0, 23, 80, 1),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d rects, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("rect %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package opentui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// linkTable maps buffer cells to hyperlink URLs. The native buffers know nothing about
+// links, so they are tracked on the Go side and emitted as OSC 8 sequences after Render.
+type linkTable struct {
+	width, height uint32
+	ids           []uint32 // link id per cell, 0 for none
+	urls          []string // URL of each link id, starting at id 1
+	index         map[string]uint32
+	showURLs      bool // draw links as text followed by the URL instead of recording them
+}
+
+// active reports whether any cell may hold a link.
+func (lt *linkTable) active() bool {
+	return lt != nil && len(lt.urls) > 0
+}
+
+// url returns the URL of the cell at index i, or "" if the cell has no link.
+func (lt *linkTable) url(i int) string {
+	if !lt.active() || i >= len(lt.ids) || lt.ids[i] == 0 {
+		return ""
+	}
+	return lt.urls[lt.ids[i]-1]
+}
+
+// set links the cells [x, x+n) of row y in a buffer of the given size to url,
+// or removes their links for "".
+func (lt *linkTable) set(x, y, n, width, height uint32, url string) {
+	if url == "" && !lt.active() {
+		return
+	}
+	if lt.width != width || lt.height != height {
+		lt.reset()
+		lt.width, lt.height = width, height
+	}
+	if y >= height || x >= width {
+		return
+	}
+	n = min(n, width-x)
+
+	var id uint32
+	if url != "" {
+		if lt.index == nil {
+			lt.index = make(map[string]uint32)
+		}
+		if id = lt.index[url]; id == 0 {
+			lt.urls = append(lt.urls, url)
+			id = uint32(len(lt.urls))
+			lt.index[url] = id
+		}
+	}
+	if cells := int(width * height); len(lt.ids) != cells {
+		if cap(lt.ids) >= cells {
+			lt.ids = lt.ids[:cells]
+			clear(lt.ids)
+		} else {
+			lt.ids = make([]uint32, cells)
+		}
+	}
+	row := lt.ids[y*width+x : y*width+x+n]
+	for i := range row {
+		row[i] = id
+	}
+}
+
+// clearRect removes the links of the cells inside a rectangle.
+func (lt *linkTable) clearRect(x, y, width, height uint32) {
+	if !lt.active() {
+		return
+	}
+	for row := y; row < y+height && row < lt.height; row++ {
+		lt.set(x, row, width, lt.width, lt.height, "")
+	}
+}
+
+// reset removes all links while keeping the allocated storage.
+func (lt *linkTable) reset() {
+	lt.ids = lt.ids[:0]
+	lt.urls = lt.urls[:0]
+	clear(lt.index)
+	lt.width, lt.height = 0, 0
+}
+
+// copyFrom replaces the links with the links of another table.
+func (lt *linkTable) copyFrom(other *linkTable) {
+	lt.reset()
+	lt.width, lt.height = other.width, other.height
+	lt.ids = append(lt.ids, other.ids...)
+	lt.urls = append(lt.urls, other.urls...)
+}
+
+// DrawTextLink draws text at the specified position like DrawText and turns it into
+// a hyperlink to url. A Renderer emits the link as an OSC 8 sequence around the
+// linked cells during Render. Drawing text, FillRect and Clear remove the links of the
+// cells they cover.
+//
+// When the terminal does not support hyperlinks, the text is drawn without a link,
+// followed by the URL in parentheses if enabled with Renderer.SetHyperlinkFallback.
+func (b *Buffer) DrawTextLink(text, url string, x, y uint32, fg RGBA, bg *RGBA, attrs Attributes) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	for i := 0; i < len(url); i++ {
+		// Control characters would terminate the OSC 8 sequence early
+		if url[i] < 0x20 || url[i] == 0x7f {
+			return newError("invalid hyperlink URL")
+		}
+	}
+	if b.links != nil && b.links.showURLs {
+		return b.DrawText(text+" ("+url+")", x, y, fg, bg, attrs)
+	}
+
+	if err := b.DrawText(text, x, y, fg, bg, attrs); err != nil {
+		return err
+	}
+	if b.links == nil {
+		b.links = &linkTable{}
+	}
+	width, height, err := b.Size()
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(text, "\n") {
+		line = b.expandTabs(strings.TrimSuffix(line, "\r"), int64(x))
+		b.links.set(x, y, uint32(stringWidth(line)), width, height, url)
+		y++
+	}
+	return nil
+}
+
+// drawFrameLinks gives the cells DrawFrameBuffer is about to draw over the links of
+// the frame buffer. Cells it leaves alone, or shows the character of through a
+// translucent space, keep their link.
+func (b *Buffer) drawFrameLinks(destX, destY int32, frameBuffer *Buffer, sourceX, sourceY, sourceWidth, sourceHeight uint32) error {
+	dst, err := b.directAccess()
+	if err != nil {
+		return err
+	}
+	src, err := frameBuffer.directAccess()
+	if err != nil {
+		return err
+	}
+	respectAlpha, err := frameBuffer.GetRespectAlpha()
+	if err != nil {
+		return err
+	}
+	// The native layer goes cell by cell, skipping transparent ones, unless it can copy
+	// whole rows
+	perCell := respectAlpha || dst.hasClusters() || src.hasClusters()
+	if b.links == nil {
+		b.links = &linkTable{}
+	}
+
+	for y := uint32(0); y < sourceHeight && sourceY+y < src.Height; y++ {
+		dy := int64(destY) + int64(y)
+		if dy < 0 || dy >= int64(dst.Height) {
+			continue
+		}
+		for x := uint32(0); x < sourceWidth && sourceX+x < src.Width; x++ {
+			dx := int64(destX) + int64(x)
+			if dx < 0 || dx >= int64(dst.Width) {
+				continue
+			}
+			si := (sourceY+y)*src.Width + sourceX + x
+			di := uint32(dy)*dst.Width + uint32(dx)
+			fg, bg := src.Foreground[si], src.Background[si]
+			if perCell && fg.A == 0 && bg.A == 0 {
+				continue
+			}
+			below := dst.Chars[di]
+			if respectAlpha && (fg.A < 1 || bg.A < 1) && src.Chars[si] == ' ' &&
+				below != 0 && below != ' ' && storedCharWidth(below) == 1 {
+				continue
+			}
+			b.links.set(uint32(dx), uint32(dy), 1, dst.Width, dst.Height, frameBuffer.links.url(int(si)))
+		}
+	}
+	return nil
+}
+
+// SetHyperlinkFallback sets whether DrawTextLink shows the URL after the link text
+// when the terminal does not support hyperlinks. It is off by default.
+func (r *Renderer) SetHyperlinkFallback(showURL bool) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.showLinkURLs = showURL
+	return nil
+}
+
+// hyperlinksSupported reports whether the terminal is known to support OSC 8.
+func (r *Renderer) hyperlinksSupported() bool {
+	return r.detected.hyperlinks || detectHyperlinkSupport()
+}
+
+// nextLinks returns the link table shared by the wrappers of the next buffer.
+func (r *Renderer) nextLinks() *linkTable {
+	if r.links == nil {
+		r.links = &linkTable{}
+		r.renderedLinks = &linkTable{}
+	}
+	r.links.showURLs = r.showLinkURLs && !r.hyperlinksSupported()
+	return r.links
+}
+
+// hyperlinkTerminals are terminal names known to support OSC 8 hyperlinks
+var hyperlinkTerminals = []string{"iTerm", "WezTerm", "vscode", "Hyper", "ghostty", "kitty", "foot", "contour", "Tabby", "rio"}
+
+// detectHyperlinkSupport checks the environment for a terminal that supports OSC 8 hyperlinks.
+func detectHyperlinkSupport() bool {
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("WT_SESSION") != "" || os.Getenv("DOMTERM") != "" {
+		return true
+	}
+	// VTE based terminals support hyperlinks since 0.50
+	if vte, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && vte >= 5000 {
+		return true
+	}
+	term := os.Getenv("TERM")
+	if strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "alacritty") {
+		return true
+	}
+	return isHyperlinkTerminal(os.Getenv("TERM_PROGRAM"))
+}
+
+func isHyperlinkTerminal(name string) bool {
+	name = strings.ToLower(name)
+	for _, terminal := range hyperlinkTerminals {
+		if strings.HasPrefix(name, strings.ToLower(terminal)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+package opentui
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns everything written to os.Stdout while fn runs.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Pipe failed: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		done <- string(data)
+	}()
+	fn()
+	writer.Close()
+	return <-done
+}
+
+func TestDrawTextLink(t *testing.T) {
+	buffer := newTestBuffer(t, 10, 3)
+
+	if err := buffer.DrawTextLink("docs\nx", "https://example.com", 2, 0, White, nil, 0); err != nil {
+		t.Fatalf("DrawTextLink failed: %v", err)
+	}
+	expectRows(t, buffer,
+		"  docs    ",
+		"  x       ",
+		"          ",
+	)
+
+	linked := func(x, y uint32) bool {
+		return buffer.links.url(int(y*10+x)) == "https://example.com"
+	}
+	for _, cell := range [][2]uint32{{2, 0}, {5, 0}, {2, 1}} {
+		if !linked(cell[0], cell[1]) {
+			t.Errorf("cell %v should be linked", cell)
+		}
+	}
+	if linked(1, 0) || linked(6, 0) || linked(3, 1) {
+		t.Error("link bled into neighboring cells")
+	}
+
+	// Drawing over a link removes it from the covered cells only
+	buffer.DrawText("ab", 4, 0, White, nil, 0)
+	buffer.FillRect(2, 1, 1, 1, Black)
+	if !linked(3, 0) || linked(4, 0) || linked(5, 0) || linked(2, 1) {
+		t.Error("overdrawn cells should lose their link")
+	}
+
+	if err := buffer.DrawTextLink("bad", "https://x\x1b]8;;evil", 0, 2, White, nil, 0); err == nil {
+		t.Error("DrawTextLink should reject URLs with control characters")
+	}
+	buffer.Clear(Black)
+	if linked(3, 0) {
+		t.Error("Clear should remove links")
+	}
+}
+
+func TestDrawFrameBufferLinks(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 1)
+	buffer.DrawTextLink("docs", "https://a.example", 0, 0, White, nil, 0)
+
+	layer := NewBuffer(4, 1, true, WidthMethodUnicode)
+	if layer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer layer.Close()
+	layer.Clear(Transparent)
+	layer.DrawTextLink("b", "https://b.example", 0, 0, White, nil, 0)
+	layer.DrawText("x", 2, 0, White, nil, 0)
+
+	// Transparent cells of the layer keep the link below
+	if err := buffer.DrawFrameBuffer(1, 0, layer, 0, 0, 4, 1); err != nil {
+		t.Fatalf("DrawFrameBuffer failed: %v", err)
+	}
+	expectRows(t, buffer, "dbcx  ")
+	for x, want := range []string{"https://a.example", "https://b.example", "https://a.example", "", "", ""} {
+		if got := buffer.links.url(x); got != want {
+			t.Errorf("cell %d links to %q, want %q", x, got, want)
+		}
+	}
+}
+
+func TestRenderHyperlinks(t *testing.T) {
+	t.Setenv("KITTY_WINDOW_ID", "1")
+	renderer := NewRenderer(10, 2)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if caps, _ := renderer.GetTerminalCapabilities(); !caps.SupportsHyperlinks {
+		t.Error("SupportsHyperlinks should be detected from KITTY_WINDOW_ID")
+	}
+
+	next, _ := renderer.GetNextBuffer()
+	next.Clear(Black)
+	next.DrawTextLink("go", "https://go.dev", 1, 0, White, nil, 0)
+	out := captureStdout(t, func() { renderer.Render(false) })
+	if !strings.Contains(out, "\x1b[1;2H\x1b]8;;https://go.dev\x1b\\") || !strings.HasSuffix(out, "o\x1b]8;;\x1b\\\x1b[0m\x1b8") {
+		t.Errorf("unexpected hyperlink output %q", out)
+	}
+
+	// The same text without the link has to be rewritten to drop the link on the terminal
+	next, _ = renderer.GetNextBuffer()
+	next.Clear(Black)
+	next.DrawText("go", 1, 0, White, nil, 0)
+	out = captureStdout(t, func() { renderer.Render(false) })
+	if !strings.Contains(out, "\x1b[1;2H") || strings.Contains(out, "go.dev") {
+		t.Errorf("unexpected output after removing the link %q", out)
+	}
+
+	// Nothing is left to fix up once the terminal matches
+	next, _ = renderer.GetNextBuffer()
+	next.Clear(Black)
+	next.DrawText("go", 1, 0, White, nil, 0)
+	if out = captureStdout(t, func() { renderer.Render(false) }); out != "" {
+		t.Errorf("unexpected output %q", out)
+	}
+}
+
+func TestHyperlinkFallback(t *testing.T) {
+	for _, name := range []string{"KITTY_WINDOW_ID", "WT_SESSION", "DOMTERM", "VTE_VERSION", "TERM_PROGRAM"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("TERM", "xterm-256color")
+	renderer := NewRenderer(20, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	renderer.SetHyperlinkFallback(true)
+	next, _ := renderer.GetNextBuffer()
+	next.Clear(Black)
+	next.DrawTextLink("go", "go.dev", 0, 0, White, nil, 0)
+	expectRows(t, next, "go (go.dev)         ")
+	if out := captureStdout(t, func() { renderer.Render(false) }); out != "" {
+		t.Errorf("unsupported terminals should get no OSC 8 output, got %q", out)
+	}
+}
+
+func TestDetectHyperlinkSupport(t *testing.T) {
+	tests := []struct {
+		env  map[string]string
+		want bool
+	}{
+		{map[string]string{}, false},
+		{map[string]string{"TERM_PROGRAM": "WezTerm"}, true},
+		{map[string]string{"TERM_PROGRAM": "Apple_Terminal"}, false},
+		{map[string]string{"VTE_VERSION": "6003"}, true},
+		{map[string]string{"VTE_VERSION": "4205"}, false},
+		{map[string]string{"TERM": "xterm-kitty"}, true},
+		{map[string]string{"WT_SESSION": "abc"}, true},
+	}
+
+	for _, tt := range tests {
+		for _, name := range []string{"KITTY_WINDOW_ID", "WT_SESSION", "DOMTERM", "VTE_VERSION", "TERM_PROGRAM"} {
+			t.Setenv(name, tt.env[name])
+		}
+		t.Setenv("TERM", tt.env["TERM"])
+		if got := detectHyperlinkSupport(); got != tt.want {
+			t.Errorf("detectHyperlinkSupport(%v) = %v, want %v", tt.env, got, tt.want)
+		}
+	}
+
+	var d detectedCapabilities
+	d.parseCapabilityResponse([]byte("\x1bP>|foot(1.16.2)\x1b\\"))
+	if !d.hyperlinks {
+		t.Error("foot should be detected from XTVERSION")
+	}
+}
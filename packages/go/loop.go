@@ -0,0 +1,117 @@
+package opentui
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// RunLoop draws and renders frames at up to fps frames per second until ctx is
+// cancelled or frame returns an error. Each frame fetches the next buffer, calls frame
+// with the time since the previous frame and renders the result. A frame that runs
+// over its budget doesn't queue up more: ticks missed meanwhile are skipped. The
+// achieved FPS and the longest frame are reported through UpdateStats once a second.
+// Returns nil when ctx is cancelled, or the error returned by frame or Render. A
+// headless renderer draws and renders a single frame and returns io.EOF. Each frame
+// holds the renderer lock, so frame must not call Render or Lock.
+func (r *Renderer) RunLoop(ctx context.Context, fps int, frame func(dt time.Duration, buf *Buffer) error) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if fps <= 0 {
+		return newError("fps must be positive")
+	}
+
+	ticker := time.NewTicker(time.Second / time.Duration(fps))
+	defer ticker.Stop()
+
+	last := time.Now()
+	stats := frameStats{start: last}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			// A tick may be ready at the same time as the cancellation
+			if ctx.Err() != nil {
+				return nil
+			}
+		}
+
+		start := time.Now()
+		callback, err := r.runFrame(start.Sub(last), frame)
+		if err != nil {
+			return err
+		}
+		last = start
+		if r.headless {
+			return io.EOF
+		}
+
+		end := time.Now()
+		if s, ok := stats.add(callback, end.Sub(start), end); ok {
+			r.UpdateStats(s)
+		}
+		// Drop the tick that came in while an overrunning frame was drawn
+		select {
+		case <-ticker.C:
+		default:
+		}
+	}
+}
+
+// runFrame draws and renders one frame of RunLoop with the renderer locked, and
+// returns the time spent in frame.
+func (r *Renderer) runFrame(dt time.Duration, frame func(dt time.Duration, buf *Buffer) error) (time.Duration, error) {
+	r.frame.Lock()
+	defer r.frame.Unlock()
+	start := time.Now()
+	// Resize before fetching the buffer so the frame draws at the new size
+	if err := r.applyPendingResize(); err != nil {
+		return 0, err
+	}
+	buf, err := r.GetNextBuffer()
+	if err != nil {
+		return 0, err
+	}
+	if err := frame(dt, buf); err != nil {
+		return 0, err
+	}
+	callback := time.Since(start)
+	return callback, r.renderLocked(false, nil)
+}
+
+// frameStats accumulates frame times over one second for UpdateStats.
+type frameStats struct {
+	start    time.Time
+	frames   uint32
+	longest  time.Duration
+	callback time.Duration // total time spent in the frame callback
+}
+
+// add records a frame and, once a second has passed since the first one, returns
+// the stats for UpdateStats: Time is the longest frame and FrameCallbackTime the
+// average time spent in the callback, both in milliseconds.
+func (s *frameStats) add(callback, frame time.Duration, now time.Time) (Stats, bool) {
+	s.frames++
+	s.callback += callback
+	if frame > s.longest {
+		s.longest = frame
+	}
+
+	elapsed := now.Sub(s.start)
+	if elapsed < time.Second {
+		return Stats{}, false
+	}
+	stats := Stats{
+		Time:              milliseconds(s.longest),
+		FPS:               uint32(float64(s.frames)/elapsed.Seconds() + 0.5),
+		FrameCallbackTime: milliseconds(s.callback) / float64(s.frames),
+	}
+	*s = frameStats{start: now}
+	return stats, true
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
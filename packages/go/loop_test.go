@@ -0,0 +1,63 @@
+package opentui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFrameStats(t *testing.T) {
+	start := time.Unix(0, 0)
+	s := frameStats{start: start}
+
+	frames := []time.Duration{10 * time.Millisecond, 40 * time.Millisecond, 10 * time.Millisecond}
+	for i, frame := range frames[:2] {
+		if _, ok := s.add(frame/2, frame, start.Add(time.Duration(i+1)*100*time.Millisecond)); ok {
+			t.Fatal("stats reported before a second passed")
+		}
+	}
+	stats, ok := s.add(frames[2]/2, frames[2], start.Add(1500*time.Millisecond))
+	if !ok {
+		t.Fatal("stats should be reported after a second")
+	}
+	if stats.FPS != 2 || stats.Time != 40 || stats.FrameCallbackTime != 10 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if s.frames != 0 || s.longest != 0 || !s.start.Equal(start.Add(1500*time.Millisecond)) {
+		t.Errorf("stats should restart after reporting, got %+v", s)
+	}
+}
+
+func TestRunLoop(t *testing.T) {
+	renderer := NewRenderer(4, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if err := renderer.RunLoop(context.Background(), 0, nil); err == nil {
+		t.Error("RunLoop should reject a non-positive fps")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	frames := 0
+	err := renderer.RunLoop(ctx, 200, func(dt time.Duration, buf *Buffer) error {
+		if dt <= 0 || buf == nil {
+			t.Errorf("frame %d got dt %v, buffer %v", frames, dt, buf)
+		}
+		if frames++; frames == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil || frames != 3 {
+		t.Errorf("cancelled loop returned %v after %d frames", err, frames)
+	}
+
+	stop := errors.New("stop")
+	err = renderer.RunLoop(context.Background(), 200, func(time.Duration, *Buffer) error { return stop })
+	if err != stop {
+		t.Errorf("RunLoop returned %v, want the frame error", err)
+	}
+}
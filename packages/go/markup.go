@@ -0,0 +1,241 @@
+package opentui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MarkupError reports a problem in markup passed to ParseMarkup.
+type MarkupError struct {
+	Offset  int    // Byte offset of the offending tag in the markup
+	Message string // Description of the problem
+}
+
+func (e *MarkupError) Error() string {
+	return fmt.Sprintf("markup: %s at byte %d", e.Message, e.Offset)
+}
+
+// markupAttributes maps attribute tag names to text attributes
+var markupAttributes = map[string]Attributes{
+	"bold":      AttrBold,
+	"dim":       AttrDim,
+	"italic":    AttrItalic,
+	"underline": AttrUnderline,
+	"blink":     AttrBlink,
+	"reverse":   AttrReverse,
+	"hidden":    AttrHidden,
+	"strike":    AttrStrike,
+}
+
+// markupColors maps color names accepted by fg= and bg= tags to colors
+var markupColors = map[string]RGBA{
+	"black":   Black,
+	"white":   White,
+	"red":     Red,
+	"green":   Green,
+	"blue":    Blue,
+	"yellow":  Yellow,
+	"cyan":    Cyan,
+	"magenta": Magenta,
+	"gray":    Gray,
+}
+
+// markupTag is an open tag in the markup
+type markupTag struct {
+	name   string // attribute name, "fg" or "bg"
+	attr   Attributes
+	color  RGBA
+	offset int
+}
+
+// ParseMarkup converts markup into styled text chunks.
+//
+// Tags in square brackets change the style of the text that follows:
+// [bold], [dim], [italic], [underline], [blink], [reverse], [hidden] and [strike] set
+// attributes, [fg=COLOR] and [bg=COLOR] set colors given as #rgb, #rrggbb or one of
+// black, white, red, green, blue, yellow, cyan, magenta and gray. [/name] closes the
+// innermost open tag, which must have that name, and [/] closes the innermost tag
+// whatever its name. Tags left open apply to the end of the text. "[[" is a literal
+// "[" while a "]" outside a tag is taken as is.
+//
+// Unknown tags, bad colors and mismatched closers are reported as a *MarkupError.
+func ParseMarkup(s string) ([]TextChunk, error) {
+	var chunks []TextChunk
+	var stack []markupTag
+	var text strings.Builder
+
+	emit := func() {
+		if text.Len() == 0 {
+			return
+		}
+		chunk := TextChunk{Text: text.String()}
+		var attrs Attributes
+		for _, tag := range stack {
+			switch tag.name {
+			case "fg":
+				fg := tag.color
+				chunk.Foreground = &fg
+			case "bg":
+				bg := tag.color
+				chunk.Background = &bg
+			default:
+				attrs |= tag.attr
+			}
+		}
+		if attrs != 0 {
+			chunk.Attributes = &attrs
+		}
+		chunks = append(chunks, chunk)
+		text.Reset()
+	}
+
+	for i := 0; i < len(s); {
+		open := strings.IndexByte(s[i:], '[')
+		if open < 0 {
+			text.WriteString(s[i:])
+			break
+		}
+		text.WriteString(s[i : i+open])
+		i += open
+		if strings.HasPrefix(s[i:], "[[") {
+			text.WriteByte('[')
+			i += 2
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], ']')
+		if end < 0 {
+			return nil, &MarkupError{Offset: i, Message: "unterminated tag"}
+		}
+		tag := s[i+1 : i+end]
+
+		if name, ok := strings.CutPrefix(tag, "/"); ok {
+			if len(stack) == 0 {
+				return nil, &MarkupError{Offset: i, Message: fmt.Sprintf("closing tag [%s] without an open tag", tag)}
+			}
+			top := stack[len(stack)-1]
+			if name != "" && name != top.name {
+				return nil, &MarkupError{Offset: i, Message: fmt.Sprintf("closing tag [%s] does not match [%s] from byte %d", tag, top.name, top.offset)}
+			}
+			emit()
+			stack = stack[:len(stack)-1]
+		} else {
+			parsed, err := parseMarkupTag(tag, i)
+			if err != nil {
+				return nil, err
+			}
+			emit()
+			stack = append(stack, parsed)
+		}
+		i += end + 1
+	}
+	emit()
+	return chunks, nil
+}
+
+// parseMarkupTag parses the contents of an opening tag found at offset.
+func parseMarkupTag(tag string, offset int) (markupTag, error) {
+	if attr, ok := markupAttributes[tag]; ok {
+		return markupTag{name: tag, attr: attr, offset: offset}, nil
+	}
+	name, value, ok := strings.Cut(tag, "=")
+	if !ok || (name != "fg" && name != "bg") {
+		return markupTag{}, &MarkupError{Offset: offset, Message: fmt.Sprintf("unknown tag [%s]", tag)}
+	}
+	color, ok := parseMarkupColor(value)
+	if !ok {
+		return markupTag{}, &MarkupError{Offset: offset, Message: fmt.Sprintf("invalid color %q", value)}
+	}
+	return markupTag{name: name, color: color, offset: offset}, nil
+}
+
+// parseMarkupColor parses a color name, #rgb or #rrggbb.
+func parseMarkupColor(value string) (RGBA, bool) {
+	if color, ok := markupColors[value]; ok {
+		return color, true
+	}
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || (len(hex) != 3 && len(hex) != 6) {
+		return RGBA{}, false
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGBA{}, false
+	}
+	if len(hex) == 3 {
+		r, g, b := uint8(v>>8&0xf), uint8(v>>4&0xf), uint8(v&0xf)
+		return rgb8(r*17, g*17, b*17), true
+	}
+	return rgb8(uint8(v>>16), uint8(v>>8), uint8(v)), true
+}
+
+// DrawMarkup draws markup as parsed by ParseMarkup at the specified position.
+// Unstyled text is drawn in White over the existing background, and each line of a
+// multi-line text starts at x on the next row. Nothing is drawn if the markup is invalid.
+func (b *Buffer) DrawMarkup(s string, x, y uint32) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	chunks, err := ParseMarkup(s)
+	if err != nil {
+		return err
+	}
+
+	height, err := b.Height()
+	if err != nil {
+		return err
+	}
+	col, row := int64(0), y
+	for _, chunk := range chunks {
+		fg := White
+		if chunk.Foreground != nil {
+			fg = *chunk.Foreground
+		}
+		var attrs Attributes
+		if chunk.Attributes != nil {
+			attrs = *chunk.Attributes
+		}
+
+		text := chunk.Text
+		for {
+			line, rest, more := strings.Cut(text, "\n")
+			line = strings.TrimSuffix(line, "\r")
+			origin := col
+			if b.absoluteTabs {
+				origin += int64(x)
+			}
+			line, end := expandTabs(line, origin, origin, b.tabWidth)
+			if row < height {
+				b.drawLine(line, x+uint32(col), row, fg, chunk.Background, attrs)
+			}
+			col += end - origin
+			if !more {
+				break
+			}
+			col, row, text = 0, row+1, rest
+		}
+	}
+	return nil
+}
+
+// WriteMarkup writes markup as parsed by ParseMarkup to the text buffer.
+// Returns the number of characters written. Nothing is written if the markup is invalid.
+func (tb *TextBuffer) WriteMarkup(s string) (uint32, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
+	}
+	chunks, err := ParseMarkup(s)
+	if err != nil {
+		return 0, err
+	}
+	var written uint32
+	for _, chunk := range chunks {
+		n, err := tb.WriteChunk(chunk)
+		if err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return written, nil
+}
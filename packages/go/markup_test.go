@@ -0,0 +1,140 @@
+package opentui
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMarkup(t *testing.T) {
+	chunks, err := ParseMarkup("plain [bold]bold [fg=#ff8800]orange[/fg] [[x] [/]end")
+	if err != nil {
+		t.Fatalf("ParseMarkup failed: %v", err)
+	}
+
+	orange := rgb8(0xff, 0x88, 0x00)
+	want := []struct {
+		text  string
+		fg    *RGBA
+		attrs Attributes
+	}{
+		{"plain ", nil, 0},
+		{"bold ", nil, AttrBold},
+		{"orange", &orange, AttrBold},
+		{" [x] ", nil, AttrBold},
+		{"end", nil, 0},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("ParseMarkup returned %d chunks, want %d: %+v", len(chunks), len(want), chunks)
+	}
+	for i, w := range want {
+		c := chunks[i]
+		var attrs Attributes
+		if c.Attributes != nil {
+			attrs = *c.Attributes
+		}
+		if c.Text != w.text || attrs != w.attrs || (c.Foreground == nil) != (w.fg == nil) ||
+			(c.Foreground != nil && *c.Foreground != *w.fg) {
+			t.Errorf("chunk %d = %q fg %v attrs %d, want %q fg %v attrs %d", i, c.Text, c.Foreground, attrs, w.text, w.fg, w.attrs)
+		}
+	}
+}
+
+func TestParseMarkupNesting(t *testing.T) {
+	chunks, err := ParseMarkup("[bg=blue][underline][fg=#0f0]a[/fg]b[/underline]c")
+	if err != nil {
+		t.Fatalf("ParseMarkup failed: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("ParseMarkup returned %d chunks, want 3", len(chunks))
+	}
+	for _, c := range chunks {
+		if c.Background == nil || *c.Background != Blue {
+			t.Errorf("chunk %q background = %v, want blue", c.Text, c.Background)
+		}
+	}
+	if *chunks[0].Foreground != Green || *chunks[0].Attributes != AttrUnderline {
+		t.Errorf("chunk a = %+v", chunks[0])
+	}
+	if chunks[1].Foreground != nil || *chunks[1].Attributes != AttrUnderline {
+		t.Errorf("chunk b = %+v", chunks[1])
+	}
+	if chunks[2].Attributes != nil {
+		t.Errorf("chunk c = %+v", chunks[2])
+	}
+}
+
+func TestParseMarkupErrors(t *testing.T) {
+	tests := []struct {
+		markup string
+		offset int
+	}{
+		{"ok [blod]text", 3},
+		{"[fg=#12345]x", 0},
+		{"[fg=orange]x", 0},
+		{"[size=2]x", 0},
+		{"[]", 0},
+		{"a [/bold]", 2},
+		{"[bold]a[italic]b[/bold]", 16},
+		{"text [bold", 5},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseMarkup(tt.markup)
+		var markupErr *MarkupError
+		if !errors.As(err, &markupErr) {
+			t.Errorf("ParseMarkup(%q) error = %v, want a MarkupError", tt.markup, err)
+			continue
+		}
+		if markupErr.Offset != tt.offset {
+			t.Errorf("ParseMarkup(%q) offset = %d, want %d (%v)", tt.markup, markupErr.Offset, tt.offset, err)
+		}
+	}
+}
+
+func TestDrawMarkup(t *testing.T) {
+	buffer := newTestBuffer(t, 10, 3)
+
+	if err := buffer.DrawMarkup("a[fg=red]b\nc[/]d]", 1, 0); err != nil {
+		t.Fatalf("DrawMarkup failed: %v", err)
+	}
+	expectRows(t, buffer,
+		" ab       ",
+		" cd]      ",
+		"          ",
+	)
+	for x, want := range map[uint32]RGBA{1: White, 2: Red} {
+		if cell, _ := buffer.GetCell(x, 0); cell.Foreground != want {
+			t.Errorf("cell %d foreground = %v, want %v", x, cell.Foreground, want)
+		}
+	}
+	if cell, _ := buffer.GetCell(1, 1); cell.Foreground != Red {
+		t.Errorf("style should continue on the next line, got %v", cell.Foreground)
+	}
+
+	if err := buffer.DrawMarkup("[nope]x", 0, 2); err == nil {
+		t.Error("DrawMarkup should fail for an unknown tag")
+	}
+	expectRows(t, buffer,
+		" ab       ",
+		" cd]      ",
+		"          ",
+	)
+}
+
+func TestTextBufferWriteMarkup(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	if _, err := tb.WriteMarkup("[bold]ab[/bold]c"); err != nil {
+		t.Fatalf("WriteMarkup failed: %v", err)
+	}
+	if length, _ := tb.Length(); length != 3 {
+		t.Errorf("length = %d, want 3", length)
+	}
+	if _, err := tb.WriteMarkup("[bold"); err == nil {
+		t.Error("WriteMarkup should fail for an unterminated tag")
+	}
+}
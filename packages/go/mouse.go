@@ -0,0 +1,139 @@
+package opentui
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Mouse buttons reported in MouseEvent.Button
+const (
+	MouseLeft      uint8 = 0
+	MouseMiddle    uint8 = 1
+	MouseRight     uint8 = 2
+	MouseNone      uint8 = 3 // Motion without a button held
+	MouseWheelUp   uint8 = 64
+	MouseWheelDown uint8 = 65
+)
+
+// mousePixelsMode is the private mode of SGR-Pixels mouse reports
+const mousePixelsMode = 1016
+
+const (
+	enableMousePixels  = "\x1b[?1016h"
+	disableMousePixels = "\x1b[?1016l"
+	queryMousePixels   = "\x1b[?1016$p"
+)
+
+// EnableMousePixels switches mouse reports to pixel coordinates (SGR-Pixels, mode
+// 1016) or back to cells. Mouse tracking is turned on with EnableMouse; this only
+// changes the coordinates it reports. The terminal is asked whether the mode took
+// effect (DECRQM), and GetTerminalCapabilities reports SupportsMousePixels once the
+// reply was passed to ProcessCapabilityResponse. The mode is turned off on Close and
+// around Suspend.
+func (r *Renderer) EnableMousePixels(enable bool) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
+	}
+	sequence := disableMousePixels
+	if enable {
+		sequence = enableMousePixels + queryMousePixels
+	}
+	if _, err := r.terminal().Write([]byte(sequence)); err != nil {
+		return err
+	}
+	r.mousePixels = enable
+	if enable {
+		r.readReplies()
+	}
+	return nil
+}
+
+// ParseMouseEvent decodes the SGR mouse report (ESC [ < button ; x ; y M or m) at the
+// start of data and returns the event and the number of bytes it takes. ok is false
+// if data doesn't start with a complete report.
+//
+// With EnableMousePixels on, unless the terminal replied that it doesn't support the
+// mode, the coordinates are taken as pixels and the cell is derived from the cell
+// size in pixels the terminal reports, or 10 by 20 pixels if it reports none.
+// Otherwise Pixel is the top left pixel of the cell.
+func (r *Renderer) ParseMouseEvent(data []byte) (event MouseEvent, n int, ok bool) {
+	button, x, y, pressed, n, ok := sgrMouseReport(data)
+	if !ok {
+		return MouseEvent{}, 0, false
+	}
+	event = MouseEvent{
+		Button:  button & 3,
+		Pressed: pressed,
+		Motion:  button&32 != 0,
+	}
+	if button&64 != 0 {
+		event.Button |= 64
+	}
+	if button&4 != 0 {
+		event.Modifiers |= ModShift
+	}
+	if button&8 != 0 {
+		event.Modifiers |= ModAlt
+	}
+	if button&16 != 0 {
+		event.Modifiers |= ModCtrl
+	}
+
+	// Reports count from 1
+	x, y = max(x-1, 0), max(y-1, 0)
+	cellWidth, cellHeight := r.cellPixelSize()
+	if r.mousePixels && (!r.detected.mousePixelsReported || r.detected.mousePixels) {
+		event.Pixels = true
+		event.Pixel = Position{X: x, Y: y}
+		event.Position = Position{X: x / cellWidth, Y: y / cellHeight}
+	} else {
+		event.Position = Position{X: x, Y: y}
+		event.Pixel = Position{X: x * cellWidth, Y: y * cellHeight}
+	}
+	return event, n, true
+}
+
+// cellPixelSize returns the size of a cell in pixels the terminal reports, or the
+// default cell size.
+func (r *Renderer) cellPixelSize() (width, height int32) {
+	if f := r.outputFile(); f != nil {
+		if width, height, ok := cellPixels(f); ok {
+			return int32(width), int32(height)
+		}
+	}
+	return defaultCellWidth, defaultCellHeight
+}
+
+// sgrMouseReport splits the SGR mouse report at the start of data into its button
+// code and coordinates. pressed is false for a release (final m).
+func sgrMouseReport(data []byte) (button uint8, x, y int32, pressed bool, n int, ok bool) {
+	if !bytes.HasPrefix(data, []byte("\x1b[<")) {
+		return 0, 0, 0, false, 0, false
+	}
+	end := 3
+	for end < len(data) && (data[end] >= '0' && data[end] <= '9' || data[end] == ';') {
+		end++
+	}
+	if end == len(data) || data[end] != 'M' && data[end] != 'm' {
+		return 0, 0, 0, false, 0, false
+	}
+	params := bytes.Split(data[3:end], []byte(";"))
+	if len(params) != 3 {
+		return 0, 0, 0, false, 0, false
+	}
+	var values [3]int64
+	for i, param := range params {
+		value, err := strconv.ParseInt(string(param), 10, 32)
+		if err != nil {
+			return 0, 0, 0, false, 0, false
+		}
+		values[i] = value
+	}
+	if values[0] > 255 {
+		return 0, 0, 0, false, 0, false
+	}
+	return uint8(values[0]), int32(values[1]), int32(values[2]), data[end] == 'M', end + 1, true
+}
@@ -0,0 +1,92 @@
+package opentui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseMouseEvent(t *testing.T) {
+	r := &Renderer{output: &bytes.Buffer{}}
+	tests := []struct {
+		data  string
+		event MouseEvent
+		n     int
+		ok    bool
+	}{
+		{"\x1b[<0;5;3M", MouseEvent{Position: Position{4, 2}, Pixel: Position{40, 40}, Button: MouseLeft, Pressed: true}, 9, true},
+		{"\x1b[<2;1;1mrest", MouseEvent{Pixel: Position{}, Button: MouseRight}, 9, true},
+		{"\x1b[<35;10;2M", MouseEvent{Position: Position{9, 1}, Pixel: Position{90, 20}, Button: MouseNone, Pressed: true, Motion: true}, 11, true},
+		{"\x1b[<65;1;1M", MouseEvent{Button: MouseWheelDown, Pressed: true}, 10, true},
+		{"\x1b[<20;1;1M", MouseEvent{Button: MouseLeft, Pressed: true, Modifiers: ModShift | ModCtrl}, 10, true},
+		{"\x1b[<0;5;3", MouseEvent{}, 0, false},
+		{"\x1b[<0;5M", MouseEvent{}, 0, false},
+		{"\x1b[M !!", MouseEvent{}, 0, false},
+	}
+	for _, tt := range tests {
+		event, n, ok := r.ParseMouseEvent([]byte(tt.data))
+		if event != tt.event || n != tt.n || ok != tt.ok {
+			t.Errorf("ParseMouseEvent(%q) = %+v, %d, %v, want %+v, %d, %v", tt.data, event, n, ok, tt.event, tt.n, tt.ok)
+		}
+	}
+
+	r.mousePixels = true
+	event, _, _ := r.ParseMouseEvent([]byte("\x1b[<0;46;31M"))
+	want := MouseEvent{Position: Position{4, 1}, Pixel: Position{45, 30}, Button: MouseLeft, Pressed: true, Pixels: true}
+	if event != want {
+		t.Errorf("pixel report = %+v, want %+v", event, want)
+	}
+
+	// A terminal that reports the mode as unsupported keeps sending cells
+	r.detected.parseCapabilityResponse([]byte("\x1b[?1016;0$y"))
+	if event, _, _ := r.ParseMouseEvent([]byte("\x1b[<0;46;31M")); event.Pixels || event.Position != (Position{45, 30}) {
+		t.Errorf("report after DECRPM 0 = %+v, want cells", event)
+	}
+	r.detected.parseCapabilityResponse([]byte("\x1b[?1016;1$y"))
+	if !r.detected.mousePixels {
+		t.Error("DECRPM reply 1 should record mouse pixel support")
+	}
+}
+
+func TestEnableMousePixels(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	renderer.suspend.stop = func() error { return nil }
+	renderer.suspend.cook = func() (func() error, error) { return nil, nil }
+	renderer.suspend.foreground = func() bool { return true }
+
+	if err := renderer.EnableMousePixels(true); err != nil {
+		t.Fatalf("EnableMousePixels failed: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, "\x1b[?1016h\x1b[?1016$p") {
+		t.Errorf("enabling wrote %q", got)
+	}
+
+	out.Reset()
+	if err := renderer.Suspend(); err != nil {
+		t.Fatalf("Suspend failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "\x1b[?1016l") {
+		t.Errorf("Suspend wrote %q, want the mode turned off", out.String())
+	}
+	out.Reset()
+	if err := renderer.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "\x1b[?1016h") {
+		t.Errorf("Resume wrote %q, want the mode turned on", out.String())
+	}
+
+	out.Reset()
+	renderer.Close()
+	if !strings.Contains(out.String(), "\x1b[?1016l") {
+		t.Errorf("Close wrote %q, want the mode turned off", out.String())
+	}
+	if err := renderer.EnableMousePixels(true); err == nil {
+		t.Error("EnableMousePixels after Close should fail")
+	}
+}
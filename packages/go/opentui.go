@@ -3,6 +3,7 @@ package opentui
 /*
 #cgo pkg-config: opentui
 #include <opentui.h>
+#include "color.h"
 #include <stdlib.h>
 */
 import "C"
@@ -38,10 +39,19 @@ func NewRGB(r, g, b float32) RGBA {
 	return RGBA{R: r, G: g, B: b, A: 1.0}
 }
 
-// toCFloat converts RGBA to C float array
-func (c RGBA) toCFloat() *C.float {
-	arr := [4]C.float{C.float(c.R), C.float(c.G), C.float(c.B), C.float(c.A)}
-	return (*C.float)(unsafe.Pointer(&arr[0]))
+// toC converts RGBA to a C Color, which is passed to the native layer by value
+// through the wrappers of color.h.
+func (c RGBA) toC() C.Color {
+	return C.Color{rgba: [4]C.float{C.float(c.R), C.float(c.G), C.float(c.B), C.float(c.A)}}
+}
+
+// optionalColor converts an optional color for the wrappers of color.h, which pass
+// NULL for colors that are not set.
+func optionalColor(c *RGBA) (C.Color, C.bool) {
+	if c == nil {
+		return C.Color{}, false
+	}
+	return c.toC(), true
 }
 
 // Common colors
@@ -58,7 +68,8 @@ var (
 	Transparent = NewRGBA(0, 0, 0, 0)
 )
 
-// CursorStyle defines the cursor appearance
+// CursorStyle defines the cursor appearance. Together with blinking it maps to a
+// CursorShape, see CursorStyle.Shape.
 type CursorStyle string
 
 const (
@@ -86,13 +97,12 @@ func SetCursorPosition(renderer *Renderer, x, y int32, visible bool) {
 }
 
 // SetCursorStyle sets the cursor style and blinking state for a specific renderer.
+// Unknown styles are ignored; Renderer.SetCursorStyle reports them.
 func SetCursorStyle(renderer *Renderer, style CursorStyle, blinking bool) {
 	if renderer == nil || renderer.ptr == nil {
 		return
 	}
-	cStyle := C.CString(string(style))
-	defer C.free(unsafe.Pointer(cStyle))
-	C.setCursorStyle(renderer.ptr, (*C.uint8_t)(unsafe.Pointer(cStyle)), C.size_t(len(style)), C.bool(blinking))
+	renderer.SetCursorStyle(style, blinking)
 }
 
 // SetCursorColor sets the cursor color for a specific renderer.
@@ -100,16 +110,17 @@ func SetCursorColor(renderer *Renderer, color RGBA) {
 	if renderer == nil || renderer.ptr == nil {
 		return
 	}
-	C.setCursorColor(renderer.ptr, color.toCFloat())
+	C.setCursorColorValue(renderer.ptr, color.toC())
 }
 
-// stringToC converts a Go string to C string parameters
+// stringToC returns a pointer to the bytes of a Go string and its length, without
+// copying them. The native layer must only read them, and only during the call they
+// are passed to; keep s alive until then with runtime.KeepAlive.
 func stringToC(s string) (*C.uint8_t, C.size_t) {
 	if len(s) == 0 {
 		return nil, 0
 	}
-	bytes := []byte(s)
-	return (*C.uint8_t)(unsafe.Pointer(&bytes[0])), C.size_t(len(bytes))
+	return (*C.uint8_t)(unsafe.Pointer(unsafe.StringData(s))), C.size_t(len(s))
 }
 
 // BorderSides represents which sides of a box border to draw
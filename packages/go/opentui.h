@@ -31,11 +31,14 @@ void setUseThread(CliRenderer* renderer, bool useThread);
 void destroyRenderer(CliRenderer* renderer, bool useAlternateScreen, uint32_t splitHeight);
 void setBackgroundColor(CliRenderer* renderer, const float* color);
 void setRenderOffset(CliRenderer* renderer, uint32_t offset);
+void setColorMode(CliRenderer* renderer, uint8_t mode, const float* palette, uint32_t paletteLen);
 void updateStats(CliRenderer* renderer, double time, uint32_t fps, double frameCallbackTime);
 void updateMemoryStats(CliRenderer* renderer, uint32_t heapUsed, uint32_t heapTotal, uint32_t arrayBuffers);
 OptimizedBuffer* getNextBuffer(CliRenderer* renderer);
 OptimizedBuffer* getCurrentBuffer(CliRenderer* renderer);
 void render(CliRenderer* renderer, bool force);
+void waitForRender(CliRenderer* renderer);
+void setDiscardOutput(CliRenderer* renderer, bool discard);
 void resizeRenderer(CliRenderer* renderer, uint32_t width, uint32_t height);
 void enableMouse(CliRenderer* renderer, bool enableMovement);
 void disableMouse(CliRenderer* renderer);
@@ -52,11 +55,14 @@ void bufferClear(OptimizedBuffer* buffer, const float* bg);
 uint32_t* bufferGetCharPtr(OptimizedBuffer* buffer);
 float* bufferGetFgPtr(OptimizedBuffer* buffer);
 float* bufferGetBgPtr(OptimizedBuffer* buffer);
-uint8_t* bufferGetAttributesPtr(OptimizedBuffer* buffer);
+uint16_t* bufferGetAttributesPtr(OptimizedBuffer* buffer);
 bool bufferGetRespectAlpha(OptimizedBuffer* buffer);
 void bufferSetRespectAlpha(OptimizedBuffer* buffer, bool respectAlpha);
-void bufferDrawText(OptimizedBuffer* buffer, const uint8_t* text, size_t textLen, uint32_t x, uint32_t y, const float* fg, const float* bg, uint8_t attributes);
-void bufferSetCellWithAlphaBlending(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t char_code, const float* fg, const float* bg, uint8_t attributes);
+uint32_t bufferWriteResolvedChars(OptimizedBuffer* buffer, uint8_t* output, size_t outputLen, bool addLineBreaks);
+uint32_t bufferGetGrapheme(OptimizedBuffer* buffer, uint32_t ch, uint8_t* output, size_t outputLen);
+void bufferDrawText(OptimizedBuffer* buffer, const uint8_t* text, size_t textLen, uint32_t x, uint32_t y, const float* fg, const float* bg, uint16_t attributes);
+void bufferSetCell(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t char_code, const float* fg, const float* bg, uint16_t attributes);
+void bufferSetCellWithAlphaBlending(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t char_code, const float* fg, const float* bg, uint16_t attributes);
 void bufferFillRect(OptimizedBuffer* buffer, uint32_t x, uint32_t y, uint32_t width, uint32_t height, const float* bg);
 void bufferDrawPackedBuffer(OptimizedBuffer* buffer, const uint8_t* data, size_t dataLen, uint32_t posX, uint32_t posY, uint32_t terminalWidthCells, uint32_t terminalHeightCells);
 void bufferDrawSuperSampleBuffer(OptimizedBuffer* buffer, uint32_t x, uint32_t y, const uint8_t* pixelData, size_t len, uint8_t format, uint32_t alignedBytesPerRow);
@@ -73,6 +79,10 @@ void setCursorColor(CliRenderer* renderer, const float* color);
 void getTerminalCapabilities(CliRenderer* renderer, Capabilities* caps);
 void processCapabilityResponse(CliRenderer* renderer, const uint8_t* response, size_t responseLen);
 
+// Error reporting: code of the last failure on the calling thread, 0 if none, with
+// its message copied NUL-terminated into message. Clears the failure.
+uint8_t getLastError(uint8_t* message, size_t maxLen);
+
 // Debug and utility functions
 void setDebugOverlay(CliRenderer* renderer, bool enabled, uint8_t corner);
 void clearTerminal(CliRenderer* renderer);
@@ -103,9 +113,9 @@ void textBufferSetSelection(TextBuffer* textBuffer, uint32_t start, uint32_t end
 void textBufferResetSelection(TextBuffer* textBuffer);
 void textBufferSetDefaultFg(TextBuffer* textBuffer, const float* fg);
 void textBufferSetDefaultBg(TextBuffer* textBuffer, const float* bg);
-void textBufferSetDefaultAttributes(TextBuffer* textBuffer, const uint8_t* attr);
+void textBufferSetDefaultAttributes(TextBuffer* textBuffer, const uint16_t* attr);
 void textBufferResetDefaults(TextBuffer* textBuffer);
-uint32_t textBufferWriteChunk(TextBuffer* textBuffer, const uint8_t* textBytes, uint32_t textLen, const float* fg, const float* bg, const uint8_t* attr);
+uint32_t textBufferWriteChunk(TextBuffer* textBuffer, const uint8_t* textBytes, uint32_t textLen, const float* fg, const float* bg, const uint16_t* attr);
 uint32_t textBufferGetCapacity(TextBuffer* textBuffer);
 void textBufferFinalizeLineInfo(TextBuffer* textBuffer);
 const uint32_t* textBufferGetLineStartsPtr(TextBuffer* textBuffer);
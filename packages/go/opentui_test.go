@@ -1,7 +1,14 @@
 package opentui
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestRGBA(t *testing.T) {
@@ -218,6 +225,116 @@ func TestBufferInvalidDimensions(t *testing.T) {
 	}
 }
 
+func TestBufferClone(t *testing.T) {
+	buffer := NewBuffer(4, 2, true, WidthMethodWCWidth)
+	if buffer == nil {
+		t.Skip("Skipping buffer test - OpenTUI library not available")
+	}
+	defer buffer.Close()
+	
+	buffer.Clear(Blue)
+	buffer.DrawText("a漢", 1, 1, Yellow, nil, AttrBold)
+	
+	clone, err := buffer.Clone()
+	if err != nil {
+		t.Fatalf("Clone failed: %v", err)
+	}
+	defer clone.Close()
+	
+	if clone.widthMethod != WidthMethodWCWidth {
+		t.Errorf("Clone width method = %d, want %d", clone.widthMethod, WidthMethodWCWidth)
+	}
+	if respectAlpha, _ := clone.GetRespectAlpha(); !respectAlpha {
+		t.Error("Clone should respect alpha like the original")
+	}
+	
+	// Changes to the original must not show up in the clone
+	buffer.Clear(Red)
+	buffer.DrawText("zz", 0, 0, White, nil, 0)
+	buffer.Resize(8, 8)
+	buffer.Close()
+	
+	expectRows(t, clone, "    ", " a漢 ")
+	if kind, _ := clone.GetCellKind(3, 1); kind != CellContinuation {
+		t.Errorf("Clone cell (3, 1) = %v, want the second half of 漢", kind)
+	}
+	da, err := clone.GetDirectAccess()
+	if err != nil {
+		t.Fatalf("GetDirectAccess failed: %v", err)
+	}
+	cell, _ := da.GetCell(1, 1)
+	if cell.Foreground != Yellow || cell.Background != Blue || cell.Attributes != AttrBold {
+		t.Errorf("Clone cell = %+v, want bold yellow on blue", cell)
+	}
+	
+	if _, err := buffer.Clone(); err == nil {
+		t.Error("Clone of a closed buffer should fail")
+	}
+}
+
+func TestBufferGetSetCell(t *testing.T) {
+	buffer := NewBuffer(3, 2, true, WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("Skipping buffer test - OpenTUI library not available")
+	}
+	defer buffer.Close()
+	buffer.Clear(Black)
+	
+	// Translucent colors are stored as they are
+	want := Cell{Char: 'x', Foreground: RGBA{R: 1, A: 0.5}, Background: Blue, Attributes: AttrItalic}
+	if err := buffer.SetCell(2, 1, want); err != nil {
+		t.Fatalf("SetCell failed: %v", err)
+	}
+	got, err := buffer.GetCell(2, 1)
+	if err != nil {
+		t.Fatalf("GetCell failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("GetCell = %+v, want %+v", got, want)
+	}
+	
+	if err := buffer.SetChar(2, 1, 'y'); err != nil {
+		t.Fatalf("SetChar failed: %v", err)
+	}
+	want.Char = 'y'
+	if got, _ := buffer.GetCell(2, 1); got != want {
+		t.Errorf("GetCell after SetChar = %+v, want %+v", got, want)
+	}
+	
+	if _, err := buffer.GetCell(3, 0); err == nil {
+		t.Error("GetCell out of bounds should fail")
+	}
+	if err := buffer.SetCell(0, 2, want); err == nil {
+		t.Error("SetCell out of bounds should fail")
+	}
+	if err := buffer.SetChar(5, 5, 'z'); err == nil {
+		t.Error("SetChar out of bounds should fail")
+	}
+}
+
+func TestBufferGetCellClusters(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 2)
+	buffer.DrawText("é漢a", 0, 0, White, nil, AttrBold)
+
+	// Clusters drawn as text live in the grapheme pool and read as their first code point
+	for x, want := range map[uint32]rune{0: 'é', 1: '漢', 2: ' ', 3: 'a'} {
+		cell, err := buffer.GetCell(x, 0)
+		if err != nil {
+			t.Fatalf("GetCell(%d, 0) failed: %v", x, err)
+		}
+		if cell.Char != want || cell.Attributes != AttrBold {
+			t.Errorf("GetCell(%d, 0) = %+v, want %q", x, cell, want)
+		}
+	}
+
+	// A row ending in a wide cluster doesn't throw off the rows after it
+	buffer.DrawText("字", 4, 0, White, nil, 0)
+	buffer.DrawText("ü", 0, 1, White, nil, 0)
+	if cell, _ := buffer.GetCell(0, 1); cell.Char != 'ü' {
+		t.Errorf("GetCell(0, 1) = %q, want 'ü'", cell.Char)
+	}
+}
+
 func TestTextBuffer(t *testing.T) {
 	// Test text buffer creation
 	textBuffer := NewTextBuffer(100, WidthMethodUnicode)
@@ -320,14 +437,18 @@ func TestGlobalCursorFunctions(t *testing.T) {
 	}
 	defer renderer.Close()
 	
-	SetCursorPosition(renderer, 10, 5, true)
-	SetCursorStyle(renderer, CursorBlock, false)
-	SetCursorColor(renderer, Green)
-	
-	// Also test renderer methods
-	renderer.SetCursorPosition(15, 10, true)
-	renderer.SetCursorStyle(CursorUnderline, true)
-	renderer.SetCursorColor(Red)
+	// Cursor styles are written to stdout straight away
+	captureStdout(t, func() {
+		SetCursorPosition(renderer, 10, 5, true)
+		SetCursorStyle(renderer, CursorBlock, false)
+		SetCursorColor(renderer, Green)
+
+		// Also test renderer methods
+		renderer.SetCursorPosition(15, 10, true)
+		renderer.SetCursorStyle(CursorUnderline, true)
+		renderer.SetCursorColor(Red)
+		renderer.ResetCursorStyle()
+	})
 	
 	// If we get here without panicking, the test passes
 }
@@ -353,4 +474,152 @@ func TestConstants(t *testing.T) {
 	if CursorBlock == CursorUnderline {
 		t.Error("CursorBlock and CursorUnderline should have different values")
 	}
-}
\ No newline at end of file
+}
+func TestHitTest(t *testing.T) {
+	renderer := NewRenderer(20, 10)
+	if renderer == nil {
+		t.Skip("Skipping hit test - OpenTUI library not available")
+	}
+	defer renderer.Close()
+	
+	// ID 0 must be usable as a region ID
+	if err := renderer.AddToHitGrid(0, 0, 5, 5, 0); err != nil {
+		t.Fatalf("AddToHitGrid failed: %v", err)
+	}
+	if err := renderer.AddToHitGrid(10, 2, 4, 4, 7); err != nil {
+		t.Fatalf("AddToHitGrid failed: %v", err)
+	}
+	
+	tests := []struct {
+		x, y uint32
+		want HitTestResult
+	}{
+		{2, 2, HitTestResult{ID: 0, Found: true}},
+		{12, 3, HitTestResult{ID: 7, Found: true}},
+		{8, 8, HitTestResult{}},
+		{20, 0, HitTestResult{}},
+		{0, 100, HitTestResult{}},
+	}
+	for _, tt := range tests {
+		got, err := renderer.HitTest(tt.x, tt.y)
+		if err != nil {
+			t.Errorf("HitTest(%d, %d) failed: %v", tt.x, tt.y, err)
+		}
+		if got != tt.want {
+			t.Errorf("HitTest(%d, %d) = %+v, want %+v", tt.x, tt.y, got, tt.want)
+		}
+	}
+	
+	id, err := renderer.CheckHit(12, 3)
+	if err != nil || id != 7 {
+		t.Errorf("CheckHit(12, 3) = %d, %v; want 7", id, err)
+	}
+	
+	if err := renderer.AddToHitGrid(0, 0, 1, 1, ^uint32(0)); err == nil {
+		t.Error("AddToHitGrid should reject the maximum ID")
+	}
+}
+
+func TestHitGridSnapshot(t *testing.T) {
+	renderer := NewRenderer(20, 10)
+	if renderer == nil {
+		t.Skip("Skipping hit grid test - OpenTUI library not available")
+	}
+	defer renderer.Close()
+	
+	renderer.AddToHitGrid(0, 0, 3, 2, 0)
+	renderer.AddToHitGrid(10, 4, 4, 3, 7)
+	
+	regions, err := renderer.HitGridSnapshot()
+	if err != nil {
+		t.Fatalf("HitGridSnapshot failed: %v", err)
+	}
+	
+	want := []HitRegion{
+		{Rect: Rect{Position{0, 0}, Size{3, 2}}, ID: 0},
+		{Rect: Rect{Position{10, 4}, Size{4, 3}}, ID: 7},
+	}
+	if len(regions) != len(want) {
+		t.Fatalf("HitGridSnapshot returned %d regions, want %d: %+v", len(regions), len(want), regions)
+	}
+	for i := range want {
+		if regions[i] != want[i] {
+			t.Errorf("region %d = %+v, want %+v", i, regions[i], want[i])
+		}
+	}
+	if !regions[1].Contains(12, 5) {
+		t.Error("cell (12,5) should map to ID 7")
+	}
+	
+	var out bytes.Buffer
+	renderer.SetDebugOutput(&out)
+	if err := renderer.DumpHitGrid(); err != nil {
+		t.Fatalf("DumpHitGrid failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("DumpHitGrid wrote %d lines, want 10", len(lines))
+	}
+	if lines[0] != "000................." || lines[5] != "..........7777......" {
+		t.Errorf("unexpected hit grid dump:\n%s", out.String())
+	}
+}
+
+func TestConcurrentDrawing(t *testing.T) {
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 40, Height: 8, Output: io.Discard, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for worker := 0; worker < 4; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; ctx.Err() == nil; i++ {
+				renderer.Lock()
+				buf, err := renderer.GetNextBuffer()
+				if err == nil {
+					err = buf.DrawText(fmt.Sprintf("worker %d: %d", worker, i), 0, uint32(worker), White, nil, 0)
+				}
+				if err == nil {
+					err = buf.FillRect(20, uint32(worker), 10, 1, Blue)
+				}
+				renderer.Unlock()
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(worker)
+	}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			if _, err := renderer.RenderWithStats(false); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		err := renderer.RunLoop(ctx, 500, func(dt time.Duration, buf *Buffer) error {
+			return buf.DrawText("loop", 0, 7, Green, nil, 0)
+		})
+		if err != nil {
+			errs <- err
+		}
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("concurrent use failed: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package opentui
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// stdoutRedirect serializes the redirections of file descriptor 1 made for
+// renderers with their own Output.
+var stdoutRedirect sync.Mutex
+
+// native runs a native call that writes to the terminal. The native renderer always
+// writes to file descriptor 1, so for a renderer with its own Output the bytes are
+// captured and copied there before native returns. Anything else the process writes
+// to fd 1 meanwhile ends up in the Output as well. Headless renderers don't write
+// natively at all, see setDiscardOutput.
+func (r *Renderer) native(call func()) error {
+	if r.output == nil || r.headless {
+		call()
+		return nil
+	}
+	stdoutRedirect.Lock()
+	defer stdoutRedirect.Unlock()
+	return redirectStdout(countingWriter{r.output, &r.written}, call)
+}
+
+// outputFile returns the file the renderer writes to, or nil if its Output is not a
+// file. Used to detect the size and color support of the terminal.
+func (r *Renderer) outputFile() *os.File {
+	if r.output == nil && r.inline != nil && r.inline.stdout != nil {
+		return r.inline.stdout
+	}
+	return outputFile(r.output)
+}
+
+func outputFile(w io.Writer) *os.File {
+	if w == nil {
+		return os.Stdout
+	}
+	f, _ := w.(*os.File)
+	return f
+}
+
+// readReplies starts reading terminal replies from the renderer's Input, passing
+// them to ProcessCapabilityResponse, unless no Input is set or it is read already.
+// Reading stops when Input fails, for example at EOF, or the renderer was closed.
+func (r *Renderer) readReplies() {
+	if r.input == nil {
+		return
+	}
+	r.replies.Do(func() {
+		go func() {
+			buf := make([]byte, 4096)
+			for {
+				n, err := r.input.Read(buf)
+				if n > 0 && r.ProcessCapabilityResponse(buf[:n]) != nil {
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	})
+}
@@ -0,0 +1,11 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package opentui
+
+import "io"
+
+// redirectStdout is not implemented on this platform, so renderers can't write to
+// their own Output.
+func redirectStdout(w io.Writer, call func()) error {
+	return newError("output redirection is not supported on this platform")
+}
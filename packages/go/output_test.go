@@ -0,0 +1,125 @@
+package opentui
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedirectStdout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("output redirection is not supported on Windows")
+	}
+	var out bytes.Buffer
+	err := redirectStdout(&out, func() {
+		// Larger than a pipe buffer, so the copy has to run while writing
+		os.NewFile(1, "fd1").Write(bytes.Repeat([]byte("x"), 1<<17))
+	})
+	if err != nil {
+		t.Fatalf("redirectStdout failed: %v", err)
+	}
+	if out.Len() != 1<<17 {
+		t.Errorf("captured %d bytes, want %d", out.Len(), 1<<17)
+	}
+}
+
+func TestRendererOutput(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 6, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if NewRendererWithOptions(RendererOptions{Output: &out}) != nil {
+		t.Error("a renderer writing to a buffer can't detect its size")
+	}
+	if err := renderer.SetUseThread(true); err == nil {
+		t.Error("threaded rendering should be refused with a custom output")
+	}
+
+	flag := "🇳🇱"
+	next, err := renderer.GetNextBuffer()
+	if err != nil {
+		t.Fatalf("GetNextBuffer failed: %v", err)
+	}
+	next.Clear(Black)
+	next.DrawText("ab", 0, 0, White, nil, 0)
+	next.SetCellGrapheme(2, 0, flag, White, Black, 0)
+
+	stdout := captureStdout(t, func() {
+		renderer.EnterAlternateScreen()
+		renderer.Render(false)
+	})
+	if stdout != "" {
+		t.Errorf("output leaked to stdout: %q", stdout)
+	}
+	frame := out.String()
+	if !strings.HasPrefix(frame, enterAltScreen) {
+		t.Errorf("output should start with the alternate screen switch, got %q", frame)
+	}
+	// The cluster is part of the native frame, after the cells left of it
+	if cells, cluster := strings.Index(frame, "ab"), strings.Index(frame, flag); cells < 0 || cluster < cells {
+		t.Errorf("unexpected frame %q", frame)
+	}
+}
+
+// The attribute bits are the native ones: the frame shows them with the SGR
+// parameters the Go side parses and writes for them.
+func TestAttributeOutput(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	next, err := renderer.GetNextBuffer()
+	if err != nil {
+		t.Fatalf("GetNextBuffer failed: %v", err)
+	}
+	next.Clear(Black)
+	next.DrawText("S", 0, 0, White, nil, AttrStrike)
+	next.DrawText("H", 1, 0, White, nil, AttrHidden)
+	if err := renderer.Render(true); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	frame := out.String()
+	for _, want := range []string{"\x1b[9mS", "\x1b[8mH"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame %q doesn't contain %q", frame, want)
+		}
+	}
+	if strings.Contains(frame, "\x1b[8mS") {
+		t.Errorf("strikethrough text rendered hidden: %q", frame)
+	}
+}
+
+func TestRendererInput(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, Input: reader})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	go writer.Write([]byte("\x1b]11;rgb:ffff/ffff/ffff\x07"))
+	color, err := renderer.QueryBackgroundColor(time.Second)
+	if err != nil {
+		t.Fatalf("QueryBackgroundColor failed: %v", err)
+	}
+	if color != White {
+		t.Errorf("background = %v, want white", color)
+	}
+	if out.String() != "\x1b]11;?\x07" {
+		t.Errorf("query written as %q", out.String())
+	}
+}
@@ -0,0 +1,47 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package opentui
+
+/*
+#include <unistd.h>
+*/
+import "C"
+import (
+	"io"
+	"os"
+)
+
+// redirectStdout points file descriptor 1 at a pipe while call runs and copies
+// everything written to it to w. Returns once all of it was copied.
+func redirectStdout(w io.Writer, call func()) error {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(w, reader)
+		reader.Close()
+		copied <- err
+	}()
+
+	saved, err := C.dup(1)
+	if saved < 0 {
+		writer.Close()
+		<-copied
+		return err
+	}
+	if result, err := C.dup2(C.int(writer.Fd()), 1); result < 0 {
+		C.close(saved)
+		writer.Close()
+		<-copied
+		return err
+	}
+	call()
+	C.dup2(saved, 1)
+	C.close(saved)
+
+	// The pipe reaches EOF once the last write end is closed
+	writer.Close()
+	return <-copied
+}
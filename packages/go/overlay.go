@@ -0,0 +1,212 @@
+package opentui
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// The native renderer knows nothing of links. Hyperlinks are kept on the Go side and
+// written over the native output after Render as runs of cells, each positioned with
+// CUP and styled with SGR.
+
+// overlayCell is a cell written over the native output
+type overlayCell struct {
+	Cell
+	text  string // Cluster or character shown in the cell
+	width int    // Cells covered by text
+}
+
+// overlayRun is a horizontal run of cells sharing a hyperlink
+type overlayRun struct {
+	x, y  uint32
+	url   string
+	cells []overlayCell
+}
+
+// overlayRuns collects the cells whose link is missing or stale on the terminal once
+// the native renderer has drawn the next buffer. Cells the native renderer redraws
+// lose their link, while cells it skips keep the link of the previous frame.
+func (r *Renderer) overlayRuns(force bool) []overlayRun {
+	if !r.links.active() && !r.renderedLinks.active() {
+		return nil
+	}
+	next, err := r.GetNextBuffer()
+	if err != nil {
+		return nil
+	}
+	current, err := r.GetCurrentBuffer()
+	if err != nil {
+		return nil
+	}
+	nextCells, err := next.GetDirectAccess()
+	if err != nil {
+		return nil
+	}
+	currentCells, err := current.GetDirectAccess()
+	if err != nil {
+		return nil
+	}
+	// After a resize the native renderer redraws everything
+	force = force || currentCells.Width != nextCells.Width || currentCells.Height != nextCells.Height
+	links := r.hyperlinksSupported()
+	texts := nextCells.cellTexts()
+
+	width := nextCells.Width
+	changed := func(i uint32) bool {
+		return force || nextCells.Chars[i] != currentCells.Chars[i] ||
+			nextCells.Foreground[i] != currentCells.Foreground[i] ||
+			nextCells.Background[i] != currentCells.Background[i] ||
+			nextCells.Attributes[i] != currentCells.Attributes[i]
+	}
+	dirty := func(i uint32) bool {
+		var url, renderedURL string
+		if links {
+			url, renderedURL = r.links.url(int(i)), r.renderedLinks.url(int(i))
+		}
+		if url != "" {
+			return changed(i) || url != renderedURL
+		}
+		return renderedURL != "" && !changed(i)
+	}
+
+	var runs []overlayRun
+	for y := uint32(0); y < nextCells.Height; y++ {
+		var run *overlayRun
+		for x := uint32(0); x < width; x++ {
+			i := y*width + x
+			cell := overlayCell{
+				Cell: Cell{
+					Char:       rune(nextCells.Chars[i]),
+					Foreground: nextCells.Foreground[i],
+					Background: nextCells.Background[i],
+					Attributes: nextCells.Attributes[i],
+				},
+				width: 1,
+			}
+			switch c := nextCells.Chars[i]; {
+			case isClusterChar(c):
+				cell.Char = cellChar(texts[i])
+				cell.text = texts[i]
+				cell.width = int(storedCharWidth(c))
+				if cell.text == "" {
+					cell.text, cell.width = " ", 1
+				}
+			case cell.Char == 0:
+				cell.text = " "
+			default:
+				cell.text = string(cell.Char)
+				cell.width = max(runeWidth(cell.Char), 1)
+			}
+			if cell.width == 2 && x+1 >= width {
+				cell.width = 1
+			}
+
+			// A double width cell is written as a whole if either half is dirty
+			isDirty := dirty(i) || (cell.width == 2 && dirty(i+1))
+			if !isDirty {
+				run = nil
+				continue
+			}
+			var url string
+			if links {
+				url = r.links.url(int(i))
+			}
+			if run == nil || run.url != url {
+				runs = append(runs, overlayRun{x: x, y: y, url: url})
+				run = &runs[len(runs)-1]
+			}
+			run.cells = append(run.cells, cell)
+			x += uint32(cell.width - 1)
+		}
+	}
+	return runs
+}
+
+// emitOverlay writes overlay runs to the terminal and remembers the links of the
+// frame.
+func (r *Renderer) emitOverlay(runs []overlayRun) error {
+	if r.links != nil {
+		r.renderedLinks.copyFrom(r.links)
+		r.links.reset()
+	}
+	if len(runs) == 0 {
+		return nil
+	}
+
+	profile := r.colorProfile()
+	var out bytes.Buffer
+	out.WriteString("\x1b7")
+	for _, run := range runs {
+		out.WriteString("\x1b[")
+		out.WriteString(strconv.Itoa(int(run.y+r.renderOffset) + 1))
+		out.WriteByte(';')
+		out.WriteString(strconv.Itoa(int(run.x) + 1))
+		out.WriteByte('H')
+		if run.url != "" {
+			out.WriteString("\x1b]8;;" + run.url + "\x1b\\")
+		}
+		for _, cell := range run.cells {
+			writeSGR(&out, cell.Cell, profile)
+			out.WriteString(cell.text)
+		}
+		if run.url != "" {
+			out.WriteString("\x1b]8;;\x1b\\")
+		}
+	}
+	out.WriteString("\x1b[0m\x1b8")
+	_, err := r.terminal().Write(out.Bytes())
+	return err
+}
+
+// sgrAttributes maps text attributes to their SGR parameters
+var sgrAttributes = [...]struct {
+	attr  Attributes
+	param string
+}{
+	{AttrBold, "1"}, {AttrDim, "2"}, {AttrItalic, "3"}, {AttrUnderline, "4"},
+	{AttrBlink, "5"}, {AttrReverse, "7"}, {AttrHidden, "8"}, {AttrStrike, "9"},
+}
+
+// writeSGR writes an SGR sequence selecting the colors and attributes of a cell,
+// with colors expressed as the profile allows.
+func writeSGR(out *bytes.Buffer, cell Cell, profile ColorProfile) {
+	out.WriteString("\x1b[0")
+	for _, a := range sgrAttributes {
+		if cell.Attributes&a.attr != 0 {
+			out.WriteByte(';')
+			out.WriteString(a.param)
+		}
+	}
+	writeColor := func(background bool, c RGBA) {
+		prefix := ";38"
+		if background {
+			prefix = ";48"
+		}
+		switch profile {
+		case Profile256:
+			out.WriteString(prefix + ";5;")
+			out.WriteString(strconv.Itoa(int(c.ToANSI256())))
+		case Profile16:
+			n := int(c.ToANSI16())
+			base := 30
+			if n >= 8 {
+				base, n = 90, n-8
+			}
+			if background {
+				base += 10
+			}
+			out.WriteByte(';')
+			out.WriteString(strconv.Itoa(base + n))
+		case ProfileMono:
+		default:
+			out.WriteString(prefix + ";2")
+			for _, v := range [3]float32{c.R, c.G, c.B} {
+				out.WriteByte(';')
+				out.WriteString(strconv.Itoa(int(clamp01(v)*255 + 0.5)))
+			}
+		}
+	}
+	writeColor(false, cell.Foreground)
+	writeColor(true, cell.Background)
+	out.WriteByte('m')
+}
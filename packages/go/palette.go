@@ -0,0 +1,60 @@
+package opentui
+
+// The 16 standard ANSI colors with the RGB values xterm uses by default. Terminals
+// let users theme these, so the colors they actually show may differ.
+var (
+	ANSIBlack         = rgb8(0, 0, 0)
+	ANSIRed           = rgb8(205, 0, 0)
+	ANSIGreen         = rgb8(0, 205, 0)
+	ANSIYellow        = rgb8(205, 205, 0)
+	ANSIBlue          = rgb8(0, 0, 238)
+	ANSIMagenta       = rgb8(205, 0, 205)
+	ANSICyan          = rgb8(0, 205, 205)
+	ANSIWhite         = rgb8(229, 229, 229)
+	ANSIBrightBlack   = rgb8(127, 127, 127)
+	ANSIBrightRed     = rgb8(255, 0, 0)
+	ANSIBrightGreen   = rgb8(0, 255, 0)
+	ANSIBrightYellow  = rgb8(255, 255, 0)
+	ANSIBrightBlue    = rgb8(92, 92, 255)
+	ANSIBrightMagenta = rgb8(255, 0, 255)
+	ANSIBrightCyan    = rgb8(0, 255, 255)
+	ANSIBrightWhite   = rgb8(255, 255, 255)
+)
+
+// ANSIPalette holds the colors a terminal shows for the 16 system colors, indexed
+// like SGR 30-37 followed by 90-97.
+type ANSIPalette [16]RGBA
+
+// DefaultPalette is the palette used when a drawing helper is not given one, and
+// the one colors are matched against when converting to Profile16. It starts with
+// the xterm defaults; replace it to match the terminal's theme.
+var DefaultPalette = ANSIPalette{
+	ANSIBlack, ANSIRed, ANSIGreen, ANSIYellow,
+	ANSIBlue, ANSIMagenta, ANSICyan, ANSIWhite,
+	ANSIBrightBlack, ANSIBrightRed, ANSIBrightGreen, ANSIBrightYellow,
+	ANSIBrightBlue, ANSIBrightMagenta, ANSIBrightCyan, ANSIBrightWhite,
+}
+
+// Color returns the color of a 256 color palette index: one of the 16 system colors
+// of the palette below 16, and the fixed xterm color cube and gray ramp above.
+func (p *ANSIPalette) Color(index uint8) RGBA {
+	if index < 16 {
+		return p[index]
+	}
+	return xterm256Color(index)
+}
+
+// PaletteColor returns the color of a 256 color palette index under DefaultPalette.
+func PaletteColor(index uint8) RGBA {
+	return DefaultPalette.Color(index)
+}
+
+// Base16Palette maps the 16 colors of a Base16 scheme, base00 through base0F, onto
+// the ANSI palette the way base16-shell does. The bright colors repeat the normal
+// ones except for black and white, which use base03 and base07.
+func Base16Palette(base [16]RGBA) ANSIPalette {
+	return ANSIPalette{
+		base[0x0], base[0x8], base[0xB], base[0xA], base[0xD], base[0xE], base[0xC], base[0x5],
+		base[0x3], base[0x8], base[0xB], base[0xA], base[0xD], base[0xE], base[0xC], base[0x7],
+	}
+}
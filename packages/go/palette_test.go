@@ -0,0 +1,63 @@
+package opentui
+
+import "testing"
+
+func TestPaletteColor(t *testing.T) {
+	tests := []struct {
+		index uint8
+		want  RGBA
+	}{
+		{1, ANSIRed},
+		{12, ANSIBrightBlue},
+		{15, ANSIBrightWhite},
+		{196, rgb8(255, 0, 0)},
+		{232, rgb8(8, 8, 8)},
+	}
+	for _, tt := range tests {
+		if got := PaletteColor(tt.index); got != tt.want {
+			t.Errorf("PaletteColor(%d) = %+v, want %+v", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestBase16Palette(t *testing.T) {
+	var base [16]RGBA
+	for i := range base {
+		base[i] = rgb8(uint8(i), 0, 0)
+	}
+	p := Base16Palette(base)
+	if p[0] != base[0x0] || p[1] != base[0x8] || p[4] != base[0xD] || p[7] != base[0x5] || p[8] != base[0x3] || p[15] != base[0x7] {
+		t.Errorf("Base16Palette mapped %+v", p)
+	}
+	if p.Color(200) != PaletteColor(200) {
+		t.Error("colors above 15 should not depend on the palette")
+	}
+}
+
+func TestDrawANSIPalette(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 1)
+	palette := DefaultPalette
+	palette[1] = rgb8(250, 80, 80)
+	palette[4] = rgb8(80, 80, 250)
+	buffer.DrawANSI([]byte("\x1b[31ma\x1b[38;5;4mb\x1b[44mc"), 0, 0, ANSIOptions{Palette: &palette})
+	want := []struct{ fg, bg RGBA }{
+		{palette[1], Black},
+		{palette[4], Black},
+		{palette[4], palette[4]},
+	}
+	for x, w := range want {
+		cell, _ := buffer.GetCell(uint32(x), 0)
+		if cell.Foreground != w.fg || cell.Background != w.bg {
+			t.Errorf("cell %d = %+v, want fg %v bg %v", x, cell, w.fg, w.bg)
+		}
+	}
+}
+
+func TestToANSI16FollowsDefaultPalette(t *testing.T) {
+	saved := DefaultPalette
+	defer func() { DefaultPalette = saved }()
+	DefaultPalette[3] = rgb8(255, 135, 0)
+	if got := rgb8(250, 130, 10).ToANSI16(); got != 3 {
+		t.Errorf("ToANSI16 = %d, want 3 for a themed palette", got)
+	}
+}
@@ -0,0 +1,67 @@
+package opentui
+
+// FillPattern is a small grid of characters tiled across a rectangle.
+// A 0 character leaves the cell below untouched.
+type FillPattern struct {
+	Cells [][]rune
+
+	// Origin anchors the pattern to an absolute buffer position instead of the rect's
+	// top left corner, so adjacent fills with the same origin line up seamlessly.
+	Origin *Position
+}
+
+// Pattern presets
+var (
+	PatternCheckerboard = FillPattern{Cells: [][]rune{{'█', ' '}, {' ', '█'}}}
+	PatternHatch        = FillPattern{Cells: [][]rune{{'╲', ' '}, {' ', '╲'}}}
+	PatternCrossHatch   = FillPattern{Cells: [][]rune{{'╳'}}}
+	PatternShadeLight   = FillPattern{Cells: [][]rune{{'░'}}}
+	PatternStipple      = FillPattern{Cells: [][]rune{{'▒'}}} // 50% stipple
+	PatternShadeDark    = FillPattern{Cells: [][]rune{{'▓'}}}
+)
+
+// FillRectPattern fills a rectangular area by tiling the pattern's characters,
+// drawn in fg on bg. The rectangle is clipped to the buffer.
+func (b *Buffer) FillRectPattern(x, y, width, height uint32, pattern FillPattern, fg, bg RGBA) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	rows := len(pattern.Cells)
+	if rows == 0 {
+		return newError("pattern is empty")
+	}
+	for _, row := range pattern.Cells {
+		if len(row) == 0 {
+			return newError("pattern has an empty row")
+		}
+	}
+
+	bufWidth, bufHeight, err := b.Size()
+	if err != nil {
+		return err
+	}
+
+	originX, originY := int64(x), int64(y)
+	if pattern.Origin != nil {
+		originX, originY = int64(pattern.Origin.X), int64(pattern.Origin.Y)
+	}
+
+	endX := min(int64(x)+int64(width), int64(bufWidth))
+	endY := min(int64(y)+int64(height), int64(bufHeight))
+	for cy := int64(y); cy < endY; cy++ {
+		row := pattern.Cells[mod64(cy-originY, int64(rows))]
+		for cx := int64(x); cx < endX; cx++ {
+			char := row[mod64(cx-originX, int64(len(row)))]
+			if char == 0 {
+				continue
+			}
+			b.SetCellWithAlphaBlending(uint32(cx), uint32(cy), char, fg, bg, 0)
+		}
+	}
+	return nil
+}
+
+// mod64 returns a modulo n in the range [0, n).
+func mod64(a, n int64) int64 {
+	return ((a % n) + n) % n
+}
@@ -0,0 +1,46 @@
+package opentui
+
+import "testing"
+
+func TestFillRectPattern(t *testing.T) {
+	buffer := newTestBuffer(t, 5, 3)
+	if err := buffer.FillRectPattern(1, 0, 3, 3, PatternCheckerboard, White, Black); err != nil {
+		t.Fatalf("FillRectPattern failed: %v", err)
+	}
+	expectRows(t, buffer,
+		" █ █ ",
+		"  █  ",
+		" █ █ ",
+	)
+}
+
+func TestFillRectPatternOrigin(t *testing.T) {
+	pattern := FillPattern{Cells: [][]rune{{'a', 'b', 'c'}}}
+
+	// Anchored to each rect, the second fill restarts the pattern
+	buffer := newTestBuffer(t, 6, 2)
+	buffer.FillRectPattern(0, 0, 2, 1, pattern, White, Black)
+	buffer.FillRectPattern(2, 0, 4, 1, pattern, White, Black)
+
+	// With a shared origin the fills continue each other
+	pattern.Origin = &Position{}
+	buffer.FillRectPattern(0, 1, 2, 1, pattern, White, Black)
+	buffer.FillRectPattern(2, 1, 4, 1, pattern, White, Black)
+
+	expectRows(t, buffer,
+		"ababca",
+		"abcabc",
+	)
+}
+
+func TestFillRectPatternTransparent(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 1)
+	buffer.DrawText("xxxx", 0, 0, White, nil, 0)
+	pattern := FillPattern{Cells: [][]rune{{'#', 0}}, Origin: &Position{X: -1}}
+	buffer.FillRectPattern(0, 0, 10, 1, pattern, White, Blue)
+	expectRows(t, buffer, "x#x#")
+
+	if err := buffer.FillRectPattern(0, 0, 1, 1, FillPattern{}, White, Blue); err == nil {
+		t.Error("expected error for an empty pattern")
+	}
+}
@@ -0,0 +1,192 @@
+package opentui
+
+/*
+#include "opentui.h"
+*/
+import "C"
+import (
+	"os"
+	"strings"
+	"unsafe"
+)
+
+// ColorProfile is the range of colors a terminal can show
+type ColorProfile uint8
+
+const (
+	ProfileTrueColor ColorProfile = iota // 24-bit colors
+	Profile256                           // xterm 256 color palette
+	Profile16                            // 16 system colors
+	ProfileMono                          // No colors, only black and white
+)
+
+// ToANSI256 returns the index of the closest color of the xterm 256 color palette,
+// measured with a perceptually weighted distance. The 16 system colors are skipped
+// since terminals configure them freely, so pure red is 196 rather than 9.
+func (c RGBA) ToANSI256() uint8 {
+	best, bestDistance := uint8(16), -1
+	for n := 16; n < 256; n++ {
+		if d := perceptualDistance(c, xterm256Color(uint8(n))); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = uint8(n), d
+		}
+	}
+	return best
+}
+
+// ToANSI16 returns the index of the closest of the 16 system colors of
+// DefaultPalette, measured with a perceptually weighted distance.
+func (c RGBA) ToANSI16() uint8 {
+	best, bestDistance := uint8(0), -1
+	for n, color := range DefaultPalette {
+		if d := perceptualDistance(c, color); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = uint8(n), d
+		}
+	}
+	return best
+}
+
+// Convert returns the color the terminal shows for c under the profile: the closest
+// palette color for Profile256 and Profile16, black or white by luminance for
+// ProfileMono and c itself for ProfileTrueColor. Alpha is kept.
+func (p ColorProfile) Convert(c RGBA) RGBA {
+	var out RGBA
+	switch p {
+	case Profile256:
+		out = xterm256Color(c.ToANSI256())
+	case Profile16:
+		out = DefaultPalette[c.ToANSI16()]
+	case ProfileMono:
+		out = Black
+		if 0.2126*c.R+0.7152*c.G+0.0722*c.B >= 0.5 {
+			out = White
+		}
+	default:
+		return c
+	}
+	out.A = c.A
+	return out
+}
+
+// perceptualDistance returns the squared "redmean" distance between two colors, a cheap
+// approximation of perceived difference that weighs channels by how red they are.
+func perceptualDistance(a, b RGBA) int {
+	r1, g1, b1 := int(clamp01(a.R)*255+0.5), int(clamp01(a.G)*255+0.5), int(clamp01(a.B)*255+0.5)
+	r2, g2, b2 := int(clamp01(b.R)*255+0.5), int(clamp01(b.G)*255+0.5), int(clamp01(b.B)*255+0.5)
+	rmean := (r1 + r2) / 2
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return ((512+rmean)*dr*dr)>>8 + 4*dg*dg + ((767-rmean)*db*db)>>8
+}
+
+// SetColorProfile sets the colors the terminal can show. Unless it is
+// ProfileTrueColor, every frame is written with the palette indices closest to its
+// colors: 38;5;n for Profile256 and 30-37 or 90-97 for Profile16, while ProfileMono
+// writes no colors at all. The buffers keep the colors drawn into them. By default
+// the profile is picked by DetectColorProfile, upgraded to ProfileTrueColor when
+// the terminal reports truecolor support.
+func (r *Renderer) SetColorProfile(profile ColorProfile) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if profile > ProfileMono {
+		return newError("invalid color profile")
+	}
+	r.profile = &profile
+	return nil
+}
+
+// GetColorProfile returns the color profile set with SetColorProfile or
+// NewRendererWithOptions, or detected from the environment and terminal.
+func (r *Renderer) GetColorProfile() (ColorProfile, error) {
+	if r.ptr == nil {
+		return ProfileTrueColor, closedError("renderer")
+	}
+	return r.colorProfile(), nil
+}
+
+// colorProfile returns the profile in effect.
+func (r *Renderer) colorProfile() ColorProfile {
+	if r.profile != nil {
+		return *r.profile
+	}
+	profile := DetectColorProfile(os.Environ(), isTerminal(r.outputFile()))
+	if profile == Profile256 || profile == Profile16 {
+		if caps, err := r.GetTerminalCapabilities(); err == nil && caps.SupportsTruecolor {
+			return ProfileTrueColor
+		}
+	}
+	return profile
+}
+
+// DetectColorProfile picks the color profile from environment variables given as
+// "KEY=value" pairs, like os.Environ returns them, and whether the output is a
+// terminal. A non-empty NO_COLOR selects ProfileMono, as does output that is not a
+// terminal or TERM=dumb unless CLICOLOR_FORCE is set to something other than "0".
+// Otherwise COLORTERM=truecolor or 24bit selects ProfileTrueColor and a TERM
+// containing "256color" Profile256, falling back to Profile16.
+func DetectColorProfile(env []string, isTTY bool) ColorProfile {
+	lookup := func(key string) string {
+		value := ""
+		for _, kv := range env {
+			if k, v, ok := strings.Cut(kv, "="); ok && k == key {
+				value = v // Later entries win, like in the environment of a process
+			}
+		}
+		return value
+	}
+
+	if lookup("NO_COLOR") != "" {
+		return ProfileMono
+	}
+	force := lookup("CLICOLOR_FORCE")
+	forced := force != "" && force != "0"
+	colorTerm := strings.ToLower(lookup("COLORTERM"))
+	term := lookup("TERM")
+	switch {
+	case !forced && (!isTTY || term == "dumb"):
+		return ProfileMono
+	case colorTerm == "truecolor" || colorTerm == "24bit":
+		return ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return Profile256
+	}
+	return Profile16
+}
+
+// isTerminal reports whether f is a character device, which terminals are.
+func isTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// nativeColorMode is a color mode given to the native renderer
+type nativeColorMode struct {
+	set     bool
+	profile ColorProfile
+	system  ANSIPalette // DefaultPalette when it was given
+}
+
+// setColorMode makes the native renderer write colors as the profile allows, picking
+// the closest colors of the palette, unless it already does.
+func (r *Renderer) setColorMode(profile ColorProfile) {
+	mode := nativeColorMode{set: true, profile: profile, system: DefaultPalette}
+	if r.colorMode == mode {
+		return
+	}
+	r.colorMode = mode
+
+	var palette [256][4]C.float
+	n := 0
+	switch profile {
+	case Profile256:
+		n = len(palette)
+	case Profile16:
+		n = len(DefaultPalette)
+	}
+	for i := 0; i < n; i++ {
+		palette[i] = PaletteColor(uint8(i)).toC().rgba
+	}
+	C.setColorMode(r.ptr, C.uint8_t(profile), (*C.float)(unsafe.Pointer(&palette[0])), C.uint32_t(n))
+}
@@ -0,0 +1,154 @@
+package opentui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestToANSI(t *testing.T) {
+	tests := []struct {
+		color   RGBA
+		ansi256 uint8
+		ansi16  uint8
+	}{
+		{Red, 196, 9},
+		{Green, 46, 10},
+		{Blue, 21, 4},
+		{Black, 16, 0},
+		{White, 231, 15},
+		{NewRGB(0.5, 0.5, 0.5), 244, 8},
+		{rgb8(205, 0, 0), 160, 1},
+		{rgb8(255, 135, 0), 208, 3},
+	}
+	for _, tt := range tests {
+		if got := tt.color.ToANSI256(); got != tt.ansi256 {
+			t.Errorf("%+v.ToANSI256() = %d, want %d", tt.color, got, tt.ansi256)
+		}
+		if got := tt.color.ToANSI16(); got != tt.ansi16 {
+			t.Errorf("%+v.ToANSI16() = %d, want %d", tt.color, got, tt.ansi16)
+		}
+	}
+}
+
+func TestColorProfileConvert(t *testing.T) {
+	c := NewRGBA(0.98, 0.02, 0.01, 0.5)
+	if got := Profile256.Convert(c); got != NewRGBA(1, 0, 0, 0.5) {
+		t.Errorf("Profile256.Convert = %+v", got)
+	}
+	if got := ProfileMono.Convert(NewRGB(0.9, 0.9, 0.2)); got != White {
+		t.Errorf("ProfileMono.Convert of a light color = %+v", got)
+	}
+	if got := ProfileTrueColor.Convert(c); got != c {
+		t.Errorf("ProfileTrueColor.Convert = %+v", got)
+	}
+}
+
+func TestDetectColorProfile(t *testing.T) {
+	tests := []struct {
+		env   []string
+		isTTY bool
+		want  ColorProfile
+	}{
+		{[]string{"COLORTERM=truecolor", "TERM=xterm"}, true, ProfileTrueColor},
+		{[]string{"TERM=xterm-256color"}, true, Profile256},
+		{[]string{"TERM=xterm"}, true, Profile16},
+		{[]string{"TERM=dumb"}, true, ProfileMono},
+		{[]string{"TERM=xterm-256color", "NO_COLOR=1"}, true, ProfileMono},
+		{[]string{"TERM=xterm-256color", "NO_COLOR="}, true, Profile256},
+		{[]string{"TERM=xterm-256color"}, false, ProfileMono},
+		{[]string{"TERM=xterm-256color", "CLICOLOR_FORCE=1"}, false, Profile256},
+		{[]string{"TERM=xterm", "CLICOLOR_FORCE=0"}, false, ProfileMono},
+		{[]string{"COLORTERM=24bit", "CLICOLOR_FORCE=1", "NO_COLOR=1"}, false, ProfileMono},
+		{[]string{"TERM=xterm", "TERM=xterm-256color"}, true, Profile256},
+	}
+	for _, tt := range tests {
+		if got := DetectColorProfile(tt.env, tt.isTTY); got != tt.want {
+			t.Errorf("DetectColorProfile(%q, %v) = %d, want %d", tt.env, tt.isTTY, got, tt.want)
+		}
+	}
+}
+
+func TestRenderColorProfile(t *testing.T) {
+	t.Setenv("KITTY_WINDOW_ID", "1")
+	renderer := NewRenderer(4, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	renderer.SetColorProfile(Profile256)
+	next, _ := renderer.GetNextBuffer()
+	next.Clear(NewRGB(0.1, 0.1, 0.12))
+	next.DrawTextLink("go", "https://go.dev", 0, 0, NewRGB(0.95, 0.05, 0.05), nil, 0)
+	out := captureStdout(t, func() { renderer.Render(false) })
+	if !strings.Contains(out, "38;5;196") {
+		t.Errorf("overlay output should use palette indices, got %q", out)
+	}
+
+	// The frame keeps the colors drawn into it
+	current, _ := renderer.GetCurrentBuffer()
+	cell, _ := current.GetCell(3, 0)
+	if cell.Background != NewRGB(0.1, 0.1, 0.12) {
+		t.Errorf("background %+v was converted in the buffer", cell.Background)
+	}
+}
+
+func TestRenderColorModes(t *testing.T) {
+	tests := []struct {
+		profile ColorProfile
+		want    []string
+		unwant  []string
+	}{
+		{ProfileTrueColor, []string{"38;2;242;13;13"}, []string{"38;5;"}},
+		{Profile256, []string{"\x1b[38;5;196m", "\x1b[48;5;234m"}, []string{"38;2;"}},
+		{Profile16, []string{"\x1b[91m", "\x1b[40m"}, []string{"38;", "48;"}},
+		{ProfileMono, nil, []string{"38;", "48;", "\x1b[91m", "\x1b[40m"}},
+	}
+	for _, tt := range tests {
+		var out bytes.Buffer
+		profile := tt.profile
+		renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &profile})
+		if renderer == nil {
+			t.Skip("OpenTUI library not available")
+		}
+		next, _ := renderer.GetNextBuffer()
+		next.Clear(NewRGB(0.1, 0.1, 0.12))
+		next.DrawText("go", 0, 0, NewRGB(0.95, 0.05, 0.05), nil, 0)
+		if err := renderer.Render(false); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		renderer.Close()
+
+		frame := out.String()
+		for _, want := range tt.want {
+			if !strings.Contains(frame, want) {
+				t.Errorf("profile %d: frame %q lacks %q", tt.profile, frame, want)
+			}
+		}
+		for _, unwant := range tt.unwant {
+			if strings.Contains(frame, unwant) {
+				t.Errorf("profile %d: frame %q contains %q", tt.profile, frame, unwant)
+			}
+		}
+	}
+}
+
+func TestNewRendererWithOptionsColorProfile(t *testing.T) {
+	profile := Profile16
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, ColorProfile: &profile})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	profile = Profile256 // The renderer keeps its own copy
+	if got, _ := renderer.GetColorProfile(); got != Profile16 {
+		t.Errorf("GetColorProfile = %d, want Profile16", got)
+	}
+	invalid := ProfileMono + 1
+	if r := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, ColorProfile: &invalid}); r != nil {
+		r.Close()
+		t.Error("NewRendererWithOptions should reject an invalid profile")
+	}
+}
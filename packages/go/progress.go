@@ -0,0 +1,137 @@
+package opentui
+
+import (
+	"fmt"
+	"math"
+)
+
+// ProgressBarStyle selects the characters used to draw a progress bar
+type ProgressBarStyle uint8
+
+const (
+	ProgressBlocks  ProgressBarStyle = iota // Block characters with 1/8 cell precision
+	ProgressASCII                           // '#' for done and '-' for remaining cells
+	ProgressBraille                         // Braille dots with 1/2 cell precision
+)
+
+// LabelPlacement selects where the label of a progress bar is drawn
+type LabelPlacement uint8
+
+const (
+	LabelInside LabelPlacement = iota // Centered on top of the bar
+	LabelRight                        // One cell to the right of the bar
+)
+
+// ProgressBarOptions controls the appearance of a progress bar
+type ProgressBarOptions struct {
+	Style          ProgressBarStyle
+	FilledColor    RGBA
+	EmptyColor     RGBA
+	LabelFormat    string // fmt format applied to the percentage, e.g. "%.0f%%". Empty draws no label
+	Label          string // Text drawn as the label instead of LabelFormat, e.g. "12/80 files"
+	LabelPlacement LabelPlacement
+	LabelColor     RGBA // Replaced by black or white inside a ProgressBlocks bar where it is not readable
+}
+
+// Eighth blocks from 1/8 to 8/8 of a cell, filling from the left
+var eighthBlocks = [8]rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉', '█'}
+
+// Braille characters used for progress bars
+const (
+	brailleFull  = '⣿' // Both dot columns
+	brailleLeft  = '⡇' // Left dot column only
+	brailleTrack = '⣀' // Bottom dots marking the remaining part
+)
+
+// DrawProgressBar draws a horizontal progress bar of width cells at (x, y).
+// The fraction is clamped to [0, 1]. With ProgressBlocks the filled part is drawn in
+// FilledColor on an EmptyColor background; the other styles keep the existing background
+// and draw the remaining part in EmptyColor.
+func (b *Buffer) DrawProgressBar(x, y uint32, width uint32, fraction float64, opts ProgressBarOptions) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if opts.Style > ProgressBraille {
+		return newError("invalid progress bar style")
+	}
+	if math.IsNaN(fraction) {
+		fraction = 0
+	}
+	fraction = math.Min(math.Max(fraction, 0), 1)
+
+	// Number of cells fully covered by the filled part
+	var full uint32
+	switch opts.Style {
+	case ProgressBlocks:
+		eighths := uint32(math.Round(fraction * float64(width) * 8))
+		full = eighths / 8
+		for i := uint32(0); i < width; i++ {
+			char := ' '
+			if i < full {
+				char = '█'
+			} else if i == full && eighths%8 > 0 {
+				char = eighthBlocks[eighths%8-1]
+			}
+			b.SetCellWithAlphaBlending(x+i, y, char, opts.FilledColor, opts.EmptyColor, 0)
+		}
+	case ProgressASCII:
+		full = uint32(math.Round(fraction * float64(width)))
+		for i := uint32(0); i < width; i++ {
+			if i < full {
+				b.setCell(x+i, y, '#', opts.FilledColor, nil, 0)
+			} else {
+				b.setCell(x+i, y, '-', opts.EmptyColor, nil, 0)
+			}
+		}
+	case ProgressBraille:
+		halves := uint32(math.Round(fraction * float64(width) * 2))
+		full = halves / 2
+		for i := uint32(0); i < width; i++ {
+			switch {
+			case i < full:
+				b.setCell(x+i, y, brailleFull, opts.FilledColor, nil, 0)
+			case i == full && halves%2 == 1:
+				b.setCell(x+i, y, brailleLeft, opts.FilledColor, nil, 0)
+			default:
+				b.setCell(x+i, y, brailleTrack, opts.EmptyColor, nil, 0)
+			}
+		}
+	}
+
+	label := opts.Label
+	if label == "" && opts.LabelFormat != "" {
+		label = fmt.Sprintf(opts.LabelFormat, fraction*100)
+	}
+	if label == "" {
+		return nil
+	}
+	if opts.LabelPlacement == LabelRight {
+		start := int64(x) + int64(width) + 1
+		b.drawClusters(label, start, int64(y), start, math.MaxUint32, opts.LabelColor, nil, 0)
+		return nil
+	}
+
+	// Centered labels take the bar color below each character, switching to black or
+	// white where LabelColor would not be readable on it
+	start := int64(x) + (int64(width)-int64(stringWidth(label)))/2
+	end := int64(x) + int64(width)
+	col := start
+	forEachCluster(label, func(_ int, cluster string, cells int) {
+		pos := col
+		col += int64(cells)
+		if pos < int64(x) || col > end {
+			return
+		}
+		fg := opts.LabelColor
+		var bg *RGBA
+		if opts.Style == ProgressBlocks {
+			bg = &opts.EmptyColor
+			if uint32(pos)-x < full {
+				bg = &opts.FilledColor
+			}
+			fg = readableOn(fg, *bg)
+		}
+		b.DrawText(cluster, uint32(pos), y, fg, bg, 0)
+	})
+	return nil
+}
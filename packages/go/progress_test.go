@@ -0,0 +1,93 @@
+package opentui
+
+import "testing"
+
+func TestDrawProgressBarBlocks(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		row      string
+	}{
+		{0.5, "█████     "},
+		{0.423, "████▎     "},
+		{0, "          "},
+		{1, "██████████"},
+		{-3, "          "},
+		{7, "██████████"},
+	}
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 10, 1)
+		if err := buffer.DrawProgressBar(0, 0, 10, tt.fraction, ProgressBarOptions{}); err != nil {
+			t.Fatalf("DrawProgressBar failed: %v", err)
+		}
+		expectRows(t, buffer, tt.row)
+	}
+}
+
+func TestDrawProgressBarColors(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 1)
+	opts := ProgressBarOptions{FilledColor: Green, EmptyColor: Gray}
+	buffer.DrawProgressBar(0, 0, 4, 0.3, opts)
+
+	partial, _ := buffer.GetCell(1, 0)
+	if partial.Char != '▎' || partial.Foreground != Green || partial.Background != Gray {
+		t.Errorf("partial cell = %+v, want green ▎ on gray", partial)
+	}
+}
+
+func TestDrawProgressBarStyles(t *testing.T) {
+	tests := []struct {
+		style ProgressBarStyle
+		row   string
+	}{
+		{ProgressASCII, "####----"},
+		{ProgressBraille, "⣿⣿⣿⡇⣀⣀⣀⣀"},
+	}
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 8, 1)
+		buffer.DrawProgressBar(0, 0, 8, 0.44, ProgressBarOptions{Style: tt.style})
+		expectRows(t, buffer, tt.row)
+	}
+}
+
+func TestDrawProgressBarLabel(t *testing.T) {
+	buffer := newTestBuffer(t, 16, 2)
+	opts := ProgressBarOptions{FilledColor: Blue, EmptyColor: Gray, LabelFormat: "%.0f%%", LabelColor: White}
+	buffer.DrawProgressBar(0, 0, 10, 0.5, opts)
+
+	opts.LabelPlacement = LabelRight
+	opts.Style = ProgressASCII
+	buffer.DrawProgressBar(0, 1, 10, 0.423, opts)
+
+	expectRows(t, buffer,
+		"███50%          ",
+		"####------ 42%  ",
+	)
+
+	// The inside label takes the background of the bar below it
+	if cell, _ := buffer.GetCell(4, 0); cell.Background != Blue {
+		t.Errorf("label over the filled part has background %+v", cell.Background)
+	}
+	if cell, _ := buffer.GetCell(5, 0); cell.Background != Gray {
+		t.Errorf("label over the empty part has background %+v", cell.Background)
+	}
+}
+
+func TestDrawProgressBarTextLabel(t *testing.T) {
+	buffer := newTestBuffer(t, 12, 1)
+	opts := ProgressBarOptions{Style: ProgressASCII, FilledColor: Blue, EmptyColor: Gray, LabelFormat: "%.0f%%", Label: "3/8", LabelColor: White}
+	buffer.DrawProgressBar(0, 0, 9, 3.0/8, opts)
+	expectRows(t, buffer, "###3/8---   ")
+}
+
+func TestDrawProgressBarReadableLabel(t *testing.T) {
+	buffer := newTestBuffer(t, 10, 1)
+	opts := ProgressBarOptions{FilledColor: Blue, EmptyColor: Yellow, LabelFormat: "%.0f%%", LabelColor: White}
+	buffer.DrawProgressBar(0, 0, 10, 0.5, opts)
+
+	if cell, _ := buffer.GetCell(4, 0); cell.Foreground != White {
+		t.Errorf("label over the filled part = %+v, want White", cell.Foreground)
+	}
+	if cell, _ := buffer.GetCell(5, 0); cell.Foreground != Black {
+		t.Errorf("label over the empty part = %+v, want Black", cell.Foreground)
+	}
+}
@@ -0,0 +1,228 @@
+package opentui
+
+// ScrollRegion moves the cells inside rect by dy rows, with positive values scrolling up.
+// Rows scrolled out of the rect are dropped and the vacated rows are filled with fill.
+// Cells outside the rect are never touched. Parts of rect outside the buffer are ignored.
+func (b *Buffer) ScrollRegion(rect Rect, dy int32, fill Cell) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+
+	x0, y0, x1, y1, ok := clipToBuffer(rect, da.Width, da.Height)
+	if !ok || dy == 0 {
+		return nil
+	}
+	width := x1 - x0
+	shift := abs64(int64(dy))
+	if shift >= int64(y1-y0) {
+		for y := y0; y < y1; y++ {
+			da.fillCells(y*da.Width+x0, width, fill)
+		}
+		return nil
+	}
+	if da.hasClusters() {
+		da.scrollCells(x0, y0, x1, y1, 0, int64(dy), fill)
+		return nil
+	}
+
+	if dy > 0 {
+		for y := y0; y < y1; y++ {
+			if src := int64(y) + shift; src < int64(y1) {
+				da.copyCells(y*da.Width+x0, uint32(src)*da.Width+x0, width)
+			} else {
+				da.fillCells(y*da.Width+x0, width, fill)
+			}
+		}
+	} else {
+		for y := int64(y1) - 1; y >= int64(y0); y-- {
+			if src := y - shift; src >= int64(y0) {
+				da.copyCells(uint32(y)*da.Width+x0, uint32(src)*da.Width+x0, width)
+			} else {
+				da.fillCells(uint32(y)*da.Width+x0, width, fill)
+			}
+		}
+	}
+	return nil
+}
+
+// ScrollRegionHorizontal moves the cells inside rect by dx columns, with positive values
+// scrolling left. It is the horizontal counterpart of ScrollRegion, e.g. for tickers.
+func (b *Buffer) ScrollRegionHorizontal(rect Rect, dx int32, fill Cell) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+
+	x0, y0, x1, y1, ok := clipToBuffer(rect, da.Width, da.Height)
+	if !ok || dx == 0 {
+		return nil
+	}
+	width := x1 - x0
+	shift := uint32(min(abs64(int64(dx)), int64(width)))
+	if shift < width && da.hasClusters() {
+		da.scrollCells(x0, y0, x1, y1, int64(dx), 0, fill)
+		return nil
+	}
+
+	for y := y0; y < y1; y++ {
+		row := y*da.Width + x0
+		if dx > 0 {
+			da.copyCells(row, row+shift, width-shift)
+			da.fillCells(row+width-shift, shift, fill)
+		} else {
+			da.copyCells(row+shift, row, width-shift)
+			da.fillCells(row, shift, fill)
+		}
+	}
+	return nil
+}
+
+// clipToBuffer intersects rect with a buffer of the given size.
+// It returns the half-open cell range and false if nothing is left.
+func clipToBuffer(rect Rect, width, height uint32) (x0, y0, x1, y1 uint32, ok bool) {
+	left := max(int64(rect.X), 0)
+	top := max(int64(rect.Y), 0)
+	right := min(int64(rect.X)+int64(rect.Width), int64(width))
+	bottom := min(int64(rect.Y)+int64(rect.Height), int64(height))
+	if left >= right || top >= bottom {
+		return 0, 0, 0, 0, false
+	}
+	return uint32(left), uint32(top), uint32(right), uint32(bottom), true
+}
+
+// copyCells copies n consecutive cells from src to dst. Overlapping ranges are handled like memmove.
+func (da *DirectAccess) copyCells(dst, src, n uint32) {
+	copy(da.Chars[dst:dst+n], da.Chars[src:src+n])
+	copy(da.Foreground[dst:dst+n], da.Foreground[src:src+n])
+	copy(da.Background[dst:dst+n], da.Background[src:src+n])
+	copy(da.Attributes[dst:dst+n], da.Attributes[src:src+n])
+}
+
+// scrollCells moves the cells of the rectangle from (x0, y0) to (x1, y1) by dx columns
+// and dy rows, shifts smaller than the rectangle with positive values moving cells up
+// and left, and fills the cells left behind. Clusters are drawn anew at their new place.
+func (da *DirectAccess) scrollCells(x0, y0, x1, y1 uint32, dx, dy int64, fill Cell) {
+	width := x1 - x0 - uint32(abs64(dx))
+	height := y1 - y0 - uint32(abs64(dy))
+	srcX, srcY := x0+uint32(max(dx, 0)), y0+uint32(max(dy, 0))
+	dstX, dstY := x0+uint32(max(-dx, 0)), y0+uint32(max(-dy, 0))
+	copyCellRect(da, da, srcX, srcY, dstX, dstY, width, height)
+	for y := y0; y < y1; y++ {
+		for x := x0; x < x1; x++ {
+			if x < dstX || x >= dstX+width || y < dstY || y >= dstY+height {
+				da.fillCells(y*da.Width+x, 1, fill)
+			}
+		}
+	}
+}
+
+// fillCells sets n consecutive cells starting at index to cell.
+func (da *DirectAccess) fillCells(index, n uint32, cell Cell) {
+	for i := index; i < index+n; i++ {
+		if da.ptr != nil && isClusterChar(da.Chars[i]) {
+			// The native layer blanks the rest of the cluster overwritten
+			da.SetCell(i%da.Width, i/da.Width, cell)
+			continue
+		}
+		da.Chars[i] = uint32(cell.Char)
+		da.Foreground[i] = cell.Foreground
+		da.Background[i] = cell.Background
+		da.Attributes[i] = cell.Attributes
+	}
+}
+
+// CopyRect copies the cells inside src to dest within the same buffer.
+// Overlapping source and destination rectangles are handled like memmove.
+// Both rectangles are clipped to the buffer, so partially off-screen rectangles are fine.
+func (b *Buffer) CopyRect(src Rect, dest Position) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	copyRegion(da, da, src, dest)
+	return nil
+}
+
+// CopyRectFrom copies the cells inside srcRect of another buffer to dest in this buffer.
+// Cells are copied as they are, without the alpha blending DrawFrameBuffer may apply.
+// Rectangles are clipped to both buffers.
+func (b *Buffer) CopyRectFrom(src *Buffer, srcRect Rect, dest Position) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if src == nil || src.ptr == nil {
+		return kindError(ErrClosed, "source buffer is nil or closed")
+	}
+	dst, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	srcCells, err := src.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	copyRegion(dst, srcCells, srcRect, dest)
+	return nil
+}
+
+// copyRegion copies a rectangle of cells from src to dest in dst, clipping both sides.
+// src and dst may be the same buffer.
+func copyRegion(dst, src *DirectAccess, srcRect Rect, dest Position) {
+	x0, y0, x1, y1, ok := clipToBuffer(srcRect, src.Width, src.Height)
+	if !ok {
+		return
+	}
+	// Keep the destination aligned with the clipped source
+	destX := int64(dest.X) + int64(x0) - int64(srcRect.X)
+	destY := int64(dest.Y) + int64(y0) - int64(srcRect.Y)
+
+	clipped := Rect{
+		Position: Position{X: int32(destX), Y: int32(destY)},
+		Size:     Size{Width: x1 - x0, Height: y1 - y0},
+	}
+	if destX < -int64(clipped.Width) || destY < -int64(clipped.Height) ||
+		destX > int64(dst.Width) || destY > int64(dst.Height) {
+		return
+	}
+	dx0, dy0, dx1, dy1, ok := clipToBuffer(clipped, dst.Width, dst.Height)
+	if !ok {
+		return
+	}
+	x0 = uint32(int64(x0) + int64(dx0) - destX)
+	y0 = uint32(int64(y0) + int64(dy0) - destY)
+	width, height := dx1-dx0, dy1-dy0
+	if src.hasClusters() || dst.hasClusters() {
+		copyCellRect(dst, src, x0, y0, dx0, dy0, width, height)
+		return
+	}
+
+	copyRow := func(row uint32) {
+		s := (y0+row)*src.Width + x0
+		d := (dy0+row)*dst.Width + dx0
+		copy(dst.Chars[d:d+width], src.Chars[s:s+width])
+		copy(dst.Foreground[d:d+width], src.Foreground[s:s+width])
+		copy(dst.Background[d:d+width], src.Background[s:s+width])
+		copy(dst.Attributes[d:d+width], src.Attributes[s:s+width])
+	}
+
+	// Copying downwards within a buffer has to start at the bottom
+	if dst == src && dy0 > y0 {
+		for row := height; row > 0; row-- {
+			copyRow(row - 1)
+		}
+		return
+	}
+	for row := uint32(0); row < height; row++ {
+		copyRow(row)
+	}
+}
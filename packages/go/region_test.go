@@ -0,0 +1,166 @@
+package opentui
+
+import "testing"
+
+// fillTestRows draws one string per row, starting at the top left corner.
+func fillTestRows(buffer *Buffer, rows ...string) {
+	for y, row := range rows {
+		buffer.DrawText(row, 0, uint32(y), White, nil, 0)
+	}
+}
+
+func TestScrollRegion(t *testing.T) {
+	fill := Cell{Char: '~', Foreground: White, Background: Blue}
+	rect := Rect{Position: Position{X: 1, Y: 1}, Size: Size{Width: 2, Height: 3}}
+
+	tests := []struct {
+		name string
+		dy   int32
+		rows []string
+	}{
+		{"up", 1, []string{"abcd", "ejkh", "inol", "m~~p"}},
+		{"down", -2, []string{"abcd", "e~~h", "i~~l", "mfgp"}},
+		{"clear", 3, []string{"abcd", "e~~h", "i~~l", "m~~p"}},
+		{"none", 0, []string{"abcd", "efgh", "ijkl", "mnop"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newTestBuffer(t, 4, 4)
+			fillTestRows(buffer, "abcd", "efgh", "ijkl", "mnop")
+			if err := buffer.ScrollRegion(rect, tt.dy, fill); err != nil {
+				t.Fatalf("ScrollRegion failed: %v", err)
+			}
+			expectRows(t, buffer, tt.rows...)
+		})
+	}
+}
+
+func TestScrollRegionFillsColors(t *testing.T) {
+	buffer := newTestBuffer(t, 2, 2)
+	fill := Cell{Char: ' ', Foreground: White, Background: Blue, Attributes: AttrBold}
+	buffer.ScrollRegion(Rect{Size: Size{Width: 2, Height: 2}}, 1, fill)
+
+	da, _ := buffer.GetDirectAccess()
+	if cell, _ := da.GetCell(1, 1); *cell != fill {
+		t.Errorf("vacated cell = %+v, want %+v", cell, fill)
+	}
+}
+
+func TestScrollRegionClipping(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 3)
+	fillTestRows(buffer, "abc", "def", "ghi")
+
+	// Only the part of the rect inside the buffer scrolls
+	rect := Rect{Position: Position{X: -1, Y: 1}, Size: Size{Width: 3, Height: 10}}
+	if err := buffer.ScrollRegion(rect, 1, Cell{Char: '.'}); err != nil {
+		t.Fatalf("ScrollRegion failed: %v", err)
+	}
+	expectRows(t, buffer, "abc", "ghf", "..i")
+}
+
+func TestScrollRegionHorizontal(t *testing.T) {
+	fill := Cell{Char: '.'}
+	rect := Rect{Position: Position{X: 1, Y: 0}, Size: Size{Width: 4, Height: 1}}
+
+	tests := []struct {
+		dx  int32
+		row string
+	}{
+		{1, "acde.f"},
+		{-2, "a..bcf"},
+		{5, "a....f"},
+	}
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 6, 1)
+		fillTestRows(buffer, "abcdef")
+		if err := buffer.ScrollRegionHorizontal(rect, tt.dx, fill); err != nil {
+			t.Fatalf("ScrollRegionHorizontal failed: %v", err)
+		}
+		expectRows(t, buffer, tt.row)
+	}
+}
+
+func TestScrollRegionClusters(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 2)
+	fillTestRows(buffer, "ab漢cd", "xy")
+
+	if err := buffer.ScrollRegionHorizontal(Rect{Size: Size{Width: 6, Height: 1}}, 1, Cell{Char: '.'}); err != nil {
+		t.Fatalf("ScrollRegionHorizontal failed: %v", err)
+	}
+	if err := buffer.ScrollRegion(Rect{Size: Size{Width: 6, Height: 2}}, -1, Cell{Char: '~'}); err != nil {
+		t.Fatalf("ScrollRegion failed: %v", err)
+	}
+	expectRows(t, buffer, "~~~~~~", "b漢 cd.")
+	if kind, _ := buffer.GetCellKind(2, 1); kind != CellContinuation {
+		t.Errorf("cell (2, 1) = %v, want the second half of 漢", kind)
+	}
+}
+
+func TestCopyRect(t *testing.T) {
+	src := Rect{Position: Position{X: 0, Y: 0}, Size: Size{Width: 3, Height: 3}}
+
+	tests := []struct {
+		name string
+		dest Position
+		rows []string
+	}{
+		{"overlap down right", Position{X: 1, Y: 1}, []string{"abcd", "eabc", "iefg", "mijk"}},
+		{"overlap up left", Position{X: -1, Y: -1}, []string{"fgcd", "jkgh", "ijkl", "mnop"}},
+		{"off screen", Position{X: 10, Y: 0}, []string{"abcd", "efgh", "ijkl", "mnop"}},
+		{"partially off screen", Position{X: 2, Y: 3}, []string{"abcd", "efgh", "ijkl", "mnab"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newTestBuffer(t, 4, 4)
+			fillTestRows(buffer, "abcd", "efgh", "ijkl", "mnop")
+			if err := buffer.CopyRect(src, tt.dest); err != nil {
+				t.Fatalf("CopyRect failed: %v", err)
+			}
+			expectRows(t, buffer, tt.rows...)
+		})
+	}
+}
+
+func TestCopyRectClusters(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 1)
+	buffer.DrawText("a漢b", 0, 0, White, nil, 0)
+
+	// 漢 is copied whole
+	if err := buffer.CopyRect(Rect{Position: Position{X: 1}, Size: Size{Width: 3, Height: 1}}, Position{X: 4}); err != nil {
+		t.Fatalf("CopyRect failed: %v", err)
+	}
+	expectRows(t, buffer, "a漢 b漢 b ")
+	if kind, _ := buffer.GetCellKind(5, 0); kind != CellContinuation {
+		t.Errorf("cell 5 = %v, want the second half of 漢", kind)
+	}
+
+	// Half of 漢 is cut off by the edge of the rectangle, and the 漢 drawn over loses both halves
+	if err := buffer.CopyRect(Rect{Position: Position{X: 2}, Size: Size{Width: 2, Height: 1}}, Position{X: 0}); err != nil {
+		t.Fatalf("CopyRect failed: %v", err)
+	}
+	expectRows(t, buffer, " b b漢 b ")
+}
+
+func TestCopyRectFrom(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 2)
+	other := newTestBuffer(t, 3, 2)
+	fillTestRows(other, "xyz", "uvw")
+	other.SetCell(0, 0, Cell{Char: 'x', Foreground: White, Background: RGBA{R: 1, A: 0.5}})
+
+	// The source rect starts outside the other buffer
+	srcRect := Rect{Position: Position{X: -1, Y: 0}, Size: Size{Width: 3, Height: 5}}
+	if err := buffer.CopyRectFrom(other, srcRect, Position{X: 2, Y: 0}); err != nil {
+		t.Fatalf("CopyRectFrom failed: %v", err)
+	}
+	expectRows(t, buffer, "   x", "   u")
+
+	// Translucent cells are copied without blending
+	da, _ := buffer.GetDirectAccess()
+	if cell, _ := da.GetCell(3, 0); cell.Background != (RGBA{R: 1, A: 0.5}) {
+		t.Errorf("background = %+v, want the translucent source color", cell.Background)
+	}
+
+	if err := buffer.CopyRectFrom(nil, srcRect, Position{}); err == nil {
+		t.Error("expected error for nil source buffer")
+	}
+}
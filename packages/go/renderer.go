@@ -2,88 +2,288 @@ package opentui
 
 /*
 #include "opentui.h"
+#include "color.h"
 #include <stdlib.h>
 */
 import "C"
 import (
-	"unsafe"
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"sync"
+	"sync/atomic"
 )
 
 // Renderer wraps the CliRenderer from the C library.
 // It provides high-level access to terminal rendering functionality.
+//
+// A renderer and its buffers are meant to be used from one goroutine. Other
+// goroutines drawing into the buffers of the renderer must hold its lock, see Lock;
+// Render, RenderWithStats and RunLoop take it themselves while they use the buffers.
+// Methods documented as safe for use from any goroutine, such as PrintAbove, Suspend
+// and QueryCursorPosition, need no lock.
 type Renderer struct {
-	ptr *C.CliRenderer
+	ptr         *C.CliRenderer
+	width       uint32
+	height      uint32
+	debugOutput io.Writer
+	detected    detectedCapabilities
+	images      []terminalImage
+	layers      []*Layer // composited over every frame, see NewLayer
+	layerSeq    uint64   // creation order of the layers
+	
+	links         *linkTable // hyperlinks drawn into the next buffer
+	renderedLinks *linkTable // hyperlinks shown on the terminal
+	showLinkURLs  bool
+	
+	profile    *ColorProfile   // color profile set with SetColorProfile, nil to detect
+	colorMode  nativeColorMode // color mode last given to the native renderer
+	
+	background backgroundQuery     // background color reported with OSC 11
+	cursor     cursorQuery         // cursor position reports for QueryCursorPosition
+	detection  capabilityDetection // DetectCapabilities calls waiting for DA1
+	resize     resizeWatch         // terminal size changes applied on Render
+	
+	altScreen   bool // alternate screen buffer active
+	forceRender bool // next Render redraws every cell
+	useThread   bool // native renderer writes frames from its own thread
+	syncOutput  bool // wrap frames in synchronized output markers
+	
+	output  io.Writer // destination of the terminal output, nil for stdout
+	written atomic.Uint64 // bytes written to the terminal from Go and, with output set, natively
+	input   io.Reader // source of replies to terminal queries, nil if passed in by hand
+	replies sync.Once // starts reading replies from input
+	
+	terminalSetup bool // SetupTerminal was called
+	handleSuspend bool // SetupTerminal installs the suspend handlers
+	suspend       suspendState
+	mouseEnabled  bool
+	mouseMovement bool
+	kittyEnabled  bool
+	kittyFlags    uint8
+	kittyStack    []uint8    // flags pushed with PushKittyKeyboard and not popped yet
+	kittyQuery    kittyQuery // kitty keyboard flag replies for QueryKittyKeyboard
+	mousePixels   bool       // mouse reports in pixels, see EnableMousePixels
+	cursorStyled  bool       // a cursor style was set, see SetCursorShape
+	
+	clipboard       *bool         // OSC 52 support set with SetClipboardSupport, nil to detect
+	tmuxPassthrough bool          // wrap clipboard writes for tmux
+	clipboardRead   clipboardRead // OSC 52 replies for ReadClipboard
+	
+	headless     bool          // output is not a terminal, see Interactive
+	printFinal   bool          // print the last frame as text on Close when headless
+	inline       *inlineRegion // region on the main screen, nil unless rendering inline
+	split        *splitScreen  // layout set up by SetupSplit, nil if not split
+	renderOffset uint32        // rows between the top of the terminal and the frame
+	
+	frame sync.Mutex // guards the buffers against concurrent drawing, see Lock
+}
+
+// RendererOptions configures a renderer created with NewRendererWithOptions
+type RendererOptions struct {
+	Width  uint32 // Both Width and Height 0 takes the size from TerminalSize
+	Height uint32
+
+	// ColorProfile overrides the profile DetectColorProfile picks from the environment,
+	// nil detects it
+	ColorProfile *ColorProfile
+	
+	// Output receives everything the renderer writes to the terminal instead of
+	// stdout, for example a pty, an SSH channel or a bytes.Buffer in tests. The native
+	// renderer can only write to file descriptor 1, so its output is piped from there
+	// while it writes, which rules out threaded rendering. Set the size and the
+	// ColorProfile explicitly unless Output is a terminal *os.File.
+	Output io.Writer
+	
+	// Input is read for the replies to the queries sent by SetupTerminal,
+	// DetectCapabilities, QueryBackgroundColor, ReadClipboard and QueryCursorPosition,
+	// which are passed to ProcessCapabilityResponse. Reading starts with the first query and continues
+	// until Input fails or the renderer is closed, so Input must not be shared with
+	// keyboard input. Nil leaves passing the replies to the application.
+	Input io.Reader
+	
+	// HandleSuspend makes SetupTerminal install handlers that call Suspend on SIGTSTP
+	// and Resume on SIGCONT. Terminals in raw mode don't send SIGTSTP for Ctrl+Z, so
+	// applications reading raw input call Suspend themselves.
+	HandleSuspend bool
+	
+	// Inline renders into InlineHeight rows on the main screen instead of the whole
+	// terminal, for live output below the shell prompt. Width and Height give the
+	// terminal size, detected when both are 0. SetupTerminal reserves the rows at the
+	// cursor, scrolling if it is near the bottom, and has to be passed false.
+	Inline       bool
+	InlineHeight uint32
+	// KeepInlineFrame leaves the last frame on the screen on Close, with the cursor
+	// below it. By default the region is erased.
+	KeepInlineFrame bool
+	// CaptureStdout makes SetupTerminal replace os.Stdout with a pipe printed above
+	// the inline region with PrintAbove until Close, so fmt.Println keeps working.
+	CaptureStdout bool
+	
+	// ForceTTY treats the output as a terminal even if it is a file that isn't one,
+	// which otherwise makes the renderer headless (see Interactive).
+	ForceTTY bool
+	// PrintFinalFrame makes Close of a headless renderer write the last rendered
+	// frame to the output as plain text.
+	PrintFinalFrame bool
 }
 
-// NewRenderer creates a new renderer with the specified dimensions.
-// Returns nil if the renderer could not be created.
+// NewRenderer creates a new renderer with the specified dimensions, or with the size
+// of the terminal if both are 0. Returns nil if the renderer could not be created.
+// Use NewRendererAuto to learn why the terminal size could not be detected.
 func NewRenderer(width, height uint32) *Renderer {
-	if width == 0 || height == 0 {
+	return NewRendererWithOptions(RendererOptions{Width: width, Height: height})
+}
+
+// NewRendererWithOptions creates a new renderer configured by opts.
+// Returns nil if the renderer could not be created or the color profile is invalid.
+func NewRendererWithOptions(opts RendererOptions) *Renderer {
+	// Output to stdout itself needs no redirection
+	if f, ok := opts.Output.(*os.File); ok && f.Fd() == 1 {
+		opts.Output = nil
+	}
+	// Output to a file that isn't a terminal, such as stdout in CI, is not drawn on
+	headless := false
+	if f := outputFile(opts.Output); f != nil && !opts.ForceTTY {
+		headless = !outputIsTerminal(f)
+	}
+	if opts.Width == 0 && opts.Height == 0 && headless {
+		opts.Width, opts.Height = headlessSize()
+	} else if opts.Width == 0 && opts.Height == 0 {
+		width, height, err := terminalSize(outputFile(opts.Output))
+		if err != nil {
+			return nil
+		}
+		opts.Width, opts.Height = width, height
+	}
+	if opts.Width == 0 || opts.Height == 0 {
 		return nil
 	}
+	if opts.ColorProfile != nil && *opts.ColorProfile > ProfileMono {
+		return nil
+	}
+	var inline *inlineRegion
+	if opts.Inline {
+		if opts.InlineHeight == 0 {
+			return nil
+		}
+		inline = &inlineRegion{height: opts.InlineHeight, rows: opts.Height, keep: opts.KeepInlineFrame, captureStdout: opts.CaptureStdout}
+		opts.Height = min(opts.InlineHeight, opts.Height)
+	}
 	
-	ptr := C.createRenderer(C.uint32_t(width), C.uint32_t(height))
+	ptr := C.createRenderer(C.uint32_t(opts.Width), C.uint32_t(opts.Height))
 	if ptr == nil {
 		return nil
 	}
 	
-	r := &Renderer{ptr: ptr}
+	r := &Renderer{ptr: ptr, width: opts.Width, height: opts.Height, output: opts.Output, input: opts.Input, handleSuspend: opts.HandleSuspend, inline: inline}
+	r.headless, r.printFinal = headless, opts.PrintFinalFrame
+	if headless {
+		// The frames are still diffed, for CaptureText and the final frame
+		C.setDiscardOutput(ptr, C.bool(true))
+	}
+	if opts.ColorProfile != nil {
+		profile := *opts.ColorProfile
+		r.profile = &profile
+	}
 	setFinalizer(r, func(r *Renderer) { r.Close() })
 	return r
 }
 
-// Close destroys the renderer and releases its resources.
-// After calling Close, the renderer should not be used.
+// Close destroys the renderer and releases its resources, leaving the terminal on
+// the main screen. After calling Close, the renderer should not be used.
 func (r *Renderer) Close() error {
-	if r.ptr != nil {
-		clearFinalizer(r)
-		C.destroyRenderer(r.ptr, C.bool(false), C.uint32_t(0))
-		r.ptr = nil
-	}
-	return nil
+	return r.close(false, 0)
 }
 
 // CloseWithOptions destroys the renderer with specific cleanup options.
+// Like Close, it leaves the terminal on the main screen.
 func (r *Renderer) CloseWithOptions(useAlternateScreen bool, splitHeight uint32) error {
-	if r.ptr != nil {
-		clearFinalizer(r)
-		C.destroyRenderer(r.ptr, C.bool(useAlternateScreen), C.uint32_t(splitHeight))
-		r.ptr = nil
+	return r.close(useAlternateScreen, splitHeight)
+}
+
+// close restores what the renderer changed on the terminal and destroys it, passing
+// the cleanup options on to the native renderer. Closing twice does nothing.
+func (r *Renderer) close(useAlternateScreen bool, splitHeight uint32) error {
+	if r.ptr == nil {
+		return nil
 	}
-	return nil
+	clearFinalizer(r)
+	r.StopWatchingResize()
+	r.stopWatchingSuspend()
+	if r.inline != nil {
+		r.closeInline()
+	}
+	if r.split != nil {
+		r.closeSplit()
+	}
+	if sequence := r.popKittyStack(); sequence != "" {
+		r.terminal().Write([]byte(sequence))
+	}
+	if r.mousePixels {
+		r.terminal().Write([]byte(disableMousePixels))
+	}
+	if r.cursorStyled {
+		r.terminal().Write([]byte(resetCursorStyle))
+	}
+	r.closeLayers()
+	var finalErr error
+	if r.headless && r.printFinal {
+		finalErr = r.printFinalFrame()
+	}
+	ptr := r.ptr
+	err := r.native(func() { C.destroyRenderer(ptr, C.bool(useAlternateScreen), C.uint32_t(splitHeight)) })
+	if err == nil {
+		err = finalErr
+	}
+	r.ptr = nil
+	r.leaveAltScreenOnClose()
+	return err
 }
 
-// SetUseThread enables or disables threaded rendering.
+// SetUseThread enables or disables threaded rendering. Threaded rendering can't be
+// enabled for a renderer with its own Output. Frames with hyperlinks, images or a split
+// screen wait for the render thread to write them before going on.
 func (r *Renderer) SetUseThread(useThread bool) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
+	}
+	if useThread && r.output != nil {
+		return newError("threaded rendering needs stdout as output")
+	}
+	if useThread && r.headless {
+		return ErrNotATTY
 	}
 	C.setUseThread(r.ptr, C.bool(useThread))
+	r.useThread = useThread
 	return nil
 }
 
 // SetBackgroundColor sets the global background color for the renderer.
 func (r *Renderer) SetBackgroundColor(color RGBA) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	C.setBackgroundColor(r.ptr, color.toCFloat())
+	C.setBackgroundColorValue(r.ptr, color.toC())
 	return nil
 }
 
-// SetRenderOffset sets the vertical offset for rendering.
+// SetRenderOffset draws frames offset rows down from the top of the terminal, leaving
+// the rows above to other output. Inline renderers manage the offset themselves.
 func (r *Renderer) SetRenderOffset(offset uint32) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.setRenderOffset(r.ptr, C.uint32_t(offset))
+	r.renderOffset = offset
 	return nil
 }
 
 // UpdateStats updates the renderer's performance statistics.
 func (r *Renderer) UpdateStats(stats Stats) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.updateStats(r.ptr, C.double(stats.Time), C.uint32_t(stats.FPS), C.double(stats.FrameCallbackTime))
 	return nil
@@ -92,17 +292,33 @@ func (r *Renderer) UpdateStats(stats Stats) error {
 // UpdateMemoryStats updates the renderer's memory usage statistics.
 func (r *Renderer) UpdateMemoryStats(stats MemoryStats) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.updateMemoryStats(r.ptr, C.uint32_t(stats.HeapUsed), C.uint32_t(stats.HeapTotal), C.uint32_t(stats.ArrayBuffers))
 	return nil
 }
 
+// Lock locks the buffers of the renderer for drawing from several goroutines. Render,
+// RenderWithStats and RunLoop hold the lock while they use the buffers, so a goroutine
+// holding it can safely call GetNextBuffer, draw into the buffer, Resize and the other
+// methods touching the buffers, but must not call those three.
+//
+// Threaded rendering doesn't change this: the frame is taken from the buffers before
+// Render returns, and the render thread only writes it to the terminal.
+func (r *Renderer) Lock() {
+	r.frame.Lock()
+}
+
+// Unlock unlocks the buffers locked by Lock.
+func (r *Renderer) Unlock() {
+	r.frame.Unlock()
+}
+
 // GetNextBuffer returns the next buffer for rendering.
 // This buffer can be used to draw content that will be displayed on the next render.
 func (r *Renderer) GetNextBuffer() (*Buffer, error) {
 	if r.ptr == nil {
-		return nil, newError("renderer is closed")
+		return nil, closedError("renderer")
 	}
 	
 	bufferPtr := C.getNextBuffer(r.ptr)
@@ -112,13 +328,13 @@ func (r *Renderer) GetNextBuffer() (*Buffer, error) {
 	
 	// Don't set a finalizer for buffers obtained from renderer,
 	// they are managed by the renderer itself
-	return &Buffer{ptr: bufferPtr, managed: true}, nil
+	return &Buffer{ptr: bufferPtr, managed: true, widthMethod: WidthMethodUnicode, links: r.nextLinks()}, nil
 }
 
 // GetCurrentBuffer returns the current buffer being rendered.
 func (r *Renderer) GetCurrentBuffer() (*Buffer, error) {
 	if r.ptr == nil {
-		return nil, newError("renderer is closed")
+		return nil, closedError("renderer")
 	}
 	
 	bufferPtr := C.getCurrentBuffer(r.ptr)
@@ -126,28 +342,107 @@ func (r *Renderer) GetCurrentBuffer() (*Buffer, error) {
 		return nil, newError("failed to get current buffer")
 	}
 	
-	return &Buffer{ptr: bufferPtr, managed: true}, nil
+	return &Buffer{ptr: bufferPtr, managed: true, widthMethod: WidthMethodUnicode}, nil
 }
 
 // Render renders the current buffer to the terminal.
 // If force is true, forces a complete re-render even if nothing has changed.
+// A terminal resize noticed since the last frame is applied first, see WatchResize.
+// Use RenderWithStats to learn what the frame changed.
 func (r *Renderer) Render(force bool) error {
+	return r.render(force, nil)
+}
+
+// render renders the next buffer, filling in stats unless it is nil.
+func (r *Renderer) render(force bool, stats *RenderStats) error {
+	r.frame.Lock()
+	defer r.frame.Unlock()
+	return r.renderLocked(force, stats)
+}
+
+// renderLocked is render for a caller holding the frame lock.
+func (r *Renderer) renderLocked(force bool, stats *RenderStats) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	C.render(r.ptr, C.bool(force))
-	return nil
+	// Suspend and Resume may run on the signal handler goroutine, and PrintAbove on
+	// any goroutine
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	if r.suspend.suspended {
+		return nil
+	}
+	if err := r.applyPendingResize(); err != nil {
+		return err
+	}
+	
+	// Layers go over the frame before its cells are compared
+	if err := r.compositeLayers(); err != nil {
+		return err
+	}
+	r.setColorMode(r.colorProfile())
+	
+	// Terminal images sit on top of the cells and have to be redrawn if any cell below changes
+	force = force || r.forceRender
+	r.forceRender = false
+	if stats != nil {
+		stats.FullRedraw = force
+		stats.CellsChanged = r.changedCells(force)
+	}
+	redrawImages := len(r.images) > 0 && (force || r.imagesDamaged())
+	overlay := r.overlayRuns(force)
+	sync := r.synchronizeOutput()
+	if sync {
+		if _, err := r.terminal().Write([]byte(beginSyncOutput)); err != nil {
+			return err
+		}
+	}
+	var renderErr error
+	err := r.native(func() { renderErr = checkNative(func() { C.render(r.ptr, C.bool(force)) }) })
+	if err == nil {
+		err = renderErr
+	}
+	if err == nil && r.useThread && (len(overlay) > 0 || redrawImages || r.split != nil) {
+		// The render thread writes the frame on its own, and what goes over it has to
+		// follow it
+		C.waitForRender(r.ptr)
+	}
+	if err == nil {
+		err = r.emitOverlay(overlay)
+	}
+	if err == nil && redrawImages {
+		err = r.emitImages()
+	}
+	if err == nil && r.split != nil {
+		// Plain writes to stdout go to the scrolling area
+		_, err = r.terminal().Write([]byte(r.splitCursor()))
+	}
+	if sync {
+		// Always end the update, or the terminal keeps holding back output
+		if _, endErr := r.terminal().Write([]byte(endSyncOutput)); err == nil {
+			err = endErr
+		}
+	}
+	return err
 }
 
 // Resize changes the renderer dimensions.
 func (r *Renderer) Resize(width, height uint32) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	if width == 0 || height == 0 {
-		return newError("invalid dimensions")
+	if err := checkDimensions(width, height); err != nil {
+		return err
+	}
+	if err := checkNative(func() { C.resizeRenderer(r.ptr, C.uint32_t(width), C.uint32_t(height)) }); err != nil {
+		return err
+	}
+	r.width = width
+	r.height = height
+	if r.links != nil {
+		r.links.reset()
+		r.renderedLinks.reset()
 	}
-	C.resizeRenderer(r.ptr, C.uint32_t(width), C.uint32_t(height))
 	return nil
 }
 
@@ -155,25 +450,34 @@ func (r *Renderer) Resize(width, height uint32) error {
 // If enableMovement is true, also tracks mouse movement events.
 func (r *Renderer) EnableMouse(enableMovement bool) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
 	}
-	C.enableMouse(r.ptr, C.bool(enableMovement))
+	if err := r.native(func() { C.enableMouse(r.ptr, C.bool(enableMovement)) }); err != nil {
+		return err
+	}
+	r.mouseEnabled, r.mouseMovement = true, enableMovement
 	return nil
 }
 
 // DisableMouse disables mouse tracking.
 func (r *Renderer) DisableMouse() error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
+	}
+	if err := r.native(func() { C.disableMouse(r.ptr) }); err != nil {
+		return err
 	}
-	C.disableMouse(r.ptr)
+	r.mouseEnabled = false
 	return nil
 }
 
 // SetDebugOverlay enables or disables the debug overlay.
 func (r *Renderer) SetDebugOverlay(enabled bool, corner DebugOverlayCorner) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.setDebugOverlay(r.ptr, C.bool(enabled), C.uint8_t(corner))
 	return nil
@@ -182,45 +486,168 @@ func (r *Renderer) SetDebugOverlay(enabled bool, corner DebugOverlayCorner) erro
 // ClearTerminal clears the terminal screen.
 func (r *Renderer) ClearTerminal() error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	C.clearTerminal(r.ptr)
-	return nil
+	return r.native(func() { C.clearTerminal(r.ptr) })
 }
 
+// hitIDOffset is added to region IDs before they are handed to the native hit grid.
+// The native layer uses 0 to mean "no hit", so shifting IDs keeps 0 usable as a region ID.
+const hitIDOffset = 1
+
 // AddToHitGrid adds a rectangular area to the mouse hit testing grid.
 // When the mouse is clicked in this area, the specified ID will be returned.
+// Any ID except math.MaxUint32 may be used, including 0.
 func (r *Renderer) AddToHitGrid(x, y int32, width, height, id uint32) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
+	}
+	if id > math.MaxUint32-hitIDOffset {
+		return newError("hit grid id out of range")
 	}
-	C.addToHitGrid(r.ptr, C.int32_t(x), C.int32_t(y), C.uint32_t(width), C.uint32_t(height), C.uint32_t(id))
+	C.addToHitGrid(r.ptr, C.int32_t(x), C.int32_t(y), C.uint32_t(width), C.uint32_t(height), C.uint32_t(id+hitIDOffset))
 	return nil
 }
 
+// HitTest performs a hit test at the specified coordinates.
+// Found is false if no area was registered at the coordinates or they lie outside the renderer.
+func (r *Renderer) HitTest(x, y uint32) (HitTestResult, error) {
+	if r.ptr == nil {
+		return HitTestResult{}, closedError("renderer")
+	}
+	if x >= r.width || y >= r.height {
+		return HitTestResult{}, nil
+	}
+	id := uint32(C.checkHit(r.ptr, C.uint32_t(x), C.uint32_t(y)))
+	if id == 0 {
+		return HitTestResult{}, nil
+	}
+	return HitTestResult{ID: id - hitIDOffset, Found: true}, nil
+}
+
 // CheckHit performs a hit test at the specified coordinates.
 // Returns the ID of the hit area, or 0 if no hit was found.
+//
+// Deprecated: CheckHit cannot distinguish a miss from a hit on ID 0. Use HitTest instead.
 func (r *Renderer) CheckHit(x, y uint32) (uint32, error) {
+	result, err := r.HitTest(x, y)
+	if err != nil {
+		return 0, err
+	}
+	return result.ID, nil
+}
+
+// HitGridSnapshot returns the current contents of the hit testing grid.
+// Adjacent cells sharing an ID are merged into rectangles, so each registered
+// area is usually reported as a single region. Cells without a hit area are omitted.
+func (r *Renderer) HitGridSnapshot() ([]HitRegion, error) {
 	if r.ptr == nil {
-		return 0, newError("renderer is closed")
+		return nil, closedError("renderer")
+	}
+	
+	type runKey struct {
+		x, width int32
+		id       uint32
 	}
-	id := C.checkHit(r.ptr, C.uint32_t(x), C.uint32_t(y))
-	return uint32(id), nil
+	
+	regions := []HitRegion{}
+	open := make(map[runKey]int)
+	cells := r.hitGridCells()
+	for y := int32(0); y < int32(r.height); y++ {
+		row := cells[uint32(y)*r.width : uint32(y+1)*r.width]
+		next := make(map[runKey]int)
+		for x := int32(0); x < int32(r.width); {
+			raw := row[x]
+			start := x
+			for x < int32(r.width) && row[x] == raw {
+				x++
+			}
+			if raw == 0 {
+				continue
+			}
+			
+			key := runKey{x: start, width: x - start, id: raw - hitIDOffset}
+			if i, ok := open[key]; ok {
+				regions[i].Height++
+				next[key] = i
+				continue
+			}
+			regions = append(regions, HitRegion{
+				Rect: Rect{Position{X: start, Y: y}, Size{Width: uint32(key.width), Height: 1}},
+				ID:   key.id,
+			})
+			next[key] = len(regions) - 1
+		}
+		open = next
+	}
+	
+	return regions, nil
+}
+
+// hitGridCells reads the raw native hit grid value of every cell in row-major order.
+func (r *Renderer) hitGridCells() []uint32 {
+	cells := make([]uint32, r.width*r.height)
+	for y := uint32(0); y < r.height; y++ {
+		for x := uint32(0); x < r.width; x++ {
+			cells[y*r.width+x] = uint32(C.checkHit(r.ptr, C.uint32_t(x), C.uint32_t(y)))
+		}
+	}
+	return cells
+}
+
+// terminal returns the writer for escape sequences that bypass the native renderer.
+func (r *Renderer) terminal() io.Writer {
+	if r.headless {
+		return io.Discard
+	}
+	if r.output != nil {
+		return countingWriter{r.output, &r.written}
+	}
+	if r.inline != nil && r.inline.stdout != nil {
+		return countingWriter{r.inline.stdout, &r.written}
+	}
+	return countingWriter{os.Stdout, &r.written}
+}
+
+// SetDebugOutput sets the writer that debug dumps such as DumpHitGrid are written to.
+// Passing nil restores the default of os.Stdout.
+func (r *Renderer) SetDebugOutput(w io.Writer) {
+	r.debugOutput = w
 }
 
 // DumpHitGrid outputs debug information about the hit testing grid.
+// Each cell is written as '.' when empty or the last digit of its ID otherwise.
+// Output goes to the writer configured with SetDebugOutput.
 func (r *Renderer) DumpHitGrid() error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	C.dumpHitGrid(r.ptr)
-	return nil
+	
+	out := r.debugOutput
+	if out == nil {
+		out = os.Stdout
+	}
+	
+	w := bufio.NewWriter(out)
+	cells := r.hitGridCells()
+	for y := uint32(0); y < r.height; y++ {
+		for x := uint32(0); x < r.width; x++ {
+			raw := cells[y*r.width+x]
+			if raw == 0 {
+				w.WriteByte('.')
+			} else {
+				w.WriteByte('0' + byte((raw-hitIDOffset)%10))
+			}
+		}
+		w.WriteByte('\n')
+	}
+	return w.Flush()
 }
 
 // DumpBuffers outputs debug information about the renderer buffers.
 func (r *Renderer) DumpBuffers(timestamp int64) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.dumpBuffers(r.ptr, C.int64_t(timestamp))
 	return nil
@@ -229,7 +656,7 @@ func (r *Renderer) DumpBuffers(timestamp int64) error {
 // DumpStdoutBuffer outputs debug information about the stdout buffer.
 func (r *Renderer) DumpStdoutBuffer(timestamp int64) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.dumpStdoutBuffer(r.ptr, C.int64_t(timestamp))
 	return nil
@@ -238,24 +665,34 @@ func (r *Renderer) DumpStdoutBuffer(timestamp int64) error {
 // GetTerminalCapabilities returns the current terminal capabilities.
 func (r *Renderer) GetTerminalCapabilities() (*Capabilities, error) {
 	if r.ptr == nil {
-		return nil, newError("renderer is closed")
+		return nil, closedError("renderer")
 	}
 	
 	var caps C.Capabilities
 	C.getTerminalCapabilities(r.ptr, &caps)
 	
 	return &Capabilities{
-		SupportsTruecolor:       bool(caps.supports_truecolor),
+		SupportsTruecolor:       bool(caps.supports_truecolor) || r.detected.truecolor,
 		SupportsMouse:          bool(caps.supports_mouse),
-		SupportsKittyKeyboard:  bool(caps.supports_kitty_keyboard),
+		SupportsKittyKeyboard:  bool(caps.supports_kitty_keyboard) || r.detected.kitty,
 		SupportsAlternateScreen: bool(caps.supports_alternate_screen),
+		SupportsUnicode:         detectUnicodeSupport(),
+		SupportsSixel:           r.detected.sixel,
+		SupportsITerm2Images:    r.detected.iterm2 || detectITerm2Support(),
+		SupportsHyperlinks:      r.hyperlinksSupported(),
+		SupportsSyncOutput:      r.detected.syncOutput,
+		SupportsClipboard:       r.clipboardSupported(),
+		KittyKeyboardFlags:      r.detected.kittyFlags,
+		SupportsMousePixels:     r.detected.mousePixels,
 	}, nil
 }
 
-// ProcessCapabilityResponse processes a terminal capability response.
+// ProcessCapabilityResponse processes a terminal capability response. Replies to
+// DetectCapabilities, QueryBackgroundColor, ReadClipboard and QueryCursorPosition are
+// picked out of it as well.
 func (r *Renderer) ProcessCapabilityResponse(response []byte) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	if len(response) == 0 {
 		return nil
@@ -263,62 +700,112 @@ func (r *Renderer) ProcessCapabilityResponse(response []byte) error {
 	
 	responsePtr, responseLen := sliceToC(response)
 	C.processCapabilityResponse(r.ptr, (*C.uint8_t)(responsePtr), C.size_t(responseLen))
+	r.detected.parseCapabilityResponse(response)
+	if color, ok := backgroundColorReply(response); ok {
+		r.background.report(color)
+	}
+	r.clipboardRead.receive(response)
+	r.cursor.receive(response)
+	r.kittyQuery.receive(response)
+	r.detection.receive(response)
 	return nil
 }
 
 // EnableKittyKeyboard enables the Kitty keyboard protocol with the specified flags.
 func (r *Renderer) EnableKittyKeyboard(flags uint8) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
+	}
+	if err := r.native(func() { C.enableKittyKeyboard(r.ptr, C.uint8_t(flags)) }); err != nil {
+		return err
 	}
-	C.enableKittyKeyboard(r.ptr, C.uint8_t(flags))
+	r.kittyEnabled, r.kittyFlags = true, flags
 	return nil
 }
 
 // DisableKittyKeyboard disables the Kitty keyboard protocol.
 func (r *Renderer) DisableKittyKeyboard() error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	C.disableKittyKeyboard(r.ptr)
+	if err := r.native(func() { C.disableKittyKeyboard(r.ptr) }); err != nil {
+		return err
+	}
+	r.kittyEnabled = false
 	return nil
 }
 
-// SetupTerminal sets up the terminal with optional alternate screen buffer.
+// SetupTerminal sets up the terminal with optional alternate screen buffer and
+// queries its capabilities. The replies are read from the renderer's Input if set,
+// and have to be passed to ProcessCapabilityResponse otherwise.
 func (r *Renderer) SetupTerminal(useAlternateScreen bool) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
+	}
+	if r.inline != nil && useAlternateScreen {
+		return newError("inline renderer can't use the alternate screen")
+	}
+	var inlineTop uint32
+	if r.inline != nil {
+		r.readReplies()
+		inlineTop = r.inlineTop()
+	}
+	if err := r.native(func() { C.setupTerminal(r.ptr, C.bool(useAlternateScreen)) }); err != nil {
+		return err
+	}
+	r.altScreen = useAlternateScreen
+	r.terminalSetup = true
+	r.readReplies()
+	if r.inline != nil {
+		r.setInlineTop(inlineTop)
+		r.forceRender = true
+		if r.inline.captureStdout && r.inline.pipe == nil {
+			if err := r.interceptStdout(); err != nil {
+				return err
+			}
+		}
+	}
+	if r.handleSuspend {
+		r.watchSuspend()
 	}
-	C.setupTerminal(r.ptr, C.bool(useAlternateScreen))
 	return nil
 }
 
 // SetCursorPosition sets the cursor position and visibility.
 func (r *Renderer) SetCursorPosition(x, y int32, visible bool) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	C.setCursorPosition(r.ptr, C.int32_t(x), C.int32_t(y), C.bool(visible))
 	return nil
 }
 
-// SetCursorStyle sets the cursor style and blinking state.
+// SetCursorStyle sets the cursor style and blinking state, as SetCursorShape does
+// with the matching CursorShape. Styles other than CursorBlock, CursorUnderline and
+// CursorBar are an error.
 func (r *Renderer) SetCursorStyle(style CursorStyle, blinking bool) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	cStyle := C.CString(string(style))
-	defer C.free(unsafe.Pointer(cStyle))
-	C.setCursorStyle(r.ptr, (*C.uint8_t)(unsafe.Pointer(cStyle)), C.size_t(len(style)), C.bool(blinking))
-	return nil
+	shape, err := style.Shape(blinking)
+	if err != nil {
+		return err
+	}
+	return r.SetCursorShape(shape)
 }
 
 // SetCursorColor sets the cursor color.
 func (r *Renderer) SetCursorColor(color RGBA) error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
-	C.setCursorColor(r.ptr, color.toCFloat())
+	C.setCursorColorValue(r.ptr, color.toC())
 	return nil
 }
 
@@ -330,7 +817,7 @@ func (r *Renderer) Valid() bool {
 // ensureRenderer is a helper that checks if renderer is valid
 func (r *Renderer) ensureValid() error {
 	if r.ptr == nil {
-		return newError("renderer is closed")
+		return closedError("renderer")
 	}
 	return nil
 }
\ No newline at end of file
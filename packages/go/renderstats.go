@@ -0,0 +1,75 @@
+package opentui
+
+import (
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// renderColorEpsilon is the largest color channel difference the native renderer
+// treats as unchanged.
+const renderColorEpsilon = 0.00001
+
+// RenderWithStats renders like Render and reports what the frame changed. The
+// changed cells are counted by comparing the next buffer with the current one before
+// rendering. BytesWritten includes the frame written by the native renderer only for
+// renderers with their own Output; on stdout it counts just the sequences written from
+// Go, such as hyperlinks, images and synchronized output markers.
+func (r *Renderer) RenderWithStats(force bool) (RenderStats, error) {
+	var stats RenderStats
+	start := time.Now()
+	written := r.written.Load()
+	err := r.render(force, &stats)
+	stats.BytesWritten = uint32(r.written.Load() - written)
+	stats.Duration = time.Since(start)
+	return stats, err
+}
+
+// changedCells counts the cells of the next buffer that differ from the current
+// buffer the way the native renderer compares them, or returns the number of cells if all of them are redrawn.
+func (r *Renderer) changedCells(all bool) uint32 {
+	if all {
+		return r.width * r.height
+	}
+	next, err := r.GetNextBuffer()
+	if err != nil {
+		return 0
+	}
+	current, err := r.GetCurrentBuffer()
+	if err != nil {
+		return 0
+	}
+	to, err := next.GetDirectAccess()
+	if err != nil {
+		return 0
+	}
+	from, err := current.GetDirectAccess()
+	if err != nil {
+		return 0
+	}
+	if from.Width != to.Width || from.Height != to.Height {
+		return to.Width * to.Height
+	}
+
+	var changed uint32
+	for i := range to.Chars {
+		if from.Chars[i] != to.Chars[i] || from.Attributes[i] != to.Attributes[i] ||
+			!colorsEqual(from.Foreground[i], to.Foreground[i], renderColorEpsilon) ||
+			!colorsEqual(from.Background[i], to.Background[i], renderColorEpsilon) {
+			changed++
+		}
+	}
+	return changed
+}
+
+// countingWriter adds the number of bytes written through it to n.
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Uint64
+}
+
+func (c countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(uint64(n))
+	return n, err
+}
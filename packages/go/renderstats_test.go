@@ -0,0 +1,59 @@
+package opentui
+
+import (
+	"bytes"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCountingWriter(t *testing.T) {
+	var out bytes.Buffer
+	var n atomic.Uint64
+	w := countingWriter{&out, &n}
+	w.Write([]byte("abc"))
+	w.Write([]byte("\x1b[0m"))
+	if n.Load() != 7 || out.String() != "abc\x1b[0m" {
+		t.Errorf("counted %d bytes, wrote %q", n.Load(), out.String())
+	}
+}
+
+func TestRenderWithStats(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 6, Height: 2, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	draw := func(text string) {
+		next, err := renderer.GetNextBuffer()
+		if err != nil {
+			t.Fatalf("GetNextBuffer failed: %v", err)
+		}
+		next.Clear(Black)
+		next.DrawText(text, 0, 0, White, nil, 0)
+	}
+
+	draw("abc")
+	stats, err := renderer.RenderWithStats(true)
+	if err != nil {
+		t.Fatalf("RenderWithStats failed: %v", err)
+	}
+	if !stats.FullRedraw || stats.CellsChanged != 12 {
+		t.Errorf("forced frame: %+v", stats)
+	}
+	if stats.BytesWritten == 0 || int(stats.BytesWritten) != out.Len() {
+		t.Errorf("BytesWritten = %d, output has %d bytes", stats.BytesWritten, out.Len())
+	}
+
+	draw("abc")
+	if stats, _ = renderer.RenderWithStats(false); stats.FullRedraw || stats.CellsChanged != 0 {
+		t.Errorf("unchanged frame: %+v", stats)
+	}
+
+	draw("abd")
+	if stats, _ = renderer.RenderWithStats(false); stats.CellsChanged != 1 {
+		t.Errorf("frame changing one cell: %+v", stats)
+	}
+}
@@ -0,0 +1,118 @@
+package opentui
+
+import "sync"
+
+// resizeWatch tracks terminal size changes between frames. Size changes are noticed
+// on a watcher goroutine but only applied by Render, so they never race a frame.
+type resizeWatch struct {
+	mu        sync.Mutex
+	pending   *Size // size to apply on the next Render, nil if unchanged
+	callbacks []resizeCallback
+	nextID    uint64
+	stop      func() // stops the watcher, nil when not watching
+
+	source func() (uint32, uint32, error) // reports the terminal size, that of the output by default
+}
+
+type resizeCallback struct {
+	id uint64
+	fn func(width, height uint32)
+}
+
+// WatchResize starts watching the terminal for size changes, on SIGWINCH where the
+// platform has it and by polling otherwise. A change is applied at the start of the
+// next Render: the renderer is resized, which also resizes the buffers returned by
+// GetNextBuffer and GetCurrentBuffer, and the OnResize callbacks run before the
+// frame is drawn. Calling WatchResize again has no effect.
+func (r *Renderer) WatchResize() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.resize.mu.Lock()
+	defer r.resize.mu.Unlock()
+	if r.resize.stop == nil {
+		r.resize.stop = watchResizeEvents(r.checkSize)
+	}
+	return nil
+}
+
+// StopWatchingResize stops watching the terminal for size changes. A change noticed
+// before is still applied on the next Render.
+func (r *Renderer) StopWatchingResize() {
+	r.resize.mu.Lock()
+	stop := r.resize.stop
+	r.resize.stop = nil
+	r.resize.mu.Unlock()
+	if stop != nil {
+		stop()
+	}
+}
+
+// OnResize registers a callback invoked with the new size after the renderer was
+// resized to follow the terminal. Callbacks run in registration order on the
+// goroutine calling Render, before the frame is drawn, so they may lay out and draw
+// into the next buffer. Resizes are only noticed after WatchResize. The returned
+// function removes the callback.
+func (r *Renderer) OnResize(fn func(width, height uint32)) (unsubscribe func()) {
+	r.resize.mu.Lock()
+	defer r.resize.mu.Unlock()
+	r.resize.nextID++
+	id := r.resize.nextID
+	r.resize.callbacks = append(r.resize.callbacks, resizeCallback{id: id, fn: fn})
+	return func() {
+		r.resize.mu.Lock()
+		defer r.resize.mu.Unlock()
+		for i, c := range r.resize.callbacks {
+			if c.id == id {
+				r.resize.callbacks = append(r.resize.callbacks[:i:i], r.resize.callbacks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// checkSize reads the terminal size and records it for the next Render if it differs
+// from the size of the renderer. It runs on the watcher goroutine.
+func (r *Renderer) checkSize() {
+	r.resize.mu.Lock()
+	source := r.resize.source
+	r.resize.mu.Unlock()
+	if source == nil {
+		output := r.outputFile()
+		source = func() (uint32, uint32, error) { return terminalSize(output) }
+	}
+	width, height, err := source()
+	if err != nil || width == 0 || height == 0 {
+		return
+	}
+	r.resize.mu.Lock()
+	r.resize.pending = &Size{Width: width, Height: height}
+	r.resize.mu.Unlock()
+}
+
+// applyPendingResize resizes the renderer to the last size noticed by the watcher
+// and runs the callbacks. Render calls it before drawing a frame.
+func (r *Renderer) applyPendingResize() error {
+	r.resize.mu.Lock()
+	pending := r.resize.pending
+	r.resize.pending = nil
+	callbacks := append([]resizeCallback(nil), r.resize.callbacks...)
+	r.resize.mu.Unlock()
+
+	if pending != nil && r.inline != nil {
+		return r.applyInlineResize(*pending, callbacks)
+	}
+	if pending != nil && r.split != nil {
+		return r.applySplitResize(*pending, callbacks)
+	}
+	if pending == nil || (pending.Width == r.width && pending.Height == r.height) {
+		return nil
+	}
+	if err := r.Resize(pending.Width, pending.Height); err != nil {
+		return err
+	}
+	for _, c := range callbacks {
+		c.fn(pending.Width, pending.Height)
+	}
+	return nil
+}
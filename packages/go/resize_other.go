@@ -0,0 +1,28 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package opentui
+
+import "time"
+
+// resizePollInterval is how often the terminal size is checked without SIGWINCH
+const resizePollInterval = 250 * time.Millisecond
+
+// watchResizeEvents calls notify periodically until the returned function is called.
+func watchResizeEvents(notify func()) (stop func()) {
+	ticker := time.NewTicker(resizePollInterval)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				notify()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -0,0 +1,83 @@
+package opentui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOnResize(t *testing.T) {
+	renderer := NewRenderer(20, 5)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	width, height := uint32(30), uint32(8)
+	renderer.resize.source = func() (uint32, uint32, error) { return width, height, nil }
+
+	var calls []string
+	var sizes []Size
+	renderer.OnResize(func(w, h uint32) {
+		calls = append(calls, "first")
+		next, err := renderer.GetNextBuffer()
+		if err != nil {
+			t.Errorf("GetNextBuffer failed: %v", err)
+			return
+		}
+		bw, bh, _ := next.Size()
+		sizes = append(sizes, Size{Width: bw, Height: bh})
+	})
+	unsubscribe := renderer.OnResize(func(w, h uint32) { calls = append(calls, "second") })
+	renderer.OnResize(func(w, h uint32) { calls = append(calls, "third") })
+
+	// Nothing happens until Render applies the size noticed by the watcher
+	renderer.checkSize()
+	if len(calls) != 0 {
+		t.Fatal("callbacks ran before Render")
+	}
+	captureStdout(t, func() { renderer.Render(false) })
+	if want := []string{"first", "second", "third"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("callbacks ran as %v, want %v", calls, want)
+	}
+	if len(sizes) != 1 || sizes[0] != (Size{Width: 30, Height: 8}) {
+		t.Errorf("next buffer during the callback had size %v, want 30x8", sizes)
+	}
+
+	// An unchanged size does not resize again, and unsubscribed callbacks stay quiet
+	calls = nil
+	unsubscribe()
+	renderer.checkSize()
+	captureStdout(t, func() { renderer.Render(false) })
+	if len(calls) != 0 {
+		t.Errorf("callbacks ran without a size change: %v", calls)
+	}
+	width = 25
+	renderer.checkSize()
+	captureStdout(t, func() { renderer.Render(false) })
+	if want := []string{"first", "third"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("callbacks after unsubscribing ran as %v, want %v", calls, want)
+	}
+	if len(sizes) != 2 || sizes[1] != (Size{Width: 25, Height: 8}) {
+		t.Errorf("next buffer sizes during the callbacks were %v, want 30x8 then 25x8", sizes)
+	}
+}
+
+func TestWatchResize(t *testing.T) {
+	renderer := NewRenderer(20, 5)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	if err := renderer.WatchResize(); err != nil {
+		t.Fatalf("WatchResize failed: %v", err)
+	}
+	if err := renderer.WatchResize(); err != nil {
+		t.Fatalf("second WatchResize failed: %v", err)
+	}
+	renderer.Close()
+	if renderer.resize.stop != nil {
+		t.Error("Close should stop watching")
+	}
+	if err := renderer.WatchResize(); err == nil {
+		t.Error("WatchResize should fail on a closed renderer")
+	}
+}
@@ -0,0 +1,61 @@
+package opentui
+
+// Alternate screen sequences. Mode 1049 saves the cursor on entry, clears the
+// alternate screen and restores the cursor when switching back.
+const (
+	enterAltScreen = "\x1b[?1049h"
+	leaveAltScreen = "\x1b[?1049l"
+)
+
+// EnterAlternateScreen switches the terminal to the alternate screen buffer,
+// saving the cursor. The alternate screen starts out blank, so the next Render
+// redraws every cell. Does nothing if the alternate screen is already active.
+func (r *Renderer) EnterAlternateScreen() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
+	}
+	if r.altScreen {
+		return nil
+	}
+	if _, err := r.terminal().Write([]byte(enterAltScreen)); err != nil {
+		return err
+	}
+	r.altScreen = true
+	r.forceRender = true
+	return nil
+}
+
+// LeaveAlternateScreen switches the terminal back to the main screen, showing its
+// scrollback, and restores the cursor saved by EnterAlternateScreen. Does nothing if
+// the alternate screen is not active.
+func (r *Renderer) LeaveAlternateScreen() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if !r.altScreen {
+		return nil
+	}
+	if _, err := r.terminal().Write([]byte(leaveAltScreen)); err != nil {
+		return err
+	}
+	r.altScreen = false
+	return nil
+}
+
+// InAlternateScreen reports whether the alternate screen buffer is active, as set up
+// by SetupTerminal or switched with EnterAlternateScreen and LeaveAlternateScreen.
+func (r *Renderer) InAlternateScreen() bool {
+	return r.altScreen
+}
+
+// leaveAltScreenOnClose returns the terminal to the main screen after the renderer
+// was destroyed, whichever screen is active.
+func (r *Renderer) leaveAltScreenOnClose() {
+	if r.altScreen {
+		r.terminal().Write([]byte(leaveAltScreen))
+		r.altScreen = false
+	}
+}
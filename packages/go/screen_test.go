@@ -0,0 +1,47 @@
+package opentui
+
+import "testing"
+
+func TestAlternateScreen(t *testing.T) {
+	renderer := NewRenderer(4, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	out := captureStdout(t, func() {
+		renderer.EnterAlternateScreen()
+		renderer.EnterAlternateScreen()
+	})
+	if out != enterAltScreen {
+		t.Errorf("entering twice wrote %q, want a single %q", out, enterAltScreen)
+	}
+	if !renderer.InAlternateScreen() || !renderer.forceRender {
+		t.Error("entering should track the state and force a full render")
+	}
+
+	out = captureStdout(t, func() {
+		renderer.LeaveAlternateScreen()
+		renderer.LeaveAlternateScreen()
+	})
+	if out != leaveAltScreen || renderer.InAlternateScreen() {
+		t.Errorf("leaving twice wrote %q, want a single %q", out, leaveAltScreen)
+	}
+}
+
+func TestCloseLeavesAlternateScreen(t *testing.T) {
+	renderer := NewRenderer(4, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	out := captureStdout(t, func() {
+		renderer.EnterAlternateScreen()
+		renderer.Close()
+	})
+	if out != enterAltScreen+leaveAltScreen {
+		t.Errorf("Close in the alternate screen wrote %q", out)
+	}
+	if err := renderer.EnterAlternateScreen(); err == nil {
+		t.Error("EnterAlternateScreen should fail on a closed renderer")
+	}
+}
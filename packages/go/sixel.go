@@ -0,0 +1,352 @@
+package opentui
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Cell size in pixels assumed when the terminal doesn't report one
+const (
+	defaultCellWidth  = 10
+	defaultCellHeight = 20
+)
+
+// SixelOptions controls how DrawSixel encodes and places an image
+type SixelOptions struct {
+	CellWidth  uint32 // Width of a terminal cell in pixels, defaults to 10
+	CellHeight uint32 // Height of a terminal cell in pixels, defaults to 20
+	MaxColors  int    // Palette size between 2 and 256, defaults to 256
+}
+
+// terminalImage is an image drawn with a terminal graphics protocol on top of the cells
+type terminalImage struct {
+	rect     Rect   // Covered cells
+	sequence []byte // Escape sequence that draws the image at the cursor
+}
+
+// DrawSixel draws an image at cell (x, y) using sixel graphics.
+// The image is quantized to at most opts.MaxColors colors and clipped at the right and
+// bottom edges of the screen. Transparent pixels leave the cells below visible.
+//
+// The image stays on screen until ClearImages is called: whenever a render changes
+// any cell below the image, the image is drawn again on top.
+// Use GetTerminalCapabilities to check SupportsSixel before calling this.
+func (r *Renderer) DrawSixel(x, y uint32, img image.Image, opts SixelOptions) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if img == nil {
+		return newError("image is nil")
+	}
+	if x >= r.width || y >= r.height {
+		return nil
+	}
+
+	if opts.CellWidth == 0 {
+		opts.CellWidth = defaultCellWidth
+	}
+	if opts.CellHeight == 0 {
+		opts.CellHeight = defaultCellHeight
+	}
+	if opts.MaxColors <= 0 || opts.MaxColors > 256 {
+		opts.MaxColors = 256
+	}
+	if opts.MaxColors < 2 {
+		opts.MaxColors = 2
+	}
+
+	// Clip to the screen
+	bounds := img.Bounds()
+	maxWidth := int((r.width - x) * opts.CellWidth)
+	maxHeight := int((r.height - y) * opts.CellHeight)
+	if bounds.Dx() > maxWidth {
+		bounds.Max.X = bounds.Min.X + maxWidth
+	}
+	if bounds.Dy() > maxHeight {
+		bounds.Max.Y = bounds.Min.Y + maxHeight
+	}
+	if bounds.Empty() {
+		return nil
+	}
+
+	var seq bytes.Buffer
+	encodeSixel(&seq, img, bounds, opts.MaxColors)
+
+	rect := Rect{
+		Position: Position{X: int32(x), Y: int32(y)},
+		Size: Size{
+			Width:  (uint32(bounds.Dx()) + opts.CellWidth - 1) / opts.CellWidth,
+			Height: (uint32(bounds.Dy()) + opts.CellHeight - 1) / opts.CellHeight,
+		},
+	}
+	return r.addImage(terminalImage{rect: rect, sequence: seq.Bytes()})
+}
+
+// ClearImages forgets all images drawn with a terminal graphics protocol.
+// They remain visible until the cells below them are rendered again,
+// so call Render(true) to remove them from the screen right away.
+func (r *Renderer) ClearImages() {
+	r.images = nil
+}
+
+// addImage draws a terminal image and remembers it for redrawing.
+// An image covering exactly the same cells as an earlier one replaces it.
+func (r *Renderer) addImage(img terminalImage) error {
+	images := r.images[:0]
+	for _, existing := range r.images {
+		if existing.rect != img.rect {
+			images = append(images, existing)
+		}
+	}
+	r.images = append(images, img)
+	return r.writeImage(img)
+}
+
+// emitImages draws all remembered terminal images.
+func (r *Renderer) emitImages() error {
+	for _, img := range r.images {
+		if err := r.writeImage(img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeImage draws a terminal image at its cells, below the rows above the frame as
+// they are now: the frame of an inline renderer moves as the terminal scrolls.
+func (r *Renderer) writeImage(img terminalImage) error {
+	var seq bytes.Buffer
+	fmt.Fprintf(&seq, "\x1b7\x1b[%d;%dH", int64(img.rect.Y)+int64(r.renderOffset)+1, img.rect.X+1)
+	seq.Write(img.sequence)
+	seq.WriteString("\x1b8")
+	_, err := r.terminal().Write(seq.Bytes())
+	return err
+}
+
+// imagesDamaged reports whether the next render changes any cell covered by a terminal image.
+func (r *Renderer) imagesDamaged() bool {
+	next, err := r.GetNextBuffer()
+	if err != nil {
+		return true
+	}
+	current, err := r.GetCurrentBuffer()
+	if err != nil {
+		return true
+	}
+	nextCells, err := next.GetDirectAccess()
+	if err != nil {
+		return true
+	}
+	currentCells, err := current.GetDirectAccess()
+	if err != nil || currentCells.Width != nextCells.Width || currentCells.Height != nextCells.Height {
+		return true
+	}
+
+	for _, img := range r.images {
+		for y := int64(img.rect.Y); y < int64(img.rect.Y)+int64(img.rect.Height); y++ {
+			for x := int64(img.rect.X); x < int64(img.rect.X)+int64(img.rect.Width); x++ {
+				if x < 0 || y < 0 || x >= int64(nextCells.Width) || y >= int64(nextCells.Height) {
+					continue
+				}
+				i := uint32(y)*nextCells.Width + uint32(x)
+				if nextCells.Chars[i] != currentCells.Chars[i] ||
+					nextCells.Foreground[i] != currentCells.Foreground[i] ||
+					nextCells.Background[i] != currentCells.Background[i] ||
+					nextCells.Attributes[i] != currentCells.Attributes[i] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// encodeSixel writes the pixels of img inside bounds as a sixel DCS sequence.
+func encodeSixel(out *bytes.Buffer, img image.Image, bounds image.Rectangle, maxColors int) {
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Collect the opaque colors and map them onto a palette
+	pixels := make([]int, width*height)
+	histogram := make(map[color.RGBA]int)
+	colors := make([]color.RGBA, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			i := y*width + x
+			if c.A < pixelAlphaThreshold {
+				pixels[i] = -1
+				continue
+			}
+			colors[i] = color.RGBA{R: c.R, G: c.G, B: c.B, A: 255}
+			histogram[colors[i]]++
+		}
+	}
+
+	palette := medianCut(histogram, maxColors)
+	lookup := make(map[color.RGBA]int, len(histogram))
+	for c := range histogram {
+		lookup[c] = nearestColor(palette, c)
+	}
+	for i, c := range colors {
+		if pixels[i] >= 0 {
+			pixels[i] = lookup[c]
+		}
+	}
+
+	// Transparent background (P2=1) and 1:1 pixel aspect ratio
+	fmt.Fprintf(out, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for i, c := range palette {
+		fmt.Fprintf(out, "#%d;2;%d;%d;%d", i, percent(c.R), percent(c.G), percent(c.B))
+	}
+
+	row := make([]byte, width)
+	for band := 0; band < height; band += 6 {
+		used := make([]bool, len(palette))
+		for y := band; y < band+6 && y < height; y++ {
+			for x := 0; x < width; x++ {
+				if p := pixels[y*width+x]; p >= 0 {
+					used[p] = true
+				}
+			}
+		}
+
+		for index, ok := range used {
+			if !ok {
+				continue
+			}
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if pixels[(band+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+			fmt.Fprintf(out, "#%d", index)
+			writeSixelRuns(out, row)
+			out.WriteByte('$')
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\")
+}
+
+// writeSixelRuns writes a row of sixel characters with run-length encoding.
+func writeSixelRuns(out *bytes.Buffer, row []byte) {
+	for i := 0; i < len(row); {
+		n := 1
+		for i+n < len(row) && row[i+n] == row[i] {
+			n++
+		}
+		if n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, row[i])
+		} else {
+			out.Write(bytes.Repeat(row[i:i+1], n))
+		}
+		i += n
+	}
+}
+
+// percent converts an 8-bit channel to the 0-100 range used by sixel palettes.
+func percent(c uint8) int {
+	return (int(c)*100 + 127) / 255
+}
+
+// medianCut reduces the colors of a histogram to a palette of at most maxColors
+// by repeatedly splitting the color box with the widest channel range at its weighted median.
+func medianCut(histogram map[color.RGBA]int, maxColors int) []color.RGBA {
+	type weighted struct {
+		c color.RGBA
+		n int
+	}
+	if len(histogram) == 0 {
+		return []color.RGBA{{A: 255}}
+	}
+
+	all := make([]weighted, 0, len(histogram))
+	for c, n := range histogram {
+		all = append(all, weighted{c, n})
+	}
+	// Map iteration order is random, keep the result deterministic
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i].c, all[j].c
+		return uint32(a.R)<<16|uint32(a.G)<<8|uint32(a.B) < uint32(b.R)<<16|uint32(b.G)<<8|uint32(b.B)
+	})
+
+	channel := func(c color.RGBA, ch int) uint8 {
+		return [3]uint8{c.R, c.G, c.B}[ch]
+	}
+	widest := func(box []weighted) (int, int) {
+		bestCh, bestRange := 0, -1
+		for ch := 0; ch < 3; ch++ {
+			lo, hi := 255, 0
+			for _, w := range box {
+				v := int(channel(w.c, ch))
+				lo, hi = min(lo, v), max(hi, v)
+			}
+			if hi-lo > bestRange {
+				bestCh, bestRange = ch, hi-lo
+			}
+		}
+		return bestCh, bestRange
+	}
+
+	boxes := [][]weighted{all}
+	for len(boxes) < maxColors {
+		split, splitCh, splitRange := -1, 0, 0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			if ch, r := widest(box); r > splitRange {
+				split, splitCh, splitRange = i, ch, r
+			}
+		}
+		if split < 0 {
+			break
+		}
+
+		box := boxes[split]
+		sort.SliceStable(box, func(i, j int) bool { return channel(box[i].c, splitCh) < channel(box[j].c, splitCh) })
+		total := 0
+		for _, w := range box {
+			total += w.n
+		}
+		cut, seen := 1, box[0].n
+		for cut < len(box)-1 && seen*2 < total {
+			seen += box[cut].n
+			cut++
+		}
+		boxes[split] = box[:cut]
+		boxes = append(boxes, box[cut:])
+	}
+
+	palette := make([]color.RGBA, len(boxes))
+	for i, box := range boxes {
+		var r, g, b, n int
+		for _, w := range box {
+			r += int(w.c.R) * w.n
+			g += int(w.c.G) * w.n
+			b += int(w.c.B) * w.n
+			n += w.n
+		}
+		palette[i] = color.RGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(b / n), A: 255}
+	}
+	return palette
+}
+
+// nearestColor returns the index of the palette entry closest to c.
+func nearestColor(palette []color.RGBA, c color.RGBA) int {
+	best, bestDist := 0, -1
+	for i, p := range palette {
+		dr, dg, db := int(p.R)-int(c.R), int(p.G)-int(c.G), int(p.B)-int(c.B)
+		if d := dr*dr + dg*dg + db*db; bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
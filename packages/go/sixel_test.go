@@ -0,0 +1,91 @@
+package opentui
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestEncodeSixel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 6))
+	for y := 0; y < 6; y++ {
+		img.Set(0, y, color.RGBA{R: 255, A: 255})
+	}
+
+	var out bytes.Buffer
+	encodeSixel(&out, img, img.Bounds(), 256)
+
+	// Red fills column 0 of the only band, column 1 is transparent
+	want := "\x1bP0;1;0q\"1;1;2;6#0;2;100;0;0#0~?$-\x1b\\"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEncodeSixelRunLength(t *testing.T) {
+	var out bytes.Buffer
+	writeSixelRuns(&out, []byte("~~~~~@@?"))
+	if got := out.String(); got != "!5~@@?" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestMedianCutLimitsPalette(t *testing.T) {
+	histogram := make(map[color.RGBA]int)
+	for i := 0; i < 1000; i++ {
+		histogram[color.RGBA{R: uint8(i), G: uint8(i / 4), B: uint8(i * 7), A: 255}]++
+	}
+
+	for _, max := range []int{2, 16, 256} {
+		if got := len(medianCut(histogram, max)); got > max || got < 2 {
+			t.Errorf("palette of %d colors for max %d", got, max)
+		}
+	}
+
+	// Few colors are kept exactly
+	small := map[color.RGBA]int{{R: 10, A: 255}: 3, {G: 20, A: 255}: 1}
+	palette := medianCut(small, 256)
+	if len(palette) != 2 {
+		t.Fatalf("got %d colors, want 2", len(palette))
+	}
+	for c := range small {
+		if palette[nearestColor(palette, c)] != c {
+			t.Errorf("color %v was not kept", c)
+		}
+	}
+}
+
+func TestDetectSixelSupport(t *testing.T) {
+	var d detectedCapabilities
+	d.parseCapabilityResponse([]byte("\x1b[?62;22c"))
+	if d.sixel {
+		t.Error("sixel detected without attribute 4")
+	}
+	d.parseCapabilityResponse([]byte("junk\x1b[?1;2c\x1b[?62;4;22c"))
+	if !d.sixel {
+		t.Error("sixel not detected from DA1 reply")
+	}
+}
+
+func TestDrawSixelClosedRenderer(t *testing.T) {
+	r := &Renderer{ptr: nil}
+	if err := r.DrawSixel(0, 0, image.NewRGBA(image.Rect(0, 0, 1, 1)), SixelOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "closed") {
+		t.Errorf("expected closed renderer error, got %v", err)
+	}
+}
+
+func TestEmitImagesRenderOffset(t *testing.T) {
+	var out bytes.Buffer
+	r := &Renderer{output: &out, renderOffset: 7}
+	r.images = []terminalImage{{rect: Rect{Position: Position{X: 2, Y: 1}, Size: Size{Width: 1, Height: 1}}, sequence: []byte("IMG")}}
+	if err := r.emitImages(); err != nil {
+		t.Fatal(err)
+	}
+	// Rows are counted from the top of the frame, wherever it is at the time
+	if got, want := out.String(), "\x1b7\x1b[9;3HIMG\x1b8"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,72 @@
+package opentui
+
+import (
+	"errors"
+	"os"
+	"strconv"
+)
+
+// Terminal size used when neither the terminal nor the environment reports one
+const (
+	defaultTerminalWidth  = 80
+	defaultTerminalHeight = 24
+)
+
+// ErrNotTerminal is returned by TerminalSize when stdout is not a terminal, so the
+// size of the output is undefined.
+var ErrNotTerminal = newError("stdout is not a terminal")
+
+// TerminalSize returns the size of the terminal stdout is attached to, in cells.
+// The size is read with the TIOCGWINSZ ioctl where available, then from the
+// COLUMNS and LINES environment variables, and defaults to 80x24. Returns
+// ErrNotTerminal when stdout is redirected.
+func TerminalSize() (width, height uint32, err error) {
+	return terminalSize(os.Stdout)
+}
+
+// terminalSize returns the size of the terminal f is attached to like TerminalSize.
+func terminalSize(f *os.File) (width, height uint32, err error) {
+	if !isTerminal(f) {
+		return 0, 0, ErrNotTerminal
+	}
+	if width, height, ok := windowSize(f); ok {
+		return width, height, nil
+	}
+	if width, height, ok := environmentSize(os.Getenv("COLUMNS"), os.Getenv("LINES")); ok {
+		return width, height, nil
+	}
+	return defaultTerminalWidth, defaultTerminalHeight, nil
+}
+
+// NewRendererAuto creates a renderer with the size of the terminal as reported by
+// TerminalSize. When stdout is not a terminal, the renderer is headless (see
+// Interactive) and sized from COLUMNS and LINES, or 80x24.
+func NewRendererAuto() (*Renderer, error) {
+	width, height, err := TerminalSize()
+	if errors.Is(err, ErrNotTerminal) && !outputIsTerminal(os.Stdout) {
+		width, height = headlessSize()
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r := NewRenderer(width, height)
+	if r == nil {
+		return nil, newError("failed to create renderer")
+	}
+	return r, nil
+}
+
+// environmentSize parses the COLUMNS and LINES values, which both have to be
+// positive numbers.
+func environmentSize(columns, lines string) (width, height uint32, ok bool) {
+	w, err := strconv.ParseUint(columns, 10, 16)
+	if err != nil || w == 0 {
+		return 0, 0, false
+	}
+	h, err := strconv.ParseUint(lines, 10, 16)
+	if err != nil || h == 0 {
+		return 0, 0, false
+	}
+	return uint32(w), uint32(h), true
+}
@@ -0,0 +1,16 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package opentui
+
+import "os"
+
+// windowSize is not implemented on this platform; TerminalSize falls back to the
+// environment.
+func windowSize(f *os.File) (width, height uint32, ok bool) {
+	return 0, 0, false
+}
+
+// cellPixels is not implemented on this platform; the default cell size is used.
+func cellPixels(f *os.File) (width, height uint32, ok bool) {
+	return 0, 0, false
+}
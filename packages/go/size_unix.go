@@ -0,0 +1,38 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package opentui
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+type winsize struct {
+	Row, Col, Xpixel, Ypixel uint16
+}
+
+// windowSize reads the size of the terminal f refers to with TIOCGWINSZ.
+func windowSize(f *os.File) (width, height uint32, ok bool) {
+	ws, ok := readWinsize(f)
+	if !ok || ws.Col == 0 || ws.Row == 0 {
+		return 0, 0, false
+	}
+	return uint32(ws.Col), uint32(ws.Row), true
+}
+
+// cellPixels reads the size of a cell in pixels of the terminal f refers to with
+// TIOCGWINSZ. Many terminals leave the pixel size at 0.
+func cellPixels(f *os.File) (width, height uint32, ok bool) {
+	ws, ok := readWinsize(f)
+	if !ok || ws.Col == 0 || ws.Row == 0 || ws.Xpixel < ws.Col || ws.Ypixel < ws.Row {
+		return 0, 0, false
+	}
+	return uint32(ws.Xpixel / ws.Col), uint32(ws.Ypixel / ws.Row), true
+}
+
+func readWinsize(f *os.File) (winsize, bool) {
+	var ws winsize
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	return ws, errno == 0
+}
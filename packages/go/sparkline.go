@@ -0,0 +1,100 @@
+package opentui
+
+import "math"
+
+// Bar characters for sparklines, from lowest to highest
+var sparklineBars = [8]rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// SparklineOptions controls the scaling and colors of a sparkline.
+// Bounds left nil are taken from the minimum and maximum of the drawn values.
+type SparklineOptions struct {
+	Min            *float64 // Value drawn as the lowest bar
+	Max            *float64 // Value drawn as the highest bar
+	Color          RGBA
+	Background     *RGBA    // nil keeps the existing background
+	Threshold      *float64 // Values above the threshold use ThresholdColor
+	ThresholdColor RGBA
+	MissingChar    rune // Drawn for NaN values, defaults to '·'
+	Window         bool // Show the last width values instead of averaging all of them
+}
+
+// DrawSparkline draws values as a row of bars with width cells starting at (x, y).
+// If there are more values than cells they are averaged into width buckets,
+// or with opts.Window only the most recent ones are shown. NaN values and
+// buckets without any numbers are drawn with opts.MissingChar, and cells
+// without values are left blank.
+func (b *Buffer) DrawSparkline(x, y uint32, width uint32, values []float64, opts SparklineOptions) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if opts.MissingChar == 0 {
+		opts.MissingChar = '·'
+	}
+
+	points := sparklinePoints(values, int(width), opts.Window)
+
+	// Scale to the given bounds or the range of the points
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range points {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	if opts.Min != nil {
+		lo = *opts.Min
+	}
+	if opts.Max != nil {
+		hi = *opts.Max
+	}
+
+	for i := uint32(0); i < width; i++ {
+		if int(i) >= len(points) {
+			b.setCell(x+i, y, ' ', opts.Color, opts.Background, 0)
+			continue
+		}
+		v := points[i]
+		if math.IsNaN(v) {
+			b.setCell(x+i, y, opts.MissingChar, opts.Color, opts.Background, 0)
+			continue
+		}
+
+		level := 0.5
+		if hi > lo {
+			level = math.Min(math.Max((v-lo)/(hi-lo), 0), 1)
+		}
+		color := opts.Color
+		if opts.Threshold != nil && v > *opts.Threshold {
+			color = opts.ThresholdColor
+		}
+		b.setCell(x+i, y, sparklineBars[int(math.Round(level*7))], color, opts.Background, 0)
+	}
+	return nil
+}
+
+// sparklinePoints reduces values to at most width points.
+func sparklinePoints(values []float64, width int, window bool) []float64 {
+	if len(values) <= width {
+		return values
+	}
+	if window {
+		return values[len(values)-width:]
+	}
+
+	// Average each bucket, ignoring NaN values
+	points := make([]float64, width)
+	for i := range points {
+		start, end := i*len(values)/width, (i+1)*len(values)/width
+		sum, n := 0.0, 0
+		for _, v := range values[start:end] {
+			if !math.IsNaN(v) {
+				sum += v
+				n++
+			}
+		}
+		points[i] = math.NaN()
+		if n > 0 {
+			points[i] = sum / float64(n)
+		}
+	}
+	return points
+}
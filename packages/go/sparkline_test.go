@@ -0,0 +1,59 @@
+package opentui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDrawSparkline(t *testing.T) {
+	buffer := newTestBuffer(t, 10, 1)
+	values := []float64{0, 1, 2, 3, 4, 5, 6, 7, math.NaN()}
+	if err := buffer.DrawSparkline(0, 0, 10, values, SparklineOptions{}); err != nil {
+		t.Fatalf("DrawSparkline failed: %v", err)
+	}
+	expectRows(t, buffer, "▁▂▃▄▅▆▇█· ")
+}
+
+func TestDrawSparklineBounds(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 2)
+	lo, hi := 0.0, 100.0
+	buffer.DrawSparkline(0, 0, 4, []float64{0, 50, 100, 200}, SparklineOptions{Min: &lo, Max: &hi})
+
+	// Equal values without bounds sit in the middle
+	buffer.DrawSparkline(0, 1, 4, []float64{3, 3}, SparklineOptions{})
+	expectRows(t, buffer, "▁▅██", "▅▅  ")
+}
+
+func TestDrawSparklineDownsampling(t *testing.T) {
+	values := []float64{0, 0, 7, 7, math.NaN(), math.NaN(), 3, 4}
+
+	buffer := newTestBuffer(t, 4, 2)
+	buffer.DrawSparkline(0, 0, 4, values, SparklineOptions{})
+	buffer.DrawSparkline(0, 1, 4, values, SparklineOptions{Window: true, MissingChar: '?'})
+	expectRows(t, buffer, "▁█·▅", "??▁█")
+}
+
+func TestDrawSparklineThreshold(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 1)
+	limit := 5.0
+	buffer.DrawSparkline(0, 0, 3, []float64{1, 5, 9}, SparklineOptions{
+		Color:          Green,
+		Threshold:      &limit,
+		ThresholdColor: Red,
+	})
+
+	for x, want := range []RGBA{Green, Green, Red} {
+		if cell, _ := buffer.GetCell(uint32(x), 0); cell.Foreground != want {
+			t.Errorf("bar %d color = %+v, want %+v", x, cell.Foreground, want)
+		}
+	}
+}
+
+func TestDrawSparklineEmpty(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 1)
+	buffer.DrawText("abc", 0, 0, White, nil, 0)
+	if err := buffer.DrawSparkline(0, 0, 3, nil, SparklineOptions{}); err != nil {
+		t.Fatalf("DrawSparkline failed: %v", err)
+	}
+	expectRows(t, buffer, "   ")
+}
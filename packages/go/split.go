@@ -0,0 +1,192 @@
+package opentui
+
+/*
+#include "opentui.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+// splitScreen is the layout set up by SetupSplit: the renderer owns the bottom rows
+// of the terminal and a scroll region (DECSTBM) covers the rows above. Its fields are
+// guarded by the suspend mutex, which already serializes frames.
+type splitScreen struct {
+	height  uint32 // rows owned by the renderer
+	rows    uint32 // height of the terminal
+	pending []byte // line written to WriteScrollback without its newline yet
+}
+
+// SetupSplit splits the terminal into a scrolling area on top and the bottom height
+// rows, which the renderer draws into. It sets up the terminal like SetupTerminal on
+// the main screen, scrolls the screen up to make room and limits scrolling to the
+// area above the renderer, so output written there scrolls while the frame stays put.
+// Between frames the cursor waits on the last row of the scrolling area, so plain
+// writes to stdout end up there as well; WriteScrollback is safe to use at any time.
+// The renderer must have the size of the terminal on the first call; it is resized to
+// height rows. A terminal resize recomputes the areas when WatchResize is active.
+func (r *Renderer) SetupSplit(height uint32) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if r.headless {
+		return ErrNotATTY
+	}
+	if r.inline != nil {
+		return newError("inline renderer can't be split")
+	}
+	if r.altScreen {
+		return newError("split screen needs the main screen")
+	}
+	rows := r.height
+	if r.split != nil {
+		rows = r.split.rows
+	}
+	if height == 0 || height >= rows {
+		return newError("split height must leave room for the scrolling area")
+	}
+
+	if r.split == nil {
+		if err := r.Resize(r.width, height); err != nil {
+			return err
+		}
+		if err := r.native(func() { C.setupTerminal(r.ptr, C.bool(false)) }); err != nil {
+			return err
+		}
+		r.terminalSetup = true
+		r.readReplies()
+		if r.handleSuspend {
+			r.watchSuspend()
+		}
+	} else if err := r.Resize(r.width, height); err != nil {
+		return err
+	}
+
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	var sequence string
+	if r.split == nil {
+		r.split = &splitScreen{rows: rows}
+	} else {
+		// Erase the old frame before moving the areas
+		sequence = fmt.Sprintf("\x1b[r\x1b[%d;1H\x1b[0m\x1b[J", r.split.rows-r.split.height+1)
+	}
+	r.split.height = height
+	if _, err := r.terminal().Write([]byte(sequence + r.enterSplit())); err != nil {
+		return err
+	}
+	r.setSplitOffset()
+	r.forceRender = true
+	return nil
+}
+
+// WriteScrollback writes data into the scrolling area above a split renderer. Each
+// complete line scrolls the area up by the rows it takes; a line without its newline
+// is held back until the newline arrives or the renderer is closed.
+func (r *Renderer) WriteScrollback(data []byte) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	if r.split == nil {
+		return newError("renderer is not split, see SetupSplit")
+	}
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	split := r.split
+	split.pending = append(split.pending, data...)
+	end := strings.LastIndexByte(string(split.pending), '\n')
+	if end < 0 {
+		return nil
+	}
+	lines := string(split.pending[:end])
+	split.pending = append(split.pending[:0], split.pending[end+1:]...)
+	return r.writeScrollback(lines)
+}
+
+// writeScrollback writes complete lines to the last row of the scrolling area, which
+// is left blank again. The caller holds the suspend mutex.
+func (r *Renderer) writeScrollback(lines string) error {
+	var out strings.Builder
+	if !r.suspend.suspended {
+		out.WriteString(r.splitCursor())
+	}
+	out.WriteString(strings.ReplaceAll(lines, "\n", "\r\n"))
+	out.WriteString("\r\n")
+	_, err := r.terminal().Write([]byte(out.String()))
+	return err
+}
+
+// enterSplit returns the sequence scrolling everything on screen above the renderer,
+// leaving a blank last line in the scrolling area for the cursor, and setting up the
+// scroll region.
+func (r *Renderer) enterSplit() string {
+	return fmt.Sprintf("\x1b[%d;1H\x1b[0m", r.split.rows) + strings.Repeat("\r\n", int(r.split.height)+1) + r.splitMargins()
+}
+
+// splitMargins returns the sequence limiting scrolling to the area above the
+// renderer, followed by the cursor move into that area.
+func (r *Renderer) splitMargins() string {
+	return fmt.Sprintf("\x1b[1;%dr", r.split.rows-r.split.height) + r.splitCursor()
+}
+
+// splitCursor returns the sequence moving the cursor to the last row of the
+// scrolling area, where the next line of output goes.
+func (r *Renderer) splitCursor() string {
+	return fmt.Sprintf("\x1b[%d;1H", r.split.rows-r.split.height)
+}
+
+// setSplitOffset draws the frames into the bottom rows.
+func (r *Renderer) setSplitOffset() {
+	r.renderOffset = r.split.rows - r.split.height
+	C.setRenderOffset(r.ptr, C.uint32_t(r.renderOffset))
+}
+
+// applySplitResize follows a terminal size change: the renderer takes the new width
+// and the scroll region is set again for the new height, shrinking the renderer if the
+// terminal no longer has room for it. The renderer rows are cleared and redrawn in
+// full, since terminals rewrap their lines when the width changes.
+func (r *Renderer) applySplitResize(size Size, callbacks []resizeCallback) error {
+	split := r.split
+	if size.Width == r.width && size.Height == split.rows {
+		return nil
+	}
+	if size.Height < 2 {
+		return nil
+	}
+	split.rows = size.Height
+	split.height = min(split.height, size.Height-1)
+	if err := r.Resize(size.Width, split.height); err != nil {
+		return err
+	}
+	sequence := fmt.Sprintf("\x1b[%d;1H\x1b[0m\x1b[J", split.rows-split.height+1) + r.splitMargins()
+	if _, err := r.terminal().Write([]byte(sequence)); err != nil {
+		return err
+	}
+	r.setSplitOffset()
+	r.forceRender = true
+	for _, c := range callbacks {
+		c.fn(size.Width, split.height)
+	}
+	return nil
+}
+
+// leaveSplit returns the sequence that resets the scroll region and erases the rows
+// of the renderer, leaving the cursor on the blank line above them.
+func (r *Renderer) leaveSplit() string {
+	return "\x1b[r" + r.splitCursor() + "\x1b[0m\x1b[J"
+}
+
+// closeSplit writes what is left of the scrollback and hands the whole screen back to
+// the shell. The renderer is still open.
+func (r *Renderer) closeSplit() {
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	if len(r.split.pending) > 0 {
+		r.writeScrollback(string(r.split.pending))
+		r.split.pending = nil
+	}
+	if !r.suspend.suspended {
+		r.terminal().Write([]byte(r.leaveSplit()))
+	}
+}
@@ -0,0 +1,58 @@
+package opentui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSplitRenderer(t *testing.T) {
+	var out bytes.Buffer
+	renderer := NewRendererWithOptions(RendererOptions{Width: 20, Height: 10, Output: &out})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	if err := renderer.WriteScrollback([]byte("x\n")); err == nil {
+		t.Error("WriteScrollback should fail before SetupSplit")
+	}
+	if err := renderer.SetupSplit(10); err == nil {
+		t.Error("a split leaving no scrolling area should fail")
+	}
+	if err := renderer.SetupSplit(3); err != nil {
+		t.Fatalf("SetupSplit failed: %v", err)
+	}
+	if renderer.height != 3 || renderer.renderOffset != 7 {
+		t.Errorf("split renderer is %d rows at row %d, want 3 at row 7", renderer.height, renderer.renderOffset)
+	}
+	if got := out.String(); !strings.HasSuffix(got, "\x1b[10;1H\x1b[0m\r\n\r\n\r\n\r\n\x1b[1;7r\x1b[7;1H") {
+		t.Errorf("SetupSplit wrote %q", got)
+	}
+
+	out.Reset()
+	renderer.WriteScrollback([]byte("one\ntw"))
+	renderer.WriteScrollback([]byte("o\nthree"))
+	if got, want := out.String(), "\x1b[7;1Hone\r\n\x1b[7;1Htwo\r\n"; got != want {
+		t.Errorf("WriteScrollback wrote %q, want %q", got, want)
+	}
+
+	// A shorter terminal keeps the scroll region above the renderer
+	out.Reset()
+	renderer.resize.pending = &Size{Width: 16, Height: 8}
+	if err := renderer.applyPendingResize(); err != nil {
+		t.Fatalf("resize failed: %v", err)
+	}
+	if renderer.width != 16 || renderer.renderOffset != 5 {
+		t.Errorf("after resize: width %d at row %d, want 16 at row 5", renderer.width, renderer.renderOffset)
+	}
+	if got := out.String(); !strings.Contains(got, "\x1b[1;5r") {
+		t.Errorf("resize should move the scroll region, wrote %q", got)
+	}
+
+	out.Reset()
+	renderer.Close()
+	if got := out.String(); !strings.HasPrefix(got, "\x1b[5;1Hthree\r\n\x1b[r\x1b[5;1H\x1b[0m\x1b[J") {
+		t.Errorf("Close should flush the scrollback and reset the scroll region, wrote %q", got)
+	}
+}
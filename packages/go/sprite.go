@@ -0,0 +1,108 @@
+package opentui
+
+// BlitOptions controls which sprite cells BlitSprite copies and how they are blended
+type BlitOptions struct {
+	TransparentChar rune    // Cells with this character are skipped, 0 disables the check
+	TransparentBg   *RGBA   // Cells with this background color are skipped, nil disables the check
+	Opacity         float32 // Opacity applied to the whole sprite, 0 is treated as fully opaque
+}
+
+// BlitSprite draws a sprite buffer onto this buffer with its top left corner at dest,
+// skipping transparent cells. Unlike DrawFrameBuffer, empty cells can be left out so
+// the frame below shows through. The sprite is clipped at all edges of this buffer,
+// and dest may be negative.
+//
+// Opaque cells are copied directly, while translucent cells and sprites with an opacity
+// below 1 go through SetCellWithAlphaBlending. Wide characters and other grapheme clusters cut
+// by the edges of this buffer leave spaces.
+func (b *Buffer) BlitSprite(dest Position, sprite *Buffer, opts BlitOptions) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if sprite == nil || sprite.ptr == nil {
+		return kindError(ErrClosed, "sprite is nil or closed")
+	}
+
+	opacity := opts.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = 1
+	}
+
+	dst, err := b.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	src, err := sprite.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+
+	// Clip the sprite rectangle against the destination
+	startX, startY := max(-int64(dest.X), 0), max(-int64(dest.Y), 0)
+	endX := min(int64(src.Width), int64(dst.Width)-int64(dest.X))
+	endY := min(int64(src.Height), int64(dst.Height)-int64(dest.Y))
+
+	var texts []string
+	if src.hasClusters() {
+		texts = src.cellTexts()
+	}
+	for sy := startY; sy < endY; sy++ {
+		dy := uint32(sy + int64(dest.Y))
+		covered := uint32(0) // Cells left to cover by the last cluster drawn
+		for sx := startX; sx < endX; sx++ {
+			dx := uint32(sx + int64(dest.X))
+			si := uint32(sy)*src.Width + uint32(sx)
+			inCluster := covered > 0
+			covered = max(covered, 1) - 1
+
+			stored := src.Chars[si]
+			char := rune(stored)
+			if isClusterChar(stored) {
+				char = cellChar(texts[si])
+			}
+			fg, bg := src.Foreground[si], src.Background[si]
+			if opts.TransparentChar != 0 && char == opts.TransparentChar {
+				continue
+			}
+			if opts.TransparentBg != nil && bg == *opts.TransparentBg {
+				continue
+			}
+
+			// Clusters are written by the pool reference of the sprite, which the native
+			// layer counts for this buffer too. Halves cut by the edges become spaces.
+			switch {
+			case isGraphemeChar(stored) && sx+int64(storedCharWidth(stored)) <= endX:
+				dst.clearCovered(dx, dy, storedCharWidth(stored))
+				char = rune(stored)
+				covered = storedCharWidth(stored) - 1
+			case isContinuationChar(stored) && inCluster:
+				// Written with its cluster, and given its own colors if opaque
+				di := dy*dst.Width + dx
+				if opacity == 1 && fg.A >= 1 && bg.A >= 1 && isContinuationChar(dst.Chars[di]) {
+					dst.Foreground[di], dst.Background[di], dst.Attributes[di] = fg, bg, src.Attributes[si]
+				}
+				continue
+			case isClusterChar(stored):
+				char = ' '
+			}
+
+			if opacity == 1 && fg.A >= 1 && bg.A >= 1 {
+				di := dy*dst.Width + dx
+				if isClusterChar(dst.Chars[di]) || isClusterChar(uint32(char)) {
+					dst.SetCell(dx, dy, Cell{Char: char, Foreground: fg, Background: bg, Attributes: src.Attributes[si]})
+					continue
+				}
+				dst.Chars[di] = uint32(char)
+				dst.Foreground[di] = fg
+				dst.Background[di] = bg
+				dst.Attributes[di] = src.Attributes[si]
+				continue
+			}
+
+			fg.A *= opacity
+			bg.A *= opacity
+			b.SetCellWithAlphaBlending(dx, dy, char, fg, bg, src.Attributes[si])
+		}
+	}
+	return nil
+}
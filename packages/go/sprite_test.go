@@ -0,0 +1,153 @@
+package opentui
+
+import "testing"
+
+// newTestSprite returns a sprite buffer drawn from rows of text,
+// with '.' cells marking the transparent parts.
+func newTestSprite(t testing.TB, rows ...string) *Buffer {
+	t.Helper()
+	sprite := NewBuffer(uint32(len([]rune(rows[0]))), uint32(len(rows)), false, WidthMethodUnicode)
+	if sprite == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	t.Cleanup(func() { sprite.Close() })
+
+	sprite.Clear(Blue)
+	for y, row := range rows {
+		sprite.DrawText(row, 0, uint32(y), White, nil, 0)
+	}
+	return sprite
+}
+
+func TestBlitSpriteTransparentChar(t *testing.T) {
+	buffer := newTestBuffer(t, 5, 3)
+	buffer.DrawText("xxxxx", 0, 0, White, nil, 0)
+	buffer.DrawText("xxxxx", 0, 1, White, nil, 0)
+	buffer.DrawText("xxxxx", 0, 2, White, nil, 0)
+
+	sprite := newTestSprite(t,
+		".#.",
+		"###",
+	)
+	if err := buffer.BlitSprite(Position{X: 1, Y: 1}, sprite, BlitOptions{TransparentChar: '.'}); err != nil {
+		t.Fatalf("BlitSprite failed: %v", err)
+	}
+	expectRows(t, buffer,
+		"xxxxx",
+		"xx#xx",
+		"x###x",
+	)
+
+	da, _ := buffer.GetDirectAccess()
+	if cell, _ := da.GetCell(1, 1); cell.Background != Black {
+		t.Errorf("transparent cell background = %+v, want black", cell.Background)
+	}
+	if cell, _ := da.GetCell(2, 1); cell.Background != Blue {
+		t.Errorf("sprite cell background = %+v, want blue", cell.Background)
+	}
+}
+
+func TestBlitSpriteTransparentBg(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 1)
+	sprite := newTestSprite(t, "abc")
+	sprite.SetCellWithAlphaBlending(1, 0, 'b', White, Red, 0)
+
+	blue := Blue
+	buffer.BlitSprite(Position{}, sprite, BlitOptions{TransparentBg: &blue})
+	expectRows(t, buffer, " b ")
+}
+
+func TestBlitSpriteClipping(t *testing.T) {
+	sprite := newTestSprite(t,
+		"abc",
+		"def",
+		"ghi",
+	)
+
+	tests := []struct {
+		dest Position
+		rows []string
+	}{
+		{Position{X: -1, Y: -1}, []string{"ef  ", "hi  ", "    "}},
+		{Position{X: 2, Y: 1}, []string{"    ", "  ab", "  de"}},
+		{Position{X: -3, Y: 0}, []string{"    ", "    ", "    "}},
+		{Position{X: 4, Y: 3}, []string{"    ", "    ", "    "}},
+	}
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 4, 3)
+		if err := buffer.BlitSprite(tt.dest, sprite, BlitOptions{}); err != nil {
+			t.Fatalf("BlitSprite failed: %v", err)
+		}
+		expectRows(t, buffer, tt.rows...)
+	}
+}
+
+func TestBlitSpriteClusters(t *testing.T) {
+	sprite := newTestSprite(t, "漢a ")
+
+	// A cluster cut by the edges of the buffer leaves a space
+	tests := []struct {
+		dest         Position
+		row          string
+		continuation int32
+	}{
+		{Position{X: 1}, "x漢 ax", 2},
+		{Position{X: -1}, " axxx", -1},
+		{Position{X: 4}, "xxxx ", -1},
+	}
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 5, 1)
+		buffer.DrawText("xxxxx", 0, 0, White, nil, 0)
+		if err := buffer.BlitSprite(tt.dest, sprite, BlitOptions{}); err != nil {
+			t.Fatalf("BlitSprite failed: %v", err)
+		}
+		expectRows(t, buffer, tt.row)
+		for x := int32(0); x < 5; x++ {
+			if kind, _ := buffer.GetCellKind(uint32(x), 0); (kind == CellContinuation) != (x == tt.continuation) {
+				t.Errorf("blit at %d: cell %d is %v", tt.dest.X, x, kind)
+			}
+		}
+	}
+}
+
+func TestBlitSpriteOpacity(t *testing.T) {
+	buffer := newTestBuffer(t, 1, 1)
+	sprite := newTestSprite(t, "a")
+
+	buffer.BlitSprite(Position{}, sprite, BlitOptions{Opacity: 0.5})
+
+	da, _ := buffer.GetDirectAccess()
+	cell, _ := da.GetCell(0, 0)
+	if cell.Background == Blue {
+		t.Error("half transparent sprite was copied without blending")
+	}
+}
+
+func BenchmarkBlitSprite(b *testing.B) {
+	buffer := newTestBuffer(b, 80, 24)
+	sprite := newTestSprite(b, "..####..", ".######.", "########", ".######.", "..####..")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buffer.BlitSprite(Position{X: 10, Y: 5}, sprite, BlitOptions{TransparentChar: '.'})
+	}
+}
+
+func BenchmarkBlitSpriteNaive(b *testing.B) {
+	buffer := newTestBuffer(b, 80, 24)
+	sprite := newTestSprite(b, "..####..", ".######.", "########", ".######.", "..####..")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		da, _ := sprite.GetDirectAccess()
+		for y := uint32(0); y < da.Height; y++ {
+			for x := uint32(0); x < da.Width; x++ {
+				cell, _ := da.GetCell(x, y)
+				if cell.Char == '.' {
+					continue
+				}
+				buffer.SetCellWithAlphaBlending(10+x, 5+y, cell.Char, cell.Foreground, cell.Background, cell.Attributes)
+			}
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package opentui
+
+/*
+#include "opentui.h"
+*/
+import "C"
+import "sync"
+
+// showCursor makes the cursor visible again for the shell while suspended
+const showCursor = "\x1b[?25h"
+
+// ErrNotForeground is returned by Resume when the process was continued in the
+// background, where touching the terminal would stop it again. Call Resume again once
+// the process is back in the foreground; the handlers installed for
+// RendererOptions.HandleSuspend do.
+var ErrNotForeground = newError("process is not in the foreground")
+
+// suspendState tracks what Suspend undid, so Resume can set it up again.
+type suspendState struct {
+	mu        sync.Mutex
+	suspended bool
+	altScreen bool         // alternate screen was active before Suspend
+	external  bool         // suspended by RunExternal, which resumes on its own
+	restore   func() error // restores the terminal mode changed by Suspend, nil if unchanged
+	unhandle  func()       // removes the signal handlers, nil when not installed
+
+	// Hooks replaced in tests. Nil uses the platform implementation.
+	stop       func() error                             // stops the process group
+	foreground func() bool                              // reports whether the process owns the terminal
+	cook       func() (restore func() error, err error) // switches stdin to cooked mode
+}
+
+// Suspend hands the terminal back to the shell and stops the process group, like
+// Ctrl+Z does for other programs: mouse tracking and the Kitty keyboard protocol are
+// turned off, the alternate screen is left, the cursor is shown and stdin is switched
+// to cooked mode. Returns once the process is continued; call Resume then to set the
+// terminal up again. Render draws nothing while the renderer is suspended.
+func (r *Renderer) Suspend() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.suspend.mu.Lock()
+	if r.suspend.suspended {
+		external, stop := r.suspend.external, r.suspend.stop
+		r.suspend.mu.Unlock()
+		if external {
+			// Ctrl+Z in the program run by RunExternal, which already gave up the terminal
+			if stop == nil {
+				stop = stopProcessGroup
+			}
+			return stop()
+		}
+		return nil
+	}
+	if err := r.releaseTerminal(); err != nil {
+		r.suspend.mu.Unlock()
+		return err
+	}
+	r.suspend.suspended = true
+	stop := r.suspend.stop
+	r.suspend.mu.Unlock()
+
+	if stop == nil {
+		stop = stopProcessGroup
+	}
+	return stop()
+}
+
+// Resume sets the terminal up again after Suspend: stdin gets back the mode it had,
+// the terminal setup is run again, the alternate screen is entered if it was active,
+// mouse tracking and the Kitty keyboard protocol are turned back on and the next
+// Render redraws every cell. Returns ErrNotForeground if the process was continued in
+// the background, and nil without doing anything if the renderer is not suspended or
+// suspended by RunExternal.
+func (r *Renderer) Resume() error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	if !r.suspend.suspended || r.suspend.external {
+		return nil
+	}
+	foreground := r.suspend.foreground
+	if foreground == nil {
+		foreground = isForeground
+	}
+	if !foreground() {
+		return ErrNotForeground
+	}
+
+	if r.suspend.restore != nil {
+		if err := r.suspend.restore(); err != nil {
+			return err
+		}
+		r.suspend.restore = nil
+	}
+	r.suspend.suspended = false
+	return r.reclaimTerminal()
+}
+
+// Suspended reports whether the renderer was suspended and not resumed yet.
+func (r *Renderer) Suspended() bool {
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	return r.suspend.suspended
+}
+
+// releaseTerminal undoes the terminal setup for Suspend. The modes are remembered
+// so reclaimTerminal can turn them back on.
+func (r *Renderer) releaseTerminal() error {
+	if r.mouseEnabled {
+		if err := r.native(func() { C.disableMouse(r.ptr) }); err != nil {
+			return err
+		}
+	}
+	if r.kittyEnabled {
+		if err := r.native(func() { C.disableKittyKeyboard(r.ptr) }); err != nil {
+			return err
+		}
+	}
+	r.suspend.altScreen = r.altScreen
+	sequence := r.popKittyStack() + showCursor
+	if r.mousePixels {
+		sequence = disableMousePixels + sequence
+	}
+	if r.altScreen {
+		sequence = leaveAltScreen + sequence
+	}
+	if r.inline != nil && r.terminalSetup {
+		// The shell continues below the last frame
+		sequence = r.leaveInline(true) + sequence
+	}
+	if r.split != nil {
+		sequence = r.leaveSplit() + sequence
+	}
+	if _, err := r.terminal().Write([]byte(sequence)); err != nil {
+		return err
+	}
+	r.altScreen = false
+
+	cook := r.suspend.cook
+	if cook == nil {
+		cook = cookTerminal
+	}
+	restore, err := cook()
+	if err != nil {
+		return err
+	}
+	r.suspend.restore = restore
+	return nil
+}
+
+// reclaimTerminal sets the terminal up again as it was before releaseTerminal, and
+// makes the next Render redraw every cell.
+func (r *Renderer) reclaimTerminal() error {
+	altScreen := r.suspend.altScreen
+	var inlineTop uint32
+	if r.inline != nil && r.terminalSetup {
+		inlineTop = r.inlineTop()
+	}
+	if r.terminalSetup {
+		// Running the setup again enters the alternate screen as well
+		if err := r.native(func() { C.setupTerminal(r.ptr, C.bool(altScreen)) }); err != nil {
+			return err
+		}
+	} else if altScreen {
+		if _, err := r.terminal().Write([]byte(enterAltScreen)); err != nil {
+			return err
+		}
+	}
+	r.altScreen = altScreen
+	if r.inline != nil && r.terminalSetup {
+		r.setInlineTop(inlineTop)
+	}
+	if r.split != nil {
+		if _, err := r.terminal().Write([]byte(r.enterSplit())); err != nil {
+			return err
+		}
+	}
+
+	if r.mouseEnabled {
+		if err := r.native(func() { C.enableMouse(r.ptr, C.bool(r.mouseMovement)) }); err != nil {
+			return err
+		}
+	}
+	if r.kittyEnabled {
+		if err := r.native(func() { C.enableKittyKeyboard(r.ptr, C.uint8_t(r.kittyFlags)) }); err != nil {
+			return err
+		}
+	}
+	sequence := r.pushKittyStack()
+	if r.mousePixels {
+		sequence += enableMousePixels
+	}
+	if sequence != "" {
+		if _, err := r.terminal().Write([]byte(sequence)); err != nil {
+			return err
+		}
+	}
+	r.forceRender = true
+	return nil
+}
+
+// watchSuspend installs the signal handlers for RendererOptions.HandleSuspend:
+// SIGTSTP suspends the renderer and SIGCONT resumes it.
+func (r *Renderer) watchSuspend() {
+	r.suspend.mu.Lock()
+	defer r.suspend.mu.Unlock()
+	if r.suspend.unhandle == nil {
+		r.suspend.unhandle = watchSuspendSignals(func() { r.Suspend() }, func() { r.Resume() })
+	}
+}
+
+// stopWatchingSuspend removes the signal handlers installed by watchSuspend.
+func (r *Renderer) stopWatchingSuspend() {
+	r.suspend.mu.Lock()
+	unhandle := r.suspend.unhandle
+	r.suspend.unhandle = nil
+	r.suspend.mu.Unlock()
+	if unhandle != nil {
+		unhandle()
+	}
+}
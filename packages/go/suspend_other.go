@@ -0,0 +1,23 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package opentui
+
+// stopProcessGroup is not implemented on this platform, which has no job control.
+func stopProcessGroup() error {
+	return newError("suspending is not supported on this platform")
+}
+
+// isForeground always reports true without job control.
+func isForeground() bool {
+	return true
+}
+
+// cookTerminal leaves the terminal mode alone on this platform.
+func cookTerminal() (restore func() error, err error) {
+	return nil, nil
+}
+
+// watchSuspendSignals installs nothing on this platform, which has no SIGTSTP.
+func watchSuspendSignals(suspend, resume func()) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,76 @@
+package opentui
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSuspendResume(t *testing.T) {
+	var out bytes.Buffer
+	truecolor := ProfileTrueColor
+	renderer := NewRendererWithOptions(RendererOptions{Width: 4, Height: 1, Output: &out, ColorProfile: &truecolor})
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	var stops, cooks, restores int
+	foreground := true
+	renderer.suspend.stop = func() error { stops++; return nil }
+	renderer.suspend.foreground = func() bool { return foreground }
+	renderer.suspend.cook = func() (func() error, error) {
+		cooks++
+		return func() error { restores++; return nil }, nil
+	}
+
+	renderer.EnableMouse(true)
+	renderer.EnableKittyKeyboard(1)
+	renderer.EnterAlternateScreen()
+	out.Reset()
+
+	if err := renderer.Suspend(); err != nil {
+		t.Fatalf("Suspend failed: %v", err)
+	}
+	if out.String() != leaveAltScreen+showCursor {
+		t.Errorf("Suspend wrote %q", out.String())
+	}
+	if stops != 1 || cooks != 1 || !renderer.Suspended() || renderer.InAlternateScreen() {
+		t.Errorf("after Suspend: %d stops, %d cooks, suspended %v", stops, cooks, renderer.Suspended())
+	}
+	renderer.Suspend()
+	if stops != 1 {
+		t.Error("suspending twice should not stop the process again")
+	}
+
+	out.Reset()
+	if err := renderer.Render(true); err != nil || out.Len() != 0 {
+		t.Errorf("Render while suspended wrote %q, error %v", out.String(), err)
+	}
+
+	// Continued in the background: the terminal belongs to the shell
+	foreground = false
+	if err := renderer.Resume(); err != ErrNotForeground {
+		t.Errorf("Resume in the background returned %v", err)
+	}
+	if !renderer.Suspended() || restores != 0 || out.Len() != 0 {
+		t.Errorf("Resume in the background touched the terminal: %q", out.String())
+	}
+
+	// Brought to the foreground
+	foreground = true
+	if err := renderer.Resume(); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if out.String() != enterAltScreen {
+		t.Errorf("Resume wrote %q", out.String())
+	}
+	if renderer.Suspended() || restores != 1 || !renderer.InAlternateScreen() || !renderer.forceRender {
+		t.Errorf("after Resume: %d restores, suspended %v", restores, renderer.Suspended())
+	}
+	if !renderer.mouseEnabled || !renderer.mouseMovement || !renderer.kittyEnabled || renderer.kittyFlags != 1 {
+		t.Error("mouse tracking and the Kitty keyboard protocol should stay enabled")
+	}
+	if err := renderer.Resume(); err != nil || restores != 1 {
+		t.Errorf("resuming twice returned %v", err)
+	}
+}
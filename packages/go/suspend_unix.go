@@ -0,0 +1,76 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package opentui
+
+/*
+#include <termios.h>
+#include <unistd.h>
+*/
+import "C"
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// stopProcessGroup stops every process in the group, as the terminal does on Ctrl+Z.
+func stopProcessGroup() error {
+	return syscall.Kill(0, syscall.SIGSTOP)
+}
+
+// isForeground reports whether the process group owns the terminal on stdin. Without
+// a terminal there is nothing to wait for, so the process counts as foreground.
+func isForeground() bool {
+	owner := C.tcgetpgrp(0)
+	return owner < 0 || owner == C.getpgrp()
+}
+
+// cookTerminal switches stdin to cooked mode with line editing, echo and signals,
+// and returns a function restoring the previous mode. Does nothing if stdin is not a
+// terminal.
+func cookTerminal() (restore func() error, err error) {
+	var saved C.struct_termios
+	if C.tcgetattr(0, &saved) != 0 {
+		return nil, nil
+	}
+	cooked := saved
+	cooked.c_iflag |= C.ICRNL
+	cooked.c_oflag |= C.OPOST
+	cooked.c_lflag |= C.ICANON | C.ECHO | C.ECHOE | C.ISIG | C.IEXTEN
+	if result, err := C.tcsetattr(0, C.TCSANOW, &cooked); result != 0 {
+		return nil, err
+	}
+	return func() error {
+		if result, err := C.tcsetattr(0, C.TCSANOW, &saved); result != 0 {
+			return err
+		}
+		return nil
+	}, nil
+}
+
+// watchSuspendSignals calls suspend on every SIGTSTP and resume on every SIGCONT
+// until the returned function is called. Catching SIGTSTP keeps the process from
+// being stopped before suspend has restored the terminal.
+func watchSuspendSignals(suspend, resume func()) (stop func()) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, syscall.SIGTSTP, syscall.SIGCONT)
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				if sig == syscall.SIGTSTP {
+					suspend()
+				}
+				// Continued in the background, Resume waits for the next SIGCONT
+				resume()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,263 @@
+package opentui
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SVGExportOptions controls Buffer.ExportSVG. Zero values select the defaults.
+type SVGExportOptions struct {
+	FontFamily   string  // CSS font family list, default a common monospace stack
+	FontSize     float64 // Font size in pixels, default 14
+	CellWidth    float64 // Width of a cell in pixels, default 8.4
+	CellHeight   float64 // Height of a cell in pixels, default 17
+	Padding      float64 // Space around the cells in pixels
+	WindowChrome bool    // Draw a window frame with a title bar around the cells
+}
+
+// Defaults for SVGExportOptions
+const (
+	defaultSVGFontFamily = "Menlo, Monaco, Consolas, 'DejaVu Sans Mono', monospace"
+	defaultSVGFontSize   = 14
+	defaultSVGCellWidth  = 8.4
+	defaultSVGCellHeight = 17
+)
+
+// Window chrome layout
+const (
+	svgChromeTitleBar = 32 // height of the title bar
+	svgChromeMargin   = 12 // space between the frame and the cells
+	svgChromeRadius   = 8
+)
+
+var (
+	svgChromeBackground = "#1e1e2e"
+	svgChromeButtons    = []string{"#ff5f57", "#febc2e", "#28c840"}
+)
+
+// ExportSVG renders the buffer as an SVG image that looks the same in every viewer:
+// a rect per run of cells with the same background, a text element per run with the
+// same foreground and attributes, and lines for underlined and struck through text.
+// Text runs are stretched to the width of their cells, so double width characters
+// cover two cells whatever font the viewer substitutes. Transparent backgrounds are
+// left out, and blinking is ignored.
+func (b *Buffer) ExportSVG(opts SVGExportOptions) ([]byte, error) {
+	if b.ptr == nil {
+		return nil, closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return nil, err
+	}
+	return exportSVG(da, opts), nil
+}
+
+// svgCell is a cell resolved for export: its text and the colors to draw, with
+// reverse video applied.
+type svgCell struct {
+	text   string // empty for the trailing cell of a double width character
+	width  uint32 // cells covered
+	fg, bg RGBA
+	attrs  Attributes
+}
+
+func exportSVG(da *DirectAccess, opts SVGExportOptions) []byte {
+	if opts.FontFamily == "" {
+		opts.FontFamily = defaultSVGFontFamily
+	}
+	if opts.FontSize <= 0 {
+		opts.FontSize = defaultSVGFontSize
+	}
+	if opts.CellWidth <= 0 {
+		opts.CellWidth = defaultSVGCellWidth
+	}
+	if opts.CellHeight <= 0 {
+		opts.CellHeight = defaultSVGCellHeight
+	}
+
+	originX, originY := opts.Padding, opts.Padding
+	width := float64(da.Width)*opts.CellWidth + 2*opts.Padding
+	height := float64(da.Height)*opts.CellHeight + 2*opts.Padding
+	if opts.WindowChrome {
+		originX += svgChromeMargin
+		originY += svgChromeTitleBar
+		width += 2 * svgChromeMargin
+		height += svgChromeTitleBar + svgChromeMargin
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		svgNumber(width), svgNumber(height), svgNumber(width), svgNumber(height))
+	if opts.WindowChrome {
+		fmt.Fprintf(&out, `<rect width="%s" height="%s" rx="%d" fill="%s"/>`+"\n",
+			svgNumber(width), svgNumber(height), svgChromeRadius, svgChromeBackground)
+		for i, color := range svgChromeButtons {
+			fmt.Fprintf(&out, `<circle cx="%d" cy="%d" r="6" fill="%s"/>`+"\n", 20+i*20, svgChromeTitleBar/2, color)
+		}
+	}
+
+	texts := da.cellTexts()
+	rows := make([][]svgCell, da.Height)
+	for y := range rows {
+		rows[y] = svgRow(da, texts, uint32(y))
+	}
+
+	out.WriteString("<g>\n")
+	for y, row := range rows {
+		top := originY + float64(y)*opts.CellHeight
+		for x := 0; x < len(row); {
+			end := x + 1
+			for end < len(row) && row[end].bg == row[x].bg {
+				end++
+			}
+			if row[x].bg.A > 0 {
+				fmt.Fprintf(&out, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"%s/>`+"\n",
+					svgNumber(originX+float64(x)*opts.CellWidth), svgNumber(top),
+					svgNumber(float64(end-x)*opts.CellWidth), svgNumber(opts.CellHeight),
+					svgColor(row[x].bg), svgOpacity("fill-opacity", row[x].bg.A))
+			}
+			x = end
+		}
+	}
+	out.WriteString("</g>\n")
+
+	fmt.Fprintf(&out, `<g font-family="%s" font-size="%s" xml:space="preserve">`+"\n",
+		svgEscape(opts.FontFamily), svgNumber(opts.FontSize))
+	for y, row := range rows {
+		top := originY + float64(y)*opts.CellHeight
+		baseline := top + opts.CellHeight*0.75
+		for x := 0; x < len(row); {
+			cell := row[x]
+			end := x + 1
+			for end < len(row) && row[end].fg == cell.fg && row[end].attrs == cell.attrs {
+				end++
+			}
+			// Trailing spaces need no glyphs and would only be stretched
+			textEnd := end
+			for textEnd > x && row[textEnd-1].text == " " {
+				textEnd--
+			}
+			var text strings.Builder
+			for i := x; i < textEnd; i++ {
+				text.WriteString(row[i].text)
+			}
+			left := originX + float64(x)*opts.CellWidth
+			runWidth := float64(end-x) * opts.CellWidth
+			textWidth := float64(textEnd-x) * opts.CellWidth
+			x = end
+
+			if cell.attrs&AttrHidden == 0 && strings.TrimSpace(text.String()) != "" {
+				fmt.Fprintf(&out, `<text x="%s" y="%s" textLength="%s" lengthAdjust="spacingAndGlyphs" fill="%s"%s%s>%s</text>`+"\n",
+					svgNumber(left), svgNumber(baseline), svgNumber(textWidth), svgColor(cell.fg),
+					svgOpacity("fill-opacity", svgTextAlpha(cell)), svgFontStyle(cell.attrs), svgEscape(text.String()))
+			}
+			for _, line := range []struct {
+				attr Attributes
+				y    float64
+			}{
+				{AttrUnderline, baseline + 2},
+				{AttrStrike, top + opts.CellHeight*0.5},
+			} {
+				if cell.attrs&line.attr != 0 {
+					fmt.Fprintf(&out, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"%s/>`+"\n",
+						svgNumber(left), svgNumber(line.y), svgNumber(left+runWidth), svgNumber(line.y),
+						svgColor(cell.fg), svgOpacity("stroke-opacity", svgTextAlpha(cell)))
+				}
+			}
+		}
+	}
+	out.WriteString("</g>\n</svg>\n")
+	return out.Bytes()
+}
+
+// svgRow resolves the cells of row y, given the text of every cell.
+func svgRow(da *DirectAccess, texts []string, y uint32) []svgCell {
+	row := make([]svgCell, da.Width)
+	for x := uint32(0); x < da.Width; x++ {
+		i := y*da.Width + x
+		cell := svgCell{width: 1, fg: da.Foreground[i], bg: da.Background[i], attrs: da.Attributes[i]}
+		if cell.attrs&AttrReverse != 0 {
+			cell.fg, cell.bg = cell.bg, cell.fg
+		}
+		if c := da.Chars[i]; isClusterChar(c) {
+			cell.text = texts[i]
+			if cell.text != "" {
+				cell.width = storedCharWidth(c)
+			}
+		} else {
+			r := rune(da.Chars[i])
+			if r < ' ' || r == 0x7f || r > 0x10ffff {
+				r = ' '
+			}
+			cell.text = string(r)
+			cell.width = uint32(max(runeWidth(r), 1))
+		}
+		row[x] = cell
+		// The trailing cell of a double width character is drawn by the leading one
+		if cell.width == 2 && cell.text != "" && x+1 < da.Width {
+			x++
+			trailing := cell
+			trailing.text, trailing.width = "", 0
+			trailing.bg = da.Background[i+1]
+			if da.Attributes[i+1]&AttrReverse != 0 {
+				trailing.bg = da.Foreground[i+1]
+			}
+			row[x] = trailing
+		}
+	}
+	return row
+}
+
+// svgTextAlpha is the opacity of the text of a cell, halved for dim text.
+func svgTextAlpha(cell svgCell) float32 {
+	if cell.attrs&AttrDim != 0 {
+		return cell.fg.A * 0.5
+	}
+	return cell.fg.A
+}
+
+func svgFontStyle(attrs Attributes) string {
+	var style string
+	if attrs&AttrBold != 0 {
+		style += ` font-weight="bold"`
+	}
+	if attrs&AttrItalic != 0 {
+		style += ` font-style="italic"`
+	}
+	return style
+}
+
+// svgColor formats the color channels as #rrggbb; the alpha goes in an opacity attribute.
+func svgColor(c RGBA) string {
+	opaque := c
+	opaque.A = 1
+	return hexColor(opaque)
+}
+
+// svgOpacity returns the opacity attribute for alpha, or nothing if it is opaque.
+func svgOpacity(name string, alpha float32) string {
+	if alpha >= 1 {
+		return ""
+	}
+	return fmt.Sprintf(` %s="%s"`, name, svgNumber(float64(alpha)))
+}
+
+// svgNumber formats v with at most two decimals and without trailing zeros, so the
+// output doesn't depend on floating point noise.
+func svgNumber(v float64) string {
+	v = math.Round(v*100) / 100
+	if v == 0 {
+		v = 0 // no negative zero
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func svgEscape(s string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(s))
+	return escaped.String()
+}
@@ -0,0 +1,81 @@
+package opentui
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// testDirectAccess builds cells without the native library: one string per row, one
+// rune per cell, all white on transparent.
+func testDirectAccess(rows ...string) *DirectAccess {
+	da := &DirectAccess{Width: uint32(len([]rune(rows[0]))), Height: uint32(len(rows))}
+	for _, row := range rows {
+		for _, r := range row {
+			da.Chars = append(da.Chars, uint32(r))
+			da.Foreground = append(da.Foreground, White)
+			da.Background = append(da.Background, Transparent)
+			da.Attributes = append(da.Attributes, 0)
+		}
+	}
+	return da
+}
+
+func TestExportSVG(t *testing.T) {
+	da := testDirectAccess(
+		"Hi <ok> ",
+		"日 a     ",
+	)
+	blue := NewRGBA(0.1, 0.2, 0.6, 1)
+	for i := 0; i < 2; i++ {
+		da.Foreground[i] = Red
+		da.Background[i] = blue
+		da.Attributes[i] = AttrBold
+	}
+	da.Background[2] = blue
+	da.Attributes[3] = AttrUnderline | AttrItalic
+	da.Attributes[4] = AttrUnderline | AttrItalic
+	da.Attributes[8+3] = AttrReverse
+	da.Attributes[8+4] = AttrStrike | AttrDim
+
+	svg := exportSVG(da, SVGExportOptions{CellWidth: 10, CellHeight: 20, Padding: 4, WindowChrome: true})
+	golden := filepath.Join("testdata", "export.svg")
+	if *updateGolden {
+		if err := os.WriteFile(golden, svg, 0o644); err != nil {
+			t.Fatalf("writing golden file failed: %v", err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("reading golden file failed: %v", err)
+	}
+	if !bytes.Equal(svg, want) {
+		t.Errorf("SVG differs from %s, run with -update to inspect:\n%s", golden, svg)
+	}
+}
+
+func TestSVGNumber(t *testing.T) {
+	tests := map[float64]string{0: "0", 8.4 * 3: "25.2", 1.0 / 3: "0.33", -0.001: "0", 17: "17"}
+	for v, want := range tests {
+		if got := svgNumber(v); got != want {
+			t.Errorf("svgNumber(%v) = %q, want %q", v, got, want)
+		}
+	}
+}
+
+func TestBufferExportSVG(t *testing.T) {
+	buffer := newTestBuffer(t, 4, 1)
+	buffer.DrawText("ab", 0, 0, White, &Blue, 0)
+
+	svg, err := buffer.ExportSVG(SVGExportOptions{})
+	if err != nil {
+		t.Fatalf("ExportSVG failed: %v", err)
+	}
+	if !bytes.Contains(svg, []byte(`>ab</text>`)) || !bytes.Contains(svg, []byte(`width="33.6"`)) {
+		t.Errorf("unexpected SVG:\n%s", svg)
+	}
+}
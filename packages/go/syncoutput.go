@@ -0,0 +1,63 @@
+package opentui
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// Synchronized output sequences (mode 2026). The terminal holds back the screen
+// update between them and shows the whole frame at once, so it never tears.
+const (
+	beginSyncOutput = "\x1b[?2026h"
+	endSyncOutput   = "\x1b[?2026l"
+)
+
+// SetSynchronizedOutput sets whether each Render is wrapped in synchronized output
+// markers. The markers are only written if the terminal reported support for mode
+// 2026 (see Capabilities.SupportsSyncOutput) and threaded rendering is off, since the
+// native renderer writes the frame from its own thread in that case.
+func (r *Renderer) SetSynchronizedOutput(enabled bool) error {
+	if r.ptr == nil {
+		return closedError("renderer")
+	}
+	r.syncOutput = enabled
+	return nil
+}
+
+// synchronizeOutput reports whether Render should bracket the frame with the
+// synchronized output markers.
+func (r *Renderer) synchronizeOutput() bool {
+	return r.syncOutput && r.detected.syncOutput && !r.useThread
+}
+
+// syncOutputReply reports whether a response contains a DECRPM reply
+// (ESC [ ? 2026 ; status $ y) for mode 2026 and whether the mode is supported.
+// Status 0 means the mode is unknown and 4 that it is permanently reset.
+func syncOutputReply(response []byte) (supported, ok bool) {
+	status, ok := modeReply(response, 2026)
+	return ok && status >= 1 && status <= 3, ok
+}
+
+// modeReply extracts the status from the first DECRPM reply (ESC [ ? mode ; status
+// $ y) for a private mode contained in a response: 1 set, 2 reset, 3 permanently set,
+// 4 permanently reset and 0 unknown to the terminal.
+func modeReply(response []byte, mode int) (status int, ok bool) {
+	prefix := []byte("\x1b[?" + strconv.Itoa(mode) + ";")
+	for {
+		start := bytes.Index(response, prefix)
+		if start < 0 {
+			return 0, false
+		}
+		response = response[start+len(prefix):]
+
+		end := bytes.Index(response, []byte("$y"))
+		if end < 0 {
+			return 0, false
+		}
+		status, err := strconv.Atoi(string(response[:end]))
+		if err != nil {
+			continue
+		}
+		return status, true
+	}
+}
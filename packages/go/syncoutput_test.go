@@ -0,0 +1,79 @@
+package opentui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyncOutputReply(t *testing.T) {
+	tests := []struct {
+		response  string
+		supported bool
+		ok        bool
+	}{
+		{"\x1b[?2026;2$y", true, true},
+		{"\x1b[?2026;1$y", true, true},
+		{"\x1b[?1;2c\x1b[?2026;0$y", false, true},
+		{"\x1b[?2026;4$y", false, true},
+		{"\x1b[?2027;2$y", false, false},
+		{"\x1b[?2026;2", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		supported, ok := syncOutputReply([]byte(tt.response))
+		if supported != tt.supported || ok != tt.ok {
+			t.Errorf("syncOutputReply(%q) = %v, %v, want %v, %v", tt.response, supported, ok, tt.supported, tt.ok)
+		}
+	}
+
+	var d detectedCapabilities
+	d.parseCapabilityResponse([]byte("\x1b[?2026;2$y"))
+	if !d.syncOutput {
+		t.Error("DECRPM reply should enable synchronized output")
+	}
+}
+
+func TestRenderSynchronizedOutput(t *testing.T) {
+	t.Setenv("KITTY_WINDOW_ID", "1")
+	renderer := NewRenderer(6, 1)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	url := "https://go.dev"
+	draw := func() {
+		next, err := renderer.GetNextBuffer()
+		if err != nil {
+			t.Fatalf("GetNextBuffer failed: %v", err)
+		}
+		next.Clear(Black)
+		next.DrawTextLink("go", url, 2, 0, White, nil, 0)
+	}
+
+	// Enabled but not supported by the terminal
+	renderer.SetSynchronizedOutput(true)
+	draw()
+	if out := captureStdout(t, func() { renderer.Render(true) }); strings.Contains(out, beginSyncOutput) {
+		t.Errorf("markers written without terminal support: %q", out)
+	}
+
+	renderer.ProcessCapabilityResponse([]byte("\x1b[?2026;2$y"))
+	if caps, _ := renderer.GetTerminalCapabilities(); !caps.SupportsSyncOutput {
+		t.Error("SupportsSyncOutput should be set from the DECRPM reply")
+	}
+	draw()
+	out := captureStdout(t, func() { renderer.Render(true) })
+	if strings.Count(out, beginSyncOutput) != 1 || strings.Count(out, endSyncOutput) != 1 {
+		t.Fatalf("expected one begin and one end marker, got %q", out)
+	}
+	if !strings.HasPrefix(out, beginSyncOutput) || !strings.HasSuffix(out, endSyncOutput) || !strings.Contains(out, url) {
+		t.Errorf("markers should bracket the frame, got %q", out)
+	}
+
+	renderer.SetUseThread(true)
+	draw()
+	if out := captureStdout(t, func() { renderer.Render(true) }); strings.Contains(out, beginSyncOutput) {
+		t.Errorf("markers written with threaded rendering: %q", out)
+	}
+}
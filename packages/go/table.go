@@ -0,0 +1,324 @@
+package opentui
+
+import "strings"
+
+// TableCell is a table cell with optional styling.
+// A nil Foreground or Background uses the table defaults.
+type TableCell struct {
+	Text       string
+	Foreground *RGBA
+	Background *RGBA
+	Attributes Attributes
+}
+
+// TableData holds the contents of a table drawn with DrawTable
+type TableData struct {
+	Headers []string
+	Rows    [][]TableCell
+}
+
+// NewTableData builds table data from plain strings.
+func NewTableData(headers []string, rows [][]string) TableData {
+	data := TableData{Headers: headers, Rows: make([][]TableCell, len(rows))}
+	for i, row := range rows {
+		data.Rows[i] = make([]TableCell, len(row))
+		for j, text := range row {
+			data.Rows[i][j] = TableCell{Text: text}
+		}
+	}
+	return data
+}
+
+// ColumnWidthMode selects how the width of a table column is determined
+type ColumnWidthMode uint8
+
+const (
+	ColumnAuto    ColumnWidthMode = iota // Fit the content, sharing the space left by other columns
+	ColumnFixed                          // Value is the width in cells
+	ColumnPercent                        // Value is a percentage of the space available to columns
+)
+
+// TableColumn configures one column of a table
+type TableColumn struct {
+	Mode  ColumnWidthMode
+	Value uint32
+	Align TextAlignment
+}
+
+// TableOptions controls the layout and colors of a table
+type TableOptions struct {
+	Columns          []TableColumn // Per column settings, missing columns use ColumnAuto
+	Foreground       RGBA
+	Background       *RGBA // nil keeps the existing background
+	StripeBackground *RGBA // Background of every second row, nil disables striping
+	HeaderForeground RGBA
+	HeaderBackground *RGBA
+	HeaderAttributes Attributes
+	RowAttributes    Attributes // Attributes of every data row, combined with those of each cell
+	ShowBorders      bool       // Outer border, column separators and a line below the header
+	BorderStyle      BorderStyle
+	BorderColor      RGBA
+}
+
+// DrawTable draws a table inside rect and returns the number of data rows drawn.
+// Content wider than its column is truncated with an ellipsis, and rows that don't fit
+// into rect are left out. Columns are separated by a border or a single space.
+// Column widths are measured in display cells, so wide characters are handled.
+// Striped rows are drawn in black or white when Foreground is not readable on
+// StripeBackground.
+func (b *Buffer) DrawTable(rect Rect, table TableData, opts TableOptions) (uint32, error) {
+	if b.ptr == nil {
+		return 0, closedError("buffer")
+	}
+
+	columns := len(table.Headers)
+	for _, row := range table.Rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 || rect.Width == 0 || rect.Height == 0 {
+		return 0, nil
+	}
+	widths := tableColumnWidths(table, opts, columns, int(rect.Width))
+
+	// Vertical layout
+	hasHeader := len(table.Headers) > 0
+	overhead := 0
+	if hasHeader {
+		overhead++
+	}
+	if opts.ShowBorders {
+		overhead += 2
+		if hasHeader {
+			overhead++
+		}
+	}
+	if int(rect.Height) < overhead {
+		return 0, nil
+	}
+	rows := min(len(table.Rows), int(rect.Height)-overhead)
+
+	left, top := int64(rect.X), int64(rect.Y)
+	right := left + int64(rect.Width)
+	y := top
+	if opts.ShowBorders {
+		y++
+	}
+
+	// Content starts after the left border
+	x := left
+	if opts.ShowBorders {
+		x++
+	}
+	starts := make([]int64, columns)
+	for i, w := range widths {
+		starts[i] = x
+		x += int64(w) + 1
+	}
+
+	drawRow := func(y int64, cells []TableCell, fg RGBA, bg *RGBA, attrs Attributes) {
+		for i := 0; i < columns; i++ {
+			cell := TableCell{}
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			cellFg, cellBg := fg, bg
+			if cell.Foreground != nil {
+				cellFg = *cell.Foreground
+			}
+			if cell.Background != nil {
+				cellBg = cell.Background
+			}
+			align := AlignLeft
+			if i < len(opts.Columns) {
+				align = opts.Columns[i].Align
+			}
+			text := alignCell(cell.Text, widths[i], align)
+			b.drawClusters(text, starts[i], y, left, right, cellFg, cellBg, attrs|cell.Attributes)
+		}
+	}
+
+	if hasHeader {
+		headers := make([]TableCell, len(table.Headers))
+		for i, h := range table.Headers {
+			headers[i] = TableCell{Text: h}
+		}
+		drawRow(y, headers, opts.HeaderForeground, opts.HeaderBackground, opts.HeaderAttributes)
+		y++
+		if opts.ShowBorders {
+			y++
+		}
+	}
+	for i := 0; i < rows; i++ {
+		fg, bg := opts.Foreground, opts.Background
+		if i%2 == 1 && opts.StripeBackground != nil {
+			fg, bg = readableOn(fg, *opts.StripeBackground), opts.StripeBackground
+		}
+		drawRow(y, table.Rows[i], fg, bg, opts.RowAttributes)
+		y++
+	}
+
+	if opts.ShowBorders {
+		horizontal := []int64{top, y}
+		if hasHeader {
+			horizontal = append(horizontal, top+2)
+		}
+		vertical := []int64{left}
+		for i := 1; i < columns; i++ {
+			vertical = append(vertical, starts[i]-1)
+		}
+		vertical = append(vertical, starts[columns-1]+int64(widths[columns-1]))
+		b.drawTableGrid(horizontal, vertical, top, y, right, opts)
+	}
+	return uint32(rows), nil
+}
+
+// tableColumnWidths distributes the width of a table across its columns.
+func tableColumnWidths(table TableData, opts TableOptions, columns, width int) []int {
+	available := width - (columns - 1)
+	if opts.ShowBorders {
+		available = width - (columns + 1)
+	}
+	available = max(available, 0)
+
+	widths := make([]int, columns)
+	natural := make([]int, columns)
+	for i, h := range table.Headers {
+		natural[i] = stringWidth(h)
+	}
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			natural[i] = max(natural[i], stringWidth(cell.Text))
+		}
+	}
+
+	remaining, autoTotal := available, 0
+	for i := range widths {
+		column := TableColumn{}
+		if i < len(opts.Columns) {
+			column = opts.Columns[i]
+		}
+		switch column.Mode {
+		case ColumnFixed:
+			widths[i] = int(column.Value)
+		case ColumnPercent:
+			widths[i] = available * int(min(column.Value, 100)) / 100
+		default:
+			autoTotal += natural[i]
+			continue
+		}
+		remaining -= widths[i]
+	}
+
+	// Auto columns fit their content, or shrink in proportion when there isn't enough room
+	remaining = max(remaining, 0)
+	for i := range widths {
+		if i < len(opts.Columns) && opts.Columns[i].Mode != ColumnAuto {
+			continue
+		}
+		if autoTotal <= remaining {
+			widths[i] = natural[i]
+		} else {
+			widths[i] = natural[i] * remaining / autoTotal
+		}
+	}
+	return widths
+}
+
+// alignCell truncates text to width cells and pads it according to the alignment.
+func alignCell(text string, width int, align TextAlignment) string {
+	text = TruncateToWidth(text, uint32(max(width, 0)), "…")
+	space := width - stringWidth(text)
+	switch align {
+	case AlignCenter:
+		return strings.Repeat(" ", space/2) + text + strings.Repeat(" ", space-space/2)
+	case AlignRight:
+		return strings.Repeat(" ", space) + text
+	default:
+		return text + strings.Repeat(" ", space)
+	}
+}
+
+// drawTableGrid draws the border lines of a table, joining them where they meet.
+func (b *Buffer) drawTableGrid(horizontal, vertical []int64, top, bottom, maxX int64, opts TableOptions) {
+	contains := func(lines []int64, v int64) bool {
+		for _, line := range lines {
+			if line == v {
+				return true
+			}
+		}
+		return false
+	}
+	left, right := vertical[0], vertical[len(vertical)-1]
+
+	draw := func(x, y int64, mask uint8) {
+		if x < 0 || y < 0 || x >= maxX || x > int64(^uint32(0)) || y > int64(^uint32(0)) {
+			return
+		}
+		b.setCell(uint32(x), uint32(y), tableGlyph(opts.BorderStyle, mask), opts.BorderColor, nil, 0)
+	}
+	mask := func(x, y int64) uint8 {
+		var m uint8
+		if contains(vertical, x) {
+			if y > top {
+				m |= connectUp
+			}
+			if y < bottom {
+				m |= connectDown
+			}
+		}
+		if contains(horizontal, y) {
+			if x > left {
+				m |= connectLeft
+			}
+			if x < right {
+				m |= connectRight
+			}
+		}
+		return m
+	}
+
+	for _, y := range horizontal {
+		for x := left; x <= right; x++ {
+			draw(x, y, mask(x, y))
+		}
+	}
+	for _, x := range vertical {
+		for y := top; y <= bottom; y++ {
+			if !contains(horizontal, y) {
+				draw(x, y, mask(x, y))
+			}
+		}
+	}
+}
+
+// tableGlyph returns the border character of a style for a connection mask.
+// Corners and straight lines come from the style's box characters, junctions
+// from the matching line style.
+func tableGlyph(style BorderStyle, mask uint8) rune {
+	chars := style.BoxChars()
+	switch mask {
+	case connectRight | connectDown:
+		return chars[0]
+	case connectHorizontal, connectLeft, connectRight:
+		return chars[1]
+	case connectLeft | connectDown:
+		return chars[2]
+	case connectVertical, connectUp, connectDown:
+		return chars[3]
+	case connectUp | connectRight:
+		return chars[5]
+	case connectUp | connectLeft:
+		return chars[7]
+	}
+
+	switch style {
+	case BorderASCII:
+		return '+'
+	case BorderDouble:
+		return boxGlyphs[LineDouble][mask]
+	case BorderHeavy:
+		return boxGlyphs[LineHeavy][mask]
+	default:
+		return boxGlyphs[LineSingle][mask]
+	}
+}
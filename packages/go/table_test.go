@@ -0,0 +1,102 @@
+package opentui
+
+import "testing"
+
+func tableRect(w, h uint32) Rect {
+	return Rect{Size: Size{Width: w, Height: h}}
+}
+
+func TestDrawTableBorders(t *testing.T) {
+	buffer := newTestBuffer(t, 14, 7)
+	table := NewTableData(
+		[]string{"Name", "Qty"},
+		[][]string{{"apple", "3"}, {"kiwi", "12"}},
+	)
+	opts := TableOptions{
+		ShowBorders: true,
+		Columns:     []TableColumn{{}, {Align: AlignRight}},
+	}
+
+	rows, err := buffer.DrawTable(tableRect(14, 7), table, opts)
+	if err != nil {
+		t.Fatalf("DrawTable failed: %v", err)
+	}
+	if rows != 2 {
+		t.Errorf("drew %d rows, want 2", rows)
+	}
+	expectRows(t, buffer,
+		"┌─────┬───┐   ",
+		"│Name │Qty│   ",
+		"├─────┼───┤   ",
+		"│apple│  3│   ",
+		"│kiwi │ 12│   ",
+		"└─────┴───┘   ",
+		"              ",
+	)
+}
+
+func TestDrawTableClipsRows(t *testing.T) {
+	buffer := newTestBuffer(t, 9, 4)
+	table := NewTableData(nil, [][]string{{"a", "b"}, {"c", "d"}, {"e", "f"}})
+
+	rows, _ := buffer.DrawTable(tableRect(9, 4), table, TableOptions{ShowBorders: true, BorderStyle: BorderASCII})
+	if rows != 2 {
+		t.Errorf("drew %d rows, want 2", rows)
+	}
+	expectRows(t, buffer,
+		"+-+-+    ",
+		"|a|b|    ",
+		"|c|d|    ",
+		"+-+-+    ",
+	)
+}
+
+func TestDrawTableWidths(t *testing.T) {
+	buffer := newTestBuffer(t, 20, 2)
+	table := NewTableData(
+		[]string{"ID", "Description", "Price"},
+		[][]string{{"1", "A rather long description", "9.99"}},
+	)
+	opts := TableOptions{
+		Columns: []TableColumn{
+			{Mode: ColumnFixed, Value: 2},
+			{},
+			{Mode: ColumnPercent, Value: 25, Align: AlignRight},
+		},
+	}
+	buffer.DrawTable(tableRect(20, 2), table, opts)
+
+	// 18 cells for columns: 2 fixed, 4 (25%) for price and 12 left for the description
+	expectRows(t, buffer,
+		"ID Description  Pri…",
+		"1  A rather lo… 9.99",
+	)
+}
+
+func TestDrawTableWideCharacters(t *testing.T) {
+	buffer := newTestBuffer(t, 9, 2)
+	table := NewTableData([]string{"名前", "x"}, [][]string{{"日本語です", "y"}})
+	buffer.DrawTable(tableRect(9, 2), table, TableOptions{Columns: []TableColumn{{Mode: ColumnFixed, Value: 5}}})
+
+	// The stub writes one character per cell, so compare the cell contents by position
+	cell, _ := buffer.GetCell(0, 1)
+	if cell.Char != '日' {
+		t.Errorf("first cell = %q", cell.Char)
+	}
+	if got := alignCell("日本語です", 5, AlignLeft); got != "日本…" {
+		t.Errorf("alignCell truncated to %q", got)
+	}
+}
+
+func TestDrawTableStripes(t *testing.T) {
+	buffer := newTestBuffer(t, 3, 3)
+	table := NewTableData(nil, [][]string{{"a"}, {"b"}, {"c"}})
+	stripe := Gray
+	buffer.DrawTable(tableRect(3, 3), table, TableOptions{Columns: []TableColumn{{Mode: ColumnFixed, Value: 3}}, StripeBackground: &stripe})
+
+	for y, want := range []RGBA{Black, Gray, Black} {
+		if cell, _ := buffer.GetCell(2, uint32(y)); cell.Background != want {
+			t.Errorf("row %d background = %+v, want %+v", y, cell.Background, want)
+		}
+	}
+}
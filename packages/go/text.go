@@ -0,0 +1,191 @@
+package opentui
+
+import (
+	"strings"
+)
+
+// WrapMode controls how text is broken into lines when it exceeds the available width
+type WrapMode uint8
+
+const (
+	WrapWord WrapMode = iota // Break at spaces, falling back to WrapChar for long words
+	WrapChar                 // Break at any character
+	WrapNone                 // Don't wrap, cut off whatever doesn't fit
+)
+
+// DrawTextWrapped draws text into rect, breaking it into lines according to wrap.
+// Explicit newlines always start a new line and wide characters are never split.
+// Lines beyond the bottom of rect are clipped. Returns the number of lines drawn.
+func (b *Buffer) DrawTextWrapped(text string, rect Rect, fg RGBA, bg *RGBA, attrs Attributes, wrap WrapMode) (uint32, error) {
+	if b.ptr == nil {
+		return 0, closedError("buffer")
+	}
+	if rect.Width == 0 || rect.Height == 0 {
+		return 0, nil
+	}
+
+	lines := wrapText(b.expandTabs(text, int64(rect.X)), int(rect.Width), wrap)
+	if uint32(len(lines)) > rect.Height {
+		lines = lines[:rect.Height]
+	}
+
+	left := int64(rect.X)
+	right := left + int64(rect.Width)
+	for i, line := range lines {
+		b.drawClusters(line, left, int64(rect.Y)+int64(i), left, right, fg, bg, attrs)
+	}
+	return uint32(len(lines)), nil
+}
+
+// VerticalAlignment defines vertical alignment options
+type VerticalAlignment uint8
+
+const (
+	AlignTop VerticalAlignment = iota
+	AlignMiddle
+	AlignBottom
+)
+
+// DrawTextAligned draws text positioned inside rect according to the alignments.
+// Each line of multi-line text is aligned horizontally on its own, while the lines
+// are aligned vertically as a block. Anything outside rect is clipped.
+func (b *Buffer) DrawTextAligned(text string, rect Rect, hAlign TextAlignment, vAlign VerticalAlignment, fg RGBA, bg *RGBA, attrs Attributes) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if rect.Width == 0 || rect.Height == 0 {
+		return nil
+	}
+
+	lines := strings.Split(text, "\n")
+	top := int64(rect.Y)
+	switch vAlign {
+	case AlignMiddle:
+		top += (int64(rect.Height) - int64(len(lines))) / 2
+	case AlignBottom:
+		top += int64(rect.Height) - int64(len(lines))
+	}
+
+	left := int64(rect.X)
+	right := left + int64(rect.Width)
+	bottom := int64(rect.Y) + int64(rect.Height)
+	for i, line := range lines {
+		y := top + int64(i)
+		if y < int64(rect.Y) || y >= bottom {
+			continue
+		}
+
+		line = strings.TrimSuffix(line, "\r")
+		line = b.expandTabs(line, left)
+		x := left
+		switch hAlign {
+		case AlignCenter:
+			x += (int64(rect.Width) - int64(stringWidth(line))) / 2
+		case AlignRight:
+			x += int64(rect.Width) - int64(stringWidth(line))
+		}
+		b.drawClusters(line, x, y, left, right, fg, bg, attrs)
+	}
+	return nil
+}
+
+// wrapText splits text into lines no wider than width cells.
+func wrapText(text string, width int, mode WrapMode) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		paragraph = strings.TrimSuffix(paragraph, "\r")
+		switch mode {
+		case WrapNone:
+			lines = append(lines, cutWidth(paragraph, width))
+		case WrapChar:
+			lines = append(lines, wrapChars(paragraph, width)...)
+		default:
+			lines = append(lines, wrapWords(paragraph, width)...)
+		}
+	}
+	return lines
+}
+
+// wrapChars breaks a single line of text at character boundaries.
+// A character wider than width is placed on a line of its own.
+func wrapChars(text string, width int) []string {
+	lines := []string{}
+	start, lineWidth := 0, 0
+	forEachCluster(text, func(offset int, cluster string, cells int) {
+		if lineWidth > 0 && lineWidth+cells > width {
+			lines = append(lines, text[start:offset])
+			start, lineWidth = offset, 0
+		}
+		lineWidth += cells
+	})
+	return append(lines, text[start:])
+}
+
+// wrapWords breaks a single line of text at spaces.
+// The space a line is broken at is dropped, words wider than width are broken with wrapChars.
+func wrapWords(text string, width int) []string {
+	lines := []string{}
+	var line strings.Builder
+	lineWidth, started := 0, false
+
+	for _, word := range strings.Split(text, " ") {
+		wordWidth := stringWidth(word)
+		if started && lineWidth+1+wordWidth <= width {
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lineWidth += 1 + wordWidth
+			continue
+		}
+
+		if started {
+			lines = append(lines, line.String())
+			line.Reset()
+		}
+		if wordWidth > width {
+			chunks := wrapChars(word, width)
+			lines = append(lines, chunks[:len(chunks)-1]...)
+			word = chunks[len(chunks)-1]
+			wordWidth = stringWidth(word)
+		}
+		line.WriteString(word)
+		lineWidth, started = wordWidth, true
+	}
+	return append(lines, line.String())
+}
+
+// cutWidth returns the longest prefix of text that fits in width cells.
+func cutWidth(text string, width int) string {
+	end, lineWidth := 0, 0
+	forEachCluster(text, func(offset int, cluster string, cells int) {
+		if end < offset || lineWidth+cells > width {
+			return
+		}
+		lineWidth += cells
+		end = offset + len(cluster)
+	})
+	return text[:end]
+}
+
+// forEachCluster calls fn for every grapheme cluster of text.
+// offset is the byte offset of the cluster and cells its display width.
+func forEachCluster(text string, fn func(offset int, cluster string, cells int)) {
+	for g := Graphemes(text); g.Next(); {
+		fn(g.Offset(), g.Str(), g.Width())
+	}
+}
+
+// drawClusters draws text starting at column x, placing every character at its display column.
+// Characters not entirely inside the columns [minX, maxX) are skipped.
+func (b *Buffer) drawClusters(text string, x, y, minX, maxX int64, fg RGBA, bg *RGBA, attrs Attributes) {
+	if y < 0 || y > int64(^uint32(0)) {
+		return
+	}
+	forEachCluster(text, func(offset int, cluster string, cells int) {
+		pos := x
+		x += int64(cells)
+		if pos < 0 || pos < minX || x > maxX {
+			return
+		}
+		b.DrawText(cluster, uint32(pos), uint32(y), fg, bg, attrs)
+	})
+}
@@ -0,0 +1,325 @@
+package opentui
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		mode  WrapMode
+		want  []string
+	}{
+		{"the quick brown fox", 10, WrapWord, []string{"the quick", "brown fox"}},
+		{"the quick brown fox", 10, WrapChar, []string{"the quick ", "brown fox"}},
+		{"the quick brown fox", 10, WrapNone, []string{"the quick "}},
+		{"a verylongword b", 5, WrapWord, []string{"a", "veryl", "ongwo", "rd b"}},
+		{"one\ntwo three", 20, WrapWord, []string{"one", "two three"}},
+		{"line\r\n", 20, WrapWord, []string{"line", ""}},
+		{"漢字漢字漢", 5, WrapChar, []string{"漢字", "漢字", "漢"}},
+		{"漢字 漢字", 4, WrapWord, []string{"漢字", "漢字"}},
+		{"漢字漢字", 3, WrapNone, []string{"漢"}},
+		{"", 10, WrapWord, []string{""}},
+	}
+
+	for _, tt := range tests {
+		got := wrapText(tt.text, tt.width, tt.mode)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrapText(%q, %d, %d) = %q, want %q", tt.text, tt.width, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestDrawTextWrapped(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 4)
+
+	rect := Rect{Position{X: 1, Y: 1}, Size{Width: 6, Height: 2}}
+	lines, err := buffer.DrawTextWrapped("hello big world", rect, White, nil, 0, WrapWord)
+	if err != nil {
+		t.Fatalf("DrawTextWrapped failed: %v", err)
+	}
+	if lines != 2 {
+		t.Errorf("DrawTextWrapped used %d lines, want 2", lines)
+	}
+
+	expectRows(t, buffer,
+		"        ",
+		" hello  ",
+		" big    ",
+		"        ",
+	)
+}
+
+func TestDrawTextWrappedWideCharacters(t *testing.T) {
+	buffer := newTestBuffer(t, 5, 3)
+
+	rect := Rect{Size: Size{Width: 5, Height: 3}}
+	lines, _ := buffer.DrawTextWrapped("漢字漢字漢字", rect, White, nil, 0, WrapChar)
+	if lines != 3 {
+		t.Errorf("DrawTextWrapped used %d lines, want 3", lines)
+	}
+
+	// Wide characters occupy two columns and are never split at the right edge
+	row := []rune(bufferRows(t, buffer)[0])
+	if row[0] != '漢' || row[2] != '字' || row[4] != ' ' {
+		t.Errorf("unexpected first row %q", string(row))
+	}
+}
+
+func TestDrawTextAligned(t *testing.T) {
+	// Rows are compared rune by rune, so a wide character is followed by its untouched second cell
+	rect := Rect{Position{X: 1, Y: 0}, Size{Width: 6, Height: 3}}
+	tests := []struct {
+		h    TextAlignment
+		v    VerticalAlignment
+		want []string
+	}{
+		{AlignLeft, AlignTop, []string{" ab     ", " 漢      ", "        "}},
+		{AlignCenter, AlignMiddle, []string{"   ab   ", "   漢    ", "        "}},
+		{AlignRight, AlignBottom, []string{"        ", "     ab ", "     漢  "}},
+	}
+
+	for _, tt := range tests {
+		buffer := newTestBuffer(t, 8, 3)
+		if err := buffer.DrawTextAligned("ab\n漢", rect, tt.h, tt.v, White, nil, 0); err != nil {
+			t.Fatalf("DrawTextAligned failed: %v", err)
+		}
+		expectRows(t, buffer, tt.want...)
+	}
+}
+
+func TestDrawTextAlignedClipping(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 1)
+
+	rect := Rect{Position{X: 1, Y: 0}, Size{Width: 4, Height: 1}}
+	buffer.DrawTextAligned("abcdefgh", rect, AlignCenter, AlignMiddle, White, nil, 0)
+
+	expectRows(t, buffer, " cdef ")
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		text     string
+		col      int64
+		tabWidth uint8
+		want     string
+		wantCol  int64
+	}{
+		{"a\tb", 0, 4, "a   b", 5},
+		{"ab\t", 0, 4, "ab  ", 4},
+		{"\t", 3, 4, " ", 4},
+		{"a\tb\n\tc", 0, 4, "a   b\n    c", 5},
+		{"a\t\tb", 0, 1, "a  b", 4},
+		{"漢\tx", 0, 4, "漢  x", 5},
+		{"no tabs", 2, 4, "no tabs", 9},
+		{"\tx", 0, 0, "        x", 9},
+	}
+
+	for _, tt := range tests {
+		got, col := expandTabs(tt.text, tt.col, 0, tt.tabWidth)
+		if got != tt.want || col != tt.wantCol {
+			t.Errorf("expandTabs(%q, %d, %d) = %q, %d; want %q, %d", tt.text, tt.col, tt.tabWidth, got, col, tt.want, tt.wantCol)
+		}
+	}
+}
+
+func TestDrawTextTabs(t *testing.T) {
+	buffer := newTestBuffer(t, 10, 2)
+	if err := buffer.SetTabWidth(4); err != nil {
+		t.Fatalf("SetTabWidth failed: %v", err)
+	}
+	if err := buffer.SetTabWidth(0); err == nil {
+		t.Error("SetTabWidth(0) should fail")
+	}
+
+	// Stops are measured from the draw origin by default
+	buffer.DrawText("a\tb", 1, 0, White, nil, 0)
+	// and from column 0 of the buffer with absolute stops
+	buffer.SetAbsoluteTabStops(true)
+	buffer.DrawText("a\tb", 1, 1, White, nil, 0)
+
+	expectRows(t, buffer,
+		" a   b    ",
+		" a  b     ",
+	)
+}
+
+func TestDrawTextWrappedTabs(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 3)
+	buffer.SetTabWidth(2)
+
+	rect := Rect{Size: Size{Width: 6, Height: 3}}
+	buffer.DrawTextWrapped("a\tb\tc\tdef", rect, White, nil, 0, WrapWord)
+
+	expectRows(t, buffer,
+		"a b c ",
+		"def   ",
+		"      ",
+	)
+}
+
+func TestTextBufferTabs(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	if err := tb.SetTabWidth(4); err != nil {
+		t.Fatalf("SetTabWidth failed: %v", err)
+	}
+
+	// Tab stops continue across writes and restart after a newline
+	tb.WriteString("ab")
+	tb.WriteString("\tc\n")
+	tb.WriteString("\t")
+	if length, _ := tb.Length(); length != 10 {
+		t.Errorf("length = %d, want 10", length)
+	}
+
+	tb.Reset()
+	tb.WriteString("abc\t")
+	if length, _ := tb.Length(); length != 4 {
+		t.Errorf("length after reset = %d, want 4", length)
+	}
+}
+
+func TestDrawTextLines(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 3)
+
+	lines, err := buffer.DrawTextLines("ab\r\ncd\nef\ngh", 1, 1, White, nil, 0)
+	if err != nil {
+		t.Fatalf("DrawTextLines failed: %v", err)
+	}
+	// The last two lines fall below the buffer
+	if lines != 2 {
+		t.Errorf("DrawTextLines drew %d lines, want 2", lines)
+	}
+	expectRows(t, buffer,
+		"      ",
+		" ab   ",
+		" cd   ",
+	)
+
+	// DrawText honors newlines too, and a trailing newline adds an empty line
+	buffer = newTestBuffer(t, 4, 3)
+	buffer.DrawText("x\ny\n", 2, 0, White, nil, 0)
+	expectRows(t, buffer,
+		"  x ",
+		"  y ",
+		"    ",
+	)
+	if lines, _ := buffer.DrawTextLines("z", 0, 3, White, nil, 0); lines != 0 {
+		t.Errorf("DrawTextLines below the buffer drew %d lines", lines)
+	}
+}
+
+func TestMeasureText(t *testing.T) {
+	tests := []struct {
+		text         string
+		method       uint8
+		width, lines uint32
+	}{
+		{"", WidthMethodUnicode, 0, 1},
+		{"hello", WidthMethodUnicode, 5, 1},
+		{"ab\nlonger\r\nc", WidthMethodUnicode, 6, 3},
+		{"漢字", WidthMethodUnicode, 4, 1},
+		{"a\x1b\x07b", WidthMethodUnicode, 2, 1},
+		{"a\tb", WidthMethodUnicode, 9, 1},
+		{"👩‍👩‍👧", WidthMethodUnicode, 2, 1},
+		{"👩‍👩‍👧", WidthMethodWCWidth, 6, 1},
+		{"é", WidthMethodWCWidth, 1, 1},
+		{"line\n", WidthMethodUnicode, 4, 2},
+	}
+
+	for _, tt := range tests {
+		width, lines := MeasureText(tt.text, tt.method)
+		if width != tt.width || lines != tt.lines {
+			t.Errorf("MeasureText(%q, %d) = %d, %d; want %d, %d", tt.text, tt.method, width, lines, tt.width, tt.lines)
+		}
+	}
+}
+
+func TestMeasureTextWrappedMatchesDrawTextWrapped(t *testing.T) {
+	texts := []string{
+		"",
+		"the quick brown fox jumps over the lazy dog",
+		"a verylongword b\nsecond paragraph\r\n",
+		"漢字漢字漢字 漢字",
+		"tab\tseparated\tcolumns",
+		"👩‍👩‍👧‍👦 family 🇯🇵 flag",
+	}
+
+	for _, text := range texts {
+		for _, mode := range []WrapMode{WrapWord, WrapChar, WrapNone} {
+			for _, width := range []uint32{1, 3, 7, 20} {
+				buffer := newTestBuffer(t, width, 64)
+				drawn, err := buffer.DrawTextWrapped(text, Rect{Size: Size{Width: width, Height: 64}}, White, nil, 0, mode)
+				if err != nil {
+					t.Fatalf("DrawTextWrapped failed: %v", err)
+				}
+				if measured := MeasureTextWrapped(text, width, mode); measured != drawn {
+					t.Errorf("MeasureTextWrapped(%q, %d, %d) = %d, DrawTextWrapped drew %d", text, width, mode, measured, drawn)
+				}
+			}
+		}
+	}
+	if lines := MeasureTextWrapped("text", 0, WrapWord); lines != 0 {
+		t.Errorf("MeasureTextWrapped with zero width = %d, want 0", lines)
+	}
+}
+
+func TestTruncateToWidth(t *testing.T) {
+	tests := []struct {
+		s     string
+		width uint32
+		tail  string
+		want  string
+	}{
+		{"short", 10, "…", "short"},
+		{"exactly", 7, "…", "exactly"},
+		{"too long", 5, "…", "too …"},
+		{"漢字漢字", 5, "…", "漢字…"},
+		{"漢字漢字", 4, "…", "漢 …"},
+		{"too long", 6, "...", "too..."},
+		{"漢字漢字", 3, "", "漢 "},
+		{"abcdef", 2, "...", "ab"},
+		{"ééé", 2, "…", "é…"},
+		{"abc", 0, "…", ""},
+	}
+	for _, tt := range tests {
+		if got := TruncateToWidth(tt.s, tt.width, tt.tail); got != tt.want {
+			t.Errorf("TruncateToWidth(%q, %d, %q) = %q, want %q", tt.s, tt.width, tt.tail, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateToWidthProperties(t *testing.T) {
+	// Code points from ASCII, CJK, combining marks, emoji with ZWJ and modifiers, and flags
+	alphabet := []rune("ab 漢字́‍👩👧🏽🇯🇵")
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		runes := make([]rune, rng.Intn(16))
+		for j := range runes {
+			runes[j] = alphabet[rng.Intn(len(alphabet))]
+		}
+		s := string(runes)
+		maxWidth := uint32(rng.Intn(12))
+
+		got := TruncateToWidth(s, maxWidth, "…")
+		width, _ := MeasureText(got, WidthMethodUnicode)
+		if got != s && width != maxWidth {
+			t.Errorf("TruncateToWidth(%q, %d) = %q with width %d", s, maxWidth, got, width)
+		}
+		if width > maxWidth && got != s {
+			t.Errorf("TruncateToWidth(%q, %d) = %q exceeds the limit", s, maxWidth, got)
+		}
+		if fits := stringWidth(s) <= int(maxWidth); fits != (got == s) {
+			t.Errorf("TruncateToWidth(%q, %d) = %q, string fits: %v", s, maxWidth, got, fits)
+		}
+	}
+}
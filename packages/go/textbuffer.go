@@ -2,17 +2,35 @@ package opentui
 
 /*
 #include "opentui.h"
+#include "color.h"
 #include <stdlib.h>
 */
 import "C"
 import (
+	"runtime"
+	"strings"
+	"unicode/utf8"
 	"unsafe"
 )
 
 // TextBuffer wraps the TextBuffer from the C library.
 // It represents a buffer of styled text fragments with efficient line tracking.
 type TextBuffer struct {
-	ptr *C.TextBuffer
+	ptr       *C.TextBuffer
+	graphemes bool           // true if writes store one cell per grapheme cluster
+	tabWidth  uint8          // distance between tab stops, 0 means defaultTabWidth
+	column    int64          // display column after the last write, used for tab stops
+	newline   bool           // true if any write contained a line break
+	layout    *textLayout    // soft-wrapped lines, nil if not wrapped
+	selection *textSelection // selection set with SetSelection
+	maxBytes  int64          // limit of bytes read by ReadFrom, 0 means no limit
+	bytesRead int64          // bytes read by ReadFrom since the last Reset
+	
+	lineBreaks   uint32 // number of line breaks in the content, exact while maxLines is set
+	maxLines     uint32 // limit of lines kept, 0 means no limit
+	linesDropped uint64 // lines dropped from the front to stay within maxLines
+	
+	highlights map[uint32]cellStyle // styling of cells before HighlightMatches
 }
 
 // NewTextBuffer creates a new text buffer with the specified initial capacity.
@@ -46,7 +64,7 @@ func (tb *TextBuffer) Close() error {
 // Length returns the current length of the text buffer in characters.
 func (tb *TextBuffer) Length() (uint32, error) {
 	if tb.ptr == nil {
-		return 0, newError("text buffer is closed")
+		return 0, closedError("text buffer")
 	}
 	return uint32(C.textBufferGetLength(tb.ptr)), nil
 }
@@ -54,17 +72,54 @@ func (tb *TextBuffer) Length() (uint32, error) {
 // Capacity returns the current capacity of the text buffer.
 func (tb *TextBuffer) Capacity() (uint32, error) {
 	if tb.ptr == nil {
-		return 0, newError("text buffer is closed")
+		return 0, closedError("text buffer")
 	}
 	return uint32(C.textBufferGetCapacity(tb.ptr)), nil
 }
 
 // SetCell sets a single character at the specified index with styling.
-func (tb *TextBuffer) SetCell(index uint32, char rune, fg, bg RGBA, attributes uint16) error {
+func (tb *TextBuffer) SetCell(index uint32, char rune, fg, bg RGBA, attributes Attributes) error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
+	}
+	if da, err := tb.GetDirectAccess(); err == nil && tb.maxLines > 0 && index < da.Length {
+		if da.Chars[index] == '\n' {
+			tb.lineBreaks--
+		}
+		if char == '\n' {
+			tb.lineBreaks++
+		}
 	}
-	C.textBufferSetCell(tb.ptr, C.uint32_t(index), C.uint32_t(char), fg.toCFloat(), bg.toCFloat(), C.uint16_t(attributes))
+	C.textBufferSetCellValue(tb.ptr, C.uint32_t(index), C.uint32_t(char), fg.toC(), bg.toC(), C.uint16_t(attributes))
+	tb.invalidateLayout()
+	return nil
+}
+
+// SetGraphemeClusters sets whether writes treat grapheme clusters as units.
+// The native text buffer holds a single codepoint per cell, so when enabled every
+// cluster is stored as one cell holding its leading codepoint: an emoji ZWJ sequence
+// or flag takes one cell instead of several broken ones, while combining marks and
+// modifiers are dropped. Line widths then match the number of displayed characters.
+func (tb *TextBuffer) SetGraphemeClusters(enabled bool) error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	tb.graphemes = enabled
+	return nil
+}
+
+// SetTabWidth sets the distance between tab stops used when writing text.
+// Tabs are expanded to spaces as they are written, with stops counted from the
+// start of each line, so line widths reported by FinalizeLineInfo include them.
+// The default is 8.
+func (tb *TextBuffer) SetTabWidth(width uint8) error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	if width == 0 {
+		return newError("tab width must be at least 1")
+	}
+	tb.tabWidth = width
 	return nil
 }
 
@@ -72,29 +127,117 @@ func (tb *TextBuffer) SetCell(index uint32, char rune, fg, bg RGBA, attributes u
 // Returns the number of characters written.
 func (tb *TextBuffer) WriteChunk(chunk TextChunk) (uint32, error) {
 	if tb.ptr == nil {
-		return 0, newError("text buffer is closed")
+		return 0, closedError("text buffer")
 	}
 	
-	textPtr, textLen := stringToC(chunk.Text)
+	text, column := expandTabs(chunk.Text, tb.column, 0, tb.tabWidth)
+	tb.column = column
+	if strings.ContainsRune(text, '\n') {
+		tb.newline = true
+	}
+	if tb.graphemes {
+		text = leadingCodepoints(text)
+	}
+	written, err := tb.write(text, chunk)
+	if err != nil {
+		return written, err
+	}
+	return written, tb.dropLines()
+}
+
+// write appends text with the styling of chunk, growing the capacity as needed.
+// Tabs must already be expanded and grapheme clusters reduced.
+func (tb *TextBuffer) write(text string, chunk TextChunk) (uint32, error) {
+	textPtr, textLen := stringToC(text)
 	if textPtr == nil {
 		return 0, nil // Empty string
 	}
+	tb.invalidateLayout()
+	tb.lineBreaks += uint32(strings.Count(text, "\n"))
 	
-	var fgPtr, bgPtr *C.float
-	var attrPtr *C.uint8_t
+	length := uint32(C.textBufferGetLength(tb.ptr))
+	capacity := uint32(C.textBufferGetCapacity(tb.ptr))
+	if needed := length + uint32(utf8.RuneCountInString(text)); needed > capacity {
+		C.textBufferResize(tb.ptr, C.uint32_t(max(needed, capacity*2)))
+	}
 	
-	if chunk.Foreground != nil {
-		fgPtr = chunk.Foreground.toCFloat()
+	fg, hasFg := optionalColor(chunk.Foreground)
+	bg, hasBg := optionalColor(chunk.Background)
+	var attributes C.uint16_t
+	hasAttributes := C.bool(chunk.Attributes != nil)
+	if chunk.Attributes != nil {
+		attributes = C.uint16_t(*chunk.Attributes)
 	}
-	if chunk.Background != nil {
-		bgPtr = chunk.Background.toCFloat()
+	
+	var written uint32
+	err := checkNative(func() {
+		written = uint32(C.textBufferWriteChunkValue(tb.ptr, textPtr, C.uint32_t(textLen), fg, hasFg, bg, hasBg, attributes, hasAttributes))
+	})
+	runtime.KeepAlive(text)
+	return written, err
+}
+
+// WriteChunks appends several text chunks, like calling WriteChunk for each of them
+// but with a single call into the native library. The texts are written at once with
+// the default styling, then the styling set on each chunk is applied to its cells.
+// Returns the number of characters written.
+func (tb *TextBuffer) WriteChunks(chunks []TextChunk) (uint32, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
 	}
-	if chunk.Attributes != nil {
-		attrPtr = (*C.uint8_t)(unsafe.Pointer(chunk.Attributes))
+	
+	var text strings.Builder
+	ends := make([]uint32, len(chunks)) // end cell of each chunk, relative to the first
+	cells := uint32(0)
+	for i, chunk := range chunks {
+		expanded, column := expandTabs(chunk.Text, tb.column, 0, tb.tabWidth)
+		tb.column = column
+		if strings.ContainsRune(expanded, '\n') {
+			tb.newline = true
+		}
+		if tb.graphemes {
+			expanded = leadingCodepoints(expanded)
+		}
+		text.WriteString(expanded)
+		cells += uint32(utf8.RuneCountInString(expanded))
+		ends[i] = cells
+	}
+	
+	start := uint32(C.textBufferGetLength(tb.ptr))
+	written, err := tb.write(text.String(), TextChunk{})
+	if err != nil {
+		return written, err
+	}
+	if written != cells {
+		return written, newError("native text buffer stored an unexpected number of characters")
 	}
 	
-	written := C.textBufferWriteChunk(tb.ptr, textPtr, C.uint32_t(textLen), fgPtr, bgPtr, attrPtr)
-	return uint32(written), nil
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return written, err
+	}
+	from := start
+	for i, chunk := range chunks {
+		to := start + ends[i]
+		if chunk.Foreground != nil {
+			fillCells(da.Foreground[from:to], *chunk.Foreground)
+		}
+		if chunk.Background != nil {
+			fillCells(da.Background[from:to], *chunk.Background)
+		}
+		if chunk.Attributes != nil {
+			fillCells(da.Attributes[from:to], *chunk.Attributes)
+		}
+		from = to
+	}
+	return written, tb.dropLines()
+}
+
+// fillCells sets every element of s to v.
+func fillCells[T any](s []T, v T) {
+	for i := range s {
+		s[i] = v
+	}
 }
 
 // WriteString is a convenience method to write a string with default styling.
@@ -103,7 +246,7 @@ func (tb *TextBuffer) WriteString(text string) (uint32, error) {
 }
 
 // WriteStyledString writes a string with the specified colors and attributes.
-func (tb *TextBuffer) WriteStyledString(text string, fg, bg *RGBA, attributes *uint8) (uint32, error) {
+func (tb *TextBuffer) WriteStyledString(text string, fg, bg *RGBA, attributes *Attributes) (uint32, error) {
 	return tb.WriteChunk(TextChunk{
 		Text:       text,
 		Foreground: fg,
@@ -112,14 +255,196 @@ func (tb *TextBuffer) WriteStyledString(text string, fg, bg *RGBA, attributes *u
 	})
 }
 
+// InsertAt inserts a text chunk before the character at index, shifting the following
+// characters back. Index may equal the length to append. Unset styling falls back to
+// the SetDefault* values like WriteChunk. Line info is stale until FinalizeLineInfo is
+// called again. Returns the number of characters inserted.
+func (tb *TextBuffer) InsertAt(index uint32, chunk TextChunk) (uint32, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
+	}
+	length := uint32(C.textBufferGetLength(tb.ptr))
+	if index > length {
+		return 0, boundsError("index")
+	}
+	if index == length {
+		return tb.WriteChunk(chunk)
+	}
+	
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return 0, err
+	}
+	text, _ := expandTabs(chunk.Text, columnAt(da.Chars, index), 0, tb.tabWidth)
+	if tb.graphemes {
+		text = leadingCodepoints(text)
+	}
+	
+	// Append the chunk so the native buffer styles it, then rotate it into place
+	n, err := tb.write(text, chunk)
+	if n == 0 || err != nil {
+		return 0, err
+	}
+	if da, err = tb.GetDirectAccess(); err != nil {
+		return 0, err
+	}
+	rotateCells(da.Chars[index:], n)
+	rotateCells(da.Foreground[index:], n)
+	rotateCells(da.Background[index:], n)
+	rotateCells(da.Attributes[index:], n)
+	tb.syncColumn(da.Chars)
+	tb.shiftHighlights(index, index, n)
+	return n, tb.dropLines()
+}
+
+// DeleteRange removes the characters in [start, end), shifting the following characters
+// forward. Line info is stale until FinalizeLineInfo is called again.
+func (tb *TextBuffer) DeleteRange(start, end uint32) error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	length := uint32(C.textBufferGetLength(tb.ptr))
+	if start > end || end > length {
+		return boundsError("range")
+	}
+	if start == end {
+		return nil
+	}
+	
+	// The native buffer can only shrink by resetting, so keep the remaining cells,
+	// write placeholders of the new length and restore the cells over them
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	chars := append(append([]uint32(nil), da.Chars[:start]...), da.Chars[end:]...)
+	fg := append(append([]RGBA(nil), da.Foreground[:start]...), da.Foreground[end:]...)
+	bg := append(append([]RGBA(nil), da.Background[:start]...), da.Background[end:]...)
+	attrs := append(append([]Attributes(nil), da.Attributes[:start]...), da.Attributes[end:]...)
+	
+	C.textBufferReset(tb.ptr)
+	tb.invalidateLayout()
+	if _, err := tb.write(strings.Repeat(" ", len(chars)), TextChunk{}); err != nil {
+		return err
+	}
+	if da, err = tb.GetDirectAccess(); err != nil {
+		return err
+	}
+	copy(da.Chars, chars)
+	copy(da.Foreground, fg)
+	copy(da.Background, bg)
+	copy(da.Attributes, attrs)
+	tb.syncColumn(da.Chars)
+	tb.shiftHighlights(start, end, 0)
+	
+	if tb.selection != nil {
+		// Move the selection along with the characters it covers
+		shift := func(i uint32) uint32 {
+			switch {
+			case i >= end:
+				return i - (end - start)
+			case i > start:
+				return start
+			}
+			return i
+		}
+		s := *tb.selection
+		if s.start, s.end = shift(s.start), shift(s.end); s.start == s.end {
+			return tb.ResetSelection()
+		}
+		return tb.SetSelection(s.start, s.end, s.bg, s.fg)
+	}
+	return nil
+}
+
+// GetText returns the content of the text buffer as a string. Tabs are returned as
+// the spaces they were expanded to, and with SetGraphemeClusters only the leading
+// code point of each cluster was stored.
+func (tb *TextBuffer) GetText() (string, error) {
+	if tb.ptr == nil {
+		return "", closedError("text buffer")
+	}
+	return tb.GetTextRange(0, uint32(C.textBufferGetLength(tb.ptr)))
+}
+
+// GetTextRange returns the characters in [start, end) as a string.
+func (tb *TextBuffer) GetTextRange(start, end uint32) (string, error) {
+	if tb.ptr == nil {
+		return "", closedError("text buffer")
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return "", err
+	}
+	if start > end || end > da.Length {
+		return "", boundsError("range")
+	}
+	return charsToString(da.Chars[start:end]), nil
+}
+
+// GetSelectedText returns the text of the range set with SetSelection, or "" if
+// there is no selection.
+func (tb *TextBuffer) GetSelectedText() (string, error) {
+	if tb.ptr == nil {
+		return "", closedError("text buffer")
+	}
+	if tb.selection == nil {
+		return "", nil
+	}
+	length := uint32(C.textBufferGetLength(tb.ptr))
+	start, end := min(tb.selection.start, tb.selection.end), max(tb.selection.start, tb.selection.end)
+	return tb.GetTextRange(min(start, length), min(end, length))
+}
+
+// charsToString encodes text buffer characters as UTF-8. Empty cells, stored as 0,
+// are skipped.
+func charsToString(chars []uint32) string {
+	var text strings.Builder
+	text.Grow(len(chars))
+	for _, c := range chars {
+		if c != 0 {
+			text.WriteRune(rune(c))
+		}
+	}
+	return text.String()
+}
+
+// syncColumn recomputes the write position and line break tracking after an edit.
+func (tb *TextBuffer) syncColumn(chars []uint32) {
+	tb.column = columnAt(chars, uint32(len(chars)))
+	tb.lineBreaks = 0
+	for _, c := range chars {
+		if c == '\n' {
+			tb.lineBreaks++
+		}
+	}
+	tb.newline = tb.lineBreaks > 0
+}
+
+// columnAt returns the display column of the character at index.
+func columnAt(chars []uint32, index uint32) int64 {
+	var col int64
+	for i := index; i > 0 && chars[i-1] != '\n'; i-- {
+		col += int64(runeWidth(rune(chars[i-1])))
+	}
+	return col
+}
+
+// rotateCells moves the last n elements of s to its front.
+func rotateCells[T any](s []T, n uint32) {
+	tail := append([]T(nil), s[uint32(len(s))-n:]...)
+	copy(s[n:], s[:uint32(len(s))-n])
+	copy(s, tail)
+}
+
 // Concat concatenates this text buffer with another text buffer.
 // Returns a new text buffer containing the combined content.
 func (tb *TextBuffer) Concat(other *TextBuffer) (*TextBuffer, error) {
 	if tb.ptr == nil {
-		return nil, newError("text buffer is closed")
+		return nil, closedError("text buffer")
 	}
 	if other == nil || other.ptr == nil {
-		return nil, newError("other text buffer is nil or closed")
+		return nil, kindError(ErrClosed, "other text buffer is nil or closed")
 	}
 	
 	resultPtr := C.textBufferConcat(tb.ptr, other.ptr)
@@ -127,7 +452,14 @@ func (tb *TextBuffer) Concat(other *TextBuffer) (*TextBuffer, error) {
 		return nil, newError("failed to concatenate text buffers")
 	}
 	
-	result := &TextBuffer{ptr: resultPtr}
+	result := &TextBuffer{ptr: resultPtr, graphemes: tb.graphemes, tabWidth: tb.tabWidth, column: other.column, newline: tb.newline || other.newline,
+		lineBreaks: tb.lineBreaks + other.lineBreaks}
+	if !other.newline {
+		result.column += tb.column
+	}
+	if tb.layout != nil {
+		result.layout = &textLayout{width: tb.layout.width, mode: tb.layout.mode}
+	}
 	setFinalizer(result, func(tb *TextBuffer) { tb.Close() })
 	return result, nil
 }
@@ -135,7 +467,7 @@ func (tb *TextBuffer) Concat(other *TextBuffer) (*TextBuffer, error) {
 // Resize changes the capacity of the text buffer.
 func (tb *TextBuffer) Resize(newLength uint32) error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	C.textBufferResize(tb.ptr, C.uint32_t(newLength))
 	return nil
@@ -144,78 +476,73 @@ func (tb *TextBuffer) Resize(newLength uint32) error {
 // Reset clears the text buffer content while preserving capacity.
 func (tb *TextBuffer) Reset() error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	C.textBufferReset(tb.ptr)
+	tb.invalidateLayout()
+	tb.column = 0
+	tb.newline = false
+	tb.bytesRead = 0
+	tb.lineBreaks = 0
+	tb.linesDropped = 0
+	clear(tb.highlights)
 	return nil
 }
 
 // SetSelection sets a text selection range with optional highlighting colors.
 func (tb *TextBuffer) SetSelection(start, end uint32, bgColor, fgColor *RGBA) error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	
-	var bgPtr, fgPtr *C.float
-	if bgColor != nil {
-		bgPtr = bgColor.toCFloat()
-	}
-	if fgColor != nil {
-		fgPtr = fgColor.toCFloat()
-	}
-	
-	C.textBufferSetSelection(tb.ptr, C.uint32_t(start), C.uint32_t(end), bgPtr, fgPtr)
+	bg, hasBg := optionalColor(bgColor)
+	fg, hasFg := optionalColor(fgColor)
+	C.textBufferSetSelectionValue(tb.ptr, C.uint32_t(start), C.uint32_t(end), bg, hasBg, fg, hasFg)
+	tb.selection = &textSelection{start: start, end: end, bg: bgColor, fg: fgColor}
 	return nil
 }
 
 // ResetSelection clears any active text selection.
 func (tb *TextBuffer) ResetSelection() error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	C.textBufferResetSelection(tb.ptr)
+	tb.selection = nil
 	return nil
 }
 
 // SetDefaultForeground sets the default foreground color for new text.
 func (tb *TextBuffer) SetDefaultForeground(fg *RGBA) error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
-	}
-	
-	var fgPtr *C.float
-	if fg != nil {
-		fgPtr = fg.toCFloat()
+		return closedError("text buffer")
 	}
 	
-	C.textBufferSetDefaultFg(tb.ptr, fgPtr)
+	color, ok := optionalColor(fg)
+	C.textBufferSetDefaultFgValue(tb.ptr, color, ok)
 	return nil
 }
 
 // SetDefaultBackground sets the default background color for new text.
 func (tb *TextBuffer) SetDefaultBackground(bg *RGBA) error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
-	}
-	
-	var bgPtr *C.float
-	if bg != nil {
-		bgPtr = bg.toCFloat()
+		return closedError("text buffer")
 	}
 	
-	C.textBufferSetDefaultBg(tb.ptr, bgPtr)
+	color, ok := optionalColor(bg)
+	C.textBufferSetDefaultBgValue(tb.ptr, color, ok)
 	return nil
 }
 
 // SetDefaultAttributes sets the default text attributes for new text.
-func (tb *TextBuffer) SetDefaultAttributes(attributes *uint8) error {
+func (tb *TextBuffer) SetDefaultAttributes(attributes *Attributes) error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	
-	var attrPtr *C.uint8_t
+	var attrPtr *C.uint16_t
 	if attributes != nil {
-		attrPtr = (*C.uint8_t)(unsafe.Pointer(attributes))
+		attrPtr = (*C.uint16_t)(unsafe.Pointer(attributes))
 	}
 	
 	C.textBufferSetDefaultAttributes(tb.ptr, attrPtr)
@@ -225,7 +552,7 @@ func (tb *TextBuffer) SetDefaultAttributes(attributes *uint8) error {
 // ResetDefaults clears all default styling settings.
 func (tb *TextBuffer) ResetDefaults() error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	C.textBufferResetDefaults(tb.ptr)
 	return nil
@@ -233,28 +560,47 @@ func (tb *TextBuffer) ResetDefaults() error {
 
 // FinalizeLineInfo processes the text buffer to generate line information.
 // This should be called after adding text and before querying line information.
+// Wrapped lines are recomputed as well, which picks up changes made through
+// GetDirectAccess.
 func (tb *TextBuffer) FinalizeLineInfo() error {
 	if tb.ptr == nil {
-		return newError("text buffer is closed")
+		return closedError("text buffer")
 	}
 	C.textBufferFinalizeLineInfo(tb.ptr)
+	tb.invalidateLayout()
 	return nil
 }
 
 // LineCount returns the number of lines in the text buffer.
-// FinalizeLineInfo must be called first.
+// FinalizeLineInfo must be called first, unless the text buffer is wrapped.
 func (tb *TextBuffer) LineCount() (uint32, error) {
 	if tb.ptr == nil {
-		return 0, newError("text buffer is closed")
+		return 0, closedError("text buffer")
+	}
+	if tb.layout != nil {
+		lines, _, err := tb.wrappedLines()
+		return uint32(len(lines)), err
 	}
 	return uint32(C.textBufferGetLineCount(tb.ptr)), nil
 }
 
 // GetLineInfo returns information about all lines in the text buffer.
-// FinalizeLineInfo must be called first.
+// FinalizeLineInfo must be called first, unless the text buffer is wrapped, in which
+// case the soft-wrapped lines are returned.
 func (tb *TextBuffer) GetLineInfo() ([]LineInfo, error) {
 	if tb.ptr == nil {
-		return nil, newError("text buffer is closed")
+		return nil, closedError("text buffer")
+	}
+	if tb.layout != nil {
+		wrapped, _, err := tb.wrappedLines()
+		if err != nil {
+			return nil, err
+		}
+		lines := make([]LineInfo, len(wrapped))
+		for i, line := range wrapped {
+			lines[i] = line.LineInfo
+		}
+		return lines, nil
 	}
 	
 	lineCount := uint32(C.textBufferGetLineCount(tb.ptr))
@@ -283,7 +629,7 @@ func (tb *TextBuffer) GetLineInfo() ([]LineInfo, error) {
 // This is an advanced feature for performance-critical operations.
 func (tb *TextBuffer) GetDirectAccess() (*TextBufferDirectAccess, error) {
 	if tb.ptr == nil {
-		return nil, newError("text buffer is closed")
+		return nil, closedError("text buffer")
 	}
 	
 	length := uint32(C.textBufferGetLength(tb.ptr))
@@ -292,7 +638,7 @@ func (tb *TextBuffer) GetDirectAccess() (*TextBufferDirectAccess, error) {
 			Chars:      []uint32{},
 			Foreground: []RGBA{},
 			Background: []RGBA{},
-			Attributes: []uint16{},
+			Attributes: []Attributes{},
 			Length:     0,
 		}, nil
 	}
@@ -306,24 +652,24 @@ func (tb *TextBuffer) GetDirectAccess() (*TextBufferDirectAccess, error) {
 		Chars:      cArrayToSlice((*uint32)(charPtr), int(length)),
 		Foreground: cArrayToSlice((*RGBA)(unsafe.Pointer(fgPtr)), int(length)),
 		Background: cArrayToSlice((*RGBA)(unsafe.Pointer(bgPtr)), int(length)),
-		Attributes: cArrayToSlice((*uint16)(attrPtr), int(length)),
+		Attributes: cArrayToSlice((*Attributes)(attrPtr), int(length)),
 		Length:     length,
 	}, nil
 }
 
 // TextBufferDirectAccess provides direct access to text buffer internal arrays.
 type TextBufferDirectAccess struct {
-	Chars      []uint32 // Character codes (Unicode code points)
-	Foreground []RGBA   // Foreground colors
-	Background []RGBA   // Background colors
-	Attributes []uint16 // Text attributes
-	Length     uint32   // Buffer length
+	Chars      []uint32     // Character codes (Unicode code points)
+	Foreground []RGBA       // Foreground colors
+	Background []RGBA       // Background colors
+	Attributes []Attributes // Text attributes; the top three bits mark cells using the default styles
+	Length     uint32       // Buffer length
 }
 
 // GetChar returns the character at the specified index.
 func (da *TextBufferDirectAccess) GetChar(index uint32) (rune, error) {
 	if index >= da.Length {
-		return 0, newError("index out of bounds")
+		return 0, boundsError("index")
 	}
 	return rune(da.Chars[index]), nil
 }
@@ -331,24 +677,24 @@ func (da *TextBufferDirectAccess) GetChar(index uint32) (rune, error) {
 // SetChar sets the character at the specified index.
 func (da *TextBufferDirectAccess) SetChar(index uint32, char rune) error {
 	if index >= da.Length {
-		return newError("index out of bounds")
+		return boundsError("index")
 	}
 	da.Chars[index] = uint32(char)
 	return nil
 }
 
 // GetStyle returns the styling at the specified index.
-func (da *TextBufferDirectAccess) GetStyle(index uint32) (RGBA, RGBA, uint16, error) {
+func (da *TextBufferDirectAccess) GetStyle(index uint32) (RGBA, RGBA, Attributes, error) {
 	if index >= da.Length {
-		return RGBA{}, RGBA{}, 0, newError("index out of bounds")
+		return RGBA{}, RGBA{}, 0, boundsError("index")
 	}
 	return da.Foreground[index], da.Background[index], da.Attributes[index], nil
 }
 
 // SetStyle sets the styling at the specified index.
-func (da *TextBufferDirectAccess) SetStyle(index uint32, fg, bg RGBA, attributes uint16) error {
+func (da *TextBufferDirectAccess) SetStyle(index uint32, fg, bg RGBA, attributes Attributes) error {
 	if index >= da.Length {
-		return newError("index out of bounds")
+		return boundsError("index")
 	}
 	da.Foreground[index] = fg
 	da.Background[index] = bg
@@ -0,0 +1,308 @@
+package opentui
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// refCell is a character of the reference model for text buffer edits
+type refCell struct {
+	char rune
+	fg   RGBA
+}
+
+func TestTextBufferInsertDelete(t *testing.T) {
+	tb := NewTextBuffer(4, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	defaultFg := Yellow
+	tb.SetDefaultForeground(&defaultFg)
+
+	var model []refCell
+	colors := []RGBA{Red, Green, Blue}
+	rng := rand.New(rand.NewSource(1))
+	for step := 0; step < 300; step++ {
+		if len(model) > 0 && rng.Intn(3) == 0 {
+			start := rng.Intn(len(model) + 1)
+			end := start + rng.Intn(len(model)-start+1)
+			if err := tb.DeleteRange(uint32(start), uint32(end)); err != nil {
+				t.Fatalf("DeleteRange(%d, %d) failed: %v", start, end, err)
+			}
+			model = append(model[:start], model[end:]...)
+		} else {
+			index := rng.Intn(len(model) + 1)
+			var text strings.Builder
+			for n := rng.Intn(6); n > 0; n-- {
+				text.WriteByte("ab\n"[rng.Intn(3)])
+			}
+			chunk := TextChunk{Text: text.String()}
+			fg := defaultFg
+			if rng.Intn(2) == 0 {
+				fg = colors[rng.Intn(len(colors))]
+				chunk.Foreground = &fg
+			}
+			n, err := tb.InsertAt(uint32(index), chunk)
+			if err != nil {
+				t.Fatalf("InsertAt(%d) failed: %v", index, err)
+			}
+			if int(n) != len(chunk.Text) {
+				t.Fatalf("InsertAt(%d, %q) = %d", index, chunk.Text, n)
+			}
+			inserted := make([]refCell, 0, n)
+			for _, r := range chunk.Text {
+				inserted = append(inserted, refCell{r, fg})
+			}
+			model = append(model[:index], append(inserted, model[index:]...)...)
+		}
+
+		da, err := tb.GetDirectAccess()
+		if err != nil {
+			t.Fatalf("GetDirectAccess failed: %v", err)
+		}
+		if int(da.Length) != len(model) {
+			t.Fatalf("step %d: length %d, want %d", step, da.Length, len(model))
+		}
+		for i, cell := range model {
+			if rune(da.Chars[i]) != cell.char || da.Foreground[i] != cell.fg {
+				t.Fatalf("step %d: cell %d = %q %v, want %q %v", step, i, rune(da.Chars[i]), da.Foreground[i], cell.char, cell.fg)
+			}
+		}
+
+		if step%10 != 0 {
+			continue
+		}
+		var want []LineInfo
+		line := LineInfo{}
+		for i, cell := range model {
+			if cell.char == '\n' {
+				want = append(want, line)
+				line = LineInfo{StartIndex: uint32(i + 1)}
+			} else {
+				line.Width++
+			}
+		}
+		want = append(want, line)
+		tb.FinalizeLineInfo()
+		lines, _ := tb.GetLineInfo()
+		if len(lines) != len(want) {
+			t.Fatalf("step %d: %d lines, want %d", step, len(lines), len(want))
+		}
+		for i := range want {
+			if lines[i] != want[i] {
+				t.Fatalf("step %d: line %d = %+v, want %+v", step, i, lines[i], want[i])
+			}
+		}
+	}
+
+	if _, err := tb.InsertAt(uint32(len(model)+1), TextChunk{Text: "x"}); err == nil {
+		t.Error("InsertAt past the end should fail")
+	}
+	if err := tb.DeleteRange(1, 0); err == nil {
+		t.Error("DeleteRange with start after end should fail")
+	}
+}
+
+func TestTextBufferInsertTabs(t *testing.T) {
+	tb := NewTextBuffer(16, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.SetTabWidth(4)
+	tb.WriteString("ab\ncd")
+	// The tab stop is counted from the start of the line the text lands on
+	tb.InsertAt(4, TextChunk{Text: "\t"})
+	da, _ := tb.GetDirectAccess()
+	var got strings.Builder
+	for _, c := range da.Chars {
+		got.WriteRune(rune(c))
+	}
+	if got.String() != "ab\nc   d" {
+		t.Errorf("got %q, want %q", got.String(), "ab\nc   d")
+	}
+}
+
+func TestTextBufferGetText(t *testing.T) {
+	tb := NewTextBuffer(4, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	for _, s := range []string{"", "hello, world", "line one\nline two\n", "漢字とかな", "👩‍👩‍👧 🇯🇵 ❤️ é"} {
+		tb.Reset()
+		tb.WriteString(s)
+		if got, err := tb.GetText(); err != nil || got != s {
+			t.Errorf("GetText() = %q, %v, want %q", got, err, s)
+		}
+	}
+
+	tb.Reset()
+	tb.WriteString("abc漢字def")
+	if got, _ := tb.GetTextRange(2, 5); got != "c漢字" {
+		t.Errorf("GetTextRange(2, 5) = %q, want %q", got, "c漢字")
+	}
+	if _, err := tb.GetTextRange(4, 9); err == nil {
+		t.Error("GetTextRange past the end should fail")
+	}
+
+	if got, _ := tb.GetSelectedText(); got != "" {
+		t.Errorf("GetSelectedText without a selection = %q", got)
+	}
+	tb.SetSelection(3, 6, nil, nil)
+	if got, _ := tb.GetSelectedText(); got != "漢字d" {
+		t.Errorf("GetSelectedText() = %q, want %q", got, "漢字d")
+	}
+	tb.ResetSelection()
+	if got, _ := tb.GetSelectedText(); got != "" {
+		t.Errorf("GetSelectedText after ResetSelection = %q", got)
+	}
+}
+
+func TestDrawTextBufferAttributes(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 3)
+	tb := NewTextBuffer(16, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	// Bits past the first byte, which used to be cut off on the way to the buffer
+	wide := AttrBold | AttrStrike | 1<<8 | 1<<12
+	tb.WriteStyledString("ab", nil, nil, &wide)
+	tb.WriteString("\ncd")
+	if err := tb.SetCell(4, 'd', White, Black, wide); err != nil {
+		t.Fatalf("SetCell failed: %v", err)
+	}
+	if _, _, attrs, _ := mustDirectAccess(t, tb).GetStyle(4); attrs != wide {
+		t.Fatalf("SetCell stored attributes %#x, want %#x", attrs, wide)
+	}
+
+	if err := buffer.DrawTextBuffer(tb, 0, 0, nil); err != nil {
+		t.Fatalf("DrawTextBuffer failed: %v", err)
+	}
+	// A wrapped text buffer is drawn by the Go layout instead
+	tb.WrapToWidth(8, WrapWord)
+	if err := buffer.DrawTextBuffer(tb, 4, 1, nil); err != nil {
+		t.Fatalf("DrawTextBuffer failed: %v", err)
+	}
+
+	da, _ := buffer.GetDirectAccess()
+	for _, cell := range []struct{ x, y uint32 }{{0, 0}, {1, 0}, {1, 1}, {4, 1}, {5, 1}, {5, 2}} {
+		if attrs := da.Attributes[cell.y*da.Width+cell.x]; attrs != wide {
+			t.Errorf("cell (%d, %d) has attributes %#x, want %#x", cell.x, cell.y, attrs, wide)
+		}
+	}
+	if attrs := da.Attributes[1*da.Width]; attrs != 0 {
+		t.Errorf("unstyled cell has attributes %#x", attrs)
+	}
+}
+
+func mustDirectAccess(t *testing.T, tb *TextBuffer) *TextBufferDirectAccess {
+	t.Helper()
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		t.Fatalf("GetDirectAccess failed: %v", err)
+	}
+	return da
+}
+
+// styledChunks returns n chunks cycling through texts and styles, some left unset.
+func styledChunks(n int) []TextChunk {
+	texts := []string{"func", " ", "main", "()", " {\n\t", "fmt", ".", "Println", "(\"漢字 👍\")", "\n}"}
+	colors := []RGBA{Red, Green, Blue, Yellow}
+	bold := AttrBold
+	chunks := make([]TextChunk, n)
+	for i := range chunks {
+		chunks[i].Text = texts[i%len(texts)]
+		if i%3 != 0 {
+			chunks[i].Foreground = &colors[i%len(colors)]
+		}
+		if i%4 == 1 {
+			chunks[i].Background = &colors[(i+1)%len(colors)]
+		}
+		if i%5 == 2 {
+			chunks[i].Attributes = &bold
+		}
+	}
+	return chunks
+}
+
+func TestTextBufferWriteChunks(t *testing.T) {
+	sequential := NewTextBuffer(8, WidthMethodUnicode)
+	batched := NewTextBuffer(8, WidthMethodUnicode)
+	if sequential == nil || batched == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer sequential.Close()
+	defer batched.Close()
+
+	for _, tb := range []*TextBuffer{sequential, batched} {
+		defaultFg, italic := Cyan, AttrItalic
+		tb.SetDefaultForeground(&defaultFg)
+		tb.SetDefaultAttributes(&italic)
+		tb.SetTabWidth(4)
+		tb.WriteString("prefix\t")
+	}
+
+	chunks := styledChunks(100)
+	want := uint32(0)
+	for _, chunk := range chunks {
+		n, _ := sequential.WriteChunk(chunk)
+		want += n
+	}
+	got, err := batched.WriteChunks(chunks)
+	if err != nil {
+		t.Fatalf("WriteChunks failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("WriteChunks wrote %d characters, want %d", got, want)
+	}
+
+	a, _ := sequential.GetDirectAccess()
+	b, _ := batched.GetDirectAccess()
+	if a.Length != b.Length {
+		t.Fatalf("length %d, want %d", b.Length, a.Length)
+	}
+	for i := uint32(0); i < a.Length; i++ {
+		if a.Chars[i] != b.Chars[i] || a.Foreground[i] != b.Foreground[i] ||
+			a.Background[i] != b.Background[i] || a.Attributes[i] != b.Attributes[i] {
+			t.Fatalf("cell %d differs from sequential writes", i)
+		}
+	}
+}
+
+func BenchmarkTextBufferWriteChunk(b *testing.B) {
+	benchmarkWrite(b, func(tb *TextBuffer, chunks []TextChunk) {
+		for _, chunk := range chunks {
+			tb.WriteChunk(chunk)
+		}
+	})
+}
+
+func BenchmarkTextBufferWriteChunks(b *testing.B) {
+	benchmarkWrite(b, func(tb *TextBuffer, chunks []TextChunk) {
+		tb.WriteChunks(chunks)
+	})
+}
+
+func benchmarkWrite(b *testing.B, write func(*TextBuffer, []TextChunk)) {
+	tb := NewTextBuffer(16384, WidthMethodUnicode)
+	if tb == nil {
+		b.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	chunks := styledChunks(1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tb.Reset()
+		write(tb, chunks)
+	}
+}
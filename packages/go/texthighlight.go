@@ -0,0 +1,106 @@
+package opentui
+
+import (
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// cellStyle is the styling of a text buffer cell
+type cellStyle struct {
+	fg, bg RGBA
+	attrs  Attributes
+}
+
+// HighlightMatches styles every match of re in the text buffer with the given colors
+// and attributes, leaving the styling unchanged where they are nil. The styling the
+// cells had before is kept, so ClearHighlights can restore it; calling
+// HighlightMatches again adds to the highlights. Empty matches are skipped. Returns
+// the number of matches highlighted.
+func (tb *TextBuffer) HighlightMatches(re *regexp.Regexp, fg, bg *RGBA, attrs *Attributes) (int, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
+	}
+	if re == nil {
+		return 0, newError("regexp is nil")
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return 0, err
+	}
+
+	// offsets[i] is the byte offset of cell i in text
+	text := make([]byte, 0, len(da.Chars))
+	offsets := make([]int, len(da.Chars)+1)
+	for i, c := range da.Chars {
+		offsets[i] = len(text)
+		text = utf8.AppendRune(text, rune(c))
+	}
+	offsets[len(da.Chars)] = len(text)
+	cellAt := func(offset int) uint32 {
+		return uint32(sort.SearchInts(offsets, offset))
+	}
+
+	count := 0
+	for _, match := range re.FindAllIndex(text, -1) {
+		start, end := cellAt(match[0]), cellAt(match[1])
+		if start == end {
+			continue
+		}
+		count++
+		for i := start; i < end; i++ {
+			if _, saved := tb.highlights[i]; !saved {
+				if tb.highlights == nil {
+					tb.highlights = make(map[uint32]cellStyle)
+				}
+				tb.highlights[i] = cellStyle{da.Foreground[i], da.Background[i], da.Attributes[i]}
+			}
+			if fg != nil {
+				da.Foreground[i] = *fg
+			}
+			if bg != nil {
+				da.Background[i] = *bg
+			}
+			if attrs != nil {
+				da.Attributes[i] = *attrs
+			}
+		}
+	}
+	return count, nil
+}
+
+// ClearHighlights restores the styling of all cells highlighted by HighlightMatches.
+func (tb *TextBuffer) ClearHighlights() error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	for i, style := range tb.highlights {
+		if i < da.Length {
+			da.Foreground[i], da.Background[i], da.Attributes[i] = style.fg, style.bg, style.attrs
+		}
+	}
+	clear(tb.highlights)
+	return nil
+}
+
+// shiftHighlights moves the saved styles of highlighted cells after an edit that
+// replaced the cells [start, end) with n new ones.
+func (tb *TextBuffer) shiftHighlights(start, end, n uint32) {
+	if len(tb.highlights) == 0 {
+		return
+	}
+	shifted := make(map[uint32]cellStyle, len(tb.highlights))
+	for i, style := range tb.highlights {
+		switch {
+		case i < start:
+			shifted[i] = style
+		case i >= end:
+			shifted[i-end+start+n] = style
+		}
+	}
+	tb.highlights = shifted
+}
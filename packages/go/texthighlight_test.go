@@ -0,0 +1,55 @@
+package opentui
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestTextBufferHighlightMatches(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.WriteStyledString("漢字 ERROR ", &White, &Black, nil)
+	tb.WriteStyledString("ok\néé error", &Green, &Black, nil)
+	before, _ := tb.GetDirectAccess()
+	original := append([]RGBA(nil), before.Foreground...)
+
+	count, err := tb.HighlightMatches(regexp.MustCompile(`(?i)error`), &Red, nil, nil)
+	if err != nil {
+		t.Fatalf("HighlightMatches failed: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("HighlightMatches found %d matches, want 2", count)
+	}
+	da, _ := tb.GetDirectAccess()
+	// Cell indices, not byte offsets, despite the multi-byte characters before the matches
+	for i := uint32(0); i < da.Length; i++ {
+		highlighted := (i >= 3 && i < 8) || (i >= 15 && i < 20)
+		if got := da.Foreground[i] == Red; got != highlighted {
+			t.Errorf("cell %d highlighted = %v, want %v", i, got, highlighted)
+		}
+		if da.Background[i] != Black {
+			t.Errorf("cell %d background changed", i)
+		}
+	}
+
+	// Stacked highlights and edits still restore the original styling
+	bold := AttrBold
+	tb.HighlightMatches(regexp.MustCompile(`ok`), nil, &Blue, &bold)
+	tb.DeleteRange(0, 3)
+	tb.ClearHighlights()
+	da, _ = tb.GetDirectAccess()
+	for i := uint32(0); i < da.Length; i++ {
+		if da.Foreground[i] != original[i+3] || da.Attributes[i] != 0 {
+			t.Fatalf("cell %d was not restored", i)
+		}
+	}
+
+	// Empty matches are skipped and don't loop
+	if count, _ := tb.HighlightMatches(regexp.MustCompile(`x*`), &Red, nil, nil); count != 0 {
+		t.Errorf("empty matches counted: %d", count)
+	}
+}
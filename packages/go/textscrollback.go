@@ -0,0 +1,58 @@
+package opentui
+
+// SetMaxLines bounds the text buffer to n lines, like a terminal's scrollback: once
+// writes take it past the limit, whole lines are dropped from the front and the
+// selection moves along with the remaining characters. 0 means no limit.
+//
+// Lines are dropped in batches so that writing stays proportional to the text
+// written, which lets the text buffer hold up to n/8 lines more than n in between.
+func (tb *TextBuffer) SetMaxLines(n uint32) error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	tb.maxLines = n
+	if n > 0 {
+		// Recount, as changes through GetDirectAccess are not tracked
+		da, err := tb.GetDirectAccess()
+		if err != nil {
+			return err
+		}
+		tb.syncColumn(da.Chars)
+	}
+	return tb.dropLines()
+}
+
+// LinesDropped returns the number of lines dropped from the front to stay within
+// SetMaxLines since the last Reset.
+func (tb *TextBuffer) LinesDropped() (uint64, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
+	}
+	return tb.linesDropped, nil
+}
+
+// dropLines drops lines from the front once there are a batch more than maxLines.
+func (tb *TextBuffer) dropLines() error {
+	if tb.maxLines == 0 || tb.lineBreaks+1 <= tb.maxLines+max(tb.maxLines/8, 1) {
+		return nil
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	drop := tb.lineBreaks + 1 - tb.maxLines
+	breaks := uint32(0)
+	for i, c := range da.Chars {
+		if c != '\n' {
+			continue
+		}
+		if breaks++; breaks == drop {
+			if err := tb.DeleteRange(0, uint32(i)+1); err != nil {
+				return err
+			}
+			tb.linesDropped += uint64(drop)
+			break
+		}
+	}
+	return nil
+}
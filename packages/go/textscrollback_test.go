@@ -0,0 +1,83 @@
+package opentui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTextBufferMaxLines(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.SetMaxLines(16)
+	for i := 0; i < 100; i++ {
+		tb.WriteString(fmt.Sprintf("line %d\n", i))
+
+		// Never more than a batch of lines over the limit
+		text, _ := tb.GetText()
+		if lines := strings.Count(text, "\n") + 1; lines > 16+2 {
+			t.Fatalf("after line %d the buffer holds %d lines", i, lines)
+		}
+	}
+
+	text, _ := tb.GetText()
+	first := strings.SplitN(text, "\n", 2)[0]
+	dropped, _ := tb.LinesDropped()
+	if first != fmt.Sprintf("line %d", dropped) {
+		t.Errorf("first line %q after dropping %d lines", first, dropped)
+	}
+	if !strings.HasSuffix(text, "line 99\n") {
+		t.Errorf("last line missing: %q", text)
+	}
+
+	// Lowering the limit drops lines right away
+	tb.SetMaxLines(4)
+	text, _ = tb.GetText()
+	if text != "line 97\nline 98\nline 99\n" {
+		t.Errorf("GetText() = %q after lowering the limit", text)
+	}
+	tb.Reset()
+	if dropped, _ := tb.LinesDropped(); dropped != 0 {
+		t.Errorf("LinesDropped() = %d after Reset", dropped)
+	}
+}
+
+func TestTextBufferMaxLinesSelection(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.WriteString("a\nbb\ncc\n")
+	tb.SetSelection(5, 7, &Blue, nil) // "cc"
+	tb.SetMaxLines(2)
+	if got, _ := tb.GetSelectedText(); got != "cc" {
+		t.Errorf("selection moved to %q, want %q", got, "cc")
+	}
+
+	// A selection inside the dropped lines is cleared
+	tb.SetSelection(0, 1, &Blue, nil)
+	tb.WriteString("dd\nee\n")
+	if tb.selection != nil {
+		t.Errorf("selection of dropped lines should be cleared, got %+v", *tb.selection)
+	}
+}
+
+func BenchmarkTextBufferMaxLines(b *testing.B) {
+	tb := NewTextBuffer(0, WidthMethodUnicode)
+	if tb == nil {
+		b.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	tb.SetMaxLines(10000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tb.WriteString("2024-01-01T00:00:00 INFO request served\n")
+	}
+}
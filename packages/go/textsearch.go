@@ -0,0 +1,98 @@
+package opentui
+
+import (
+	"unicode"
+)
+
+// Find returns the index of the first occurrence of needle at or after index from,
+// in characters of the text buffer as used by SetSelection and SetCell. With
+// caseInsensitive set, characters are compared under Unicode simple case folding.
+// An empty needle or a start past the end is not found.
+func (tb *TextBuffer) Find(needle string, from uint32, caseInsensitive bool) (uint32, bool, error) {
+	if tb.ptr == nil {
+		return 0, false, closedError("text buffer")
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return 0, false, err
+	}
+	pattern := searchPattern(needle, caseInsensitive)
+	if len(pattern) == 0 || from >= da.Length {
+		return 0, false, nil
+	}
+	index, found := findChars(da.Chars, pattern, from, caseInsensitive)
+	return index, found, nil
+}
+
+// FindAll returns the indices of all non-overlapping occurrences of needle, compared
+// case sensitively. An empty needle has no occurrences.
+func (tb *TextBuffer) FindAll(needle string) ([]uint32, error) {
+	if tb.ptr == nil {
+		return nil, closedError("text buffer")
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return nil, err
+	}
+	pattern := searchPattern(needle, false)
+	var matches []uint32
+	if len(pattern) == 0 {
+		return matches, nil
+	}
+	for from := uint32(0); from < da.Length; {
+		index, found := findChars(da.Chars, pattern, from, false)
+		if !found {
+			break
+		}
+		matches = append(matches, index)
+		from = index + uint32(len(pattern))
+	}
+	return matches, nil
+}
+
+// searchPattern returns the characters of needle, case folded if requested.
+func searchPattern(needle string, fold bool) []uint32 {
+	pattern := make([]uint32, 0, len(needle))
+	for _, r := range needle {
+		if fold {
+			r = foldRune(r)
+		}
+		pattern = append(pattern, uint32(r))
+	}
+	return pattern
+}
+
+// findChars returns the first index at or after from where chars matches pattern.
+func findChars(chars, pattern []uint32, from uint32, fold bool) (uint32, bool) {
+	if uint32(len(pattern)) > uint32(len(chars)) {
+		return 0, false
+	}
+	last := uint32(len(chars) - len(pattern))
+	for i := from; i <= last; i++ {
+		match := true
+		for j, p := range pattern {
+			c := chars[i+uint32(j)]
+			if fold {
+				c = uint32(foldRune(rune(c)))
+			}
+			if c != p {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// foldRune maps r to the smallest rune of its simple case folding orbit, so that
+// runes that fold to each other map to the same rune.
+func foldRune(r rune) rune {
+	smallest := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		smallest = min(smallest, f)
+	}
+	return smallest
+}
@@ -0,0 +1,62 @@
+package opentui
+
+import (
+	"testing"
+)
+
+func TestTextBufferFind(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	tb.WriteString("Error: disk ERROR\nerror ΣΊΣΥΦΟΣ")
+
+	tests := []struct {
+		needle          string
+		from            uint32
+		caseInsensitive bool
+		index           uint32
+		found           bool
+	}{
+		{"ERROR", 0, false, 12, true},
+		{"error", 0, false, 18, true},
+		{"error", 0, true, 0, true},
+		{"error", 1, true, 12, true},
+		{"error", 13, true, 18, true},
+		{"error", 19, true, 0, false},
+		// Final sigma folds like the other sigmas
+		{"σίσυφος", 0, true, 24, true},
+		{"missing", 0, true, 0, false},
+		{"", 0, false, 0, false},
+		{"e", 100, false, 0, false},
+	}
+	for _, tt := range tests {
+		index, found, err := tb.Find(tt.needle, tt.from, tt.caseInsensitive)
+		if err != nil {
+			t.Fatalf("Find failed: %v", err)
+		}
+		if index != tt.index || found != tt.found {
+			t.Errorf("Find(%q, %d, %v) = %d, %v, want %d, %v", tt.needle, tt.from, tt.caseInsensitive, index, found, tt.index, tt.found)
+		}
+	}
+
+	matches, err := tb.FindAll("or")
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	want := []uint32{3, 21}
+	if len(matches) != len(want) || matches[0] != want[0] || matches[1] != want[1] {
+		t.Errorf("FindAll(\"or\") = %v, want %v", matches, want)
+	}
+	if matches, _ := tb.FindAll("aa"); len(matches) != 0 {
+		t.Errorf("FindAll(\"aa\") = %v, want none", matches)
+	}
+
+	// Matches never overlap
+	tb.Reset()
+	tb.WriteString("aaaa")
+	if matches, _ := tb.FindAll("aa"); len(matches) != 2 || matches[1] != 2 {
+		t.Errorf("FindAll(\"aa\") = %v, want [0 2]", matches)
+	}
+}
@@ -0,0 +1,107 @@
+package opentui
+
+import (
+	"sort"
+)
+
+// IndexAt returns the index of the character shown at column col of a line, as
+// reported by GetLineInfo, so FinalizeLineInfo must have been called unless the text
+// buffer is wrapped. A column on the second half of a wide character maps to that
+// character, and a column past the end of the line to the end of the line.
+func (tb *TextBuffer) IndexAt(line, col uint32) (uint32, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
+	}
+	lines, err := tb.GetLineInfo()
+	if err != nil {
+		return 0, err
+	}
+	if line >= uint32(len(lines)) {
+		return 0, boundsError("line")
+	}
+	chars, start, err := tb.lineChars(lines, line)
+	if err != nil {
+		return 0, err
+	}
+
+	x := uint32(0)
+	for _, c := range textClusters(chars, tb.graphemes) {
+		if c.hard {
+			break
+		}
+		if col < x+c.width {
+			return start + c.start, nil
+		}
+		x += c.width
+	}
+	// Clamp to the end of the line, in front of its line break
+	end := start + uint32(len(chars))
+	if len(chars) > 0 && (chars[len(chars)-1] == '\n' || chars[len(chars)-1] == '\r') {
+		end--
+		if end > start && chars[end-start-1] == '\r' {
+			end--
+		}
+	}
+	return end, nil
+}
+
+// PositionOf returns the line and column of the character at index, the inverse of
+// IndexAt. The length of the text buffer is a valid index, positioned after the last
+// character.
+func (tb *TextBuffer) PositionOf(index uint32) (line, col uint32, err error) {
+	if tb.ptr == nil {
+		return 0, 0, closedError("text buffer")
+	}
+	lines, err := tb.GetLineInfo()
+	if err != nil {
+		return 0, 0, err
+	}
+	length, err := tb.Length()
+	if err != nil {
+		return 0, 0, err
+	}
+	if index > length || len(lines) == 0 {
+		return 0, 0, boundsError("index")
+	}
+	line = uint32(sort.Search(len(lines), func(i int) bool { return lines[i].StartIndex > index })) - 1
+	chars, start, err := tb.lineChars(lines, line)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, c := range textClusters(chars, tb.graphemes) {
+		if start+c.start+c.cells > index {
+			break
+		}
+		col += c.width
+	}
+	return line, col, nil
+}
+
+// SelectRange selects the characters from (line1, col1) up to (line2, col2), in
+// either order, with optional highlighting colors. Positions are mapped with IndexAt.
+func (tb *TextBuffer) SelectRange(line1, col1, line2, col2 uint32, bg, fg *RGBA) error {
+	start, err := tb.IndexAt(line1, col1)
+	if err != nil {
+		return err
+	}
+	end, err := tb.IndexAt(line2, col2)
+	if err != nil {
+		return err
+	}
+	return tb.SetSelection(min(start, end), max(start, end), bg, fg)
+}
+
+// lineChars returns the characters of a line up to the start of the next line and
+// the index of its first character.
+func (tb *TextBuffer) lineChars(lines []LineInfo, line uint32) ([]uint32, uint32, error) {
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return nil, 0, err
+	}
+	start, end := lines[line].StartIndex, da.Length
+	if line+1 < uint32(len(lines)) {
+		end = lines[line+1].StartIndex
+	}
+	start, end = min(start, da.Length), min(end, da.Length)
+	return da.Chars[start:end], start, nil
+}
@@ -0,0 +1,84 @@
+package opentui
+
+import (
+	"testing"
+)
+
+func TestTextBufferPositions(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	tb.WriteString("ab漢字c\nxy\n")
+	tb.FinalizeLineInfo()
+
+	tests := []struct {
+		line, col uint32
+		index     uint32
+	}{
+		{0, 0, 0},
+		{0, 2, 2},
+		{0, 3, 2}, // second half of 漢
+		{0, 4, 3},
+		{0, 6, 4},
+		{0, 7, 5}, // past the end clamps before the line break
+		{0, 99, 5},
+		{1, 1, 7},
+		{1, 5, 8},
+		{2, 0, 9},
+	}
+	for _, tt := range tests {
+		index, err := tb.IndexAt(tt.line, tt.col)
+		if err != nil {
+			t.Fatalf("IndexAt(%d, %d) failed: %v", tt.line, tt.col, err)
+		}
+		if index != tt.index {
+			t.Errorf("IndexAt(%d, %d) = %d, want %d", tt.line, tt.col, index, tt.index)
+		}
+	}
+	if _, err := tb.IndexAt(3, 0); err == nil {
+		t.Error("IndexAt past the last line should fail")
+	}
+
+	positions := [][2]uint32{{0, 0}, {0, 1}, {0, 2}, {0, 4}, {0, 6}, {0, 7}, {1, 0}, {1, 1}, {1, 2}, {2, 0}}
+	for index, want := range positions {
+		line, col, err := tb.PositionOf(uint32(index))
+		if err != nil {
+			t.Fatalf("PositionOf(%d) failed: %v", index, err)
+		}
+		if line != want[0] || col != want[1] {
+			t.Errorf("PositionOf(%d) = %d, %d, want %v", index, line, col, want)
+		}
+	}
+	if _, _, err := tb.PositionOf(10); err == nil {
+		t.Error("PositionOf past the end should fail")
+	}
+}
+
+func TestTextBufferSelectRange(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	tb.WriteString("hello world\nsecond line")
+	tb.FinalizeLineInfo()
+
+	// Dragging backwards selects the same range
+	if err := tb.SelectRange(1, 6, 0, 6, &Blue, nil); err != nil {
+		t.Fatalf("SelectRange failed: %v", err)
+	}
+	if got, _ := tb.GetSelectedText(); got != "world\nsecond" {
+		t.Errorf("selected %q, want %q", got, "world\nsecond")
+	}
+
+	// Positions follow the wrapped lines
+	tb.WrapToWidth(6, WrapWord)
+	if index, _ := tb.IndexAt(1, 0); index != 6 {
+		t.Errorf("IndexAt(1, 0) on wrapped text = %d, want 6", index)
+	}
+	if line, col, _ := tb.PositionOf(8); line != 1 || col != 2 {
+		t.Errorf("PositionOf(8) on wrapped text = %d, %d, want 1, 2", line, col)
+	}
+}
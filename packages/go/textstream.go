@@ -0,0 +1,112 @@
+package opentui
+
+import (
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// readChunkSize is the size of the reads ReadFrom makes
+	readChunkSize = 32 * 1024
+	// lineInfoInterval is the number of bytes ReadFrom reads between line info updates
+	lineInfoInterval = 64 * 1024
+)
+
+var _ io.ReaderFrom = (*TextBuffer)(nil)
+
+// SetMaxBytes limits how many bytes ReadFrom reads into the text buffer until the
+// next Reset, 0 means no limit. Once the limit is reached ReadFrom returns an error
+// and leaves the rest of the reader unread, so a writing process blocks instead of
+// the buffer growing without bound.
+func (tb *TextBuffer) SetMaxBytes(maxBytes int64) error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	if maxBytes < 0 {
+		return newError("max bytes must not be negative")
+	}
+	tb.maxBytes = maxBytes
+	return nil
+}
+
+// ReadFrom appends everything read from r to the text buffer with the default styling,
+// until EOF or an error. UTF-8 sequences split across reads are kept together and
+// "\r\n" is stored as "\n". Line info is updated every 64 KiB and when ReadFrom
+// returns, so the content can be drawn while a long stream is still being read.
+// Returns the number of bytes read.
+func (tb *TextBuffer) ReadFrom(r io.Reader) (int64, error) {
+	if tb.ptr == nil {
+		return 0, closedError("text buffer")
+	}
+
+	buf := make([]byte, readChunkSize)
+	var pending []byte // incomplete UTF-8 sequence or "\r" left over from the last read
+	var total, sinceLineInfo int64
+	defer tb.FinalizeLineInfo()
+
+	for {
+		size := len(buf)
+		if tb.maxBytes > 0 {
+			remaining := tb.maxBytes - tb.bytesRead
+			if remaining <= 0 {
+				if _, err := tb.writeStream(pending, true); err != nil {
+					return total, err
+				}
+				return total, newError("text buffer reached its max bytes")
+			}
+			size = int(min(int64(size), remaining))
+		}
+
+		n, err := r.Read(buf[:size])
+		total += int64(n)
+		tb.bytesRead += int64(n)
+		sinceLineInfo += int64(n)
+
+		var werr error
+		pending, werr = tb.writeStream(append(pending, buf[:n]...), err != nil)
+		if werr != nil {
+			return total, werr
+		}
+		if sinceLineInfo >= lineInfoInterval {
+			tb.FinalizeLineInfo()
+			sinceLineInfo = 0
+		}
+
+		if err == io.EOF {
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// writeStream writes data read by ReadFrom and returns the bytes held back until
+// more data arrives: an incomplete UTF-8 sequence or a trailing "\r". With final set
+// everything is written. Returns the error of the write, if any.
+func (tb *TextBuffer) writeStream(data []byte, final bool) ([]byte, error) {
+	var held []byte
+	if !final {
+		cut := len(data)
+		// Back up to the start of a trailing sequence that is not complete yet
+		for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(data[i]) {
+				if !utf8.FullRune(data[i:]) {
+					cut = i
+				}
+				break
+			}
+		}
+		if cut > 0 && data[cut-1] == '\r' {
+			cut--
+		}
+		data, held = data[:cut], data[cut:]
+	}
+	if len(data) > 0 {
+		if _, err := tb.WriteString(string(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")))); err != nil {
+			return nil, err
+		}
+	}
+	return append([]byte(nil), held...), nil
+}
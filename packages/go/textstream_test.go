@@ -0,0 +1,84 @@
+package opentui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestTextBufferReadFrom(t *testing.T) {
+	tb := NewTextBuffer(16, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	// One byte at a time splits every multi-byte sequence and every "\r\n"
+	input := "héllo\r\nwörld 漢字 👩‍👩‍👧\r\nlast\r"
+	n, err := tb.ReadFrom(iotest.OneByteReader(strings.NewReader(input)))
+	if err != nil || n != int64(len(input)) {
+		t.Fatalf("ReadFrom = %d, %v, want %d", n, err, len(input))
+	}
+	want := "héllo\nwörld 漢字 👩‍👩‍👧\nlast\r"
+	if got, _ := tb.GetText(); got != want {
+		t.Errorf("GetText() = %q, want %q", got, want)
+	}
+	// Line info is up to date without calling FinalizeLineInfo
+	if count, _ := tb.LineCount(); count != 3 {
+		t.Errorf("LineCount() = %d, want 3", count)
+	}
+}
+
+func TestTextBufferReadFromLargeLog(t *testing.T) {
+	tb := NewTextBuffer(0, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	var log strings.Builder
+	lines := 0
+	for log.Len() < 4<<20 {
+		fmt.Fprintf(&log, "2024-01-01T00:00:%02d INFO request %d served in %dms ✓\r\n", lines%60, lines, lines%97)
+		lines++
+	}
+	n, err := tb.ReadFrom(strings.NewReader(log.String()))
+	if err != nil || n != int64(log.Len()) {
+		t.Fatalf("ReadFrom = %d, %v, want %d", n, err, log.Len())
+	}
+	if count, _ := tb.LineCount(); count != uint32(lines+1) {
+		t.Errorf("LineCount() = %d, want %d", count, lines+1)
+	}
+	if got, _ := tb.GetText(); got != strings.ReplaceAll(log.String(), "\r\n", "\n") {
+		t.Error("streamed text does not match the log")
+	}
+}
+
+func TestTextBufferMaxBytes(t *testing.T) {
+	tb := NewTextBuffer(16, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.SetMaxBytes(6)
+	reader := strings.NewReader("first\nsecond\n")
+	n, err := tb.ReadFrom(reader)
+	if err == nil || n != 6 {
+		t.Fatalf("ReadFrom = %d, %v, want 6 and an error", n, err)
+	}
+	if got, _ := tb.GetText(); got != "first\n" {
+		t.Errorf("GetText() = %q, want %q", got, "first\n")
+	}
+
+	// The rest of the reader is left for after the buffer was drained
+	tb.Reset()
+	tb.SetMaxBytes(0)
+	if _, err := tb.ReadFrom(reader); err != nil {
+		t.Fatalf("ReadFrom failed: %v", err)
+	}
+	if got, _ := tb.GetText(); got != "second\n" {
+		t.Errorf("GetText() = %q, want %q", got, "second\n")
+	}
+}
@@ -0,0 +1,141 @@
+package opentui
+
+/*
+#include "opentui.h"
+*/
+import "C"
+
+// LineRange returns the characters [startIndex, endIndex) of count lines starting at
+// firstLine, with the lines as reported by GetLineInfo, so FinalizeLineInfo must have
+// been called unless the text buffer is wrapped. The window is clipped to the
+// existing lines. Only the two lines bounding the window are looked up, so the cost
+// does not depend on the size of the text buffer.
+func (tb *TextBuffer) LineRange(firstLine, count uint32) (startIndex, endIndex uint32, err error) {
+	if tb.ptr == nil {
+		return 0, 0, closedError("text buffer")
+	}
+	lines, start, err := tb.lineStarts()
+	if err != nil {
+		return 0, 0, err
+	}
+	if firstLine >= lines {
+		return 0, 0, boundsError("line")
+	}
+	return start(firstLine), start(firstLine + min(count, lines-firstLine)), nil
+}
+
+// DrawTextBufferRegion draws lineCount lines of a text buffer starting at firstLine,
+// with firstLine at row y, like a viewport scrolled to firstLine. Lines are the ones
+// reported by GetLineInfo, so FinalizeLineInfo must have been called unless the
+// text buffer is wrapped. Only the lines inside the window and the clip rect are
+// visited, so the cost depends on what is visible rather than the size of the text
+// buffer.
+func (b *Buffer) DrawTextBufferRegion(textBuffer *TextBuffer, x, y int32, firstLine, lineCount uint32, clipRect *ClipRect) error {
+	if b.ptr == nil {
+		return closedError("buffer")
+	}
+	if textBuffer == nil || textBuffer.ptr == nil {
+		return kindError(ErrClosed, "text buffer is nil or closed")
+	}
+	return b.drawTextLines(textBuffer, x, y, firstLine, lineCount, clipRect)
+}
+
+// lineStarts returns the number of lines of a text buffer and a function returning the
+// index of the first character of a line, or the length for the line after the last.
+func (tb *TextBuffer) lineStarts() (uint32, func(line uint32) uint32, error) {
+	length := uint32(C.textBufferGetLength(tb.ptr))
+	if tb.layout != nil {
+		lines, _, err := tb.wrappedLines()
+		if err != nil {
+			return 0, nil, err
+		}
+		return uint32(len(lines)), func(line uint32) uint32 {
+			if line >= uint32(len(lines)) {
+				return length
+			}
+			return lines[line].StartIndex
+		}, nil
+	}
+
+	count := uint32(C.textBufferGetLineCount(tb.ptr))
+	starts := cArrayToSlice((*uint32)(C.textBufferGetLineStartsPtr(tb.ptr)), int(count))
+	return count, func(line uint32) uint32 {
+		if line >= count {
+			return length
+		}
+		return min(starts[line], length)
+	}, nil
+}
+
+// drawTextLines draws lineCount lines of a text buffer starting at firstLine in Go,
+// visiting only the lines inside the clip rect. Wrapped lines are cut at the wrap
+// width, which hides the spaces hanging past it.
+func (b *Buffer) drawTextLines(tb *TextBuffer, x, y int32, firstLine, lineCount uint32, clipRect *ClipRect) error {
+	lines, start, err := tb.lineStarts()
+	if err != nil {
+		return err
+	}
+	da, err := tb.GetDirectAccess()
+	if err != nil {
+		return err
+	}
+	bufferWidth, bufferHeight, err := b.Size()
+	if err != nil {
+		return err
+	}
+	minX, minY := int64(0), int64(0)
+	maxX, maxY := int64(bufferWidth), int64(bufferHeight)
+	if clipRect != nil {
+		minX, minY = max(minX, int64(clipRect.X)), max(minY, int64(clipRect.Y))
+		maxX = min(maxX, int64(clipRect.X)+int64(clipRect.Width))
+		maxY = min(maxY, int64(clipRect.Y)+int64(clipRect.Height))
+	}
+	width := uint32(0)
+	if tb.layout != nil {
+		width = tb.layout.width
+	}
+
+	// Rows of the window that are inside the clip rect
+	first := max(minY-int64(y), 0)
+	last := min(maxY-int64(y), int64(lineCount), int64(lines)-int64(firstLine))
+	for n := first; n < last; n++ {
+		line := firstLine + uint32(n)
+		row := int64(y) + n
+		from, to := start(line), start(line+1)
+
+		col := uint32(0)
+		for _, c := range textClusters(da.Chars[from:to], tb.graphemes) {
+			if c.hard {
+				break
+			}
+			if c.width == 0 {
+				continue
+			}
+			if width > 0 && col > 0 && col+c.width > width {
+				break // Hanging spaces and whatever WrapNone cuts off
+			}
+			cellX := int64(x) + int64(col)
+			col += c.width
+			if cellX < minX || cellX+int64(c.width) > maxX {
+				continue
+			}
+
+			i := from + c.start
+			fg, bg := da.Foreground[i], da.Background[i]
+			if s := tb.selection; s != nil && i >= s.start && i < s.end {
+				if s.fg != nil {
+					fg = *s.fg
+				}
+				if s.bg != nil {
+					bg = *s.bg
+				}
+			}
+			if c.cells == 1 && c.width == 1 {
+				b.SetCellWithAlphaBlending(uint32(cellX), uint32(row), rune(da.Chars[i]), fg, bg, da.Attributes[i]&attrMask)
+				continue
+			}
+			b.SetCellGrapheme(uint32(cellX), uint32(row), charsToString(da.Chars[i:i+c.cells]), fg, bg, da.Attributes[i]&attrMask)
+		}
+	}
+	return nil
+}
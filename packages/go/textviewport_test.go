@@ -0,0 +1,80 @@
+package opentui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTextBufferLineRange(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	tb.WriteString("zero\none\ntwo\nthree")
+	tb.FinalizeLineInfo()
+
+	tests := []struct {
+		first, count uint32
+		start, end   uint32
+	}{
+		{0, 1, 0, 5},
+		{1, 2, 5, 13},
+		{2, 10, 9, 18},
+		{3, 1, 13, 18},
+	}
+	for _, tt := range tests {
+		start, end, err := tb.LineRange(tt.first, tt.count)
+		if err != nil {
+			t.Fatalf("LineRange(%d, %d) failed: %v", tt.first, tt.count, err)
+		}
+		if start != tt.start || end != tt.end {
+			t.Errorf("LineRange(%d, %d) = %d, %d, want %d, %d", tt.first, tt.count, start, end, tt.start, tt.end)
+		}
+	}
+	if _, _, err := tb.LineRange(4, 1); err == nil {
+		t.Error("LineRange past the last line should fail")
+	}
+}
+
+func TestDrawTextBufferRegion(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 4)
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	defer tb.Close()
+	tb.WriteString("zero\none\ntwo\nthree\n漢字")
+	tb.FinalizeLineInfo()
+
+	if err := buffer.DrawTextBufferRegion(tb, 1, 0, 2, 3, &ClipRect{X: 0, Y: 1, Width: 8, Height: 3}); err != nil {
+		t.Fatalf("DrawTextBufferRegion failed: %v", err)
+	}
+	// Row 0 is clipped, so the window starts showing at its second line
+	expectRows(t, buffer,
+		"        ",
+		" three  ",
+		" 漢 字    ",
+		"        ",
+	)
+}
+
+func BenchmarkDrawTextBufferRegion(b *testing.B) {
+	for _, lines := range []int{50000, 500000} {
+		b.Run(fmt.Sprintf("lines=%d", lines), func(b *testing.B) {
+			buffer := newTestBuffer(b, 120, 40)
+			tb := NewTextBuffer(uint32(lines*48), WidthMethodUnicode)
+			defer tb.Close()
+			var text strings.Builder
+			for i := 0; i < lines; i++ {
+				fmt.Fprintf(&text, "%08d INFO request served in 12ms\n", i)
+			}
+			tb.WriteString(text.String())
+			tb.FinalizeLineInfo()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				first := uint32(i*40) % uint32(lines-40)
+				buffer.DrawTextBufferRegion(tb, 0, 0, first, 40, nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package opentui
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// textCluster is a grapheme cluster of a text buffer, spanning one or more cells
+type textCluster struct {
+	start uint32 // index of the first cell
+	cells uint32 // number of cells
+	width uint32 // display width
+	space bool   // true for spaces, which are allowed to hang past the wrap width
+	hard  bool   // true for line breaks
+}
+
+// wrapLine is a soft-wrapped line of a text buffer
+type wrapLine struct {
+	LineInfo
+	cluster int // index of the first cluster of the line
+}
+
+// textLayout is the soft-wrapped view of a text buffer's content
+type textLayout struct {
+	width    uint32
+	mode     WrapMode
+	clusters []textCluster // nil when the content changed since they were computed
+	lines    []wrapLine    // nil when the clusters or the width changed
+}
+
+// textSelection is the selection of a text buffer, kept for drawing wrapped text
+type textSelection struct {
+	start, end uint32
+	bg, fg     *RGBA
+}
+
+// WrapToWidth soft-wraps the text buffer to width cells, so that GetLineInfo and
+// LineCount report the wrapped lines and DrawTextBuffer draws them. The content is
+// not changed and stays wrapped across edits. Wide characters and grapheme clusters
+// are never split, and with WrapWord the spaces a line is broken at hang past the
+// end of the line. Width 0 turns wrapping off again.
+//
+// The text is segmented once per edit, so changing the width only reflows the lines.
+func (tb *TextBuffer) WrapToWidth(width uint32, mode WrapMode) error {
+	if tb.ptr == nil {
+		return closedError("text buffer")
+	}
+	if width == 0 {
+		tb.layout = nil
+		return nil
+	}
+	if tb.layout == nil {
+		tb.layout = &textLayout{}
+	}
+	if tb.layout.width != width || tb.layout.mode != mode {
+		tb.layout.width, tb.layout.mode = width, mode
+		tb.layout.lines = nil
+	}
+	return nil
+}
+
+// invalidateLayout drops the wrapped lines after the content changed.
+func (tb *TextBuffer) invalidateLayout() {
+	if tb.layout != nil {
+		tb.layout.clusters, tb.layout.lines = nil, nil
+	}
+}
+
+// wrappedLines returns the soft-wrapped lines, reflowing them if they are stale.
+func (tb *TextBuffer) wrappedLines() ([]wrapLine, []textCluster, error) {
+	l := tb.layout
+	if l.lines != nil {
+		return l.lines, l.clusters, nil
+	}
+	if l.clusters == nil {
+		da, err := tb.GetDirectAccess()
+		if err != nil {
+			return nil, nil, err
+		}
+		l.clusters = textClusters(da.Chars, tb.graphemes)
+	}
+	l.lines = wrapClusters(l.clusters, l.width, l.mode)
+	return l.lines, l.clusters, nil
+}
+
+// textClusters segments the cells of a text buffer into grapheme clusters. With
+// graphemes set every cell already holds a whole cluster.
+func textClusters(chars []uint32, graphemes bool) []textCluster {
+	clusters := make([]textCluster, 0, len(chars))
+	if graphemes {
+		for i, c := range chars {
+			clusters = append(clusters, newTextCluster(uint32(i), 1, uint32(runeWidth(rune(c))), rune(c)))
+		}
+		return clusters
+	}
+
+	var text strings.Builder
+	text.Grow(len(chars))
+	for _, c := range chars {
+		text.WriteRune(rune(c))
+	}
+	cell := uint32(0)
+	for g := Graphemes(text.String()); g.Next(); {
+		first, _ := utf8.DecodeRuneInString(g.Str())
+		cells := uint32(utf8.RuneCountInString(g.Str()))
+		clusters = append(clusters, newTextCluster(cell, cells, uint32(g.Width()), first))
+		cell += cells
+	}
+	return clusters
+}
+
+// newTextCluster returns the cluster of cells starting with rune first.
+func newTextCluster(start, cells, width uint32, first rune) textCluster {
+	return textCluster{start: start, cells: cells, width: width, space: first == ' ', hard: first == '\n' || first == '\r'}
+}
+
+// wrapClusters breaks clusters into lines no wider than width cells. Line breaks
+// always start a new line, and a cluster wider than width gets a line of its own.
+func wrapClusters(clusters []textCluster, width uint32, mode WrapMode) []wrapLine {
+	lines := []wrapLine{}
+	line := wrapLine{}
+	// With WrapWord, breakAt is the first cluster after the last run of spaces and
+	// breakWidth the width of the line before those spaces
+	breakAt, breakWidth, breakEnd := -1, uint32(0), uint32(0)
+
+	startLine := func(cluster int) {
+		lines = append(lines, line)
+		line = wrapLine{cluster: cluster}
+		if cluster < len(clusters) {
+			line.StartIndex = clusters[cluster].start
+		}
+		breakAt = -1
+	}
+
+	for i, c := range clusters {
+		switch {
+		case c.hard:
+			startLine(i + 1)
+			continue
+		case c.space && mode == WrapWord:
+			if breakAt != i {
+				breakWidth = line.Width
+			}
+			line.Width += c.width
+			breakAt, breakEnd = i+1, line.Width
+			continue
+		}
+
+		if mode != WrapNone && line.Width+c.width > width {
+			if breakAt >= 0 && breakWidth > 0 {
+				// Break after the spaces, which hang at the end of the line
+				rest := line.Width - breakEnd
+				line.Width = breakWidth
+				startLine(breakAt)
+				line.Width = rest
+			}
+			if line.Width > 0 && line.Width+c.width > width {
+				startLine(i)
+			}
+		}
+		line.Width += c.width
+	}
+	return append(lines, line)
+}
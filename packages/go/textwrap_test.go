@@ -0,0 +1,125 @@
+package opentui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTextBufferWrapToWidth(t *testing.T) {
+	tests := []struct {
+		text  string
+		width uint32
+		mode  WrapMode
+		lines []LineInfo
+	}{
+		{"hello world foo", 11, WrapWord, []LineInfo{{0, 11}, {12, 3}}},
+		{"hello world foo", 8, WrapWord, []LineInfo{{0, 5}, {6, 5}, {12, 3}}},
+		{"hello world", 4, WrapChar, []LineInfo{{0, 4}, {4, 4}, {8, 3}}},
+		{"abcdefgh ij", 5, WrapWord, []LineInfo{{0, 5}, {5, 3}, {9, 2}}},
+		{"ab\ncd ef", 3, WrapWord, []LineInfo{{0, 2}, {3, 2}, {6, 2}}},
+		{"long line", 4, WrapNone, []LineInfo{{0, 9}}},
+		// Wide characters are never split
+		{"漢字漢", 3, WrapChar, []LineInfo{{0, 2}, {1, 2}, {2, 2}}},
+		// A ZWJ sequence of five code points stays in one piece
+		{"a👩‍👩‍👧b", 2, WrapChar, []LineInfo{{0, 1}, {1, 2}, {6, 1}}},
+	}
+
+	for _, tt := range tests {
+		tb := NewTextBuffer(64, WidthMethodUnicode)
+		if tb == nil {
+			t.Skip("OpenTUI library not available")
+		}
+		tb.WriteString(tt.text)
+		tb.WrapToWidth(tt.width, tt.mode)
+		lines, err := tb.GetLineInfo()
+		if err != nil {
+			t.Fatalf("GetLineInfo failed: %v", err)
+		}
+		if !equalLines(lines, tt.lines) {
+			t.Errorf("WrapToWidth(%q, %d) lines = %v, want %v", tt.text, tt.width, lines, tt.lines)
+		}
+		if count, _ := tb.LineCount(); count != uint32(len(tt.lines)) {
+			t.Errorf("LineCount(%q) = %d, want %d", tt.text, count, len(tt.lines))
+		}
+		tb.Close()
+	}
+}
+
+func TestTextBufferWrapEdits(t *testing.T) {
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	if tb == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+
+	tb.WriteString("one two")
+	tb.WrapToWidth(5, WrapWord)
+	tb.WriteString(" three")
+	lines, _ := tb.GetLineInfo()
+	if want := []LineInfo{{0, 3}, {4, 3}, {8, 5}}; !equalLines(lines, want) {
+		t.Errorf("lines after write = %v, want %v", lines, want)
+	}
+
+	tb.DeleteRange(0, 4)
+	lines, _ = tb.GetLineInfo()
+	if want := []LineInfo{{0, 3}, {4, 5}}; !equalLines(lines, want) {
+		t.Errorf("lines after delete = %v, want %v", lines, want)
+	}
+
+	// Turning wrapping off goes back to the native line info
+	tb.WrapToWidth(0, WrapWord)
+	tb.FinalizeLineInfo()
+	if count, _ := tb.LineCount(); count != 1 {
+		t.Errorf("LineCount without wrapping = %d, want 1", count)
+	}
+}
+
+func TestDrawTextBufferWrapped(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 4)
+	tb := NewTextBuffer(64, WidthMethodUnicode)
+	defer tb.Close()
+
+	tb.WriteString("the quick brown fox")
+	tb.WrapToWidth(6, WrapWord)
+	if err := buffer.DrawTextBuffer(tb, 1, 0, &ClipRect{X: 0, Y: 0, Width: 8, Height: 3}); err != nil {
+		t.Fatalf("DrawTextBuffer failed: %v", err)
+	}
+	expectRows(t, buffer,
+		" the    ",
+		" quick  ",
+		" brown  ",
+		"        ",
+	)
+}
+
+func equalLines(a, b []LineInfo) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func BenchmarkTextBufferWrapToWidth(b *testing.B) {
+	tb := NewTextBuffer(100000, WidthMethodUnicode)
+	if tb == nil {
+		b.Skip("OpenTUI library not available")
+	}
+	defer tb.Close()
+	paragraph := strings.Repeat("lorem ipsum dolor sit amet 漢字 ", 40) + "\n"
+	for length, _ := tb.Length(); length < 100000; length, _ = tb.Length() {
+		tb.WriteString(paragraph)
+	}
+	tb.WrapToWidth(80, WrapWord)
+	tb.LineCount()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tb.WrapToWidth(uint32(40+i%40), WrapWord)
+		tb.LineCount()
+	}
+}
@@ -0,0 +1,215 @@
+package opentui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Theme holds the semantic colors and default attributes used by the themed drawing
+// helpers. Keep the theme in one place and pass it on every frame, so switching
+// themes only takes a re-render.
+type Theme struct {
+	Primary RGBA // Titles, headers and filled progress
+	Surface RGBA // Background of boxes, tables and empty progress
+	Border  RGBA
+	Text    RGBA
+	Accent  RGBA
+	Error   RGBA
+
+	TextAttributes   Attributes // Applied to body text such as table rows
+	TitleAttributes  Attributes // Applied to box titles
+	HeaderAttributes Attributes // Applied to table headers
+}
+
+// Built-in themes
+var (
+	DarkTheme = Theme{
+		Primary:          rgb8(97, 175, 239),
+		Surface:          rgb8(30, 33, 39),
+		Border:           rgb8(92, 99, 112),
+		Text:             rgb8(220, 223, 228),
+		Accent:           rgb8(198, 120, 221),
+		Error:            rgb8(224, 108, 117),
+		TitleAttributes:  AttrBold,
+		HeaderAttributes: AttrBold,
+	}
+	LightTheme = Theme{
+		Primary:          rgb8(64, 120, 242),
+		Surface:          rgb8(250, 250, 250),
+		Border:           rgb8(160, 161, 167),
+		Text:             rgb8(56, 58, 66),
+		Accent:           rgb8(166, 38, 164),
+		Error:            rgb8(228, 86, 73),
+		TitleAttributes:  AttrBold,
+		HeaderAttributes: AttrBold,
+	}
+)
+
+// ReadableForeground returns t.Text when it is readable on bg and t.Surface otherwise,
+// or whichever of the two contrasts more when neither reaches MinContrastRatio.
+func (t Theme) ReadableForeground(bg RGBA) RGBA {
+	return ReadableForegroundFrom(bg, t.Text, t.Surface)
+}
+
+// DrawBoxThemed draws a box over rect with the border in theme.Border on a
+// theme.Surface background. A title without a TitleColor is drawn in theme.Primary,
+// and theme.TitleAttributes are added to the title attributes.
+func (b *Buffer) DrawBoxThemed(rect Rect, theme Theme, opts BoxOptions) error {
+	if opts.TitleColor == nil {
+		opts.TitleColor = &theme.Primary
+	}
+	opts.TitleAttributes |= theme.TitleAttributes
+	return b.DrawBox(rect.X, rect.Y, rect.Width, rect.Height, opts, theme.Border, theme.Surface)
+}
+
+// DrawTableThemed draws a table like DrawTable with its colors taken from the theme:
+// rows in theme.Text on theme.Surface, headers in theme.Primary and borders in
+// theme.Border. The colors of opts are replaced, except for StripeBackground, and
+// the theme attributes are added to the header and row attributes.
+func (b *Buffer) DrawTableThemed(rect Rect, table TableData, theme Theme, opts TableOptions) (uint32, error) {
+	opts.Foreground = theme.Text
+	opts.Background = &theme.Surface
+	opts.HeaderForeground = theme.Primary
+	opts.HeaderBackground = &theme.Surface
+	opts.HeaderAttributes |= theme.HeaderAttributes
+	opts.RowAttributes |= theme.TextAttributes
+	opts.BorderColor = theme.Border
+	return b.DrawTable(rect, table, opts)
+}
+
+// DrawProgressBarThemed draws a progress bar like DrawProgressBar, filled in
+// theme.Primary over theme.Surface with the label in theme.Text.
+func (b *Buffer) DrawProgressBarThemed(x, y uint32, width uint32, fraction float64, theme Theme, opts ProgressBarOptions) error {
+	opts.FilledColor = theme.Primary
+	opts.EmptyColor = theme.Surface
+	opts.LabelColor = theme.Text
+	return b.DrawProgressBar(x, y, width, fraction, opts)
+}
+
+// themeJSON is the file format of a theme: colors as "#rrggbb", or "#rrggbbaa" when
+// not opaque, and attributes as lists of markup attribute names.
+type themeJSON struct {
+	Primary          string   `json:"primary"`
+	Surface          string   `json:"surface"`
+	Border           string   `json:"border"`
+	Text             string   `json:"text"`
+	Accent           string   `json:"accent"`
+	Error            string   `json:"error"`
+	TextAttributes   []string `json:"textAttributes,omitempty"`
+	TitleAttributes  []string `json:"titleAttributes,omitempty"`
+	HeaderAttributes []string `json:"headerAttributes,omitempty"`
+}
+
+// MarshalJSON encodes the theme with hex colors and named attributes.
+func (t Theme) MarshalJSON() ([]byte, error) {
+	return json.Marshal(themeJSON{
+		Primary:          hexColor(t.Primary),
+		Surface:          hexColor(t.Surface),
+		Border:           hexColor(t.Border),
+		Text:             hexColor(t.Text),
+		Accent:           hexColor(t.Accent),
+		Error:            hexColor(t.Error),
+		TextAttributes:   attributeNames(t.TextAttributes),
+		TitleAttributes:  attributeNames(t.TitleAttributes),
+		HeaderAttributes: attributeNames(t.HeaderAttributes),
+	})
+}
+
+// UnmarshalJSON decodes a theme written by MarshalJSON. Colors may also be given as
+// #rgb or by the color names accepted by markup. Fields missing from the JSON keep
+// their current value, so a theme file can override just a few colors of a copy of
+// DarkTheme or LightTheme.
+func (t *Theme) UnmarshalJSON(data []byte) error {
+	var raw themeJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	out := *t
+	colors := []struct {
+		name  string
+		value string
+		dst   *RGBA
+	}{
+		{"primary", raw.Primary, &out.Primary},
+		{"surface", raw.Surface, &out.Surface},
+		{"border", raw.Border, &out.Border},
+		{"text", raw.Text, &out.Text},
+		{"accent", raw.Accent, &out.Accent},
+		{"error", raw.Error, &out.Error},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		color, ok := parseThemeColor(c.value)
+		if !ok {
+			return newError(fmt.Sprintf("invalid %s color %q", c.name, c.value))
+		}
+		*c.dst = color
+	}
+	attrs := []struct {
+		names []string
+		dst   *Attributes
+	}{
+		{raw.TextAttributes, &out.TextAttributes},
+		{raw.TitleAttributes, &out.TitleAttributes},
+		{raw.HeaderAttributes, &out.HeaderAttributes},
+	}
+	for _, a := range attrs {
+		if a.names == nil {
+			continue
+		}
+		var mask Attributes
+		for _, name := range a.names {
+			attr, ok := markupAttributes[name]
+			if !ok {
+				return newError(fmt.Sprintf("unknown attribute %q", name))
+			}
+			mask |= attr
+		}
+		*a.dst = mask
+	}
+	*t = out
+	return nil
+}
+
+// attributeOrder lists the markup attribute names in bit order
+var attributeOrder = []string{"bold", "dim", "italic", "underline", "blink", "reverse", "strike"}
+
+// attributeNames returns the markup names of the attributes set in mask.
+func attributeNames(mask Attributes) []string {
+	var names []string
+	for _, name := range attributeOrder {
+		if mask&markupAttributes[name] != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hexColor formats a color as #rrggbb, with an alpha byte appended unless it is opaque.
+func hexColor(c RGBA) string {
+	channel := func(v float32) uint8 { return uint8(clamp01(v)*255 + 0.5) }
+	s := fmt.Sprintf("#%02x%02x%02x", channel(c.R), channel(c.G), channel(c.B))
+	if a := channel(c.A); a != 255 {
+		s += fmt.Sprintf("%02x", a)
+	}
+	return s
+}
+
+// parseThemeColor parses #rrggbbaa or anything parseMarkupColor accepts.
+func parseThemeColor(value string) (RGBA, bool) {
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || len(hex) != 8 {
+		return parseMarkupColor(value)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return RGBA{}, false
+	}
+	c := rgb8(uint8(v>>24), uint8(v>>16), uint8(v>>8))
+	c.A = float32(uint8(v)) / 255
+	return c, true
+}
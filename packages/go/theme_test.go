@@ -0,0 +1,88 @@
+package opentui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestThemeJSONRoundTrip(t *testing.T) {
+	theme := LightTheme
+	theme.Accent = NewRGBA(1, 0, 0, 0.5)
+	theme.TextAttributes = AttrItalic | AttrUnderline
+
+	data, err := json.Marshal(theme)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"accent":"#ff000080"`) || !strings.Contains(string(data), `"textAttributes":["italic","underline"]`) {
+		t.Errorf("unexpected JSON %s", data)
+	}
+
+	var got Theme
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	got.Accent.A = theme.Accent.A // 0.5 is stored as 128/255
+	if got != theme {
+		t.Errorf("round trip = %+v, want %+v", got, theme)
+	}
+}
+
+func TestThemeUnmarshalKeepsMissingFields(t *testing.T) {
+	theme := DarkTheme
+	if err := json.Unmarshal([]byte(`{"primary":"#f80","error":"red","headerAttributes":[]}`), &theme); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := DarkTheme
+	want.Primary, want.Error, want.HeaderAttributes = rgb8(255, 136, 0), Red, 0
+	if theme != want {
+		t.Errorf("theme = %+v, want %+v", theme, want)
+	}
+}
+
+func TestThemeUnmarshalErrors(t *testing.T) {
+	for _, data := range []string{`{"text":"#12"}`, `{"titleAttributes":["loud"]}`, `[]`} {
+		theme := DarkTheme
+		if err := json.Unmarshal([]byte(data), &theme); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", data)
+		}
+		if theme != DarkTheme {
+			t.Errorf("Unmarshal(%s) modified the theme", data)
+		}
+	}
+}
+
+func TestDrawBoxThemed(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 3)
+	opts := BoxOptions{Sides: BorderSides{Top: true, Right: true, Bottom: true, Left: true}, Title: "Hi"}.WithStyle(BorderSingle)
+	if err := buffer.DrawBoxThemed(Rect{Size: Size{Width: 8, Height: 3}}, DarkTheme, opts); err != nil {
+		t.Fatalf("DrawBoxThemed failed: %v", err)
+	}
+	corner, _ := buffer.GetCell(0, 0)
+	if corner.Foreground != DarkTheme.Border || corner.Background != DarkTheme.Surface {
+		t.Errorf("corner = %+v, want the theme border on its surface", corner)
+	}
+	title, _ := buffer.GetCell(2, 0)
+	if title.Char != 'H' || title.Foreground != DarkTheme.Primary || title.Attributes&AttrBold == 0 {
+		t.Errorf("title cell = %+v, want bold 'H' in the primary color", title)
+	}
+}
+
+func TestDrawTableThemed(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 2)
+	theme := LightTheme
+	theme.TextAttributes = AttrItalic
+	table := NewTableData([]string{"Name"}, [][]string{{"kiwi"}})
+	if _, err := buffer.DrawTableThemed(tableRect(6, 2), table, theme, TableOptions{}); err != nil {
+		t.Fatalf("DrawTableThemed failed: %v", err)
+	}
+	header, _ := buffer.GetCell(0, 0)
+	row, _ := buffer.GetCell(0, 1)
+	if header.Foreground != theme.Primary || header.Attributes != AttrBold {
+		t.Errorf("header cell = %+v", header)
+	}
+	if row.Foreground != theme.Text || row.Background != theme.Surface || row.Attributes != AttrItalic {
+		t.Errorf("row cell = %+v", row)
+	}
+}
@@ -0,0 +1,158 @@
+package opentui
+
+// TooltipPlacement is the side of its anchor a tooltip goes to
+type TooltipPlacement uint8
+
+const (
+	TooltipBelow TooltipPlacement = iota // Under the anchor, left aligned with it
+	TooltipAbove                         // Over the anchor, left aligned with it
+	TooltipRight                         // Right of the anchor, top aligned with it
+)
+
+// tooltipZ is the layer Z of tooltips, above the layers created with the default Z
+const tooltipZ = 1 << 16
+
+// TooltipOptions controls the placement and the colors of a tooltip
+type TooltipOptions struct {
+	Placement  TooltipPlacement // Tried first; the other sides follow in the order below, above, right
+	MaxWidth   uint32           // Widest the text gets before being word wrapped, 40 cells if 0
+	Foreground RGBA
+	Background RGBA
+	Border     RGBA // Color of the rounded border; Transparent draws none
+}
+
+// DefaultTooltipOptions draws light text on dark gray in a gray border, below the anchor.
+var DefaultTooltipOptions = TooltipOptions{
+	Placement:  TooltipBelow,
+	Foreground: NewRGB(0.95, 0.95, 0.95),
+	Background: NewRGB(0.15, 0.15, 0.18),
+	Border:     NewRGB(0.5, 0.5, 0.55),
+}
+
+// defaultTooltipWidth is the widest the text of a tooltip gets unless MaxWidth is set
+const defaultTooltipWidth = 40
+
+// Tooltip is a small box of text floating next to an anchor rect, shown with
+// ShowTooltip. It lives on a layer over the frame, so it covers what is below it
+// without changing it.
+type Tooltip struct {
+	Anchor    Rect
+	Placement TooltipPlacement // Side it was placed on
+	layer     *Layer
+}
+
+// Rect returns the cells the tooltip covers, border included.
+func (t *Tooltip) Rect() Rect {
+	return t.layer.Rect()
+}
+
+// Close removes the tooltip from the next frame on. Closing it twice does nothing.
+func (t *Tooltip) Close() error {
+	return t.layer.Close()
+}
+
+// ShowTooltip shows content in a box next to anchor, on a layer above the frame and
+// any other layer, until Close is called. The text is word wrapped to opts.MaxWidth
+// and the box goes to the side of anchor given by opts.Placement if it fits on the
+// screen there, or else to the first other side it fits on, shifted left if needed
+// to stay on screen. When it fits nowhere, it goes to the preferred side, moved
+// inside the screen.
+func (r *Renderer) ShowTooltip(anchor Rect, content string, opts TooltipOptions) (*Tooltip, error) {
+	if r.ptr == nil {
+		return nil, closedError("renderer")
+	}
+	if opts.Placement > TooltipRight {
+		return nil, newError("invalid tooltip placement")
+	}
+	next, err := r.GetNextBuffer()
+	if err != nil {
+		return nil, err
+	}
+	screenWidth, err := next.Width()
+	if err != nil {
+		return nil, err
+	}
+	screenHeight, err := next.Height()
+	if err != nil {
+		return nil, err
+	}
+
+	// One cell of border and one of padding on each side of the text
+	maxWidth := opts.MaxWidth
+	if maxWidth == 0 {
+		maxWidth = defaultTooltipWidth
+	}
+	maxWidth = max(min(maxWidth, screenWidth-min(screenWidth, 4)), 1)
+	content, _ = expandTabs(content, 0, 0, defaultTabWidth)
+	lines := wrapText(content, int(maxWidth), WrapWord)
+	textWidth := 0
+	for _, line := range lines {
+		textWidth = max(textWidth, stringWidth(line))
+	}
+	size := Size{Width: uint32(textWidth) + 4, Height: uint32(len(lines)) + 2}
+
+	placement, pos := placeTooltip(anchor, size, Size{Width: screenWidth, Height: screenHeight}, opts.Placement)
+	layer, err := r.NewLayer(pos, size.Width, size.Height, tooltipZ)
+	if err != nil {
+		return nil, err
+	}
+	buf := layer.Buffer()
+	box := BoxOptions{
+		Sides:       BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+		Fill:        true,
+		BorderChars: RoundedBoxChars,
+	}
+	if opts.Border.A == 0 {
+		box.Sides = BorderSides{}
+	}
+	if err := buf.DrawBox(0, 0, size.Width, size.Height, box, opts.Border, opts.Background); err != nil {
+		layer.Close()
+		return nil, err
+	}
+	for i, line := range lines {
+		if err := buf.DrawText(line, 2, uint32(i)+1, opts.Foreground, &opts.Background, 0); err != nil {
+			layer.Close()
+			return nil, err
+		}
+	}
+	return &Tooltip{Anchor: anchor, Placement: placement, layer: layer}, nil
+}
+
+// placeTooltip returns the side of anchor a box of size goes to on a screen of the
+// given size and the position of its top left cell.
+func placeTooltip(anchor Rect, size, screen Size, preferred TooltipPlacement) (TooltipPlacement, Position) {
+	at := func(p TooltipPlacement) Position {
+		switch p {
+		case TooltipAbove:
+			return Position{X: anchor.X, Y: anchor.Y - int32(size.Height)}
+		case TooltipRight:
+			return Position{X: anchor.X + int32(anchor.Width), Y: anchor.Y}
+		}
+		return Position{X: anchor.X, Y: anchor.Y + int32(anchor.Height)}
+	}
+	// Boxes above or below may slide left to stay on screen, boxes on the right may
+	// slide up
+	clamp := func(pos Position) Position {
+		pos.X = max(min(pos.X, int32(screen.Width)-int32(size.Width)), 0)
+		pos.Y = max(min(pos.Y, int32(screen.Height)-int32(size.Height)), 0)
+		return pos
+	}
+	fits := func(p TooltipPlacement) bool {
+		pos := at(p)
+		if size.Width > screen.Width || size.Height > screen.Height {
+			return false
+		}
+		if p == TooltipRight {
+			return pos.X+int32(size.Width) <= int32(screen.Width)
+		}
+		return pos.Y >= 0 && pos.Y+int32(size.Height) <= int32(screen.Height)
+	}
+
+	order := []TooltipPlacement{preferred, TooltipBelow, TooltipAbove, TooltipRight}
+	for _, p := range order {
+		if fits(p) {
+			return p, clamp(at(p))
+		}
+	}
+	return preferred, clamp(at(preferred))
+}
@@ -0,0 +1,79 @@
+package opentui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlaceTooltip(t *testing.T) {
+	screen := Size{Width: 40, Height: 10}
+	size := Size{Width: 12, Height: 3}
+	anchor := func(x, y int32) Rect {
+		return Rect{Position: Position{X: x, Y: y}, Size: Size{Width: 6, Height: 1}}
+	}
+	tests := []struct {
+		name      string
+		anchor    Rect
+		preferred TooltipPlacement
+		want      TooltipPlacement
+		pos       Position
+	}{
+		{"below", anchor(2, 2), TooltipBelow, TooltipBelow, Position{X: 2, Y: 3}},
+		{"above", anchor(2, 5), TooltipAbove, TooltipAbove, Position{X: 2, Y: 2}},
+		{"no room above", anchor(2, 1), TooltipAbove, TooltipBelow, Position{X: 2, Y: 2}},
+		{"no room below", anchor(2, 8), TooltipBelow, TooltipAbove, Position{X: 2, Y: 5}},
+		{"slides left at the right edge", anchor(34, 2), TooltipBelow, TooltipBelow, Position{X: 28, Y: 3}},
+		{"right", anchor(2, 8), TooltipRight, TooltipRight, Position{X: 8, Y: 7}},
+		{"no room on the right", anchor(30, 2), TooltipRight, TooltipBelow, Position{X: 28, Y: 3}},
+	}
+	for _, tt := range tests {
+		placement, pos := placeTooltip(tt.anchor, size, screen, tt.preferred)
+		if placement != tt.want || pos != tt.pos {
+			t.Errorf("%s: placed %d at %+v, want %d at %+v", tt.name, placement, pos, tt.want, tt.pos)
+		}
+	}
+
+	// A box taller than the screen goes to the preferred side, inside the screen
+	placement, pos := placeTooltip(anchor(2, 2), Size{Width: 12, Height: 12}, screen, TooltipBelow)
+	if placement != TooltipBelow || pos != (Position{X: 2, Y: 0}) {
+		t.Errorf("oversized box placed %d at %+v", placement, pos)
+	}
+}
+
+func TestTooltipLayer(t *testing.T) {
+	renderer := NewRenderer(20, 5)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	draw := func() {
+		next, _ := renderer.GetNextBuffer()
+		next.Clear(Black)
+		next.DrawText("content", 0, 0, White, nil, 0)
+		next.DrawText("underneath", 0, 2, White, nil, 0)
+	}
+	anchor := Rect{Position: Position{X: 0, Y: 0}, Size: Size{Width: 7, Height: 1}}
+	tooltip, err := renderer.ShowTooltip(anchor, "tip", DefaultTooltipOptions)
+	if err != nil {
+		t.Fatalf("ShowTooltip failed: %v", err)
+	}
+	if rect := tooltip.Rect(); rect != (Rect{Position: Position{X: 0, Y: 1}, Size: Size{Width: 7, Height: 3}}) {
+		t.Errorf("tooltip covers %+v", rect)
+	}
+	draw()
+	captureStdout(t, func() { renderer.Render(false) })
+	rows, _ := renderer.CaptureText()
+	if !strings.Contains(rows[2], "tip") || strings.Contains(rows[2], "underneath") {
+		t.Errorf("row under the tooltip = %q", rows[2])
+	}
+
+	tooltip.Close()
+	tooltip.Close()
+	draw()
+	captureStdout(t, func() { renderer.Render(false) })
+	rows, _ = renderer.CaptureText()
+	if !strings.HasPrefix(rows[2], "underneath") {
+		t.Errorf("row after closing the tooltip = %q", rows[2])
+	}
+}
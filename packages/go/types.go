@@ -6,28 +6,50 @@ package opentui
 import "C"
 import (
 	"runtime"
+	"time"
 	"unsafe"
 )
 
 // Cell represents a single terminal cell with character, colors, and attributes
 type Cell struct {
-	Char       rune  // Unicode character
-	Foreground RGBA  // Foreground color
-	Background RGBA  // Background color
-	Attributes uint8 // Text attributes (bold, italic, etc.)
+	Char       rune       // Unicode character
+	Foreground RGBA       // Foreground color
+	Background RGBA       // Background color
+	Attributes Attributes // Text attributes (bold, italic, etc.)
 }
 
+// CellChange describes a cell that differs between two buffers. The cells hold the first
+// character of their grapheme cluster, and the texts the whole cluster, like GetCluster
+// returns it: "" for the trailing cell of a double width cluster.
+type CellChange struct {
+	X, Y     uint32
+	From     Cell   // Cell in the buffer Diff was called on
+	To       Cell   // Cell in the other buffer
+	FromText string // Text shown by From
+	ToText   string // Text shown by To
+}
+
+// Attributes is a set of text attributes such as AttrBold, combined with |. Buffers
+// and text buffers store the same 16 bits, so attributes survive moving text between
+// them.
+type Attributes uint16
+
 // Text attributes constants
 const (
-	AttrBold      uint8 = 1 << 0
-	AttrDim       uint8 = 1 << 1
-	AttrItalic    uint8 = 1 << 2
-	AttrUnderline uint8 = 1 << 3
-	AttrBlink     uint8 = 1 << 4
-	AttrReverse   uint8 = 1 << 5
-	AttrStrike    uint8 = 1 << 6
+	AttrBold      Attributes = 1 << 0
+	AttrDim       Attributes = 1 << 1
+	AttrItalic    Attributes = 1 << 2
+	AttrUnderline Attributes = 1 << 3
+	AttrBlink     Attributes = 1 << 4
+	AttrReverse   Attributes = 1 << 5
+	AttrHidden    Attributes = 1 << 6
+	AttrStrike    Attributes = 1 << 7
 )
 
+// attrMask selects the attributes of a text buffer cell. The native text buffer
+// keeps flags for default styles in the top three bits.
+const attrMask Attributes = 0x1fff
+
 // ClipRect defines a rectangular clipping region
 type ClipRect struct {
 	X      int32
@@ -43,6 +65,14 @@ type Stats struct {
 	FrameCallbackTime float64
 }
 
+// RenderStats describes a frame rendered with RenderWithStats
+type RenderStats struct {
+	CellsChanged uint32        // Cells that differ from the previous frame, all cells on a full redraw
+	BytesWritten uint32        // Bytes written to the terminal, see RenderWithStats
+	Duration     time.Duration // Time spent rendering
+	FullRedraw   bool          // Every cell was redrawn
+}
+
 // MemoryStats holds memory usage statistics
 type MemoryStats struct {
 	HeapUsed     uint32
@@ -52,11 +82,15 @@ type MemoryStats struct {
 
 // BoxOptions holds options for drawing boxes
 type BoxOptions struct {
-	Sides          BorderSides
-	Fill           bool
-	Title          string
-	TitleAlignment TextAlignment
-	BorderChars    [8]rune // Top-left, top, top-right, right, bottom-right, bottom, bottom-left, left
+	Sides           BorderSides
+	Fill            bool
+	Title           string
+	TitleAlignment  TextAlignment
+	TitleColor      *RGBA       // Title foreground, defaults to the border color
+	TitleAttributes Attributes  // Text attributes applied to the title
+	TitlePadding    uint8       // Blank cells drawn on each side of the title
+	BorderChars     [8]rune     // Top-left, top, top-right, left, right, bottom-left, bottom, bottom-right
+	BorderDash      DashPattern // Replaces the edge characters with dashes, keeping the corners
 }
 
 // DefaultBoxChars provides default Unicode box drawing characters
@@ -81,7 +115,7 @@ type TextChunk struct {
 	Text       string
 	Foreground *RGBA
 	Background *RGBA
-	Attributes *uint8
+	Attributes *Attributes
 }
 
 // LineInfo represents information about a line in a text buffer
@@ -92,19 +126,31 @@ type LineInfo struct {
 
 // HitTestResult represents the result of a mouse hit test
 type HitTestResult struct {
-	ID    uint32
-	Found bool
+	ID    uint32 // ID of the hit area, only meaningful when Found is true
+	Found bool   // Whether any hit area was registered at the tested position
+}
+
+// HitRegion represents a rectangular area of the hit grid mapped to a single ID
+type HitRegion struct {
+	Rect
+	ID uint32
 }
 
 // Error represents an OpenTUI error
 type Error struct {
 	Message string
+	kind    error // ErrClosed, ErrOutOfBounds or ErrAllocation, nil if none
 }
 
 func (e *Error) Error() string {
 	return e.Message
 }
 
+// Is reports whether the error is of the kind target, see ErrClosed.
+func (e *Error) Is(target error) bool {
+	return e.kind != nil && e.kind == target
+}
+
 // newError creates a new OpenTUI error
 func newError(msg string) error {
 	return &Error{Message: msg}
@@ -140,19 +186,6 @@ func cArrayToSlice[T any](ptr *T, length int) []T {
 	return unsafe.Slice(ptr, length)
 }
 
-// runesToC converts a rune slice to uint32 C array
-func runesToC(runes []rune) *C.uint32_t {
-	if len(runes) == 0 {
-		return nil
-	}
-	// Convert runes to uint32
-	uint32s := make([]uint32, len(runes))
-	for i, r := range runes {
-		uint32s[i] = uint32(r)
-	}
-	return (*C.uint32_t)(unsafe.Pointer(&uint32s[0]))
-}
-
 // Position represents a 2D coordinate
 type Position struct {
 	X int32
@@ -185,11 +218,15 @@ func (r Rect) Overlaps(other Rect) bool {
 		r.Y+int32(r.Height) > other.Y
 }
 
-// MouseEvent represents a mouse interaction
+// MouseEvent represents a mouse interaction, see ParseMouseEvent
 type MouseEvent struct {
-	Position Position
-	Button   uint8
-	Pressed  bool
+	Position  Position // Cell under the pointer, 0-based
+	Pixel     Position // Pixel under the pointer, the top left pixel of the cell unless Pixels
+	Button    uint8    // MouseLeft, MouseMiddle, MouseRight, MouseNone or a wheel button
+	Pressed   bool
+	Motion    bool  // Pointer moved, with Button held or MouseNone
+	Modifiers uint8 // ModShift, ModAlt and ModCtrl held
+	Pixels    bool  // Reported in pixels, see EnableMousePixels
 }
 
 // KeyEvent represents a keyboard interaction
@@ -198,6 +235,21 @@ type KeyEvent struct {
 	Modifiers uint8
 }
 
+// Keys without a character of their own, as KeyEvent.Key. Enter and Escape are their
+// control characters; the others take the private use code points macOS gives them.
+const (
+	KeyEnter    rune = '\r'
+	KeyEscape   rune = 0x1b
+	KeyUp       rune = 0xF700
+	KeyDown     rune = 0xF701
+	KeyLeft     rune = 0xF702
+	KeyRight    rune = 0xF703
+	KeyHome     rune = 0xF729
+	KeyEnd      rune = 0xF72B
+	KeyPageUp   rune = 0xF72C
+	KeyPageDown rune = 0xF72D
+)
+
 // Key modifier constants
 const (
 	ModShift   uint8 = 1 << 0
@@ -212,4 +264,12 @@ type Capabilities struct {
 	SupportsMouse          bool // Terminal supports mouse events
 	SupportsKittyKeyboard  bool // Terminal supports Kitty keyboard protocol
 	SupportsAlternateScreen bool // Terminal supports alternate screen buffer
+	SupportsUnicode         bool // Terminal can display Unicode box drawing characters
+	SupportsSixel           bool // Terminal supports sixel graphics
+	SupportsITerm2Images    bool // Terminal supports the iTerm2 inline image protocol
+	SupportsHyperlinks      bool // Terminal supports OSC 8 hyperlinks
+	SupportsSyncOutput      bool // Terminal supports synchronized output (mode 2026)
+	SupportsClipboard       bool // Terminal accepts OSC 52 clipboard writes
+	KittyKeyboardFlags      uint8 // Active kitty keyboard flags the terminal last reported, see QueryKittyKeyboard
+	SupportsMousePixels     bool // Terminal reported mouse pixel coordinates (mode 1016) on, see EnableMousePixels
 }
\ No newline at end of file
@@ -0,0 +1,39 @@
+package opentui
+
+// CellKind tells apart the two cells covered by a double width character such as 漢
+type CellKind uint8
+
+const (
+	CellNarrow       CellKind = iota // A cell of its own
+	CellWide                         // First cell of a double width character, which covers the next cell too
+	CellContinuation                 // Second cell of a double width character, drawn by the cell before it
+)
+
+// GetCellKind reports whether the cell at the specified coordinates holds a double
+// width character or the second half of one. Writing to either half of a double width
+// character with DrawText, SetCell, SetChar, SetCellWithAlphaBlending or FillRect
+// blanks the other half, so halves never outlive their partner.
+func (b *Buffer) GetCellKind(x, y uint32) (CellKind, error) {
+	if b.ptr == nil {
+		return CellNarrow, closedError("buffer")
+	}
+	da, err := b.GetDirectAccess()
+	if err != nil {
+		return CellNarrow, err
+	}
+	if x >= da.Width || y >= da.Height {
+		return CellNarrow, boundsError("coordinates")
+	}
+	return cellKind(da.Chars[y*da.Width+x]), nil
+}
+
+// cellKind classifies a cell by the flags of its stored character.
+func cellKind(c uint32) CellKind {
+	switch {
+	case isContinuationChar(c):
+		return CellContinuation
+	case charRightExtent(c) > 0:
+		return CellWide
+	}
+	return CellNarrow
+}
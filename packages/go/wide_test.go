@@ -0,0 +1,67 @@
+package opentui
+
+import "testing"
+
+func TestDrawOverWideChars(t *testing.T) {
+	buffer := newTestBuffer(t, 8, 4)
+
+	for y := uint32(0); y < 4; y++ {
+		buffer.DrawText("漢字漢", 0, y, White, nil, 0)
+	}
+	// Each row overwrites one half of the middle character
+	buffer.DrawText("x", 3, 0, Red, nil, 0)
+	buffer.SetCell(2, 1, Cell{Char: 'y', Foreground: Red, Background: Black})
+	buffer.FillRect(3, 2, 1, 1, Blue)
+	buffer.SetCellWithAlphaBlending(2, 3, 'z', Red, Black, 0)
+
+	expectRows(t, buffer,
+		"漢  x漢   ",
+		"漢 y 漢   ",
+		"漢   漢   ",
+		"漢 z 漢   ",
+	)
+
+	rows, err := buffer.CaptureTextWithOptions(CaptureOptions{TrimTrailingSpace: true})
+	if err != nil {
+		t.Fatalf("CaptureText failed: %v", err)
+	}
+	for i, want := range []string{"漢  x漢", "漢 y 漢", "漢   漢", "漢 z 漢"} {
+		if rows[i] != want {
+			t.Errorf("captured row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+
+	for _, tc := range []struct {
+		x, y uint32
+		want CellKind
+	}{
+		{0, 0, CellWide},
+		{1, 0, CellContinuation},
+		{2, 0, CellNarrow},
+		{3, 0, CellNarrow},
+		{4, 0, CellWide},
+		{5, 0, CellContinuation},
+		{3, 1, CellNarrow},
+	} {
+		if kind, err := buffer.GetCellKind(tc.x, tc.y); err != nil || kind != tc.want {
+			t.Errorf("GetCellKind(%d, %d) = %v, %v, want %v", tc.x, tc.y, kind, err, tc.want)
+		}
+	}
+	if _, err := buffer.GetCellKind(8, 0); err == nil {
+		t.Error("GetCellKind out of bounds should fail")
+	}
+}
+
+func TestWideCharOverWideChar(t *testing.T) {
+	buffer := newTestBuffer(t, 6, 1)
+	buffer.DrawText("漢字", 0, 0, White, nil, 0)
+
+	// The new character covers the first half of 字, whose second half goes with it
+	buffer.SetCell(1, 0, Cell{Char: '字', Foreground: Red, Background: Black})
+	expectRows(t, buffer, " 字    ")
+	for x, want := range []CellKind{CellNarrow, CellWide, CellContinuation, CellNarrow} {
+		if kind, _ := buffer.GetCellKind(uint32(x), 0); kind != want {
+			t.Errorf("GetCellKind(%d, 0) = %v, want %v", x, kind, want)
+		}
+	}
+}
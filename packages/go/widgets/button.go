@@ -0,0 +1,232 @@
+// Package widgets provides reusable components drawn with opentui, starting with a
+// clickable Button.
+package widgets
+
+import (
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// sparkleDuration is how long a click keeps the button in its pressed color, with
+// sparkles fading out around the label.
+const sparkleDuration = 300 * time.Millisecond
+
+// Style holds the colors of a button in each of its states. NewStyle derives them
+// from one base color; any of them may be changed afterwards.
+type Style struct {
+	Normal          opentui.RGBA // Background when idle
+	Hover           opentui.RGBA // Background under the pointer
+	Pressed         opentui.RGBA // Background while pressed and just after a click
+	Disabled        opentui.RGBA // Background when disabled
+	Border          opentui.RGBA
+	Label           opentui.RGBA
+	DisabledLabel   opentui.RGBA
+	LabelAttributes opentui.Attributes
+}
+
+// NewStyle derives the colors of a button from its background: a lighter background on
+// hover, a darker one when pressed, a grayed out one when disabled and a brighter border.
+func NewStyle(color opentui.RGBA) Style {
+	return Style{
+		Normal:   color,
+		Hover:    color.Lighten(0.15),
+		Pressed:  color.Darken(0.2),
+		Disabled: opentui.Mix(color, opentui.Gray, 0.7).Darken(0.2),
+		Border: opentui.NewRGBA(
+			min(color.R*1.3, 1),
+			min(color.G*1.3, 1),
+			min(color.B*1.3, 1),
+			1,
+		),
+		Label:           opentui.White,
+		DisabledLabel:   opentui.Gray,
+		LabelAttributes: opentui.AttrBold,
+	}
+}
+
+// Button is a bordered button with a centered label. It lights up under the pointer,
+// darkens while pressed and calls OnClick when clicked, counting the clicks. Feed it
+// mouse events with HandleMouse and draw it every frame with Render.
+type Button struct {
+	ID       uint32       // Hit grid ID, see Register
+	Rect     opentui.Rect // Cells covered, border included
+	Style    Style
+	OnClick  func() // Called on every click, if set
+	Disabled bool   // Ignores the pointer and clicks, drawn in the disabled colors
+	Focused  bool   // Set while the button has the keyboard focus, drawn as hovered; see FocusManager
+
+	label     string
+	hovered   bool
+	pressed   bool
+	clicks    int
+	lastClick time.Time
+	renderer  *opentui.Renderer
+}
+
+// NewButton creates a button labeled label covering rect, in the colors of style.
+func NewButton(id uint32, rect opentui.Rect, label string, style Style) *Button {
+	return &Button{ID: id, Rect: rect, Style: style, label: label}
+}
+
+// Label returns the text shown on the button.
+func (b *Button) Label() string {
+	return b.label
+}
+
+// SetLabel changes the text shown on the button from the next Render.
+func (b *Button) SetLabel(label string) {
+	b.label = label
+}
+
+// Hovered reports whether the pointer is over the button.
+func (b *Button) Hovered() bool {
+	return b.hovered
+}
+
+// Pressed reports whether a mouse button was pressed on the button and not released yet.
+func (b *Button) Pressed() bool {
+	return b.pressed
+}
+
+// Clicks returns the number of clicks so far.
+func (b *Button) Clicks() int {
+	return b.clicks
+}
+
+// LastClick returns the time of the last click, zero if there was none.
+func (b *Button) LastClick() time.Time {
+	return b.lastClick
+}
+
+// Click clicks the button as the pointer would, for keyboard shortcuts: it counts
+// the click and calls OnClick. A disabled button ignores it.
+func (b *Button) Click() {
+	if b.Disabled {
+		return
+	}
+	b.lastClick = time.Now()
+	b.clicks++
+	if b.OnClick != nil {
+		b.OnClick()
+	}
+}
+
+// HandleKey clicks the button on Enter or Space. Returns whether the key was one of
+// these.
+func (b *Button) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	switch ev.Key {
+	case opentui.KeyEnter, '\n', ' ':
+		b.Click()
+		return true
+	}
+	return false
+}
+
+// CanFocus reports whether the button can take the keyboard focus, that is whether
+// it's enabled.
+func (b *Button) CanFocus() bool {
+	return !b.Disabled
+}
+
+// SetFocused sets Focused, for FocusManager.
+func (b *Button) SetFocused(focused bool) {
+	b.Focused = focused
+}
+
+// Register adds the button to the renderer's hit grid under its ID. The grid is
+// rebuilt every frame, so register the button whenever it's rendered. Once registered,
+// HandleMouse asks the renderer which area is under the pointer, so a button covered
+// by another area registered later doesn't react to it.
+func (b *Button) Register(renderer *opentui.Renderer) error {
+	if err := renderer.AddToHitGrid(b.Rect.X, b.Rect.Y, b.Rect.Width, b.Rect.Height, b.ID); err != nil {
+		return err
+	}
+	b.renderer = renderer
+	return nil
+}
+
+// HandleMouse updates the hover and press states from a mouse event, clicking the
+// button when the left button is released over it after being pressed there. Leaving
+// the button while pressed cancels the press. Returns whether the event was over the
+// button, so the caller can stop passing it on.
+func (b *Button) HandleMouse(ev opentui.MouseEvent) bool {
+	over := b.contains(ev.Position)
+	if b.Disabled {
+		b.hovered, b.pressed = false, false
+		return over
+	}
+	b.hovered = over
+	switch {
+	case !over:
+		b.pressed = false
+	case ev.Button == opentui.MouseLeft && ev.Pressed && !ev.Motion:
+		b.pressed = true
+	case ev.Button == opentui.MouseLeft && !ev.Pressed:
+		if b.pressed {
+			b.pressed = false
+			b.Click()
+		}
+	}
+	return over
+}
+
+// contains reports whether the button is under the cell at pos, asking the hit grid if
+// the button was registered.
+func (b *Button) contains(pos opentui.Position) bool {
+	if !b.Rect.Contains(pos.X, pos.Y) {
+		return false
+	}
+	if b.renderer == nil || !b.renderer.Valid() {
+		return true
+	}
+	hit, err := b.renderer.HitTest(uint32(pos.X), uint32(pos.Y))
+	return err == nil && hit.Found && hit.ID == b.ID
+}
+
+// Render draws the button into buf: the box with its border, the label centered
+// inside, and sparkles fading out over its middle right after a click.
+func (b *Button) Render(buf *opentui.Buffer) error {
+	sinceClick := time.Since(b.lastClick)
+	background, label := b.Style.Normal, b.Style.Label
+	switch {
+	case b.Disabled:
+		background, label = b.Style.Disabled, b.Style.DisabledLabel
+	case b.pressed || sinceClick < sparkleDuration:
+		background = b.Style.Pressed
+	case b.hovered || b.Focused:
+		background = b.Style.Hover
+	}
+
+	options := opentui.BoxOptions{
+		Sides:       opentui.BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+		Fill:        true,
+		BorderChars: opentui.DefaultBoxChars,
+	}
+	if err := buf.DrawBox(b.Rect.X, b.Rect.Y, b.Rect.Width, b.Rect.Height, options, b.Style.Border, background); err != nil {
+		return err
+	}
+	if b.Rect.Width <= 2 || b.Rect.Height <= 2 {
+		return nil
+	}
+	inside := opentui.Rect{
+		Position: opentui.Position{X: b.Rect.X + 1, Y: b.Rect.Y + 1},
+		Size:     opentui.Size{Width: b.Rect.Width - 2, Height: b.Rect.Height - 2},
+	}
+	if err := buf.DrawTextAligned(b.label, inside, opentui.AlignCenter, opentui.AlignMiddle, label, &background, b.Style.LabelAttributes); err != nil {
+		return err
+	}
+
+	if !b.Disabled && sinceClick < sparkleDuration && b.Rect.X >= 0 && b.Rect.Y >= 0 {
+		fade := float32(sinceClick) / float32(sparkleDuration)
+		sparkle := opentui.Lerp(b.Style.Label, opentui.Transparent, fade)
+		centerX := uint32(b.Rect.X) + b.Rect.Width/2
+		centerY := uint32(b.Rect.Y) + b.Rect.Height/2
+		buf.SetCellWithAlphaBlending(centerX-1, centerY, '✦', sparkle, background, 0)
+		buf.SetCellWithAlphaBlending(centerX+1, centerY, '✦', sparkle, background, 0)
+	}
+	return nil
+}
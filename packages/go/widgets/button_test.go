@@ -0,0 +1,95 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func mouseAt(x, y int32, button uint8, pressed, motion bool) opentui.MouseEvent {
+	return opentui.MouseEvent{Position: opentui.Position{X: x, Y: y}, Button: button, Pressed: pressed, Motion: motion}
+}
+
+func TestButtonHandleMouse(t *testing.T) {
+	rect := opentui.Rect{Position: opentui.Position{X: 2, Y: 1}, Size: opentui.Size{Width: 8, Height: 3}}
+	button := NewButton(0, rect, "OK", NewStyle(opentui.Blue))
+	clicks := 0
+	button.OnClick = func() { clicks++ }
+
+	if button.HandleMouse(mouseAt(0, 0, opentui.MouseNone, false, true)) || button.Hovered() {
+		t.Error("a move outside the button should be ignored")
+	}
+	if !button.HandleMouse(mouseAt(3, 2, opentui.MouseNone, false, true)) || !button.Hovered() {
+		t.Error("a move over the button should hover it")
+	}
+	button.HandleMouse(mouseAt(3, 2, opentui.MouseLeft, true, false))
+	if !button.Pressed() || clicks != 0 {
+		t.Errorf("after a press: pressed %v, %d clicks", button.Pressed(), clicks)
+	}
+	button.HandleMouse(mouseAt(4, 2, opentui.MouseLeft, false, false))
+	if button.Pressed() || clicks != 1 || button.Clicks() != 1 || button.LastClick().IsZero() {
+		t.Errorf("after a release: pressed %v, %d clicks, counted %d", button.Pressed(), clicks, button.Clicks())
+	}
+
+	// Dragging out of the button cancels the press
+	button.HandleMouse(mouseAt(3, 2, opentui.MouseLeft, true, false))
+	button.HandleMouse(mouseAt(12, 2, opentui.MouseLeft, true, true))
+	button.HandleMouse(mouseAt(12, 2, opentui.MouseLeft, false, false))
+	if clicks != 1 || button.Pressed() || button.Hovered() {
+		t.Errorf("a press released outside clicked: %d clicks", clicks)
+	}
+	// A release without a press on the button is not a click either
+	button.HandleMouse(mouseAt(3, 2, opentui.MouseLeft, false, false))
+	if clicks != 1 {
+		t.Error("a release alone should not click")
+	}
+
+	button.Disabled = true
+	button.HandleMouse(mouseAt(3, 2, opentui.MouseLeft, true, false))
+	button.HandleMouse(mouseAt(3, 2, opentui.MouseLeft, false, false))
+	button.Click()
+	if clicks != 1 || button.Hovered() {
+		t.Error("a disabled button should not react")
+	}
+	button.Disabled = false
+	button.Click()
+	if clicks != 2 || button.Clicks() != 2 {
+		t.Errorf("Click counted %d clicks", button.Clicks())
+	}
+}
+
+func TestButtonRender(t *testing.T) {
+	buffer := opentui.NewBuffer(12, 5, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+	buffer.Clear(opentui.Black)
+
+	style := NewStyle(opentui.Blue)
+	rect := opentui.Rect{Position: opentui.Position{X: 1, Y: 1}, Size: opentui.Size{Width: 10, Height: 3}}
+	button := NewButton(0, rect, "OK", style)
+	button.SetLabel("Save")
+	if button.Label() != "Save" {
+		t.Errorf("Label = %q", button.Label())
+	}
+	button.HandleMouse(mouseAt(2, 2, opentui.MouseNone, false, true))
+	if err := button.Render(buffer); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	rows, err := buffer.CaptureText()
+	if err != nil {
+		t.Fatalf("CaptureText failed: %v", err)
+	}
+	if !strings.Contains(rows[2], "Save") {
+		t.Fatalf("label row = %q", rows[2])
+	}
+	// The label is drawn on the background of the state
+	column := uint32(len([]rune(rows[2][:strings.Index(rows[2], "Save")])))
+	cell, err := buffer.GetCell(column, 2)
+	if err != nil || cell.Background != style.Hover {
+		t.Errorf("hovered background = %v, %v, want %v", cell.Background, err, style.Hover)
+	}
+}
@@ -0,0 +1,184 @@
+package widgets
+
+import (
+	"github.com/sst/opentui/packages/go"
+)
+
+// Glyphs are the marks drawn before the label of a checkbox or a radio option.
+type Glyphs struct {
+	On  string // Checked box or selected option
+	Off string
+}
+
+// Glyph sets for Checkbox.Glyphs and RadioGroup.Glyphs. The ASCII ones suit terminals
+// and fonts without the other characters.
+var (
+	CheckboxGlyphs      = Glyphs{On: "[x]", Off: "[ ]"}
+	ASCIICheckboxGlyphs = Glyphs{On: "[*]", Off: "[ ]"}
+	RadioGlyphs         = Glyphs{On: "(•)", Off: "( )"}
+	ASCIIRadioGlyphs    = Glyphs{On: "(o)", Off: "( )"}
+)
+
+// width returns the number of cells of the wider glyph, the column width that keeps
+// labels aligned.
+func (g Glyphs) width() uint32 {
+	return max(textWidth(g.On), textWidth(g.Off))
+}
+
+// ToggleStyle holds the colors of checkboxes and radio groups.
+type ToggleStyle struct {
+	Foreground      opentui.RGBA
+	Disabled        opentui.RGBA // Foreground when disabled
+	FocusForeground opentui.RGBA // Label with the keyboard focus
+	FocusBackground opentui.RGBA
+}
+
+// DefaultToggleStyle draws white labels, gray when disabled, and the focused one in
+// black on cyan.
+var DefaultToggleStyle = ToggleStyle{
+	Foreground:      opentui.White,
+	Disabled:        opentui.Gray,
+	FocusForeground: opentui.Black,
+	FocusBackground: opentui.Cyan,
+}
+
+// Checkbox is a labeled box that Space or a click checks and unchecks.
+type Checkbox struct {
+	ID       uint32 // Hit grid ID, see Register
+	Position opentui.Position
+	Label    string
+	Disabled bool
+	Focused  bool // Set while the checkbox has the keyboard focus, to highlight it; see FocusManager
+	Glyphs   Glyphs
+	Style    ToggleStyle
+	OnChange func(checked bool) // Called when the box gets checked or unchecked
+
+	checked  bool
+	renderer *opentui.Renderer
+}
+
+// NewCheckbox creates an unchecked checkbox at pos.
+func NewCheckbox(id uint32, pos opentui.Position, label string) *Checkbox {
+	return &Checkbox{ID: id, Position: pos, Label: label, Glyphs: CheckboxGlyphs, Style: DefaultToggleStyle}
+}
+
+// Checked reports whether the box is checked.
+func (c *Checkbox) Checked() bool {
+	return c.checked
+}
+
+// SetChecked checks or unchecks the box, calling OnChange if that changes it. It works
+// on disabled checkboxes too.
+func (c *Checkbox) SetChecked(checked bool) {
+	changed := checked != c.checked
+	c.checked = checked
+	if changed && c.OnChange != nil {
+		c.OnChange(checked)
+	}
+}
+
+// Toggle checks an unchecked box and unchecks a checked one, unless disabled.
+func (c *Checkbox) Toggle() {
+	if !c.Disabled {
+		c.SetChecked(!c.checked)
+	}
+}
+
+// CanFocus reports whether the checkbox can take the keyboard focus, that is whether
+// it's enabled.
+func (c *Checkbox) CanFocus() bool {
+	return !c.Disabled
+}
+
+// SetFocused sets Focused, for FocusManager.
+func (c *Checkbox) SetFocused(focused bool) {
+	c.Focused = focused
+}
+
+// Rect returns the cells covered by the glyph and the label.
+func (c *Checkbox) Rect() opentui.Rect {
+	width := c.Glyphs.width()
+	if c.Label != "" {
+		width += 1 + textWidth(c.Label)
+	}
+	return opentui.Rect{Position: c.Position, Size: opentui.Size{Width: width, Height: 1}}
+}
+
+// HandleKey toggles the box on Space. Returns whether the key was Space.
+func (c *Checkbox) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Key != ' ' || ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	c.Toggle()
+	return true
+}
+
+// Register adds the checkbox to the renderer's hit grid under its ID, so that
+// HandleMouse ignores clicks on it where an area registered later covers it. The grid
+// is rebuilt every frame, so register the checkbox whenever it's rendered.
+func (c *Checkbox) Register(renderer *opentui.Renderer) error {
+	rect := c.Rect()
+	if err := renderer.AddToHitGrid(rect.X, rect.Y, rect.Width, rect.Height, c.ID); err != nil {
+		return err
+	}
+	c.renderer = renderer
+	return nil
+}
+
+// HandleMouse toggles the box when the glyph or the label is clicked with the left
+// button. Returns whether the event was over the checkbox.
+func (c *Checkbox) HandleMouse(ev opentui.MouseEvent) bool {
+	if !hits(c.renderer, c.ID, c.Rect(), ev.Position) {
+		return false
+	}
+	if ev.Button == opentui.MouseLeft && ev.Pressed && !ev.Motion {
+		c.Toggle()
+	}
+	return true
+}
+
+// Render draws the glyph and the label into buf, keeping the background.
+func (c *Checkbox) Render(buf *opentui.Buffer) error {
+	return drawToggle(buf, c.Position, c.Glyphs, c.checked, c.Label, c.Style, c.Focused, c.Disabled)
+}
+
+// hits reports whether pos is in rect and, once registered with a renderer, the hit
+// grid has the area id there.
+func hits(renderer *opentui.Renderer, id uint32, rect opentui.Rect, pos opentui.Position) bool {
+	if !rect.Contains(pos.X, pos.Y) {
+		return false
+	}
+	if renderer == nil || !renderer.Valid() {
+		return true
+	}
+	hit, err := renderer.HitTest(uint32(pos.X), uint32(pos.Y))
+	return err == nil && hit.Found && hit.ID == id
+}
+
+// drawToggle draws the on or off glyph at pos, padded to the width of the wider one,
+// and the label after a space, highlighted if focused.
+func drawToggle(buf *opentui.Buffer, pos opentui.Position, glyphs Glyphs, on bool, label string, style ToggleStyle, focused, disabled bool) error {
+	if pos.X < 0 || pos.Y < 0 {
+		return nil
+	}
+	x, y := uint32(pos.X), uint32(pos.Y)
+	glyph := glyphs.Off
+	if on {
+		glyph = glyphs.On
+	}
+	fg := style.Foreground
+	if disabled {
+		fg = style.Disabled
+	}
+	if err := buf.DrawText(glyph, x, y, fg, nil, 0); err != nil {
+		return err
+	}
+	if label == "" {
+		return nil
+	}
+	var bg *opentui.RGBA
+	if focused && !disabled {
+		fg, bg = style.FocusForeground, &style.FocusBackground
+	}
+	return buf.DrawText(label, x+glyphs.width()+1, y, fg, bg, 0)
+}
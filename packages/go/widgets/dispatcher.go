@@ -0,0 +1,258 @@
+package widgets
+
+import (
+	"github.com/sst/opentui/packages/go"
+)
+
+// leaveEvent is the pointer event passed to a region left without the pointer moving,
+// when input gets captured or the region is removed. It's over no cell.
+var leaveEvent = opentui.MouseEvent{Position: opentui.Position{X: -1, Y: -1}, Button: opentui.MouseNone, Motion: true}
+
+// MouseWidget is a widget that a Dispatcher passes mouse events to. All the widgets of
+// this package with a hit grid ID are.
+type MouseWidget interface {
+	HandleMouse(ev opentui.MouseEvent) bool
+	Register(renderer *opentui.Renderer) error
+}
+
+// InputCapturer takes all the input while it captures it, see Dispatcher.Capture.
+// Modal is one.
+type InputCapturer interface {
+	HandleKey(ev opentui.KeyEvent) bool
+	HandleMouse(ev opentui.MouseEvent) bool
+}
+
+// Region is an area of the screen that receives the mouse events over it.
+type Region struct {
+	ID      uint32       // Hit grid ID
+	Rect    opentui.Rect // Cells covered; widgets register their own cells, so theirs is only used without a renderer
+	OnMouse func(ev opentui.MouseEvent)
+	OnEnter func(ev opentui.MouseEvent) // Called when the pointer moves onto the region
+	OnLeave func(ev opentui.MouseEvent) // Called when the pointer moves off the region, with an event that isn't over it
+
+	widget MouseWidget
+}
+
+// Dispatcher routes input events to the regions and widgets on screen. Mouse events go
+// to the region under the pointer, found in the renderer's hit grid, with OnEnter and
+// OnLeave called as the pointer moves between regions. Key events go to the focused
+// widget of Focus, and to OnKey when it doesn't handle them. An InputCapturer, such
+// as an open modal, can take all the input from both.
+//
+// Regions don't have to be widgets: Add registers a plain rect with a callback.
+type Dispatcher struct {
+	Focus   *FocusManager                  // Gets the key events first; nil sends them all to OnKey
+	OnKey   func(ev opentui.KeyEvent) bool // App-wide shortcuts, for the keys that the focused widget doesn't handle
+	OnMouse func(ev opentui.MouseEvent)    // Called with every mouse event before it's routed, unless captured
+
+	renderer *opentui.Renderer
+	regions  []*Region
+	hovered  *Region
+	captures []InputCapturer
+}
+
+// NewDispatcher creates a dispatcher hit testing in the grid of renderer, passing keys
+// to the focused widget of focus. Both can be nil: without a renderer, regions are hit
+// tested with their Rect, the region added last on top.
+func NewDispatcher(renderer *opentui.Renderer, focus *FocusManager) *Dispatcher {
+	return &Dispatcher{Focus: focus, renderer: renderer}
+}
+
+// Add registers a region covering rect under id, calling onMouse with the mouse events
+// over it. Regions added later are on top of the earlier ones.
+func (d *Dispatcher) Add(id uint32, rect opentui.Rect, onMouse func(ev opentui.MouseEvent)) *Region {
+	r := &Region{ID: id, Rect: rect, OnMouse: onMouse}
+	d.regions = append(d.regions, r)
+	return r
+}
+
+// AddWidget registers w as a region under id, its hit grid ID. It gets the mouse
+// events over it, and the event that moves the pointer off it so that it drops its
+// hover state. Clicking it focuses it if it's registered with Focus.
+func (d *Dispatcher) AddWidget(id uint32, w MouseWidget) *Region {
+	r := d.Add(id, opentui.Rect{}, func(ev opentui.MouseEvent) { w.HandleMouse(ev) })
+	r.OnLeave = r.OnMouse
+	r.widget = w
+	return r
+}
+
+// Remove unregisters r, calling its OnLeave if the pointer is over it.
+func (d *Dispatcher) Remove(r *Region) {
+	for i, other := range d.regions {
+		if other == r {
+			d.regions = append(d.regions[:i], d.regions[i+1:]...)
+			break
+		}
+	}
+	if d.hovered == r {
+		d.hover(nil, leaveEvent)
+	}
+}
+
+// Register adds the regions to the renderer's hit grid in the order they were added,
+// widgets registering themselves. The grid is rebuilt every frame, so call Register
+// every frame once the regions are rendered and their rects are up to date.
+func (d *Dispatcher) Register() error {
+	if d.renderer == nil {
+		return nil
+	}
+	for _, r := range d.regions {
+		var err error
+		if r.widget != nil {
+			err = r.widget.Register(d.renderer)
+		} else {
+			err = d.renderer.AddToHitGrid(r.Rect.X, r.Rect.Y, r.Rect.Width, r.Rect.Height, r.ID)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Capture sends all the input to c until Release is called with it, or until c
+// reports that it no longer captures input if it has a CapturesInput() bool method,
+// like Modal. Captures stack: the last one gets the input.
+func (d *Dispatcher) Capture(c InputCapturer) {
+	d.hover(nil, leaveEvent)
+	d.captures = append(d.captures, c)
+}
+
+// Release ends the capture of input by c.
+func (d *Dispatcher) Release(c InputCapturer) {
+	for i := len(d.captures) - 1; i >= 0; i-- {
+		if d.captures[i] == c {
+			d.captures = append(d.captures[:i], d.captures[i+1:]...)
+			return
+		}
+	}
+}
+
+// Captured returns what captures the input, or nil.
+func (d *Dispatcher) Captured() InputCapturer {
+	for len(d.captures) > 0 {
+		c := d.captures[len(d.captures)-1]
+		if w, ok := c.(interface{ CapturesInput() bool }); !ok || w.CapturesInput() {
+			return c
+		}
+		d.captures = d.captures[:len(d.captures)-1]
+	}
+	return nil
+}
+
+// Hovered returns the region under the pointer, or nil.
+func (d *Dispatcher) Hovered() *Region {
+	return d.hovered
+}
+
+// HandleKey passes a key event to what captures the input, or else to Focus and then
+// to OnKey. Returns whether it was handled; captured events always are.
+func (d *Dispatcher) HandleKey(ev opentui.KeyEvent) bool {
+	if c := d.Captured(); c != nil {
+		c.HandleKey(ev)
+		return true
+	}
+	if d.Focus != nil && d.Focus.HandleKey(ev) {
+		return true
+	}
+	return d.OnKey != nil && d.OnKey(ev)
+}
+
+// HandleMouse passes a mouse event to what captures the input, or else to the region
+// under the pointer, after calling OnLeave and OnEnter if the pointer moved to another
+// region. Returns whether the event went somewhere.
+func (d *Dispatcher) HandleMouse(ev opentui.MouseEvent) bool {
+	if c := d.Captured(); c != nil {
+		c.HandleMouse(ev)
+		return true
+	}
+	if d.OnMouse != nil {
+		d.OnMouse(ev)
+	}
+	r := d.regionAt(ev.Position)
+	d.hover(r, ev)
+	if r == nil {
+		return false
+	}
+	if r.widget != nil && d.Focus != nil && ev.Button == opentui.MouseLeft && ev.Pressed && !ev.Motion {
+		if f, ok := r.widget.(Focusable); ok {
+			d.Focus.Focus(f)
+		}
+	}
+	if r.OnMouse != nil {
+		r.OnMouse(ev)
+	}
+	return true
+}
+
+// Dispatch passes an opentui.KeyEvent or opentui.MouseEvent to HandleKey or
+// HandleMouse. Returns whether it was handled; other events never are.
+func (d *Dispatcher) Dispatch(ev interface{}) bool {
+	switch ev := ev.(type) {
+	case opentui.KeyEvent:
+		return d.HandleKey(ev)
+	case opentui.MouseEvent:
+		return d.HandleMouse(ev)
+	}
+	return false
+}
+
+// DispatchPending dispatches the events waiting in events without blocking, for a
+// RunLoop frame function to call before drawing. Returns false once events is closed.
+func (d *Dispatcher) DispatchPending(events <-chan interface{}) bool {
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return false
+			}
+			d.Dispatch(ev)
+		default:
+			return true
+		}
+	}
+}
+
+// hover makes r the region under the pointer, calling OnLeave on the one that was and
+// OnEnter on r.
+func (d *Dispatcher) hover(r *Region, ev opentui.MouseEvent) {
+	if r == d.hovered {
+		return
+	}
+	if old := d.hovered; old != nil {
+		d.hovered = nil
+		if old.OnLeave != nil {
+			old.OnLeave(ev)
+		}
+	}
+	d.hovered = r
+	if r != nil && r.OnEnter != nil {
+		r.OnEnter(ev)
+	}
+}
+
+// regionAt returns the region under the cell at pos, from the hit grid if there's a
+// renderer, or nil.
+func (d *Dispatcher) regionAt(pos opentui.Position) *Region {
+	if d.renderer != nil && d.renderer.Valid() {
+		if pos.X < 0 || pos.Y < 0 {
+			return nil
+		}
+		hit, err := d.renderer.HitTest(uint32(pos.X), uint32(pos.Y))
+		if err != nil || !hit.Found {
+			return nil
+		}
+		for i := len(d.regions) - 1; i >= 0; i-- {
+			if d.regions[i].ID == hit.ID {
+				return d.regions[i]
+			}
+		}
+		return nil
+	}
+	for i := len(d.regions) - 1; i >= 0; i-- {
+		if d.regions[i].Rect.Contains(pos.X, pos.Y) {
+			return d.regions[i]
+		}
+	}
+	return nil
+}
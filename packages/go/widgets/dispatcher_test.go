@@ -0,0 +1,102 @@
+package widgets
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func rect(x, y int32, width, height uint32) opentui.Rect {
+	return opentui.Rect{Position: opentui.Position{X: x, Y: y}, Size: opentui.Size{Width: width, Height: height}}
+}
+
+func TestDispatcherMouse(t *testing.T) {
+	d := NewDispatcher(nil, nil)
+	var log []string
+	record := func(name string) func(opentui.MouseEvent) {
+		return func(opentui.MouseEvent) { log = append(log, name) }
+	}
+	back := d.Add(1, rect(0, 0, 20, 10), record("back"))
+	back.OnEnter, back.OnLeave = record("enter back"), record("leave back")
+	front := d.Add(2, rect(5, 2, 4, 2), record("front"))
+	front.OnEnter, front.OnLeave = record("enter front"), record("leave front")
+
+	d.HandleMouse(mouseAt(1, 1, opentui.MouseNone, false, true))
+	d.HandleMouse(mouseAt(6, 3, opentui.MouseNone, false, true))
+	d.HandleMouse(mouseAt(6, 3, opentui.MouseLeft, true, false))
+	if d.HandleMouse(mouseAt(30, 3, opentui.MouseNone, false, true)) {
+		t.Error("an event over no region should not be handled")
+	}
+	want := []string{"enter back", "back", "leave back", "enter front", "front", "front", "leave front"}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("calls %v, want %v", log, want)
+	}
+
+	log = nil
+	d.HandleMouse(mouseAt(6, 3, opentui.MouseNone, false, true))
+	d.Remove(front)
+	if d.Hovered() != nil || !reflect.DeepEqual(log, []string{"enter front", "front", "leave front"}) {
+		t.Errorf("removing the hovered region: calls %v", log)
+	}
+}
+
+func TestDispatcherKeys(t *testing.T) {
+	box := NewCheckbox(1, opentui.Position{X: 0, Y: 0}, "Sound")
+	group := NewRadioGroup(2, opentui.Position{X: 0, Y: 1}, "Low", "High")
+	d := NewDispatcher(nil, NewFocusManager(box, group))
+	var shortcuts []rune
+	d.OnKey = func(ev opentui.KeyEvent) bool {
+		shortcuts = append(shortcuts, ev.Key)
+		return ev.Key == 'q'
+	}
+
+	d.Dispatch(opentui.KeyEvent{Key: ' '})
+	if !box.Checked() {
+		t.Error("Space should go to the focused checkbox")
+	}
+	if !d.Dispatch(opentui.KeyEvent{Key: 'q'}) || d.Dispatch(opentui.KeyEvent{Key: 'z'}) {
+		t.Error("keys the focused widget ignores should go to OnKey")
+	}
+
+	// A click focuses the widget it lands on
+	region := d.AddWidget(group.ID, group)
+	region.Rect = group.Rect()
+	d.HandleMouse(mouseAt(1, 2, opentui.MouseLeft, true, false))
+	if !group.Focused || box.Focused || group.Selected() != 1 {
+		t.Errorf("clicking High: group focused %v, box focused %v, selected %d", group.Focused, box.Focused, group.Selected())
+	}
+
+	modal := &Modal{Title: "Quit?", Buttons: []string{"Yes", "No"}, Cancel: -1}
+	modal.Open()
+	d.Capture(modal)
+	d.Dispatch(opentui.KeyEvent{Key: 'q'})
+	d.Dispatch(opentui.KeyEvent{Key: opentui.KeyUp})
+	if group.Selected() != 1 || !reflect.DeepEqual(shortcuts, []rune{'q', 'z'}) {
+		t.Error("keys should go to the modal while it captures the input")
+	}
+	d.Dispatch(opentui.KeyEvent{Key: opentui.KeyEnter})
+	if modal.IsOpen() || d.Captured() != nil {
+		t.Error("closing the modal should end the capture")
+	}
+	d.Dispatch(opentui.KeyEvent{Key: opentui.KeyUp})
+	if group.Selected() != 0 {
+		t.Error("keys should go back to the focused widget after the capture")
+	}
+}
+
+func TestDispatchPending(t *testing.T) {
+	box := NewCheckbox(1, opentui.Position{}, "Sound")
+	d := NewDispatcher(nil, NewFocusManager(box))
+	events := make(chan interface{}, 4)
+	events <- opentui.KeyEvent{Key: ' '}
+	events <- opentui.KeyEvent{Key: ' '}
+	events <- opentui.KeyEvent{Key: ' '}
+	if !d.DispatchPending(events) || !box.Checked() {
+		t.Error("all pending events should be dispatched")
+	}
+	close(events)
+	if d.DispatchPending(events) {
+		t.Error("a closed channel should end the dispatch")
+	}
+}
@@ -0,0 +1,257 @@
+package widgets
+
+import (
+	"sort"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// Focusable is a widget that takes key events while it has the keyboard focus.
+//
+// A widget can also implement CanFocus() bool, returning false while it's disabled or
+// hidden so that the focus skips it, and SetFocused(bool), called when it gains or
+// loses the focus so that it can restyle. Checkbox, RadioGroup and Button do both.
+type Focusable interface {
+	HandleKey(ev opentui.KeyEvent) bool
+}
+
+// FocusEntry is a widget registered with a FocusManager.
+type FocusEntry struct {
+	Widget   Focusable
+	TabIndex int    // Position in the tab order, lower first; entries with the same index keep the order they were added in
+	Group    string // Entries sharing a non-empty group are navigated with the arrow keys, see FocusManager.ArrowKeys
+	OnFocus  func() // Called when the widget gains the focus, e.g. to move the cursor into a text input
+	OnBlur   func() // Called when the widget loses the focus
+
+	seq int
+}
+
+// canFocus reports whether the focus may land on the entry.
+func (e *FocusEntry) canFocus() bool {
+	if w, ok := e.Widget.(interface{ CanFocus() bool }); ok {
+		return w.CanFocus()
+	}
+	return true
+}
+
+// FocusManager decides which widget receives key events. Tab and Shift+Tab cycle the
+// focus through the widgets in tab order, skipping the ones that can't take it, and
+// the other keys go to the focused widget.
+//
+// The zero value is an empty manager with arrow key navigation off.
+type FocusManager struct {
+	// ArrowKeys moves the focus within the group of the focused widget with the arrow
+	// keys, for the keys that the widget doesn't handle itself
+	ArrowKeys bool
+
+	entries []*FocusEntry
+	focused *FocusEntry
+	seq     int
+}
+
+// NewFocusManager creates a manager with widgets added in tab order, the first one
+// that can take the focus focused.
+func NewFocusManager(widgets ...Focusable) *FocusManager {
+	m := &FocusManager{}
+	for _, w := range widgets {
+		m.Add(w)
+	}
+	m.Next()
+	return m
+}
+
+// Add registers w after the widgets added so far and returns its entry, whose tab
+// index, group and callbacks can be set. Adding a widget twice returns its entry.
+func (m *FocusManager) Add(w Focusable) *FocusEntry {
+	if e := m.entry(w); e != nil {
+		return e
+	}
+	m.seq++
+	e := &FocusEntry{Widget: w, seq: m.seq}
+	m.entries = append(m.entries, e)
+	return e
+}
+
+// AddAt registers w at tabIndex in the tab order and returns its entry.
+func (m *FocusManager) AddAt(w Focusable, tabIndex int) *FocusEntry {
+	e := m.Add(w)
+	e.TabIndex = tabIndex
+	return e
+}
+
+// Remove unregisters w. When w has the focus, the focus moves to the next widget in
+// tab order that can take it, or to none.
+func (m *FocusManager) Remove(w Focusable) {
+	e := m.entry(w)
+	if e == nil {
+		return
+	}
+	var next *FocusEntry
+	if e == m.focused {
+		next = m.step(e, 1, "")
+		if next == e {
+			next = nil
+		}
+	}
+	for i, other := range m.entries {
+		if other == e {
+			m.entries = append(m.entries[:i], m.entries[i+1:]...)
+			break
+		}
+	}
+	if e == m.focused {
+		m.setFocus(next)
+	}
+}
+
+// Entry returns the entry of w, or nil if w isn't registered.
+func (m *FocusManager) Entry(w Focusable) *FocusEntry {
+	return m.entry(w)
+}
+
+func (m *FocusManager) entry(w Focusable) *FocusEntry {
+	for _, e := range m.entries {
+		if e.Widget == w {
+			return e
+		}
+	}
+	return nil
+}
+
+// Focused returns the widget with the focus, or nil.
+func (m *FocusManager) Focused() Focusable {
+	if m.focused == nil {
+		return nil
+	}
+	return m.focused.Widget
+}
+
+// IsFocused reports whether w has the focus.
+func (m *FocusManager) IsFocused(w Focusable) bool {
+	return m.focused != nil && m.focused.Widget == w
+}
+
+// Focus gives the focus to w. Returns false, leaving the focus alone, if w isn't
+// registered or can't take the focus.
+func (m *FocusManager) Focus(w Focusable) bool {
+	e := m.entry(w)
+	if e == nil || !e.canFocus() {
+		return false
+	}
+	m.setFocus(e)
+	return true
+}
+
+// Blur takes the focus away, so that no widget has it.
+func (m *FocusManager) Blur() {
+	m.setFocus(nil)
+}
+
+// Next moves the focus to the next widget in tab order that can take it, wrapping
+// around. Without a focused widget it goes to the first one.
+func (m *FocusManager) Next() {
+	m.setFocus(m.step(m.focused, 1, ""))
+}
+
+// Prev moves the focus to the previous widget in tab order that can take it, wrapping
+// around. Without a focused widget it goes to the last one.
+func (m *FocusManager) Prev() {
+	m.setFocus(m.step(m.focused, -1, ""))
+}
+
+// HandleKey moves the focus on Tab and Shift+Tab, or passes the key to the focused
+// widget. With ArrowKeys set, the arrow keys that the widget doesn't handle move the
+// focus within its group. Returns whether the key was handled.
+func (m *FocusManager) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Key == '\t' && ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) == 0 {
+		if ev.Modifiers&opentui.ModShift != 0 {
+			m.Prev()
+		} else {
+			m.Next()
+		}
+		return true
+	}
+	if m.focused == nil {
+		return false
+	}
+	if m.focused.Widget.HandleKey(ev) {
+		return true
+	}
+	if !m.ArrowKeys || m.focused.Group == "" || ev.Modifiers != 0 {
+		return false
+	}
+	switch ev.Key {
+	case opentui.KeyUp, opentui.KeyLeft:
+		m.setFocus(m.step(m.focused, -1, m.focused.Group))
+	case opentui.KeyDown, opentui.KeyRight:
+		m.setFocus(m.step(m.focused, 1, m.focused.Group))
+	default:
+		return false
+	}
+	return true
+}
+
+// order returns the entries in tab order.
+func (m *FocusManager) order() []*FocusEntry {
+	order := append([]*FocusEntry(nil), m.entries...)
+	sort.SliceStable(order, func(i, j int) bool {
+		if order[i].TabIndex != order[j].TabIndex {
+			return order[i].TabIndex < order[j].TabIndex
+		}
+		return order[i].seq < order[j].seq
+	})
+	return order
+}
+
+// step returns the entry that can take the focus dir entries away from from in tab
+// order, wrapping around, within group if it isn't empty. Returns from itself when
+// no other entry qualifies, or nil when none does at all.
+func (m *FocusManager) step(from *FocusEntry, dir int, group string) *FocusEntry {
+	order := m.order()
+	count := len(order)
+	if count == 0 {
+		return nil
+	}
+	start := -1
+	if dir < 0 {
+		start = count
+	}
+	for i, e := range order {
+		if e == from {
+			start = i
+		}
+	}
+	for n := 1; n <= count; n++ {
+		e := order[((start+dir*n)%count+count)%count]
+		if (group == "" || e.Group == group) && e.canFocus() {
+			return e
+		}
+	}
+	return nil
+}
+
+// setFocus moves the focus to e, blurring the widget that had it.
+func (m *FocusManager) setFocus(e *FocusEntry) {
+	if e == m.focused {
+		return
+	}
+	if old := m.focused; old != nil {
+		m.focused = nil
+		if w, ok := old.Widget.(interface{ SetFocused(bool) }); ok {
+			w.SetFocused(false)
+		}
+		if old.OnBlur != nil {
+			old.OnBlur()
+		}
+	}
+	m.focused = e
+	if e == nil {
+		return
+	}
+	if w, ok := e.Widget.(interface{ SetFocused(bool) }); ok {
+		w.SetFocused(true)
+	}
+	if e.OnFocus != nil {
+		e.OnFocus()
+	}
+}
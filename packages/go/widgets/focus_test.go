@@ -0,0 +1,113 @@
+package widgets
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// keyLog is a focusable widget recording the keys it gets.
+type keyLog struct {
+	name   string
+	hidden bool
+	keys   []rune
+}
+
+func (k *keyLog) HandleKey(ev opentui.KeyEvent) bool {
+	k.keys = append(k.keys, ev.Key)
+	return ev.Key == 'x'
+}
+
+func (k *keyLog) CanFocus() bool {
+	return !k.hidden
+}
+
+func TestFocusManagerTabOrder(t *testing.T) {
+	a := NewCheckbox(0, opentui.Position{}, "a")
+	b := NewCheckbox(0, opentui.Position{}, "b")
+	c := &keyLog{name: "c"}
+	m := NewFocusManager(a, b)
+	m.AddAt(c, -1)
+
+	var events []string
+	m.Entry(a).OnFocus = func() { events = append(events, "focus a") }
+	m.Entry(a).OnBlur = func() { events = append(events, "blur a") }
+
+	if m.Focused() != a || !a.Focused {
+		t.Fatalf("focused %v, want the first widget added", m.Focused())
+	}
+	tab := opentui.KeyEvent{Key: '\t'}
+	backTab := opentui.KeyEvent{Key: '\t', Modifiers: opentui.ModShift}
+	m.HandleKey(tab)
+	if m.Focused() != b || a.Focused || !b.Focused {
+		t.Errorf("Tab focused %v", m.Focused())
+	}
+	b.Disabled = true
+	m.HandleKey(backTab)
+	m.HandleKey(backTab)
+	if m.Focused() != c {
+		t.Errorf("Shift+Tab twice from b focused %v, want c, first in tab order", m.Focused())
+	}
+	if m.HandleKey(opentui.KeyEvent{Key: 'y'}) || !m.HandleKey(opentui.KeyEvent{Key: 'x'}) {
+		t.Error("keys should be handled as the focused widget handles them")
+	}
+	if want := []rune{'y', 'x'}; !reflect.DeepEqual(c.keys, want) {
+		t.Errorf("focused widget got %q, want %q", c.keys, want)
+	}
+	if m.Focus(b) || m.Focused() != c {
+		t.Error("a disabled widget should not take the focus")
+	}
+	if want := []string{"blur a", "focus a", "blur a"}; !reflect.DeepEqual(events, want) {
+		t.Errorf("callbacks %v, want %v", events, want)
+	}
+}
+
+func TestFocusManagerRemove(t *testing.T) {
+	a, b, c, d := &keyLog{name: "a"}, &keyLog{name: "b"}, &keyLog{name: "c"}, &keyLog{name: "d"}
+	m := NewFocusManager(a, b, c, d)
+	c.hidden = true
+	m.Focus(b)
+	m.Remove(b)
+	if m.Focused() != d {
+		t.Errorf("removing the focused widget focused %v, want d", m.Focused())
+	}
+	m.Remove(a)
+	if m.Focused() != d {
+		t.Errorf("removing another widget moved the focus to %v", m.Focused())
+	}
+	m.Remove(d)
+	if m.Focused() != nil {
+		t.Errorf("focused %v with no widget able to take the focus", m.Focused())
+	}
+	if m.HandleKey(opentui.KeyEvent{Key: 'x'}) {
+		t.Error("keys should not be handled without a focused widget")
+	}
+}
+
+func TestFocusManagerArrowKeys(t *testing.T) {
+	first := NewButton(0, opentui.Rect{}, "OK", NewStyle(opentui.Blue))
+	second := NewButton(0, opentui.Rect{}, "Cancel", NewStyle(opentui.Blue))
+	other := &keyLog{}
+	m := NewFocusManager(first, second, other)
+	m.Entry(first).Group = "buttons"
+	m.Entry(second).Group = "buttons"
+
+	right := opentui.KeyEvent{Key: opentui.KeyRight}
+	if m.HandleKey(right) {
+		t.Error("arrow keys should not move the focus unless enabled")
+	}
+	m.ArrowKeys = true
+	m.HandleKey(right)
+	if m.Focused() != second || !second.Focused {
+		t.Errorf("Right focused %v, want the next button", m.Focused())
+	}
+	m.HandleKey(right)
+	if m.Focused() != first {
+		t.Errorf("Right should wrap around within the group, focused %v", m.Focused())
+	}
+	m.HandleKey(opentui.KeyEvent{Key: opentui.KeyEnter})
+	if first.Clicks() != 1 {
+		t.Error("Enter should click the focused button")
+	}
+}
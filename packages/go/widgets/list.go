@@ -0,0 +1,323 @@
+package widgets
+
+import (
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// doubleClickTime is the longest time between two clicks on an item that activate it
+const doubleClickTime = 500 * time.Millisecond
+
+// ListItem is an entry of a List, plain text drawn in the colors of the list or
+// styled chunks.
+type ListItem struct {
+	Text   string              // Drawn in the list's colors, unless Chunks is set
+	Chunks []opentui.TextChunk // Styled text; colors and attributes left nil come from the list
+}
+
+// StringItems makes a plain item of each string.
+func StringItems(texts ...string) []ListItem {
+	items := make([]ListItem, len(texts))
+	for i, text := range texts {
+		items[i] = ListItem{Text: text}
+	}
+	return items
+}
+
+// chunks returns the item as styled chunks.
+func (item ListItem) chunks() []opentui.TextChunk {
+	if item.Chunks != nil {
+		return item.Chunks
+	}
+	return []opentui.TextChunk{{Text: item.Text}}
+}
+
+// ListStyle holds the colors of a list and of its selected item.
+type ListStyle struct {
+	Foreground         opentui.RGBA
+	Background         opentui.RGBA
+	Attributes         opentui.Attributes
+	SelectedForeground opentui.RGBA
+	SelectedBackground opentui.RGBA
+	SelectedAttributes opentui.Attributes
+}
+
+// DefaultListStyle is white on black, with the selected item in reverse.
+var DefaultListStyle = ListStyle{
+	Foreground:         opentui.White,
+	Background:         opentui.Black,
+	SelectedForeground: opentui.Black,
+	SelectedBackground: opentui.White,
+}
+
+// List shows items one per row in a rect, with one of them selected. It scrolls so
+// the selection stays in view. Keys move the selection (HandleKey), a click selects
+// and the mouse wheel scrolls (HandleMouse). Items are addressed by their index in
+// the items given, also when a filter hides some of them.
+type List struct {
+	Rect       opentui.Rect
+	Style      ListStyle
+	OnSelect   func(index int) // Called when the selection moves to another item
+	OnActivate func(index int) // Called on Enter or a double click on an item
+
+	items     []ListItem
+	filter    func(index int, item ListItem) bool
+	shown     []int // indices of the items the filter keeps
+	selected  int   // position of the selected item in shown
+	offset    int   // position in shown of the item on the first row
+	lastClick time.Time
+	lastRow   int // position in shown of the item clicked last, for double clicks
+}
+
+// NewList creates a list of items covering rect, with the first item selected.
+func NewList(rect opentui.Rect, items []ListItem, style ListStyle) *List {
+	l := &List{Rect: rect, Style: style, lastRow: -1}
+	l.SetItems(items)
+	return l
+}
+
+// Items returns the items of the list, including those hidden by the filter.
+func (l *List) Items() []ListItem {
+	return l.items
+}
+
+// SetItems replaces the items, selecting the first one shown.
+func (l *List) SetItems(items []ListItem) {
+	l.items = items
+	l.selected, l.offset = 0, 0
+	l.refilter()
+}
+
+// SetFilter shows only the items for which keep returns true, or every item for nil.
+// The selection stays on the same item if it is still shown, else moves to the first.
+func (l *List) SetFilter(keep func(index int, item ListItem) bool) {
+	current := l.Selected()
+	l.filter = keep
+	l.refilter()
+	l.selected = 0
+	for i, index := range l.shown {
+		if index == current {
+			l.selected = i
+		}
+	}
+	l.offset = scrollOffset(l.offset, l.selected, len(l.shown), l.height())
+}
+
+func (l *List) refilter() {
+	l.shown = l.shown[:0]
+	for i, item := range l.items {
+		if l.filter == nil || l.filter(i, item) {
+			l.shown = append(l.shown, i)
+		}
+	}
+	l.selected = min(l.selected, max(len(l.shown)-1, 0))
+	l.offset = scrollOffset(l.offset, l.selected, len(l.shown), l.height())
+}
+
+// Selected returns the index of the selected item, or -1 if no item is shown.
+func (l *List) Selected() int {
+	if len(l.shown) == 0 {
+		return -1
+	}
+	return l.shown[l.selected]
+}
+
+// Select selects the item at index and scrolls it into view. Items that don't exist
+// or are hidden by the filter are ignored.
+func (l *List) Select(index int) {
+	for i, shown := range l.shown {
+		if shown == index {
+			l.moveTo(i)
+			return
+		}
+	}
+}
+
+// Offset returns the number of shown items scrolled past the top of the list.
+func (l *List) Offset() int {
+	return l.offset
+}
+
+// height returns the number of rows of the list.
+func (l *List) height() int {
+	return int(l.Rect.Height)
+}
+
+// moveTo selects the shown item at position i, clamped to the items shown, and calls
+// OnSelect if the selection changed.
+func (l *List) moveTo(i int) {
+	if len(l.shown) == 0 {
+		return
+	}
+	i = max(0, min(i, len(l.shown)-1))
+	changed := i != l.selected
+	l.selected = i
+	l.offset = scrollOffset(l.offset, l.selected, len(l.shown), l.height())
+	if changed && l.OnSelect != nil {
+		l.OnSelect(l.shown[i])
+	}
+}
+
+// scrollOffset returns the offset to show count items on height rows starting from
+// offset, moved as little as possible to keep the item at selected in view and
+// clamped so that no rows are left empty past the last item.
+func scrollOffset(offset, selected, count, height int) int {
+	if height <= 0 || count <= height {
+		return 0
+	}
+	if selected < offset {
+		offset = selected
+	} else if selected >= offset+height {
+		offset = selected - height + 1
+	}
+	return max(0, min(offset, count-height))
+}
+
+// HandleKey moves the selection with Up or k, Down or j, PageUp, PageDown, Home or g
+// and End or G, and activates the selected item with Enter. Returns whether the key
+// was one of these.
+func (l *List) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	page := max(l.height(), 1)
+	switch ev.Key {
+	case opentui.KeyUp, 'k':
+		l.moveTo(l.selected - 1)
+	case opentui.KeyDown, 'j':
+		l.moveTo(l.selected + 1)
+	case opentui.KeyPageUp:
+		l.moveTo(l.selected - page)
+	case opentui.KeyPageDown:
+		l.moveTo(l.selected + page)
+	case opentui.KeyHome, 'g':
+		l.moveTo(0)
+	case opentui.KeyEnd, 'G':
+		l.moveTo(len(l.shown) - 1)
+	case opentui.KeyEnter, '\n':
+		l.activate()
+	default:
+		return false
+	}
+	return true
+}
+
+func (l *List) activate() {
+	if index := l.Selected(); index >= 0 && l.OnActivate != nil {
+		l.OnActivate(index)
+	}
+}
+
+// HandleMouse selects the item clicked with the left button, activating it on a
+// double click, and scrolls the list by a row for each step of the wheel without
+// moving the selection. Returns whether the event was over the list.
+func (l *List) HandleMouse(ev opentui.MouseEvent) bool {
+	if !l.Rect.Contains(ev.Position.X, ev.Position.Y) {
+		return false
+	}
+	switch {
+	case ev.Button == opentui.MouseWheelUp && ev.Pressed:
+		l.scroll(-1)
+	case ev.Button == opentui.MouseWheelDown && ev.Pressed:
+		l.scroll(1)
+	case ev.Button == opentui.MouseLeft && ev.Pressed && !ev.Motion:
+		row := l.offset + int(ev.Position.Y-l.Rect.Y)
+		if row >= len(l.shown) {
+			break
+		}
+		now := time.Now()
+		double := row == l.lastRow && now.Sub(l.lastClick) < doubleClickTime
+		l.moveTo(row)
+		if double {
+			l.activate()
+			now = time.Time{}
+		}
+		l.lastClick, l.lastRow = now, row
+	}
+	return true
+}
+
+// scroll moves the items in view by rows, keeping the selection.
+func (l *List) scroll(rows int) {
+	l.offset = max(0, min(l.offset+rows, len(l.shown)-l.height()))
+}
+
+// Render draws the rows of the list in view into buf, the selected one in the
+// selected colors. Items wider than the list are cut with an ellipsis.
+func (l *List) Render(buf *opentui.Buffer) error {
+	if l.Rect.X < 0 || l.Rect.Width == 0 {
+		return nil
+	}
+	x, width := uint32(l.Rect.X), l.Rect.Width
+	for row := 0; row < l.height(); row++ {
+		y := l.Rect.Y + int32(row)
+		if y < 0 {
+			continue
+		}
+		fg, bg, attrs := l.Style.Foreground, l.Style.Background, l.Style.Attributes
+		position := l.offset + row
+		if position == l.selected && position < len(l.shown) {
+			fg, bg, attrs = l.Style.SelectedForeground, l.Style.SelectedBackground, l.Style.SelectedAttributes
+		}
+		if err := buf.FillRect(x, uint32(y), width, 1, bg); err != nil {
+			return err
+		}
+		if position >= len(l.shown) {
+			continue
+		}
+		selected := position == l.selected
+		if err := drawChunks(buf, l.items[l.shown[position]].chunks(), x, uint32(y), width, fg, bg, attrs, selected); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawChunks draws chunks on one row from x, cut to width cells with an ellipsis if
+// they don't fit. Colors and attributes the chunks leave nil are fg, bg and attrs;
+// a selected row is drawn on bg whatever the chunks say.
+func drawChunks(buf *opentui.Buffer, chunks []opentui.TextChunk, x, y, width uint32, fg, bg opentui.RGBA, attrs opentui.Attributes, selected bool) error {
+	total := uint32(0)
+	for _, chunk := range chunks {
+		total += textWidth(chunk.Text)
+	}
+	budget := width
+	if total > width {
+		budget = width - 1
+	}
+
+	col := uint32(0)
+	for _, chunk := range chunks {
+		if col >= budget {
+			break
+		}
+		text := chunk.Text
+		if col+textWidth(text) > budget {
+			text = opentui.TruncateToWidth(text, budget-col, "")
+		}
+		chunkFg, chunkBg, chunkAttrs := fg, bg, attrs
+		if chunk.Foreground != nil {
+			chunkFg = *chunk.Foreground
+		}
+		if chunk.Background != nil && !selected {
+			chunkBg = *chunk.Background
+		}
+		if chunk.Attributes != nil {
+			chunkAttrs = *chunk.Attributes
+		}
+		if err := buf.DrawText(text, x+col, y, chunkFg, &chunkBg, chunkAttrs); err != nil {
+			return err
+		}
+		col += textWidth(text)
+	}
+	if budget < width {
+		return buf.DrawText("…", x+budget, y, fg, &bg, attrs)
+	}
+	return nil
+}
+
+func textWidth(text string) uint32 {
+	width, _ := opentui.MeasureText(text, opentui.WidthMethodUnicode)
+	return width
+}
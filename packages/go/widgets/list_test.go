@@ -0,0 +1,205 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestScrollOffset(t *testing.T) {
+	tests := []struct {
+		name                            string
+		offset, selected, count, height int
+		want                            int
+	}{
+		{"list shorter than the view", 3, 2, 4, 10, 0},
+		{"list as tall as the view", 1, 4, 5, 5, 0},
+		{"selection in view", 2, 4, 20, 5, 2},
+		{"selection above the view", 6, 3, 20, 5, 3},
+		{"selection below the view", 0, 7, 20, 5, 3},
+		{"selecting the last item", 0, 19, 20, 5, 15},
+		{"selecting the first item", 15, 0, 20, 5, 0},
+		{"offset past the end", 18, 18, 20, 5, 15},
+		{"no rows", 4, 9, 20, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := scrollOffset(tt.offset, tt.selected, tt.count, tt.height); got != tt.want {
+			t.Errorf("%s: scrollOffset(%d, %d, %d, %d) = %d, want %d", tt.name, tt.offset, tt.selected, tt.count, tt.height, got, tt.want)
+		}
+	}
+}
+
+func numberedItems(n int) []ListItem {
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("item %d", i)
+	}
+	return StringItems(texts...)
+}
+
+func TestListKeys(t *testing.T) {
+	rect := opentui.Rect{Size: opentui.Size{Width: 10, Height: 4}}
+	list := NewList(rect, numberedItems(10), DefaultListStyle)
+	var selected, activated []int
+	list.OnSelect = func(index int) { selected = append(selected, index) }
+	list.OnActivate = func(index int) { activated = append(activated, index) }
+
+	key := func(k rune) bool { return list.HandleKey(opentui.KeyEvent{Key: k}) }
+	steps := []struct {
+		key              rune
+		selected, offset int
+	}{
+		{'j', 1, 0},
+		{opentui.KeyDown, 2, 0},
+		{opentui.KeyPageDown, 6, 3},
+		{opentui.KeyEnd, 9, 6},
+		{'j', 9, 6},
+		{'k', 8, 6},
+		{opentui.KeyPageUp, 4, 4},
+		{opentui.KeyUp, 3, 3},
+		{'g', 0, 0},
+		{'k', 0, 0},
+		{'G', 9, 6},
+		{opentui.KeyHome, 0, 0},
+	}
+	for _, step := range steps {
+		if !key(step.key) {
+			t.Errorf("key %q not handled", step.key)
+		}
+		if list.Selected() != step.selected || list.Offset() != step.offset {
+			t.Errorf("after %q: selected %d at offset %d, want %d at %d", step.key, list.Selected(), list.Offset(), step.selected, step.offset)
+		}
+	}
+	if len(selected) != 10 {
+		t.Errorf("OnSelect called %d times for %v, want once per move", len(selected), selected)
+	}
+
+	key(opentui.KeyEnter)
+	if len(activated) != 1 || activated[0] != 0 {
+		t.Errorf("activated %v", activated)
+	}
+	if key('x') || list.HandleKey(opentui.KeyEvent{Key: 'j', Modifiers: opentui.ModCtrl}) {
+		t.Error("other keys should not be handled")
+	}
+}
+
+func TestListFilter(t *testing.T) {
+	rect := opentui.Rect{Size: opentui.Size{Width: 10, Height: 3}}
+	list := NewList(rect, numberedItems(10), DefaultListStyle)
+	list.Select(8)
+	if list.Selected() != 8 || list.Offset() != 6 {
+		t.Fatalf("Select(8): selected %d at offset %d", list.Selected(), list.Offset())
+	}
+
+	// Odd items only: item 8 is hidden, so the selection moves to the first shown
+	list.SetFilter(func(index int, item ListItem) bool { return index%2 == 1 })
+	if list.Selected() != 1 || list.Offset() != 0 {
+		t.Errorf("after filtering: selected %d at offset %d", list.Selected(), list.Offset())
+	}
+	list.HandleKey(opentui.KeyEvent{Key: opentui.KeyEnd})
+	if list.Selected() != 9 || list.Offset() != 2 {
+		t.Errorf("End: selected %d at offset %d", list.Selected(), list.Offset())
+	}
+	list.Select(4)
+	if list.Selected() != 9 {
+		t.Error("selecting a hidden item should be ignored")
+	}
+
+	list.SetFilter(func(index int, item ListItem) bool { return false })
+	list.HandleKey(opentui.KeyEvent{Key: 'j'})
+	if list.Selected() != -1 {
+		t.Errorf("an empty list selected %d", list.Selected())
+	}
+	list.SetFilter(nil)
+	if len(list.Items()) != 10 || list.Selected() != 0 {
+		t.Errorf("without a filter: selected %d", list.Selected())
+	}
+}
+
+func TestListMouse(t *testing.T) {
+	rect := opentui.Rect{Position: opentui.Position{X: 2, Y: 1}, Size: opentui.Size{Width: 10, Height: 3}}
+	list := NewList(rect, numberedItems(5), DefaultListStyle)
+	activated := -1
+	list.OnActivate = func(index int) { activated = index }
+
+	if list.HandleMouse(mouseAt(0, 2, opentui.MouseLeft, true, false)) {
+		t.Error("a click outside the list should be ignored")
+	}
+	list.HandleMouse(mouseAt(3, 3, opentui.MouseLeft, true, false))
+	if list.Selected() != 2 || activated != -1 {
+		t.Errorf("click on the third row: selected %d, activated %d", list.Selected(), activated)
+	}
+	list.HandleMouse(mouseAt(3, 3, opentui.MouseLeft, true, false))
+	if activated != 2 {
+		t.Errorf("double click activated %d", activated)
+	}
+
+	// The wheel scrolls up to the last page without moving the selection
+	for i := 0; i < 4; i++ {
+		list.HandleMouse(mouseAt(3, 2, opentui.MouseWheelDown, true, false))
+	}
+	if list.Offset() != 2 || list.Selected() != 2 {
+		t.Errorf("after scrolling down: offset %d, selected %d", list.Offset(), list.Selected())
+	}
+	list.HandleMouse(mouseAt(3, 1, opentui.MouseLeft, true, false))
+	if list.Selected() != 2 {
+		t.Errorf("click on the first row after scrolling selected %d", list.Selected())
+	}
+	list.HandleMouse(mouseAt(3, 2, opentui.MouseWheelUp, true, false))
+	if list.Offset() != 1 {
+		t.Errorf("after scrolling up: offset %d", list.Offset())
+	}
+}
+
+func TestListRender(t *testing.T) {
+	buffer := opentui.NewBuffer(8, 4, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+	buffer.Clear(opentui.Blue)
+
+	red := opentui.Red
+	items := []ListItem{
+		{Text: "short"},
+		{Text: "much too long"},
+		{Chunks: []opentui.TextChunk{{Text: "ab", Foreground: &red}, {Text: "漢字漢"}}},
+	}
+	rect := opentui.Rect{Size: opentui.Size{Width: 6, Height: 4}}
+	list := NewList(rect, items, DefaultListStyle)
+	list.Select(1)
+	if err := list.Render(buffer); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	rows, err := buffer.CaptureText()
+	if err != nil {
+		t.Fatalf("CaptureText failed: %v", err)
+	}
+	// The wide character that would straddle the ellipsis becomes a space, after the
+	// space capturing puts in the second cell of 漢
+	for i, want := range []string{"short ", "much …", "ab漢  …", "      "} {
+		if !strings.HasPrefix(rows[i], want) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+	for _, tc := range []struct {
+		x, y uint32
+		fg   opentui.RGBA
+		bg   opentui.RGBA
+	}{
+		{0, 0, opentui.White, opentui.Black},
+		{0, 1, opentui.Black, opentui.White},
+		{0, 2, opentui.Red, opentui.Black},
+		{2, 2, opentui.White, opentui.Black},
+		{0, 3, opentui.White, opentui.Black},
+		{6, 0, opentui.White, opentui.Blue},
+	} {
+		cell, _ := buffer.GetCell(tc.x, tc.y)
+		if cell.Background != tc.bg || (cell.Char != ' ' && cell.Foreground != tc.fg) {
+			t.Errorf("cell (%d, %d) = %v on %v, want %v on %v", tc.x, tc.y, cell.Foreground, cell.Background, tc.fg, tc.bg)
+		}
+	}
+}
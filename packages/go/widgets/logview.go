@@ -0,0 +1,417 @@
+package widgets
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// defaultLogLines is the number of lines a log view keeps unless told otherwise
+const defaultLogLines = 10000
+
+// LogLevel is the severity of a line of log output
+type LogLevel uint8
+
+const (
+	LevelNone LogLevel = iota // No level found in the line
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// logLevels maps the words that DefaultLogClassifier recognizes to their level
+var logLevels = map[string]LogLevel{
+	"DEBUG": LevelDebug, "TRACE": LevelDebug,
+	"INFO": LevelInfo,
+	"WARN": LevelWarn, "WARNING": LevelWarn,
+	"ERROR": LevelError, "ERR": LevelError, "FATAL": LevelError, "PANIC": LevelError,
+}
+
+// LogClassifier returns the level of a line of log output, without its line break.
+type LogClassifier func(line string) LogLevel
+
+// DefaultLogClassifier finds the level of lines that name it in upper case among
+// their first four words, such as "2024/05/01 12:00:00 WARN: disk almost full" or
+// "[ERROR] request failed". Brackets, parentheses and colons around the word are
+// ignored.
+func DefaultLogClassifier(line string) LogLevel {
+	for i, word := range strings.Fields(line) {
+		if i == 4 {
+			break
+		}
+		if level, ok := logLevels[strings.Trim(word, "[]():")]; ok {
+			return level
+		}
+	}
+	return LevelNone
+}
+
+// LogStyle holds the colors of a log view: the lines of each level and the timestamps.
+type LogStyle struct {
+	Text      opentui.RGBA // Lines without a level
+	Debug     opentui.RGBA
+	Info      opentui.RGBA
+	Warn      opentui.RGBA
+	Error     opentui.RGBA
+	Timestamp opentui.RGBA
+}
+
+// DefaultLogStyle draws debug lines in gray, warnings in yellow and errors in red.
+var DefaultLogStyle = LogStyle{
+	Text:      opentui.NewRGB(0.85, 0.85, 0.85),
+	Debug:     opentui.Gray,
+	Info:      opentui.NewRGB(0.55, 0.8, 0.95),
+	Warn:      opentui.NewRGB(0.95, 0.8, 0.35),
+	Error:     opentui.NewRGB(0.95, 0.4, 0.4),
+	Timestamp: opentui.NewRGB(0.45, 0.45, 0.5),
+}
+
+// color returns the color of lines of level.
+func (s LogStyle) color(level LogLevel) opentui.RGBA {
+	switch level {
+	case LevelDebug:
+		return s.Debug
+	case LevelInfo:
+		return s.Info
+	case LevelWarn:
+		return s.Warn
+	case LevelError:
+		return s.Error
+	}
+	return s.Text
+}
+
+// logEntry is what a log view knows of a line besides its text
+type logEntry struct {
+	time  time.Time
+	level LogLevel
+}
+
+// LogView is a pane of log output. It's an io.Writer, so log.SetOutput(view) sends
+// the standard logger to it, and it's safe to write to from any goroutine. Complete
+// lines are kept in a TextBuffer bounded to a number of lines, dropping the oldest
+// ones, colored by the level Classifier finds in them and stamped with the time they
+// were written at.
+//
+// The view follows the end of the log until it's scrolled up, and follows it again
+// on End. A filter hides the lines that don't match it. Only the lines in view are
+// drawn, however long the log.
+type LogView struct {
+	ID             uint32       // Hit grid ID, see Register
+	Rect           opentui.Rect // Cells covered
+	Style          LogStyle
+	Classifier     LogClassifier // DefaultLogClassifier unless replaced; nil leaves lines without a level
+	ShowTimestamps bool
+	TimeFormat     string // time.Format layout of the timestamps, "15:04:05" by default
+
+	mu        sync.Mutex
+	text      *opentui.TextBuffer
+	entries   []logEntry // one per line of text, the oldest first
+	pending   []byte     // start of a line whose line break wasn't written yet
+	dropped   uint64     // lines of text dropped so far
+	dirty     bool       // lines written since the line info was finalized
+	filter    func(line string) bool
+	matches   []int // entries shown while filtered
+	offset    int   // first row shown
+	following bool
+	renderer  *opentui.Renderer
+}
+
+// NewLogView creates an empty log view keeping about the last maxLines lines, 10000
+// if 0, see TextBuffer.SetMaxLines. It follows the end of the log and shows
+// timestamps.
+func NewLogView(id uint32, rect opentui.Rect, maxLines uint32) (*LogView, error) {
+	if maxLines == 0 {
+		maxLines = defaultLogLines
+	}
+	text := opentui.NewTextBuffer(0, opentui.WidthMethodUnicode)
+	if text == nil {
+		return nil, opentui.ErrAllocation
+	}
+	if err := text.SetMaxLines(maxLines); err != nil {
+		text.Close()
+		return nil, err
+	}
+	return &LogView{
+		ID:             id,
+		Rect:           rect,
+		Style:          DefaultLogStyle,
+		Classifier:     DefaultLogClassifier,
+		ShowTimestamps: true,
+		TimeFormat:     "15:04:05",
+		text:           text,
+		following:      true,
+	}, nil
+}
+
+// Close frees the text buffer. The view must not be written to afterwards.
+func (v *LogView) Close() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.text.Close()
+}
+
+// Write adds the complete lines of p to the log. A line without its line break yet is
+// kept until the rest of it is written. Always returns len(p), nil unless the text
+// buffer fails.
+func (v *LogView) Write(p []byte) (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	now := time.Now()
+	data := append(v.pending, p...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		if err := v.writeLine(strings.TrimSuffix(string(data[:i]), "\r"), now); err != nil {
+			v.pending = nil
+			return 0, err
+		}
+		data = data[i+1:]
+	}
+	v.pending = append([]byte(nil), data...)
+	return len(p), nil
+}
+
+// writeLine adds a line to the text buffer, colored by its level.
+func (v *LogView) writeLine(line string, now time.Time) error {
+	level := LevelNone
+	if v.Classifier != nil {
+		level = v.Classifier(line)
+	}
+	color := v.Style.color(level)
+	if _, err := v.text.WriteChunk(opentui.TextChunk{Text: line + "\n", Foreground: &color}); err != nil {
+		return err
+	}
+	v.entries = append(v.entries, logEntry{time: now, level: level})
+	if v.filter != nil && v.filter(line) {
+		v.matches = append(v.matches, len(v.entries)-1)
+	}
+	v.dirty = true
+
+	// Drop the entries of the lines the text buffer dropped
+	dropped, err := v.text.LinesDropped()
+	if err != nil || dropped == v.dropped {
+		return err
+	}
+	n := int(dropped - v.dropped)
+	v.dropped = dropped
+	rows := v.rows()
+	v.entries = v.entries[n:]
+	if v.filter != nil {
+		kept := v.matches[:0]
+		for _, e := range v.matches {
+			if e >= n {
+				kept = append(kept, e-n)
+			}
+		}
+		v.matches = kept
+	}
+	// Keep the same lines in view
+	v.offset = max(0, v.offset-(rows-v.rows()))
+	return nil
+}
+
+// Lines returns the number of lines kept, shown or not.
+func (v *LogView) Lines() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return len(v.entries)
+}
+
+// Clear removes all the lines.
+func (v *LogView) Clear() error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.text.Reset(); err != nil {
+		return err
+	}
+	v.entries, v.matches, v.pending = nil, nil, nil
+	v.dropped, v.offset, v.dirty = 0, 0, true
+	return nil
+}
+
+// SetFilter shows only the lines containing substring, or all of them if it's empty.
+func (v *LogView) SetFilter(substring string) error {
+	if substring == "" {
+		return v.setFilter(nil)
+	}
+	return v.setFilter(func(line string) bool { return strings.Contains(line, substring) })
+}
+
+// SetFilterRegexp shows only the lines matching re, or all of them if it's nil.
+func (v *LogView) SetFilterRegexp(re *regexp.Regexp) error {
+	if re == nil {
+		return v.setFilter(nil)
+	}
+	return v.setFilter(re.MatchString)
+}
+
+// setFilter filters the lines kept with match, going back to the end of the log.
+func (v *LogView) setFilter(match func(line string) bool) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.filter, v.matches = match, nil
+	v.offset, v.following = 0, true
+	if match == nil || len(v.entries) == 0 {
+		return nil
+	}
+	text, err := v.text.GetText()
+	if err != nil {
+		return err
+	}
+	for i, line := range strings.SplitN(text, "\n", len(v.entries)+1)[:len(v.entries)] {
+		if match(line) {
+			v.matches = append(v.matches, i)
+		}
+	}
+	return nil
+}
+
+// rows returns the number of lines shown, those matching the filter if any.
+func (v *LogView) rows() int {
+	if v.filter != nil {
+		return len(v.matches)
+	}
+	return len(v.entries)
+}
+
+// Following reports whether the view follows the end of the log.
+func (v *LogView) Following() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.following
+}
+
+// Offset returns the first line shown, counting the lines shown only.
+func (v *LogView) Offset() int {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.clampOffset()
+	return v.offset
+}
+
+// clampOffset scrolls to the end while following, and keeps the offset within the
+// lines shown.
+func (v *LogView) clampOffset() {
+	last := max(0, v.rows()-int(v.Rect.Height))
+	if v.following {
+		v.offset = last
+	}
+	v.offset = max(0, min(v.offset, last))
+}
+
+// ScrollBy scrolls by rows, down if positive. Scrolling up stops following the end
+// of the log.
+func (v *LogView) ScrollBy(rows int) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.clampOffset()
+	if rows < 0 {
+		v.following = false
+	}
+	v.offset += rows
+	v.clampOffset()
+}
+
+// Follow scrolls to the end of the log and follows it, or stops following it.
+func (v *LogView) Follow(follow bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.following = follow
+	v.clampOffset()
+}
+
+// HandleKey scrolls by a line with Up and Down, by the height of the view with PageUp
+// and PageDown, to the start with Home and to the end with End, which follows the
+// log again. Returns whether the key was one of these.
+func (v *LogView) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	page := max(int(v.Rect.Height), 1)
+	switch ev.Key {
+	case opentui.KeyUp:
+		v.ScrollBy(-1)
+	case opentui.KeyDown:
+		v.ScrollBy(1)
+	case opentui.KeyPageUp:
+		v.ScrollBy(-page)
+	case opentui.KeyPageDown:
+		v.ScrollBy(page)
+	case opentui.KeyHome:
+		v.ScrollBy(-v.Lines())
+	case opentui.KeyEnd:
+		v.Follow(true)
+	default:
+		return false
+	}
+	return true
+}
+
+// Register adds the view to the renderer's hit grid under its ID, so that
+// HandleMouse only scrolls for wheel events over the view that no area registered
+// later covers. The grid is rebuilt every frame, so register the view whenever it's
+// rendered.
+func (v *LogView) Register(renderer *opentui.Renderer) error {
+	if err := renderer.AddToHitGrid(v.Rect.X, v.Rect.Y, v.Rect.Width, v.Rect.Height, v.ID); err != nil {
+		return err
+	}
+	v.renderer = renderer
+	return nil
+}
+
+// HandleMouse scrolls three lines per step of the mouse wheel over the view. Returns
+// whether the event was over the view.
+func (v *LogView) HandleMouse(ev opentui.MouseEvent) bool {
+	if !hits(v.renderer, v.ID, v.Rect, ev.Position) {
+		return false
+	}
+	if ev.Pressed && ev.Button == opentui.MouseWheelUp {
+		v.ScrollBy(-wheelStep)
+	} else if ev.Pressed && ev.Button == opentui.MouseWheelDown {
+		v.ScrollBy(wheelStep)
+	}
+	return true
+}
+
+// Render draws the lines in view into buf, keeping the background, each after its
+// timestamp if ShowTimestamps is set. Lines longer than the view are cut off.
+func (v *LogView) Render(buf *opentui.Buffer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.dirty {
+		if err := v.text.FinalizeLineInfo(); err != nil {
+			return err
+		}
+		v.dirty = false
+	}
+	v.clampOffset()
+	clip := opentui.ClipRect{X: v.Rect.X, Y: v.Rect.Y, Width: v.Rect.Width, Height: v.Rect.Height}
+	for row := 0; row < int(v.Rect.Height) && v.offset+row < v.rows(); row++ {
+		entry := v.offset + row
+		if v.filter != nil {
+			entry = v.matches[entry]
+		}
+		x, y := v.Rect.X, v.Rect.Y+int32(row)
+		if v.ShowTimestamps {
+			stamp := v.entries[entry].time.Format(v.TimeFormat)
+			if x >= 0 && y >= 0 {
+				if err := buf.DrawText(stamp, uint32(x), uint32(y), v.Style.Timestamp, nil, 0); err != nil {
+					return err
+				}
+			}
+			x += int32(textWidth(stamp)) + 1
+		}
+		if err := buf.DrawTextBufferRegion(v.text, x, y, uint32(entry), 1, &clip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
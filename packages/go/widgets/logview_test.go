@@ -0,0 +1,159 @@
+package widgets
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestDefaultLogClassifier(t *testing.T) {
+	tests := []struct {
+		line string
+		want LogLevel
+	}{
+		{"2024/05/01 12:00:00 WARN: disk almost full", LevelWarn},
+		{"[ERROR] request failed", LevelError},
+		{"DEBUG cache hit", LevelDebug},
+		{"INFO: started", LevelInfo},
+		{"just a message with ERROR far into it", LevelNone},
+		{"information", LevelNone},
+		{"", LevelNone},
+	}
+	for _, tt := range tests {
+		if got := DefaultLogClassifier(tt.line); got != tt.want {
+			t.Errorf("DefaultLogClassifier(%q) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestLogViewWrite(t *testing.T) {
+	skipWithoutTextBuffers(t)
+	view, err := NewLogView(0, rect(0, 0, 20, 3), 0)
+	if err != nil {
+		t.Fatalf("NewLogView failed: %v", err)
+	}
+	defer view.Close()
+
+	logger := log.New(view, "", 0)
+	logger.Print("INFO: one")
+	fmt.Fprint(view, "WARN: tw")
+	if view.Lines() != 1 {
+		t.Errorf("Lines() = %d before the line break, want 1", view.Lines())
+	}
+	fmt.Fprint(view, "o\r\nthree\n")
+	if view.Lines() != 3 {
+		t.Errorf("Lines() = %d, want 3", view.Lines())
+	}
+	if levels := []LogLevel{view.entries[0].level, view.entries[1].level, view.entries[2].level}; levels[0] != LevelInfo || levels[1] != LevelWarn || levels[2] != LevelNone {
+		t.Errorf("levels = %v", levels)
+	}
+}
+
+func TestLogViewFollow(t *testing.T) {
+	skipWithoutTextBuffers(t)
+	view, err := NewLogView(0, rect(0, 0, 20, 3), 0)
+	if err != nil {
+		t.Fatalf("NewLogView failed: %v", err)
+	}
+	defer view.Close()
+
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(view, "line %d\n", i)
+	}
+	if !view.Following() || view.Offset() != 7 {
+		t.Errorf("following %v at %d, want the last 3 lines", view.Following(), view.Offset())
+	}
+
+	view.HandleKey(opentui.KeyEvent{Key: opentui.KeyUp})
+	fmt.Fprintln(view, "line 10")
+	if view.Following() || view.Offset() != 6 {
+		t.Errorf("after scrolling up: following %v at %d, want paused at 6", view.Following(), view.Offset())
+	}
+
+	view.HandleKey(opentui.KeyEvent{Key: opentui.KeyEnd})
+	if !view.Following() || view.Offset() != 8 {
+		t.Errorf("after End: following %v at %d, want 8", view.Following(), view.Offset())
+	}
+}
+
+func TestLogViewMaxLines(t *testing.T) {
+	skipWithoutTextBuffers(t)
+	view, err := NewLogView(0, rect(0, 0, 20, 3), 50)
+	if err != nil {
+		t.Fatalf("NewLogView failed: %v", err)
+	}
+	defer view.Close()
+
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(view, "line %d\n", i)
+	}
+	// The text buffer drops lines in batches of an eighth of the limit
+	if lines := view.Lines(); lines > 50+50/8 || lines < 40 {
+		t.Errorf("Lines() = %d, want about 50", lines)
+	}
+	if dropped, _ := view.text.LinesDropped(); int(dropped)+view.Lines() != 500 {
+		t.Errorf("%d lines dropped and %d kept, want 500 in all", dropped, view.Lines())
+	}
+}
+
+func TestLogViewRender(t *testing.T) {
+	skipWithoutTextBuffers(t)
+	buffer := opentui.NewBuffer(16, 3, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+	view, err := NewLogView(0, rect(0, 0, 16, 3), 0)
+	if err != nil {
+		t.Fatalf("NewLogView failed: %v", err)
+	}
+	defer view.Close()
+	view.ShowTimestamps = false
+
+	for _, line := range []string{"INFO: start", "ERROR: disk", "DEBUG: x", "ERROR: net", "done"} {
+		fmt.Fprintln(view, line)
+	}
+	render := func() []string {
+		buffer.Clear(opentui.Black)
+		if err := view.Render(buffer); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+		rows, _ := buffer.CaptureText()
+		return rows
+	}
+
+	rows := render()
+	for i, want := range []string{"DEBUG: x", "ERROR: net", "done"} {
+		if strings.TrimRight(rows[i], " ") != want {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+	if cell, _ := buffer.GetCell(0, 1); cell.Foreground != view.Style.Error {
+		t.Errorf("error line drawn in %v", cell.Foreground)
+	}
+
+	view.SetFilterRegexp(regexp.MustCompile(`^ERROR`))
+	rows = render()
+	for i, want := range []string{"ERROR: disk", "ERROR: net", ""} {
+		if strings.TrimRight(rows[i], " ") != want {
+			t.Errorf("filtered row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+	fmt.Fprintln(view, "ERROR: again")
+	fmt.Fprintln(view, "fine")
+	if rows = render(); strings.TrimRight(rows[2], " ") != "ERROR: again" {
+		t.Errorf("matching line written while filtered shown as %q", rows[2])
+	}
+
+	view.SetFilter("")
+	view.ShowTimestamps = true
+	view.TimeFormat = "15:04"
+	rows = render()
+	if !strings.HasSuffix(strings.TrimRight(rows[2], " "), " fine") || len(strings.TrimRight(rows[2], " ")) != 10 {
+		t.Errorf("timestamped row = %q", rows[2])
+	}
+}
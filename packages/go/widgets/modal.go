@@ -0,0 +1,272 @@
+package widgets
+
+import (
+	"github.com/sst/opentui/packages/go"
+)
+
+// defaultModalWidth is the widest a modal gets unless Modal.Width says otherwise
+const defaultModalWidth = 50
+
+// ModalStyle holds the colors of a modal dialog and of the content it dims.
+type ModalStyle struct {
+	Backdrop          opentui.RGBA // Blended over everything behind the dialog; its alpha sets how much it dims
+	Border            opentui.RGBA
+	Background        opentui.RGBA
+	Title             opentui.RGBA
+	Text              opentui.RGBA
+	Button            opentui.RGBA
+	ButtonBackground  opentui.RGBA
+	FocusedButton     opentui.RGBA
+	FocusedBackground opentui.RGBA
+}
+
+// DefaultModalStyle dims the screen by 60% under a dark blue dialog, with the focused
+// button in black on white.
+var DefaultModalStyle = ModalStyle{
+	Backdrop:          opentui.NewRGBA(0, 0, 0, 0.6),
+	Border:            opentui.NewRGB(0.5, 0.6, 0.9),
+	Background:        opentui.NewRGB(0.1, 0.12, 0.25),
+	Title:             opentui.White,
+	Text:              opentui.NewRGB(0.85, 0.85, 0.85),
+	Button:            opentui.White,
+	ButtonBackground:  opentui.NewRGB(0.25, 0.3, 0.5),
+	FocusedButton:     opentui.Black,
+	FocusedBackground: opentui.White,
+}
+
+// Modal is a dialog with a title, word wrapped body text and a row of buttons,
+// centered over dimmed content. While open it takes all input: Tab and Shift+Tab or
+// Left and Right move the focus between buttons, Enter or Space chooses the focused one,
+// Esc chooses Cancel, and a click chooses the button under the pointer. The choice
+// closes the modal and is delivered to OnResult and to the channel returned by Open.
+type Modal struct {
+	Title    string
+	Body     string
+	Buttons  []string
+	Cancel   int    // Index of the button Esc chooses, the last one by default; -1 for none
+	Width    uint32 // Widest the dialog gets, 50 by default; it never exceeds the buffer
+	Style    ModalStyle
+	OnResult func(index int) // Called with the index of the chosen button
+
+	open    bool
+	focused int
+	result  chan int
+	size    opentui.Size // size of the buffer last rendered into, for HandleMouse
+}
+
+// modalLayout is where the parts of a modal go in a buffer
+type modalLayout struct {
+	dialog  opentui.Rect
+	body    opentui.Rect
+	buttons []tabSpan // x counts from the left of the buffer
+	row     int32     // row of the buttons
+}
+
+// NewModal creates a closed modal with the given buttons, such as "OK" and "Cancel".
+// Esc chooses the last button.
+func NewModal(title, body string, buttons ...string) *Modal {
+	return &Modal{Title: title, Body: body, Buttons: buttons, Cancel: len(buttons) - 1, Width: defaultModalWidth, Style: DefaultModalStyle}
+}
+
+// Open shows the modal with the focus on the first button. The returned channel
+// receives the index of the button chosen, or -1 if the modal is closed by Esc
+// without a Cancel button.
+func (m *Modal) Open() <-chan int {
+	m.open, m.focused = true, 0
+	m.result = make(chan int, 1)
+	return m.result
+}
+
+// IsOpen reports whether the modal is shown.
+func (m *Modal) IsOpen() bool {
+	return m.open
+}
+
+// CapturesInput reports whether all key and mouse events should go to the modal, as
+// long as it is open. Event loops check it before passing events to anything else.
+func (m *Modal) CapturesInput() bool {
+	return m.open
+}
+
+// Focused returns the index of the focused button.
+func (m *Modal) Focused() int {
+	return m.focused
+}
+
+// Choose closes the modal with the button at index as the result, which -1 may stand
+// for no button. Nothing happens if the modal isn't open.
+func (m *Modal) Choose(index int) {
+	if !m.open {
+		return
+	}
+	m.open = false
+	m.result <- index
+	if m.OnResult != nil {
+		m.OnResult(index)
+	}
+}
+
+// HandleKey moves the focus or chooses a button. Returns whether the modal took the
+// key, which is always the case while it is open.
+func (m *Modal) HandleKey(ev opentui.KeyEvent) bool {
+	if !m.open {
+		return false
+	}
+	count := len(m.Buttons)
+	switch ev.Key {
+	case '\t':
+		if ev.Modifiers&opentui.ModShift != 0 {
+			m.focus(m.focused - 1)
+		} else {
+			m.focus(m.focused + 1)
+		}
+	case opentui.KeyLeft:
+		m.focus(m.focused - 1)
+	case opentui.KeyRight:
+		m.focus(m.focused + 1)
+	case opentui.KeyEnter, '\n', ' ':
+		if count > 0 {
+			m.Choose(m.focused)
+		}
+	case opentui.KeyEscape:
+		if m.Cancel < count {
+			m.Choose(m.Cancel)
+		}
+	}
+	return true
+}
+
+// focus moves the focus to the button at index, wrapping around the row.
+func (m *Modal) focus(index int) {
+	if count := len(m.Buttons); count > 0 {
+		m.focused = (index%count + count) % count
+	}
+}
+
+// HandleMouse focuses the button under the pointer and chooses it when clicked.
+// Returns whether the modal took the event, which is always the case while it is open.
+func (m *Modal) HandleMouse(ev opentui.MouseEvent) bool {
+	if !m.open {
+		return false
+	}
+	layout := m.layout(m.size.Width, m.size.Height)
+	if ev.Position.Y != layout.row || ev.Position.X < 0 {
+		return true
+	}
+	col, right := uint32(ev.Position.X), uint32(layout.body.X)+layout.body.Width
+	for _, span := range layout.buttons {
+		if col < span.x || col >= span.x+span.width || col >= right {
+			continue
+		}
+		m.focused = span.index
+		if ev.Button == opentui.MouseLeft && ev.Pressed && !ev.Motion {
+			m.Choose(span.index)
+		}
+	}
+	return true
+}
+
+// buttonLabel returns the text drawn for a button, its label padded with spaces.
+func buttonLabel(label string) string {
+	return " " + label + " "
+}
+
+// layout centers the dialog in a buffer of the given size, as wide as the longest
+// line of the body, the buttons or the title need, up to Width and the buffer width.
+func (m *Modal) layout(width, height uint32) modalLayout {
+	const gap = 2 // cells between buttons
+	buttonsWidth := uint32(0)
+	for i, label := range m.Buttons {
+		if i > 0 {
+			buttonsWidth += gap
+		}
+		buttonsWidth += textWidth(buttonLabel(label))
+	}
+	bodyWidth, _ := opentui.MeasureText(m.Body, opentui.WidthMethodUnicode)
+	// Border and a space of padding on both sides
+	dialogWidth := max(bodyWidth, buttonsWidth, textWidth(m.Title)+2) + 4
+	dialogWidth = min(dialogWidth, max(m.Width, 5), width)
+	inner := uint32(0)
+	if dialogWidth > 4 {
+		inner = dialogWidth - 4
+	}
+
+	lines := opentui.MeasureTextWrapped(m.Body, inner, opentui.WrapWord)
+	// Border, body, a blank line, buttons, border
+	dialogHeight := min(lines+4, height)
+	var layout modalLayout
+	layout.dialog = opentui.Rect{
+		Position: opentui.Position{X: int32((width - dialogWidth) / 2), Y: int32((height - dialogHeight) / 2)},
+		Size:     opentui.Size{Width: dialogWidth, Height: dialogHeight},
+	}
+	layout.body = opentui.Rect{
+		Position: opentui.Position{X: layout.dialog.X + 2, Y: layout.dialog.Y + 1},
+		Size:     opentui.Size{Width: inner, Height: uint32(max(int(dialogHeight)-4, 0))},
+	}
+	layout.row = layout.dialog.Y + int32(dialogHeight) - 2
+
+	// Buttons centered below the body, cut off on the right if they don't fit
+	col := uint32(layout.body.X) + (inner-min(buttonsWidth, inner))/2
+	for i, label := range m.Buttons {
+		layout.buttons = append(layout.buttons, tabSpan{i, col, textWidth(buttonLabel(label))})
+		col += textWidth(buttonLabel(label)) + gap
+	}
+	return layout
+}
+
+// Render dims everything already drawn in buf by blending Style.Backdrop over it, then
+// draws the dialog centered in buf. Nothing is drawn while the modal is closed. Draw
+// the content behind the modal before calling it on every frame: the dimming is
+// applied to that frame only, leaving the content itself untouched.
+func (m *Modal) Render(buf *opentui.Buffer) error {
+	if !m.open {
+		return nil
+	}
+	width, height, err := buf.Size()
+	if err != nil {
+		return err
+	}
+	m.size = opentui.Size{Width: width, Height: height}
+	if err := buf.FillRect(0, 0, width, height, m.Style.Backdrop); err != nil {
+		return err
+	}
+
+	layout := m.layout(width, height)
+	dialog := layout.dialog
+	options := opentui.BoxOptions{
+		Sides:          opentui.BorderSides{Top: true, Right: true, Bottom: true, Left: true},
+		Fill:           true,
+		Title:          m.Title,
+		TitleAlignment: opentui.AlignCenter,
+		TitleColor:     &m.Style.Title,
+		BorderChars:    opentui.DefaultBoxChars,
+	}
+	if err := buf.DrawBox(dialog.X, dialog.Y, dialog.Width, dialog.Height, options, m.Style.Border, m.Style.Background); err != nil {
+		return err
+	}
+	if layout.body.Width == 0 || layout.row <= layout.dialog.Y {
+		return nil
+	}
+	if _, err := buf.DrawTextWrapped(m.Body, layout.body, m.Style.Text, &m.Style.Background, 0, opentui.WrapWord); err != nil {
+		return err
+	}
+
+	right := uint32(layout.body.X) + layout.body.Width
+	for _, span := range layout.buttons {
+		if span.x >= right {
+			break
+		}
+		fg, bg := m.Style.Button, m.Style.ButtonBackground
+		if span.index == m.focused {
+			fg, bg = m.Style.FocusedButton, m.Style.FocusedBackground
+		}
+		label := buttonLabel(m.Buttons[span.index])
+		if span.x+span.width > right {
+			label = opentui.TruncateToWidth(label, right-span.x, "…")
+		}
+		if err := buf.DrawText(label, span.x, uint32(layout.row), fg, &bg, 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
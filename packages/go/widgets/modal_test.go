@@ -0,0 +1,99 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestModalKeys(t *testing.T) {
+	modal := NewModal("Delete files", "Delete 3 files?", "OK", "Cancel")
+	if modal.HandleKey(opentui.KeyEvent{Key: '\t'}) || modal.CapturesInput() {
+		t.Fatal("a closed modal should not take input")
+	}
+	var chosen []int
+	modal.OnResult = func(index int) { chosen = append(chosen, index) }
+
+	result := modal.Open()
+	for _, tc := range []struct {
+		ev      opentui.KeyEvent
+		focused int
+	}{
+		{opentui.KeyEvent{Key: '\t'}, 1},
+		{opentui.KeyEvent{Key: '\t'}, 0},
+		{opentui.KeyEvent{Key: '\t', Modifiers: opentui.ModShift}, 1},
+		{opentui.KeyEvent{Key: 'x'}, 1},
+	} {
+		if !modal.HandleKey(tc.ev) || modal.Focused() != tc.focused {
+			t.Errorf("after %+v: focus on %d, want %d", tc.ev, modal.Focused(), tc.focused)
+		}
+	}
+	modal.HandleKey(opentui.KeyEvent{Key: opentui.KeyEnter})
+	if got := <-result; got != 1 || modal.IsOpen() {
+		t.Errorf("Enter chose %d, open %v", got, modal.IsOpen())
+	}
+
+	result = modal.Open()
+	modal.HandleKey(opentui.KeyEvent{Key: opentui.KeyEscape})
+	if got := <-result; got != 1 || len(chosen) != 2 || chosen[1] != 1 {
+		t.Errorf("Esc chose %d, OnResult got %v", got, chosen)
+	}
+}
+
+func TestModalRender(t *testing.T) {
+	content := opentui.NewBuffer(40, 12, false, opentui.WidthMethodUnicode)
+	if content == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer content.Close()
+	content.Clear(opentui.White)
+	content.DrawText("behind", 0, 0, opentui.Black, nil, 0)
+
+	frame := opentui.NewBuffer(40, 12, false, opentui.WidthMethodUnicode)
+	defer frame.Close()
+	frame.SetBlendMode(opentui.BlendLinear)
+
+	modal := NewModal("Delete files", "Delete 3 files? This cannot be undone.", "OK", "Cancel")
+	modal.Width = 30
+	result := modal.Open()
+	render := func() {
+		frame.DrawFrameBuffer(0, 0, content, 0, 0, 40, 12)
+		if err := modal.Render(frame); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+	}
+	render()
+	render()
+
+	// The backdrop dims what is behind the dialog the same on every frame, and only
+	// in the frame
+	cell, _ := frame.GetCell(0, 0)
+	want := opentui.Mix(opentui.White, opentui.Black, 0.6)
+	if cell.Char != 'b' || cell.Background != want {
+		t.Errorf("dimmed cell %q on %v, want 'b' on %v", cell.Char, cell.Background, want)
+	}
+	if cell, _ := content.GetCell(0, 0); cell.Background != opentui.White {
+		t.Errorf("content changed to %v", cell.Background)
+	}
+
+	// A 30 cell dialog centered in 40x12, the body wrapped in 26 cells, and the
+	// buttons centered two rows above the bottom
+	rows, _ := frame.CaptureText()
+	for y, want := range map[int]string{4: "Delete 3 files? This", 5: "cannot be undone.", 7: "       OK    Cancel "} {
+		if !strings.HasPrefix(string([]rune(rows[y])[7:]), want) {
+			t.Errorf("row %d = %q, want %q", y, rows[y], want)
+		}
+	}
+	if cell, _ := frame.GetCell(14, 7); cell.Background != modal.Style.FocusedBackground {
+		t.Errorf("focused button on %v", cell.Background)
+	}
+
+	if !modal.HandleMouse(mouseAt(0, 0, opentui.MouseLeft, true, false)) || !modal.IsOpen() {
+		t.Error("clicks outside the buttons should be taken without closing the modal")
+	}
+	modal.HandleMouse(mouseAt(20, 7, opentui.MouseLeft, true, false))
+	if got := <-result; got != 1 {
+		t.Errorf("clicking Cancel chose %d", got)
+	}
+}
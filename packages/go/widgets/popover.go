@@ -0,0 +1,72 @@
+package widgets
+
+import (
+	"github.com/sst/opentui/packages/go"
+)
+
+// Popover shows one tooltip at a time for the widgets of a screen, e.g. the full text
+// of a truncated table cell while the pointer is over it. It hides the tooltip when
+// the pointer leaves the anchor or a key is pressed, so pass it the input events
+// before the widgets, or call Show and Hide from the OnEnter and OnLeave callbacks of
+// a Dispatcher region.
+type Popover struct {
+	Options opentui.TooltipOptions
+
+	renderer *opentui.Renderer
+	tooltip  *opentui.Tooltip
+	content  string
+}
+
+// NewPopover creates a popover showing tooltips on renderer.
+func NewPopover(renderer *opentui.Renderer) *Popover {
+	return &Popover{Options: opentui.DefaultTooltipOptions, renderer: renderer}
+}
+
+// Show shows content next to anchor, replacing the tooltip shown so far. Showing the
+// same content at the same anchor again keeps the tooltip.
+func (p *Popover) Show(anchor opentui.Rect, content string) error {
+	if p.tooltip != nil && p.tooltip.Anchor == anchor && p.content == content {
+		return nil
+	}
+	p.Hide()
+	tooltip, err := p.renderer.ShowTooltip(anchor, content, p.Options)
+	if err != nil {
+		return err
+	}
+	p.tooltip, p.content = tooltip, content
+	return nil
+}
+
+// Hide closes the tooltip, if one is shown.
+func (p *Popover) Hide() {
+	if p.tooltip != nil {
+		p.tooltip.Close()
+		p.tooltip, p.content = nil, ""
+	}
+}
+
+// Visible reports whether a tooltip is shown.
+func (p *Popover) Visible() bool {
+	return p.tooltip != nil
+}
+
+// Tooltip returns the tooltip shown, or nil.
+func (p *Popover) Tooltip() *opentui.Tooltip {
+	return p.tooltip
+}
+
+// HandleMouse hides the tooltip when the pointer is off its anchor. It never consumes
+// the event, so it returns false.
+func (p *Popover) HandleMouse(ev opentui.MouseEvent) bool {
+	if p.tooltip != nil && !p.tooltip.Anchor.Contains(ev.Position.X, ev.Position.Y) {
+		p.Hide()
+	}
+	return false
+}
+
+// HandleKey hides the tooltip on any key. It never consumes the key, so it returns
+// false.
+func (p *Popover) HandleKey(ev opentui.KeyEvent) bool {
+	p.Hide()
+	return false
+}
@@ -0,0 +1,37 @@
+package widgets
+
+import (
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestPopoverDismiss(t *testing.T) {
+	renderer := opentui.NewRenderer(30, 8)
+	if renderer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer renderer.Close()
+
+	p := NewPopover(renderer)
+	anchor := rect(2, 2, 6, 1)
+	if err := p.Show(anchor, "full value"); err != nil {
+		t.Fatalf("Show failed: %v", err)
+	}
+	tooltip := p.Tooltip()
+	p.Show(anchor, "full value")
+	if p.Tooltip() != tooltip {
+		t.Error("showing the same tooltip again should keep it")
+	}
+	if p.HandleMouse(mouseAt(4, 2, opentui.MouseNone, false, true)) || !p.Visible() {
+		t.Error("moving over the anchor should keep the tooltip")
+	}
+	p.HandleMouse(mouseAt(9, 2, opentui.MouseNone, false, true))
+	if p.Visible() {
+		t.Error("leaving the anchor should hide the tooltip")
+	}
+	p.Show(anchor, "full value")
+	if p.HandleKey(opentui.KeyEvent{Key: 'a'}) || p.Visible() {
+		t.Error("a key should hide the tooltip without being consumed")
+	}
+}
@@ -0,0 +1,214 @@
+package widgets
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// defaultSweep is the time the segment of an indeterminate bar takes to cross it once
+const defaultSweep = 1500 * time.Millisecond
+
+// ProgressState tells whether the task a progress bar follows is still running.
+type ProgressState uint8
+
+const (
+	ProgressRunning   ProgressState = iota // Drawn in Filled, moving with SetFraction or Tick
+	ProgressSucceeded                      // Full bar drawn in Success
+	ProgressFailed                         // Bar drawn in Error where it stopped
+)
+
+// ProgressStyle holds the characters and colors of a progress bar.
+type ProgressStyle struct {
+	Bar     opentui.ProgressBarStyle
+	Filled  opentui.RGBA // Done part while running, and the moving segment of an indeterminate bar
+	Empty   opentui.RGBA // Remaining part
+	Label   opentui.RGBA // Replaced by black or white where it is not readable on a ProgressBlocks bar
+	Success opentui.RGBA // Filled part once succeeded
+	Error   opentui.RGBA // Filled part once failed
+}
+
+// DefaultProgressStyle draws blue blocks on dark gray, turning green on success and red
+// on failure.
+var DefaultProgressStyle = ProgressStyle{
+	Bar:     opentui.ProgressBlocks,
+	Filled:  opentui.Blue,
+	Empty:   opentui.NewRGB(0.2, 0.2, 0.2),
+	Label:   opentui.White,
+	Success: opentui.Green,
+	Error:   opentui.Red,
+}
+
+// Progress is a one row progress bar following a task. A determinate bar shows the
+// fraction done given to SetFraction. An indeterminate bar, for tasks of unknown
+// length, bounces a segment from end to end as Tick is called from the frame loop.
+// Succeed and Fail end the task, changing the color of the bar.
+type Progress struct {
+	Rect        opentui.Rect // The bar is drawn on the first row, see PreferredHeight
+	Style       ProgressStyle
+	LabelFormat string        // fmt format applied to the percentage, "%3.0f%%" by default. Empty draws no label
+	Sweep       time.Duration // Time the indeterminate segment takes to cross the bar once
+
+	fraction      float64
+	label         string // replaces LabelFormat when set
+	indeterminate bool
+	phase         float64 // indeterminate segment position, 0 to 1 going right and 1 to 2 going back
+	state         ProgressState
+}
+
+// NewProgress creates a running determinate progress bar covering rect, at 0%.
+func NewProgress(rect opentui.Rect, style ProgressStyle) *Progress {
+	return &Progress{Rect: rect, Style: style, LabelFormat: "%3.0f%%", Sweep: defaultSweep}
+}
+
+// PreferredHeight returns the number of rows the bar needs, always 1.
+func (p *Progress) PreferredHeight() uint32 {
+	return 1
+}
+
+// Fraction returns the fraction done, from 0 to 1.
+func (p *Progress) Fraction() float64 {
+	return p.fraction
+}
+
+// SetFraction sets the fraction done, clamped to [0, 1], making the bar determinate.
+func (p *Progress) SetFraction(fraction float64) {
+	if math.IsNaN(fraction) {
+		fraction = 0
+	}
+	p.fraction = math.Min(math.Max(fraction, 0), 1)
+	p.indeterminate = false
+}
+
+// Indeterminate reports whether the bar shows a bouncing segment instead of a fraction.
+func (p *Progress) Indeterminate() bool {
+	return p.indeterminate
+}
+
+// SetIndeterminate makes the bar indeterminate, starting the segment from the left.
+// SetFraction makes it determinate again.
+func (p *Progress) SetIndeterminate() {
+	p.indeterminate = true
+	p.phase = 0
+}
+
+// SetLabel sets a text such as "12/80 files" drawn as the label instead of the
+// percentage formatted with LabelFormat. An empty text goes back to LabelFormat.
+// Indeterminate bars only draw labels set this way.
+func (p *Progress) SetLabel(text string) {
+	p.label = text
+}
+
+// Label returns the text drawn as the label, empty if none.
+func (p *Progress) Label() string {
+	if p.label != "" || p.LabelFormat == "" || (p.indeterminate && p.state == ProgressRunning) {
+		return p.label
+	}
+	return fmt.Sprintf(p.LabelFormat, p.fraction*100)
+}
+
+// Tick moves the segment of a running indeterminate bar by the time dt elapsed since
+// the previous frame, such as the one RunLoop passes to each frame.
+func (p *Progress) Tick(dt time.Duration) {
+	if !p.indeterminate || p.state != ProgressRunning || p.Sweep <= 0 {
+		return
+	}
+	p.phase = math.Mod(p.phase+dt.Seconds()/p.Sweep.Seconds(), 2)
+}
+
+// State returns whether the task is running, succeeded or failed.
+func (p *Progress) State() ProgressState {
+	return p.state
+}
+
+// Succeed ends the task successfully, filling the bar in the Success color.
+func (p *Progress) Succeed() {
+	p.state = ProgressSucceeded
+	p.fraction, p.indeterminate = 1, false
+}
+
+// Fail ends the task in failure. The bar stays where it stopped, or fills if it was
+// indeterminate, in the Error color.
+func (p *Progress) Fail() {
+	p.state = ProgressFailed
+	if p.indeterminate {
+		p.fraction, p.indeterminate = 1, false
+	}
+}
+
+// Reset starts the bar over: running, determinate and at 0%.
+func (p *Progress) Reset() {
+	p.state = ProgressRunning
+	p.fraction, p.indeterminate, p.phase = 0, false, 0
+}
+
+// segment returns the width of the moving segment of an indeterminate bar width cells
+// wide, a quarter of it but at least two cells, and its offset from the left end.
+func (p *Progress) segment(width uint32) (length, offset uint32) {
+	length = max(width/4, min(width, 2))
+	position := p.phase
+	if position > 1 {
+		position = 2 - position
+	}
+	return length, uint32(math.Round(position * float64(width-length)))
+}
+
+// Render draws the bar on the first row of its rect in buf. A label wider than the
+// bar is cut with an ellipsis.
+func (p *Progress) Render(buf *opentui.Buffer) error {
+	if p.Rect.X < 0 || p.Rect.Y < 0 || p.Rect.Width == 0 || p.Rect.Height == 0 {
+		return nil
+	}
+	x, y, width := uint32(p.Rect.X), uint32(p.Rect.Y), p.Rect.Width
+	opts := opentui.ProgressBarOptions{
+		Style:       p.Style.Bar,
+		FilledColor: p.Style.Filled,
+		EmptyColor:  p.Style.Empty,
+		LabelColor:  p.Style.Label,
+	}
+	switch p.state {
+	case ProgressSucceeded:
+		opts.FilledColor = p.Style.Success
+	case ProgressFailed:
+		opts.FilledColor = p.Style.Error
+	}
+	label := opentui.TruncateToWidth(p.Label(), width, "…")
+
+	if !p.indeterminate || p.state != ProgressRunning {
+		opts.Label = label
+		return buf.DrawProgressBar(x, y, width, p.fraction, opts)
+	}
+
+	length, offset := p.segment(width)
+	if err := buf.DrawProgressBar(x, y, width, 0, opts); err != nil {
+		return err
+	}
+	if err := buf.DrawProgressBar(x+offset, y, length, 1, opts); err != nil {
+		return err
+	}
+	return p.drawLabel(buf, label, x, y, width, offset, length, opts)
+}
+
+// drawLabel centers label on an indeterminate bar, taking the color of the bar below
+// each character like the labels of DrawProgressBar.
+func (p *Progress) drawLabel(buf *opentui.Buffer, label string, x, y, width, offset, length uint32, opts opentui.ProgressBarOptions) error {
+	col := x + (width-textWidth(label))/2
+	for g := opentui.Graphemes(label); g.Next(); {
+		fg := opts.LabelColor
+		var bg *opentui.RGBA
+		if opts.Style == opentui.ProgressBlocks {
+			bg = &opts.EmptyColor
+			if col-x >= offset && col-x < offset+length {
+				bg = &opts.FilledColor
+			}
+			fg = opentui.ReadableForegroundFrom(*bg, fg, opentui.Black, opentui.White)
+		}
+		if err := buf.DrawText(g.Str(), col, y, fg, bg, 0); err != nil {
+			return err
+		}
+		col += uint32(g.Width())
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package widgets
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestProgressStates(t *testing.T) {
+	bar := NewProgress(opentui.Rect{Size: opentui.Size{Width: 10, Height: 1}}, DefaultProgressStyle)
+	for _, tc := range []struct{ set, want float64 }{{0.25, 0.25}, {-1, 0}, {2, 1}, {math.NaN(), 0}} {
+		if bar.SetFraction(tc.set); bar.Fraction() != tc.want {
+			t.Errorf("SetFraction(%v) gave %v, want %v", tc.set, bar.Fraction(), tc.want)
+		}
+	}
+	bar.SetFraction(0.5)
+	if bar.Label() != " 50%" || bar.PreferredHeight() != 1 {
+		t.Errorf("label %q, preferred height %d", bar.Label(), bar.PreferredHeight())
+	}
+	bar.SetLabel("40/80 files")
+	if bar.Label() != "40/80 files" {
+		t.Errorf("label %q, want the text set", bar.Label())
+	}
+
+	bar.SetIndeterminate()
+	bar.Fail()
+	if bar.State() != ProgressFailed || bar.Indeterminate() || bar.Fraction() != 1 {
+		t.Errorf("failed indeterminate bar: state %d, indeterminate %v, fraction %v", bar.State(), bar.Indeterminate(), bar.Fraction())
+	}
+	bar.Reset()
+	bar.SetFraction(0.3)
+	bar.Fail()
+	if bar.Fraction() != 0.3 {
+		t.Errorf("a failed bar should stay at %v, got %v", 0.3, bar.Fraction())
+	}
+	bar.Succeed()
+	if bar.State() != ProgressSucceeded || bar.Fraction() != 1 {
+		t.Errorf("succeeded bar: state %d, fraction %v", bar.State(), bar.Fraction())
+	}
+}
+
+func TestProgressTick(t *testing.T) {
+	bar := NewProgress(opentui.Rect{Size: opentui.Size{Width: 10, Height: 1}}, DefaultProgressStyle)
+	bar.Sweep = time.Second
+	bar.Tick(time.Second)
+	if _, offset := bar.segment(10); offset != 0 {
+		t.Errorf("a determinate bar should ignore Tick, segment at %d", offset)
+	}
+
+	bar.SetIndeterminate()
+	// The segment is 2 cells long and goes right, then back left
+	for _, tc := range []struct {
+		dt     time.Duration
+		offset uint32
+	}{
+		{0, 0},
+		{500 * time.Millisecond, 4},
+		{500 * time.Millisecond, 8},
+		{250 * time.Millisecond, 6},
+		{750 * time.Millisecond, 0},
+		{1750 * time.Millisecond, 2},
+	} {
+		bar.Tick(tc.dt)
+		if length, offset := bar.segment(10); length != 2 || offset != tc.offset {
+			t.Errorf("after %v: segment of %d at %d, want 2 at %d", tc.dt, length, offset, tc.offset)
+		}
+	}
+	if length, offset := bar.segment(1); length != 1 || offset != 0 {
+		t.Errorf("one cell bar: segment of %d at %d", length, offset)
+	}
+}
+
+func TestProgressRender(t *testing.T) {
+	buffer := opentui.NewBuffer(7, 3, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+
+	style := DefaultProgressStyle
+	style.Bar = opentui.ProgressASCII
+	at := func(y int32) opentui.Rect {
+		return opentui.Rect{Position: opentui.Position{X: 1, Y: y}, Size: opentui.Size{Width: 5, Height: 1}}
+	}
+	determinate := NewProgress(at(0), style)
+	determinate.LabelFormat = ""
+	determinate.SetFraction(0.6)
+	labeled := NewProgress(at(1), style)
+	labeled.SetLabel("12/80 files")
+	indeterminate := NewProgress(at(2), style)
+	indeterminate.SetIndeterminate()
+	indeterminate.Sweep = time.Second
+	indeterminate.Tick(time.Second)
+	for _, bar := range []*Progress{determinate, labeled, indeterminate} {
+		if err := bar.Render(buffer); err != nil {
+			t.Fatalf("Render failed: %v", err)
+		}
+	}
+
+	rows, _ := buffer.CaptureText()
+	for i, want := range []string{" ###-- ", " 12/8… ", " ---## "} {
+		if rows[i] != want {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+
+	determinate.Succeed()
+	determinate.Render(buffer)
+	if cell, _ := buffer.GetCell(5, 0); cell.Char != '#' || cell.Foreground != style.Success {
+		t.Errorf("succeeded bar cell %q in %v, want '#' in %v", cell.Char, cell.Foreground, style.Success)
+	}
+}
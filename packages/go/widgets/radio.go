@@ -0,0 +1,159 @@
+package widgets
+
+import (
+	"github.com/sst/opentui/packages/go"
+)
+
+// radioGap is the number of blank cells between the options of a horizontal group
+const radioGap = 2
+
+// RadioGroup is a set of options of which exactly one is selected, laid out one per
+// row or side by side. The arrow keys move the selection within the group and a click
+// selects an option.
+type RadioGroup struct {
+	ID         uint32 // Hit grid ID, see Register
+	Position   opentui.Position
+	Horizontal bool // Options side by side instead of one per row
+	Disabled   bool
+	Focused    bool // Set while the group has the keyboard focus, to highlight it; see FocusManager
+	Glyphs     Glyphs
+	Style      ToggleStyle
+	OnChange   func(index int) // Called when another option gets selected
+
+	options  []string
+	selected int
+	renderer *opentui.Renderer
+}
+
+// NewRadioGroup creates a vertical group at pos with the first of options selected.
+func NewRadioGroup(id uint32, pos opentui.Position, options ...string) *RadioGroup {
+	return &RadioGroup{ID: id, Position: pos, Glyphs: RadioGlyphs, Style: DefaultToggleStyle, options: options}
+}
+
+// Options returns the labels of the options.
+func (g *RadioGroup) Options() []string {
+	return g.options
+}
+
+// Selected returns the index of the selected option, or -1 without options.
+func (g *RadioGroup) Selected() int {
+	if len(g.options) == 0 {
+		return -1
+	}
+	return g.selected
+}
+
+// Select selects the option at index, clamped to the options, calling OnChange if
+// another one was selected. It works on disabled groups too.
+func (g *RadioGroup) Select(index int) {
+	if len(g.options) == 0 {
+		return
+	}
+	index = max(0, min(index, len(g.options)-1))
+	changed := index != g.selected
+	g.selected = index
+	if changed && g.OnChange != nil {
+		g.OnChange(index)
+	}
+}
+
+// CanFocus reports whether the group can take the keyboard focus, that is whether it's
+// enabled and has options.
+func (g *RadioGroup) CanFocus() bool {
+	return !g.Disabled && len(g.options) > 0
+}
+
+// SetFocused sets Focused, for FocusManager.
+func (g *RadioGroup) SetFocused(focused bool) {
+	g.Focused = focused
+}
+
+// optionRect returns the cells covered by the glyph and label of the option at index.
+func (g *RadioGroup) optionRect(index int) opentui.Rect {
+	rect := opentui.Rect{Position: g.Position, Size: opentui.Size{Width: g.optionWidth(index), Height: 1}}
+	if !g.Horizontal {
+		rect.Y += int32(index)
+		return rect
+	}
+	for i := 0; i < index; i++ {
+		rect.X += int32(g.optionWidth(i) + radioGap)
+	}
+	return rect
+}
+
+func (g *RadioGroup) optionWidth(index int) uint32 {
+	return g.Glyphs.width() + 1 + textWidth(g.options[index])
+}
+
+// Rect returns the cells covered by all the options.
+func (g *RadioGroup) Rect() opentui.Rect {
+	rect := opentui.Rect{Position: g.Position}
+	for i := range g.options {
+		option := g.optionRect(i)
+		rect.Width = max(rect.Width, uint32(option.X-g.Position.X)+option.Width)
+		rect.Height = max(rect.Height, uint32(option.Y-g.Position.Y)+1)
+	}
+	return rect
+}
+
+// HandleKey selects the previous option with Up or Left and the next one with Down or
+// Right, wrapping around the group. Returns whether the key was one of these.
+func (g *RadioGroup) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	step := 0
+	switch ev.Key {
+	case opentui.KeyUp, opentui.KeyLeft:
+		step = -1
+	case opentui.KeyDown, opentui.KeyRight:
+		step = 1
+	default:
+		return false
+	}
+	if count := len(g.options); count > 0 && !g.Disabled {
+		g.Select(((g.selected+step)%count + count) % count)
+	}
+	return true
+}
+
+// Register adds the group to the renderer's hit grid under its ID, so that
+// HandleMouse ignores clicks on options that an area registered later covers. The grid
+// is rebuilt every frame, so register the group whenever it's rendered.
+func (g *RadioGroup) Register(renderer *opentui.Renderer) error {
+	for i := range g.options {
+		rect := g.optionRect(i)
+		if err := renderer.AddToHitGrid(rect.X, rect.Y, rect.Width, rect.Height, g.ID); err != nil {
+			return err
+		}
+	}
+	g.renderer = renderer
+	return nil
+}
+
+// HandleMouse selects the option whose glyph or label is clicked with the left
+// button. Returns whether the event was over an option.
+func (g *RadioGroup) HandleMouse(ev opentui.MouseEvent) bool {
+	for i := range g.options {
+		if !hits(g.renderer, g.ID, g.optionRect(i), ev.Position) {
+			continue
+		}
+		if ev.Button == opentui.MouseLeft && ev.Pressed && !ev.Motion && !g.Disabled {
+			g.Select(i)
+		}
+		return true
+	}
+	return false
+}
+
+// Render draws the options into buf, keeping the background. The selected option of
+// a focused group is highlighted.
+func (g *RadioGroup) Render(buf *opentui.Buffer) error {
+	for i, label := range g.options {
+		selected := i == g.selected
+		if err := drawToggle(buf, g.optionRect(i).Position, g.Glyphs, selected, label, g.Style, g.Focused && selected, g.Disabled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+package widgets
+
+import (
+	"unicode/utf8"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// Paragraph is a block of styled text of a RichText, aligned on its own.
+type Paragraph struct {
+	Chunks []opentui.TextChunk
+	Align  opentui.TextAlignment
+}
+
+// MarkupParagraph returns a paragraph of the text in markup, see opentui.ParseMarkup.
+func MarkupParagraph(markup string, align opentui.TextAlignment) (Paragraph, error) {
+	chunks, err := opentui.ParseMarkup(markup)
+	if err != nil {
+		return Paragraph{}, err
+	}
+	return Paragraph{Chunks: chunks, Align: align}, nil
+}
+
+// richParagraph is a paragraph with its text buffer, wrapped to the width of the text
+type richParagraph struct {
+	Paragraph
+	text  *opentui.TextBuffer // nil until first laid out, or after a change
+	lines []opentui.LineInfo  // wrapped lines, nil when stale
+	width uint32              // width the lines were wrapped to
+	mode  opentui.WrapMode
+}
+
+// rows returns the number of rows the paragraph takes. An empty paragraph is a blank
+// row.
+func (p *richParagraph) rows() int {
+	return max(len(p.lines), 1)
+}
+
+// RichText is a read-only block of styled text, such as a help screen or a chat
+// message, made of paragraphs that are wrapped to the width of its rect and aligned
+// each on its own. Text taller than the rect scrolls with the mouse wheel and the
+// arrow and paging keys. Every paragraph is kept in a TextBuffer, so changing one
+// rewraps only that one, and a new width only reflows the lines.
+type RichText struct {
+	ID        uint32       // Hit grid ID, see Register
+	Rect      opentui.Rect // Cells covered, scrollbar included
+	Wrap      opentui.WrapMode
+	Spacing   uint32 // Blank rows between paragraphs
+	Scrollbar bool   // Takes the last column while the text is taller than the rect
+	Style     ViewportStyle
+
+	paragraphs []*richParagraph
+	offset     int // first row shown
+	renderer   *opentui.Renderer
+}
+
+// NewRichText creates a word wrapped text covering rect, showing paragraphs.
+func NewRichText(id uint32, rect opentui.Rect, paragraphs ...Paragraph) *RichText {
+	t := &RichText{ID: id, Rect: rect, Wrap: opentui.WrapWord, Style: DefaultViewportStyle}
+	t.SetParagraphs(paragraphs...)
+	return t
+}
+
+// SetParagraphs replaces all the paragraphs and scrolls back to the top.
+func (t *RichText) SetParagraphs(paragraphs ...Paragraph) {
+	t.Close()
+	t.paragraphs = make([]*richParagraph, len(paragraphs))
+	for i, p := range paragraphs {
+		t.paragraphs[i] = &richParagraph{Paragraph: p}
+	}
+	t.offset = 0
+}
+
+// SetMarkup replaces all the paragraphs with a left aligned paragraph of the text in
+// markup, see opentui.ParseMarkup.
+func (t *RichText) SetMarkup(markup string) error {
+	p, err := MarkupParagraph(markup, opentui.AlignLeft)
+	if err != nil {
+		return err
+	}
+	t.SetParagraphs(p)
+	return nil
+}
+
+// Paragraphs returns the number of paragraphs.
+func (t *RichText) Paragraphs() int {
+	return len(t.paragraphs)
+}
+
+// Paragraph returns the paragraph at index.
+func (t *RichText) Paragraph(index int) Paragraph {
+	return t.paragraphs[index].Paragraph
+}
+
+// SetParagraph replaces the paragraph at index, leaving the others as they are
+// wrapped. Panics if index is out of range, like indexing a slice.
+func (t *RichText) SetParagraph(index int, p Paragraph) {
+	old := t.paragraphs[index]
+	if old.text != nil {
+		old.text.Close()
+	}
+	t.paragraphs[index] = &richParagraph{Paragraph: p}
+}
+
+// AppendParagraph adds p after the last paragraph.
+func (t *RichText) AppendParagraph(p Paragraph) {
+	t.paragraphs = append(t.paragraphs, &richParagraph{Paragraph: p})
+}
+
+// Close frees the text buffers of the paragraphs. The text is laid out again on the
+// next use.
+func (t *RichText) Close() error {
+	for _, p := range t.paragraphs {
+		if p.text != nil {
+			p.text.Close()
+			p.text, p.lines = nil, nil
+		}
+	}
+	return nil
+}
+
+// wrap wraps every paragraph to width cells, and returns the total number of rows.
+func (t *RichText) wrap(width uint32) (int, error) {
+	total := 0
+	for i, p := range t.paragraphs {
+		if p.lines == nil || p.width != width || p.mode != t.Wrap {
+			if err := p.layout(width, t.Wrap); err != nil {
+				return 0, err
+			}
+		}
+		if i > 0 {
+			total += int(t.Spacing)
+		}
+		total += p.rows()
+	}
+	return total, nil
+}
+
+// layout fills the text buffer of the paragraph if needed and wraps it.
+func (p *richParagraph) layout(width uint32, mode opentui.WrapMode) error {
+	if p.text == nil {
+		length := uint32(1)
+		for _, chunk := range p.Chunks {
+			length += uint32(utf8.RuneCountInString(chunk.Text))
+		}
+		text := opentui.NewTextBuffer(length, opentui.WidthMethodUnicode)
+		if text == nil {
+			return opentui.ErrAllocation
+		}
+		if _, err := text.WriteChunks(p.Chunks); err != nil {
+			text.Close()
+			return err
+		}
+		p.text = text
+	}
+	if err := p.text.WrapToWidth(max(width, 1), mode); err != nil {
+		return err
+	}
+	lines, err := p.text.GetLineInfo()
+	if err != nil {
+		return err
+	}
+	p.lines, p.width, p.mode = lines, width, mode
+	return nil
+}
+
+// PreferredHeight returns the number of rows the text takes at width cells, for
+// laying it out without scrolling.
+func (t *RichText) PreferredHeight(width uint32) (uint32, error) {
+	rows, err := t.wrap(width)
+	return uint32(rows), err
+}
+
+// view returns the width the text is wrapped to in the rect and its number of rows.
+// The scrollbar takes a column when the text doesn't fit at the full width.
+func (t *RichText) view() (width uint32, rows int, err error) {
+	width = t.Rect.Width
+	rows, err = t.wrap(width)
+	if err == nil && t.Scrollbar && rows > int(t.Rect.Height) && width > 1 {
+		width--
+		rows, err = t.wrap(width)
+	}
+	return width, rows, err
+}
+
+// Offset returns the first row of text shown.
+func (t *RichText) Offset() int {
+	return t.offset
+}
+
+// ScrollTo scrolls so that row is the first row shown, as close as the end of the
+// text allows.
+func (t *RichText) ScrollTo(row int) error {
+	_, rows, err := t.view()
+	if err != nil {
+		return err
+	}
+	t.offset = max(0, min(row, rows-int(t.Rect.Height)))
+	return nil
+}
+
+// ScrollBy scrolls by rows, down if positive.
+func (t *RichText) ScrollBy(rows int) error {
+	return t.ScrollTo(t.offset + rows)
+}
+
+// HandleKey scrolls by a row with Up and Down, by the height of the rect with PageUp
+// and PageDown, and to the ends with Home and End. Returns whether the key was one of
+// these.
+func (t *RichText) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	page := max(int(t.Rect.Height), 1)
+	switch ev.Key {
+	case opentui.KeyUp:
+		t.ScrollBy(-1)
+	case opentui.KeyDown:
+		t.ScrollBy(1)
+	case opentui.KeyPageUp:
+		t.ScrollBy(-page)
+	case opentui.KeyPageDown:
+		t.ScrollBy(page)
+	case opentui.KeyHome:
+		t.ScrollTo(0)
+	case opentui.KeyEnd:
+		_, rows, _ := t.view()
+		t.ScrollTo(rows)
+	default:
+		return false
+	}
+	return true
+}
+
+// Register adds the text to the renderer's hit grid under its ID, so that
+// HandleMouse only scrolls for wheel events over the text that no area registered
+// later covers. The grid is rebuilt every frame, so register the text whenever it's
+// rendered.
+func (t *RichText) Register(renderer *opentui.Renderer) error {
+	if err := renderer.AddToHitGrid(t.Rect.X, t.Rect.Y, t.Rect.Width, t.Rect.Height, t.ID); err != nil {
+		return err
+	}
+	t.renderer = renderer
+	return nil
+}
+
+// HandleMouse scrolls three rows per step of the mouse wheel over the text. Returns
+// whether the event was over the text.
+func (t *RichText) HandleMouse(ev opentui.MouseEvent) bool {
+	if !hits(t.renderer, t.ID, t.Rect, ev.Position) {
+		return false
+	}
+	if ev.Pressed && ev.Button == opentui.MouseWheelUp {
+		t.ScrollBy(-wheelStep)
+	} else if ev.Pressed && ev.Button == opentui.MouseWheelDown {
+		t.ScrollBy(wheelStep)
+	}
+	return true
+}
+
+// Render draws the rows of text in view into buf, keeping the background where the
+// text has none, and the scrollbar if enabled and needed. Only the lines in view are
+// drawn.
+func (t *RichText) Render(buf *opentui.Buffer) error {
+	width, rows, err := t.view()
+	if err != nil {
+		return err
+	}
+	// The text may have shrunk since the last scroll
+	t.offset = max(0, min(t.offset, rows-int(t.Rect.Height)))
+	height := int(t.Rect.Height)
+	clip := opentui.ClipRect{X: t.Rect.X, Y: t.Rect.Y, Width: width, Height: t.Rect.Height}
+
+	row := 0
+	for _, p := range t.paragraphs {
+		if row >= t.offset+height {
+			break
+		}
+		for i, line := range p.lines {
+			r := row + i
+			if r < t.offset || r >= t.offset+height {
+				continue
+			}
+			x := t.Rect.X + alignOffset(line.Width, width, p.Align)
+			y := t.Rect.Y + int32(r-t.offset)
+			if err := buf.DrawTextBufferRegion(p.text, x, y, uint32(i), 1, &clip); err != nil {
+				return err
+			}
+		}
+		row += p.rows() + int(t.Spacing)
+	}
+
+	if width < t.Rect.Width && t.Rect.X >= 0 && t.Rect.Y >= 0 && t.Rect.Height > 0 {
+		x := uint32(t.Rect.X) + width
+		thumb, offset := scrollbarThumb(t.Rect.Height, uint32(t.offset), uint32(rows))
+		if err := buf.FillRect(x, uint32(t.Rect.Y), 1, t.Rect.Height, t.Style.Track); err != nil {
+			return err
+		}
+		return buf.FillRect(x, uint32(t.Rect.Y)+offset, 1, thumb, t.Style.Thumb)
+	}
+	return nil
+}
+
+// alignOffset returns the column a line of lineWidth cells starts at in width cells.
+func alignOffset(lineWidth, width uint32, align opentui.TextAlignment) int32 {
+	if lineWidth >= width {
+		return 0
+	}
+	switch align {
+	case opentui.AlignCenter:
+		return int32((width - lineWidth) / 2)
+	case opentui.AlignRight:
+		return int32(width - lineWidth)
+	}
+	return 0
+}
@@ -0,0 +1,87 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func skipWithoutTextBuffers(t *testing.T) {
+	text := opentui.NewTextBuffer(1, opentui.WidthMethodUnicode)
+	if text == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	text.Close()
+}
+
+func TestRichTextLayout(t *testing.T) {
+	skipWithoutTextBuffers(t)
+	text := NewRichText(0, rect(0, 0, 12, 3),
+		Paragraph{Chunks: []opentui.TextChunk{{Text: "one two three four"}}},
+		Paragraph{Chunks: []opentui.TextChunk{{Text: "end"}}, Align: opentui.AlignRight},
+	)
+	defer text.Close()
+	text.Spacing = 1
+
+	if height, err := text.PreferredHeight(12); err != nil || height != 4 {
+		t.Errorf("PreferredHeight(12) = %d, %v, want 4", height, err)
+	}
+	if height, _ := text.PreferredHeight(40); height != 3 {
+		t.Errorf("PreferredHeight(40) = %d, want 3", height)
+	}
+
+	text.HandleKey(opentui.KeyEvent{Key: opentui.KeyEnd})
+	if text.Offset() != 1 {
+		t.Errorf("End scrolled to %d, want 1", text.Offset())
+	}
+	text.HandleMouse(mouseAt(2, 1, opentui.MouseWheelUp, true, false))
+	if text.Offset() != 0 {
+		t.Errorf("wheel up scrolled to %d", text.Offset())
+	}
+
+	// Changing a paragraph only rewraps that one
+	first := text.paragraphs[0]
+	text.SetParagraph(1, Paragraph{Chunks: []opentui.TextChunk{{Text: "a much longer ending"}}})
+	if height, _ := text.PreferredHeight(12); height != 6 || text.paragraphs[0] != first || first.text == nil {
+		t.Errorf("after SetParagraph: height %d", height)
+	}
+}
+
+func TestRichTextRender(t *testing.T) {
+	skipWithoutTextBuffers(t)
+	buffer := opentui.NewBuffer(10, 3, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+
+	text := NewRichText(0, rect(0, 0, 10, 3),
+		Paragraph{Chunks: []opentui.TextChunk{{Text: "left"}}},
+		Paragraph{Chunks: []opentui.TextChunk{{Text: "mid"}}, Align: opentui.AlignCenter},
+		Paragraph{Chunks: []opentui.TextChunk{{Text: "right"}}, Align: opentui.AlignRight},
+	)
+	defer text.Close()
+	if err := text.Render(buffer); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rows, _ := buffer.CaptureText()
+	for i, want := range []string{"left      ", "   mid    ", "     right"} {
+		if rows[i] != want {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+
+	text.Rect.Height = 2
+	text.Scrollbar = true
+	text.ScrollTo(1)
+	buffer.Clear(opentui.Black)
+	text.Render(buffer)
+	rows, _ = buffer.CaptureText()
+	if !strings.HasPrefix(rows[1], "    right ") {
+		t.Errorf("with a scrollbar, row 1 = %q, want the paragraph right aligned in 9 columns", rows[1])
+	}
+	if cell, _ := buffer.GetCell(9, 1); cell.Background != text.Style.Thumb {
+		t.Errorf("scrollbar thumb on %v", cell.Background)
+	}
+}
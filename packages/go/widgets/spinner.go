@@ -0,0 +1,157 @@
+package widgets
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// defaultSpinnerInterval is the time each frame of a spinner stays on screen
+const defaultSpinnerInterval = 80 * time.Millisecond
+
+// Frame sets for Spinner.Frames
+var (
+	SpinnerBraille = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerLine    = []string{"|", "/", "-", "\\"}
+	SpinnerDots    = []string{"⣾", "⣽", "⣻", "⢿", "⡿", "⣟", "⣯", "⣷"}
+	SpinnerArc     = []string{"◜", "◠", "◝", "◞", "◡", "◟"}
+	SpinnerMoon    = []string{"🌑", "🌒", "🌓", "🌔", "🌕", "🌖", "🌗", "🌘"} // Double width
+)
+
+// Glyphs a stopped spinner shows in place of its frames
+const (
+	spinnerSuccess = "✓"
+	spinnerFailure = "✗"
+)
+
+// SpinnerStyle holds the colors of a spinner.
+type SpinnerStyle struct {
+	Spinner opentui.RGBA // Frames while spinning
+	Label   opentui.RGBA
+	Success opentui.RGBA // ✓ once stopped successfully
+	Error   opentui.RGBA // ✗ once stopped in failure
+}
+
+// DefaultSpinnerStyle draws a cyan spinner with a white label, stopping on a green ✓
+// or a red ✗.
+var DefaultSpinnerStyle = SpinnerStyle{
+	Spinner: opentui.Cyan,
+	Label:   opentui.White,
+	Success: opentui.Green,
+	Error:   opentui.Red,
+}
+
+// Spinner is an activity indicator cycling through Frames in a cell, or two for double
+// width frame sets, followed by an optional label. Frames advance with Tick, called
+// from the frame loop, so a spinner needs no goroutine of its own. The label stays in
+// the same column whatever the width of the current frame.
+type Spinner struct {
+	Position opentui.Position
+	Frames   []string      // Frame set, such as SpinnerBraille
+	Interval time.Duration // Time each frame stays on screen
+	Label    string        // Drawn one cell after the frames, if not empty
+	Style    SpinnerStyle
+
+	start   time.Time // time of the first Tick since started, zero before it
+	frame   int
+	stopped bool
+	success bool
+}
+
+// NewSpinner creates a running spinner at pos cycling through frames every 80ms.
+func NewSpinner(pos opentui.Position, frames []string, label string) *Spinner {
+	return &Spinner{Position: pos, Frames: frames, Interval: defaultSpinnerInterval, Label: label, Style: DefaultSpinnerStyle}
+}
+
+// Tick shows the frame due at now, counting from the first Tick since the spinner was
+// created or restarted. Returns whether the frame changed, so that callers rendering
+// only on change know when to. A stopped spinner doesn't move.
+func (s *Spinner) Tick(now time.Time) bool {
+	if s.stopped || len(s.Frames) == 0 || s.Interval <= 0 {
+		return false
+	}
+	if s.start.IsZero() {
+		s.start = now
+	}
+	frame := int(now.Sub(s.start)/s.Interval) % len(s.Frames)
+	if frame < 0 {
+		frame += len(s.Frames)
+	}
+	changed := frame != s.frame
+	s.frame = frame
+	return changed
+}
+
+// Stop stops the spinner, replacing its frames with ✓ if success is true, else ✗.
+func (s *Spinner) Stop(success bool) {
+	s.stopped, s.success = true, success
+}
+
+// Start restarts a stopped spinner from its first frame.
+func (s *Spinner) Start() {
+	s.stopped, s.start, s.frame = false, time.Time{}, 0
+}
+
+// Stopped reports whether Stop was called since the spinner was last started.
+func (s *Spinner) Stopped() bool {
+	return s.stopped
+}
+
+// Glyph returns the frame on screen, or ✓ or ✗ once stopped.
+func (s *Spinner) Glyph() string {
+	switch {
+	case s.stopped && s.success:
+		return spinnerSuccess
+	case s.stopped:
+		return spinnerFailure
+	case len(s.Frames) == 0:
+		return ""
+	}
+	return s.Frames[s.frame%len(s.Frames)]
+}
+
+// glyphWidth returns the number of cells of the widest frame, the column width that
+// keeps the label in place.
+func (s *Spinner) glyphWidth() uint32 {
+	width := max(textWidth(spinnerSuccess), textWidth(spinnerFailure))
+	for _, frame := range s.Frames {
+		width = max(width, textWidth(frame))
+	}
+	return width
+}
+
+// Width returns the number of cells the spinner covers, label included.
+func (s *Spinner) Width() uint32 {
+	if s.Label == "" {
+		return s.glyphWidth()
+	}
+	return s.glyphWidth() + 1 + textWidth(s.Label)
+}
+
+// Render draws the current frame, padded to the width of the widest one, and the label
+// after a space into buf, keeping the background.
+func (s *Spinner) Render(buf *opentui.Buffer) error {
+	if s.Position.X < 0 || s.Position.Y < 0 {
+		return nil
+	}
+	x, y := uint32(s.Position.X), uint32(s.Position.Y)
+	color := s.Style.Spinner
+	if s.stopped && s.success {
+		color = s.Style.Success
+	} else if s.stopped {
+		color = s.Style.Error
+	}
+	glyph := s.Glyph()
+	column := s.glyphWidth()
+	if s.Label != "" {
+		column++
+	}
+	if err := buf.DrawText(glyph+strings.Repeat(" ", int(column-textWidth(glyph))), x, y, color, nil, 0); err != nil {
+		return err
+	}
+	if s.Label == "" {
+		return nil
+	}
+	return buf.DrawText(s.Label, x+column, y, s.Style.Label, nil, 0)
+}
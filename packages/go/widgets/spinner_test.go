@@ -0,0 +1,79 @@
+package widgets
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestSpinnerTick(t *testing.T) {
+	spinner := NewSpinner(opentui.Position{}, SpinnerLine, "")
+	spinner.Interval = 100 * time.Millisecond
+	start := time.Now()
+	for _, tc := range []struct {
+		after   time.Duration
+		glyph   string
+		changed bool
+	}{
+		{0, "|", false},
+		{50 * time.Millisecond, "|", false},
+		{100 * time.Millisecond, "/", true},
+		{350 * time.Millisecond, "\\", true},
+		{420 * time.Millisecond, "|", true},
+	} {
+		if changed := spinner.Tick(start.Add(tc.after)); changed != tc.changed || spinner.Glyph() != tc.glyph {
+			t.Errorf("after %v: glyph %q, changed %v, want %q, %v", tc.after, spinner.Glyph(), changed, tc.glyph, tc.changed)
+		}
+	}
+
+	spinner.Stop(true)
+	if spinner.Tick(start.Add(time.Second)) || spinner.Glyph() != "✓" || !spinner.Stopped() {
+		t.Errorf("stopped spinner shows %q", spinner.Glyph())
+	}
+	spinner.Stop(false)
+	if spinner.Glyph() != "✗" {
+		t.Errorf("failed spinner shows %q", spinner.Glyph())
+	}
+	spinner.Start()
+	spinner.Tick(start.Add(time.Hour))
+	if spinner.Stopped() || spinner.Glyph() != "|" {
+		t.Errorf("restarted spinner shows %q", spinner.Glyph())
+	}
+}
+
+func TestSpinnerRender(t *testing.T) {
+	buffer := opentui.NewBuffer(10, 2, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+
+	moon := NewSpinner(opentui.Position{X: 1, Y: 0}, SpinnerMoon, "wait")
+	line := NewSpinner(opentui.Position{X: 1, Y: 1}, SpinnerLine, "wait")
+	if moon.Width() != 7 || line.Width() != 6 {
+		t.Errorf("widths %d and %d, want 7 and 6", moon.Width(), line.Width())
+	}
+	moon.Render(buffer)
+	line.Stop(true)
+	line.Render(buffer)
+
+	rows, _ := buffer.CaptureText()
+	for i, want := range []string{" 🌑  wait", " ✓ wait"} {
+		if !strings.HasPrefix(rows[i], want) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+	if cell, _ := buffer.GetCell(1, 1); cell.Foreground != DefaultSpinnerStyle.Success {
+		t.Errorf("✓ drawn in %v, want %v", cell.Foreground, DefaultSpinnerStyle.Success)
+	}
+
+	// Once stopped, the narrow ✓ replaces the wide frame without moving the label
+	moon.Stop(true)
+	moon.Render(buffer)
+	rows, _ = buffer.CaptureText()
+	if !strings.HasPrefix(rows[0], " ✓  wait") {
+		t.Errorf("row 0 = %q after stopping", rows[0])
+	}
+}
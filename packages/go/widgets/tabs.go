@@ -0,0 +1,368 @@
+package widgets
+
+import (
+	"strings"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// TabsOverflow selects what a tab bar does with titles that don't fit on its row.
+type TabsOverflow uint8
+
+const (
+	TabsScroll TabsOverflow = iota // Show the tabs that fit, with ‹ and › marking more on either side
+	TabsShrink                     // Cut the longest titles with an ellipsis until all tabs fit
+)
+
+// TabsStyle holds the colors of a tab bar.
+type TabsStyle struct {
+	Foreground       opentui.RGBA // Inactive titles
+	Background       opentui.RGBA // Inactive titles and the rest of the row
+	ActiveForeground opentui.RGBA
+	ActiveBackground opentui.RGBA
+	ActiveAttributes opentui.Attributes
+	Indicator        opentui.RGBA // ‹ and › when scrolling
+}
+
+// DefaultTabsStyle draws gray titles on dark gray, with the active one in bold white
+// on blue.
+var DefaultTabsStyle = TabsStyle{
+	Foreground:       opentui.Gray,
+	Background:       opentui.NewRGB(0.15, 0.15, 0.15),
+	ActiveForeground: opentui.White,
+	ActiveBackground: opentui.Blue,
+	ActiveAttributes: opentui.AttrBold,
+	Indicator:        opentui.Yellow,
+}
+
+// Tabs is a row of titles on top of a content area, one of them active. The arrow
+// keys (HandleKey) and clicks on a title (HandleMouse) switch tabs. The rows below the
+// titles belong to the active tab; callers draw its panel in ActiveContentRect.
+type Tabs struct {
+	ID       uint32       // Hit grid ID, see Register
+	Rect     opentui.Rect // Title row and content area below it
+	Style    TabsStyle
+	Overflow TabsOverflow
+	OnChange func(index int) // Called when another tab becomes active
+
+	titles   []string
+	active   int
+	offset   int // first tab shown when scrolling
+	renderer *opentui.Renderer
+}
+
+// tabSpan is the part of the title row a tab is drawn on, from x cells after Rect.X
+type tabSpan struct {
+	index    int
+	x, width uint32
+}
+
+// NewTabs creates a tab bar with the first of titles active.
+func NewTabs(id uint32, rect opentui.Rect, titles []string, style TabsStyle) *Tabs {
+	return &Tabs{ID: id, Rect: rect, Style: style, titles: titles}
+}
+
+// Titles returns the titles of the tabs.
+func (t *Tabs) Titles() []string {
+	return t.titles
+}
+
+// SetTitles replaces the tabs, keeping the active index if it still exists.
+func (t *Tabs) SetTitles(titles []string) {
+	t.titles = titles
+	t.active = max(0, min(t.active, len(titles)-1))
+	t.offset = 0
+	t.scrollToActive()
+}
+
+// Active returns the index of the active tab, or -1 without tabs.
+func (t *Tabs) Active() int {
+	if len(t.titles) == 0 {
+		return -1
+	}
+	return t.active
+}
+
+// SetActive makes the tab at index, clamped to the tabs, active and scrolls it into
+// view, calling OnChange if it wasn't active before.
+func (t *Tabs) SetActive(index int) {
+	if len(t.titles) == 0 {
+		return
+	}
+	index = max(0, min(index, len(t.titles)-1))
+	changed := index != t.active
+	t.active = index
+	t.scrollToActive()
+	if changed && t.OnChange != nil {
+		t.OnChange(index)
+	}
+}
+
+// ActiveContentRect returns the area below the title row, where the panel of the
+// active tab goes.
+func (t *Tabs) ActiveContentRect() opentui.Rect {
+	rect := t.Rect
+	if rect.Height > 0 {
+		rect.Y++
+		rect.Height--
+	}
+	return rect
+}
+
+// naturalWidth returns the width of the tab at index with its whole title, padded with
+// a space on both sides.
+func (t *Tabs) naturalWidth(index int) uint32 {
+	return textWidth(t.titles[index]) + 2
+}
+
+// fits reports whether the tabs from first to last fit between the scroll indicators.
+func (t *Tabs) fits(first, last int) bool {
+	total := uint32(0)
+	for i := first; i <= last; i++ {
+		total += t.naturalWidth(i)
+	}
+	return total+2 <= t.Rect.Width
+}
+
+// overflows reports whether the tabs are too wide for the row.
+func (t *Tabs) overflows() bool {
+	total := uint32(0)
+	for i := range t.titles {
+		total += t.naturalWidth(i)
+	}
+	return total > t.Rect.Width
+}
+
+// maxOffset returns the first tab shown when scrolled to the end.
+func (t *Tabs) maxOffset() int {
+	offset := len(t.titles) - 1
+	for offset > 0 && t.fits(offset-1, len(t.titles)-1) {
+		offset--
+	}
+	return max(offset, 0)
+}
+
+// scrollToActive scrolls the title row as little as possible to show the active tab.
+func (t *Tabs) scrollToActive() {
+	if t.active < t.offset {
+		t.offset = t.active
+	}
+	for t.offset < t.active && !t.fits(t.offset, t.active) {
+		t.offset++
+	}
+}
+
+// scroll moves the title row by tabs without changing the active tab.
+func (t *Tabs) scroll(tabs int) {
+	t.offset = max(0, min(t.offset+tabs, t.maxOffset()))
+}
+
+// scrolling reports whether the title row shows scroll indicators.
+func (t *Tabs) scrolling() bool {
+	return t.Overflow == TabsScroll && t.overflows() && t.Rect.Width > 2
+}
+
+// layout returns where the tabs shown are drawn on the title row.
+func (t *Tabs) layout() []tabSpan {
+	var spans []tabSpan
+	switch {
+	case !t.overflows():
+		col := uint32(0)
+		for i := range t.titles {
+			spans = append(spans, tabSpan{i, col, t.naturalWidth(i)})
+			col += t.naturalWidth(i)
+		}
+	case t.scrolling():
+		// Whole tabs from the offset, only the first one cut if it alone is too wide
+		col, end := uint32(1), t.Rect.Width-1
+		offset := min(t.offset, t.maxOffset())
+		for i := offset; i < len(t.titles); i++ {
+			width := t.naturalWidth(i)
+			if col+width > end {
+				if i > offset {
+					break
+				}
+				width = end - col
+			}
+			spans = append(spans, tabSpan{i, col, width})
+			col += width
+		}
+	case t.Overflow == TabsShrink:
+		widths := shrinkWidths(t.titles, t.Rect.Width)
+		col := uint32(0)
+		for i, width := range widths {
+			if width > 0 {
+				spans = append(spans, tabSpan{i, col, width})
+			}
+			col += width
+		}
+	}
+	return spans
+}
+
+// shrinkWidths returns the width of each tab so that all of them fill width cells, the
+// widest ones cut to the same size. When there aren't enough cells for one each, the
+// last tabs get none.
+func shrinkWidths(titles []string, width uint32) []uint32 {
+	natural := make([]uint32, len(titles))
+	longest := uint32(0)
+	for i, title := range titles {
+		natural[i] = textWidth(title) + 2
+		longest = max(longest, natural[i])
+	}
+	total := func(limit uint32) uint32 {
+		sum := uint32(0)
+		for _, w := range natural {
+			sum += min(w, limit)
+		}
+		return sum
+	}
+	// The largest limit on tab widths that fits, found by bisection
+	low, high := uint32(0), longest
+	for low < high {
+		mid := (low + high + 1) / 2
+		if total(mid) <= width {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	widths := make([]uint32, len(titles))
+	spare := width - total(low)
+	for i, w := range natural {
+		widths[i] = min(w, low)
+		// Cells left over go to the cut tabs from the left
+		if w > low && spare > 0 {
+			widths[i]++
+			spare--
+		}
+	}
+	return widths
+}
+
+// HandleKey activates the previous tab with Left, the next one with Right, and the
+// first or last one with Home or End. Returns whether the key was one of these.
+func (t *Tabs) HandleKey(ev opentui.KeyEvent) bool {
+	if ev.Modifiers&(opentui.ModCtrl|opentui.ModAlt|opentui.ModSuper) != 0 {
+		return false
+	}
+	switch ev.Key {
+	case opentui.KeyLeft:
+		t.SetActive(t.active - 1)
+	case opentui.KeyRight:
+		t.SetActive(t.active + 1)
+	case opentui.KeyHome:
+		t.SetActive(0)
+	case opentui.KeyEnd:
+		t.SetActive(len(t.titles) - 1)
+	default:
+		return false
+	}
+	return true
+}
+
+// Register adds the title row to the renderer's hit grid under the tab bar's ID, so
+// that HandleMouse ignores clicks on titles covered by areas registered later. The
+// grid is rebuilt every frame, so register the tab bar whenever it's rendered.
+func (t *Tabs) Register(renderer *opentui.Renderer) error {
+	if t.Rect.Height == 0 {
+		return nil
+	}
+	if err := renderer.AddToHitGrid(t.Rect.X, t.Rect.Y, t.Rect.Width, 1, t.ID); err != nil {
+		return err
+	}
+	t.renderer = renderer
+	return nil
+}
+
+// HandleMouse activates the tab whose title is clicked with the left button. Clicks
+// on ‹ and › and the mouse wheel scroll the title row. Returns whether the event was
+// over the title row.
+func (t *Tabs) HandleMouse(ev opentui.MouseEvent) bool {
+	if !t.contains(ev.Position) {
+		return false
+	}
+	if !ev.Pressed || ev.Motion {
+		return true
+	}
+	col := uint32(ev.Position.X - t.Rect.X)
+	switch ev.Button {
+	case opentui.MouseWheelUp:
+		t.scroll(-1)
+	case opentui.MouseWheelDown:
+		t.scroll(1)
+	case opentui.MouseLeft:
+		if t.scrolling() && col == 0 {
+			t.scroll(-1)
+			break
+		}
+		if t.scrolling() && col == t.Rect.Width-1 {
+			t.scroll(1)
+			break
+		}
+		for _, span := range t.layout() {
+			if col >= span.x && col < span.x+span.width {
+				t.SetActive(span.index)
+			}
+		}
+	}
+	return true
+}
+
+// contains reports whether the title row is under the cell at pos, asking the hit
+// grid if the tab bar was registered.
+func (t *Tabs) contains(pos opentui.Position) bool {
+	row := opentui.Rect{Position: t.Rect.Position, Size: opentui.Size{Width: t.Rect.Width, Height: min(t.Rect.Height, 1)}}
+	if !row.Contains(pos.X, pos.Y) {
+		return false
+	}
+	if t.renderer == nil || !t.renderer.Valid() {
+		return true
+	}
+	hit, err := t.renderer.HitTest(uint32(pos.X), uint32(pos.Y))
+	return err == nil && hit.Found && hit.ID == t.ID
+}
+
+// Render draws the title row into buf. Titles cut short end with an ellipsis. The
+// content area is left to the caller.
+func (t *Tabs) Render(buf *opentui.Buffer) error {
+	if t.Rect.X < 0 || t.Rect.Y < 0 || t.Rect.Width == 0 || t.Rect.Height == 0 {
+		return nil
+	}
+	x, y := uint32(t.Rect.X), uint32(t.Rect.Y)
+	if err := buf.FillRect(x, y, t.Rect.Width, 1, t.Style.Background); err != nil {
+		return err
+	}
+	for _, span := range t.layout() {
+		fg, bg, attrs := t.Style.Foreground, t.Style.Background, opentui.Attributes(0)
+		if span.index == t.active {
+			fg, bg, attrs = t.Style.ActiveForeground, t.Style.ActiveBackground, t.Style.ActiveAttributes
+		}
+		if err := buf.DrawText(tabLabel(t.titles[span.index], span.width), x+span.x, y, fg, &bg, attrs); err != nil {
+			return err
+		}
+	}
+	if !t.scrolling() {
+		return nil
+	}
+	if min(t.offset, t.maxOffset()) > 0 {
+		if err := buf.DrawText("‹", x, y, t.Style.Indicator, &t.Style.Background, 0); err != nil {
+			return err
+		}
+	}
+	if t.offset < t.maxOffset() {
+		return buf.DrawText("›", x+t.Rect.Width-1, y, t.Style.Indicator, &t.Style.Background, 0)
+	}
+	return nil
+}
+
+// tabLabel returns title padded with a space on both sides to width cells, cut with an
+// ellipsis if too long. Tabs narrower than three cells get no padding.
+func tabLabel(title string, width uint32) string {
+	pad := uint32(1)
+	if width < 3 {
+		pad = 0
+	}
+	label := strings.Repeat(" ", int(pad)) + opentui.TruncateToWidth(title, width-2*pad, "…")
+	return label + strings.Repeat(" ", int(width-textWidth(label)))
+}
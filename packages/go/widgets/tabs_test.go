@@ -0,0 +1,113 @@
+package widgets
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+// tenTabs returns a bar of ten tabs, 71 cells wide in full, on a 30 column row above
+// four rows of content.
+func tenTabs(overflow TabsOverflow) *Tabs {
+	titles := make([]string, 10)
+	for i := range titles {
+		titles[i] = fmt.Sprintf("Tab %d", i+1)
+	}
+	rect := opentui.Rect{Position: opentui.Position{X: 0, Y: 1}, Size: opentui.Size{Width: 30, Height: 5}}
+	tabs := NewTabs(0, rect, titles, DefaultTabsStyle)
+	tabs.Overflow = overflow
+	return tabs
+}
+
+func renderRow(t *testing.T, tabs *Tabs) string {
+	t.Helper()
+	buffer := opentui.NewBuffer(30, 2, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+	if err := tabs.Render(buffer); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rows, _ := buffer.CaptureText()
+	return rows[1]
+}
+
+func TestTabsKeys(t *testing.T) {
+	tabs := tenTabs(TabsScroll)
+	var changes []int
+	tabs.OnChange = func(index int) { changes = append(changes, index) }
+
+	for _, k := range []rune{opentui.KeyLeft, opentui.KeyRight, opentui.KeyRight, opentui.KeyEnd, opentui.KeyRight, opentui.KeyHome} {
+		if !tabs.HandleKey(opentui.KeyEvent{Key: k}) {
+			t.Errorf("key %#x not handled", k)
+		}
+	}
+	if want := []int{1, 2, 9, 0}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("OnChange called with %v, want %v", changes, want)
+	}
+	if tabs.HandleKey(opentui.KeyEvent{Key: 'x'}) {
+		t.Error("other keys should not be handled")
+	}
+
+	content := tabs.ActiveContentRect()
+	if content.Y != 2 || content.Height != 4 || content.Width != 30 {
+		t.Errorf("content rect %+v", content)
+	}
+}
+
+func TestTabsScroll(t *testing.T) {
+	tabs := tenTabs(TabsScroll)
+	if row := renderRow(t, tabs); row != "  Tab 1  Tab 2  Tab 3  Tab 4 ›" {
+		t.Errorf("first tabs: %q", row)
+	}
+	tabs.SetActive(5)
+	if row := renderRow(t, tabs); row != "‹ Tab 3  Tab 4  Tab 5  Tab 6 ›" {
+		t.Errorf("scrolled to the sixth tab: %q", row)
+	}
+	tabs.SetActive(9)
+	if row := renderRow(t, tabs); row != "‹ Tab 8  Tab 9  Tab 10        " {
+		t.Errorf("scrolled to the end: %q", row)
+	}
+
+	// ‹ scrolls back without changing the active tab, then a click on a title does
+	tabs.HandleMouse(mouseAt(0, 1, opentui.MouseLeft, true, false))
+	if row := renderRow(t, tabs); row != "‹ Tab 7  Tab 8  Tab 9        ›" || tabs.Active() != 9 {
+		t.Errorf("after clicking ‹: %q, tab %d active", row, tabs.Active())
+	}
+	tabs.HandleMouse(mouseAt(10, 1, opentui.MouseLeft, true, false))
+	if tabs.Active() != 7 {
+		t.Errorf("clicking Tab 8 activated tab %d", tabs.Active())
+	}
+	if tabs.HandleMouse(mouseAt(10, 2, opentui.MouseLeft, true, false)) {
+		t.Error("clicks on the content should be ignored")
+	}
+}
+
+func TestTabsShrink(t *testing.T) {
+	tabs := tenTabs(TabsShrink)
+	tabs.SetActive(4)
+	if row := renderRow(t, tabs); row != " …  …  …  …  …  …  …  …  …  … " {
+		t.Errorf("shrunk tabs: %q", row)
+	}
+	tabs.HandleMouse(mouseAt(28, 1, opentui.MouseLeft, true, false))
+	if tabs.Active() != 9 {
+		t.Errorf("clicking the last tab activated tab %d", tabs.Active())
+	}
+
+	for _, tc := range []struct {
+		width uint32
+		want  []uint32
+	}{
+		{30, []uint32{3, 10, 6}},
+		{15, []uint32{3, 6, 6}},
+		{14, []uint32{3, 6, 5}},
+		{2, []uint32{1, 1, 0}},
+	} {
+		if got := shrinkWidths([]string{"a", "Settings", "Help"}, tc.width); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("shrinkWidths in %d cells = %v, want %v", tc.width, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package widgets
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestCheckbox(t *testing.T) {
+	box := NewCheckbox(0, opentui.Position{X: 2, Y: 1}, "Dark mode")
+	var changes []bool
+	box.OnChange = func(checked bool) { changes = append(changes, checked) }
+
+	if !box.HandleKey(opentui.KeyEvent{Key: ' '}) || !box.Checked() {
+		t.Error("Space should check the box")
+	}
+	if box.HandleKey(opentui.KeyEvent{Key: 'x'}) {
+		t.Error("other keys should not be handled")
+	}
+	if box.HandleMouse(mouseAt(15, 1, opentui.MouseLeft, true, false)) {
+		t.Error("a click past the label should be ignored")
+	}
+	box.HandleMouse(mouseAt(14, 1, opentui.MouseLeft, true, false))
+	box.Disabled = true
+	box.HandleKey(opentui.KeyEvent{Key: ' '})
+	box.HandleMouse(mouseAt(2, 1, opentui.MouseLeft, true, false))
+	if want := []bool{true, false}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("OnChange called with %v, want %v", changes, want)
+	}
+}
+
+func TestRadioGroup(t *testing.T) {
+	group := NewRadioGroup(0, opentui.Position{X: 1, Y: 1}, "Small", "Medium", "Large")
+	var changes []int
+	group.OnChange = func(index int) { changes = append(changes, index) }
+
+	for _, k := range []rune{opentui.KeyDown, opentui.KeyRight, opentui.KeyDown, opentui.KeyUp} {
+		group.HandleKey(opentui.KeyEvent{Key: k})
+	}
+	group.HandleMouse(mouseAt(4, 2, opentui.MouseLeft, true, false))
+	if want := []int{1, 2, 0, 2, 1}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("OnChange called with %v, want %v", changes, want)
+	}
+
+	group.Horizontal = true
+	if rect := group.Rect(); rect.Width != 32 || rect.Height != 1 {
+		t.Errorf("horizontal group covers %+v", rect.Size)
+	}
+	group.HandleMouse(mouseAt(24, 1, opentui.MouseLeft, true, false))
+	if group.Selected() != 2 {
+		t.Errorf("clicking Large selected %d", group.Selected())
+	}
+	if group.HandleMouse(mouseAt(10, 1, opentui.MouseLeft, true, false)) || group.Selected() != 2 {
+		t.Error("a click between options should be ignored")
+	}
+}
+
+func TestToggleRender(t *testing.T) {
+	buffer := opentui.NewBuffer(24, 4, false, opentui.WidthMethodUnicode)
+	if buffer == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer buffer.Close()
+
+	box := NewCheckbox(0, opentui.Position{X: 0, Y: 0}, "Sound")
+	box.Glyphs = ASCIICheckboxGlyphs
+	box.SetChecked(true)
+	group := NewRadioGroup(0, opentui.Position{X: 0, Y: 1}, "Low", "High")
+	group.Glyphs = ASCIIRadioGlyphs
+	group.Focused = true
+	group.Select(1)
+	box.Render(buffer)
+	group.Render(buffer)
+
+	rows, _ := buffer.CaptureText()
+	for i, want := range []string{"[*] Sound", "( ) Low", "(o) High"} {
+		if !strings.HasPrefix(rows[i], want) {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+	if cell, _ := buffer.GetCell(4, 2); cell.Background != group.Style.FocusBackground {
+		t.Errorf("focused option on %v", cell.Background)
+	}
+}
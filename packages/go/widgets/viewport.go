@@ -0,0 +1,259 @@
+package widgets
+
+import (
+	"github.com/sst/opentui/packages/go"
+)
+
+// wheelStep is the number of rows a step of the mouse wheel scrolls a viewport by
+const wheelStep = 3
+
+// ViewportStyle holds the colors of the scrollbars of a viewport.
+type ViewportStyle struct {
+	Track opentui.RGBA // Background of the scrollbar
+	Thumb opentui.RGBA // Part of the scrollbar standing for the content in view
+}
+
+// DefaultViewportStyle draws light gray thumbs on dark gray tracks.
+var DefaultViewportStyle = ViewportStyle{
+	Track: opentui.NewRGB(0.2, 0.2, 0.2),
+	Thumb: opentui.NewRGB(0.6, 0.6, 0.6),
+}
+
+// Viewport shows part of content larger than its rect, either an offscreen Buffer or
+// a TextBuffer, scrolled to a position. Scrolling stops at the edges of the content.
+// With Scrollbars set, a scrollbar takes the last column while the content is taller
+// than the viewport, and another the last row while it is wider.
+type Viewport struct {
+	ID         uint32       // Hit grid ID, see Register
+	Rect       opentui.Rect // Cells covered, scrollbars included; change it with SetRect
+	Style      ViewportStyle
+	Scrollbars bool
+
+	content  *opentui.Buffer
+	text     *opentui.TextBuffer
+	x, y     int // content position shown at the top left corner
+	renderer *opentui.Renderer
+}
+
+// NewViewport creates a viewport onto content, scrolled to its top left corner.
+func NewViewport(id uint32, rect opentui.Rect, content *opentui.Buffer) *Viewport {
+	return &Viewport{ID: id, Rect: rect, Style: DefaultViewportStyle, content: content}
+}
+
+// NewTextViewport creates a viewport onto the lines of text, scrolled to its start.
+// The lines are the ones reported by GetLineInfo, so FinalizeLineInfo must have been
+// called unless the text buffer is wrapped.
+func NewTextViewport(id uint32, rect opentui.Rect, text *opentui.TextBuffer) *Viewport {
+	return &Viewport{ID: id, Rect: rect, Style: DefaultViewportStyle, text: text}
+}
+
+// ContentSize returns the size of the content: the buffer size, or the width of the
+// widest line and the number of lines of a text buffer.
+func (v *Viewport) ContentSize() (width, height uint32, err error) {
+	if v.content != nil {
+		return v.content.Size()
+	}
+	lines, err := v.text.GetLineInfo()
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, line := range lines {
+		width = max(width, line.Width)
+	}
+	return width, uint32(len(lines)), nil
+}
+
+// Position returns the content position shown at the top left corner.
+func (v *Viewport) Position() (x, y int) {
+	return v.x, v.y
+}
+
+// ScrollTo scrolls so that the content position x, y is at the top left corner, as
+// close as the edges of the content allow.
+func (v *Viewport) ScrollTo(x, y int) error {
+	width, height, err := v.ContentSize()
+	if err != nil {
+		return err
+	}
+	view := v.view(width, height)
+	v.x = max(0, min(x, int(width)-int(view.Width)))
+	v.y = max(0, min(y, int(height)-int(view.Height)))
+	return nil
+}
+
+// ScrollBy scrolls by dx columns and dy rows, stopping at the edges of the content.
+func (v *Viewport) ScrollBy(dx, dy int) error {
+	return v.ScrollTo(v.x+dx, v.y+dy)
+}
+
+// PageDown scrolls down by the height of the viewport.
+func (v *Viewport) PageDown() error {
+	return v.ScrollBy(0, v.pageHeight())
+}
+
+// PageUp scrolls up by the height of the viewport.
+func (v *Viewport) PageUp() error {
+	return v.ScrollBy(0, -v.pageHeight())
+}
+
+func (v *Viewport) pageHeight() int {
+	width, height, err := v.ContentSize()
+	if err != nil {
+		return int(v.Rect.Height)
+	}
+	return max(int(v.view(width, height).Height), 1)
+}
+
+// SetRect moves and resizes the viewport. The content position at the top left
+// corner stays there, unless the larger viewport would then reach past the content.
+func (v *Viewport) SetRect(rect opentui.Rect) error {
+	v.Rect = rect
+	return v.ScrollTo(v.x, v.y)
+}
+
+// view returns the part of the rect showing content of the given size, without the
+// scrollbars it needs. A scrollbar for one direction can make the other one needed.
+func (v *Viewport) view(width, height uint32) opentui.Rect {
+	view := v.Rect
+	if !v.Scrollbars {
+		return view
+	}
+	vertical, horizontal := false, false
+	for i := 0; i < 2; i++ {
+		vertical = height > view.Height && v.Rect.Width > 1
+		horizontal = width > view.Width && v.Rect.Height > 1
+		view.Size = v.Rect.Size
+		if vertical {
+			view.Width--
+		}
+		if horizontal {
+			view.Height--
+		}
+	}
+	return view
+}
+
+// Register adds the viewport to the renderer's hit grid under its ID, so that
+// HandleMouse only scrolls for wheel events over the viewport that no area registered
+// later covers. The grid is rebuilt every frame, so register the viewport whenever
+// it's rendered.
+func (v *Viewport) Register(renderer *opentui.Renderer) error {
+	if err := renderer.AddToHitGrid(v.Rect.X, v.Rect.Y, v.Rect.Width, v.Rect.Height, v.ID); err != nil {
+		return err
+	}
+	v.renderer = renderer
+	return nil
+}
+
+// HandleMouse scrolls three rows per step of the mouse wheel over the viewport, or
+// three columns with Shift held. Returns whether the event was over the viewport.
+func (v *Viewport) HandleMouse(ev opentui.MouseEvent) bool {
+	if !v.contains(ev.Position) {
+		return false
+	}
+	if !ev.Pressed || (ev.Button != opentui.MouseWheelUp && ev.Button != opentui.MouseWheelDown) {
+		return true
+	}
+	step := wheelStep
+	if ev.Button == opentui.MouseWheelUp {
+		step = -step
+	}
+	if ev.Modifiers&opentui.ModShift != 0 {
+		v.ScrollBy(step, 0)
+	} else {
+		v.ScrollBy(0, step)
+	}
+	return true
+}
+
+// contains reports whether the viewport is under the cell at pos, asking the hit grid
+// if the viewport was registered.
+func (v *Viewport) contains(pos opentui.Position) bool {
+	if !v.Rect.Contains(pos.X, pos.Y) {
+		return false
+	}
+	if v.renderer == nil || !v.renderer.Valid() {
+		return true
+	}
+	hit, err := v.renderer.HitTest(uint32(pos.X), uint32(pos.Y))
+	return err == nil && hit.Found && hit.ID == v.ID
+}
+
+// Render draws the content in view into buf, clipped to the viewport, and the
+// scrollbars if enabled and needed.
+func (v *Viewport) Render(buf *opentui.Buffer) error {
+	width, height, err := v.ContentSize()
+	if err != nil {
+		return err
+	}
+	// The content may have shrunk since the last scroll
+	if err := v.ScrollTo(v.x, v.y); err != nil {
+		return err
+	}
+	view := v.view(width, height)
+	if view.Width > 0 && view.Height > 0 {
+		if v.content != nil {
+			sourceWidth := min(view.Width, width-uint32(v.x))
+			sourceHeight := min(view.Height, height-uint32(v.y))
+			err = buf.DrawFrameBuffer(view.X, view.Y, v.content, uint32(v.x), uint32(v.y), sourceWidth, sourceHeight)
+		} else {
+			clip := opentui.ClipRect{X: view.X, Y: view.Y, Width: view.Width, Height: view.Height}
+			err = buf.DrawTextBufferRegion(v.text, view.X-int32(v.x), view.Y, uint32(v.y), view.Height, &clip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if view.Width < v.Rect.Width {
+		x := v.Rect.X + int32(view.Width)
+		if err := v.drawScrollbar(buf, x, view.Y, view.Height, v.y, height, true); err != nil {
+			return err
+		}
+	}
+	if view.Height < v.Rect.Height {
+		y := v.Rect.Y + int32(view.Height)
+		if err := v.drawScrollbar(buf, view.X, y, view.Width, v.x, width, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drawScrollbar draws a scrollbar length cells long from x, y, down if vertical or
+// else to the right, for a viewport showing length of total cells from position.
+func (v *Viewport) drawScrollbar(buf *opentui.Buffer, x, y int32, length uint32, position int, total uint32, vertical bool) error {
+	if x < 0 || y < 0 || length == 0 {
+		return nil
+	}
+	thumb, offset := scrollbarThumb(length, uint32(position), total)
+	fill := func(from, cells uint32, color opentui.RGBA) error {
+		if vertical {
+			return buf.FillRect(uint32(x), uint32(y)+from, 1, cells, color)
+		}
+		return buf.FillRect(uint32(x)+from, uint32(y), cells, 1, color)
+	}
+	if err := fill(0, length, v.Style.Track); err != nil {
+		return err
+	}
+	return fill(offset, thumb, v.Style.Thumb)
+}
+
+// scrollbarThumb returns the length and offset of the thumb of a scrollbar length
+// cells long, for length of total cells in view from position. The thumb is at least
+// a cell long, and touches the end of the track only when scrolled to the end.
+func scrollbarThumb(length, position, total uint32) (thumb, offset uint32) {
+	if total <= length {
+		return length, 0
+	}
+	thumb = max(uint32(uint64(length)*uint64(length)/uint64(total)), 1)
+	scrollable, track := total-length, length-thumb
+	offset = uint32(uint64(position) * uint64(track) / uint64(scrollable))
+	if position > 0 && offset == 0 && track > 1 {
+		offset = 1
+	}
+	if position < scrollable && offset == track && track > 1 {
+		offset = track - 1
+	}
+	return thumb, offset
+}
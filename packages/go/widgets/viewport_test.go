@@ -0,0 +1,189 @@
+package widgets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/sst/opentui/packages/go"
+)
+
+func TestScrollbarThumb(t *testing.T) {
+	tests := []struct {
+		length, position, total uint32
+		thumb, offset           uint32
+	}{
+		{10, 0, 5, 10, 0},
+		{10, 0, 10, 10, 0},
+		{10, 0, 20, 5, 0},
+		{10, 10, 20, 5, 5},
+		{10, 5, 20, 5, 2},
+		{10, 0, 1000, 1, 0},
+		// Scrolled a little, the thumb leaves the top; short of the end, the bottom
+		{10, 1, 1000, 1, 1},
+		{10, 989, 1000, 1, 8},
+		{10, 990, 1000, 1, 9},
+	}
+	for _, tt := range tests {
+		thumb, offset := scrollbarThumb(tt.length, tt.position, tt.total)
+		if thumb != tt.thumb || offset != tt.offset {
+			t.Errorf("scrollbarThumb(%d, %d, %d) = %d, %d, want %d, %d", tt.length, tt.position, tt.total, thumb, offset, tt.thumb, tt.offset)
+		}
+	}
+}
+
+func newContent(t *testing.T, width, height uint32) *opentui.Buffer {
+	t.Helper()
+	content := opentui.NewBuffer(width, height, false, opentui.WidthMethodUnicode)
+	if content == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	t.Cleanup(func() { content.Close() })
+	content.Clear(opentui.Black)
+	for y := uint32(0); y < height; y++ {
+		content.DrawText(fmt.Sprintf("%02d%s", y, "abcdefghijklmnopqrstuvwxyz"[:width-2]), 0, y, opentui.White, nil, 0)
+	}
+	return content
+}
+
+func TestViewportScrolling(t *testing.T) {
+	content := newContent(t, 20, 30)
+	rect := opentui.Rect{Size: opentui.Size{Width: 8, Height: 5}}
+	view := NewViewport(0, rect, content)
+
+	steps := []struct {
+		name string
+		do   func() error
+		x, y int
+	}{
+		{"ScrollTo", func() error { return view.ScrollTo(4, 10) }, 4, 10},
+		{"ScrollBy", func() error { return view.ScrollBy(-1, 2) }, 3, 12},
+		{"PageDown", view.PageDown, 3, 17},
+		{"PageUp", view.PageUp, 3, 12},
+		{"past the end", func() error { return view.ScrollTo(100, 100) }, 12, 25},
+		{"before the start", func() error { return view.ScrollBy(-100, -100) }, 0, 0},
+	}
+	for _, step := range steps {
+		if err := step.do(); err != nil {
+			t.Fatalf("%s failed: %v", step.name, err)
+		}
+		if x, y := view.Position(); x != step.x || y != step.y {
+			t.Errorf("%s: position %d, %d, want %d, %d", step.name, x, y, step.x, step.y)
+		}
+	}
+
+	// Scrollbars take a column and a row from the content shown
+	view.Scrollbars = true
+	view.ScrollTo(100, 100)
+	if x, y := view.Position(); x != 13 || y != 26 {
+		t.Errorf("end with scrollbars at %d, %d, want 13, 26", x, y)
+	}
+
+	// Resizing keeps the top left content position, unless the content ends
+	view.Scrollbars = false
+	view.ScrollTo(5, 20)
+	view.SetRect(opentui.Rect{Size: opentui.Size{Width: 10, Height: 8}})
+	if x, y := view.Position(); x != 5 || y != 20 {
+		t.Errorf("after growing: position %d, %d, want 5, 20", x, y)
+	}
+	view.SetRect(opentui.Rect{Size: opentui.Size{Width: 18, Height: 15}})
+	if x, y := view.Position(); x != 2 || y != 15 {
+		t.Errorf("after growing past the content: position %d, %d, want 2, 15", x, y)
+	}
+	view.SetRect(opentui.Rect{Size: opentui.Size{Width: 40, Height: 40}})
+	if x, y := view.Position(); x != 0 || y != 0 {
+		t.Errorf("larger than the content: position %d, %d", x, y)
+	}
+}
+
+func TestViewportWheel(t *testing.T) {
+	content := newContent(t, 20, 30)
+	rect := opentui.Rect{Position: opentui.Position{X: 2, Y: 2}, Size: opentui.Size{Width: 8, Height: 5}}
+	view := NewViewport(0, rect, content)
+
+	if view.HandleMouse(mouseAt(0, 0, opentui.MouseWheelDown, true, false)) {
+		t.Error("the wheel outside the viewport should be ignored")
+	}
+	view.HandleMouse(mouseAt(3, 3, opentui.MouseWheelDown, true, false))
+	view.HandleMouse(mouseAt(3, 3, opentui.MouseWheelDown, true, false))
+	view.HandleMouse(mouseAt(3, 3, opentui.MouseWheelUp, true, false))
+	if x, y := view.Position(); x != 0 || y != 3 {
+		t.Errorf("after the wheel: position %d, %d, want 0, 3", x, y)
+	}
+	shifted := mouseAt(3, 3, opentui.MouseWheelDown, true, false)
+	shifted.Modifiers = opentui.ModShift
+	view.HandleMouse(shifted)
+	if x, y := view.Position(); x != 3 || y != 3 {
+		t.Errorf("after Shift+wheel: position %d, %d, want 3, 3", x, y)
+	}
+}
+
+func TestViewportRender(t *testing.T) {
+	content := newContent(t, 20, 30)
+	buffer := opentui.NewBuffer(10, 6, false, opentui.WidthMethodUnicode)
+	defer buffer.Close()
+	buffer.Clear(opentui.Blue)
+
+	rect := opentui.Rect{Position: opentui.Position{X: 1, Y: 1}, Size: opentui.Size{Width: 6, Height: 4}}
+	view := NewViewport(0, rect, content)
+	view.Scrollbars = true
+	view.ScrollTo(1, 100)
+	if err := view.Render(buffer); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	rows, _ := buffer.CaptureText()
+	for i, want := range []string{"          ", " 7abcd    ", " 8abcd    ", " 9abcd    ", "          "} {
+		if rows[i][:6] != want[:6] {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+	// The vertical scrollbar is scrolled to the end, the horizontal one near the start
+	for _, tc := range []struct {
+		x, y uint32
+		bg   opentui.RGBA
+	}{
+		{6, 1, view.Style.Track},
+		{6, 3, view.Style.Thumb},
+		{1, 4, view.Style.Track},
+		{2, 4, view.Style.Thumb},
+		{5, 4, view.Style.Track},
+		{7, 1, opentui.Blue},
+	} {
+		if cell, _ := buffer.GetCell(tc.x, tc.y); cell.Background != tc.bg {
+			t.Errorf("cell (%d, %d) background %v, want %v", tc.x, tc.y, cell.Background, tc.bg)
+		}
+	}
+}
+
+func TestTextViewport(t *testing.T) {
+	text := opentui.NewTextBuffer(256, opentui.WidthMethodUnicode)
+	if text == nil {
+		t.Skip("OpenTUI library not available")
+	}
+	defer text.Close()
+	for i := 0; i < 10; i++ {
+		text.WriteString(fmt.Sprintf("line %d of the text\n", i))
+	}
+	text.WriteString("last")
+	text.FinalizeLineInfo()
+
+	rect := opentui.Rect{Size: opentui.Size{Width: 6, Height: 3}}
+	view := NewTextViewport(0, rect, text)
+	if width, height, err := view.ContentSize(); err != nil || width != 18 || height != 11 {
+		t.Fatalf("ContentSize = %d, %d, %v", width, height, err)
+	}
+	view.ScrollTo(5, 100)
+
+	buffer := opentui.NewBuffer(8, 3, false, opentui.WidthMethodUnicode)
+	defer buffer.Close()
+	buffer.Clear(opentui.Black)
+	if err := view.Render(buffer); err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rows, _ := buffer.CaptureTextWithOptions(opentui.CaptureOptions{TrimTrailingSpace: true})
+	for i, want := range []string{"8 of t", "9 of t", ""} {
+		if rows[i] != want {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package opentui
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// wideRanges lists the code point ranges displayed two cells wide.
+// This covers the East Asian Wide and Fullwidth blocks as well as the common emoji blocks.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},   // Hangul Jamo initial consonants
+	{0x231A, 0x231B},   // Watch, hourglass
+	{0x2329, 0x232A},   // Angle brackets
+	{0x23E9, 0x23EC},   // Media controls
+	{0x23F0, 0x23F0},   // Alarm clock
+	{0x23F3, 0x23F3},   // Hourglass with flowing sand
+	{0x25FD, 0x25FE},   // Medium small squares
+	{0x2614, 0x2615},   // Umbrella, hot beverage
+	{0x2648, 0x2653},   // Zodiac
+	{0x267F, 0x267F},   // Wheelchair
+	{0x2693, 0x2693},   // Anchor
+	{0x26A1, 0x26A1},   // High voltage
+	{0x26AA, 0x26AB},   // Circles
+	{0x26BD, 0x26BE},   // Balls
+	{0x26C4, 0x26C5},   // Snowman, sun behind cloud
+	{0x26CE, 0x26CE},   // Ophiuchus
+	{0x26D4, 0x26D4},   // No entry
+	{0x26EA, 0x26EA},   // Church
+	{0x26F2, 0x26F3},   // Fountain, golf
+	{0x26F5, 0x26F5},   // Sailboat
+	{0x26FA, 0x26FA},   // Tent
+	{0x26FD, 0x26FD},   // Fuel pump
+	{0x2705, 0x2705},   // Check mark button
+	{0x270A, 0x270B},   // Raised fists
+	{0x2728, 0x2728},   // Sparkles
+	{0x274C, 0x274C},   // Cross mark
+	{0x274E, 0x274E},   // Cross mark button
+	{0x2753, 0x2755},   // Question and exclamation marks
+	{0x2757, 0x2757},   // Exclamation mark
+	{0x2795, 0x2797},   // Plus, minus, divide
+	{0x27B0, 0x27B0},   // Curly loop
+	{0x27BF, 0x27BF},   // Double curly loop
+	{0x2B1B, 0x2B1C},   // Large squares
+	{0x2B50, 0x2B50},   // Star
+	{0x2B55, 0x2B55},   // Heavy large circle
+	{0x2E80, 0x303E},   // CJK radicals, punctuation
+	{0x3041, 0x33FF},   // Hiragana, Katakana, CJK compatibility
+	{0x3400, 0x4DBF},   // CJK extension A
+	{0x4E00, 0x9FFF},   // CJK unified ideographs
+	{0xA000, 0xA4CF},   // Yi
+	{0xA960, 0xA97F},   // Hangul Jamo extended A
+	{0xAC00, 0xD7A3},   // Hangul syllables
+	{0xF900, 0xFAFF},   // CJK compatibility ideographs
+	{0xFE10, 0xFE19},   // Vertical forms
+	{0xFE30, 0xFE6F},   // CJK compatibility forms, small forms
+	{0xFF00, 0xFF60},   // Fullwidth forms
+	{0xFFE0, 0xFFE6},   // Fullwidth signs
+	{0x16FE0, 0x16FE4}, // Ideographic symbols
+	{0x17000, 0x18AFF}, // Tangut
+	{0x1B000, 0x1B16F}, // Kana supplement and extensions
+	{0x1F004, 0x1F004}, // Mahjong tile
+	{0x1F0CF, 0x1F0CF}, // Playing card
+	{0x1F18E, 0x1F18E}, // AB button
+	{0x1F191, 0x1F19A}, // Squared words
+	{0x1F200, 0x1F251}, // Enclosed ideographic supplement
+	{0x1F300, 0x1F64F}, // Misc symbols and pictographs, emoticons
+	{0x1F680, 0x1F6FF}, // Transport and map symbols
+	{0x1F7E0, 0x1F7EB}, // Colored circles and squares
+	{0x1F90C, 0x1F9FF}, // Supplemental symbols and pictographs
+	{0x1FA70, 0x1FAFF}, // Symbols and pictographs extended A
+	{0x20000, 0x2FFFD}, // CJK extensions B-F
+	{0x30000, 0x3FFFD}, // CJK extension G
+}
+
+// runeWidth returns the number of terminal cells a rune occupies: 0, 1 or 2.
+// Control characters, combining marks and other invisible format characters are zero width.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0:
+		return 0
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r < 0x300:
+		return 1
+	case r == 0x200D || (r >= 0xFE00 && r <= 0xFE0F) || (r >= 0xE0100 && r <= 0xE01EF):
+		// Zero width joiner and variation selectors
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+
+	lo, hi := 0, len(wideRanges)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		switch {
+		case r < wideRanges[mid][0]:
+			hi = mid - 1
+		case r > wideRanges[mid][1]:
+			lo = mid + 1
+		default:
+			return 2
+		}
+	}
+	return 1
+}
+
+// stringWidth returns the number of terminal cells a string occupies.
+func stringWidth(s string) int {
+	width := 0
+	for g := Graphemes(s); g.Next(); {
+		width += g.Width()
+	}
+	return width
+}
+
+// TruncateToWidth shortens s to at most maxWidth cells, cutting on grapheme cluster
+// boundaries and replacing the cut off part with tail, typically "…". A wide character
+// that would straddle the cut is replaced by a space, so a truncated result is always
+// exactly maxWidth cells wide. Strings that fit are returned unchanged. If tail itself
+// does not fit, s is cut without it.
+func TruncateToWidth(s string, maxWidth uint32, tail string) string {
+	limit := int(min(maxWidth, math.MaxInt32))
+	if stringWidth(s) <= limit {
+		return s
+	}
+	tailWidth := stringWidth(tail)
+	if tailWidth > limit {
+		tail, tailWidth = "", 0
+	}
+
+	available := limit - tailWidth
+	prefix := cutWidth(s, available)
+	return prefix + strings.Repeat(" ", available-stringWidth(prefix)) + tail
+}
+
+// defaultTabWidth is the distance between tab stops unless configured otherwise
+const defaultTabWidth = 8
+
+// expandTabs replaces tabs with spaces up to the next multiple of tabWidth, with text
+// starting at column col and newlines returning to column lineStart. It returns the
+// expanded text and the column after it. Text without tabs is returned as is.
+func expandTabs(text string, col, lineStart int64, tabWidth uint8) (string, int64) {
+	if !strings.ContainsRune(text, '\t') {
+		return text, textEndColumn(text, col, lineStart)
+	}
+	width := int64(tabWidth)
+	if width == 0 {
+		width = defaultTabWidth
+	}
+
+	var out strings.Builder
+	for g := Graphemes(text); g.Next(); {
+		switch g.Str() {
+		case "\t":
+			spaces := width - col%width
+			out.WriteString(strings.Repeat(" ", int(spaces)))
+			col += spaces
+		case "\n", "\r\n":
+			out.WriteString(g.Str())
+			col = lineStart
+		default:
+			out.WriteString(g.Str())
+			col += int64(g.Width())
+		}
+	}
+	return out.String(), col
+}
+
+// textEndColumn returns the column after text starting at column col.
+func textEndColumn(text string, col, lineStart int64) int64 {
+	if i := strings.LastIndexByte(text, '\n'); i >= 0 {
+		col, text = lineStart, text[i+1:]
+	}
+	return col + int64(stringWidth(text))
+}
+
+// MeasureText returns the display width of the widest line of s and its number of lines,
+// using the same width rules as buffers created with the given width method.
+// WidthMethodUnicode measures whole grapheme clusters, so an emoji sequence takes the
+// width of one emoji, while WidthMethodWCWidth adds up the width of every code point.
+// East Asian ambiguous characters are narrow in both methods. Lines are separated by
+// "\n", tabs are expanded to the default tab stops like DrawText does and other control
+// characters are zero width. An empty string is one line of width 0.
+func MeasureText(s string, method uint8) (width, lines uint32) {
+	s, _ = expandTabs(s, 0, 0, defaultTabWidth)
+	for {
+		line, rest, more := strings.Cut(s, "\n")
+		width = max(width, uint32(methodWidth(line, method)))
+		lines++
+		if !more {
+			return width, lines
+		}
+		s = rest
+	}
+}
+
+// MeasureTextWrapped returns the number of lines DrawTextWrapped uses to draw s into
+// a rect maxWidth cells wide and tall enough to hold all of them. Tabs are expanded to
+// the default tab stops.
+func MeasureTextWrapped(s string, maxWidth uint32, mode WrapMode) (lines uint32) {
+	if maxWidth == 0 {
+		return 0
+	}
+	s, _ = expandTabs(s, 0, 0, defaultTabWidth)
+	return uint32(len(wrapText(s, int(maxWidth), mode)))
+}
+
+// methodWidth returns the number of cells s occupies with the given width method.
+func methodWidth(s string, method uint8) int {
+	if method != WidthMethodWCWidth {
+		return stringWidth(s)
+	}
+	width := 0
+	for _, r := range s {
+		width += runeWidth(r)
+	}
+	return width
+}